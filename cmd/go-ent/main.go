@@ -53,6 +53,14 @@ func run(ctx context.Context, getenv func(string) string, stdout, stderr io.Writ
 	defer cancel()
 
 	s := internalserver.New()
+	defer func() {
+		flushCtx, flushCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer flushCancel()
+		if err := internalserver.Shutdown(flushCtx); err != nil {
+			logger.Warn("failed to shut down server resources", "error", err)
+		}
+	}()
+
 	transport := &mcp.StdioTransport{}
 
 	errCh := make(chan error, 1)
@@ -94,7 +94,6 @@ type yamlTask struct {
 	DependsOn   []spec.TaskID           `yaml:"depends_on,omitempty"`
 	BlockedBy   []spec.TaskID           `yaml:"blocked_by,omitempty"`
 	Assignee    string                  `yaml:"assignee,omitempty"`
-	Session     string                  `yaml:"session,omitempty"`
 	StartedAt   *time.Time              `yaml:"started_at,omitempty"`
 	CompletedAt *time.Time              `yaml:"completed_at,omitempty"`
 	Notes       string                  `yaml:"notes,omitempty"`
@@ -114,7 +113,6 @@ func (yt *yamlTask) toRegistryTask() spec.RegistryTask {
 		DependsOn:   yt.DependsOn,
 		BlockedBy:   yt.BlockedBy,
 		Assignee:    yt.Assignee,
-		Session:     yt.Session,
 		StartedAt:   yt.StartedAt,
 		CompletedAt: yt.CompletedAt,
 		Notes:       yt.Notes,
@@ -143,3 +143,22 @@ func (r *Registry) UnregisterAgent(name string) error {
 	delete(r.agents, name)
 	return nil
 }
+
+// ReplaceAgent re-reads the agent registered as name from path and swaps it
+// in, or registers it if it is not loaded yet.
+func (r *Registry) ReplaceAgent(name, path string) error {
+	meta, err := r.parser.ParseAgentFile(path)
+	if err != nil {
+		return fmt.Errorf("parse agent file: %w", err)
+	}
+
+	if meta.Name != name {
+		return fmt.Errorf("agent name mismatch: expected %s, got %s", name, meta.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.agents[name] = *meta
+	return nil
+}
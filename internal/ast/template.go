@@ -335,9 +335,8 @@ func (c *nodeCopier) copyDecl(d ast.Decl) ast.Decl {
 			specs[i] = c.copySpec(spec)
 		}
 		return &ast.GenDecl{
-			TokPos: n.TokPos,
-			Tok:    n.Tok,
-			Specs:  specs,
+			Tok:   n.Tok,
+			Specs: specs,
 		}
 	case *ast.FuncDecl:
 		return c.copyFuncDecl(n)
@@ -411,6 +410,30 @@ func (c *nodeCopier) copyStmt(s ast.Stmt) ast.Stmt {
 		return &ast.ExprStmt{X: c.copyExpr(n.X)}
 	case *ast.BlockStmt:
 		return c.copyBlockStmt(n)
+	case *ast.RangeStmt:
+		return &ast.RangeStmt{
+			Key:   c.copyExpr(n.Key),
+			Value: c.copyExpr(n.Value),
+			Tok:   n.Tok,
+			X:     c.copyExpr(n.X),
+			Body:  c.copyBlockStmt(n.Body),
+		}
+	case *ast.IncDecStmt:
+		return &ast.IncDecStmt{X: c.copyExpr(n.X), Tok: n.Tok}
+	case *ast.DeferStmt:
+		return &ast.DeferStmt{Call: c.copyCallExpr(n.Call)}
+	case *ast.SwitchStmt:
+		return &ast.SwitchStmt{
+			Init: c.copyStmt(n.Init),
+			Tag:  c.copyExpr(n.Tag),
+			Body: c.copyBlockStmt(n.Body),
+		}
+	case *ast.CaseClause:
+		body := make([]ast.Stmt, len(n.Body))
+		for i, stmt := range n.Body {
+			body[i] = c.copyStmt(stmt)
+		}
+		return &ast.CaseClause{List: c.copyExprs(n.List), Body: body}
 	default:
 		return s
 	}
@@ -473,6 +496,26 @@ func (c *nodeCopier) copyExpr(e ast.Expr) ast.Expr {
 			Key:   c.copyExpr(n.Key),
 			Value: c.copyExpr(n.Value),
 		}
+	case *ast.IndexExpr:
+		return &ast.IndexExpr{X: c.copyExpr(n.X), Index: c.copyExpr(n.Index)}
+	case *ast.SliceExpr:
+		return &ast.SliceExpr{
+			X:      c.copyExpr(n.X),
+			Low:    c.copyExpr(n.Low),
+			High:   c.copyExpr(n.High),
+			Max:    c.copyExpr(n.Max),
+			Slice3: n.Slice3,
+		}
+	case *ast.TypeAssertExpr:
+		return &ast.TypeAssertExpr{X: c.copyExpr(n.X), Type: c.copyExpr(n.Type)}
+	case *ast.FuncType:
+		return c.copyFuncType(n)
+	case *ast.FuncLit:
+		return &ast.FuncLit{Type: c.copyFuncType(n.Type), Body: c.copyBlockStmt(n.Body)}
+	case *ast.StructType:
+		return c.copyStructType(n)
+	case *ast.InterfaceType:
+		return c.copyInterfaceType(n)
 	default:
 		return e
 	}
@@ -540,15 +583,10 @@ func (c *nodeCopier) copyValueSpec(n *ast.ValueSpec) *ast.ValueSpec {
 		names[i] = c.copyIdent(name)
 	}
 
-	values := make([]ast.Expr, len(n.Values))
-	for i, val := range n.Values {
-		values[i] = c.copyExpr(val)
-	}
-
 	return &ast.ValueSpec{
 		Names:  names,
 		Type:   c.copyExpr(n.Type),
-		Values: values,
+		Values: c.copyExprs(n.Values),
 	}
 }
 
@@ -600,7 +638,8 @@ func (c *nodeCopier) copyFieldList(n *ast.FieldList) *ast.FieldList {
 	}
 
 	if len(n.List) == 0 {
-		return nil
+		// Keep the empty list: the printer needs it for struct{} and interface{}.
+		return &ast.FieldList{}
 	}
 
 	fields := make([]*ast.Field, len(n.List))
@@ -616,14 +655,16 @@ func (c *nodeCopier) copyField(n *ast.Field) *ast.Field {
 		return nil
 	}
 
-	names := make([]*ast.Ident, len(n.Names))
-	for i, name := range n.Names {
-		names[i] = c.copyIdent(name)
+	// Unnamed fields keep nil Names so the printer drops result parentheses.
+	var names []*ast.Ident
+	for _, name := range n.Names {
+		names = append(names, c.copyIdent(name))
 	}
 
 	return &ast.Field{
 		Names: names,
 		Type:  c.copyExpr(n.Type),
+		Tag:   c.copyBasicLit(n.Tag),
 	}
 }
 
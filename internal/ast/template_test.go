@@ -515,6 +515,45 @@ func TestEngine_ExecuteTemplate(t *testing.T) {
 	}
 }
 
+func TestEngine_ExecuteTemplate_DoesNotMutateTemplate(t *testing.T) {
+	t.Parallel()
+
+	e := NewEngine(nil)
+	require.NoError(t, e.Register(&Template{
+		Name: "handler",
+		Source: `type TypeName struct {
+			FieldName FieldType ` + "`json:\"field\"`" + `
+		}
+
+		func (r *TypeName) MethodName(items []FieldType) func() error {
+			return func() error {
+				for _, Value := range items {
+					_ = Value
+				}
+				return nil
+			}
+		}`,
+	}))
+
+	printer := NewPrinter(e.fset)
+	for _, name := range []string{"Order", "User"} {
+		node, err := e.Execute("handler", map[string]string{
+			"TypeName":   name,
+			"FieldType":  "string",
+			"MethodName": "Run",
+			"Value":      "item",
+		})
+		require.NoError(t, err)
+
+		out, err := printer.PrintNode(node)
+		require.NoError(t, err)
+		assert.Contains(t, out, "type "+name+" struct")
+		assert.Contains(t, out, "func (r *"+name+") Run(items []string) func() error")
+		assert.Contains(t, out, "for _, item := range items")
+		assert.Contains(t, out, `json:"field"`)
+	}
+}
+
 func TestEngine_RegisterBuiltIns(t *testing.T) {
 	e := NewEngine(nil)
 	err := e.RegisterBuiltIns()
@@ -0,0 +1,365 @@
+// Package breaker tracks provider failures with per-provider circuit breakers.
+//
+// A breaker starts closed. After FailureThreshold consecutive failures it
+// opens and the router stops sending work to the provider. Once OpenTimeout
+// has passed the breaker is half-open: Allow lets a single probe through and
+// refuses other calls until the probe's result either closes the breaker or
+// opens it for another timeout. A probe whose result is never recorded is
+// abandoned after OpenTimeout and the next call probes instead.
+//
+// Failures are fed from worker results, provider HTTP 429/5xx responses and
+// ACP errors through the process-wide registry returned by Default.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// State is the state of a circuit breaker.
+type State string
+
+const (
+	StateClosed   State = "closed"
+	StateOpen     State = "open"
+	StateHalfOpen State = "half_open"
+)
+
+// Config controls when breakers open and how long they stay open.
+type Config struct {
+	// FailureThreshold is the number of consecutive failures that opens a breaker.
+	FailureThreshold int
+
+	// OpenTimeout is how long a breaker stays open before going half-open.
+	OpenTimeout time.Duration
+}
+
+// DefaultConfig returns the thresholds used when none are configured.
+func DefaultConfig() Config {
+	return Config{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+	}
+}
+
+// Snapshot is a point-in-time view of one breaker.
+type Snapshot struct {
+	Name      string    `json:"name"`
+	State     State     `json:"state"`
+	Failures  int       `json:"failures"`
+	OpenedAt  time.Time `json:"opened_at,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Breaker is a circuit breaker for a single provider.
+type Breaker struct {
+	mu        sync.Mutex
+	name      string
+	cfg       Config
+	now       func() time.Time
+	logger    *slog.Logger
+	state     State
+	failures  int
+	openedAt  time.Time
+	lastError string
+	probeAt   time.Time
+}
+
+// State returns the current state, moving an expired open breaker to half-open.
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stateLocked()
+}
+
+func (b *Breaker) stateLocked() State {
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = StateHalfOpen
+		b.logger.Info("circuit half-open", "provider", b.name)
+	}
+	return b.state
+}
+
+// Available reports whether Allow would let a call through, without
+// claiming the half-open probe.
+func (b *Breaker) Available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.stateLocked() {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		return !b.probingLocked()
+	default:
+		return false
+	}
+}
+
+// Allow reports whether work may be sent to the provider. A half-open
+// breaker allows one probe at a time; the caller must record its result.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.stateLocked() {
+	case StateClosed:
+		return true
+	case StateHalfOpen:
+		if b.probingLocked() {
+			return false
+		}
+		b.probeAt = b.now()
+		return true
+	default:
+		return false
+	}
+}
+
+// Release gives back a probe claimed by Allow that was never sent, so the
+// next call can probe the half-open breaker.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stateLocked() == StateHalfOpen {
+		b.probeAt = time.Time{}
+	}
+}
+
+func (b *Breaker) probingLocked() bool {
+	return !b.probeAt.IsZero() && b.now().Sub(b.probeAt) < b.cfg.OpenTimeout
+}
+
+// Success records a successful call and closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.stateLocked() != StateClosed {
+		b.logger.Info("circuit closed", "provider", b.name)
+	}
+	b.state = StateClosed
+	b.failures = 0
+	b.probeAt = time.Time{}
+}
+
+// Failure records a failed call. It opens the breaker once the threshold is
+// reached, or immediately when the breaker is half-open.
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	state := b.stateLocked()
+	if state == StateHalfOpen || (state == StateClosed && b.failures >= b.cfg.FailureThreshold) {
+		b.state = StateOpen
+		b.openedAt = b.now()
+		b.probeAt = time.Time{}
+		b.logger.Warn("circuit open",
+			"provider", b.name,
+			"failures", b.failures,
+			"retry_after", b.cfg.OpenTimeout,
+			"error", b.lastError,
+		)
+	}
+}
+
+// Snapshot returns the breaker's current state.
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s := Snapshot{
+		Name:      b.name,
+		State:     b.stateLocked(),
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+	if s.State != StateClosed {
+		s.OpenedAt = b.openedAt
+	}
+	return s
+}
+
+// Registry holds one breaker per provider name.
+type Registry struct {
+	mu       sync.Mutex
+	cfg      Config
+	now      func() time.Time
+	logger   *slog.Logger
+	breakers map[string]*Breaker
+}
+
+// NewRegistry creates a registry whose breakers use cfg. Zero fields fall
+// back to DefaultConfig.
+func NewRegistry(cfg Config) *Registry {
+	def := DefaultConfig()
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = def.FailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = def.OpenTimeout
+	}
+
+	return &Registry{
+		cfg:      cfg,
+		now:      time.Now,
+		logger:   slog.Default(),
+		breakers: make(map[string]*Breaker),
+	}
+}
+
+// SetClock replaces the clock of the registry and of breakers created later.
+func (r *Registry) SetClock(now func() time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.now = now
+}
+
+// Get returns the breaker for name, creating a closed one if needed.
+func (r *Registry) Get(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, ok := r.breakers[name]
+	if !ok {
+		b = &Breaker{
+			name:   name,
+			cfg:    r.cfg,
+			now:    r.now,
+			logger: r.logger,
+			state:  StateClosed,
+		}
+		r.breakers[name] = b
+	}
+	return b
+}
+
+// Allow reports whether work may be sent to name, claiming the probe of a
+// half-open breaker. Unknown providers are allowed.
+func (r *Registry) Allow(name string) bool {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	return !ok || b.Allow()
+}
+
+// Release gives back the probe of name's half-open breaker claimed by Allow.
+func (r *Registry) Release(name string) {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	if ok {
+		b.Release()
+	}
+}
+
+// Available reports whether Allow would let a call to name through, without
+// claiming a probe. Unknown providers are available.
+func (r *Registry) Available(name string) bool {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	return !ok || b.Available()
+}
+
+// State returns the state of name's breaker, closed when none exists.
+func (r *Registry) State(name string) State {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return StateClosed
+	}
+	return b.State()
+}
+
+// Lookup returns the snapshot of name's breaker if one has been created.
+func (r *Registry) Lookup(name string) (Snapshot, bool) {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return Snapshot{}, false
+	}
+	return b.Snapshot(), true
+}
+
+// Record records the outcome of a call to name; a nil error is a success.
+// Cancelled calls say nothing about the provider and are ignored.
+func (r *Registry) Record(name string, err error) {
+	if name == "" || errors.Is(err, context.Canceled) {
+		return
+	}
+	if err != nil {
+		r.Get(name).Failure(err)
+		return
+	}
+	r.Get(name).Success()
+}
+
+// RecordStatus records an HTTP attempt. Transport errors, 429 and 5xx count
+// as failures, 2xx as success; other client errors leave the breaker alone.
+func (r *Registry) RecordStatus(name string, statusCode int, err error) {
+	switch {
+	case statusCode == 0 && err != nil,
+		statusCode == http.StatusTooManyRequests,
+		statusCode >= http.StatusInternalServerError:
+		if err == nil {
+			err = fmt.Errorf("HTTP %d", statusCode)
+		}
+		r.Record(name, err)
+	case statusCode >= 200 && statusCode < 300:
+		r.Record(name, nil)
+	}
+}
+
+// Snapshots returns every breaker sorted by name.
+func (r *Registry) Snapshots() []Snapshot {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	snaps := make([]Snapshot, 0, len(breakers))
+	for _, b := range breakers {
+		snaps = append(snaps, b.Snapshot())
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Name < snaps[j].Name })
+	return snaps
+}
+
+var defaultRegistry atomic.Pointer[Registry]
+
+func init() {
+	defaultRegistry.Store(NewRegistry(DefaultConfig()))
+}
+
+// SetDefault replaces the process-wide registry.
+func SetDefault(r *Registry) {
+	defaultRegistry.Store(r)
+}
+
+// Default returns the process-wide registry.
+func Default() *Registry {
+	return defaultRegistry.Load()
+}
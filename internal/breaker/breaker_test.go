@@ -0,0 +1,163 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(threshold int, timeout time.Duration) (*Registry, *time.Time) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	reg := NewRegistry(Config{FailureThreshold: threshold, OpenTimeout: timeout})
+	reg.SetClock(func() time.Time { return now })
+	return reg, &now
+}
+
+func TestBreaker_Transitions(t *testing.T) {
+	t.Parallel()
+
+	reg, now := newTestRegistry(3, time.Minute)
+	boom := errors.New("boom")
+
+	assert.Equal(t, StateClosed, reg.State("glm"))
+	assert.True(t, reg.Allow("glm"))
+
+	reg.Record("glm", boom)
+	reg.Record("glm", boom)
+	assert.Equal(t, StateClosed, reg.State("glm"))
+
+	reg.Record("glm", nil)
+	reg.Record("glm", boom)
+	reg.Record("glm", boom)
+	assert.Equal(t, StateClosed, reg.State("glm"), "success resets the failure count")
+
+	reg.Record("glm", boom)
+	assert.Equal(t, StateOpen, reg.State("glm"))
+	assert.False(t, reg.Allow("glm"))
+
+	*now = now.Add(59 * time.Second)
+	assert.Equal(t, StateOpen, reg.State("glm"))
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, StateHalfOpen, reg.State("glm"))
+	assert.True(t, reg.Allow("glm"))
+
+	reg.Record("glm", boom)
+	assert.Equal(t, StateOpen, reg.State("glm"), "a failed probe reopens the circuit")
+
+	*now = now.Add(time.Minute)
+	reg.Record("glm", nil)
+	assert.Equal(t, StateClosed, reg.State("glm"))
+}
+
+func TestBreaker_HalfOpenAllowsOneProbe(t *testing.T) {
+	t.Parallel()
+
+	reg, now := newTestRegistry(1, time.Minute)
+	reg.Record("glm", errors.New("boom"))
+
+	*now = now.Add(time.Minute)
+	assert.True(t, reg.Available("glm"))
+	assert.True(t, reg.Allow("glm"), "the first call probes")
+	assert.False(t, reg.Available("glm"))
+	assert.False(t, reg.Allow("glm"), "only one probe is in flight")
+	assert.Equal(t, StateHalfOpen, reg.State("glm"))
+
+	*now = now.Add(time.Minute)
+	assert.True(t, reg.Allow("glm"), "an abandoned probe is replaced")
+
+	reg.Record("glm", nil)
+	assert.True(t, reg.Allow("glm"))
+	assert.True(t, reg.Allow("glm"), "a closed breaker allows every call")
+}
+
+func TestBreaker_ReleaseReturnsProbe(t *testing.T) {
+	t.Parallel()
+
+	reg, now := newTestRegistry(1, time.Minute)
+	reg.Record("glm", errors.New("boom"))
+	reg.Release("glm")
+	assert.Equal(t, StateOpen, reg.State("glm"), "release does not close an open circuit")
+
+	*now = now.Add(time.Minute)
+	require.True(t, reg.Allow("glm"))
+	reg.Release("glm")
+	assert.True(t, reg.Available("glm"))
+	assert.True(t, reg.Allow("glm"), "the released probe can be claimed again")
+	assert.Equal(t, StateHalfOpen, reg.State("glm"))
+
+	reg.Release("unknown")
+}
+
+func TestRegistry_RecordStatus(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   int
+		err      error
+		expected State
+		lastErr  string
+	}{
+		{name: "rate limited", status: http.StatusTooManyRequests, expected: StateOpen, lastErr: "HTTP 429"},
+		{name: "server error", status: http.StatusBadGateway, err: fmt.Errorf("API error (status 502)"), expected: StateOpen, lastErr: "API error (status 502)"},
+		{name: "transport error", err: errors.New("connection refused"), expected: StateOpen, lastErr: "connection refused"},
+		{name: "client error ignored", status: http.StatusBadRequest, err: errors.New("bad request"), expected: StateClosed},
+		{name: "success", status: http.StatusOK, expected: StateClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg, _ := newTestRegistry(1, time.Minute)
+			reg.RecordStatus("anthropic", tt.status, tt.err)
+
+			assert.Equal(t, tt.expected, reg.State("anthropic"))
+			if tt.lastErr != "" {
+				snap, ok := reg.Lookup("anthropic")
+				require.True(t, ok)
+				assert.Equal(t, tt.lastErr, snap.LastError)
+			}
+		})
+	}
+}
+
+func TestRegistry_IgnoresCancellation(t *testing.T) {
+	t.Parallel()
+
+	reg, _ := newTestRegistry(1, time.Minute)
+	reg.Record("glm", fmt.Errorf("send: %w", context.Canceled))
+	reg.Record("", errors.New("boom"))
+
+	_, ok := reg.Lookup("glm")
+	assert.False(t, ok)
+	assert.Empty(t, reg.Snapshots())
+}
+
+func TestRegistry_Snapshots(t *testing.T) {
+	t.Parallel()
+
+	reg, now := newTestRegistry(1, time.Minute)
+	reg.Record("kimi", nil)
+	reg.Record("glm", errors.New("timeout"))
+
+	snaps := reg.Snapshots()
+	require.Len(t, snaps, 2)
+
+	assert.Equal(t, Snapshot{Name: "glm", State: StateOpen, Failures: 1, OpenedAt: *now, LastError: "timeout"}, snaps[0])
+	assert.Equal(t, Snapshot{Name: "kimi", State: StateClosed}, snaps[1])
+}
+
+func TestNewRegistry_Defaults(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry(Config{})
+	assert.Equal(t, DefaultConfig(), reg.cfg)
+}
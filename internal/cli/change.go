@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+// hookMarker identifies git hooks written by `change hooks`.
+const hookMarker = "# installed by go-ent change hooks"
+
+func newChangeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "change",
+		Short: "Link OpenSpec changes to git",
+		Long: `Work on OpenSpec changes in git branches.
+
+Each change gets a branch named change/<id>. Commits whose messages mention a
+task ID (add-sso/3, or the tasks.md number as in add-sso/1.2) are recorded on
+the task, and once such a commit reaches the branch the change was started
+from, the task is completed in the registry and ticked in tasks.md. Archiving
+a change is refused while its branch is not merged; spec_archive takes
+allow_unmerged to archive it anyway.
+
+Only the local git binary is used; nothing is fetched or pushed.`,
+	}
+
+	cmd.AddCommand(newChangeStartCmd())
+	cmd.AddCommand(newChangeSyncCmd())
+	cmd.AddCommand(newChangeHooksCmd())
+
+	return cmd
+}
+
+func newChangeStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start <change-id>",
+		Short: "Create and check out the branch of a change",
+		Long:  "Create the change/<id> branch from the current branch, which the change is expected to merge back into",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get current directory: %w", err)
+			}
+
+			changeID := args[0]
+			store := spec.NewStore(cwd)
+			if _, err := os.Stat(filepath.Join(store.SpecPath(), "changes", changeID)); err != nil {
+				return fmt.Errorf("change not found: %s", changeID)
+			}
+
+			git := spec.NewGit(cwd)
+			branch, err := git.StartChange(changeID)
+			if err != nil {
+				return fmt.Errorf("start change: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Switched to new branch %s (merges into %s)\n", branch, git.BaseBranch(changeID))
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Mention task IDs such as %s/1 in commit messages to link them.\n", changeID)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func newChangeSyncCmd() *cobra.Command {
+	var (
+		quiet        bool
+		registryOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Record task commits and complete merged tasks",
+		Long:  "Scan local branches for commits mentioning task IDs, record them on the tasks, and complete tasks whose commits reached the base branch. With --registry-only, tasks.md and state.md are left alone and the next registry_sync ticks completed tasks.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runChangeSync(cmd, quiet, registryOnly)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "print nothing, for use in git hooks")
+	cmd.Flags().BoolVar(&registryOnly, "registry-only", false, "update only the registry, leaving tasks.md and state.md untouched")
+	return cmd
+}
+
+func newChangeHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Install git hooks that run change sync",
+		Long:  "Install post-commit and post-merge hooks that run 'go-ent change sync --quiet --registry-only'. The hooks update only the registry, so a commit never leaves tasks.md or state.md modified; the next registry_sync ticks completed tasks in tasks.md. Existing hooks not written by go-ent are left alone.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cwd, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("get current directory: %w", err)
+			}
+
+			git := spec.NewGit(cwd)
+			if !git.IsRepo() {
+				return spec.ErrNotGitRepo
+			}
+			dir, err := git.HooksDir()
+			if err != nil {
+				return fmt.Errorf("find hooks directory: %w", err)
+			}
+			if err := os.MkdirAll(dir, 0750); err != nil {
+				return fmt.Errorf("create hooks directory: %w", err)
+			}
+
+			script := "#!/bin/sh\n" + hookMarker + "\ncommand -v go-ent >/dev/null 2>&1 && go-ent change sync --quiet --registry-only\nexit 0\n"
+			for _, name := range []string{"post-commit", "post-merge"} {
+				path := filepath.Join(dir, name)
+				if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) { // #nosec G304 -- git hooks path
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "⚠️  Skipped %s: existing hook not written by go-ent\n", name)
+					continue
+				}
+				if err := os.WriteFile(path, []byte(script), 0755); err != nil { //nolint:gosec // hooks must be executable
+					return fmt.Errorf("write %s hook: %w", name, err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Installed %s hook\n", name)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
+
+func runChangeSync(cmd *cobra.Command, quiet, registryOnly bool) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get current directory: %w", err)
+	}
+
+	store := spec.NewStore(cwd)
+	if _, err := os.Stat(filepath.Join(store.SpecPath(), "registry.db")); os.IsNotExist(err) {
+		return fmt.Errorf("registry not found: run registry_sync first")
+	}
+
+	registryStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return fmt.Errorf("create registry store: %w", err)
+	}
+	defer func() { _ = registryStore.Close() }()
+
+	result, err := registryStore.SyncGit(spec.NewGit(cwd), registryOnly)
+	if err != nil {
+		return fmt.Errorf("sync git: %w", err)
+	}
+
+	if len(result.Completed) > 0 && !registryOnly {
+		stateStore := registryStore.StateStore()
+		changes := make(map[string]bool)
+		for _, id := range result.Completed {
+			if changes[id.ChangeID] {
+				continue
+			}
+			changes[id.ChangeID] = true
+			statePath := filepath.Join(store.SpecPath(), "changes", id.ChangeID, "state.md")
+			if err := stateStore.WriteChangeStateMd(id.ChangeID, statePath); err != nil {
+				return fmt.Errorf("write change state.md: %w", err)
+			}
+		}
+		if err := stateStore.WriteRootStateMd(filepath.Join(store.SpecPath(), "state.md")); err != nil {
+			return fmt.Errorf("write root state.md: %w", err)
+		}
+	}
+
+	if quiet {
+		return nil
+	}
+
+	out := cmd.OutOrStdout()
+	for _, l := range result.Linked {
+		_, _ = fmt.Fprintf(out, "🔗 %s ← %.7s %s\n", l.TaskID.String(), l.Commit.SHA, l.Commit.Subject)
+	}
+	for _, id := range result.Completed {
+		_, _ = fmt.Fprintf(out, "✅ %s completed (merged)\n", id.String())
+	}
+	_, _ = fmt.Fprintf(out, "%d commit link(s) recorded, %d task(s) completed\n", len(result.Linked), len(result.Completed))
+	return nil
+}
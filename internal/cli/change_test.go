@@ -0,0 +1,67 @@
+package cli_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+func TestChangeCommands(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte("# Tasks\n\n- [ ] Add provider\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("openspec/registry.db\n"), 0o600))
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q", "-b", "main")
+	git("add", "-A")
+	git("commit", "-qm", "Propose add-sso")
+	t.Chdir(root)
+
+	_, _, err := executeCommand(t, "change", "sync")
+	require.Error(t, err, "sync needs a registry")
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	stdout, _, err := executeCommand(t, "change", "start", "add-sso")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Switched to new branch change/add-sso (merges into main)")
+
+	_, _, err = executeCommand(t, "change", "start", "missing")
+	require.Error(t, err)
+
+	git("commit", "-q", "--allow-empty", "-m", "Wire provider (add-sso/1)")
+	git("checkout", "-q", "main")
+	git("merge", "-q", "--no-ff", "-m", "Merge add-sso", "change/add-sso")
+
+	stdout, _, err = executeCommand(t, "change", "sync")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "add-sso/1 completed (merged)")
+	assert.Contains(t, stdout, "1 commit link(s) recorded, 1 task(s) completed")
+
+	stdout, _, err = executeCommand(t, "change", "hooks")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Installed post-commit hook")
+	hook, err := os.ReadFile(filepath.Join(root, ".git", "hooks", "post-merge"))
+	require.NoError(t, err)
+	assert.Contains(t, string(hook), "go-ent change sync --quiet --registry-only")
+}
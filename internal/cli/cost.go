@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/victorzhuk/go-ent/internal/cost"
+)
+
+func newCostCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cost",
+		Short: "Inspect spend and budgets",
+		Long:  "Inspect persisted spend against the budget limits in .go-ent/config.yaml",
+	}
+
+	cmd.AddCommand(newCostReportCmd())
+
+	return cmd
+}
+
+func newCostReportCmd() *cobra.Command {
+	var (
+		path   string
+		month  string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Show spend by day, provider, model, change and agent role",
+		Long: `Show persisted spend for a month, broken down by day, provider, model,
+change ID and agent role, compared with the configured budget limits.
+
+The report projects month-end spend from the current run rate and warns
+before the budget check starts rejecting work.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "markdown" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be markdown or json)", format)
+			}
+
+			report, err := cost.ProjectReport(path, month, time.Now())
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal report: %w", err)
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+				return nil
+			}
+
+			_, _ = fmt.Fprint(out, cost.FormatMarkdown(report))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "path", ".", "project directory")
+	cmd.Flags().StringVar(&month, "month", "", "month to report as YYYY-MM (default: current month)")
+	cmd.Flags().StringVarP(&format, "format", "f", "markdown", "output format (markdown or json)")
+
+	return cmd
+}
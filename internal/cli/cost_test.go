@@ -0,0 +1,38 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostCommands(t *testing.T) {
+	t.Run("cost report markdown", func(t *testing.T) {
+		stdout, _, err := executeCommand(t, "cost", "report", "--path", t.TempDir())
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "## Cost Report: "+time.Now().Format("2006-01"))
+		assert.Contains(t, stdout, "### Budget")
+	})
+
+	t.Run("cost report json", func(t *testing.T) {
+		stdout, _, err := executeCommand(t, "cost", "report", "--path", t.TempDir(), "--month", "2026-01", "--format", "json")
+		require.NoError(t, err)
+
+		var report map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+		assert.Equal(t, "2026-01", report["month"])
+	})
+
+	t.Run("cost report invalid format", func(t *testing.T) {
+		_, _, err := executeCommand(t, "cost", "report", "--format", "xml")
+		require.Error(t, err)
+	})
+
+	t.Run("cost report invalid month", func(t *testing.T) {
+		_, _, err := executeCommand(t, "cost", "report", "--path", t.TempDir(), "--month", "jan")
+		require.Error(t, err)
+	})
+}
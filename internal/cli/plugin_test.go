@@ -0,0 +1,73 @@
+package cli_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/marketplace"
+)
+
+func TestPluginIndexBuild(t *testing.T) {
+	t.Parallel()
+
+	src := filepath.Join(t.TempDir(), "plugins")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "go-kit"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "go-kit", "plugin.yaml"), []byte(`name: go-kit
+version: 1.0.0
+description: Go skills
+author: Test
+`), 0o600))
+
+	out := filepath.Join(t.TempDir(), "mirror")
+	stdout, _, err := executeCommand(t, "plugin", "index", "build", src, "-o", out)
+	require.NoError(t, err)
+
+	assert.Contains(t, stdout, "packed go-kit@1.0.0 -> plugins/go-kit-1.0.0.zip")
+	assert.Contains(t, stdout, "index: 1 plugin versions")
+	assert.FileExists(t, filepath.Join(out, "index.yaml"))
+	assert.FileExists(t, filepath.Join(out, "plugins", "go-kit-1.0.0.zip"))
+}
+
+func TestPluginIndexBuild_MissingSource(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := executeCommand(t, "plugin", "index", "build", filepath.Join(t.TempDir(), "missing"), "-o", t.TempDir())
+	assert.ErrorContains(t, err, "read plugin source")
+}
+
+func TestPluginIndexBuild_SignKey(t *testing.T) {
+	t.Parallel()
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "release.pem")
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	src := filepath.Join(dir, "go-kit")
+	require.NoError(t, os.MkdirAll(src, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml"), []byte("name: go-kit\nversion: 1.0.0\ndescription: Go skills\nauthor: Test\n"), 0o600))
+
+	out := filepath.Join(dir, "mirror")
+	_, _, err = executeCommand(t, "plugin", "index", "build", src, "-o", out, "--sign-key", keyPath)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(out, "plugins", "go-kit-1.0.0.zip"))
+	require.NoError(t, err)
+	v, err := marketplace.VerifyArchive(data, marketplace.Trust{Keys: []marketplace.TrustedKey{{ID: "release", Key: priv.Public().(ed25519.PublicKey)}}}, marketplace.DefaultLimits())
+	require.NoError(t, err)
+	assert.Equal(t, "release", v.SignedBy)
+
+	_, _, err = executeCommand(t, "plugin", "index", "build", src, "-o", out, "--sign-key", filepath.Join(dir, "missing.pem"))
+	assert.ErrorContains(t, err, "read sign key")
+}
@@ -38,6 +38,10 @@ spec-driven development, and intelligent task execution.`,
 	cmd.AddCommand(newConfigCmd())
 	cmd.AddCommand(newModelCmd())
 	cmd.AddCommand(newTaskCmd())
+	cmd.AddCommand(newChangeCmd())
+	cmd.AddCommand(newCostCmd())
+	cmd.AddCommand(newRouteCmd())
+	cmd.AddCommand(newPluginCmd())
 
 	return cmd
 }
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/router"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+func newRouteCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "route",
+		Short: "Inspect and tune task routing",
+		Long:  "Inspect and tune the routing rules in .goent/routing.yaml",
+	}
+
+	cmd.AddCommand(newRouteSimulateCmd())
+
+	return cmd
+}
+
+func newRouteSimulateCmd() *cobra.Command {
+	var (
+		path     string
+		rules    string
+		baseline string
+		history  string
+		format   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Replay recorded tasks against new routing rules",
+		Long: `Replay recorded tasks through the router with the current rules and with
+the rules in --rules, then report which decisions change, the estimated cost
+delta, rules that never fire and rules shadowed by higher-priority ones.
+
+The history file is a JSON array or JSONL of recorded tasks, memory patterns,
+or metrics carrying task_type in their metadata.`,
+		Example: `  go-ent route simulate --rules new.yaml --history tasks.jsonl
+  go-ent route simulate --rules new.yaml --history tasks.jsonl -f json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "markdown" && format != "json" {
+				return fmt.Errorf("invalid format: %s (must be markdown or json)", format)
+			}
+			if rules == "" {
+				return fmt.Errorf("--rules is required")
+			}
+			if history == "" {
+				return fmt.Errorf("--history is required")
+			}
+
+			if _, err := os.Stat(rules); err != nil {
+				return fmt.Errorf("rules file: %w", err)
+			}
+			candidate, err := router.LoadRoutingConfig(rules)
+			if err != nil {
+				return err
+			}
+
+			if baseline == "" {
+				baseline = filepath.Join(path, ".goent", "routing.yaml")
+			}
+			current, err := router.LoadRoutingConfig(baseline)
+			if err != nil {
+				return err
+			}
+
+			providers, err := config.LoadProviders(path)
+			if err != nil {
+				return err
+			}
+
+			tasks, err := router.LoadHistory(history)
+			if err != nil {
+				return err
+			}
+
+			report, err := router.Simulate(cmd.Context(), worker.FromProvidersConfig(providers), current.Rules, candidate.Rules, tasks)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if format == "json" {
+				data, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal report: %w", err)
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+				return nil
+			}
+
+			_, _ = fmt.Fprint(out, router.FormatSimulation(report))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&path, "path", ".", "project directory")
+	cmd.Flags().StringVar(&rules, "rules", "", "candidate routing rules file")
+	cmd.Flags().StringVar(&baseline, "baseline", "", "baseline routing rules file (default: <path>/.goent/routing.yaml)")
+	cmd.Flags().StringVar(&history, "history", "", "recorded tasks (JSON or JSONL)")
+	cmd.Flags().StringVarP(&format, "format", "f", "markdown", "output format (markdown or json)")
+
+	return cmd
+}
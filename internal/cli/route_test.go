@@ -0,0 +1,103 @@
+package cli_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeRouteFixture(t *testing.T) (dir, rules, history string) {
+	t.Helper()
+
+	dir = t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".goent"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".goent", "providers.yaml"), []byte(`providers:
+  glm:
+    method: acp
+    provider: moonshot
+    model: glm-4
+  haiku:
+    method: cli
+    provider: anthropic
+    model: claude-3-haiku
+defaults:
+  implementation: glm
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".goent", "routing.yaml"), []byte(`rules:
+  - id: implement-glm
+    priority: 100
+    match:
+      type: [implement]
+    action:
+      method: acp
+      provider: glm
+`), 0o600))
+
+	rules = filepath.Join(dir, "new.yaml")
+	require.NoError(t, os.WriteFile(rules, []byte(`rules:
+  - id: tests-haiku
+    priority: 200
+    match:
+      type: [test]
+    action:
+      method: cli
+      provider: haiku
+  - id: docs-haiku
+    priority: 10
+    match:
+      type: [documentation]
+    action:
+      method: cli
+      provider: haiku
+`), 0o600))
+
+	history = filepath.Join(dir, "history.jsonl")
+	require.NoError(t, os.WriteFile(history, []byte(`{"type":"implement","description":"add handler"}
+{"type":"test","description":"cover handler"}
+`), 0o600))
+
+	return dir, rules, history
+}
+
+func TestRouteCommands(t *testing.T) {
+	t.Run("route simulate markdown", func(t *testing.T) {
+		dir, rules, history := writeRouteFixture(t)
+
+		stdout, _, err := executeCommand(t, "route", "simulate", "--path", dir, "--rules", rules, "--history", history)
+		require.NoError(t, err)
+		assert.Contains(t, stdout, "# Routing Simulation")
+		assert.Contains(t, stdout, "**Tasks:** 2")
+		assert.Contains(t, stdout, "**Changed decisions:** 1")
+		assert.Contains(t, stdout, "- docs-haiku")
+	})
+
+	t.Run("route simulate json", func(t *testing.T) {
+		dir, rules, history := writeRouteFixture(t)
+
+		stdout, _, err := executeCommand(t, "route", "simulate", "--path", dir, "--rules", rules, "--history", history, "-f", "json")
+		require.NoError(t, err)
+
+		var report map[string]any
+		require.NoError(t, json.Unmarshal([]byte(stdout), &report))
+		assert.EqualValues(t, 2, report["tasks"])
+		assert.Equal(t, []any{"docs-haiku"}, report["never_fired"])
+	})
+
+	t.Run("route simulate missing rules file", func(t *testing.T) {
+		dir, _, history := writeRouteFixture(t)
+
+		_, _, err := executeCommand(t, "route", "simulate", "--path", dir, "--rules", filepath.Join(dir, "missing.yaml"), "--history", history)
+		require.Error(t, err)
+	})
+
+	t.Run("route simulate requires history", func(t *testing.T) {
+		_, rules, _ := writeRouteFixture(t)
+
+		_, _, err := executeCommand(t, "route", "simulate", "--rules", rules)
+		require.Error(t, err)
+	})
+}
@@ -1,12 +1,16 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/victorzhuk/go-ent/internal/spec"
+	"github.com/victorzhuk/go-ent/internal/spec/spectest"
 )
 
 func newSpecCmd() *cobra.Command {
@@ -19,6 +23,11 @@ func newSpecCmd() *cobra.Command {
 	cmd.AddCommand(newSpecInitCmd())
 	cmd.AddCommand(newSpecListCmd())
 	cmd.AddCommand(newSpecShowCmd())
+	cmd.AddCommand(newSpecConflictsCmd())
+	cmd.AddCommand(newSpecTraceCmd())
+	cmd.AddCommand(newSpecTestCmd())
+	cmd.AddCommand(newSpecHistoryCmd())
+	cmd.AddCommand(newSpecUnarchiveCmd())
 
 	return cmd
 }
@@ -203,6 +212,415 @@ func newSpecShowCmd() *cobra.Command {
 	}
 }
 
+func newSpecConflictsCmd() *cobra.Command {
+	var (
+		change string
+		strict bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "conflicts [path]",
+		Short: "Detect pending changes whose deltas touch the same requirement",
+		Long: `Scan the delta specs of all pending changes and report requirements touched
+by more than one of them, classified as modify/modify, modify/remove,
+remove/remove, add/add or rename-chain.
+
+A conflict is resolved once one change is rebased on the other and lists it
+under "Requires:" in proposal.md; the required change is then archived first.
+With --strict the command fails while unresolved conflicts remain, or, with
+--change, while that change is blocked.`,
+		Example: `  go-ent spec conflicts
+  go-ent spec conflicts --change add-sso --strict`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			store := spec.NewStore(path)
+
+			exists, err := store.Exists()
+			if err != nil {
+				return fmt.Errorf("check openspec folder: %w", err)
+			}
+
+			if !exists {
+				return fmt.Errorf("no openspec folder found. Run 'go-ent spec init' first")
+			}
+
+			report, err := spec.DetectConflicts(store)
+			if err != nil {
+				return fmt.Errorf("detect conflicts: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			conflicts := report.Conflicts
+			if change != "" {
+				conflicts = report.ForChange(change)
+			}
+
+			if len(conflicts) == 0 {
+				_, _ = fmt.Fprintf(out, "No conflicts across %d pending change(s)\n", len(report.Changes))
+				return nil
+			}
+
+			unresolved := 0
+			for _, c := range conflicts {
+				if !c.Resolved {
+					unresolved++
+				}
+				_, _ = fmt.Fprintln(out, c.String())
+				for _, t := range c.Touches {
+					_, _ = fmt.Fprintf(out, "  %s\n", t.String())
+				}
+			}
+
+			if change != "" {
+				blockers := report.Blockers(change)
+				for _, b := range blockers {
+					_, _ = fmt.Fprintf(out, "blocked: %s\n", b)
+				}
+				if strict && len(blockers) > 0 {
+					return fmt.Errorf("%s is blocked by %d conflict(s)", change, len(blockers))
+				}
+				return nil
+			}
+
+			if strict && unresolved > 0 {
+				return fmt.Errorf("%d unresolved conflict(s)", unresolved)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&change, "change", "", "Only report conflicts involving this change")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on unresolved conflicts or archive blockers")
+
+	return cmd
+}
+
+func newSpecTraceCmd() *cobra.Command {
+	var (
+		format string
+		output string
+		noGit  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trace [path]",
+		Short: "Report which code and tests implement each spec requirement",
+		Long: `Build a traceability matrix linking every "### Requirement:" in
+openspec/specs to Go code and tests. Links come from:
+
+  - annotations such as "// spec: mcp-tools/Project Generation Tool"
+  - declarations named after a requirement and tests whose name contains it
+    (TestProjectGenerationTool_InvalidPath)
+  - files changed by commits that mention a task ID ("add-sso/1.2") of a
+    change whose deltas touch the requirement
+
+The report lists untested requirements, tests that trace to no requirement
+and annotations naming requirements that no longer exist.`,
+		Example: `  go-ent spec trace
+  go-ent spec trace --format json -o trace.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			store := spec.NewStore(path)
+
+			exists, err := store.Exists()
+			if err != nil {
+				return fmt.Errorf("check openspec folder: %w", err)
+			}
+
+			if !exists {
+				return fmt.Errorf("no openspec folder found. Run 'go-ent spec init' first")
+			}
+
+			matrix, err := spec.BuildTrace(store, spec.TraceOptions{SkipGit: noGit})
+			if err != nil {
+				return fmt.Errorf("build trace: %w", err)
+			}
+
+			var report []byte
+			switch format {
+			case "markdown":
+				report = []byte(matrix.Markdown())
+			case "json":
+				report, err = json.MarshalIndent(matrix, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal trace: %w", err)
+				}
+				report = append(report, '\n')
+			default:
+				return fmt.Errorf("unknown format: %s", format)
+			}
+
+			if output != "" {
+				if err := os.WriteFile(output, report, 0600); err != nil {
+					return fmt.Errorf("write report: %w", err)
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s (%d requirement(s), %d untested)\n", output, len(matrix.Requirements), len(matrix.Untested()))
+				return nil
+			}
+
+			_, _ = cmd.OutOrStdout().Write(report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "markdown", "Report format (markdown, json)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the report to a file")
+	cmd.Flags().BoolVar(&noGit, "no-git", false, "Skip task-to-commit links from git history")
+
+	return cmd
+}
+
+func newSpecHistoryCmd() *cobra.Command {
+	var (
+		requirement string
+		format      string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history <spec> [path]",
+		Short: "List the archived changes that edited a spec",
+		Long: `List, oldest first, the archived changes that edited a spec and the
+requirements each added (+), modified (~), removed (-) or renamed (→).
+
+With --requirement only the changes that shaped that requirement are listed,
+following it back across renames. Changes archived before patches were
+recorded are read from their delta specs.`,
+		Example: `  go-ent spec history auth
+  go-ent spec history auth --requirement "Idle timeout"`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 1 {
+				path = args[1]
+			}
+
+			store := spec.NewStore(path)
+
+			exists, err := store.Exists()
+			if err != nil {
+				return fmt.Errorf("check openspec folder: %w", err)
+			}
+
+			if !exists {
+				return fmt.Errorf("no openspec folder found. Run 'go-ent spec init' first")
+			}
+
+			entries, err := spec.NewArchiver(store).History(args[0], requirement)
+			if err != nil {
+				return fmt.Errorf("read history: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			switch format {
+			case "text":
+				_, _ = fmt.Fprint(out, spec.FormatSpecHistory(args[0], entries))
+			case "json":
+				data, err := json.MarshalIndent(entries, "", "  ")
+				if err != nil {
+					return fmt.Errorf("marshal history: %w", err)
+				}
+				_, _ = fmt.Fprintln(out, string(data))
+			default:
+				return fmt.Errorf("unknown format: %s", format)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&requirement, "requirement", "r", "", "Only changes that shaped this requirement")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format (text, json)")
+
+	return cmd
+}
+
+func newSpecUnarchiveCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "unarchive <change> [path]",
+		Short: "Revert an archived change and restore it to changes/",
+		Long: `Revert the spec edits an archived change made and move it from
+changes/archive back to changes/.
+
+A spec nothing else touched since is restored exactly. Otherwise the
+change's requirement edits are reverted one by one, which is refused while a
+later archived change or a hand edit touched the same requirements.`,
+		Example: `  go-ent spec unarchive add-2fa --dry-run
+  go-ent spec unarchive add-2fa`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 1 {
+				path = args[1]
+			}
+
+			store := spec.NewStore(path)
+
+			exists, err := store.Exists()
+			if err != nil {
+				return fmt.Errorf("check openspec folder: %w", err)
+			}
+
+			if !exists {
+				return fmt.Errorf("no openspec folder found. Run 'go-ent spec init' first")
+			}
+
+			out := cmd.OutOrStdout()
+			result, err := spec.NewArchiver(store).Unarchive(args[0], dryRun)
+			if errors.Is(err, spec.ErrUnarchiveConflict) {
+				for _, c := range result.Conflicts {
+					_, _ = fmt.Fprintf(out, "conflict: %s\n", c)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("unarchive %s: %w", args[0], err)
+			}
+
+			verb := "Restored"
+			if result.DryRun {
+				verb = "Would restore"
+			}
+			_, _ = fmt.Fprintf(out, "%s %s to %s\n", verb, result.ChangeID, result.ChangePath)
+			for _, name := range result.RevertedSpecs {
+				_, _ = fmt.Fprintf(out, "  reverted %s\n", name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Preview without reverting")
+
+	return cmd
+}
+
+func newSpecTestCmd() *cobra.Command {
+	var (
+		pkg    string
+		run    string
+		from   string
+		strict bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "test [path]",
+		Short: "Run spec scenarios as acceptance tests",
+		Long: `Execute every "#### Scenario:" in openspec/specs against step definitions
+registered by Go tests through spectest.Run, and report each scenario as
+passed, failed or undefined, grouped by requirement.
+
+The command runs "go test" on the selected packages with ` + spectest.ReportEnv + `
+pointing at a temporary file and reads the results the tests append to it.
+Use --from to render a report collected by an earlier test run instead.
+
+Failed scenarios make the command fail; with --strict undefined ones do too.`,
+		Example: `  go-ent spec test
+  go-ent spec test --pkg ./internal/... --run TestSpecScenarios
+  ` + spectest.ReportEnv + `=scenarios.jsonl go test ./... && go-ent spec test --from scenarios.jsonl`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "."
+			if len(args) > 0 {
+				path = args[0]
+			}
+
+			store := spec.NewStore(path)
+
+			exists, err := store.Exists()
+			if err != nil {
+				return fmt.Errorf("check openspec folder: %w", err)
+			}
+
+			if !exists {
+				return fmt.Errorf("no openspec folder found. Run 'go-ent spec init' first")
+			}
+
+			report := from
+			if report == "" {
+				report, err = runScenarioTests(path, pkg, run)
+				if err != nil {
+					return err
+				}
+				defer func() { _ = os.Remove(report) }()
+			}
+
+			data, err := os.ReadFile(report) // #nosec G304 -- user-provided or temporary report path
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("read scenario report: %w", err)
+			}
+			results, err := spec.ParseScenarioResults(data)
+			if err != nil {
+				return fmt.Errorf("parse scenario report: %w", err)
+			}
+
+			groups, err := spec.SummarizeScenarios(store, results)
+			if err != nil {
+				return fmt.Errorf("summarize scenarios: %w", err)
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), spec.FormatScenarioReport(groups))
+
+			failed, undefined := 0, 0
+			for _, g := range groups {
+				for _, sc := range g.Scenarios {
+					switch sc.Status {
+					case spec.ScenarioFailed:
+						failed++
+					case spec.ScenarioUndefined:
+						undefined++
+					}
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d scenario(s) failed", failed)
+			}
+			if strict && undefined > 0 {
+				return fmt.Errorf("%d scenario(s) undefined", undefined)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pkg, "pkg", "./...", "Packages to test")
+	cmd.Flags().StringVar(&run, "run", "Scenarios", "Run only tests matching this regular expression")
+	cmd.Flags().StringVar(&from, "from", "", "Read results from a report file instead of running go test")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on undefined scenarios")
+
+	return cmd
+}
+
+// runScenarioTests runs go test in dir and returns the report file the
+// scenario tests appended their results to. A failing go test run is not an
+// error by itself, since failing scenarios fail their tests.
+func runScenarioTests(dir, pkg, run string) (string, error) {
+	f, err := os.CreateTemp("", "go-ent-scenarios-*.jsonl")
+	if err != nil {
+		return "", fmt.Errorf("create report file: %w", err)
+	}
+	report := f.Name()
+	_ = f.Close()
+
+	goTest := exec.Command("go", "test", "-count=1", "-run", run, pkg) // #nosec G204 -- user-selected packages
+	goTest.Dir = dir
+	goTest.Env = append(os.Environ(), spectest.ReportEnv+"="+report)
+	out, runErr := goTest.CombinedOutput()
+
+	if info, err := os.Stat(report); runErr != nil && (err != nil || info.Size() == 0) {
+		_ = os.Remove(report)
+		return "", fmt.Errorf("go test: %w\n%s", runErr, out)
+	}
+	return report, nil
+}
+
 func printSpecTable(items []spec.ListItem, itemType string) error {
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 	_ = w.Flush() // intentionally ignore error in defer
@@ -0,0 +1,133 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+func TestSpecConflicts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	delta := "## MODIFIED Requirements\n\n### Requirement: Login\nThe system SHALL accept passkeys.\n"
+	for _, id := range []string{"add-passkeys", "harden-login"} {
+		dir := filepath.Join(root, "openspec", "changes", id, "specs", "auth")
+		require.NoError(t, os.MkdirAll(dir, 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.md"), []byte(delta), 0o600))
+	}
+
+	stdout, _, err := executeCommand(t, "spec", "conflicts", root)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `auth: "Login" modify/modify between add-passkeys, harden-login (unresolved)`)
+	assert.Contains(t, stdout, "add-passkeys MODIFIED (")
+
+	_, _, err = executeCommand(t, "spec", "conflicts", root, "--strict")
+	assert.ErrorContains(t, err, "1 unresolved conflict(s)")
+
+	stdout, _, err = executeCommand(t, "spec", "conflicts", root, "--change", "harden-login", "--strict")
+	assert.ErrorContains(t, err, "harden-login is blocked by 1 conflict(s)")
+	assert.Contains(t, stdout, "blocked: requirement \"Login\" in auth conflicts with add-passkeys")
+
+	proposal := filepath.Join(root, "openspec", "changes", "harden-login", "proposal.md")
+	require.NoError(t, os.WriteFile(proposal, []byte("# Harden login\n\n- Requires: `add-passkeys`\n"), 0o600))
+
+	_, _, err = executeCommand(t, "spec", "conflicts", root, "--strict")
+	require.NoError(t, err)
+	_, _, err = executeCommand(t, "spec", "conflicts", root, "--change", "add-passkeys", "--strict")
+	require.NoError(t, err)
+}
+
+func TestSpecTrace(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	specDir := filepath.Join(root, "openspec", "specs", "auth")
+	require.NoError(t, os.MkdirAll(specDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.md"), []byte("# Auth\n\n### Requirement: Login\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "login_test.go"), []byte("package auth\n\nimport \"testing\"\n\nfunc TestLogin(t *testing.T) {}\n"), 0o600))
+
+	stdout, _, err := executeCommand(t, "spec", "trace", root, "--no-git")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "1 requirement(s), 0 untested, 0 orphan test(s), 0 stale reference(s)")
+	assert.Contains(t, stdout, "| auth/Login | — | login_test.go:5 TestLogin |")
+
+	out := filepath.Join(t.TempDir(), "trace.json")
+	stdout, _, err = executeCommand(t, "spec", "trace", root, "--no-git", "-f", "json", "-o", out)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "(1 requirement(s), 0 untested)")
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"requirement": "Login"`)
+}
+
+func TestSpecTest_FromReport(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	specDir := filepath.Join(root, "openspec", "specs", "auth")
+	require.NoError(t, os.MkdirAll(specDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.md"), []byte(`# Auth
+
+### Requirement: Login
+
+#### Scenario: Valid password
+- **WHEN** the password matches
+- **THEN** a session starts
+
+#### Scenario: Locked account
+- **WHEN** the account is locked
+- **THEN** login is refused
+`), 0o600))
+
+	report := filepath.Join(t.TempDir(), "scenarios.jsonl")
+	require.NoError(t, os.WriteFile(report, []byte(`{"capability":"auth","requirement":"Login","scenario":"Valid password","status":"passed"}`+"\n"), 0o600))
+
+	stdout, _, err := executeCommand(t, "spec", "test", root, "--from", report)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "? auth/Login\n  ✓ Valid password\n  ? Locked account: no step definitions ran this scenario\n")
+	assert.Contains(t, stdout, "2 scenario(s) in 1 requirement(s): 1 passed, 0 failed, 1 undefined")
+
+	_, _, err = executeCommand(t, "spec", "test", root, "--from", report, "--strict")
+	assert.ErrorContains(t, err, "1 scenario(s) undefined")
+
+	require.NoError(t, os.WriteFile(report, []byte(`{"capability":"auth","requirement":"Login","scenario":"Locked account","status":"failed","step":"THEN login is refused","error":"session started"}`+"\n"), 0o600))
+	stdout, _, err = executeCommand(t, "spec", "test", root, "--from", report)
+	assert.ErrorContains(t, err, "1 scenario(s) failed")
+	assert.Contains(t, stdout, "✗ Locked account: THEN login is refused: session started")
+}
+
+func TestSpecHistoryUnarchive(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	deltaDir := filepath.Join(root, "openspec", "changes", "add-2fa", "specs", "auth")
+	require.NoError(t, os.MkdirAll(deltaDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(deltaDir, "spec.md"), []byte("## ADDED Requirements\n\n### Requirement: Two-factor authentication\n\n#### Scenario: Enable\n- WHEN user enables 2FA\n- THEN login asks for a code\n"), 0o600))
+	_, err := spec.NewArchiver(spec.NewStore(root)).Archive("add-2fa", false, false, false)
+	require.NoError(t, err)
+
+	stdout, _, err := executeCommand(t, "spec", "history", "auth", root)
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "add-2fa\n  + Two-factor authentication")
+
+	stdout, _, err = executeCommand(t, "spec", "unarchive", "add-2fa", root, "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Would restore add-2fa")
+	assert.Contains(t, stdout, "  reverted auth")
+
+	_, _, err = executeCommand(t, "spec", "unarchive", "add-2fa", root)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(root, "openspec", "specs", "auth"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(deltaDir, "spec.md"))
+	require.NoError(t, err)
+
+	stdout, _, err = executeCommand(t, "spec", "history", "auth", root)
+	require.NoError(t, err)
+	assert.Equal(t, "No archived changes edited auth\n", stdout)
+}
@@ -2,11 +2,14 @@ package cli
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -26,6 +29,9 @@ func newTaskCmd() *cobra.Command {
 	cmd.AddCommand(newTaskRunCmd())
 	cmd.AddCommand(newTaskCompleteCmd())
 	cmd.AddCommand(newTaskListCmd())
+	cmd.AddCommand(newTaskPlanCmd())
+	cmd.AddCommand(newTaskExportCmd())
+	cmd.AddCommand(newTaskImportCmd())
 
 	return cmd
 }
@@ -112,6 +118,108 @@ func newTaskListCmd() *cobra.Command {
 	return cmd
 }
 
+func newTaskPlanCmd() *cobra.Command {
+	var (
+		changeID        string
+		format          string
+		defaultEstimate float64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Schedule remaining tasks along the critical path",
+		Long: `Schedule remaining tasks with the critical path method.
+
+Effort comes from estimate comments in tasks.md, in minutes (m), hours (h),
+days (d, 8h) or weeks (w, 5d):
+
+  - [ ] 2.1 Add token refresh <!-- depends: 1 --> <!-- estimate: 4h -->
+
+Tasks without an estimate count as --default-estimate hours. The plan shows
+slack per task, the critical path, and a projected completion based on the
+throughput of completed tasks.`,
+		Example: `  go-ent task plan
+  go-ent task plan --change add-sso
+  go-ent task plan --format mermaid > plan.mmd`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskPlan(cmd, changeID, format, defaultEstimate)
+		},
+	}
+
+	cmd.Flags().StringVarP(&changeID, "change", "c", "", "plan a single change")
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "output format (text, mermaid, json)")
+	cmd.Flags().Float64Var(&defaultEstimate, "default-estimate", spec.DefaultTaskEstimate, "hours assumed for tasks without an estimate")
+
+	return cmd
+}
+
+func newTaskExportCmd() *cobra.Command {
+	var (
+		format   string
+		changeID string
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export tasks to GitHub issues JSON, Jira CSV or JSON Lines",
+		Long: `Export registry tasks to an issue-tracker file.
+
+Formats:
+  github  JSON array shaped like 'gh issue list --json number,title,body,state,labels,assignees'
+  jira    Jira issue CSV with an "OpenSpec Task" column
+  jsonl   one change or task record per line, without loss
+
+Change, priority and statuses other than open/closed travel as change:,
+priority: and status: labels; task IDs and dependencies as HTML comments in
+the issue body or as CSV columns, so an export imports back unchanged.`,
+		Example: `  go-ent task export --format github -o issues.json
+  go-ent task export --format jira --change add-sso -o sso.csv`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskExport(cmd, format, changeID, output)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "jsonl", "output format (github, jira, jsonl)")
+	cmd.Flags().StringVarP(&changeID, "change", "c", "", "export a single change")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "write to file instead of stdout")
+
+	return cmd
+}
+
+func newTaskImportCmd() *cobra.Command {
+	var (
+		format   string
+		changeID string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import tasks from GitHub issues JSON, Jira CSV or JSON Lines",
+		Long: `Import tasks from an issue-tracker file into the registry and tasks.md.
+
+Issues are matched to tasks by the task ID they carry, else by change and
+title. New tasks are appended to the tasks.md of their change; status,
+priority, assignee and dependencies of existing tasks are updated. The format
+is taken from the file extension (.json github, .csv jira, .jsonl) unless
+--format is given.`,
+		Example: `  gh issue list --state all --json number,title,body,state,labels,assignees > issues.json
+  go-ent task import issues.json --change add-sso --dry-run
+  go-ent task import sso.csv`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTaskImport(cmd, args[0], format, changeID, dryRun)
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "", "input format (github, jira, jsonl)")
+	cmd.Flags().StringVarP(&changeID, "change", "c", "", "change for issues without a change: label")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "show the diff without writing")
+
+	return cmd
+}
+
 func runTaskNext(ctx context.Context, count int) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -299,6 +407,150 @@ func runTaskList(ctx context.Context, status, changeID string, unblocked bool) e
 	return nil
 }
 
+func runTaskPlan(cmd *cobra.Command, changeID, format string, defaultEstimate float64) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get current directory: %w", err)
+	}
+
+	store := spec.NewStore(cwd)
+	registryStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return fmt.Errorf("create registry store: %w", err)
+	}
+	defer func() { _ = registryStore.Close() }()
+
+	plan, err := registryStore.Plan(spec.PlanOptions{
+		ChangeID:        changeID,
+		DefaultEstimate: defaultEstimate,
+	})
+	if err != nil {
+		return fmt.Errorf("plan tasks: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	switch format {
+	case "text":
+		_, _ = fmt.Fprint(out, spec.FormatPlan(plan))
+	case "mermaid":
+		_, _ = fmt.Fprint(out, spec.FormatPlanMermaid(plan))
+	case "json":
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal plan: %w", err)
+		}
+		_, _ = fmt.Fprintln(out, string(data))
+	default:
+		return fmt.Errorf("unknown format %q: use text, mermaid, or json", format)
+	}
+	return nil
+}
+
+func runTaskExport(cmd *cobra.Command, formatName, changeID, output string) error {
+	format, err := spec.ParseTrackerFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get current directory: %w", err)
+	}
+
+	store := spec.NewStore(cwd)
+	registryStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return fmt.Errorf("create registry store: %w", err)
+	}
+	defer func() { _ = registryStore.Close() }()
+
+	tasks, err := registryStore.ListTasks(spec.TaskFilter{ChangeID: changeID})
+	if err != nil {
+		return fmt.Errorf("list tasks: %w", err)
+	}
+	registry, err := registryStore.Load()
+	if err != nil {
+		return fmt.Errorf("load registry: %w", err)
+	}
+	var changes []spec.ChangeSummary
+	for id, c := range registry.Changes {
+		if changeID == "" || id == changeID {
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+
+	var buf bytes.Buffer
+	if err := spec.ExportTasks(&buf, format, changes, tasks); err != nil {
+		return fmt.Errorf("export tasks: %w", err)
+	}
+
+	if output == "" {
+		_, _ = cmd.OutOrStdout().Write(buf.Bytes())
+		return nil
+	}
+	if err := os.WriteFile(output, buf.Bytes(), 0600); err != nil {
+		return fmt.Errorf("write %s: %w", output, err)
+	}
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "✅ Exported %d task(s) to %s\n", len(tasks), output)
+	return nil
+}
+
+func runTaskImport(cmd *cobra.Command, path, formatName, changeID string, dryRun bool) error {
+	var (
+		format spec.TrackerFormat
+		err    error
+	)
+	if formatName != "" {
+		format, err = spec.ParseTrackerFormat(formatName)
+	} else {
+		format, err = spec.TrackerFormatFromPath(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path) // #nosec G304 -- user-selected import file
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	imp, err := spec.ParseTrackerImport(f, format)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("get current directory: %w", err)
+	}
+
+	store := spec.NewStore(cwd)
+	registryStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return fmt.Errorf("create registry store: %w", err)
+	}
+	defer func() { _ = registryStore.Close() }()
+
+	diff, err := registryStore.DiffImport(imp, spec.ImportOptions{ChangeID: changeID})
+	if err != nil {
+		return fmt.Errorf("diff import: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	_, _ = fmt.Fprint(out, diff.String())
+	if dryRun || len(diff.Added)+len(diff.Updated) == 0 {
+		return nil
+	}
+
+	if err := registryStore.ApplyImport(diff); err != nil {
+		return fmt.Errorf("apply import: %w", err)
+	}
+	_, _ = fmt.Fprintln(out, "✅ Import applied")
+	return nil
+}
+
 func runTaskComplete(ctx context.Context, taskIDStr string, yes bool) error {
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -402,13 +654,13 @@ func runTaskComplete(ctx context.Context, taskIDStr string, yes bool) error {
 		return fmt.Errorf("write tasks.md: %w", err)
 	}
 
-	stateStore := registryStore.StateStore()
-
-	if err := stateStore.SyncFromTasksMd(); err != nil {
+	if _, err := registryStore.Sync(spec.SyncOptions{}); err != nil {
 		_ = os.WriteFile(tasksPath, []byte(originalContent), 0644) //nolint:gosec
 		return fmt.Errorf("sync from tasks.md (rolled back tasks.md): %w", err)
 	}
 
+	stateStore := registryStore.StateStore()
+
 	changeStatePath := filepath.Join(store.SpecPath(), "changes", taskID.ChangeID, "state.md")
 	if err := stateStore.WriteChangeStateMd(taskID.ChangeID, changeStatePath); err != nil {
 		_ = os.WriteFile(tasksPath, []byte(originalContent), 0644) //nolint:gosec
@@ -1,10 +1,13 @@
 package cli_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
 )
 
 func TestTaskCommands(t *testing.T) {
@@ -118,6 +121,90 @@ func TestTaskHelp(t *testing.T) {
 	})
 }
 
+func TestTaskPlan(t *testing.T) {
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte(`# Tasks
+
+- [ ] Add provider <!-- estimate: 4h -->
+- [ ] Add callback <!-- depends: 1 --> <!-- estimate: 2h -->
+- [ ] Write docs
+`), 0o600))
+	t.Chdir(root)
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	stdout, _, err := executeCommand(t, "task", "plan")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Effort: 7.0h remaining, critical path 6.0h")
+	assert.Contains(t, stdout, "Critical path: add-sso/1 → add-sso/2")
+	assert.Contains(t, stdout, "slack 5.0h")
+
+	stdout, _, err = executeCommand(t, "task", "plan", "--change", "add-sso", "--format", "mermaid")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "section add-sso")
+	assert.Contains(t, stdout, ":crit, add_sso_1,")
+
+	_, _, err = executeCommand(t, "task", "plan", "--format", "svg")
+	require.Error(t, err)
+}
+
+func TestTaskExportImport(t *testing.T) {
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n"), 0o600))
+	t.Chdir(root)
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	stdout, _, err := executeCommand(t, "task", "export", "--format", "github")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, `"title": "Add provider"`)
+	assert.Contains(t, stdout, "<!-- openspec-task: add-sso/1 -->")
+
+	stdout, _, err = executeCommand(t, "task", "export", "--format", "jsonl", "-o", "tasks.jsonl")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Exported 1 task(s) to tasks.jsonl")
+
+	issues := `[
+  {"number": 7, "title": "Add provider", "body": "<!-- openspec-task: add-sso/1 -->", "state": "CLOSED", "labels": [], "assignees": []},
+  {"number": 8, "title": "Add callback", "body": "Depends on #7", "state": "OPEN", "labels": [{"name": "priority:high"}], "assignees": []}
+]`
+	require.NoError(t, os.WriteFile("issues.json", []byte(issues), 0o600))
+
+	stdout, _, err = executeCommand(t, "task", "import", "issues.json", "--change", "add-sso", "--dry-run")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "+ add-sso/2 \"Add callback\" [pending, high] depends on add-sso/1 (#8)")
+	assert.Contains(t, stdout, "1 added, 1 updated, 0 unchanged")
+	assert.NotContains(t, stdout, "Import applied")
+
+	stdout, _, err = executeCommand(t, "task", "import", "issues.json", "--change", "add-sso")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "Import applied")
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [x] Add provider\n\n- [ ] Add callback <!-- depends: 1 -->\n", string(content))
+
+	stdout, _, err = executeCommand(t, "task", "import", "tasks.jsonl")
+	require.NoError(t, err)
+	assert.Contains(t, stdout, "0 added, 1 updated", "the earlier export still has task 1 pending")
+
+	_, _, err = executeCommand(t, "task", "import", "issues.txt")
+	require.Error(t, err)
+}
+
 func TestTaskCommandFlags(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1,18 +1,134 @@
 package config
 
-import "github.com/victorzhuk/go-ent/internal/domain"
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/victorzhuk/go-ent/internal/domain"
+)
 
 // MetricsConfig configures metrics collection and privacy settings.
 type MetricsConfig struct {
 	// Enabled enables metrics collection (default: true)
 	Enabled bool `yaml:"enabled" env:"GOENT_METRICS_ENABLED"`
+
+	// Listen is the address of the Prometheus /metrics listener, e.g. ":9464".
+	// Empty disables the listener (default).
+	Listen string `yaml:"listen,omitempty" env:"GOENT_METRICS_LISTEN"`
+
+	// RawRetentionDays is how long individual events are kept (default: 7).
+	RawRetentionDays int `yaml:"raw_retention_days,omitempty"`
+
+	// HourlyRetentionDays is how long hourly rollups are kept (default: 90).
+	HourlyRetentionDays int `yaml:"hourly_retention_days,omitempty"`
+
+	// DailyRetentionDays is how long daily rollups are kept (default: 730).
+	DailyRetentionDays int `yaml:"daily_retention_days,omitempty"`
 }
 
 // Validate validates the metrics configuration.
 func (m *MetricsConfig) Validate() error {
+	if m.RawRetentionDays < 0 {
+		return fmt.Errorf("raw_retention_days must be >= 0: %w", ErrInvalidMetricsConfig)
+	}
+	if m.HourlyRetentionDays < 0 {
+		return fmt.Errorf("hourly_retention_days must be >= 0: %w", ErrInvalidMetricsConfig)
+	}
+	if m.DailyRetentionDays < 0 {
+		return fmt.Errorf("daily_retention_days must be >= 0: %w", ErrInvalidMetricsConfig)
+	}
 	return nil
 }
 
+// TracingConfig configures span export for tools, engine, workers and providers.
+type TracingConfig struct {
+	// Enabled enables tracing (default: false)
+	Enabled bool `yaml:"enabled" env:"GOENT_TRACING_ENABLED"`
+
+	// Exporter selects the span exporter: "otlp" (default) or "jsonl".
+	Exporter string `yaml:"exporter,omitempty" env:"GOENT_TRACING_EXPORTER"`
+
+	// Endpoint is the OTLP/HTTP traces endpoint (default: http://localhost:4318/v1/traces).
+	Endpoint string `yaml:"endpoint,omitempty" env:"GOENT_TRACING_ENDPOINT"`
+
+	// Headers are sent with every OTLP export request.
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// File is the JSONL output path, relative to the project root (default: data/traces.jsonl).
+	File string `yaml:"file,omitempty"`
+
+	// ServiceName is reported as service.name (default: go-ent).
+	ServiceName string `yaml:"service_name,omitempty"`
+}
+
+// DefaultTraceFile is the JSONL trace output path relative to the project root.
+const DefaultTraceFile = "data/traces.jsonl"
+
+// FilePath returns the JSONL output path resolved against projectRoot.
+// Absolute paths are returned unchanged.
+func (t *TracingConfig) FilePath(projectRoot string) string {
+	file := t.File
+	if file == "" {
+		file = DefaultTraceFile
+	}
+	if filepath.IsAbs(file) {
+		return file
+	}
+	return filepath.Join(projectRoot, file)
+}
+
+// Validate validates the tracing configuration.
+func (t *TracingConfig) Validate() error {
+	switch t.Exporter {
+	case "", "otlp", "jsonl":
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown exporter %q", ErrInvalidTracingConfig, t.Exporter)
+	}
+}
+
+// MarketplaceConfig selects where plugin_search and plugin_install find plugins.
+type MarketplaceConfig struct {
+	// Backend is "http" (default), "fs" for a local directory with an
+	// index.yaml, or "git" for a repository holding the same layout.
+	Backend string `yaml:"backend,omitempty" env:"GOENT_MARKETPLACE_BACKEND"`
+
+	// URL is the marketplace API base URL for "http", or the repository to
+	// clone for "git".
+	URL string `yaml:"url,omitempty" env:"GOENT_MARKETPLACE_URL"`
+
+	// Path is the marketplace directory for "fs", or the clone destination
+	// for "git" (default: .go-ent/marketplace). Relative to the project root.
+	Path string `yaml:"path,omitempty" env:"GOENT_MARKETPLACE_PATH"`
+
+	// Ref is the branch or tag to check out for "git".
+	Ref string `yaml:"ref,omitempty"`
+
+	// AllowUnsigned installs archives that no trusted key in
+	// .go-ent/trusted-keys signed. Checksums are verified regardless.
+	AllowUnsigned bool `yaml:"allow_unsigned,omitempty"`
+}
+
+// Validate validates the marketplace configuration.
+func (m *MarketplaceConfig) Validate() error {
+	switch m.Backend {
+	case "", "http":
+		return nil
+	case "fs":
+		if m.Path == "" {
+			return fmt.Errorf("%w: fs backend requires path", ErrInvalidMarketplaceConfig)
+		}
+		return nil
+	case "git":
+		if m.URL == "" {
+			return fmt.Errorf("%w: git backend requires url", ErrInvalidMarketplaceConfig)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown backend %q", ErrInvalidMarketplaceConfig, m.Backend)
+	}
+}
+
 // Config represents the complete go-ent configuration.
 // Supports hierarchical loading from project-level (.go-ent/config.yaml)
 // with environment variable overrides.
@@ -40,6 +156,12 @@ type Config struct {
 
 	// Metrics configures metrics collection and privacy settings.
 	Metrics MetricsConfig `yaml:"metrics,omitempty"`
+
+	// Tracing configures span export.
+	Tracing TracingConfig `yaml:"tracing,omitempty"`
+
+	// Marketplace selects the plugin marketplace backend.
+	Marketplace MarketplaceConfig `yaml:"marketplace,omitempty"`
 }
 
 // RuntimeConfig configures execution environment preferences.
@@ -118,6 +240,14 @@ func (c *Config) Validate() error {
 		return err
 	}
 
+	if err := c.Tracing.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Marketplace.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
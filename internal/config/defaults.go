@@ -48,6 +48,15 @@ import "github.com/victorzhuk/go-ent/internal/domain"
 //
 // Metrics:
 //   - enabled: true (metrics collection enabled by default)
+//   - listen: "" (no /metrics HTTP listener)
+//   - raw_retention_days: 7, hourly_retention_days: 90, daily_retention_days: 730
+//
+// Tracing:
+//   - enabled: false (spans are only exported when explicitly enabled)
+//
+// Marketplace:
+//   - backend: http (the public marketplace API)
+//   - allow_unsigned: false (installs require a signature from a trusted key)
 func DefaultConfig() *Config {
 	return &Config{
 		Version: "1.0",
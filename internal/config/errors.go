@@ -18,6 +18,15 @@ var (
 	// ErrInvalidModelConfig indicates the model configuration is invalid.
 	ErrInvalidModelConfig = errors.New("invalid model config")
 
+	// ErrInvalidMetricsConfig indicates the metrics configuration is invalid.
+	ErrInvalidMetricsConfig = errors.New("invalid metrics config")
+
+	// ErrInvalidTracingConfig indicates the tracing configuration is invalid.
+	ErrInvalidTracingConfig = errors.New("invalid tracing config")
+
+	// ErrInvalidMarketplaceConfig indicates the marketplace configuration is invalid.
+	ErrInvalidMarketplaceConfig = errors.New("invalid marketplace config")
+
 	// ErrConfigNotFound indicates the configuration file was not found.
 	ErrConfigNotFound = errors.New("config file not found")
 
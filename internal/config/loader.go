@@ -80,6 +80,17 @@ func Load(projectRoot string) (*Config, error) {
 //
 // Metrics Section:
 //   - GOENT_METRICS_ENABLED: Enable or disable metrics collection (true|false)
+//   - GOENT_METRICS_LISTEN: Address of the Prometheus /metrics listener (e.g. :9464)
+//
+// Tracing Section:
+//   - GOENT_TRACING_ENABLED: Enable or disable tracing (true|false)
+//   - GOENT_TRACING_EXPORTER: Span exporter (otlp|jsonl)
+//   - GOENT_TRACING_ENDPOINT: OTLP/HTTP traces endpoint URL
+//
+// Marketplace Section:
+//   - GOENT_MARKETPLACE_BACKEND: Plugin marketplace backend (http|fs|git)
+//   - GOENT_MARKETPLACE_URL: Marketplace API base URL or git repository
+//   - GOENT_MARKETPLACE_PATH: Marketplace directory or git clone destination
 //
 // # Error Handling
 //
@@ -141,6 +152,38 @@ func LoadWithEnv(projectRoot string, getenv func(string) string) (*Config, error
 		cfg.Metrics.Enabled = val
 	}
 
+	if v := getenv("GOENT_METRICS_LISTEN"); v != "" {
+		cfg.Metrics.Listen = v
+	}
+
+	if v := getenv("GOENT_TRACING_ENABLED"); v != "" {
+		var val bool
+		if _, err := fmt.Sscanf(v, "%t", &val); err != nil {
+			return nil, fmt.Errorf("invalid GOENT_TRACING_ENABLED: %w", err)
+		}
+		cfg.Tracing.Enabled = val
+	}
+
+	if v := getenv("GOENT_TRACING_EXPORTER"); v != "" {
+		cfg.Tracing.Exporter = v
+	}
+
+	if v := getenv("GOENT_TRACING_ENDPOINT"); v != "" {
+		cfg.Tracing.Endpoint = v
+	}
+
+	if v := getenv("GOENT_MARKETPLACE_BACKEND"); v != "" {
+		cfg.Marketplace.Backend = v
+	}
+
+	if v := getenv("GOENT_MARKETPLACE_URL"); v != "" {
+		cfg.Marketplace.URL = v
+	}
+
+	if v := getenv("GOENT_MARKETPLACE_PATH"); v != "" {
+		cfg.Marketplace.Path = v
+	}
+
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("validate config after env overrides: %w", err)
 	}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarketplaceConfig_Validate(t *testing.T) {
+	valid := []MarketplaceConfig{
+		{},
+		{Backend: "http", URL: "https://mirror.local/api/v1"},
+		{Backend: "fs", Path: "/srv/marketplace"},
+		{Backend: "git", URL: "git@example.com:plugins.git"},
+	}
+	for _, cfg := range valid {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%+v: unexpected error: %v", cfg, err)
+		}
+	}
+
+	invalid := []MarketplaceConfig{
+		{Backend: "fs"},
+		{Backend: "git"},
+		{Backend: "s3"},
+	}
+	for _, cfg := range invalid {
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidMarketplaceConfig) {
+			t.Errorf("%+v: expected ErrInvalidMarketplaceConfig, got %v", cfg, err)
+		}
+	}
+}
+
+func TestLoadWithEnv_Marketplace(t *testing.T) {
+	getenv := func(key string) string {
+		switch key {
+		case "GOENT_MARKETPLACE_BACKEND":
+			return "fs"
+		case "GOENT_MARKETPLACE_PATH":
+			return "/mnt/plugins"
+		}
+		return ""
+	}
+
+	cfg, err := LoadWithEnv(".", getenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Marketplace.Backend != "fs" || cfg.Marketplace.Path != "/mnt/plugins" {
+		t.Errorf("unexpected marketplace config from env: %+v", cfg.Marketplace)
+	}
+}
+
+func TestMarketplaceConfig_AllowUnsigned(t *testing.T) {
+	if DefaultConfig().Marketplace.AllowUnsigned {
+		t.Error("unsigned plugins must be refused by default")
+	}
+
+	var cfg Config
+	data := []byte("marketplace:\n  backend: fs\n  path: mirror\n  allow_unsigned: true\n")
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cfg.Marketplace.AllowUnsigned {
+		t.Errorf("allow_unsigned not loaded: %+v", cfg.Marketplace)
+	}
+}
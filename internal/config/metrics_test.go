@@ -1,6 +1,8 @@
 package config
 
 import (
+	"errors"
+	"path/filepath"
 	"testing"
 )
 
@@ -97,3 +99,94 @@ func TestLoadWithEnv_Metrics(t *testing.T) {
 		}
 	})
 }
+
+func TestTracingConfig_Validate(t *testing.T) {
+	for _, exporter := range []string{"", "otlp", "jsonl"} {
+		cfg := TracingConfig{Enabled: true, Exporter: exporter}
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("exporter %q: unexpected error: %v", exporter, err)
+		}
+	}
+
+	cfg := TracingConfig{Enabled: true, Exporter: "zipkin"}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidTracingConfig) {
+		t.Errorf("expected ErrInvalidTracingConfig, got %v", err)
+	}
+}
+
+func TestTracingConfig_FilePath(t *testing.T) {
+	root := filepath.Join("/srv", "project")
+
+	cfg := TracingConfig{}
+	if got, want := cfg.FilePath(root), filepath.Join(root, "data", "traces.jsonl"); got != want {
+		t.Errorf("default path = %q, want %q", got, want)
+	}
+
+	cfg.File = "logs/spans.jsonl"
+	if got, want := cfg.FilePath(root), filepath.Join(root, "logs", "spans.jsonl"); got != want {
+		t.Errorf("relative path = %q, want %q", got, want)
+	}
+
+	cfg.File = filepath.Join("/var", "log", "spans.jsonl")
+	if got := cfg.FilePath(root); got != cfg.File {
+		t.Errorf("absolute path = %q, want %q", got, cfg.File)
+	}
+}
+
+func TestLoadWithEnv_Tracing(t *testing.T) {
+	getenv := func(key string) string {
+		switch key {
+		case "GOENT_TRACING_ENABLED":
+			return "true"
+		case "GOENT_TRACING_EXPORTER":
+			return "jsonl"
+		case "GOENT_TRACING_ENDPOINT":
+			return "http://collector:4318/v1/traces"
+		}
+		return ""
+	}
+
+	cfg, err := LoadWithEnv(".", getenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !cfg.Tracing.Enabled || cfg.Tracing.Exporter != "jsonl" || cfg.Tracing.Endpoint != "http://collector:4318/v1/traces" {
+		t.Errorf("unexpected tracing config from env: %+v", cfg.Tracing)
+	}
+}
+
+func TestLoadWithEnv_MetricsListen(t *testing.T) {
+	getenv := func(key string) string {
+		if key == "GOENT_METRICS_LISTEN" {
+			return "127.0.0.1:9464"
+		}
+		return ""
+	}
+
+	cfg, err := LoadWithEnv(".", getenv)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if cfg.Metrics.Listen != "127.0.0.1:9464" {
+		t.Errorf("Metrics.Listen = %q, want 127.0.0.1:9464", cfg.Metrics.Listen)
+	}
+}
+
+func TestMetricsConfig_ValidateRetention(t *testing.T) {
+	cfg := MetricsConfig{Enabled: true, RawRetentionDays: 1, HourlyRetentionDays: 30, DailyRetentionDays: 365}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for _, cfg := range []MetricsConfig{
+		{RawRetentionDays: -1},
+		{HourlyRetentionDays: -1},
+		{DailyRetentionDays: -1},
+	} {
+		if err := cfg.Validate(); !errors.Is(err, ErrInvalidMetricsConfig) {
+			t.Errorf("%+v: expected ErrInvalidMetricsConfig, got %v", cfg, err)
+		}
+	}
+}
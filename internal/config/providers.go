@@ -158,14 +158,24 @@ type HealthConfig struct {
 	WorkerTimeout int `yaml:"worker_timeout,omitempty"`
 	MaxRetries    int `yaml:"max_retries,omitempty"`
 	RetryDelay    int `yaml:"retry_delay,omitempty"`
+
+	// FailureThreshold is the number of consecutive failures that opens a
+	// provider's circuit breaker.
+	FailureThreshold int `yaml:"failure_threshold,omitempty"`
+
+	// OpenTimeout is the number of seconds an open circuit waits before
+	// letting a probe request through.
+	OpenTimeout int `yaml:"open_timeout,omitempty"`
 }
 
 func DefaultHealthConfig() *HealthConfig {
 	return &HealthConfig{
-		CheckInterval: 30,
-		WorkerTimeout: 300,
-		MaxRetries:    3,
-		RetryDelay:    5,
+		CheckInterval:    30,
+		WorkerTimeout:    300,
+		MaxRetries:       3,
+		RetryDelay:       5,
+		FailureThreshold: 5,
+		OpenTimeout:      30,
 	}
 }
 
@@ -272,6 +282,12 @@ func (h *HealthConfig) Validate() error {
 	if h.RetryDelay < 0 {
 		return fmt.Errorf("retry_delay cannot be negative")
 	}
+	if h.FailureThreshold < 0 {
+		return fmt.Errorf("failure_threshold cannot be negative")
+	}
+	if h.OpenTimeout < 0 {
+		return fmt.Errorf("open_timeout cannot be negative")
+	}
 	return nil
 }
 
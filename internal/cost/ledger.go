@@ -0,0 +1,203 @@
+// Package cost persists spend entries and builds budget reports from them.
+package cost
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+// LedgerFile is the ledger path relative to the project root.
+const LedgerFile = "data/costs.jsonl"
+
+// Entry sources.
+const (
+	SourceEngine   = "engine"
+	SourceWorker   = "worker"
+	SourceWorkflow = "workflow"
+)
+
+// Entry is a single spend record.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Source    string    `json:"source,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	Model     string    `json:"model,omitempty"`
+	ChangeID  string    `json:"change_id,omitempty"`
+	AgentRole string    `json:"agent_role,omitempty"`
+	TaskID    string    `json:"task_id,omitempty"`
+	TokensIn  int       `json:"tokens_in"`
+	TokensOut int       `json:"tokens_out"`
+	Cost      float64   `json:"cost"`
+}
+
+// Ledger is an append-only JSONL file of spend entries. Each Record opens the
+// file in append mode, so the MCP server and the CLI can share it.
+type Ledger struct {
+	mu   sync.Mutex
+	path string
+}
+
+// LedgerPath returns the ledger path of the project at root. The MCP server
+// and the cost report both go through it so they read the same file.
+func LedgerPath(root string) string {
+	return filepath.Join(root, LedgerFile)
+}
+
+// OpenLedger returns the ledger at path, creating its directory.
+func OpenLedger(path string) (*Ledger, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("create dir: %w", err)
+	}
+	return &Ledger{path: path}, nil
+}
+
+// Path returns the ledger file path.
+func (l *Ledger) Path() string {
+	return l.path
+}
+
+// Record appends e, defaulting its timestamp to now.
+func (l *Ledger) Record(e Entry) error {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600) // #nosec G304 -- ledger path is controlled
+	if err != nil {
+		return fmt.Errorf("open ledger: %w", err)
+	}
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write ledger: %w", err)
+	}
+	return f.Close()
+}
+
+// Entries returns all entries at or after since, oldest first. A missing
+// ledger yields no entries; malformed lines are skipped.
+func (l *Ledger) Entries(since time.Time) ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path) // #nosec G304 -- ledger path is controlled
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("open ledger: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			slog.Warn("skipping malformed cost entry", "path", l.path, "line", line, "error", err)
+			continue
+		}
+		if !since.IsZero() && e.Timestamp.Before(since) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read ledger: %w", err)
+	}
+
+	sortEntries(entries)
+	return entries, nil
+}
+
+// FromWorkflows converts workflow execution history into entries attributed
+// to each workflow's change.
+func FromWorkflows(states []spec.WorkflowState) []Entry {
+	var entries []Entry
+	for _, state := range states {
+		for _, rec := range state.ExecutionHistory {
+			entries = append(entries, Entry{
+				Timestamp: rec.ExecutedAt,
+				Source:    SourceWorkflow,
+				Provider:  rec.Runtime,
+				Model:     rec.Model,
+				ChangeID:  state.ChangeID,
+				AgentRole: string(rec.AgentRole),
+				TaskID:    rec.TaskID,
+				TokensIn:  rec.TokensIn,
+				TokensOut: rec.TokensOut,
+				Cost:      rec.Cost,
+			})
+		}
+	}
+	sortEntries(entries)
+	return entries
+}
+
+// Collect loads the ledger and workflow history of the project at root.
+// Workflow history is skipped with a warning when the workflow database is
+// unavailable, e.g. locked by another process.
+func Collect(root string, since time.Time) ([]Entry, error) {
+	ledger, err := OpenLedger(LedgerPath(root))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := ledger.Entries(since)
+	if err != nil {
+		return nil, err
+	}
+
+	store := spec.NewStore(root)
+	if _, err := os.Stat(store.WorkflowDBPath()); err == nil {
+		wfStore, err := spec.NewWorkflowStore(store)
+		if err != nil {
+			slog.Warn("skipping workflow cost history", "error", err)
+		} else {
+			defer func() { _ = wfStore.Close() }()
+
+			states, err := wfStore.List(spec.WorkflowFilter{})
+			if err != nil {
+				return nil, fmt.Errorf("list workflows: %w", err)
+			}
+			for _, e := range FromWorkflows(states) {
+				if since.IsZero() || !e.Timestamp.Before(since) {
+					entries = append(entries, e)
+				}
+			}
+		}
+	}
+
+	sortEntries(entries)
+	return entries, nil
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+}
@@ -0,0 +1,115 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/domain"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+func TestLedger_RecordAndEntries(t *testing.T) {
+	t.Parallel()
+
+	ledger, err := OpenLedger(filepath.Join(t.TempDir(), "data", "costs.jsonl"))
+	require.NoError(t, err)
+
+	entries, err := ledger.Entries(time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	base := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, ledger.Record(Entry{Timestamp: base.Add(time.Hour), Provider: "anthropic", Cost: 0.2}))
+	require.NoError(t, ledger.Record(Entry{Timestamp: base, Provider: "openai", Cost: 0.1}))
+	require.NoError(t, ledger.Record(Entry{Provider: "now", Cost: 0.3}))
+
+	entries, err = ledger.Entries(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "openai", entries[0].Provider)
+	assert.Equal(t, "anthropic", entries[1].Provider)
+	assert.False(t, entries[2].Timestamp.IsZero())
+
+	since, err := ledger.Entries(base.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, since, 2)
+}
+
+func TestLedger_SkipsMalformedLines(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "costs.jsonl")
+	content := `{"timestamp":"2026-05-10T12:00:00Z","cost":0.5}
+not json
+
+{"timestamp":"2026-05-11T12:00:00Z","cost":0.25}
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	ledger, err := OpenLedger(path)
+	require.NoError(t, err)
+
+	entries, err := ledger.Entries(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.InDelta(t, 0.75, entries[0].Cost+entries[1].Cost, 1e-9)
+}
+
+func TestFromWorkflows(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	states := []spec.WorkflowState{
+		{
+			ChangeID: "add-auth",
+			ExecutionHistory: []spec.ExecutionRecord{
+				{TaskID: "add-auth/1.1", AgentRole: domain.AgentRoleSenior, Model: "sonnet", Runtime: "claude-code", Cost: 0.4, ExecutedAt: at},
+			},
+		},
+		{ChangeID: "empty"},
+	}
+
+	entries := FromWorkflows(states)
+	require.Len(t, entries, 1)
+	assert.Equal(t, Entry{
+		Timestamp: at,
+		Source:    SourceWorkflow,
+		Provider:  "claude-code",
+		Model:     "sonnet",
+		ChangeID:  "add-auth",
+		AgentRole: string(domain.AgentRoleSenior),
+		TaskID:    "add-auth/1.1",
+		Cost:      0.4,
+	}, entries[0])
+}
+
+func TestCollect(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "openspec"), 0750))
+
+	ledger, err := OpenLedger(LedgerPath(root))
+	require.NoError(t, err)
+	at := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, ledger.Record(Entry{Timestamp: at, Source: SourceEngine, Cost: 1}))
+	require.NoError(t, ledger.Record(Entry{Timestamp: at.AddDate(0, -1, 0), Source: SourceEngine, Cost: 5}))
+
+	wfStore, err := spec.NewWorkflowStore(spec.NewStore(root))
+	require.NoError(t, err)
+	state := spec.NewWorkflowState("add-auth", "plan")
+	state.RecordExecution(spec.ExecutionRecord{Cost: 2, ExecutedAt: at.Add(time.Hour)})
+	require.NoError(t, wfStore.Save(state))
+	require.NoError(t, wfStore.Close())
+
+	entries, err := Collect(root, MonthStart(at))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, SourceEngine, entries[0].Source)
+	assert.Equal(t, SourceWorkflow, entries[1].Source)
+	assert.Equal(t, "add-auth", entries[1].ChangeID)
+}
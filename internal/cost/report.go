@@ -0,0 +1,340 @@
+package cost
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/execution"
+)
+
+// WarnRatio is the share of a limit at which the report starts warning.
+const WarnRatio = 0.8
+
+// Line aggregates spend for one key of a breakdown.
+type Line struct {
+	Key       string  `json:"key"`
+	Count     int     `json:"count"`
+	TokensIn  int     `json:"tokens_in"`
+	TokensOut int     `json:"tokens_out"`
+	Cost      float64 `json:"cost"`
+}
+
+// BudgetStatus compares spend with the configured limits. Zero limits are
+// not enforced.
+type BudgetStatus struct {
+	Daily            float64 `json:"daily_limit"`
+	Monthly          float64 `json:"monthly_limit"`
+	PerTask          float64 `json:"per_task_limit"`
+	Today            float64 `json:"today"`
+	MonthToDate      float64 `json:"month_to_date"`
+	DailyRemaining   float64 `json:"daily_remaining,omitempty"`
+	MonthlyRemaining float64 `json:"monthly_remaining,omitempty"`
+}
+
+// Projection extrapolates month-end spend from the month-to-date run rate.
+type Projection struct {
+	DaysElapsed float64 `json:"days_elapsed"`
+	DaysInMonth int     `json:"days_in_month"`
+	RunRate     float64 `json:"run_rate_per_day"`
+	MonthEnd    float64 `json:"month_end"`
+}
+
+// Report consolidates spend for one calendar month.
+type Report struct {
+	Month       string       `json:"month"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Total       float64      `json:"total"`
+	TokensIn    int          `json:"tokens_in"`
+	TokensOut   int          `json:"tokens_out"`
+	ByDay       []Line       `json:"by_day"`
+	ByProvider  []Line       `json:"by_provider"`
+	ByModel     []Line       `json:"by_model"`
+	ByChange    []Line       `json:"by_change"`
+	ByRole      []Line       `json:"by_role"`
+	Budget      BudgetStatus `json:"budget"`
+	Projection  Projection   `json:"projection"`
+	Warnings    []string     `json:"warnings,omitempty"`
+}
+
+// MonthStart returns the first instant of the month containing t.
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+// BuildReport summarizes entries for the month containing month, as seen at
+// now. Entries outside that month are ignored. For past months, the
+// projection equals the final total and no daily figures are reported.
+func BuildReport(entries []Entry, budget config.BudgetConfig, month, now time.Time) Report {
+	start := MonthStart(month)
+	end := start.AddDate(0, 1, 0)
+	current := !now.Before(start) && now.Before(end)
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	r := Report{
+		Month:       start.Format("2006-01"),
+		GeneratedAt: now,
+		Budget: BudgetStatus{
+			Daily:   budget.Daily,
+			Monthly: budget.Monthly,
+			PerTask: budget.PerTask,
+		},
+	}
+
+	byDay := make(map[string]*Line)
+	byProvider := make(map[string]*Line)
+	byModel := make(map[string]*Line)
+	byChange := make(map[string]*Line)
+	byRole := make(map[string]*Line)
+	byTask := make(map[string]float64)
+	lastModel := ""
+
+	for _, e := range entries {
+		ts := e.Timestamp.In(start.Location())
+		if ts.Before(start) || !ts.Before(end) {
+			continue
+		}
+
+		r.Total += e.Cost
+		r.TokensIn += e.TokensIn
+		r.TokensOut += e.TokensOut
+		if current && !ts.Before(dayStart) && ts.Before(dayStart.AddDate(0, 0, 1)) {
+			r.Budget.Today += e.Cost
+		}
+		if e.TaskID != "" {
+			byTask[e.TaskID] += e.Cost
+		}
+		if e.Model != "" {
+			lastModel = e.Model
+		}
+
+		addLine(byDay, ts.Format("2006-01-02"), e)
+		addLine(byProvider, orUnknown(e.Provider), e)
+		addLine(byModel, orUnknown(e.Model), e)
+		addLine(byChange, orUnknown(e.ChangeID), e)
+		addLine(byRole, orUnknown(e.AgentRole), e)
+	}
+
+	r.Budget.MonthToDate = r.Total
+	r.ByDay = sortedLines(byDay, true)
+	r.ByProvider = sortedLines(byProvider, false)
+	r.ByModel = sortedLines(byModel, false)
+	r.ByChange = sortedLines(byChange, false)
+	r.ByRole = sortedLines(byRole, false)
+	r.Projection = project(r.Total, start, end, now)
+
+	if current && budget.Daily > 0 {
+		r.Budget.DailyRemaining = budget.Daily - r.Budget.Today
+	}
+	if budget.Monthly > 0 {
+		r.Budget.MonthlyRemaining = budget.Monthly - r.Total
+	}
+
+	r.Warnings = warnings(r, byTask, execution.EstimateTask(lastModel), current)
+	return r
+}
+
+// ProjectReport loads the spend and budget config of the project at root and
+// reports on month (YYYY-MM), or on the month containing now when empty.
+func ProjectReport(root, month string, now time.Time) (Report, error) {
+	at := MonthStart(now)
+	if month != "" {
+		start, err := time.ParseInLocation("2006-01", month, now.Location())
+		if err != nil {
+			return Report{}, fmt.Errorf("invalid month %q (expected YYYY-MM): %w", month, err)
+		}
+		at = start
+	}
+
+	cfg, err := config.Load(root)
+	if err != nil {
+		slog.Warn("failed to load config, using default budget", "error", err)
+		cfg = config.DefaultConfig()
+	}
+
+	entries, err := Collect(root, at)
+	if err != nil {
+		return Report{}, fmt.Errorf("collect spend: %w", err)
+	}
+
+	return BuildReport(entries, cfg.Budget, at, now), nil
+}
+
+func project(total float64, start, end, now time.Time) Projection {
+	daysInMonth := int(end.Sub(start).Hours()/24 + 0.5)
+	p := Projection{DaysInMonth: daysInMonth}
+
+	if !now.Before(end) {
+		p.DaysElapsed = float64(daysInMonth)
+		p.RunRate = total / float64(daysInMonth)
+		p.MonthEnd = total
+		return p
+	}
+
+	if now.Before(start) {
+		return p
+	}
+
+	p.DaysElapsed = now.Sub(start).Hours() / 24
+	// Less than a day of data extrapolates wildly, so the rate is taken over
+	// at least one day.
+	p.RunRate = total / max(p.DaysElapsed, 1)
+	p.MonthEnd = total + p.RunRate*(end.Sub(now).Hours()/24)
+	return p
+}
+
+// warnings flags limits that are close to or past the point where
+// BudgetTracker.Check rejects the next task of the typical estimate.
+func warnings(r Report, byTask map[string]float64, next execution.CostEstimate, current bool) []string {
+	var out []string
+
+	if current && r.Budget.Daily > 0 {
+		switch {
+		case r.Budget.Today+next.Cost > r.Budget.Daily:
+			out = append(out, fmt.Sprintf(
+				"daily budget: $%.2f of $%.2f spent; the next task (estimated $%.4f) would be rejected by the budget check",
+				r.Budget.Today, r.Budget.Daily, next.Cost))
+		case r.Budget.Today >= r.Budget.Daily*WarnRatio:
+			out = append(out, fmt.Sprintf(
+				"daily budget: %.0f%% used ($%.2f of $%.2f); about %d more tasks before the budget check rejects work",
+				r.Budget.Today/r.Budget.Daily*100, r.Budget.Today, r.Budget.Daily,
+				tasksLeft(r.Budget.DailyRemaining, next.Cost)))
+		}
+	}
+
+	if r.Budget.Monthly > 0 {
+		switch {
+		case r.Total >= r.Budget.Monthly:
+			out = append(out, fmt.Sprintf("monthly budget exceeded: $%.2f of $%.2f spent", r.Total, r.Budget.Monthly))
+		case r.Total >= r.Budget.Monthly*WarnRatio:
+			out = append(out, fmt.Sprintf("monthly budget: %.0f%% used ($%.2f of $%.2f)",
+				r.Total/r.Budget.Monthly*100, r.Total, r.Budget.Monthly))
+		}
+
+		if current && r.Total < r.Budget.Monthly && r.Projection.MonthEnd > r.Budget.Monthly && r.Projection.RunRate > 0 {
+			daysLeft := (r.Budget.Monthly - r.Total) / r.Projection.RunRate
+			reachedAt := r.GeneratedAt.Add(time.Duration(daysLeft * 24 * float64(time.Hour)))
+			out = append(out, fmt.Sprintf(
+				"projected month-end spend $%.2f exceeds the monthly limit $%.2f at $%.2f/day; limit reached around %s",
+				r.Projection.MonthEnd, r.Budget.Monthly, r.Projection.RunRate, reachedAt.Format("2006-01-02")))
+		}
+	}
+
+	if r.Budget.PerTask > 0 {
+		tasks := make([]string, 0, len(byTask))
+		for id := range byTask {
+			tasks = append(tasks, id)
+		}
+		sort.Strings(tasks)
+		for _, id := range tasks {
+			if byTask[id] > r.Budget.PerTask {
+				out = append(out, fmt.Sprintf("task %s spent $%.2f, over the per-task limit $%.2f",
+					id, byTask[id], r.Budget.PerTask))
+			}
+		}
+	}
+
+	return out
+}
+
+func tasksLeft(remaining, estimate float64) int {
+	if estimate <= 0 || remaining <= 0 {
+		return 0
+	}
+	return int(remaining / estimate)
+}
+
+func addLine(lines map[string]*Line, key string, e Entry) {
+	l, ok := lines[key]
+	if !ok {
+		l = &Line{Key: key}
+		lines[key] = l
+	}
+	l.Count++
+	l.TokensIn += e.TokensIn
+	l.TokensOut += e.TokensOut
+	l.Cost += e.Cost
+}
+
+// sortedLines orders lines by key, or by cost descending then key.
+func sortedLines(lines map[string]*Line, byKey bool) []Line {
+	out := make([]Line, 0, len(lines))
+	for _, l := range lines {
+		out = append(out, *l)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !byKey && out[i].Cost != out[j].Cost {
+			return out[i].Cost > out[j].Cost
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func orUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
+
+// FormatMarkdown renders the report as markdown.
+func FormatMarkdown(r Report) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("## Cost Report: %s\n\n", r.Month))
+	sb.WriteString(fmt.Sprintf("**Total:** $%.4f (%d in / %d out tokens)\n", r.Total, r.TokensIn, r.TokensOut))
+	sb.WriteString(fmt.Sprintf("**Today:** $%.4f\n", r.Budget.Today))
+	sb.WriteString(fmt.Sprintf("**Run rate:** $%.4f/day\n", r.Projection.RunRate))
+	sb.WriteString(fmt.Sprintf("**Projected month-end:** $%.2f\n\n", r.Projection.MonthEnd))
+
+	sb.WriteString("### Budget\n\n")
+	sb.WriteString("| Limit | Configured | Spent | Remaining |\n")
+	sb.WriteString("|-------|------------|-------|-----------|\n")
+	sb.WriteString(budgetRow("Daily", r.Budget.Daily, r.Budget.Today, r.Budget.DailyRemaining))
+	sb.WriteString(budgetRow("Monthly", r.Budget.Monthly, r.Budget.MonthToDate, r.Budget.MonthlyRemaining))
+	if r.Budget.PerTask > 0 {
+		sb.WriteString(fmt.Sprintf("| Per task | $%.2f | - | - |\n", r.Budget.PerTask))
+	}
+	sb.WriteString("\n")
+
+	if len(r.Warnings) > 0 {
+		sb.WriteString("### Warnings\n\n")
+		for _, w := range r.Warnings {
+			sb.WriteString(fmt.Sprintf("- ⚠️ %s\n", w))
+		}
+		sb.WriteString("\n")
+	}
+
+	writeLines(&sb, "By Day", r.ByDay)
+	writeLines(&sb, "By Provider", r.ByProvider)
+	writeLines(&sb, "By Model", r.ByModel)
+	writeLines(&sb, "By Change", r.ByChange)
+	writeLines(&sb, "By Agent Role", r.ByRole)
+
+	return sb.String()
+}
+
+func budgetRow(name string, limit, spent, remaining float64) string {
+	if limit <= 0 {
+		return fmt.Sprintf("| %s | none | $%.4f | - |\n", name, spent)
+	}
+	return fmt.Sprintf("| %s | $%.2f | $%.4f | $%.4f |\n", name, limit, spent, remaining)
+}
+
+func writeLines(sb *strings.Builder, title string, lines []Line) {
+	if len(lines) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("### %s\n\n", title))
+	sb.WriteString("| Key | Runs | Tokens In | Tokens Out | Cost |\n")
+	sb.WriteString("|-----|------|-----------|------------|------|\n")
+	for _, l := range lines {
+		sb.WriteString(fmt.Sprintf("| %s | %d | %d | %d | $%.4f |\n", l.Key, l.Count, l.TokensIn, l.TokensOut, l.Cost))
+	}
+	sb.WriteString("\n")
+}
@@ -0,0 +1,194 @@
+package cost
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/config"
+)
+
+func TestBuildReport_Breakdowns(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 11, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{Timestamp: now.AddDate(0, 0, -1), Provider: "anthropic", Model: "opus", ChangeID: "add-auth", AgentRole: "architect", TokensIn: 100, TokensOut: 10, Cost: 2},
+		{Timestamp: now.Add(-time.Hour), Provider: "anthropic", Model: "sonnet", ChangeID: "add-auth", AgentRole: "developer", TokensIn: 50, TokensOut: 5, Cost: 1},
+		{Timestamp: now.Add(-2 * time.Hour), Provider: "openai", Model: "gpt-4o", Cost: 0.5},
+		{Timestamp: now.AddDate(0, -1, 0), Provider: "anthropic", Cost: 100},
+	}
+
+	r := BuildReport(entries, config.BudgetConfig{}, now, now)
+
+	assert.Equal(t, "2026-05", r.Month)
+	assert.InDelta(t, 3.5, r.Total, 1e-9)
+	assert.Equal(t, 150, r.TokensIn)
+	assert.Equal(t, 15, r.TokensOut)
+	assert.InDelta(t, 1.5, r.Budget.Today, 1e-9)
+
+	require.Len(t, r.ByDay, 2)
+	assert.Equal(t, "2026-05-10", r.ByDay[0].Key)
+	assert.Equal(t, "2026-05-11", r.ByDay[1].Key)
+
+	require.Len(t, r.ByProvider, 2)
+	assert.Equal(t, Line{Key: "anthropic", Count: 2, TokensIn: 150, TokensOut: 15, Cost: 3}, r.ByProvider[0])
+
+	require.Len(t, r.ByChange, 2)
+	assert.Equal(t, "add-auth", r.ByChange[0].Key)
+	assert.Equal(t, "unknown", r.ByChange[1].Key)
+
+	assert.Len(t, r.ByModel, 3)
+	assert.Len(t, r.ByRole, 3)
+	assert.Empty(t, r.Warnings)
+}
+
+func TestBuildReport_Projection(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	now := start.AddDate(0, 0, 10)
+	var entries []Entry
+	for d := 0; d < 10; d++ {
+		entries = append(entries, Entry{Timestamp: start.AddDate(0, 0, d).Add(time.Hour), Cost: 3})
+	}
+
+	r := BuildReport(entries, config.BudgetConfig{}, now, now)
+	assert.Equal(t, 30, r.Projection.DaysInMonth)
+	assert.InDelta(t, 10, r.Projection.DaysElapsed, 1e-9)
+	assert.InDelta(t, 3, r.Projection.RunRate, 1e-9)
+	assert.InDelta(t, 90, r.Projection.MonthEnd, 1e-9)
+
+	past := BuildReport(entries, config.BudgetConfig{Daily: 1, Monthly: 10}, start, start.AddDate(0, 2, 0))
+	assert.InDelta(t, 30, past.Projection.MonthEnd, 1e-9)
+	assert.Zero(t, past.Budget.Today)
+	assert.Equal(t, []string{"monthly budget exceeded: $30.00 of $10.00 spent"}, past.Warnings)
+}
+
+func TestBuildReport_Warnings(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 11, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		entries []Entry
+		budget  config.BudgetConfig
+		want    []string
+	}{
+		{
+			name:    "within budget",
+			entries: []Entry{{Timestamp: now, Model: "sonnet", Cost: 1}},
+			budget:  config.BudgetConfig{Daily: 10, Monthly: 1000},
+		},
+		{
+			name:    "daily near limit",
+			entries: []Entry{{Timestamp: now, Model: "sonnet", Cost: 8.5}},
+			budget:  config.BudgetConfig{Daily: 10},
+			want:    []string{"daily budget: 85% used"},
+		},
+		{
+			name:    "next task rejected",
+			entries: []Entry{{Timestamp: now, Model: "sonnet", Cost: 9.99}},
+			budget:  config.BudgetConfig{Daily: 10},
+			want:    []string{"would be rejected by the budget check"},
+		},
+		{
+			name:    "monthly projection over limit",
+			entries: []Entry{{Timestamp: now.AddDate(0, 0, -5), Cost: 20}},
+			budget:  config.BudgetConfig{Monthly: 50},
+			want:    []string{"projected month-end spend"},
+		},
+		{
+			name:    "monthly near limit",
+			entries: []Entry{{Timestamp: now.AddDate(0, 0, -5), Cost: 45}},
+			budget:  config.BudgetConfig{Monthly: 50},
+			want:    []string{"monthly budget: 90% used", "projected month-end spend"},
+		},
+		{
+			name:    "per task limit",
+			entries: []Entry{{Timestamp: now, TaskID: "add-auth/1.1", Cost: 3}, {Timestamp: now, TaskID: "add-auth/1.2", Cost: 1}},
+			budget:  config.BudgetConfig{PerTask: 2},
+			want:    []string{"task add-auth/1.1 spent $3.00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := BuildReport(tt.entries, tt.budget, now, now)
+			require.Len(t, r.Warnings, len(tt.want), "warnings: %v", r.Warnings)
+			for i, want := range tt.want {
+				assert.Contains(t, r.Warnings[i], want)
+			}
+		})
+	}
+}
+
+func TestFormatMarkdown(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 5, 11, 12, 0, 0, 0, time.UTC)
+	r := BuildReport([]Entry{{Timestamp: now, Provider: "anthropic", Model: "opus", Cost: 9}},
+		config.BudgetConfig{Daily: 10, Monthly: 100}, now, now)
+
+	out := FormatMarkdown(r)
+	for _, want := range []string{
+		"## Cost Report: 2026-05",
+		"| Daily | $10.00 | $9.0000 | $1.0000 |",
+		"### Warnings",
+		"### By Provider",
+		"| anthropic | 1 |",
+		"### By Agent Role",
+	} {
+		assert.Contains(t, out, want)
+	}
+	assert.False(t, strings.Contains(out, "| Per task |"))
+}
+
+func TestProjectReport(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".go-ent"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".go-ent", "config.yaml"), []byte(`version: "1.0"
+agents:
+  default: architect
+  roles:
+    architect:
+      model: opus
+runtime:
+  preferred: claude-code
+budget:
+  daily: 5
+  monthly: 100
+  tracking: true
+models:
+  opus: claude-opus-4-5-20251101
+`), 0600))
+
+	ledger, err := OpenLedger(LedgerPath(root))
+	require.NoError(t, err)
+	now := time.Date(2026, 5, 11, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, ledger.Record(Entry{Timestamp: now.Add(-time.Hour), Cost: 1}))
+	require.NoError(t, ledger.Record(Entry{Timestamp: now.AddDate(0, -1, 0), Cost: 7}))
+
+	r, err := ProjectReport(root, "", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-05", r.Month)
+	assert.InDelta(t, 1, r.Total, 1e-9)
+	assert.InDelta(t, 5, r.Budget.Daily, 1e-9)
+
+	r, err = ProjectReport(root, "2026-04", now)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-04", r.Month)
+	assert.InDelta(t, 7, r.Total, 1e-9)
+
+	_, err = ProjectReport(root, "April", now)
+	assert.Error(t, err)
+}
@@ -69,6 +69,12 @@ func NewCostEstimate(model string, tokensIn, tokensOut int) CostEstimate {
 	}
 }
 
+// EstimateTask returns the rough pre-execution estimate used for budget checks
+// when the actual token usage is not yet known.
+func EstimateTask(model string) CostEstimate {
+	return NewCostEstimate(model, 2000, 1000)
+}
+
 // SpendingWindow tracks spending over a time window.
 type SpendingWindow struct {
 	mu      sync.RWMutex
@@ -7,6 +7,7 @@ import (
 
 	"github.com/victorzhuk/go-ent/internal/agent"
 	"github.com/victorzhuk/go-ent/internal/domain"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 // Engine orchestrates task execution across runners and strategies.
@@ -77,11 +78,15 @@ func (e *Engine) RegisterStrategy(s Strategy) {
 func (e *Engine) Execute(ctx context.Context, task *Task) (*Result, error) {
 	e.logger.Info("executing task", "description", truncate(task.Description, 100))
 
+	ctx, span := tracing.Start(ctx, "engine.execute", tracing.String("goent.task.type", task.Type))
+	defer span.End()
+
 	// Select strategy
 	strategy := e.selectStrategy(task)
 
 	// Execute with strategy
-	result, err := strategy.Execute(ctx, e, task)
+	result, err := e.executeStrategy(ctx, strategy, task)
+	recordSpanResult(span, result, err)
 	if err != nil {
 		e.logger.Error("execution failed", "error", err)
 		return result, err
@@ -108,8 +113,56 @@ func (e *Engine) ExecuteWithRunner(ctx context.Context, runtime domain.Runtime,
 	task.ForceRuntime = runtime
 
 	// Use single strategy for direct runner execution
-	strategy := NewSingleStrategy()
-	return strategy.Execute(ctx, e, task)
+	return e.executeStrategy(ctx, NewSingleStrategy(), task)
+}
+
+// executeStrategy runs a strategy inside a strategy.<name> span.
+func (e *Engine) executeStrategy(ctx context.Context, strategy Strategy, task *Task) (*Result, error) {
+	ctx, span := tracing.Start(ctx, "strategy."+string(strategy.Name()))
+	defer span.End()
+
+	result, err := strategy.Execute(ctx, e, task)
+	recordSpanResult(span, result, err)
+	return result, err
+}
+
+// runRunner executes a request inside a runner.<runtime> span, so each agent
+// invocation of a strategy shows up as its own child span.
+func (e *Engine) runRunner(ctx context.Context, runner Runner, req *Request) (*Result, error) {
+	ctx, span := tracing.Start(ctx, "runner."+string(runner.Runtime()),
+		tracing.String(tracing.AttrAgentRole, string(req.Agent)),
+		tracing.String(tracing.AttrModel, req.Model),
+	)
+	defer span.End()
+
+	result, err := runner.Execute(ctx, req)
+	if err == nil && result != nil && result.Cost == 0 {
+		span.SetAttributes(tracing.Float64(tracing.AttrCost, CalculateCost(req.Model, result.TokensIn, result.TokensOut)))
+	}
+	recordSpanResult(span, result, err)
+	return result, err
+}
+
+// recordSpanResult copies token, cost and outcome data of a result onto a span.
+func recordSpanResult(span *tracing.Span, result *Result, err error) {
+	if err != nil {
+		span.RecordError(err)
+		return
+	}
+	if result == nil {
+		return
+	}
+	span.SetAttributes(
+		tracing.Bool("goent.success", result.Success),
+		tracing.Int(tracing.AttrTokensIn, result.TokensIn),
+		tracing.Int(tracing.AttrTokensOut, result.TokensOut),
+	)
+	if result.Cost > 0 {
+		span.SetAttributes(tracing.Float64(tracing.AttrCost, result.Cost))
+	}
+	if !result.Success {
+		span.SetStatus(tracing.StatusError, result.Error)
+	}
 }
 
 // GetBudgetTracker returns the budget tracker.
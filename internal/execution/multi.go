@@ -85,14 +85,14 @@ func (m *MultiStrategy) Execute(ctx context.Context, engine *Engine, task *Task)
 
 		// Check budget before execution
 		if task.Budget != nil {
-			estimate := NewCostEstimate(model, 2000, 1000)
+			estimate := EstimateTask(model)
 			if err := engine.budget.Check(ctx, estimate, task.Budget); err != nil {
 				return nil, fmt.Errorf("budget check for %s: %w", agent, err)
 			}
 		}
 
 		// Execute this agent
-		result, err := runner.Execute(ctx, req)
+		result, err := engine.runRunner(ctx, runner, req)
 		if err != nil {
 			return nil, fmt.Errorf("execution by %s: %w", agent, err)
 		}
@@ -121,14 +121,14 @@ func (p *ParallelStrategy) Execute(ctx context.Context, engine *Engine, task *Ta
 
 			// Check budget
 			if task.Budget != nil {
-				estimate := NewCostEstimate(parallelTask.Model, 2000, 1000)
+				estimate := EstimateTask(parallelTask.Model)
 				if err := engine.budget.Check(egCtx, estimate, task.Budget); err != nil {
 					return fmt.Errorf("budget check for task %s: %w", taskID, err)
 				}
 			}
 
 			// Execute
-			result, err := runner.Execute(egCtx, req)
+			result, err := engine.runRunner(egCtx, runner, req)
 			if err != nil {
 				return fmt.Errorf("execution of task %s: %w", taskID, err)
 			}
@@ -70,14 +70,14 @@ func (s *SingleStrategy) Execute(ctx context.Context, engine *Engine, task *Task
 
 	// Check budget before execution
 	if task.Budget != nil {
-		estimate := NewCostEstimate(model, 2000, 1000) // Rough estimate
+		estimate := EstimateTask(model)
 		if err := engine.budget.Check(ctx, estimate, task.Budget); err != nil {
 			return nil, fmt.Errorf("budget check: %w", err)
 		}
 	}
 
 	// Execute
-	result, err := runner.Execute(ctx, req)
+	result, err := engine.runRunner(ctx, runner, req)
 	if err != nil {
 		return nil, fmt.Errorf("execution: %w", err)
 	}
@@ -0,0 +1,682 @@
+package generation
+
+import (
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	astpkg "github.com/victorzhuk/go-ent/internal/ast"
+)
+
+// DomainOptions configures GenerateDomain.
+type DomainOptions struct {
+	ModulePath string
+	Entity     string
+}
+
+// GeneratedFile is a Go source file produced from spec requirements.
+// Path is relative to the project root.
+type GeneratedFile struct {
+	Path    string
+	Content string
+}
+
+// domainTemplates are the declaration shapes used for domain generation.
+// Identifiers that match a data key are replaced on execution.
+var domainTemplates = []*astpkg.Template{
+	{Name: "entity", Source: `type EntityName struct {
+		ID        string
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}`},
+	{Name: "contract-errors", Source: `var (
+		ErrNotFound     = errors.New("not found")
+		ErrInvalidInput = errors.New("invalid input")
+		ErrConflict     = errors.New("conflict")
+	)`},
+	{Name: "repo", Source: `type RepoName interface {
+		Save(ctx context.Context, item *entity.EntityName) error
+		FindByID(ctx context.Context, id string) (*entity.EntityName, error)
+		List(ctx context.Context) ([]*entity.EntityName, error)
+		Delete(ctx context.Context, id string) error
+	}`},
+	{Name: "usecase-errors", Source: `var ErrNotImplemented = errors.New("use case not implemented")`},
+	{Name: "usecase-req", Source: `type ReqName struct {
+		ID string
+	}`},
+	{Name: "usecase-resp", Source: `type RespName struct {
+		Item  *entity.EntityName
+		Items []*entity.EntityName
+	}`},
+	{Name: "usecase-iface", Source: `type UCName interface {
+		Execute(ctx context.Context, req ReqName) (RespName, error)
+	}`},
+	{Name: "usecase-impl", Source: `type implName struct {
+		repo contract.RepoName
+	}`},
+	{Name: "usecase-new", Source: `func NewName(repo contract.RepoName) UCName {
+		return &implName{repo: repo}
+	}`},
+	{Name: "usecase-execute", Source: `func (uc *implName) Execute(ctx context.Context, req ReqName) (RespName, error) {
+		return RespName{}, ErrNotImplemented
+	}`},
+	{Name: "usecase-test", Source: `func TestName(t *testing.T) {
+		t.Parallel()
+		tests := []struct {
+			name    string
+			when    string
+			then    string
+			wantErr bool
+		}{}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+				uc := NewName(nil)
+				_, err := uc.Execute(context.Background(), ReqName{})
+				if errors.Is(err, ErrNotImplemented) {
+					t.Skipf("not implemented: when %s, then %s", tt.when, tt.then)
+				}
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			})
+		}
+	}`},
+	{Name: "handler", Source: `type Handler struct {
+		fieldName usecase.UCName
+	}`},
+	{Name: "handler-new", Source: `func New(fieldName usecase.UCName) *Handler {
+		return &Handler{fieldName: fieldName}
+	}`},
+	{Name: "handler-register", Source: `func (h *Handler) Register(mux *stdhttp.ServeMux) {
+		mux.HandleFunc(route, h.handleName)
+	}`},
+	{Name: "handler-method", Source: `func (h *Handler) handleName(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+		var req usecase.ReqName
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(w, stdhttp.StatusBadRequest, err)
+				return
+			}
+		}
+		if id := r.PathValue("id"); id != "" {
+			req.ID = id
+		}
+		resp, err := h.fieldName.Execute(r.Context(), req)
+		if err != nil {
+			writeUsecaseError(w, err)
+			return
+		}
+		writeJSON(w, stdhttp.StatusOK, resp)
+	}`},
+	{Name: "handler-write-json", Source: `func writeJSON(w stdhttp.ResponseWriter, status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}`},
+	{Name: "handler-write-error", Source: `func writeError(w stdhttp.ResponseWriter, status int, err error) {
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+	}`},
+	{Name: "handler-usecase-error", Source: `func writeUsecaseError(w stdhttp.ResponseWriter, err error) {
+		switch {
+		case errors.Is(err, contract.ErrNotFound):
+			writeError(w, stdhttp.StatusNotFound, err)
+		case errors.Is(err, contract.ErrInvalidInput):
+			writeError(w, stdhttp.StatusBadRequest, err)
+		case errors.Is(err, contract.ErrConflict):
+			writeError(w, stdhttp.StatusConflict, err)
+		case errors.Is(err, usecase.ErrNotImplemented):
+			writeError(w, stdhttp.StatusNotImplemented, err)
+		default:
+			writeError(w, stdhttp.StatusInternalServerError, err)
+		}
+	}`},
+}
+
+// failureWords mark a THEN step that expects the use case to return an error.
+var failureWords = regexp.MustCompile(`(?i)\b(error|errors|reject|rejected|rejects|fail|fails|failed|invalid|denied|deny|denies)\b`)
+
+// GenerateDomain turns spec requirements into Clean Architecture code: an
+// entity, a repository contract, one use case stub and table test per
+// requirement, and an HTTP handler that routes to every use case.
+// Use case stubs return ErrNotImplemented, which the generated tests skip on.
+func GenerateDomain(reqs []Requirement, opts DomainOptions) ([]GeneratedFile, error) {
+	if opts.ModulePath == "" {
+		return nil, fmt.Errorf("module path is required")
+	}
+	if len(reqs) == 0 {
+		return nil, fmt.Errorf("spec has no requirements")
+	}
+
+	entity := EntityName(opts.Entity)
+	if entity == "" {
+		return nil, fmt.Errorf("entity name is required")
+	}
+
+	g, err := newDomainGen(opts.ModulePath, entity)
+	if err != nil {
+		return nil, err
+	}
+
+	ucs := g.useCases(reqs)
+	steps := []func() (GeneratedFile, error){g.entityFile, g.contractErrorsFile, g.repoFile, g.usecaseErrorsFile}
+	for _, uc := range ucs {
+		steps = append(steps, func() (GeneratedFile, error) { return g.usecaseFile(uc) })
+		steps = append(steps, func() (GeneratedFile, error) { return g.usecaseTestFile(uc) })
+	}
+	steps = append(steps, func() (GeneratedFile, error) { return g.handlerFile(ucs) })
+
+	files := make([]GeneratedFile, 0, len(steps))
+	for _, step := range steps {
+		f, err := step()
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+	return files, nil
+}
+
+// EntityFromSpecPath derives the entity name from a spec location: the
+// capability directory for spec.md files, the file name otherwise.
+func EntityFromSpecPath(specPath string) string {
+	name := strings.TrimSuffix(filepath.Base(specPath), filepath.Ext(specPath))
+	if name == "spec" {
+		name = filepath.Base(filepath.Dir(specPath))
+	}
+	return EntityName(name)
+}
+
+// EntityName normalizes a name such as "user-accounts" to an exported,
+// singular Go identifier ("UserAccount").
+func EntityName(name string) string {
+	words := splitWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+	words[len(words)-1] = singular(words[len(words)-1])
+	return exportedName(words)
+}
+
+// useCase holds the identifiers generated for one requirement.
+type useCase struct {
+	req   Requirement
+	name  string
+	file  string
+	field string
+	route string
+}
+
+type domainGen struct {
+	module  string
+	entity  string
+	plural  string
+	fset    *token.FileSet
+	engine  *astpkg.Engine
+	printer *astpkg.Printer
+}
+
+func newDomainGen(module, entity string) (*domainGen, error) {
+	fset := token.NewFileSet()
+	engine := astpkg.NewEngine(fset)
+	for _, tmpl := range domainTemplates {
+		t := *tmpl
+		if err := engine.Register(&t); err != nil {
+			return nil, fmt.Errorf("register template %s: %w", tmpl.Name, err)
+		}
+	}
+
+	return &domainGen{
+		module:  strings.TrimSuffix(module, "/"),
+		entity:  entity,
+		plural:  plural(strings.Join(splitWords(entity), "-")),
+		fset:    fset,
+		engine:  engine,
+		printer: astpkg.NewPrinter(fset),
+	}, nil
+}
+
+func (g *domainGen) useCases(reqs []Requirement) []useCase {
+	seenNames := make(map[string]int)
+	seenRoutes := make(map[string]bool)
+
+	ucs := make([]useCase, 0, len(reqs))
+	for _, req := range reqs {
+		words := splitWords(req.Name)
+		if len(words) == 0 {
+			words = []string{"requirement"}
+		}
+		name := exportedName(words)
+		if !unicode.IsLetter(rune(name[0])) {
+			name = "Req" + name
+		}
+		if n := seenNames[name]; n > 0 {
+			name += strconv.Itoa(n + 1)
+		}
+		seenNames[name]++
+
+		uc := useCase{
+			req:   req,
+			name:  name,
+			file:  snakeCase(name),
+			field: lowerFirst(name) + "UC",
+		}
+		uc.route = g.route(words)
+		if seenRoutes[uc.route] {
+			uc.route = "POST /" + g.plural + "/" + kebabCase(name)
+		}
+		seenRoutes[uc.route] = true
+
+		ucs = append(ucs, uc)
+	}
+	return ucs
+}
+
+// route maps CRUD verbs to REST routes and everything else to an action path.
+func (g *domainGen) route(words []string) string {
+	base := "/" + g.plural
+	switch strings.ToLower(words[0]) {
+	case "create", "add", "register":
+		return "POST " + base
+	case "list", "search":
+		return "GET " + base
+	case "get", "read", "retrieve", "view":
+		return "GET " + base + "/{id}"
+	case "update", "edit":
+		return "PUT " + base + "/{id}"
+	case "delete", "remove":
+		return "DELETE " + base + "/{id}"
+	}
+	return "POST " + base + "/" + kebabCase(exportedName(words))
+}
+
+func (g *domainGen) entityFile() (GeneratedFile, error) {
+	f := newGoFile("entity", "time")
+	f.add(fmt.Sprintf("// %s is the aggregate described by the spec.", g.entity), g.render("entity", g.data()))
+	return f.build(filepath.Join("internal", "domain", "entity", snakeCase(g.entity)+".go"))
+}
+
+func (g *domainGen) contractErrorsFile() (GeneratedFile, error) {
+	f := newGoFile("contract", "errors")
+	f.add("// Errors returned by repositories and mapped to transport status codes.", g.render("contract-errors", nil))
+	return f.build(filepath.Join("internal", "domain", "contract", "errors.go"))
+}
+
+func (g *domainGen) repoFile() (GeneratedFile, error) {
+	f := newGoFile("contract", "context", g.module+"/internal/domain/entity")
+	f.add(fmt.Sprintf("// %sRepo persists %s entities.", g.entity, g.entity), g.render("repo", g.data()))
+	return f.build(filepath.Join("internal", "domain", "contract", snakeCase(g.entity)+"_repo.go"))
+}
+
+func (g *domainGen) usecaseErrorsFile() (GeneratedFile, error) {
+	f := newGoFile("usecase", "errors")
+	f.add("// ErrNotImplemented is returned by generated use case stubs.", g.render("usecase-errors", nil))
+	return f.build(filepath.Join("internal", "usecase", "usecase.go"))
+}
+
+func (g *domainGen) usecaseFile(uc useCase) (GeneratedFile, error) {
+	data := g.ucData(uc)
+	f := newGoFile("usecase", "context", g.module+"/internal/domain/contract", g.module+"/internal/domain/entity")
+
+	f.add(fmt.Sprintf("// %sReq is the input of the %s use case.", uc.name, uc.name), g.render("usecase-req", data))
+	f.add(fmt.Sprintf("// %sResp is the output of the %s use case.", uc.name, uc.name), g.render("usecase-resp", data))
+
+	doc := fmt.Sprintf("// %sUC implements the %q requirement.", uc.name, uc.req.Name)
+	if uc.req.Description != "" {
+		doc += "\n// " + uc.req.Description
+	}
+	f.add(doc, g.render("usecase-iface", data))
+	f.add("", g.render("usecase-impl", data))
+	f.add(fmt.Sprintf("// New%sUC creates the %s use case.", uc.name, uc.name), g.render("usecase-new", data))
+	f.add("", g.render("usecase-execute", data))
+
+	return f.build(filepath.Join("internal", "usecase", uc.file+".go"))
+}
+
+func (g *domainGen) usecaseTestFile(uc useCase) (GeneratedFile, error) {
+	f := newGoFile("usecase", "context", "errors", "testing")
+
+	node, err := g.engine.Execute("usecase-test", g.ucData(uc))
+	if err != nil {
+		f.err = fmt.Errorf("execute usecase-test: %w", err)
+		return f.build("")
+	}
+	if lit := firstCompositeLit(node); lit != nil {
+		g.fillTable(lit, scenarioRows(uc.req))
+	}
+	f.add("", g.print(node))
+
+	return f.build(filepath.Join("internal", "usecase", uc.file+"_test.go"))
+}
+
+func (g *domainGen) handlerFile(ucs []useCase) (GeneratedFile, error) {
+	f := newGoFile("http",
+		"encoding/json",
+		"errors",
+		`stdhttp "net/http"`,
+		g.module+"/internal/domain/contract",
+		g.module+"/internal/usecase",
+	)
+
+	handler := g.execute(f, "handler", g.ucData(ucs[0]))
+	ctor := g.execute(f, "handler-new", g.ucData(ucs[0]))
+	register := g.execute(f, "handler-register", g.ucData(ucs[0]))
+	if f.err != nil {
+		return f.build("")
+	}
+
+	fields := handler.(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields
+	ctorFn := ctor.(*ast.FuncDecl)
+	params := ctorFn.Type.Params
+	elts := &ctorFn.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.UnaryExpr).X.(*ast.CompositeLit).Elts
+	stmts := &register.(*ast.FuncDecl).Body.List
+	routeArg(*stmts, 0, ucs[0].route)
+
+	for i, uc := range ucs[1:] {
+		data := g.ucData(uc)
+		h := g.execute(f, "handler", data)
+		c := g.execute(f, "handler-new", data)
+		r := g.execute(f, "handler-register", data)
+		if f.err != nil {
+			return f.build("")
+		}
+
+		fields.List = append(fields.List, h.(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List...)
+		cfn := c.(*ast.FuncDecl)
+		params.List = append(params.List, cfn.Type.Params.List...)
+		*elts = append(*elts, cfn.Body.List[0].(*ast.ReturnStmt).Results[0].(*ast.UnaryExpr).X.(*ast.CompositeLit).Elts...)
+		*stmts = append(*stmts, r.(*ast.FuncDecl).Body.List...)
+		routeArg(*stmts, i+1, uc.route)
+	}
+
+	f.add(fmt.Sprintf("// Handler serves the %s HTTP API.", g.plural), g.print(handler))
+	f.add("// New creates a Handler backed by the given use cases.", g.print(ctor))
+	f.add(fmt.Sprintf("// Register mounts the %s routes on mux.", g.plural), g.print(register))
+	for _, uc := range ucs {
+		f.add(fmt.Sprintf("// handle%s serves %s.", uc.name, uc.route), g.render("handler-method", g.ucData(uc)))
+	}
+	f.add("", g.render("handler-write-json", nil))
+	f.add("", g.render("handler-write-error", nil))
+	f.add("", g.render("handler-usecase-error", nil))
+
+	return f.build(filepath.Join("internal", "transport", "http", snakeCase(g.entity)+"_handler.go"))
+}
+
+func (g *domainGen) data() map[string]string {
+	return map[string]string{
+		"EntityName": g.entity,
+		"RepoName":   g.entity + "Repo",
+	}
+}
+
+func (g *domainGen) ucData(uc useCase) map[string]string {
+	data := g.data()
+	data["ReqName"] = uc.name + "Req"
+	data["RespName"] = uc.name + "Resp"
+	data["UCName"] = uc.name + "UC"
+	data["implName"] = lowerFirst(uc.name) + "UC"
+	data["NewName"] = "New" + uc.name + "UC"
+	data["TestName"] = "Test" + uc.name + "UC_Execute"
+	data["fieldName"] = uc.field
+	data["handleName"] = "handle" + uc.name
+	return data
+}
+
+// rendered is printed declaration source or the error that prevented it.
+type rendered struct {
+	src string
+	err error
+}
+
+func (g *domainGen) render(name string, data map[string]string) rendered {
+	node, err := g.engine.Execute(name, data)
+	if err != nil {
+		return rendered{err: fmt.Errorf("execute %s: %w", name, err)}
+	}
+	return g.print(node)
+}
+
+func (g *domainGen) execute(f *goFile, name string, data map[string]string) ast.Node {
+	node, err := g.engine.Execute(name, data)
+	if err != nil && f.err == nil {
+		f.err = fmt.Errorf("execute %s: %w", name, err)
+	}
+	return node
+}
+
+func (g *domainGen) print(node ast.Node) rendered {
+	src, err := g.printer.PrintNode(node)
+	if err != nil {
+		return rendered{err: err}
+	}
+	return rendered{src: src}
+}
+
+// fillTable sets the rows of a table test literal. Rows get synthetic
+// positions on consecutive lines so the printer puts each on its own line.
+func (g *domainGen) fillTable(lit *ast.CompositeLit, rows [][]ast.Expr) {
+	lines := len(rows) + 2
+	file := g.fset.AddFile("", -1, lines)
+	offsets := make([]int, lines)
+	for i := range offsets {
+		offsets[i] = i
+	}
+	file.SetLines(offsets)
+
+	lit.Lbrace = file.Pos(0)
+	lit.Elts = make([]ast.Expr, len(rows))
+	for i, row := range rows {
+		pos := file.Pos(i + 1)
+		lit.Elts[i] = &ast.CompositeLit{Lbrace: pos, Elts: row, Rbrace: pos}
+	}
+	lit.Rbrace = file.Pos(lines - 1)
+}
+
+// scenarioRows builds one table row per scenario, or a single row named
+// after the requirement when it has none.
+func scenarioRows(req Requirement) [][]ast.Expr {
+	scenarios := req.Scenarios
+	if len(scenarios) == 0 {
+		scenarios = []Scenario{{Name: req.Name}}
+	}
+
+	rows := make([][]ast.Expr, 0, len(scenarios))
+	for _, sc := range scenarios {
+		then := strings.Join(sc.Then, "; ")
+		wantErr := "false"
+		if failureWords.MatchString(then) {
+			wantErr = "true"
+		}
+		rows = append(rows, []ast.Expr{
+			keyValue("name", stringLit(sc.Name)),
+			keyValue("when", stringLit(strings.Join(append(slicesClone(sc.Given), sc.When...), "; "))),
+			keyValue("then", stringLit(then)),
+			keyValue("wantErr", ast.NewIdent(wantErr)),
+		})
+	}
+	return rows
+}
+
+func keyValue(key string, value ast.Expr) ast.Expr {
+	return &ast.KeyValueExpr{Key: ast.NewIdent(key), Value: value}
+}
+
+func stringLit(s string) ast.Expr {
+	return &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(s)}
+}
+
+func slicesClone(s []string) []string {
+	return append([]string(nil), s...)
+}
+
+func firstCompositeLit(node ast.Node) *ast.CompositeLit {
+	var lit *ast.CompositeLit
+	ast.Inspect(node, func(n ast.Node) bool {
+		if c, ok := n.(*ast.CompositeLit); ok && lit == nil {
+			lit = c
+		}
+		return lit == nil
+	})
+	return lit
+}
+
+// routeArg replaces the route placeholder of the i-th HandleFunc call.
+func routeArg(stmts []ast.Stmt, i int, route string) {
+	call := stmts[i].(*ast.ExprStmt).X.(*ast.CallExpr)
+	call.Args[0] = stringLit(route)
+}
+
+// goFile assembles printed declarations into a formatted source file.
+type goFile struct {
+	pkg     string
+	imports []string
+	decls   []string
+	err     error
+}
+
+func newGoFile(pkg string, imports ...string) *goFile {
+	return &goFile{pkg: pkg, imports: imports}
+}
+
+func (f *goFile) add(doc string, r rendered) {
+	if f.err != nil {
+		return
+	}
+	if r.err != nil {
+		f.err = r.err
+		return
+	}
+	src := strings.TrimPrefix(strings.TrimSpace(r.src), "package template")
+	if doc != "" {
+		src = doc + "\n" + strings.TrimSpace(src)
+	}
+	f.decls = append(f.decls, strings.TrimSpace(src))
+}
+
+func (f *goFile) build(path string) (GeneratedFile, error) {
+	if f.err != nil {
+		return GeneratedFile{}, f.err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "package %s\n\n", f.pkg)
+	if len(f.imports) > 0 {
+		// Standard library first, then module imports, as goimports groups them.
+		var std, local []string
+		for _, imp := range f.imports {
+			if !strings.Contains(imp, `"`) {
+				imp = strconv.Quote(imp)
+			}
+			path := imp[strings.Index(imp, `"`)+1:]
+			if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+				local = append(local, imp)
+			} else {
+				std = append(std, imp)
+			}
+		}
+		sb.WriteString("import (\n")
+		for _, imp := range std {
+			sb.WriteString("\t" + imp + "\n")
+		}
+		if len(std) > 0 && len(local) > 0 {
+			sb.WriteString("\n")
+		}
+		for _, imp := range local {
+			sb.WriteString("\t" + imp + "\n")
+		}
+		sb.WriteString(")\n\n")
+	}
+	sb.WriteString(strings.Join(f.decls, "\n\n"))
+	sb.WriteString("\n")
+
+	src, err := format.Source([]byte(sb.String()))
+	if err != nil {
+		return GeneratedFile{}, fmt.Errorf("format %s: %w", path, err)
+	}
+	return GeneratedFile{Path: filepath.ToSlash(path), Content: string(src)}, nil
+}
+
+// splitWords splits a name into lower-case words on punctuation, spaces
+// and camel-case boundaries.
+func splitWords(s string) []string {
+	return strings.FieldsFunc(kebabCase(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// initialisms are written in upper case inside identifiers.
+var initialisms = map[string]bool{"api": true, "http": true, "id": true, "json": true, "url": true, "uuid": true}
+
+func exportedName(words []string) string {
+	var sb strings.Builder
+	for _, w := range words {
+		if initialisms[w] {
+			sb.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		sb.WriteString(string(r))
+	}
+	return sb.String()
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) && (i == 0 || i+1 >= len(r) || unicode.IsUpper(r[i+1])) {
+		r[i] = unicode.ToLower(r[i])
+		i++
+	}
+	return string(r)
+}
+
+// snakeCase converts an exported identifier to snake_case.
+func snakeCase(name string) string {
+	return strings.ReplaceAll(kebabCase(name), "-", "_")
+}
+
+// kebabCase converts an exported identifier to kebab-case.
+func kebabCase(name string) string {
+	r := []rune(name)
+	var sb strings.Builder
+	for i, c := range r {
+		if unicode.IsUpper(c) && i > 0 && (unicode.IsLower(r[i-1]) || i+1 < len(r) && unicode.IsLower(r[i+1])) {
+			sb.WriteByte('-')
+		}
+		sb.WriteRune(unicode.ToLower(c))
+	}
+	return sb.String()
+}
+
+func singular(w string) string {
+	lower := strings.ToLower(w)
+	switch {
+	case strings.HasSuffix(lower, "ies") && len(w) > 3:
+		return w[:len(w)-3] + "y"
+	case strings.HasSuffix(lower, "sses"), strings.HasSuffix(lower, "xes"),
+		strings.HasSuffix(lower, "ches"), strings.HasSuffix(lower, "shes"):
+		return w[:len(w)-2]
+	case strings.HasSuffix(lower, "s") && !strings.HasSuffix(lower, "ss") && len(w) > 1:
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func plural(w string) string {
+	w = strings.ToLower(w)
+	switch {
+	case strings.HasSuffix(w, "y") && len(w) > 1 && !strings.ContainsRune("aeiou", rune(w[len(w)-2])):
+		return w[:len(w)-1] + "ies"
+	case strings.HasSuffix(w, "s"), strings.HasSuffix(w, "x"),
+		strings.HasSuffix(w, "ch"), strings.HasSuffix(w, "sh"):
+		return w + "es"
+	}
+	return w + "s"
+}
@@ -0,0 +1,144 @@
+package generation
+
+import (
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDomain(t *testing.T) {
+	t.Parallel()
+
+	reqs := []Requirement{
+		{
+			Name:        "Create Order",
+			Description: "The system SHALL create orders.",
+			Scenarios: []Scenario{
+				{Name: "Valid order", When: []string{"the cart has items"}, Then: []string{"the order is stored"}},
+				{Name: "Empty cart", When: []string{"the cart is empty"}, Then: []string{"the order is rejected"}},
+			},
+		},
+		{Name: "Cancel Order"},
+	}
+
+	files, err := GenerateDomain(reqs, DomainOptions{ModulePath: "example.com/shop", Entity: "orders"})
+	if err != nil {
+		t.Fatalf("GenerateDomain() error = %v", err)
+	}
+
+	byPath := make(map[string]string)
+	for _, f := range files {
+		byPath[f.Path] = f.Content
+
+		if _, err := parser.ParseFile(token.NewFileSet(), f.Path, f.Content, parser.AllErrors); err != nil {
+			t.Errorf("%s does not parse: %v", f.Path, err)
+		}
+		formatted, err := format.Source([]byte(f.Content))
+		if err != nil || string(formatted) != f.Content {
+			t.Errorf("%s is not gofmt-clean", f.Path)
+		}
+	}
+
+	wantPaths := []string{
+		"internal/domain/entity/order.go",
+		"internal/domain/contract/errors.go",
+		"internal/domain/contract/order_repo.go",
+		"internal/usecase/usecase.go",
+		"internal/usecase/create_order.go",
+		"internal/usecase/create_order_test.go",
+		"internal/usecase/cancel_order.go",
+		"internal/usecase/cancel_order_test.go",
+		"internal/transport/http/order_handler.go",
+	}
+	if len(files) != len(wantPaths) {
+		t.Errorf("generated %d files, want %d", len(files), len(wantPaths))
+	}
+
+	contains := map[string][]string{
+		"internal/domain/entity/order.go": {"type Order struct", "CreatedAt time.Time"},
+		"internal/domain/contract/order_repo.go": {
+			"type OrderRepo interface",
+			"FindByID(ctx context.Context, id string) (*entity.Order, error)",
+			`"example.com/shop/internal/domain/entity"`,
+		},
+		"internal/usecase/create_order.go": {
+			`// CreateOrderUC implements the "Create Order" requirement.`,
+			"// The system SHALL create orders.",
+			"func NewCreateOrderUC(repo contract.OrderRepo) CreateOrderUC {",
+			"return CreateOrderResp{}, ErrNotImplemented",
+		},
+		"internal/usecase/create_order_test.go": {
+			"func TestCreateOrderUC_Execute(t *testing.T) {",
+			"\t\t{name: \"Valid order\", when: \"the cart has items\", then: \"the order is stored\", wantErr: false},\n",
+			"\t\t{name: \"Empty cart\", when: \"the cart is empty\", then: \"the order is rejected\", wantErr: true},\n",
+		},
+		"internal/usecase/cancel_order_test.go": {
+			`{name: "Cancel Order", when: "", then: "", wantErr: false}`,
+		},
+		"internal/transport/http/order_handler.go": {
+			`stdhttp "net/http"`,
+			`mux.HandleFunc("POST /orders", h.handleCreateOrder)`,
+			`mux.HandleFunc("POST /orders/cancel-order", h.handleCancelOrder)`,
+			"func New(createOrderUC usecase.CreateOrderUC, cancelOrderUC usecase.CancelOrderUC) *Handler {",
+			"case errors.Is(err, usecase.ErrNotImplemented):",
+		},
+	}
+	for _, path := range wantPaths {
+		content, ok := byPath[path]
+		if !ok {
+			t.Errorf("missing %s", path)
+			continue
+		}
+		for _, s := range contains[path] {
+			if !strings.Contains(content, s) {
+				t.Errorf("%s does not contain %q:\n%s", path, s, content)
+			}
+		}
+	}
+}
+
+func TestGenerateDomain_Errors(t *testing.T) {
+	t.Parallel()
+
+	reqs := []Requirement{{Name: "Create Order"}}
+	tests := []struct {
+		name string
+		reqs []Requirement
+		opts DomainOptions
+	}{
+		{name: "no module", reqs: reqs, opts: DomainOptions{Entity: "order"}},
+		{name: "no requirements", opts: DomainOptions{ModulePath: "example.com/shop", Entity: "order"}},
+		{name: "no entity", reqs: reqs, opts: DomainOptions{ModulePath: "example.com/shop", Entity: "--"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := GenerateDomain(tt.reqs, tt.opts); err == nil {
+				t.Error("GenerateDomain() expected error")
+			}
+		})
+	}
+}
+
+func TestEntityFromSpecPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "openspec/specs/user-accounts/spec.md", want: "UserAccount"},
+		{path: "specs/categories.md", want: "Category"},
+		{path: "specs/api-keys/spec.md", want: "APIKey"},
+		{path: "specs/addresses/spec.md", want: "Address"},
+	}
+
+	for _, tt := range tests {
+		if got := EntityFromSpecPath(tt.path); got != tt.want {
+			t.Errorf("EntityFromSpecPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
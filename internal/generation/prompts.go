@@ -22,6 +22,7 @@ type PromptContext struct {
 type Requirement struct {
 	Name        string
 	Description string
+	Scenarios   []Scenario
 }
 
 // PromptTemplate represents a loaded prompt template.
@@ -0,0 +1,92 @@
+package generation
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Scenario is a single `#### Scenario:` block of a requirement.
+type Scenario struct {
+	Name  string
+	Given []string
+	When  []string
+	Then  []string
+}
+
+var (
+	requirementHeader = regexp.MustCompile(`^###\s+Requirement:\s*(.+?)\s*$`)
+	scenarioHeader    = regexp.MustCompile(`^####\s+Scenario:\s*(.+?)\s*$`)
+	scenarioStep      = regexp.MustCompile(`^[-*]\s+\*\*(GIVEN|WHEN|THEN|AND)\*\*:?\s*(.+?)\s*$`)
+)
+
+// ParseRequirements extracts requirements and their scenarios from spec markdown.
+// AND steps extend whichever of GIVEN, WHEN or THEN came before them.
+func ParseRequirements(content string) []Requirement {
+	var (
+		reqs     []Requirement
+		req      *Requirement
+		scenario *Scenario
+		last     *[]string
+		desc     []string
+	)
+
+	flush := func() {
+		if req == nil {
+			return
+		}
+		req.Description = strings.Join(desc, " ")
+		reqs = append(reqs, *req)
+		req, scenario, last, desc = nil, nil, nil, nil
+	}
+
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+
+		if m := requirementHeader.FindStringSubmatch(line); m != nil {
+			flush()
+			req = &Requirement{Name: m[1]}
+			continue
+		}
+		if req == nil {
+			continue
+		}
+		if strings.HasPrefix(line, "#") && !strings.HasPrefix(line, "####") {
+			// Any other heading of level three or above ends the requirement.
+			flush()
+			continue
+		}
+
+		if m := scenarioHeader.FindStringSubmatch(line); m != nil {
+			req.Scenarios = append(req.Scenarios, Scenario{Name: m[1]})
+			scenario = &req.Scenarios[len(req.Scenarios)-1]
+			last = nil
+			continue
+		}
+
+		if scenario == nil {
+			if line != "" {
+				desc = append(desc, line)
+			}
+			continue
+		}
+
+		m := scenarioStep.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch m[1] {
+		case "GIVEN":
+			last = &scenario.Given
+		case "WHEN":
+			last = &scenario.When
+		case "THEN":
+			last = &scenario.Then
+		}
+		if last != nil {
+			*last = append(*last, m[2])
+		}
+	}
+	flush()
+
+	return reqs
+}
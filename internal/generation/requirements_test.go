@@ -0,0 +1,76 @@
+package generation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRequirements(t *testing.T) {
+	t.Parallel()
+
+	content := `# Orders Specification
+
+## Requirements
+
+### Requirement: Create Order
+
+The system SHALL create orders
+for registered customers.
+
+#### Scenario: Valid order
+- **GIVEN** a registered customer
+- **WHEN** the customer submits an order
+- **AND** the cart is not empty
+- **THEN** the order is stored
+- **AND** an ID is returned
+
+#### Scenario: Empty cart
+- **WHEN** the cart is empty
+- **THEN** the order is rejected with an error
+
+### Requirement: List Orders
+
+The system SHALL list orders.
+
+## Notes
+
+- **WHEN** this is ignored
+`
+
+	got := ParseRequirements(content)
+	want := []Requirement{
+		{
+			Name:        "Create Order",
+			Description: "The system SHALL create orders for registered customers.",
+			Scenarios: []Scenario{
+				{
+					Name:  "Valid order",
+					Given: []string{"a registered customer"},
+					When:  []string{"the customer submits an order", "the cart is not empty"},
+					Then:  []string{"the order is stored", "an ID is returned"},
+				},
+				{
+					Name: "Empty cart",
+					When: []string{"the cart is empty"},
+					Then: []string{"the order is rejected with an error"},
+				},
+			},
+		},
+		{
+			Name:        "List Orders",
+			Description: "The system SHALL list orders.",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRequirements() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRequirements_None(t *testing.T) {
+	t.Parallel()
+
+	if got := ParseRequirements("# Title\n\nNo requirements here.\n"); got != nil {
+		t.Errorf("ParseRequirements() = %+v, want nil", got)
+	}
+}
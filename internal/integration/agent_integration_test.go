@@ -147,12 +147,16 @@ func TestWorkflowState_AgentTracking(t *testing.T) {
 	state := spec.NewWorkflowState("test-change", "execution")
 	state.SetAgent(domain.AgentRoleArchitect)
 
+	wfStore, err := spec.NewWorkflowStore(store)
+	require.NoError(t, err)
+	defer func() { _ = wfStore.Close() }()
+
 	// Save workflow
-	err = store.SaveWorkflow(state)
+	err = wfStore.Save(state)
 	require.NoError(t, err)
 
 	// Load workflow
-	loaded, err := store.LoadWorkflow()
+	loaded, err := wfStore.Get(state.ID)
 	require.NoError(t, err)
 
 	assert.Equal(t, domain.AgentRoleArchitect, loaded.AgentRole)
@@ -161,11 +165,11 @@ func TestWorkflowState_AgentTracking(t *testing.T) {
 
 	// Update agent
 	loaded.SetAgent(domain.AgentRoleDeveloper)
-	err = store.SaveWorkflow(loaded)
+	err = wfStore.Save(loaded)
 	require.NoError(t, err)
 
 	// Reload and verify
-	reloaded, err := store.LoadWorkflow()
+	reloaded, err := wfStore.Get(state.ID)
 	require.NoError(t, err)
 	assert.Equal(t, domain.AgentRoleDeveloper, reloaded.AgentRole)
 }
@@ -346,11 +350,14 @@ description: "Go implementation. Auto-activates for: implement, code."
 	workflow.SetAgent(result.Role)
 
 	// Save workflow
-	err = store.SaveWorkflow(workflow)
+	wfStore, err := spec.NewWorkflowStore(store)
+	require.NoError(t, err)
+	defer func() { _ = wfStore.Close() }()
+	err = wfStore.Save(workflow)
 	require.NoError(t, err)
 
 	// Load and verify complete state
-	loadedWorkflow, err := store.LoadWorkflow()
+	loadedWorkflow, err := wfStore.Get(workflow.ID)
 	require.NoError(t, err)
 	assert.Equal(t, domain.AgentRoleDeveloper, loadedWorkflow.AgentRole)
 	assert.Equal(t, spec.WorkflowStatusActive, loadedWorkflow.Status)
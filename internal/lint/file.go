@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"fmt"
+	goast "go/ast"
+	"strings"
+	"sync"
+
+	"github.com/victorzhuk/go-ent/internal/ast"
+)
+
+// File is the input to a rule. Go facts are parsed once, on first use.
+type File struct {
+	Path    string
+	Target  Target
+	Content string
+
+	linesOnce sync.Once
+	lines     []string
+
+	goOnce sync.Once
+	facts  *GoFacts
+	goErr  error
+}
+
+// NewFile wraps content for checking.
+func NewFile(target Target, path, content string) *File {
+	return &File{Path: path, Target: target, Content: content}
+}
+
+// Lines returns the content split into lines.
+func (f *File) Lines() []string {
+	f.linesOnce.Do(func() {
+		f.lines = strings.Split(f.Content, "\n")
+	})
+	return f.lines
+}
+
+// GoFacts is what rules can see of a Go file.
+type GoFacts struct {
+	Package string       `json:"package"`
+	Imports []GoImport   `json:"imports"`
+	Calls   []GoCall     `json:"calls"`
+	Funcs   []GoFunction `json:"funcs"`
+}
+
+// GoImport is an import path and its line.
+type GoImport struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+// GoCall is a call expression rendered as "pkg.Func", "recv.Method" or
+// "Func".
+type GoCall struct {
+	Name string `json:"name"`
+	Line int    `json:"line"`
+}
+
+// GoFunction is a function or method declaration.
+type GoFunction struct {
+	Name    string `json:"name"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line"`
+}
+
+// Go parses the file as Go source.
+func (f *File) Go() (*GoFacts, error) {
+	f.goOnce.Do(func() {
+		f.facts, f.goErr = parseGo(f.Content)
+		if f.goErr != nil {
+			f.goErr = fmt.Errorf("%s: %w", f.Path, f.goErr)
+		}
+	})
+	return f.facts, f.goErr
+}
+
+func parseGo(src string) (*GoFacts, error) {
+	p := ast.NewParser()
+	file, err := p.ParseString(src)
+	if err != nil {
+		return nil, err
+	}
+	fset := p.FileSet()
+
+	facts := &GoFacts{Package: file.Name.Name}
+	for _, imp := range file.Imports {
+		facts.Imports = append(facts.Imports, GoImport{
+			Path: strings.Trim(imp.Path.Value, "\""),
+			Line: fset.Position(imp.Pos()).Line,
+		})
+	}
+
+	goast.Inspect(file, func(n goast.Node) bool {
+		switch node := n.(type) {
+		case *goast.FuncDecl:
+			if node.Body != nil {
+				facts.Funcs = append(facts.Funcs, GoFunction{
+					Name:    node.Name.Name,
+					Line:    fset.Position(node.Pos()).Line,
+					EndLine: fset.Position(node.End()).Line,
+				})
+			}
+		case *goast.CallExpr:
+			if name := callName(node.Fun); name != "" {
+				facts.Calls = append(facts.Calls, GoCall{Name: name, Line: fset.Position(node.Pos()).Line})
+			}
+		}
+		return true
+	})
+
+	return facts, nil
+}
+
+func callName(expr goast.Expr) string {
+	switch e := expr.(type) {
+	case *goast.Ident:
+		return e.Name
+	case *goast.SelectorExpr:
+		if x := callName(e.X); x != "" {
+			return x + "." + e.Sel.Name
+		}
+		return e.Sel.Name
+	}
+	return ""
+}
@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Registry holds the rules of enabled plugins.
+type Registry struct {
+	mu    sync.RWMutex
+	rules map[string][]*Rule
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{rules: make(map[string][]*Rule)}
+}
+
+var (
+	defaultMu       sync.RWMutex
+	defaultRegistry = NewRegistry()
+)
+
+// Default returns the process-wide registry consulted by the skill and spec
+// validators and the Go lint tool.
+func Default() *Registry {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultRegistry
+}
+
+// SetDefault replaces the process-wide registry.
+func SetDefault(r *Registry) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultRegistry = r
+}
+
+// Add registers rule for plugin, replacing a rule of the same name.
+func (r *Registry) Add(plugin string, rule *Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := slices.DeleteFunc(r.rules[plugin], func(cur *Rule) bool { return cur.Name == rule.Name })
+	r.rules[plugin] = append(rules, rule)
+}
+
+// SetPlugin replaces every rule of plugin with rules in one step.
+func (r *Registry) SetPlugin(plugin string, rules []*Rule) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(rules) == 0 {
+		delete(r.rules, plugin)
+		return
+	}
+	r.rules[plugin] = slices.Clone(rules)
+}
+
+// RemovePlugin drops every rule of plugin.
+func (r *Registry) RemovePlugin(plugin string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.rules, plugin)
+}
+
+// Count returns the number of registered rules for target; an empty target
+// counts all rules.
+func (r *Registry) Count(target Target) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	n := 0
+	for _, rules := range r.rules {
+		for _, rule := range rules {
+			if target == "" || rule.Target == target {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// Check runs every rule that applies to f, sorted by plugin and rule name.
+// A rule that fails to run is reported as a warning finding instead of
+// failing the whole check.
+func (r *Registry) Check(ctx context.Context, f *File) []Finding {
+	type entry struct {
+		plugin string
+		rule   *Rule
+	}
+
+	r.mu.RLock()
+	var active []entry
+	for plugin, rules := range r.rules {
+		for _, rule := range rules {
+			if rule.Applies(f.Target, f.Path) {
+				active = append(active, entry{plugin, rule})
+			}
+		}
+	}
+	r.mu.RUnlock()
+
+	slices.SortFunc(active, func(a, b entry) int {
+		if c := strings.Compare(a.plugin, b.plugin); c != 0 {
+			return c
+		}
+		return strings.Compare(a.rule.Name, b.rule.Name)
+	})
+
+	var findings []Finding
+	for _, e := range active {
+		found, err := e.rule.Check(ctx, f)
+		if err != nil {
+			slog.Warn("plugin rule failed", "plugin", e.plugin, "rule", e.rule.Name, "file", f.Path, "error", err)
+			found = []Finding{{
+				Rule:     e.rule.Name,
+				Severity: SeverityWarning,
+				File:     f.Path,
+				Message:  fmt.Sprintf("rule failed: %v", err),
+			}}
+		}
+		for _, finding := range found {
+			finding.Plugin = e.plugin
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,54 @@
+package lint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	t.Parallel()
+
+	todo := &Rule{
+		Name:       "no-todo",
+		Target:     TargetSkill,
+		Conditions: []Condition{{Type: "content_matches", Pattern: "TODO"}},
+		Actions:    []Action{{Type: "warn", Message: "resolve TODOs"}},
+	}
+	require.NoError(t, todo.compile())
+
+	broken := &Rule{Name: "broken", Target: TargetSkill, Script: "x.js", source: "function check() { return 1; }"}
+	require.NoError(t, broken.compile())
+
+	goOnly := &Rule{
+		Name:       "go-only",
+		Target:     TargetGo,
+		Conditions: []Condition{{Type: "content_matches", Pattern: "."}},
+		Actions:    []Action{{Type: "reject"}},
+	}
+	require.NoError(t, goOnly.compile())
+
+	r := NewRegistry()
+	r.Add("writing", todo)
+	r.Add("writing", todo)
+	r.Add("alpha", broken)
+	r.Add("go-kit", goOnly)
+	assert.Equal(t, 3, r.Count(""))
+	assert.Equal(t, 2, r.Count(TargetSkill))
+
+	findings := r.Check(context.Background(), NewFile(TargetSkill, "SKILL.md", "# Skill\nTODO: examples\n"))
+	require.Len(t, findings, 2)
+
+	assert.Equal(t, "alpha", findings[0].Plugin)
+	assert.Equal(t, SeverityWarning, findings[0].Severity)
+	assert.Contains(t, findings[0].Message, "rule failed")
+
+	assert.Equal(t, Finding{Plugin: "writing", Rule: "no-todo", Severity: SeverityWarning, File: "SKILL.md", Line: 2, Message: "resolve TODOs"}, findings[1])
+	assert.Equal(t, "[warning] SKILL.md:2: resolve TODOs (writing/no-todo)", findings[1].String())
+
+	r.RemovePlugin("writing")
+	r.RemovePlugin("alpha")
+	assert.Empty(t, r.Check(context.Background(), NewFile(TargetSkill, "SKILL.md", "TODO")))
+}
@@ -0,0 +1,318 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target is the kind of file a rule checks.
+type Target string
+
+const (
+	TargetGo    Target = "go"
+	TargetSkill Target = "skill"
+	TargetSpec  Target = "spec"
+)
+
+// Severity of a finding.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Finding is one problem a rule reports.
+type Finding struct {
+	Plugin   string
+	Rule     string
+	Severity Severity
+	File     string
+	Line     int
+	Message  string
+}
+
+func (f Finding) String() string {
+	loc := f.File
+	if f.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("[%s] %s: %s (%s/%s)", f.Severity, loc, f.Message, f.Plugin, f.Rule)
+}
+
+// Rule is a check loaded from a rule.yaml file. Declarative rules list
+// conditions that must all match; script rules delegate to a JavaScript
+// check(file) function run in the execution sandbox.
+type Rule struct {
+	Name        string      `yaml:"name"`
+	Description string      `yaml:"description"`
+	Priority    string      `yaml:"priority"`
+	Target      Target      `yaml:"target"`
+	Trigger     Trigger     `yaml:"trigger"`
+	Conditions  []Condition `yaml:"conditions"`
+	Actions     []Action    `yaml:"actions"`
+	Script      string      `yaml:"script"`
+
+	source string
+}
+
+// Trigger selects the files a rule runs on. Pattern is a glob matched
+// against the file name, or against the whole path when it contains "/".
+type Trigger struct {
+	Event   string `yaml:"event"`
+	Pattern string `yaml:"pattern"`
+}
+
+// Condition is one declarative check.
+//
+//   - content_matches: regexp Pattern matches a line; reported per line
+//   - content_missing: regexp Pattern matches nowhere in the file
+//   - import: a Go import path matches glob Pattern
+//   - call: a Go call such as "fmt.Println" matches glob Pattern
+//   - func_length: a Go function body is longer than Max lines
+type Condition struct {
+	Type    string `yaml:"type"`
+	Pattern string `yaml:"pattern"`
+	Max     int    `yaml:"max"`
+
+	re *regexp.Regexp
+}
+
+// Action says how a matching rule is reported: reject (error), warn
+// (warning) or info.
+type Action struct {
+	Type    string `yaml:"type"`
+	Message string `yaml:"message"`
+}
+
+// LoadRule reads and validates a rule file. A script path is resolved
+// relative to the rule file.
+func LoadRule(rulePath string) (*Rule, error) {
+	data, err := os.ReadFile(rulePath) // #nosec G304 -- rule path from an installed plugin manifest
+	if err != nil {
+		return nil, fmt.Errorf("read rule: %w", err)
+	}
+
+	var r Rule
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse rule: %w", err)
+	}
+
+	if r.Script != "" {
+		scriptPath := filepath.Join(filepath.Dir(rulePath), filepath.FromSlash(r.Script))
+		src, err := os.ReadFile(scriptPath) // #nosec G304 -- script next to the rule file
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: read script: %w", r.Name, err)
+		}
+		r.source = string(src)
+	}
+
+	if err := r.compile(); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+
+	return &r, nil
+}
+
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+
+	if r.Target == "" {
+		r.Target = targetFor(r.Trigger.Pattern)
+	}
+	switch r.Target {
+	case TargetGo, TargetSkill, TargetSpec:
+	default:
+		return fmt.Errorf("unknown target %q (expected go, skill or spec)", r.Target)
+	}
+
+	if r.Script != "" {
+		if len(r.Conditions) > 0 {
+			return fmt.Errorf("script and conditions are mutually exclusive")
+		}
+		return nil
+	}
+
+	if len(r.Conditions) == 0 {
+		return fmt.Errorf("conditions or script required")
+	}
+	if len(r.Actions) == 0 {
+		return fmt.Errorf("actions required")
+	}
+	if _, err := r.severity(); err != nil {
+		return err
+	}
+
+	for i := range r.Conditions {
+		c := &r.Conditions[i]
+		switch c.Type {
+		case "content_matches", "content_missing":
+			re, err := regexp.Compile(c.Pattern)
+			if err != nil {
+				return fmt.Errorf("condition %d: %w", i+1, err)
+			}
+			c.re = re
+		case "import", "call":
+			if r.Target != TargetGo {
+				return fmt.Errorf("condition %d: %s only applies to go rules", i+1, c.Type)
+			}
+			if _, err := path.Match(c.Pattern, ""); err != nil {
+				return fmt.Errorf("condition %d: %w", i+1, err)
+			}
+		case "func_length":
+			if r.Target != TargetGo {
+				return fmt.Errorf("condition %d: func_length only applies to go rules", i+1)
+			}
+			if c.Max <= 0 {
+				return fmt.Errorf("condition %d: func_length needs max > 0", i+1)
+			}
+		default:
+			return fmt.Errorf("condition %d: unknown type %q", i+1, c.Type)
+		}
+	}
+
+	return nil
+}
+
+func targetFor(pattern string) Target {
+	switch {
+	case strings.HasSuffix(pattern, ".go"):
+		return TargetGo
+	case strings.Contains(pattern, "SKILL"):
+		return TargetSkill
+	case strings.HasSuffix(pattern, ".md"):
+		return TargetSpec
+	}
+	return ""
+}
+
+func (r *Rule) severity() (Severity, error) {
+	switch r.Actions[0].Type {
+	case "reject", "error":
+		return SeverityError, nil
+	case "warn", "warning":
+		return SeverityWarning, nil
+	case "info", "log":
+		return SeverityInfo, nil
+	}
+	return "", fmt.Errorf("unknown action %q (expected reject, warn or info)", r.Actions[0].Type)
+}
+
+func (r *Rule) message() string {
+	if msg := r.Actions[0].Message; msg != "" {
+		return msg
+	}
+	if r.Description != "" {
+		return r.Description
+	}
+	return r.Name
+}
+
+// Applies reports whether the rule checks file at path of kind target.
+func (r *Rule) Applies(target Target, filePath string) bool {
+	if r.Target != target {
+		return false
+	}
+	if r.Trigger.Pattern == "" {
+		return true
+	}
+
+	name := filepath.ToSlash(filePath)
+	if !strings.Contains(r.Trigger.Pattern, "/") {
+		name = path.Base(name)
+	}
+	ok, _ := path.Match(r.Trigger.Pattern, name)
+	return ok
+}
+
+// Check runs the rule on f. Findings carry the rule name; the registry adds
+// the plugin.
+func (r *Rule) Check(ctx context.Context, f *File) ([]Finding, error) {
+	if r.Script != "" {
+		return r.runScript(ctx, f)
+	}
+
+	var hits []int
+	for i, c := range r.Conditions {
+		lines, err := c.match(f)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			return nil, nil
+		}
+		if i == 0 {
+			hits = lines
+		}
+	}
+
+	severity, _ := r.severity()
+	findings := make([]Finding, 0, len(hits))
+	for _, line := range hits {
+		findings = append(findings, Finding{
+			Rule:     r.Name,
+			Severity: severity,
+			File:     f.Path,
+			Line:     line,
+			Message:  r.message(),
+		})
+	}
+	return findings, nil
+}
+
+// match returns the lines where c holds; 0 stands for the whole file.
+func (c Condition) match(f *File) ([]int, error) {
+	switch c.Type {
+	case "content_matches":
+		var lines []int
+		for i, line := range f.Lines() {
+			if c.re.MatchString(line) {
+				lines = append(lines, i+1)
+			}
+		}
+		return lines, nil
+	case "content_missing":
+		if c.re.MatchString(f.Content) {
+			return nil, nil
+		}
+		return []int{0}, nil
+	}
+
+	facts, err := f.Go()
+	if err != nil {
+		return nil, err
+	}
+
+	var lines []int
+	switch c.Type {
+	case "import":
+		for _, imp := range facts.Imports {
+			if ok, _ := path.Match(c.Pattern, imp.Path); ok {
+				lines = append(lines, imp.Line)
+			}
+		}
+	case "call":
+		for _, call := range facts.Calls {
+			if ok, _ := path.Match(c.Pattern, call.Name); ok {
+				lines = append(lines, call.Line)
+			}
+		}
+	case "func_length":
+		for _, fn := range facts.Funcs {
+			if fn.EndLine-fn.Line+1 > c.Max {
+				lines = append(lines, fn.Line)
+			}
+		}
+	}
+	return lines, nil
+}
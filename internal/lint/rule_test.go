@@ -0,0 +1,232 @@
+package lint
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goSample = `package demo
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// TODO: remove
+func Short() { fmt.Println("hi") }
+
+func Long() {
+	a := 1
+	b := 2
+	fmt.Printf("%d\n", a+b)
+}
+
+var _ = unsafe.Sizeof(0)
+`
+
+func writeRule(t *testing.T, yaml string, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rule.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0600))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	return path
+}
+
+func TestRule_Check_Declarative(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		rule      string
+		wantLines []int
+		severity  Severity
+	}{
+		{
+			name: "content matches",
+			rule: `name: no-todo
+trigger: {pattern: "*.go"}
+conditions: [{type: content_matches, pattern: "TODO"}]
+actions: [{type: reject, message: "TODOs not allowed"}]`,
+			wantLines: []int{8},
+			severity:  SeverityError,
+		},
+		{
+			name: "content missing",
+			rule: `name: license
+target: go
+conditions: [{type: content_missing, pattern: "Copyright"}]
+actions: [{type: warn, message: "missing license header"}]`,
+			wantLines: []int{0},
+			severity:  SeverityWarning,
+		},
+		{
+			name: "import",
+			rule: `name: no-unsafe
+trigger: {pattern: "*.go"}
+conditions: [{type: import, pattern: "unsafe"}]
+actions: [{type: reject}]`,
+			wantLines: []int{5},
+			severity:  SeverityError,
+		},
+		{
+			name: "call",
+			rule: `name: no-print
+trigger: {pattern: "*.go"}
+conditions: [{type: call, pattern: "fmt.Print*"}]
+actions: [{type: info}]`,
+			wantLines: []int{9, 14},
+			severity:  SeverityInfo,
+		},
+		{
+			name: "func length",
+			rule: `name: short-funcs
+trigger: {pattern: "*.go"}
+conditions: [{type: func_length, max: 3}]
+actions: [{type: warn}]`,
+			wantLines: []int{11},
+			severity:  SeverityWarning,
+		},
+		{
+			name: "all conditions must hold",
+			rule: `name: print-without-log
+trigger: {pattern: "*.go"}
+conditions:
+  - {type: call, pattern: "fmt.Println"}
+  - {type: import, pattern: "log"}
+actions: [{type: warn}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := LoadRule(writeRule(t, tt.rule, nil))
+			require.NoError(t, err)
+			assert.Equal(t, TargetGo, rule.Target)
+
+			findings, err := rule.Check(context.Background(), NewFile(TargetGo, "demo.go", goSample))
+			require.NoError(t, err)
+
+			lines := []int{}
+			for _, f := range findings {
+				lines = append(lines, f.Line)
+				assert.Equal(t, tt.severity, f.Severity)
+				assert.Equal(t, rule.Name, f.Rule)
+			}
+			if tt.wantLines == nil {
+				tt.wantLines = []int{}
+			}
+			assert.Equal(t, tt.wantLines, lines)
+		})
+	}
+}
+
+func TestRule_Check_Script(t *testing.T) {
+	t.Parallel()
+
+	script := `function check(file) {
+  var out = [];
+  for (var i = 0; i < file.calls.length; i++) {
+    var c = file.calls[i];
+    if (c.name === "fmt.Printf") {
+      out.push({line: c.line, message: "use a logger in " + file.package, severity: "error"});
+    }
+  }
+  if (file.funcs.length > 1) {
+    out.push({message: "one function per file"});
+  }
+  return out;
+}`
+	path := writeRule(t, `name: scripted
+trigger: {pattern: "*.go"}
+script: check.js
+`, map[string]string{"check.js": script})
+
+	rule, err := LoadRule(path)
+	require.NoError(t, err)
+
+	findings, err := rule.Check(context.Background(), NewFile(TargetGo, "demo.go", goSample))
+	require.NoError(t, err)
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{Rule: "scripted", Severity: SeverityError, File: "demo.go", Line: 14, Message: "use a logger in demo"}, findings[0])
+	assert.Equal(t, SeverityWarning, findings[1].Severity)
+	assert.Equal(t, "one function per file", findings[1].Message)
+}
+
+func TestRule_Check_ScriptErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{name: "not an array", script: `function check(file) { return "nope"; }`, wantErr: "must return an array"},
+		{name: "bad severity", script: `function check(file) { return [{severity: "fatal"}]; }`, wantErr: "unknown severity"},
+		{name: "throws", script: `function check(file) { throw new Error("boom"); }`, wantErr: "boom"},
+		{name: "no check", script: `var x = 1;`, wantErr: "function not found: check"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rule, err := LoadRule(writeRule(t, "name: s\ntarget: skill\nscript: s.js\n", map[string]string{"s.js": tt.script}))
+			require.NoError(t, err)
+
+			_, err = rule.Check(context.Background(), NewFile(TargetSkill, "SKILL.md", "# Skill"))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestLoadRule_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr string
+	}{
+		{name: "no name", rule: "target: go\nconditions: [{type: content_matches, pattern: x}]\nactions: [{type: warn}]", wantErr: "name cannot be empty"},
+		{name: "no target", rule: "name: r\nconditions: [{type: content_matches, pattern: x}]\nactions: [{type: warn}]", wantErr: "unknown target"},
+		{name: "no conditions", rule: "name: r\ntarget: go\nactions: [{type: warn}]", wantErr: "conditions or script required"},
+		{name: "no actions", rule: "name: r\ntarget: go\nconditions: [{type: content_matches, pattern: x}]", wantErr: "actions required"},
+		{name: "bad action", rule: "name: r\ntarget: go\nconditions: [{type: content_matches, pattern: x}]\nactions: [{type: explode}]", wantErr: "unknown action"},
+		{name: "bad regexp", rule: "name: r\ntarget: go\nconditions: [{type: content_matches, pattern: '('}]\nactions: [{type: warn}]", wantErr: "condition 1"},
+		{name: "go condition on skill", rule: "name: r\ntarget: skill\nconditions: [{type: import, pattern: x}]\nactions: [{type: warn}]", wantErr: "only applies to go rules"},
+		{name: "missing script", rule: "name: r\ntarget: go\nscript: missing.js", wantErr: "read script"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadRule(writeRule(t, tt.rule, nil))
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestRule_Applies(t *testing.T) {
+	t.Parallel()
+
+	rule := &Rule{Target: TargetSpec, Trigger: Trigger{Pattern: "spec.md"}}
+	assert.True(t, rule.Applies(TargetSpec, "openspec/specs/auth/spec.md"))
+	assert.False(t, rule.Applies(TargetSpec, "openspec/changes/x/tasks.md"))
+	assert.False(t, rule.Applies(TargetSkill, "spec.md"))
+
+	nested := &Rule{Target: TargetGo, Trigger: Trigger{Pattern: "internal/*/*.go"}}
+	assert.True(t, nested.Applies(TargetGo, "internal/lint/rule.go"))
+	assert.False(t, nested.Applies(TargetGo, "cmd/main.go"))
+}
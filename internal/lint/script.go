@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/execution"
+)
+
+// ScriptTimeout bounds a single check(file) call of a script rule.
+const ScriptTimeout = 5 * time.Second
+
+// runScript evaluates the rule script in a fresh sandboxed VM and calls its
+// check(file) function. The function returns an array of
+// {line, message, severity} objects; severity defaults to warning.
+func (r *Rule) runScript(ctx context.Context, f *File) ([]Finding, error) {
+	sandbox := execution.NewSandbox(execution.ResourceLimits{MaxExecTime: ScriptTimeout})
+	vm := execution.NewCodeMode(sandbox)
+
+	if _, err := vm.Execute(ctx, r.source, nil); err != nil {
+		return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+
+	input := map[string]any{
+		"path":    f.Path,
+		"target":  string(f.Target),
+		"content": f.Content,
+		"lines":   f.Lines(),
+	}
+	if f.Target == TargetGo {
+		facts, err := f.Go()
+		if err != nil {
+			return nil, err
+		}
+		input["package"] = facts.Package
+		input["imports"] = facts.importsJS()
+		input["calls"] = facts.callsJS()
+		input["funcs"] = facts.funcsJS()
+	}
+
+	out, err := vm.ExecuteFunction(ctx, "check", input)
+	if err != nil {
+		return nil, fmt.Errorf("rule %s: %w", r.Name, err)
+	}
+
+	return r.scriptFindings(f, out)
+}
+
+func (r *Rule) scriptFindings(f *File, out any) ([]Finding, error) {
+	if out == nil {
+		return nil, nil
+	}
+
+	items, ok := out.([]any)
+	if !ok {
+		return nil, fmt.Errorf("rule %s: check must return an array, got %T", r.Name, out)
+	}
+
+	findings := make([]Finding, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("rule %s: finding %d is not an object", r.Name, i+1)
+		}
+
+		finding := Finding{
+			Rule:     r.Name,
+			Severity: SeverityWarning,
+			File:     f.Path,
+			Message:  r.Description,
+		}
+		if msg, ok := obj["message"].(string); ok && msg != "" {
+			finding.Message = msg
+		}
+		switch line := obj["line"].(type) {
+		case int64:
+			finding.Line = int(line)
+		case float64:
+			finding.Line = int(line)
+		}
+		if sev, ok := obj["severity"].(string); ok {
+			switch Severity(sev) {
+			case SeverityError, SeverityWarning, SeverityInfo:
+				finding.Severity = Severity(sev)
+			default:
+				return nil, fmt.Errorf("rule %s: finding %d: unknown severity %q", r.Name, i+1, sev)
+			}
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings, nil
+}
+
+// The JS helpers hand plain maps to the VM so scripts see the lower-case
+// field names rather than the Go struct fields.
+
+func (g *GoFacts) importsJS() []any {
+	out := make([]any, 0, len(g.Imports))
+	for _, imp := range g.Imports {
+		out = append(out, map[string]any{"path": imp.Path, "line": imp.Line})
+	}
+	return out
+}
+
+func (g *GoFacts) callsJS() []any {
+	out := make([]any, 0, len(g.Calls))
+	for _, call := range g.Calls {
+		out = append(out, map[string]any{"name": call.Name, "line": call.Line})
+	}
+	return out
+}
+
+func (g *GoFacts) funcsJS() []any {
+	out := make([]any, 0, len(g.Funcs))
+	for _, fn := range g.Funcs {
+		out = append(out, map[string]any{"name": fn.Name, "line": fn.Line, "end_line": fn.EndLine})
+	}
+	return out
+}
@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"time"
+
+	"github.com/victorzhuk/go-ent/internal/config"
 )
 
 const (
@@ -19,17 +24,37 @@ const (
 	MaxDownloadSize = 100 * 1024 * 1024
 	// MaxErrorBodySize is the maximum error response body to read (1MB).
 	MaxErrorBodySize = 1024 * 1024
+	// ProjectDir is the directory under the project root holding marketplace
+	// state: the git mirror and the trusted keys.
+	ProjectDir = ".go-ent"
+	// DefaultMirrorPath is where the git backend clones the marketplace,
+	// relative to the project root.
+	DefaultMirrorPath = ProjectDir + "/marketplace"
+	// SyncTimeout bounds the git clone or fetch run when the server starts.
+	SyncTimeout = 30 * time.Second
 )
 
-// Client handles marketplace API interactions.
-type Client struct {
+// Client is a source of marketplace plugins.
+type Client interface {
+	// Search returns plugins matching query and opts.
+	Search(ctx context.Context, query string, opts SearchOptions) ([]PluginInfo, error)
+
+	// Download returns the zip archive of a plugin version.
+	Download(ctx context.Context, name, version string) ([]byte, error)
+
+	// GetPlugin returns the latest version of a plugin.
+	GetPlugin(ctx context.Context, name string) (*PluginInfo, error)
+}
+
+// HTTPClient handles marketplace API interactions.
+type HTTPClient struct {
 	baseURL    string
 	httpClient *http.Client
 }
 
 // NewClient creates a new marketplace client with default base URL.
-func NewClient() *Client {
-	return &Client{
+func NewClient() *HTTPClient {
+	return &HTTPClient{
 		baseURL: DefaultBaseURL,
 		httpClient: &http.Client{
 			Timeout: Timeout,
@@ -38,8 +63,8 @@ func NewClient() *Client {
 }
 
 // NewClientWithURL creates a new marketplace client with custom base URL.
-func NewClientWithURL(baseURL string) *Client {
-	return &Client{
+func NewClientWithURL(baseURL string) *HTTPClient {
+	return &HTTPClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: Timeout,
@@ -48,7 +73,7 @@ func NewClientWithURL(baseURL string) *Client {
 }
 
 // Search searches for plugins in the marketplace.
-func (c *Client) Search(ctx context.Context, query string, opts SearchOptions) ([]PluginInfo, error) {
+func (c *HTTPClient) Search(ctx context.Context, query string, opts SearchOptions) ([]PluginInfo, error) {
 	reqURL := fmt.Sprintf("%s/plugins/search?q=%s", c.baseURL, url.QueryEscape(query))
 
 	if opts.Category != "" {
@@ -92,7 +117,7 @@ func (c *Client) Search(ctx context.Context, query string, opts SearchOptions) (
 }
 
 // Download downloads a plugin from the marketplace.
-func (c *Client) Download(ctx context.Context, name, version string) ([]byte, error) {
+func (c *HTTPClient) Download(ctx context.Context, name, version string) ([]byte, error) {
 	url := fmt.Sprintf("%s/plugins/%s/versions/%s/download", c.baseURL, name, version)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -124,7 +149,7 @@ func (c *Client) Download(ctx context.Context, name, version string) ([]byte, er
 }
 
 // GetPlugin retrieves plugin details from marketplace.
-func (c *Client) GetPlugin(ctx context.Context, name string) (*PluginInfo, error) {
+func (c *HTTPClient) GetPlugin(ctx context.Context, name string) (*PluginInfo, error) {
 	url := fmt.Sprintf("%s/plugins/%s", c.baseURL, name)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -179,3 +204,38 @@ type SearchResponse struct {
 	Plugins []PluginInfo `json:"plugins"`
 	Total   int          `json:"total"`
 }
+
+// NewFromConfig creates the client selected by cfg. Relative paths are
+// resolved against projectRoot; the git backend syncs its clone first and
+// serves the existing clone when the remote cannot be reached.
+func NewFromConfig(ctx context.Context, cfg config.MarketplaceConfig, projectRoot string) (Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	path := cfg.Path
+	if path != "" && !filepath.IsAbs(path) {
+		path = filepath.Join(projectRoot, path)
+	}
+
+	switch cfg.Backend {
+	case "fs":
+		return NewFSClient(path), nil
+	case "git":
+		if path == "" {
+			path = filepath.Join(projectRoot, DefaultMirrorPath)
+		}
+		if err := SyncGit(ctx, cfg.URL, cfg.Ref, path); err != nil {
+			if _, statErr := os.Stat(filepath.Join(path, IndexFile)); statErr != nil {
+				return nil, fmt.Errorf("sync marketplace: %w", err)
+			}
+			slog.Warn("failed to sync marketplace, using local copy", "path", path, "error", err)
+		}
+		return NewFSClient(path), nil
+	default:
+		if cfg.URL != "" {
+			return NewClientWithURL(cfg.URL), nil
+		}
+		return NewClient(), nil
+	}
+}
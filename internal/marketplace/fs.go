@@ -0,0 +1,195 @@
+package marketplace
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FSClient serves plugins from a directory holding an index.yaml and the
+// archives it references, for air-gapped machines and local mirrors.
+type FSClient struct {
+	root string
+}
+
+// NewFSClient creates a marketplace client reading from root.
+func NewFSClient(root string) *FSClient {
+	return &FSClient{root: root}
+}
+
+// Root returns the marketplace directory.
+func (c *FSClient) Root() string {
+	return c.root
+}
+
+// Search matches query against plugin names, descriptions and tags. The
+// index is re-read on every call so a rebuilt mirror is picked up at once.
+func (c *FSClient) Search(ctx context.Context, query string, opts SearchOptions) ([]PluginInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	idx, err := LoadIndex(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	terms := strings.Fields(strings.ToLower(strings.ReplaceAll(query, ",", " ")))
+
+	plugins := []PluginInfo{}
+	for _, e := range idx.Latest() {
+		if opts.Category != "" && !strings.EqualFold(e.Category, opts.Category) {
+			continue
+		}
+		if opts.Author != "" && !strings.EqualFold(e.Author, opts.Author) {
+			continue
+		}
+		if !matchesTerms(e, terms) {
+			continue
+		}
+		plugins = append(plugins, e.Info())
+	}
+
+	if opts.Limit > 0 && len(plugins) > opts.Limit {
+		plugins = plugins[:opts.Limit]
+	}
+
+	return plugins, nil
+}
+
+func matchesTerms(e IndexEntry, terms []string) bool {
+	text := strings.ToLower(e.Name + " " + e.Description + " " + strings.Join(e.Tags, " "))
+	for _, t := range terms {
+		if !strings.Contains(text, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// Download reads the archive of a plugin version and checks it against the
+// size and digest recorded in the index.
+func (c *FSClient) Download(ctx context.Context, name, version string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	idx, err := LoadIndex(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := idx.Find(name, version)
+	if !ok {
+		return nil, fmt.Errorf("download failed: plugin %s@%s not in index", name, version)
+	}
+
+	path := filepath.Join(c.root, filepath.FromSlash(entry.Archive))
+	rel, err := filepath.Rel(c.root, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return nil, fmt.Errorf("unsafe archive path: %s", entry.Archive)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("stat archive: %w", err)
+	}
+	if info.Size() >= MaxDownloadSize {
+		return nil, fmt.Errorf("download size exceeds maximum allowed size of %d bytes", MaxDownloadSize)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- path checked against marketplace root
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+
+	if entry.SHA256 != "" && !strings.EqualFold(Digest(data), entry.SHA256) {
+		return nil, fmt.Errorf("archive %s digest mismatch", entry.Archive)
+	}
+
+	return data, nil
+}
+
+// GetPlugin returns the latest indexed version of name.
+func (c *FSClient) GetPlugin(ctx context.Context, name string) (*PluginInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	idx, err := LoadIndex(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, ok := idx.Find(name, "")
+	if !ok {
+		return nil, fmt.Errorf("get plugin failed: plugin %s not in index", name)
+	}
+
+	info := entry.Info()
+	return &info, nil
+}
+
+// Versions lists every indexed version of name, newest first.
+func (c *FSClient) Versions(ctx context.Context, name string) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	idx, err := LoadIndex(c.root)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range idx.Plugins {
+		if e.Name == name {
+			versions = append(versions, e.Version)
+		}
+	}
+	slices.SortFunc(versions, func(a, b string) int { return compareVersions(b, a) })
+
+	return versions, nil
+}
+
+// SyncGit clones repo into dir, or fast-forwards an existing clone, so a git
+// repository can serve as a file-based marketplace. An empty ref tracks the
+// remote default branch.
+func SyncGit(ctx context.Context, repo, ref, dir string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		if err := runGit(ctx, dir, "fetch", "--depth", "1", "origin", orDefaultRef(ref)); err != nil {
+			return err
+		}
+		return runGit(ctx, dir, "checkout", "--force", "FETCH_HEAD")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0750); err != nil {
+		return fmt.Errorf("create marketplace directory: %w", err)
+	}
+
+	args := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	return runGit(ctx, "", slices.Concat(args, []string{repo, dir})...)
+}
+
+func orDefaultRef(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+func runGit(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...) // #nosec G204 -- arguments from marketplace config
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package marketplace
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/config"
+)
+
+func writeMirror(t *testing.T, entries ...IndexEntry) string {
+	t.Helper()
+
+	root := t.TempDir()
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml"), []byte("name: demo\n"), 0600))
+
+	data, err := PackDir(src, nil)
+	require.NoError(t, err)
+
+	idx := &Index{Generated: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)}
+	for _, e := range entries {
+		archive, err := ArchiveName(e.Name, e.Version)
+		require.NoError(t, err)
+		e.Archive = archive
+		e.SHA256 = Digest(data)
+		e.Size = int64(len(data))
+		require.NoError(t, os.MkdirAll(filepath.Join(root, ArchiveDir), 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(root, e.Archive), data, 0600))
+		idx.Put(e)
+	}
+	require.NoError(t, WriteIndex(root, idx))
+
+	return root
+}
+
+func TestFSClient_Search(t *testing.T) {
+	t.Parallel()
+
+	root := writeMirror(t,
+		IndexEntry{Name: "go-kit", Version: "1.2.0", Description: "Go service skills", Author: "acme", Category: "skills", Tags: []string{"go", "api"}},
+		IndexEntry{Name: "go-kit", Version: "1.10.0", Description: "Go service skills", Author: "acme", Category: "skills", Tags: []string{"go", "api"}},
+		IndexEntry{Name: "db-rules", Version: "0.1.0", Description: "Database review rules", Author: "other", Category: "rules", Tags: []string{"sql"}},
+	)
+	client := NewFSClient(root)
+
+	tests := []struct {
+		name     string
+		query    string
+		opts     SearchOptions
+		expected []string
+	}{
+		{name: "empty query lists latest versions", expected: []string{"db-rules@0.1.0", "go-kit@1.10.0"}},
+		{name: "matches description", query: "database", expected: []string{"db-rules@0.1.0"}},
+		{name: "matches tags", query: "go,api", expected: []string{"go-kit@1.10.0"}},
+		{name: "category filter", opts: SearchOptions{Category: "RULES"}, expected: []string{"db-rules@0.1.0"}},
+		{name: "author filter", opts: SearchOptions{Author: "acme"}, expected: []string{"go-kit@1.10.0"}},
+		{name: "limit", opts: SearchOptions{Limit: 1}, expected: []string{"db-rules@0.1.0"}},
+		{name: "no match", query: "python", expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plugins, err := client.Search(context.Background(), tt.query, tt.opts)
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, p := range plugins {
+				got = append(got, p.Name+"@"+p.Version)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestFSClient_Download(t *testing.T) {
+	t.Parallel()
+
+	root := writeMirror(t,
+		IndexEntry{Name: "go-kit", Version: "1.0.0"},
+		IndexEntry{Name: "go-kit", Version: "2.0.0"},
+	)
+	client := NewFSClient(root)
+	ctx := context.Background()
+
+	data, err := client.Download(ctx, "go-kit", "1.0.0")
+	require.NoError(t, err)
+	assert.NoError(t, NewInstaller(client, t.TempDir()).Verify(data))
+
+	_, err = client.Download(ctx, "go-kit", "latest")
+	require.NoError(t, err)
+
+	_, err = client.Download(ctx, "go-kit", "3.0.0")
+	assert.ErrorContains(t, err, "not in index")
+
+	archive, err := ArchiveName("go-kit", "2.0.0")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(root, archive), []byte("PK\x03\x04tampered"), 0600))
+	_, err = client.Download(ctx, "go-kit", "2.0.0")
+	assert.ErrorContains(t, err, "digest mismatch")
+}
+
+func TestFSClient_GetPlugin(t *testing.T) {
+	t.Parallel()
+
+	root := writeMirror(t,
+		IndexEntry{Name: "go-kit", Version: "1.9.0", Skills: 2},
+		IndexEntry{Name: "go-kit", Version: "1.10.0", Skills: 3},
+	)
+	client := NewFSClient(root)
+
+	info, err := client.GetPlugin(context.Background(), "go-kit")
+	require.NoError(t, err)
+	assert.Equal(t, "1.10.0", info.Version)
+	assert.Equal(t, 3, info.Skills)
+
+	_, err = client.GetPlugin(context.Background(), "missing")
+	assert.Error(t, err)
+}
+
+func TestFSClient_MissingIndex(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFSClient(t.TempDir()).Search(context.Background(), "", SearchOptions{})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestPackDir_Deterministic(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "skills", "go"), 0750))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, ".git"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml"), []byte("name: demo\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "skills", "go", "SKILL.md"), []byte("# Go\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref\n"), 0600))
+
+	first, err := PackDir(src, nil)
+	require.NoError(t, err)
+
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(src, "plugin.yaml"), later, later))
+	second, err := PackDir(src, nil)
+	require.NoError(t, err)
+	assert.Equal(t, Digest(first), Digest(second))
+
+	installer := NewInstaller(NewFSClient(""), t.TempDir())
+	require.NoError(t, installer.Extract("demo", first))
+	assert.FileExists(t, filepath.Join(installer.pluginsDir, "demo", "skills", "go", "SKILL.md"))
+	assert.NoFileExists(t, filepath.Join(installer.pluginsDir, "demo", ".git", "HEAD"))
+}
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.9.0", "1.10.0", -1},
+		{"2.0.0", "1.99.99", 1},
+		{"1.0", "1.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, compareVersions(tt.a, tt.b), "%s vs %s", tt.a, tt.b)
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	t.Parallel()
+
+	archive, err := ArchiveName("go-kit", "v2.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "plugins/go-kit-2.0.0.zip", archive)
+
+	for _, tt := range [][2]string{
+		{"../escape", "1.0.0"},
+		{"nested/name", "1.0.0"},
+		{`win\name`, "1.0.0"},
+		{"go-kit", "1.0.0/../../x"},
+		{"go-kit", ".."},
+		{"", "1.0.0"},
+		{"go-kit", ""},
+	} {
+		_, err := ArchiveName(tt[0], tt[1])
+		assert.ErrorIs(t, err, ErrUnsafeArchiveName, "%q %q", tt[0], tt[1])
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	client, err := NewFromConfig(context.Background(), config.MarketplaceConfig{}, root)
+	require.NoError(t, err)
+	assert.IsType(t, &HTTPClient{}, client)
+
+	client, err = NewFromConfig(context.Background(), config.MarketplaceConfig{Backend: "http", URL: "http://mirror.local/api"}, root)
+	require.NoError(t, err)
+	assert.Equal(t, "http://mirror.local/api", client.(*HTTPClient).baseURL)
+
+	client, err = NewFromConfig(context.Background(), config.MarketplaceConfig{Backend: "fs", Path: "mirror"}, root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "mirror"), client.(*FSClient).Root())
+
+	_, err = NewFromConfig(context.Background(), config.MarketplaceConfig{Backend: "ftp"}, root)
+	assert.ErrorIs(t, err, config.ErrInvalidMarketplaceConfig)
+}
+
+func TestNewFromConfig_Git(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := writeMirror(t, IndexEntry{Name: "go-kit", Version: "1.0.0"})
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-qm", "index"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	root := t.TempDir()
+	cfg := config.MarketplaceConfig{Backend: "git", URL: repo}
+
+	for range 2 {
+		client, err := NewFromConfig(context.Background(), cfg, root)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, DefaultMirrorPath), client.(*FSClient).Root())
+
+		info, err := client.GetPlugin(context.Background(), "go-kit")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", info.Version)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := NewFromConfig(ctx, config.MarketplaceConfig{Backend: "git", URL: repo, Path: "other"}, root)
+	assert.Error(t, err, "the sync stops with its context")
+}
+
+func TestNewFromConfig_GitUnreachable(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	unreachable := filepath.Join(t.TempDir(), "missing")
+
+	_, err := NewFromConfig(context.Background(), config.MarketplaceConfig{Backend: "git", URL: unreachable}, root)
+	require.Error(t, err, "nothing to serve without a local copy")
+
+	mirror := writeMirror(t, IndexEntry{Name: "go-kit", Version: "1.0.0"})
+	require.NoError(t, os.Rename(mirror, filepath.Join(root, "mirror")))
+	for _, args := range [][]string{{"init", "-q"}, {"remote", "add", "origin", unreachable}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = filepath.Join(root, "mirror")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	client, err := NewFromConfig(context.Background(), config.MarketplaceConfig{Backend: "git", URL: unreachable, Path: "mirror"}, root)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "mirror"), client.(*FSClient).Root())
+
+	info, err := client.GetPlugin(context.Background(), "go-kit")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", info.Version)
+}
@@ -0,0 +1,309 @@
+package marketplace
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// IndexFile is the name of the index at the root of a file-based marketplace.
+	IndexFile = "index.yaml"
+	// ArchiveDir is the directory holding plugin archives next to the index.
+	ArchiveDir = "plugins"
+	// IndexVersion is the index format written by WriteIndex.
+	IndexVersion = 1
+)
+
+// ErrUnsafeArchiveName indicates a plugin name or version that would place
+// its archive outside ArchiveDir.
+var ErrUnsafeArchiveName = errors.New("unsafe plugin archive name")
+
+// Index lists the plugins of a file-based marketplace.
+type Index struct {
+	Version   int          `yaml:"version"`
+	Generated time.Time    `yaml:"generated"`
+	Plugins   []IndexEntry `yaml:"plugins"`
+}
+
+// IndexEntry is one plugin version in an index. Archive is relative to the
+// index directory.
+type IndexEntry struct {
+	Name        string   `yaml:"name"`
+	Version     string   `yaml:"version"`
+	Description string   `yaml:"description,omitempty"`
+	Author      string   `yaml:"author,omitempty"`
+	Category    string   `yaml:"category,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Skills      int      `yaml:"skills_count,omitempty"`
+	Agents      int      `yaml:"agents_count,omitempty"`
+	Rules       int      `yaml:"rules_count,omitempty"`
+	Archive     string   `yaml:"archive"`
+	SHA256      string   `yaml:"sha256"`
+	Size        int64    `yaml:"size"`
+}
+
+// Info converts the entry to the metadata returned by Client.Search.
+func (e IndexEntry) Info() PluginInfo {
+	return PluginInfo{
+		Name:        e.Name,
+		Version:     e.Version,
+		Description: e.Description,
+		Author:      e.Author,
+		Category:    e.Category,
+		Tags:        e.Tags,
+		Skills:      e.Skills,
+		Agents:      e.Agents,
+		Rules:       e.Rules,
+	}
+}
+
+// LoadIndex reads the index of the marketplace rooted at dir.
+func LoadIndex(dir string) (*Index, error) {
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile)) // #nosec G304 -- configured marketplace directory
+	if err != nil {
+		return nil, fmt.Errorf("read index: %w", err)
+	}
+
+	var idx Index
+	if err := yaml.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("parse index: %w", err)
+	}
+
+	if idx.Version > IndexVersion {
+		return nil, fmt.Errorf("unsupported index version %d (max %d)", idx.Version, IndexVersion)
+	}
+
+	for i, e := range idx.Plugins {
+		if e.Name == "" || e.Version == "" || e.Archive == "" {
+			return nil, fmt.Errorf("index entry %d: name, version and archive are required", i+1)
+		}
+	}
+
+	return &idx, nil
+}
+
+// WriteIndex writes idx to dir, sorting entries by name and version.
+func WriteIndex(dir string, idx *Index) error {
+	idx.Version = IndexVersion
+	slices.SortFunc(idx.Plugins, func(a, b IndexEntry) int {
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c
+		}
+		return compareVersions(a.Version, b.Version)
+	})
+
+	data, err := yaml.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("marshal index: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("create index directory: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, IndexFile), data, 0600); err != nil {
+		return fmt.Errorf("write index: %w", err)
+	}
+
+	return nil
+}
+
+// Find returns the entry for name at version, or the latest version when
+// version is empty or "latest".
+func (idx *Index) Find(name, version string) (IndexEntry, bool) {
+	var found IndexEntry
+	ok := false
+	for _, e := range idx.Plugins {
+		if e.Name != name {
+			continue
+		}
+		if version != "" && version != "latest" {
+			if strings.TrimPrefix(e.Version, "v") == strings.TrimPrefix(version, "v") {
+				return e, true
+			}
+			continue
+		}
+		if !ok || compareVersions(e.Version, found.Version) > 0 {
+			found, ok = e, true
+		}
+	}
+	return found, ok
+}
+
+// Latest returns the newest version of every plugin, sorted by name.
+func (idx *Index) Latest() []IndexEntry {
+	latest := make(map[string]IndexEntry)
+	for _, e := range idx.Plugins {
+		if cur, ok := latest[e.Name]; !ok || compareVersions(e.Version, cur.Version) > 0 {
+			latest[e.Name] = e
+		}
+	}
+
+	entries := make([]IndexEntry, 0, len(latest))
+	for _, e := range latest {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b IndexEntry) int { return strings.Compare(a.Name, b.Name) })
+	return entries
+}
+
+// Put adds e to the index, replacing an entry with the same name and version.
+func (idx *Index) Put(e IndexEntry) {
+	for i, cur := range idx.Plugins {
+		if cur.Name == e.Name && cur.Version == e.Version {
+			idx.Plugins[i] = e
+			return
+		}
+	}
+	idx.Plugins = append(idx.Plugins, e)
+}
+
+// PackDir zips the contents of dir with stable ordering and timestamps, so
+// the same tree always produces the same archive and digest. The archive
+// carries a checksum manifest, signed with signer when it is not nil.
+func PackDir(dir string, signer ed25519.PrivateKey) ([]byte, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", dir, err)
+	}
+	slices.Sort(files)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	sums := make(map[string]string, len(files))
+	for _, path := range files {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, fmt.Errorf("relative path %s: %w", path, err)
+		}
+		name := filepath.ToSlash(rel)
+		if name == ChecksumFile || name == SignatureFile {
+			continue
+		}
+
+		data, err := os.ReadFile(path) // #nosec G304 -- walking the plugin source directory
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", rel, err)
+		}
+		if err := writeZipEntry(zw, name, data); err != nil {
+			return nil, err
+		}
+		sums[name] = Digest(data)
+	}
+
+	checksums := formatChecksums(sums)
+	if err := writeZipEntry(zw, ChecksumFile, checksums); err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		sig := base64.StdEncoding.EncodeToString(ed25519.Sign(signer, checksums))
+		if err := writeZipEntry(zw, SignatureFile, []byte(sig+"\n")); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeZipEntry(zw *zip.Writer, name string, data []byte) error {
+	hdr := &zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: time.Unix(0, 0).UTC(),
+	}
+	hdr.SetMode(0644)
+
+	w, err := zw.CreateHeader(hdr)
+	if err != nil {
+		return fmt.Errorf("add %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// Digest returns the hex-encoded SHA-256 of data.
+func Digest(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ArchiveName returns the archive path of a plugin version relative to the
+// index directory. Names and versions that are empty or contain a path
+// separator or ".." are rejected.
+func ArchiveName(name, version string) (string, error) {
+	for _, part := range []string{name, version} {
+		if part == "" || strings.ContainsAny(part, `/\`) || strings.Contains(part, "..") {
+			return "", fmt.Errorf("%w: %q %q", ErrUnsafeArchiveName, name, version)
+		}
+	}
+	return ArchiveDir + "/" + name + "-" + strings.TrimPrefix(version, "v") + ".zip", nil
+}
+
+// compareVersions orders dotted numeric versions, ignoring a leading "v" and
+// falling back to string comparison for non-numeric parts.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
@@ -5,27 +5,78 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // Installer handles plugin installation from marketplace.
 type Installer struct {
-	client     *Client
+	client     Client
 	pluginsDir string
+	trust      Trust
+	limits     Limits
+	lockPath   string
+	now        func() time.Time
+}
+
+// InstallerOption configures an Installer.
+type InstallerOption func(*Installer)
+
+// WithTrustedKeys sets the keys whose signatures are accepted.
+func WithTrustedKeys(keys []TrustedKey) InstallerOption {
+	return func(i *Installer) {
+		i.trust.Keys = keys
+	}
+}
+
+// WithAllowUnsigned installs archives that no trusted key signed. Their
+// checksum manifest is still verified.
+func WithAllowUnsigned(allow bool) InstallerOption {
+	return func(i *Installer) {
+		i.trust.AllowUnsigned = allow
+	}
+}
+
+// WithLimits sets the per-file and total uncompressed size limits.
+func WithLimits(limits Limits) InstallerOption {
+	return func(i *Installer) {
+		i.limits = limits
+	}
+}
+
+// WithLockfile sets where installs are recorded, instead of plugins.lock in
+// the plugins directory.
+func WithLockfile(path string) InstallerOption {
+	return func(i *Installer) {
+		i.lockPath = path
+	}
 }
 
 // NewInstaller creates a new plugin installer.
-func NewInstaller(client *Client, pluginsDir string) *Installer {
-	return &Installer{
+func NewInstaller(client Client, pluginsDir string, opts ...InstallerOption) *Installer {
+	i := &Installer{
 		client:     client,
 		pluginsDir: pluginsDir,
+		limits:     DefaultLimits(),
+		lockPath:   filepath.Join(pluginsDir, LockFile),
+		now:        time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(i)
 	}
+
+	return i
 }
 
-// Install downloads and installs a plugin.
+// Install downloads, verifies and installs a plugin, then records the
+// archive digest in the lockfile. The archive is extracted into a staging
+// directory next to the plugins directory and replaces an existing install
+// only once extraction succeeded.
 func (i *Installer) Install(ctx context.Context, name, version string) error {
 	data, err := i.client.Download(ctx, name, version)
 	if err != nil {
@@ -36,10 +87,32 @@ func (i *Installer) Install(ctx context.Context, name, version string) error {
 		return fmt.Errorf("verify plugin: %w", err)
 	}
 
-	if err := i.Extract(name, data); err != nil {
+	verification, err := VerifyArchive(data, i.trust, i.limits)
+	if err != nil {
+		return fmt.Errorf("verify plugin: %w", err)
+	}
+
+	if err := i.extractAndReplace(name, data); err != nil {
 		return fmt.Errorf("extract plugin: %w", err)
 	}
 
+	installed := i.installedVersion(name)
+	if installed == "" {
+		installed = version
+	}
+
+	err = UpdateLockfile(i.lockPath, func(l *Lockfile) {
+		l.Plugins[name] = LockEntry{
+			Version:     installed,
+			Digest:      "sha256:" + verification.Digest,
+			SignedBy:    verification.SignedBy,
+			InstalledAt: i.now().UTC(),
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("record plugin: %w", err)
+	}
+
 	return nil
 }
 
@@ -56,10 +129,60 @@ func (i *Installer) Verify(data []byte) error {
 	return nil
 }
 
-// Extract extracts plugin archive to plugin directory.
-func (i *Installer) Extract(name string, data []byte) error {
+// extractAndReplace extracts the archive into a staging directory and
+// renames it over the plugin directory. A failed extraction removes only the
+// staging directory, leaving the existing install untouched.
+func (i *Installer) extractAndReplace(name string, data []byte) error {
+	parent := filepath.Dir(filepath.Clean(i.pluginsDir))
+	prefix := "." + filepath.Base(filepath.Clean(i.pluginsDir)) + "-" + name + "-"
+
+	if err := os.MkdirAll(i.pluginsDir, 0750); err != nil {
+		return fmt.Errorf("create plugins directory: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(parent, prefix)
+	if err != nil {
+		return fmt.Errorf("create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	if err := os.Chmod(staging, 0750); err != nil { // #nosec G302 -- same mode as other plugin directories
+		return fmt.Errorf("create staging directory: %w", err)
+	}
+
+	if err := i.extractTo(staging, data); err != nil {
+		return err
+	}
+
 	pluginDir := filepath.Join(i.pluginsDir, name)
+	if _, err := os.Stat(pluginDir); err != nil {
+		if err := os.Rename(staging, pluginDir); err != nil {
+			return fmt.Errorf("move plugin into place: %w", err)
+		}
+		return nil
+	}
 
+	previous := staging + ".old"
+	if err := os.Rename(pluginDir, previous); err != nil {
+		return fmt.Errorf("move previous install aside: %w", err)
+	}
+	if err := os.Rename(staging, pluginDir); err != nil {
+		_ = os.Rename(previous, pluginDir)
+		return fmt.Errorf("move plugin into place: %w", err)
+	}
+	_ = os.RemoveAll(previous)
+
+	return nil
+}
+
+// Extract extracts plugin archive to plugin directory. Files are written
+// without executable bits and the size limits are enforced on the bytes
+// actually decompressed, whatever the archive headers claim.
+func (i *Installer) Extract(name string, data []byte) error {
+	return i.extractTo(filepath.Join(i.pluginsDir, name), data)
+}
+
+func (i *Installer) extractTo(pluginDir string, data []byte) error {
 	if err := os.MkdirAll(pluginDir, 0750); err != nil {
 		return fmt.Errorf("create plugin directory: %w", err)
 	}
@@ -69,6 +192,11 @@ func (i *Installer) Extract(name string, data []byte) error {
 		return fmt.Errorf("create zip reader: %w", err)
 	}
 
+	if err := checkLimits(zipReader.File, i.limits); err != nil {
+		return err
+	}
+
+	var total int64
 	for _, file := range zipReader.File {
 		filePath := filepath.Join(pluginDir, file.Name) // #nosec G305 -- path validated below
 
@@ -78,39 +206,88 @@ func (i *Installer) Extract(name string, data []byte) error {
 		}
 
 		if file.FileInfo().IsDir() {
-			if err := os.MkdirAll(filePath, file.Mode()); err != nil {
+			if err := os.MkdirAll(filePath, 0750); err != nil {
 				return fmt.Errorf("create directory %s: %w", file.Name, err)
 			}
 			continue
 		}
 
+		if file.Name == ChecksumFile || file.Name == SignatureFile {
+			continue
+		}
+
+		if !file.Mode().IsRegular() {
+			return fmt.Errorf("unsupported file type: %s", file.Name)
+		}
+
 		if err := os.MkdirAll(filepath.Dir(filePath), 0750); err != nil {
 			return fmt.Errorf("create parent directory for %s: %w", file.Name, err)
 		}
 
-		fileReader, err := file.Open()
+		n, err := i.extractFile(file, filePath, total)
 		if err != nil {
-			return fmt.Errorf("open file %s: %w", file.Name, err)
+			return err
 		}
+		total += n
+	}
 
-		destFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode()) // #nosec G304 -- controlled file path
-		if err != nil {
-			_ = fileReader.Close()
-			return fmt.Errorf("create file %s: %w", file.Name, err)
-		}
+	return nil
+}
 
-		// #nosec G110 -- archive from trusted source
-		if _, err := io.Copy(destFile, fileReader); err != nil {
-			_ = destFile.Close()
-			_ = fileReader.Close()
-			return fmt.Errorf("copy file %s: %w", file.Name, err)
+func (i *Installer) extractFile(file *zip.File, filePath string, total int64) (int64, error) {
+	limit := i.limits.MaxFileSize
+	if i.limits.MaxTotalSize > 0 {
+		remaining := i.limits.MaxTotalSize - total
+		if remaining <= 0 {
+			// copyLimited treats a non-positive limit as unlimited; allow one
+			// byte so anything left over trips the check below.
+			remaining = 1
+			if file.UncompressedSize64 > 0 {
+				return 0, fmt.Errorf("%w: %d bytes uncompressed (max %d)", ErrArchiveTooLarge, total, i.limits.MaxTotalSize)
+			}
+		}
+		if limit <= 0 || remaining < limit {
+			limit = remaining
 		}
+	}
 
-		_ = destFile.Close()
-		_ = fileReader.Close()
+	fileReader, err := file.Open()
+	if err != nil {
+		return 0, fmt.Errorf("open file %s: %w", file.Name, err)
 	}
+	defer func() { _ = fileReader.Close() }()
 
-	return nil
+	destFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644) // #nosec G302 G304 -- controlled file path, executable bits dropped
+	if err != nil {
+		return 0, fmt.Errorf("create file %s: %w", file.Name, err)
+	}
+	defer func() { _ = destFile.Close() }()
+
+	n, err := copyLimited(destFile, fileReader, limit)
+	if err != nil {
+		return n, fmt.Errorf("copy file %s: %w", file.Name, err)
+	}
+	if i.limits.MaxTotalSize > 0 && total+n > i.limits.MaxTotalSize {
+		return n, fmt.Errorf("%w: more than %d bytes uncompressed", ErrArchiveTooLarge, i.limits.MaxTotalSize)
+	}
+
+	return n, nil
+}
+
+func (i *Installer) installedVersion(name string) string {
+	data, err := os.ReadFile(filepath.Join(i.pluginsDir, name, "plugin.yaml")) // #nosec G304 -- controlled file path
+	if err != nil {
+		return ""
+	}
+
+	var manifest struct {
+		Version string `yaml:"version"`
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return ""
+	}
+
+	return manifest.Version
 }
 
 // Validate checks if installed plugin is valid.
@@ -125,7 +302,7 @@ func (i *Installer) Validate(name string) error {
 	return nil
 }
 
-// Uninstall removes plugin from file system.
+// Uninstall removes plugin from file system and from the lockfile.
 func (i *Installer) Uninstall(name string) error {
 	pluginDir := filepath.Join(i.pluginsDir, name)
 
@@ -133,6 +310,10 @@ func (i *Installer) Uninstall(name string) error {
 		return fmt.Errorf("remove plugin directory: %w", err)
 	}
 
+	if err := UpdateLockfile(i.lockPath, func(l *Lockfile) { delete(l.Plugins, name) }); err != nil {
+		return fmt.Errorf("update lockfile: %w", err)
+	}
+
 	return nil
 }
 
@@ -19,7 +19,7 @@ import (
 func TestNewInstaller_CreatesStruct(t *testing.T) {
 	t.Parallel()
 
-	mockClient := &Client{}
+	mockClient := &HTTPClient{}
 	tempDir := t.TempDir()
 
 	installer := NewInstaller(mockClient, tempDir)
@@ -33,7 +33,7 @@ func TestInstallerVerify_EmptyData(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	err := installer.Verify([]byte{})
 
@@ -45,7 +45,7 @@ func TestInstallerVerify_InvalidZip(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	err := installer.Verify([]byte{0x00, 0x00, 0x00, 0x00})
 
@@ -59,7 +59,7 @@ func TestInstallerVerify_ValidZip(t *testing.T) {
 	zipData := []byte{0x50, 0x4B, 0x03, 0x04, 0x00, 0x00, 0x00, 0x00}
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	err := installer.Verify(zipData)
 
@@ -70,7 +70,7 @@ func TestInstallerExtract_CreatesDirectories(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -100,7 +100,7 @@ func TestInstallerExtract_ExtractsFiles(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -129,7 +129,7 @@ func TestInstallerExtract_UnsafePath(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -153,7 +153,7 @@ func TestInstallerExtract_AbsolutePath(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -176,7 +176,7 @@ func TestInstallerExtract_WithManifest(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -205,7 +205,7 @@ func TestInstallerExtract_NestedDirectories(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -234,7 +234,7 @@ func TestInstallerValidate_ManifestExists(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	pluginDir := filepath.Join(tempDir, "test-plugin")
 	require.NoError(t, os.Mkdir(pluginDir, 0750))
@@ -251,7 +251,7 @@ func TestInstallerValidate_ManifestMissing(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	pluginDir := filepath.Join(tempDir, "test-plugin")
 	require.NoError(t, os.Mkdir(pluginDir, 0750))
@@ -266,7 +266,7 @@ func TestInstallerValidate_PluginNotExists(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	err := installer.Validate("nonexistent")
 
@@ -278,7 +278,7 @@ func TestInstallerUninstall_RemovesDirectory(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	pluginDir := filepath.Join(tempDir, "test-plugin")
 	require.NoError(t, os.Mkdir(pluginDir, 0750))
@@ -297,7 +297,7 @@ func TestInstallerUninstall_NonExistent(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	err := installer.Uninstall("nonexistent")
 
@@ -308,7 +308,7 @@ func TestInstallerListInstalled_ReturnsNames(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	plugin1Dir := filepath.Join(tempDir, "plugin1")
 	require.NoError(t, os.Mkdir(plugin1Dir, 0750))
@@ -330,7 +330,7 @@ func TestInstallerListInstalled_Empty(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	plugins := installer.ListInstalled()
 
@@ -341,7 +341,7 @@ func TestInstallerListInstalled_WithFiles(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	filePath := filepath.Join(tempDir, "file.txt")
 	require.NoError(t, os.WriteFile(filePath, []byte("test"), 0600))
@@ -355,7 +355,7 @@ func TestIsZipArchive_ValidSignature(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	result := installer.isZipArchive([]byte{0x50, 0x4B, 0x03, 0x04})
 
@@ -366,7 +366,7 @@ func TestIsZipArchive_InvalidSignature(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	result := installer.isZipArchive([]byte{0x00, 0x00, 0x00, 0x00})
 
@@ -377,7 +377,7 @@ func TestIsZipArchive_TooShort(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	result := installer.isZipArchive([]byte{0x50, 0x4B})
 
@@ -388,7 +388,7 @@ func TestIsZipArchive_Empty(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	result := installer.isZipArchive([]byte{})
 
@@ -398,18 +398,11 @@ func TestIsZipArchive_Empty(t *testing.T) {
 func TestInstallerInstall_Success(t *testing.T) {
 	t.Parallel()
 
-	var buf bytes.Buffer
-	zipWriter := zip.NewWriter(&buf)
-
-	manifestContent := []byte("name: test-plugin\nversion: 1.0.0\n")
-	writer, err := zipWriter.Create("plugin.yaml")
-	require.NoError(t, err)
-	_, err = writer.Write(manifestContent)
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml"), []byte("name: test-plugin\nversion: 1.0.0\n"), 0600))
+	zipData, err := PackDir(src, nil)
 	require.NoError(t, err)
 
-	require.NoError(t, zipWriter.Close())
-	zipData := buf.Bytes()
-
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "/plugins/test-plugin/versions/1.0.0/download", r.URL.Path)
 		w.WriteHeader(http.StatusOK)
@@ -419,7 +412,7 @@ func TestInstallerInstall_Success(t *testing.T) {
 
 	tempDir := t.TempDir()
 	client := NewClientWithURL(server.URL)
-	installer := NewInstaller(client, tempDir)
+	installer := NewInstaller(client, tempDir, WithAllowUnsigned(true))
 
 	err = installer.Install(context.Background(), "test-plugin", "1.0.0")
 
@@ -472,7 +465,7 @@ func TestInstallerExtract_Overwrite(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	pluginDir := filepath.Join(tempDir, "test-plugin")
 	require.NoError(t, os.Mkdir(pluginDir, 0750))
@@ -506,7 +499,7 @@ func TestInstallerExtract_MultipleFiles(t *testing.T) {
 	t.Parallel()
 
 	tempDir := t.TempDir()
-	installer := NewInstaller(&Client{}, tempDir)
+	installer := NewInstaller(&HTTPClient{}, tempDir)
 
 	var buf bytes.Buffer
 	zipWriter := zip.NewWriter(&buf)
@@ -0,0 +1,83 @@
+package marketplace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LockFile is the name of the lockfile kept in the plugins directory.
+const LockFile = "plugins.lock"
+
+// Lockfile records the exact archive installed for each plugin.
+type Lockfile struct {
+	Version int                  `yaml:"version"`
+	Plugins map[string]LockEntry `yaml:"plugins"`
+}
+
+// LockEntry is one installed plugin.
+type LockEntry struct {
+	Version     string    `yaml:"version"`
+	Digest      string    `yaml:"digest"`
+	SignedBy    string    `yaml:"signed_by,omitempty"`
+	Requires    []string  `yaml:"requires,omitempty"`
+	InstalledAt time.Time `yaml:"installed_at"`
+}
+
+// LoadLockfile reads the lockfile at path; a missing file yields an empty one.
+func LoadLockfile(path string) (*Lockfile, error) {
+	lock := &Lockfile{Version: 1, Plugins: make(map[string]LockEntry)}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- lockfile in the plugins directory
+	if err != nil {
+		if os.IsNotExist(err) {
+			return lock, nil
+		}
+		return nil, fmt.Errorf("read lockfile: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("parse lockfile: %w", err)
+	}
+	if lock.Plugins == nil {
+		lock.Plugins = make(map[string]LockEntry)
+	}
+
+	return lock, nil
+}
+
+// Save writes the lockfile to path atomically.
+func (l *Lockfile) Save(path string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("marshal lockfile: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return fmt.Errorf("create lockfile directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write lockfile: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace lockfile: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateLockfile loads the lockfile at path, applies fn and saves it.
+func UpdateLockfile(path string, fn func(*Lockfile)) error {
+	lock, err := LoadLockfile(path)
+	if err != nil {
+		return err
+	}
+	fn(lock)
+	return lock.Save(path)
+}
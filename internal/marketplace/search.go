@@ -8,11 +8,11 @@ import (
 
 // Searcher provides plugin search functionality.
 type Searcher struct {
-	client *Client
+	client Client
 }
 
 // NewSearcher creates a new plugin searcher.
-func NewSearcher(client *Client) *Searcher {
+func NewSearcher(client Client) *Searcher {
 	return &Searcher{
 		client: client,
 	}
@@ -0,0 +1,363 @@
+package marketplace
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// ChecksumFile lists the SHA-256 of every other file in a plugin archive,
+	// one "<hex>  <path>" line per file as written by sha256sum.
+	ChecksumFile = "CHECKSUMS.sha256"
+	// SignatureFile holds the base64 ed25519 signature of ChecksumFile.
+	SignatureFile = ChecksumFile + ".sig"
+	// TrustedKeysDir is the directory, relative to the project root, holding
+	// PEM public keys whose signatures are accepted on install.
+	TrustedKeysDir = ProjectDir + "/trusted-keys"
+	// DefaultMaxFileSize is the largest uncompressed file accepted (10MB).
+	DefaultMaxFileSize = 10 * 1024 * 1024
+	// DefaultMaxTotalSize is the largest uncompressed archive accepted (50MB).
+	DefaultMaxTotalSize = 50 * 1024 * 1024
+)
+
+var (
+	// ErrUnsigned indicates an archive without a signature.
+	ErrUnsigned = errors.New("plugin archive is not signed")
+	// ErrNoChecksums indicates an archive without a checksum manifest.
+	ErrNoChecksums = errors.New("plugin archive has no " + ChecksumFile)
+	// ErrUntrustedSignature indicates a signature no trusted key verifies.
+	ErrUntrustedSignature = errors.New("plugin signature not made by a trusted key")
+	// ErrChecksumMismatch indicates archive contents that differ from the checksum manifest.
+	ErrChecksumMismatch = errors.New("plugin checksum mismatch")
+	// ErrArchiveTooLarge indicates a file or archive over the extraction limits.
+	ErrArchiveTooLarge = errors.New("plugin archive too large")
+)
+
+// Limits bounds the uncompressed size of an extracted archive.
+type Limits struct {
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+// DefaultLimits returns the limits used when none are configured.
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFileSize:  DefaultMaxFileSize,
+		MaxTotalSize: DefaultMaxTotalSize,
+	}
+}
+
+// Trust is the signature policy VerifyArchive enforces.
+type Trust struct {
+	Keys []TrustedKey
+
+	// AllowUnsigned accepts archives that are unsigned or signed by a key
+	// outside Keys. It is set from the marketplace.allow_unsigned config.
+	AllowUnsigned bool
+}
+
+// TrustedKey is a public key allowed to sign plugin archives. ID is the key
+// file name without extension.
+type TrustedKey struct {
+	ID  string
+	Key ed25519.PublicKey
+}
+
+// Verification is the outcome of VerifyArchive.
+type Verification struct {
+	Digest   string
+	SignedBy string
+	Files    int
+}
+
+// LoadTrustedKeys reads every *.pem and *.pub PEM public key in dir. A
+// missing directory yields no keys.
+func LoadTrustedKeys(dir string) ([]TrustedKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read trusted keys: %w", err)
+	}
+
+	var keys []TrustedKey
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".pem" && ext != ".pub") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name())) // #nosec G304 -- trusted key directory
+		if err != nil {
+			return nil, fmt.Errorf("read trusted key %s: %w", e.Name(), err)
+		}
+
+		key, err := ParsePublicKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %s: %w", e.Name(), err)
+		}
+
+		keys = append(keys, TrustedKey{ID: strings.TrimSuffix(e.Name(), ext), Key: key})
+	}
+
+	return keys, nil
+}
+
+// ParsePublicKey decodes a PEM "PUBLIC KEY" block holding an ed25519 key, as
+// written by `openssl pkey -pubout`.
+func ParsePublicKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+
+	key, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an ed25519 public key")
+	}
+
+	return key, nil
+}
+
+// ParsePrivateKey decodes a PEM "PRIVATE KEY" block holding an ed25519 key,
+// as written by `openssl genpkey -algorithm ed25519`.
+func ParsePrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
+	}
+
+	key, ok := priv.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("not an ed25519 private key")
+	}
+
+	return key, nil
+}
+
+// VerifyArchive checks an archive against its checksum manifest and
+// signature. The manifest is always required. A signature from one of the
+// trusted keys is required too, unless trust allows unsigned archives.
+func VerifyArchive(data []byte, trust Trust, limits Limits) (*Verification, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read archive: %w", err)
+	}
+
+	if err := checkLimits(zr.File, limits); err != nil {
+		return nil, err
+	}
+
+	v := &Verification{Digest: Digest(data)}
+
+	checksums, hasChecksums, err := readEntry(zr, ChecksumFile, limits.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	signature, hasSignature, err := readEntry(zr, SignatureFile, limits.MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if !hasChecksums {
+		return nil, ErrNoChecksums
+	}
+
+	if hasSignature {
+		sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return nil, fmt.Errorf("decode signature: %w", err)
+		}
+		for _, k := range trust.Keys {
+			if ed25519.Verify(k.Key, checksums, sig) {
+				v.SignedBy = k.ID
+				break
+			}
+		}
+		if v.SignedBy == "" && !trust.AllowUnsigned {
+			return nil, untrusted(ErrUntrustedSignature, trust)
+		}
+	} else if !trust.AllowUnsigned {
+		return nil, untrusted(ErrUnsigned, trust)
+	}
+
+	want, err := parseChecksums(checksums)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || f.Name == ChecksumFile || f.Name == SignatureFile {
+			continue
+		}
+
+		expected, ok := want[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s not listed", ErrChecksumMismatch, f.Name)
+		}
+		delete(want, f.Name)
+
+		sum, err := hashEntry(f, limits.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		if sum != expected {
+			return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, f.Name)
+		}
+		v.Files++
+	}
+
+	if len(want) > 0 {
+		missing := make([]string, 0, len(want))
+		for name := range want {
+			missing = append(missing, name)
+		}
+		slices.Sort(missing)
+		return nil, fmt.Errorf("%w: missing %s", ErrChecksumMismatch, strings.Join(missing, ", "))
+	}
+
+	return v, nil
+}
+
+// untrusted explains a signature failure, pointing at the missing keys when
+// none are configured.
+func untrusted(err error, trust Trust) error {
+	if len(trust.Keys) == 0 {
+		return fmt.Errorf("%w: no keys in %s (set marketplace.allow_unsigned to skip signature checks)", err, TrustedKeysDir)
+	}
+	return err
+}
+
+// checkLimits rejects archives whose declared sizes exceed limits. Extract
+// enforces the same limits on the bytes actually written.
+func checkLimits(files []*zip.File, limits Limits) error {
+	var total uint64
+	for _, f := range files {
+		if limits.MaxFileSize > 0 && f.UncompressedSize64 > uint64(limits.MaxFileSize) {
+			return fmt.Errorf("%w: %s is %d bytes (max %d)", ErrArchiveTooLarge, f.Name, f.UncompressedSize64, limits.MaxFileSize)
+		}
+		total += f.UncompressedSize64
+	}
+	if limits.MaxTotalSize > 0 && total > uint64(limits.MaxTotalSize) {
+		return fmt.Errorf("%w: %d bytes uncompressed (max %d)", ErrArchiveTooLarge, total, limits.MaxTotalSize)
+	}
+	return nil
+}
+
+func readEntry(zr *zip.Reader, name string, limit int64) ([]byte, bool, error) {
+	for _, f := range zr.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, true, fmt.Errorf("open %s: %w", name, err)
+		}
+		defer func() { _ = rc.Close() }()
+
+		data, err := readLimited(rc, limit)
+		if err != nil {
+			return nil, true, fmt.Errorf("read %s: %w", name, err)
+		}
+		return data, true, nil
+	}
+	return nil, false, nil
+}
+
+func hashEntry(f *zip.File, limit int64) (string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return "", fmt.Errorf("open %s: %w", f.Name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	h := sha256.New()
+	if _, err := copyLimited(h, rc, limit); err != nil {
+		return "", fmt.Errorf("hash %s: %w", f.Name, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := copyLimited(&buf, r, limit); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// copyLimited copies at most limit bytes, failing when more are available.
+// A non-positive limit copies everything.
+func copyLimited(dst io.Writer, src io.Reader, limit int64) (int64, error) {
+	if limit <= 0 {
+		return io.Copy(dst, src)
+	}
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err != nil {
+		return n, err
+	}
+	if n > limit {
+		return n, fmt.Errorf("%w: more than %d bytes", ErrArchiveTooLarge, limit)
+	}
+	return n, nil
+}
+
+func parseChecksums(data []byte) (map[string]string, error) {
+	sums := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		sum, name, ok := strings.Cut(text, "  ")
+		if !ok || len(sum) != sha256.Size*2 {
+			return nil, fmt.Errorf("%s line %d: expected \"<sha256>  <path>\"", ChecksumFile, line)
+		}
+		sums[strings.TrimPrefix(name, "*")] = strings.ToLower(sum)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan %s: %w", ChecksumFile, err)
+	}
+	return sums, nil
+}
+
+// formatChecksums renders sums sorted by path in sha256sum format.
+func formatChecksums(sums map[string]string) []byte {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var b bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&b, "%s  %s\n", sums[name], name)
+	}
+	return b.Bytes()
+}
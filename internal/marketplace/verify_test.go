@@ -0,0 +1,316 @@
+package marketplace
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticClient struct {
+	Client
+	data []byte
+}
+
+func (c staticClient) Download(context.Context, string, string) ([]byte, error) {
+	return c.data, nil
+}
+
+func packTestPlugin(t *testing.T, signer ed25519.PrivateKey) []byte {
+	t.Helper()
+
+	src := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(src, "plugin.yaml"), []byte("name: demo\nversion: 1.2.0\n"), 0600))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "skills"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "skills", "SKILL.md"), []byte("# Demo\n"), 0600))
+
+	data, err := PackDir(src, signer)
+	require.NoError(t, err)
+	return data
+}
+
+func newKey(t *testing.T, id string) (TrustedKey, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	return TrustedKey{ID: id, Key: pub}, priv
+}
+
+// rewriteZip copies an archive, letting edit replace the content of entries.
+func rewriteZip(t *testing.T, data []byte, edit func(name string, content []byte) []byte) []byte {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		var content bytes.Buffer
+		_, err = content.ReadFrom(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+
+		w, err := zw.CreateHeader(&f.FileHeader)
+		require.NoError(t, err)
+		_, err = w.Write(edit(f.Name, content.Bytes()))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestVerifyArchive(t *testing.T) {
+	t.Parallel()
+
+	trusted, signer := newKey(t, "release")
+	other, otherSigner := newKey(t, "other")
+
+	signed := packTestPlugin(t, signer)
+	unsigned := packTestPlugin(t, nil)
+	tampered := rewriteZip(t, signed, func(name string, content []byte) []byte {
+		if name == "skills/SKILL.md" {
+			return []byte("# Evil\n")
+		}
+		return content
+	})
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("plugin.yaml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("name: demo\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	noChecksums := buf.Bytes()
+
+	keys := []TrustedKey{trusted}
+	tests := []struct {
+		name     string
+		data     []byte
+		trust    Trust
+		signedBy string
+		files    int
+		wantErr  error
+	}{
+		{name: "signed by trusted key", data: signed, trust: Trust{Keys: []TrustedKey{other, trusted}}, signedBy: "release", files: 2},
+		{name: "unsigned without keys", data: unsigned, wantErr: ErrUnsigned},
+		{name: "unsigned with keys", data: unsigned, trust: Trust{Keys: keys}, wantErr: ErrUnsigned},
+		{name: "unsigned allowed", data: unsigned, trust: Trust{AllowUnsigned: true}, files: 2},
+		{name: "signed by unknown key", data: packTestPlugin(t, otherSigner), trust: Trust{Keys: keys}, wantErr: ErrUntrustedSignature},
+		{name: "unknown key allowed", data: packTestPlugin(t, otherSigner), trust: Trust{Keys: keys, AllowUnsigned: true}, files: 2},
+		{name: "signed without keys", data: signed, wantErr: ErrUntrustedSignature},
+		{name: "tampered file", data: tampered, trust: Trust{Keys: keys}, wantErr: ErrChecksumMismatch},
+		{name: "tampered file allowed unsigned", data: tampered, trust: Trust{AllowUnsigned: true}, wantErr: ErrChecksumMismatch},
+		{name: "no checksum manifest", data: noChecksums, trust: Trust{AllowUnsigned: true}, wantErr: ErrNoChecksums},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			v, err := VerifyArchive(tt.data, tt.trust, DefaultLimits())
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.signedBy, v.SignedBy)
+			assert.Equal(t, Digest(tt.data), v.Digest)
+			assert.Equal(t, tt.files, v.Files)
+		})
+	}
+}
+
+func TestVerifyArchive_Limits(t *testing.T) {
+	t.Parallel()
+
+	data := packTestPlugin(t, nil)
+
+	_, err := VerifyArchive(data, Trust{AllowUnsigned: true}, Limits{MaxFileSize: 8})
+	require.ErrorIs(t, err, ErrArchiveTooLarge)
+
+	_, err = VerifyArchive(data, Trust{AllowUnsigned: true}, Limits{MaxTotalSize: 32})
+	require.ErrorIs(t, err, ErrArchiveTooLarge)
+}
+
+func TestInstallerExtract_DropsExecutableBits(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	hdr := &zip.FileHeader{Name: "bin/run.sh"}
+	hdr.SetMode(0755)
+	w, err := zw.CreateHeader(hdr)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("#!/bin/sh\n"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	tempDir := t.TempDir()
+	installer := NewInstaller(&HTTPClient{}, tempDir)
+	require.NoError(t, installer.Extract("demo", buf.Bytes()))
+
+	info, err := os.Stat(filepath.Join(tempDir, "demo", "bin", "run.sh"))
+	require.NoError(t, err)
+	assert.Zero(t, info.Mode().Perm()&0111)
+}
+
+func TestInstallerExtract_EnforcesActualSize(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("big.txt")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(strings.Repeat("x", 1024)))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	tempDir := t.TempDir()
+	installer := NewInstaller(&HTTPClient{}, tempDir, WithLimits(Limits{MaxTotalSize: 100}))
+	err = installer.Extract("demo", buf.Bytes())
+	require.ErrorIs(t, err, ErrArchiveTooLarge)
+}
+
+func TestInstaller_Install_RecordsLockfile(t *testing.T) {
+	t.Parallel()
+
+	trusted, signer := newKey(t, "release")
+	data := packTestPlugin(t, signer)
+
+	tempDir := t.TempDir()
+	installer := NewInstaller(staticClient{data: data}, tempDir, WithTrustedKeys([]TrustedKey{trusted}))
+	require.NoError(t, installer.Install(context.Background(), "demo", "latest"))
+
+	assert.FileExists(t, filepath.Join(tempDir, "demo", "skills", "SKILL.md"))
+	assert.NoFileExists(t, filepath.Join(tempDir, "demo", ChecksumFile))
+
+	lock, err := LoadLockfile(filepath.Join(tempDir, LockFile))
+	require.NoError(t, err)
+	entry, ok := lock.Plugins["demo"]
+	require.True(t, ok)
+	assert.Equal(t, "1.2.0", entry.Version)
+	assert.Equal(t, "sha256:"+Digest(data), entry.Digest)
+	assert.Equal(t, "release", entry.SignedBy)
+	assert.False(t, entry.InstalledAt.IsZero())
+
+	require.NoError(t, installer.Uninstall("demo"))
+	lock, err = LoadLockfile(filepath.Join(tempDir, LockFile))
+	require.NoError(t, err)
+	assert.Empty(t, lock.Plugins)
+}
+
+func TestInstaller_Install_RejectsUnsigned(t *testing.T) {
+	t.Parallel()
+
+	trusted, _ := newKey(t, "release")
+	tempDir := t.TempDir()
+	installer := NewInstaller(staticClient{data: packTestPlugin(t, nil)}, tempDir, WithTrustedKeys([]TrustedKey{trusted}))
+
+	err := installer.Install(context.Background(), "demo", "latest")
+	require.ErrorIs(t, err, ErrUnsigned)
+	assert.NoDirExists(t, filepath.Join(tempDir, "demo"))
+	assert.NoFileExists(t, filepath.Join(tempDir, LockFile))
+}
+
+func TestInstaller_Install_KeepsPreviousOnFailure(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	pluginsDir := filepath.Join(tempDir, "plugins")
+	installer := NewInstaller(staticClient{data: packTestPlugin(t, nil)}, pluginsDir, WithAllowUnsigned(true))
+	require.NoError(t, installer.Install(context.Background(), "demo", "latest"))
+
+	// "skills" as a file and as a directory cannot both be extracted.
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string][]byte{"skills": []byte("file\n"), "skills/SKILL.md": []byte("# Broken\n")}
+	sums := make(map[string]string)
+	for _, name := range []string{"skills", "skills/SKILL.md"} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write(files[name])
+		require.NoError(t, err)
+		sums[name] = Digest(files[name])
+	}
+	w, err := zw.Create(ChecksumFile)
+	require.NoError(t, err)
+	_, err = w.Write(formatChecksums(sums))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	broken := NewInstaller(staticClient{data: buf.Bytes()}, pluginsDir, WithAllowUnsigned(true))
+	err = broken.Install(context.Background(), "demo", "2.0.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "extract plugin")
+
+	content, err := os.ReadFile(filepath.Join(pluginsDir, "demo", "skills", "SKILL.md")) //nolint:gosec // test path
+	require.NoError(t, err)
+	assert.Equal(t, "# Demo\n", string(content), "the previous install is untouched")
+
+	entries, err := os.ReadDir(tempDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the staging directory is removed")
+	assert.Equal(t, "plugins", entries[0].Name())
+
+	lock, err := LoadLockfile(filepath.Join(pluginsDir, LockFile))
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", lock.Plugins["demo"].Version)
+}
+
+func TestInstaller_Install_ReplacesPrevious(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	installer := NewInstaller(staticClient{data: packTestPlugin(t, nil)}, tempDir, WithAllowUnsigned(true))
+	require.NoError(t, installer.Install(context.Background(), "demo", "latest"))
+	stale := filepath.Join(tempDir, "demo", "stale.md")
+	require.NoError(t, os.WriteFile(stale, []byte("old\n"), 0600))
+
+	require.NoError(t, installer.Install(context.Background(), "demo", "latest"))
+	assert.NoFileExists(t, stale, "files of the previous install are dropped")
+	assert.FileExists(t, filepath.Join(tempDir, "demo", "skills", "SKILL.md"))
+}
+
+func TestLoadTrustedKeys(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "release.pem"), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("ignored"), 0600))
+
+	keys, err := LoadTrustedKeys(dir)
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+	assert.Equal(t, "release", keys[0].ID)
+	assert.True(t, pub.Equal(keys[0].Key))
+
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	parsed, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+	require.NoError(t, err)
+	assert.True(t, priv.Equal(parsed))
+
+	missing, err := LoadTrustedKeys(filepath.Join(dir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -10,16 +11,41 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/agent"
 	"github.com/victorzhuk/go-ent/internal/agent/background"
+	"github.com/victorzhuk/go-ent/internal/breaker"
 	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/cost"
 	"github.com/victorzhuk/go-ent/internal/marketplace"
 	"github.com/victorzhuk/go-ent/internal/mcp/tools"
 	"github.com/victorzhuk/go-ent/internal/metrics"
 	"github.com/victorzhuk/go-ent/internal/plugin"
+	"github.com/victorzhuk/go-ent/internal/reload"
 	"github.com/victorzhuk/go-ent/internal/skill"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 	"github.com/victorzhuk/go-ent/internal/version"
 	"github.com/victorzhuk/go-ent/internal/worker"
 )
 
+// shutdownFuncs release resources started by NewWithSkillsPath. They run in
+// reverse registration order, like deferred calls.
+var shutdownFuncs []func(context.Context) error
+
+// Shutdown stops the metrics listener, drains the metrics collector and
+// flushes pending traces.
+func Shutdown(ctx context.Context) error {
+	var errs []error
+	for i := len(shutdownFuncs) - 1; i >= 0; i-- {
+		if err := shutdownFuncs[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	shutdownFuncs = nil
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
 func New() *mcp.Server {
 	return NewWithSkillsPath("")
 }
@@ -33,7 +59,9 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 		nil,
 	)
 
-	cfg, err := config.Load(".")
+	projectRoot := resolveProjectRoot()
+
+	cfg, err := config.Load(projectRoot)
 	if err != nil {
 		slog.Warn("failed to load config, using defaults", "error", err)
 		cfg = config.DefaultConfig()
@@ -44,21 +72,95 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 		slog.Info("metrics collection disabled by configuration")
 	}
 
-	metricsStore, err := metrics.NewStore("data/metrics.json", 7*24*time.Hour)
+	var metricsStore metrics.Backend
+	metricsPath := metrics.DurablePath(projectRoot)
+	durableStore, err := metrics.OpenDurableStore(metricsPath, metricsRetention(cfg.Metrics))
 	if err != nil {
-		slog.Warn("failed to initialize metrics store", "error", err)
-		tools.InitMetricsStore(nil)
+		slog.Warn("failed to initialize metrics store", "path", metricsPath, "error", err)
 	} else {
+		legacyPath := filepath.Join(projectRoot, metrics.LegacyFile)
+		if n, err := durableStore.ImportJSON(legacyPath); err != nil {
+			slog.Warn("failed to import legacy metrics", "path", legacyPath, "error", err)
+		} else if n > 0 {
+			slog.Info("imported legacy metrics", "path", legacyPath, "count", n)
+		}
+
+		compactCtx, stopCompaction := context.WithCancel(context.Background())
+		go durableStore.RunCompaction(compactCtx, 24*time.Hour)
+		shutdownFuncs = append(shutdownFuncs, func(context.Context) error {
+			stopCompaction()
+			return durableStore.Close()
+		})
+
 		slog.Info("metrics system initialized",
 			"enabled", true,
-			"storage", "file",
-			"storage_path", "data/metrics.json",
-			"retention_days", 7,
-			"max_entries", 1000,
+			"storage", "bbolt",
+			"storage_path", metricsPath,
+			"raw_retention", durableStore.Retention().Raw,
+			"hourly_retention", durableStore.Retention().Hourly,
+			"daily_retention", durableStore.Retention().Daily,
 		)
-		tools.InitMetricsStore(metricsStore)
+		metricsStore = durableStore
+	}
+	tools.InitMetricsStore(metricsStore)
+
+	var costLedger *cost.Ledger
+	if cfg.Budget.Tracking {
+		costLedger, err = cost.OpenLedger(cost.LedgerPath(projectRoot))
+		if err != nil {
+			slog.Warn("failed to open cost ledger", "error", err)
+		} else {
+			tools.InitCostLedger(costLedger)
+		}
 	}
 
+	liveMetrics := metrics.NewLive()
+	liveMetrics.SetBudget(cfg.Budget.Daily, cfg.Budget.Monthly)
+	if costLedger != nil {
+		// The budget gauge starts from the spend already persisted this month
+		entries, err := costLedger.Entries(cost.MonthStart(time.Now()))
+		if err != nil {
+			slog.Warn("failed to read cost ledger", "path", costLedger.Path(), "error", err)
+		}
+		for _, e := range entries {
+			liveMetrics.SeedSpend(e.Timestamp, e.Cost)
+		}
+	}
+	collector := metrics.NewLiveCollector(metricsStore, liveMetrics)
+	tools.InitMetricsCollector(collector)
+	shutdownFuncs = append(shutdownFuncs, collector.Shutdown)
+
+	if cfg.Metrics.Listen != "" {
+		srv, addr, err := liveMetrics.Listen(cfg.Metrics.Listen)
+		if err != nil {
+			slog.Warn("failed to start metrics listener", "listen", cfg.Metrics.Listen, "error", err)
+		} else {
+			slog.Info("metrics listener started", "addr", addr.String(), "path", "/metrics")
+			shutdownFuncs = append(shutdownFuncs, srv.Shutdown)
+		}
+	}
+
+	if cfg.Tracing.Enabled {
+		traceFile := cfg.Tracing.FilePath(projectRoot)
+		_, err := tracing.Setup(tracing.Config{
+			Exporter:    cfg.Tracing.Exporter,
+			Endpoint:    cfg.Tracing.Endpoint,
+			Headers:     cfg.Tracing.Headers,
+			File:        traceFile,
+			ServiceName: cfg.Tracing.ServiceName,
+		})
+		if err != nil {
+			slog.Warn("failed to initialize tracing", "error", err)
+		} else {
+			slog.Info("tracing initialized",
+				"exporter", cfg.Tracing.Exporter,
+				"endpoint", cfg.Tracing.Endpoint,
+				"file", traceFile,
+			)
+		}
+	}
+	s.AddReceivingMiddleware(tools.TracingMiddleware(), tools.MetricsMiddleware())
+
 	// Initialize skill registry
 	registry := skill.NewRegistry()
 	if skillsPath == "" {
@@ -73,6 +175,10 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 		}
 	}
 
+	if cfg.Metrics.Enabled {
+		registry.SetMatchObserver(collector.RecordSkillMatch)
+	}
+
 	if err := registry.Load(skillsPath); err != nil {
 		slog.Warn("failed to load skills", "path", skillsPath, "error", err)
 	} else {
@@ -90,10 +196,25 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 		pluginsDir = filepath.Join(exeDir, "..", "plugins")
 	}
 
-	marketplaceClient := marketplace.NewClient()
+	syncCtx, cancelSync := context.WithTimeout(context.Background(), marketplace.SyncTimeout)
+	marketplaceClient, err := marketplace.NewFromConfig(syncCtx, cfg.Marketplace, projectRoot)
+	cancelSync()
+	if err != nil {
+		slog.Warn("failed to set up marketplace, using default", "backend", cfg.Marketplace.Backend, "error", err)
+		marketplaceClient = marketplace.NewClient()
+	}
 	marketplaceSearcher := marketplace.NewSearcher(marketplaceClient)
 	registryWrapper := &skillRegistryWrapper{registry: registry, agentRegistry: agentRegistry}
 	pluginManager := plugin.NewManager(pluginsDir, registryWrapper, marketplaceClient, nil)
+	trustedKeys, err := marketplace.LoadTrustedKeys(filepath.Join(projectRoot, marketplace.TrustedKeysDir))
+	if err != nil {
+		slog.Warn("failed to load trusted plugin keys", "error", err)
+	}
+	pluginManager.SetTrustedKeys(trustedKeys)
+	pluginManager.SetAllowUnsigned(cfg.Marketplace.AllowUnsigned)
+	if cfg.Marketplace.AllowUnsigned {
+		slog.Warn("plugin signature checks disabled by marketplace.allow_unsigned")
+	}
 
 	if err := pluginManager.Initialize(context.TODO()); err != nil {
 		slog.Warn("failed to initialize plugin manager", "error", err)
@@ -105,9 +226,24 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 	slog.Info("background agent manager initialized", "default_role", background.DefaultConfig().DefaultRole, "default_model", background.DefaultConfig().DefaultModel)
 
 	workerManager := worker.NewWorkerManagerWithoutTracking()
+	liveMetrics.WatchWorkers(func() map[string]int {
+		counts := make(map[string]int)
+		for _, w := range workerManager.List() {
+			counts[string(w.Status)]++
+		}
+		return counts
+	})
+	workerManager.RegisterHook(worker.HookPostComplete, func(ctx context.Context, hookCtx *worker.HookContext) error {
+		if hookCtx.Worker != nil && hookCtx.Result != nil {
+			collector.RecordCost(hookCtx.Worker.Provider, hookCtx.Worker.Model,
+				hookCtx.Result.TokensIn, hookCtx.Result.TokensOut, hookCtx.Result.Cost)
+			recordWorkerSpend(costLedger, hookCtx)
+		}
+		return nil
+	}, nil)
 	slog.Info("worker manager initialized")
 
-	providerConfig, err := config.LoadProviders(".")
+	providerConfig, err := config.LoadProviders(projectRoot)
 	if err != nil {
 		slog.Warn("failed to load providers config, using defaults", "error", err)
 		providerConfig = config.DefaultProvidersConfig()
@@ -115,11 +251,41 @@ func NewWithSkillsPath(skillsPath string) *mcp.Server {
 		slog.Info("providers config loaded", "providers", len(providerConfig.Providers))
 	}
 
+	if providerConfig.Health != nil {
+		breaker.SetDefault(breaker.NewRegistry(breaker.Config{
+			FailureThreshold: providerConfig.Health.FailureThreshold,
+			OpenTimeout:      time.Duration(providerConfig.Health.OpenTimeout) * time.Second,
+		}))
+	}
+
 	tools.Register(s, registry, pluginManager, marketplaceSearcher, backgroundManager, workerManager, providerConfig)
 
+	reloader := reload.NewReloader(registry, skillsPath, pluginManager, pluginsDir, nil)
+	reloader.OnReload(func(report reload.Report) {
+		tools.PublishReload(context.Background(), s, report)
+	})
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	go reloader.Watch(watchCtx, reload.DefaultInterval)
+	shutdownFuncs = append(shutdownFuncs, func(context.Context) error {
+		stopWatch()
+		return nil
+	})
+	slog.Info("hot reload enabled", "skills_path", skillsPath, "plugins_dir", pluginsDir, "interval", reload.DefaultInterval)
+
 	return s
 }
 
+// resolveProjectRoot returns the absolute project directory the server was
+// started in; project-relative paths from the config resolve against it.
+func resolveProjectRoot() string {
+	root, err := os.Getwd()
+	if err != nil {
+		slog.Warn("failed to resolve project root, using relative paths", "error", err)
+		return "."
+	}
+	return root
+}
+
 type skillRegistryWrapper struct {
 	registry      *skill.Registry
 	agentRegistry *agent.Registry
@@ -140,3 +306,55 @@ func (w *skillRegistryWrapper) UnregisterSkill(name string) error {
 func (w *skillRegistryWrapper) UnregisterAgent(name string) error {
 	return w.agentRegistry.UnregisterAgent(name)
 }
+
+func (w *skillRegistryWrapper) ReplaceSkill(name, path string) error {
+	return w.registry.ReplaceSkill(name, path)
+}
+
+func (w *skillRegistryWrapper) ReplaceAgent(name, path string) error {
+	return w.agentRegistry.ReplaceAgent(name, path)
+}
+
+// metricsRetention converts the configured retention days; zero keeps the
+// store default.
+func metricsRetention(cfg config.MetricsConfig) metrics.Retention {
+	day := 24 * time.Hour
+	return metrics.Retention{
+		Raw:    time.Duration(cfg.RawRetentionDays) * day,
+		Hourly: time.Duration(cfg.HourlyRetentionDays) * day,
+		Daily:  time.Duration(cfg.DailyRetentionDays) * day,
+	}
+}
+
+// recordWorkerSpend appends a finished worker's spend to the cost ledger,
+// attributing it to the change and agent role of the worker's task.
+func recordWorkerSpend(ledger *cost.Ledger, hookCtx *worker.HookContext) {
+	if ledger == nil {
+		return
+	}
+
+	e := cost.Entry{
+		Source:    cost.SourceWorker,
+		Provider:  hookCtx.Worker.Provider,
+		Model:     hookCtx.Worker.Model,
+		TaskID:    hookCtx.Worker.ID,
+		TokensIn:  hookCtx.Result.TokensIn,
+		TokensOut: hookCtx.Result.TokensOut,
+		Cost:      hookCtx.Result.Cost,
+	}
+
+	task := hookCtx.Task
+	if task == nil {
+		task = hookCtx.Worker.Task
+	}
+	if task != nil {
+		e.AgentRole = string(task.ForceAgent)
+		if changeID, ok := task.Metadata["change_id"].(string); ok {
+			e.ChangeID = changeID
+		}
+	}
+
+	if err := ledger.Record(e); err != nil {
+		slog.Warn("failed to record worker spend", "worker_id", hookCtx.Worker.ID, "error", err)
+	}
+}
@@ -77,8 +77,7 @@ func registerAgentExecute(s *mcp.Server, registry *skill.Registry) {
 	}
 
 	baseHandler := makeAgentExecuteHandler(registry)
-	handler := WithMetrics[AgentExecuteInput, any]("agent_execute", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func makeAgentExecuteHandler(registry *skill.Registry) func(context.Context, *mcp.CallToolRequest, AgentExecuteInput) (*mcp.CallToolResult, any, error) {
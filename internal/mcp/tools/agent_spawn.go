@@ -55,8 +55,7 @@ func registerAgentSpawn(s *mcp.Server, manager *background.Manager) {
 	}
 
 	baseHandler := makeAgentSpawnHandler(manager)
-	handler := WithMetrics[AgentSpawnInput, any]("go_ent_agent_spawn", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func makeAgentSpawnHandler(manager *background.Manager) func(context.Context, *mcp.CallToolRequest, AgentSpawnInput) (*mcp.CallToolResult, any, error) {
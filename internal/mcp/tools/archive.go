@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,10 +12,24 @@ import (
 )
 
 type ArchiveInput struct {
-	Path      string `json:"path"`
-	ID        string `json:"id"`
-	SkipSpecs bool   `json:"skip_specs,omitempty"`
-	DryRun    bool   `json:"dry_run,omitempty"`
+	Path          string `json:"path"`
+	ID            string `json:"id"`
+	SkipSpecs     bool   `json:"skip_specs,omitempty"`
+	DryRun        bool   `json:"dry_run,omitempty"`
+	AllowUnmerged bool   `json:"allow_unmerged,omitempty"`
+}
+
+type SpecHistoryInput struct {
+	Path        string `json:"path"`
+	Spec        string `json:"spec"`
+	Requirement string `json:"requirement,omitempty"`
+	Format      string `json:"format,omitempty"`
+}
+
+type UnarchiveInput struct {
+	Path   string `json:"path"`
+	ID     string `json:"id"`
+	DryRun bool   `json:"dry_run,omitempty"`
 }
 
 func registerArchive(s *mcp.Server) {
@@ -41,12 +57,48 @@ func registerArchive(s *mcp.Server) {
 					"description": "Preview changes without actually archiving",
 					"default":     false,
 				},
+				"allow_unmerged": map[string]any{
+					"type":        "boolean",
+					"description": "Archive even though the change's git branch has commits its base branch lacks",
+					"default":     false,
+				},
 			},
 			"required": []string{"path", "id"},
 		},
 	}
 
 	mcp.AddTool(s, tool, archiveHandler)
+
+	historyTool := &mcp.Tool{
+		Name:        "spec_history",
+		Description: "List the archived changes that edited a spec, oldest first, with the requirements each added, modified, removed or renamed. Filter to one requirement to follow it across renames.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":        map[string]any{"type": "string", "description": "Path to the project directory containing openspec folder"},
+				"spec":        map[string]any{"type": "string", "description": "Spec (capability) name"},
+				"requirement": map[string]any{"type": "string", "description": "Only changes that shaped this requirement, by its current name"},
+				"format":      map[string]any{"type": "string", "description": "Output format", "enum": []string{"text", "json"}, "default": "text"},
+			},
+			"required": []string{"path", "spec"},
+		},
+	}
+	mcp.AddTool(s, historyTool, specHistoryHandler)
+
+	unarchiveTool := &mcp.Tool{
+		Name:        "spec_unarchive",
+		Description: "Revert the spec edits of an archived change and move it back to changes/. Refuses when later archived changes or hand edits touched the same requirements. Use dry_run to preview.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":    map[string]any{"type": "string", "description": "Path to the project directory containing openspec folder"},
+				"id":      map[string]any{"type": "string", "description": "ID of the archived change"},
+				"dry_run": map[string]any{"type": "boolean", "description": "Preview without reverting", "default": false},
+			},
+			"required": []string{"path", "id"},
+		},
+	}
+	mcp.AddTool(s, unarchiveTool, unarchiveHandler)
 }
 
 func archiveHandler(ctx context.Context, req *mcp.CallToolRequest, input ArchiveInput) (*mcp.CallToolResult, any, error) {
@@ -96,7 +148,12 @@ func archiveHandler(ctx context.Context, req *mcp.CallToolRequest, input Archive
 	}
 
 	// Perform archive
-	result, err := archiver.Archive(input.ID, input.SkipSpecs, input.DryRun)
+	result, err := archiver.Archive(input.ID, input.SkipSpecs, input.DryRun, input.AllowUnmerged)
+	if errors.Is(err, spec.ErrBranchNotMerged) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("❌ Cannot archive '%s' - %v\n\nMerge the branch first, or pass allow_unmerged to archive anyway.", input.ID, err)}},
+		}, nil, nil
+	}
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error archiving: %v", err)}},
@@ -145,3 +202,121 @@ func formatArchiveResult(result *spec.ArchiveResult) string {
 
 	return sb.String()
 }
+
+func specHistoryHandler(ctx context.Context, req *mcp.CallToolRequest, input SpecHistoryInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if input.Spec == "" {
+		return nil, nil, fmt.Errorf("spec is required")
+	}
+
+	store := spec.NewStore(input.Path)
+
+	exists, err := store.Exists()
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error checking spec folder: %v", err)}},
+		}, nil, nil
+	}
+
+	if !exists {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No openspec folder found. Run spec_init first."}},
+		}, nil, nil
+	}
+
+	entries, err := spec.NewArchiver(store).History(input.Spec, input.Requirement)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error reading history: %v", err)}},
+		}, nil, nil
+	}
+
+	var text string
+	switch input.Format {
+	case "", "text":
+		text = spec.FormatSpecHistory(input.Spec, entries)
+	case "json":
+		data, _ := json.MarshalIndent(entries, "", "  ")
+		text = string(data)
+	default:
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown format: %s. Use text or json.", input.Format)}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
+
+func unarchiveHandler(ctx context.Context, req *mcp.CallToolRequest, input UnarchiveInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if input.ID == "" {
+		return nil, nil, fmt.Errorf("id is required")
+	}
+
+	store := spec.NewStore(input.Path)
+
+	exists, err := store.Exists()
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error checking spec folder: %v", err)}},
+		}, nil, nil
+	}
+
+	if !exists {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No openspec folder found. Run spec_init first."}},
+		}, nil, nil
+	}
+
+	result, err := spec.NewArchiver(store).Unarchive(input.ID, input.DryRun)
+	if err != nil && !errors.Is(err, spec.ErrUnarchiveConflict) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error unarchiving: %v", err)}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatUnarchiveResult(result)}},
+	}, nil, nil
+}
+
+func formatUnarchiveResult(result *spec.UnarchiveResult) string {
+	var sb strings.Builder
+
+	if len(result.Conflicts) > 0 {
+		sb.WriteString(fmt.Sprintf("❌ Cannot unarchive '%s' - %d conflict(s):\n", result.ChangeID, len(result.Conflicts)))
+		for _, c := range result.Conflicts {
+			sb.WriteString(fmt.Sprintf("  - %s\n", c))
+		}
+		sb.WriteString("\nUnarchive the later changes first, or revert the edits by hand.")
+		return sb.String()
+	}
+
+	if result.DryRun {
+		sb.WriteString("🔍 DRY RUN - No changes made\n\n")
+		sb.WriteString(fmt.Sprintf("Would restore '%s' to:\n  %s\n\n", result.ChangeID, result.ChangePath))
+	} else {
+		sb.WriteString(fmt.Sprintf("✅ Restored '%s' to:\n  %s\n\n", result.ChangeID, result.ChangePath))
+	}
+
+	if len(result.RevertedSpecs) > 0 {
+		if result.DryRun {
+			sb.WriteString("Would revert specs:\n")
+		} else {
+			sb.WriteString("Reverted specs:\n")
+		}
+		for _, name := range result.RevertedSpecs {
+			sb.WriteString(fmt.Sprintf("  - %s\n", name))
+		}
+	} else {
+		sb.WriteString("No spec edits to revert\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package tools
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+func TestSpecHistoryAndUnarchiveHandlers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	deltaDir := filepath.Join(root, "openspec", "changes", "add-2fa", "specs", "auth")
+	require.NoError(t, os.MkdirAll(deltaDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(deltaDir, "spec.md"), []byte("## ADDED Requirements\n\n### Requirement: Two-factor authentication\n\n#### Scenario: Enable\n- WHEN user enables 2FA\n- THEN login asks for a code\n"), 0o600))
+	specPath := filepath.Join(root, "openspec", "specs", "auth", "spec.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(specPath), 0o750))
+	base := "# Auth\n\n### Requirement: User login\n\n#### Scenario: Valid\n- WHEN valid\n- THEN ok\n"
+	require.NoError(t, os.WriteFile(specPath, []byte(base), 0o600))
+
+	_, err := spec.NewArchiver(spec.NewStore(root)).Archive("add-2fa", false, false, false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := specHistoryHandler(ctx, &mcp.CallToolRequest{}, SpecHistoryInput{Path: root, Spec: "auth"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "History of auth (1 archived change(s))")
+	assert.Contains(t, text(result), "add-2fa\n  + Two-factor authentication")
+
+	result, _, err = specHistoryHandler(ctx, &mcp.CallToolRequest{}, SpecHistoryInput{Path: root, Spec: "auth", Requirement: "User login", Format: "json"})
+	require.NoError(t, err)
+	var entries []spec.SpecHistoryEntry
+	require.NoError(t, json.Unmarshal([]byte(text(result)), &entries))
+	assert.Empty(t, entries)
+
+	_, _, err = specHistoryHandler(ctx, &mcp.CallToolRequest{}, SpecHistoryInput{Path: root})
+	require.Error(t, err)
+
+	result, _, err = unarchiveHandler(ctx, &mcp.CallToolRequest{}, UnarchiveInput{Path: root, ID: "add-2fa", DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "DRY RUN")
+	assert.Contains(t, text(result), "Would revert specs:\n  - auth")
+
+	result, _, err = unarchiveHandler(ctx, &mcp.CallToolRequest{}, UnarchiveInput{Path: root, ID: "add-2fa"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "✅ Restored 'add-2fa'")
+	content, err := os.ReadFile(specPath)
+	require.NoError(t, err)
+	assert.Equal(t, base, string(content))
+
+	result, _, err = unarchiveHandler(ctx, &mcp.CallToolRequest{}, UnarchiveInput{Path: root, ID: "add-2fa"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Error unarchiving")
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/lint"
+)
+
+type ASTLintInput struct {
+	File    string `json:"file,omitempty"`
+	Package string `json:"package,omitempty"`
+}
+
+func registerASTLint(s *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "go_ent_ast_lint",
+		Description: "Run the Go lint rules of enabled plugins on a file or package; findings name the plugin that reported them",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"file": map[string]any{
+					"type":        "string",
+					"description": "Path to the Go file to lint (alternative to package)",
+				},
+				"package": map[string]any{
+					"type":        "string",
+					"description": "Package directory to lint (use '...' for recursive search)",
+				},
+			},
+			"oneOf": []map[string]any{
+				{"required": []string{"file"}},
+				{"required": []string{"package"}},
+			},
+		},
+	}
+
+	mcp.AddTool(s, tool, astLintHandler)
+}
+
+func astLintHandler(ctx context.Context, req *mcp.CallToolRequest, input ASTLintInput) (*mcp.CallToolResult, any, error) {
+	var paths []string
+	switch {
+	case input.File != "":
+		paths = []string{input.File}
+	case input.Package != "":
+		found, err := findGoFiles(input.Package)
+		if err != nil {
+			return errorResult(fmt.Errorf("find go files in %s: %w", input.Package, err)), nil, nil
+		}
+		paths = found
+	default:
+		return errorResult(fmt.Errorf("either file or package must be specified")), nil, nil
+	}
+
+	rules := lint.Default()
+	if rules.Count(lint.TargetGo) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No Go lint rules enabled. Enable a plugin that provides rules targeting Go files.\n"}},
+		}, nil, nil
+	}
+
+	var findings []lint.Finding
+	for _, path := range paths {
+		content, err := os.ReadFile(path) // #nosec G304 -- user-provided source path
+		if err != nil {
+			return errorResult(fmt.Errorf("read %s: %w", path, err)), nil, nil
+		}
+		findings = append(findings, rules.Check(ctx, lint.NewFile(lint.TargetGo, path, string(content)))...)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatLintFindings(len(paths), findings)}},
+	}, nil, nil
+}
+
+func formatLintFindings(files int, findings []lint.Finding) string {
+	if len(findings) == 0 {
+		return fmt.Sprintf("No findings in %d file(s)\n", files)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Found %d finding(s) in %d file(s):\n", len(findings), files))
+	for _, f := range findings {
+		sb.WriteString("  - " + f.String() + "\n")
+	}
+
+	return sb.String()
+}
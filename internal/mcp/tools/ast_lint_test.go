@@ -0,0 +1,54 @@
+package tools
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/lint"
+)
+
+func TestASTLint_PluginRules(t *testing.T) {
+	tmpDir := t.TempDir()
+	rulePath := filepath.Join(tmpDir, "rule.yaml")
+	require.NoError(t, os.WriteFile(rulePath, []byte(`name: no-panic
+trigger:
+  pattern: ast_lint_target.go
+conditions:
+  - type: call
+    pattern: panic
+actions:
+  - type: reject
+    message: return an error instead of panicking
+`), 0600))
+
+	rule, err := lint.LoadRule(rulePath)
+	require.NoError(t, err)
+	lint.Default().Add("ast-lint-test", rule)
+	t.Cleanup(func() { lint.Default().RemovePlugin("ast-lint-test") })
+
+	file := filepath.Join(tmpDir, "ast_lint_target.go")
+	require.NoError(t, os.WriteFile(file, []byte("package demo\n\nfunc Must() {\n\tpanic(\"no\")\n}\n"), 0600))
+
+	result, _, err := astLintHandler(context.Background(), nil, ASTLintInput{Package: tmpDir})
+	require.NoError(t, err)
+	require.Len(t, result.Content, 1)
+
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "Found 1 finding(s) in 1 file(s)")
+	assert.Contains(t, text, "ast_lint_target.go:4: return an error instead of panicking (ast-lint-test/no-panic)")
+}
+
+func TestASTLint_RequiresInput(t *testing.T) {
+	t.Parallel()
+
+	result, _, err := astLintHandler(context.Background(), nil, ASTLintInput{})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "either file or package must be specified")
+}
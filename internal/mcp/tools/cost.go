@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/cost"
+)
+
+var costLedger *cost.Ledger
+
+// InitCostLedger persists execution spend to ledger for cost reports.
+func InitCostLedger(ledger *cost.Ledger) {
+	costLedger = ledger
+}
+
+// recordSpend feeds the live cost metrics and appends e to the cost ledger.
+func recordSpend(e cost.Entry) {
+	recordCost(e.Provider, e.Model, e.TokensIn, e.TokensOut, e.Cost)
+
+	if costLedger == nil || e.Cost <= 0 && e.TokensIn == 0 && e.TokensOut == 0 {
+		return
+	}
+	if err := costLedger.Record(e); err != nil {
+		slog.Warn("failed to record spend", "error", err)
+	}
+}
+
+type CostReportInput struct {
+	Path   string `json:"path"`
+	Month  string `json:"month,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+func registerCostReport(s *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "cost_report",
+		Description: "Report persisted spend by day, provider, model, change and agent role against budget limits, with month-end projection and budget warnings",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{
+					"type":        "string",
+					"description": "Path to the project directory",
+				},
+				"month": map[string]any{
+					"type":        "string",
+					"description": "Month to report as YYYY-MM (default: current month)",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"description": "Output format: markdown, json",
+					"enum":        []string{"markdown", "json"},
+				},
+			},
+			"required": []string{"path"},
+		},
+	}
+
+	mcp.AddTool(s, tool, costReportHandler)
+}
+
+func costReportHandler(_ context.Context, _ *mcp.CallToolRequest, input CostReportInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	format := input.Format
+	if format == "" {
+		format = "markdown"
+	}
+	if format != "markdown" && format != "json" {
+		return nil, nil, fmt.Errorf("invalid format: %s (must be markdown or json)", format)
+	}
+
+	report, err := cost.ProjectReport(input.Path, input.Month, time.Now())
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)}},
+		}, nil, nil
+	}
+
+	text := cost.FormatMarkdown(report)
+	if format == "json" {
+		data, _ := json.MarshalIndent(report, "", "  ")
+		text = string(data)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, report, nil
+}
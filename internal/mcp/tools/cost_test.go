@@ -0,0 +1,51 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/cost"
+)
+
+func TestCostReport_Handler(t *testing.T) {
+	root := t.TempDir()
+	ledger, err := cost.OpenLedger(filepath.Join(root, cost.LedgerFile))
+	require.NoError(t, err)
+
+	InitCostLedger(ledger)
+	defer InitCostLedger(nil)
+
+	recordSpend(cost.Entry{Source: cost.SourceEngine, Provider: "claude-code", Model: "sonnet", ChangeID: "add-auth", TokensIn: 100, Cost: 0.25})
+	recordSpend(cost.Entry{Source: cost.SourceEngine, Provider: "claude-code"})
+
+	entries, err := ledger.Entries(time.Time{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "empty spend is not recorded")
+
+	result, report, err := costReportHandler(context.Background(), nil, CostReportInput{Path: root})
+	require.NoError(t, err)
+
+	text, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok, "expected TextContent")
+	assert.Contains(t, text.Text, "| add-auth | 1 |")
+	assert.InDelta(t, 0.25, report.(cost.Report).Total, 1e-9)
+
+	result, _, err = costReportHandler(context.Background(), nil, CostReportInput{Path: root, Format: "json"})
+	require.NoError(t, err)
+	text, ok = result.Content[0].(*mcp.TextContent)
+	require.True(t, ok, "expected TextContent")
+	assert.Contains(t, text.Text, `"by_change"`)
+}
+
+func TestCostReport_Validation(t *testing.T) {
+	_, _, err := costReportHandler(context.Background(), nil, CostReportInput{})
+	assert.Error(t, err)
+
+	_, _, err = costReportHandler(context.Background(), nil, CostReportInput{Path: t.TempDir(), Format: "xml"})
+	assert.Error(t, err)
+}
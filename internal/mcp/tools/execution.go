@@ -7,6 +7,7 @@ import (
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/agent"
+	"github.com/victorzhuk/go-ent/internal/cost"
 	"github.com/victorzhuk/go-ent/internal/domain"
 	"github.com/victorzhuk/go-ent/internal/execution"
 	"github.com/victorzhuk/go-ent/internal/skill"
@@ -125,8 +126,7 @@ func registerEngineExecute(s *mcp.Server, registry *skill.Registry) {
 	}
 
 	baseHandler := makeEngineExecuteHandler(registry)
-	handler := WithMetrics[EngineExecuteInput, any]("engine_execute", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func makeEngineExecuteHandler(registry *skill.Registry) func(context.Context, *mcp.CallToolRequest, EngineExecuteInput) (*mcp.CallToolResult, any, error) {
@@ -187,6 +187,21 @@ func makeEngineExecuteHandler(registry *skill.Registry) func(context.Context, *m
 			}, nil, nil
 		}
 
+		runtime, _ := result.Metadata["runtime"].(string)
+		model, _ := result.Metadata["model"].(string)
+		agentRole, _ := result.Metadata["agent"].(string)
+		changeID, _ := input.Context["change_id"].(string)
+		recordSpend(cost.Entry{
+			Source:    cost.SourceEngine,
+			Provider:  runtime,
+			Model:     model,
+			ChangeID:  changeID,
+			AgentRole: agentRole,
+			TokensIn:  result.TokensIn,
+			TokensOut: result.TokensOut,
+			Cost:      result.Cost,
+		})
+
 		response := EngineExecuteResponse{
 			Success:     result.Success,
 			Output:      result.Output,
@@ -22,12 +22,13 @@ type GenerateFromSpecInput struct {
 	ProjectName string `json:"project_name,omitempty"`
 	ProjectRoot string `json:"project_root,omitempty"`
 	GoVersion   string `json:"go_version,omitempty"`
+	Entity      string `json:"entity,omitempty"`
 }
 
 func registerGenerateFromSpec(s *mcp.Server) {
 	tool := &mcp.Tool{
 		Name:        "generate_from_spec",
-		Description: "Generate a complete project from a spec file. Analyzes spec, selects archetype, generates scaffold, and provides AI prompts for business logic.",
+		Description: "Generate a complete project from a spec file. Analyzes spec, selects archetype, generates scaffold, and turns each requirement into a domain entity, repository contract, use case stub, HTTP handler and table-driven test seeded from its scenarios.",
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -55,6 +56,10 @@ func registerGenerateFromSpec(s *mcp.Server) {
 					"type":        "string",
 					"description": "Go version (e.g., '1.25'). Defaults to current runtime version",
 				},
+				"entity": map[string]any{
+					"type":        "string",
+					"description": "Domain entity name (defaults to the spec's capability directory, singularized)",
+				},
 			},
 			"required": []string{"spec_path", "output_dir", "module_path"},
 		},
@@ -176,6 +181,40 @@ func generateFromSpecHandler(ctx context.Context, req *mcp.CallToolRequest, inpu
 		}, nil, nil
 	}
 
+	// Generate domain code from requirements
+	specContent, err := os.ReadFile(specPath) // #nosec G304 -- controlled file path
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("Error reading spec: %v", err),
+			}},
+		}, nil, nil
+	}
+
+	entity := input.Entity
+	if entity == "" {
+		entity = generation.EntityFromSpecPath(specPath)
+	}
+
+	reqs := generation.ParseRequirements(string(specContent))
+	var domainFiles []generation.GeneratedFile
+	if len(reqs) > 0 {
+		domainFiles, err = generation.GenerateDomain(reqs, generation.DomainOptions{
+			ModulePath: input.ModulePath,
+			Entity:     entity,
+		})
+		if err == nil {
+			err = writeGeneratedFiles(input.OutputDir, domainFiles)
+		}
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error generating domain code: %v", err),
+				}},
+			}, nil, nil
+		}
+	}
+
 	// Get generated files
 	files, err := listGeneratedFiles(input.OutputDir)
 	if err != nil {
@@ -198,6 +237,17 @@ func generateFromSpecHandler(ctx context.Context, req *mcp.CallToolRequest, inpu
 		msg += fmt.Sprintf("  - %s\n", f)
 	}
 
+	msg += "\n## Domain Code\n\n"
+	if len(domainFiles) == 0 {
+		msg += "No `### Requirement:` sections found; only the scaffold was generated.\n"
+	} else {
+		msg += fmt.Sprintf("Generated from %d requirement(s) for entity %s:\n", len(reqs), generation.EntityName(entity))
+		for _, f := range domainFiles {
+			msg += fmt.Sprintf("  - %s\n", f.Path)
+		}
+		msg += "\nUse case stubs return `usecase.ErrNotImplemented`; their scenario tests skip until implemented.\n"
+	}
+
 	msg += "\n## Extension Points\n\n"
 	msg += "The generated code contains `@generate:` markers for AI completion:\n"
 	msg += "- `@generate:constructor` - Dependency injection\n"
@@ -207,7 +257,8 @@ func generateFromSpecHandler(ctx context.Context, req *mcp.CallToolRequest, inpu
 	msg += "## Next Steps\n\n"
 	msg += fmt.Sprintf("  cd %s\n", input.OutputDir)
 	msg += "  go mod tidy\n"
-	msg += "  # Review extension points and use AI to generate business logic\n"
+	msg += "  # Implement the use case stubs until the scenario tests pass\n"
+	msg += "  go test ./internal/usecase/...\n"
 	msg += "  make build\n\n"
 
 	msg += "## AI Prompt Templates Available\n\n"
@@ -220,3 +271,16 @@ func generateFromSpecHandler(ctx context.Context, req *mcp.CallToolRequest, inpu
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
 	}, nil, nil
 }
+
+func writeGeneratedFiles(root string, files []generation.GeneratedFile) error {
+	for _, f := range files {
+		path := filepath.Join(root, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return fmt.Errorf("create directory for %s: %w", f.Path, err)
+		}
+		if err := os.WriteFile(path, []byte(f.Content), 0600); err != nil {
+			return fmt.Errorf("write %s: %w", f.Path, err)
+		}
+	}
+	return nil
+}
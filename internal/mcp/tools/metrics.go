@@ -13,15 +13,31 @@ import (
 	"github.com/victorzhuk/go-ent/internal/metrics"
 )
 
-var metricsStore *metrics.Store
+var metricsStore metrics.Backend
+var metricsCollector *metrics.Collector
 var metricsEnabled bool = true
 
 // InitMetricsStore initializes the metrics store for tools.
 // This is called during MCP server initialization.
-func InitMetricsStore(store *metrics.Store) {
+func InitMetricsStore(store metrics.Backend) {
 	metricsStore = store
 }
 
+// InitMetricsCollector routes tool metrics through collector, which feeds
+// both the live /metrics families and the store.
+func InitMetricsCollector(collector *metrics.Collector) {
+	metricsCollector = collector
+}
+
+// recordCost attributes execution spend to a provider and model on the live
+// metrics, if a collector is configured.
+func recordCost(provider, model string, tokensIn, tokensOut int, cost float64) {
+	if metricsCollector == nil || !IsMetricsEnabled() {
+		return
+	}
+	metricsCollector.RecordCost(provider, model, tokensIn, tokensOut, cost)
+}
+
 // SetMetricsEnabled sets whether metrics collection is enabled.
 func SetMetricsEnabled(enabled bool) {
 	metricsEnabled = enabled
@@ -139,6 +155,9 @@ func metricsShowHandler(_ context.Context, _ *mcp.CallToolRequest, input Metrics
 	case "table":
 		return formatTableMetrics(aggregator, filter, groupBy)
 	default:
+		if q, ok := rollupQuery(input.SessionID, input.ToolName, input.StartTime, input.EndTime); ok {
+			return formatRollupShow(aggregator, q, groupBy)
+		}
 		return formatSummaryMetrics(aggregator, filter, groupBy)
 	}
 }
@@ -253,47 +272,11 @@ func formatGroupedSummary(aggregator *metrics.Aggregator, filter metrics.Filter,
 		}, nil, nil
 	}
 
-	groups := aggregator.GroupByTime(groupByType, filter)
-
-	if len(groups) == 0 {
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: "No metrics found for grouping"}},
-		}, nil, nil
-	}
-
-	var builder strings.Builder
-	builder.WriteString(fmt.Sprintf("## Metrics Summary (grouped by %s)\n\n", groupBy))
-
-	for timeKey, metricsList := range groups {
-		builder.WriteString(fmt.Sprintf("### %s\n", timeKey))
-		builder.WriteString(fmt.Sprintf("**Count:** %d\n", len(metricsList)))
-
-		count := len(metricsList)
-		var totalTokensIn, totalTokensOut int
-		var totalDuration time.Duration
-		var successCount int
-
-		for _, m := range metricsList {
-			totalTokensIn += m.TokensIn
-			totalTokensOut += m.TokensOut
-			totalDuration += m.Duration
-			if m.Success {
-				successCount++
-			}
-		}
-
-		avgDuration := totalDuration / time.Duration(count)
-		successRate := float64(successCount) / float64(count) * 100
-
-		builder.WriteString(fmt.Sprintf("- Avg Tokens In: %.0f\n", float64(totalTokensIn)/float64(count)))
-		builder.WriteString(fmt.Sprintf("- Avg Tokens Out: %.0f\n", float64(totalTokensOut)/float64(count)))
-		builder.WriteString(fmt.Sprintf("- Avg Duration: %v\n", avgDuration))
-		builder.WriteString(fmt.Sprintf("- Success Rate: %.1f%%\n\n", successRate))
+	groups, err := aggregator.GroupByTime(groupByType, filter)
+	if err != nil {
+		return errorResult(err), nil, nil
 	}
-
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
-	}, nil, nil
+	return formatGroupedRollups(groups, groupBy), nil, nil
 }
 
 func formatTableMetrics(aggregator *metrics.Aggregator, filter metrics.Filter, groupBy string) (*mcp.CallToolResult, any, error) {
@@ -460,6 +443,12 @@ func metricsSummaryHandler(_ context.Context, _ *mcp.CallToolRequest, input Metr
 
 	aggregator := metrics.NewAggregator(metricsStore)
 
+	if groupBy != "session" {
+		if q, ok := rollupQuery(input.SessionID, input.ToolName, input.StartTime, input.EndTime); ok {
+			return formatRollupSummary(aggregator, q, groupBy, format)
+		}
+	}
+
 	if format == "json" {
 		return formatSummaryJSON(aggregator, filter, groupBy, limit)
 	}
@@ -0,0 +1,227 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/metrics"
+)
+
+// rollupQuery builds a rollup query from tool inputs. It reports false when
+// the store keeps no rollups or a session filter requires raw events.
+func rollupQuery(sessionID, toolName, startTime, endTime string) (metrics.Query, bool) {
+	if sessionID != "" {
+		return metrics.Query{}, false
+	}
+	if _, ok := metricsStore.(metrics.RollupSource); !ok {
+		return metrics.Query{}, false
+	}
+
+	q := metrics.Query{Tool: toolName}
+	if startTime != "" {
+		if t, err := time.Parse(time.RFC3339, startTime); err == nil {
+			q.Start = t
+		}
+	}
+	if endTime != "" {
+		if t, err := time.Parse(time.RFC3339, endTime); err == nil {
+			q.End = t
+		}
+	}
+	return q, true
+}
+
+func parseGroupBy(groupBy string) (metrics.GroupBy, bool) {
+	switch groupBy {
+	case "hour":
+		return metrics.GroupByHour, true
+	case "day":
+		return metrics.GroupByDay, true
+	case "week":
+		return metrics.GroupByWeek, true
+	default:
+		return 0, false
+	}
+}
+
+// rollupGroups returns merged rollups keyed by group for none, tool, hour,
+// day and week grouping.
+func rollupGroups(aggregator *metrics.Aggregator, q metrics.Query, groupBy string) (map[string]metrics.Rollup, error) {
+	switch groupBy {
+	case "none":
+		summary, err := aggregator.Summarize(q)
+		if err != nil {
+			return nil, err
+		}
+		if summary.Count == 0 {
+			return map[string]metrics.Rollup{}, nil
+		}
+		return map[string]metrics.Rollup{"": summary}, nil
+	case "tool":
+		return aggregator.GroupRollupsByTool(q)
+	default:
+		gb, ok := parseGroupBy(groupBy)
+		if !ok {
+			return nil, fmt.Errorf("invalid group_by: %s", groupBy)
+		}
+		return aggregator.GroupRollups(gb, q)
+	}
+}
+
+func sortedRollupKeys(groups map[string]metrics.Rollup) []string {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatRollupShow renders the metrics_show summary from rollups, so the
+// totals cover the whole requested range rather than only retained raw events.
+func formatRollupShow(aggregator *metrics.Aggregator, q metrics.Query, groupBy string) (*mcp.CallToolResult, any, error) {
+	if groupBy == "none" {
+		summary, err := aggregator.Summarize(q)
+		if err != nil {
+			return errorResult(err), nil, nil
+		}
+		if summary.Count == 0 {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "No metrics available"}},
+			}, nil, nil
+		}
+
+		p50, _ := summary.Percentile("duration", 0.50)
+		p95, _ := summary.Percentile("duration", 0.95)
+		p99, _ := summary.Percentile("duration", 0.99)
+
+		var builder strings.Builder
+		builder.WriteString("## Metrics Summary\n\n")
+		builder.WriteString(fmt.Sprintf("**Total Entries:** %d\n\n", summary.Count))
+		builder.WriteString("### Token Usage\n")
+		builder.WriteString(fmt.Sprintf("- Average Input Tokens: %.0f\n", summary.AverageTokensIn()))
+		builder.WriteString(fmt.Sprintf("- Average Output Tokens: %.0f\n\n", summary.AverageTokensOut()))
+		builder.WriteString("### Performance\n")
+		builder.WriteString(fmt.Sprintf("- Average Duration: %v\n", summary.AverageDuration()))
+		builder.WriteString(fmt.Sprintf("- P50 Duration: %v\n", time.Duration(p50)))
+		builder.WriteString(fmt.Sprintf("- P95 Duration: %v\n", time.Duration(p95)))
+		builder.WriteString(fmt.Sprintf("- P99 Duration: %v\n\n", time.Duration(p99)))
+		builder.WriteString(fmt.Sprintf("- Success Rate: %.1f%%\n", summary.SuccessRate()))
+
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
+		}, nil, nil
+	}
+
+	gb, ok := parseGroupBy(groupBy)
+	if !ok {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid group_by: %s", groupBy)}},
+		}, nil, nil
+	}
+
+	groups, err := aggregator.GroupRollups(gb, q)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	return formatGroupedRollups(groups, groupBy), nil, nil
+}
+
+// formatGroupedRollups renders one section per time key, oldest first.
+func formatGroupedRollups(groups map[string]metrics.Rollup, groupBy string) *mcp.CallToolResult {
+	if len(groups) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No metrics found for grouping"}},
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("## Metrics Summary (grouped by %s)\n\n", groupBy))
+	for _, key := range sortedRollupKeys(groups) {
+		r := groups[key]
+		builder.WriteString(fmt.Sprintf("### %s\n", key))
+		builder.WriteString(fmt.Sprintf("**Count:** %d\n", r.Count))
+		builder.WriteString(fmt.Sprintf("- Avg Tokens In: %.0f\n", r.AverageTokensIn()))
+		builder.WriteString(fmt.Sprintf("- Avg Tokens Out: %.0f\n", r.AverageTokensOut()))
+		builder.WriteString(fmt.Sprintf("- Avg Duration: %v\n", r.AverageDuration()))
+		builder.WriteString(fmt.Sprintf("- Success Rate: %.1f%%\n\n", r.SuccessRate()))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
+	}
+}
+
+// formatRollupSummary renders metrics_summary output from rollups.
+func formatRollupSummary(aggregator *metrics.Aggregator, q metrics.Query, groupBy, format string) (*mcp.CallToolResult, any, error) {
+	groups, err := rollupGroups(aggregator, q, groupBy)
+	if err != nil {
+		return errorResult(err), nil, nil
+	}
+	if len(groups) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No metrics found"}},
+		}, nil, nil
+	}
+
+	if format == "json" {
+		result := summaryResult{}
+		if groupBy == "none" {
+			r := groups[""]
+			result = summaryResult{
+				Count:     r.Count,
+				TokensIn:  r.AverageTokensIn(),
+				TokensOut: r.AverageTokensOut(),
+				Duration:  float64(r.AverageDuration()) / float64(time.Millisecond),
+				Success:   r.SuccessRate(),
+			}
+		} else {
+			for _, key := range sortedRollupKeys(groups) {
+				r := groups[key]
+				result.Count += r.Count
+				result.Groups = append(result.Groups, summaryGroup{
+					Key:         key,
+					Count:       r.Count,
+					TokensIn:    r.AverageTokensIn(),
+					TokensOut:   r.AverageTokensOut(),
+					Duration:    float64(r.AverageDuration()) / float64(time.Millisecond),
+					SuccessRate: r.SuccessRate(),
+				})
+			}
+		}
+
+		resultJSON, _ := json.MarshalIndent(result, "", "  ")
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: string(resultJSON)}},
+		}, nil, nil
+	}
+
+	var builder strings.Builder
+	builder.WriteString("## Metrics Summary\n\n")
+
+	if groupBy == "none" {
+		r := groups[""]
+		builder.WriteString(fmt.Sprintf("**Count:** %d\n\n", r.Count))
+		builder.WriteString(fmt.Sprintf("**Avg Tokens In:** %.0f\n", r.AverageTokensIn()))
+		builder.WriteString(fmt.Sprintf("**Avg Tokens Out:** %.0f\n", r.AverageTokensOut()))
+		builder.WriteString(fmt.Sprintf("**Avg Duration:** %v\n", r.AverageDuration()))
+		builder.WriteString(fmt.Sprintf("**Success Rate:** %.1f%%\n", r.SuccessRate()))
+	} else {
+		builder.WriteString(fmt.Sprintf("**Grouped by:** %s\n\n", groupBy))
+		builder.WriteString("| Key | Count | Avg Tokens In | Avg Tokens Out | Avg Duration | Success Rate |\n")
+		builder.WriteString("|-----|-------|---------------|----------------|--------------|--------------|\n")
+		for _, key := range sortedRollupKeys(groups) {
+			r := groups[key]
+			builder.WriteString(fmt.Sprintf("| %s | %d | %.0f | %.0f | %v | %.1f%% |\n",
+				key, r.Count, r.AverageTokensIn(), r.AverageTokensOut(), r.AverageDuration(), r.SuccessRate()))
+		}
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: builder.String()}},
+	}, nil, nil
+}
@@ -1792,3 +1792,69 @@ func TestMetricsReset_StoreNil(t *testing.T) {
 	require.True(t, ok, "expected TextContent")
 	assert.Contains(t, textContent.Text, "Metrics store not initialized")
 }
+
+func TestMetricsShow_DurableStoreRollups(t *testing.T) {
+	store, err := metrics.OpenDurableStore(t.TempDir()+"/metrics.db", metrics.Retention{})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	InitMetricsStore(store)
+	defer InitMetricsStore(nil)
+
+	now := time.Now()
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, store.Add(metrics.Metric{
+			SessionID: "session-1",
+			ToolName:  "tool_a",
+			TokensIn:  100,
+			TokensOut: 50,
+			Duration:  time.Duration(i) * 100 * time.Millisecond,
+			Success:   i != 4,
+			Timestamp: now,
+		}))
+	}
+
+	result, _, err := metricsShowHandler(context.Background(), nil, MetricsShowInput{ToolName: "tool_a"})
+	require.NoError(t, err)
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok, "expected TextContent")
+	assert.Contains(t, textContent.Text, "**Total Entries:** 4")
+	assert.Contains(t, textContent.Text, "Average Duration: 250ms")
+	assert.Contains(t, textContent.Text, "P95 Duration:")
+	assert.Contains(t, textContent.Text, "Success Rate: 75.0%")
+}
+
+func TestMetricsSummary_DurableStoreGroupByTool(t *testing.T) {
+	store, err := metrics.OpenDurableStore(t.TempDir()+"/metrics.db", metrics.Retention{})
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	InitMetricsStore(store)
+	defer InitMetricsStore(nil)
+
+	now := time.Now()
+	for _, tool := range []string{"tool_a", "tool_a", "tool_b"} {
+		require.NoError(t, store.Add(metrics.Metric{
+			ToolName:  tool,
+			TokensIn:  100,
+			Duration:  100 * time.Millisecond,
+			Success:   true,
+			Timestamp: now,
+		}))
+	}
+
+	result, _, err := metricsSummaryHandler(context.Background(), nil, MetricsSummaryInput{GroupBy: "tool", Format: "json"})
+	require.NoError(t, err)
+
+	textContent, ok := result.Content[0].(*mcp.TextContent)
+	require.True(t, ok, "expected TextContent")
+
+	var summary summaryResult
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &summary))
+	assert.Equal(t, 3, summary.Count)
+	require.Len(t, summary.Groups, 2)
+	assert.Equal(t, "tool_a", summary.Groups[0].Key)
+	assert.Equal(t, 2, summary.Groups[0].Count)
+	assert.Equal(t, "tool_b", summary.Groups[1].Key)
+}
@@ -2,62 +2,156 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/metrics"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
-func WithMetrics[In, Out any](toolName string, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error) {
-	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
-		if !IsMetricsEnabled() {
-			return handler(ctx, req, input)
+// TracingMiddleware starts a server span for every MCP request. Tool calls
+// are named mcp.tools/call with the tool name as an attribute, so spans
+// created by handlers (engine, workers, providers) nest under them.
+func TracingMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			ctx, span := tracing.Start(ctx, "mcp."+method, tracing.String("mcp.method", method))
+			if span == nil {
+				return next(ctx, method, req)
+			}
+			defer span.End()
+			span.SetKind(tracing.SpanKindServer)
+
+			if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+				span.SetAttributes(tracing.String("mcp.tool", call.Params.Name))
+			}
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				span.RecordError(err)
+				return result, err
+			}
+
+			if toolResult, ok := result.(*mcp.CallToolResult); ok {
+				span.SetAttributes(tracing.Int(tracing.AttrTokensOut, estimateOutputTokens(toolResult)))
+				if toolResult.IsError {
+					span.SetStatus(tracing.StatusError, "tool returned error")
+				}
+			}
+			return result, nil
 		}
+	}
+}
 
-		if metricsStore == nil {
-			return handler(ctx, req, input)
+// MetricsMiddleware records a metric for every tools/call request, so the
+// store and the live /metrics families cover all registered tools. Tool
+// results flagged IsError count as failures.
+func MetricsMiddleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok || call.Params == nil || !metricsActive() {
+				return next(ctx, method, req)
+			}
+
+			sessionID := getSessionID(ctx)
+			if sessionID == "" && call.Session != nil {
+				sessionID = call.Session.ID()
+			}
+			ctx, sessionID = withSession(ctx, sessionID)
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			toolResult, _ := result.(*mcp.CallToolResult)
+
+			failure := err
+			if failure == nil && toolResult != nil && toolResult.IsError {
+				failure = errors.New(toolResultText(toolResult))
+			}
+			recordToolMetric(ctx, call.Params.Name, sessionID, call, toolResult, failure, time.Since(start))
+
+			return result, err
 		}
+	}
+}
 
-		sessionID := getSessionID(ctx)
-		if sessionID == "" {
-			sessionID = uuid.Must(uuid.NewV7()).String()
-			ctx = context.WithValue(ctx, sessionContextKey, sessionID)
+// WithMetrics instruments a single handler. Servers install
+// MetricsMiddleware instead, which covers every tool; WithMetrics is for
+// handlers invoked outside an MCP server.
+func WithMetrics[In, Out any](toolName string, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, Out, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, input In) (*mcp.CallToolResult, Out, error) {
+		if !metricsActive() {
+			return handler(ctx, req, input)
 		}
 
-		slog.Debug("collecting metrics",
-			"tool", toolName,
-			"session_id", sessionID,
-		)
+		ctx, sessionID := withSession(ctx, getSessionID(ctx))
 
 		start := time.Now()
-
 		result, meta, err := handler(ctx, req, input)
+		recordToolMetric(ctx, toolName, sessionID, req, result, err, time.Since(start))
 
-		duration := time.Since(start)
-
-		metric := metrics.Metric{
-			SessionID: sessionID,
-			ToolName:  toolName,
-			TokensIn:  estimateTokens(req),
-			TokensOut: estimateOutputTokens(result),
-			Duration:  duration,
-			Success:   err == nil,
-			ErrorMsg:  getErrorMessage(err),
-			Timestamp: time.Now(),
-		}
+		return result, meta, err
+	}
+}
 
-		if err := metricsStore.Add(metric); err != nil {
-			slog.Warn("failed to add metric",
-				"tool", toolName,
-				"session_id", sessionID,
-				"error", err,
-			)
-		}
+func metricsActive() bool {
+	return IsMetricsEnabled() && (metricsStore != nil || metricsCollector != nil)
+}
 
-		return result, meta, err
+// withSession stores sessionID in ctx, generating one when it is empty.
+func withSession(ctx context.Context, sessionID string) (context.Context, string) {
+	if sessionID == "" {
+		sessionID = uuid.Must(uuid.NewV7()).String()
+	}
+	return context.WithValue(ctx, sessionContextKey, sessionID), sessionID
+}
+
+func recordToolMetric(ctx context.Context, toolName, sessionID string, req *mcp.CallToolRequest, result *mcp.CallToolResult, err error, duration time.Duration) {
+	slog.Debug("collecting metrics",
+		"tool", toolName,
+		"session_id", sessionID,
+	)
+
+	tracing.SpanFromContext(ctx).SetAttributes(
+		tracing.String("goent.session_id", sessionID),
+		tracing.String("goent.tool", toolName),
+	)
+
+	metric := metrics.Metric{
+		SessionID: sessionID,
+		ToolName:  toolName,
+		TokensIn:  estimateTokens(req),
+		TokensOut: estimateOutputTokens(result),
+		Duration:  duration,
+		Success:   err == nil,
+		ErrorMsg:  getErrorMessage(err),
+		Timestamp: time.Now(),
+	}
+
+	if metricsCollector != nil {
+		metricsCollector.Record(metric)
+		return
+	}
+
+	if err := metricsStore.Add(metric); err != nil {
+		slog.Warn("failed to add metric",
+			"tool", toolName,
+			"session_id", sessionID,
+			"error", err,
+		)
+	}
+}
+
+func toolResultText(result *mcp.CallToolResult) string {
+	for _, c := range result.Content {
+		if text, ok := c.(*mcp.TextContent); ok && text.Text != "" {
+			return text.Text
+		}
 	}
+	return "tool returned error"
 }
 
 type contextKey string
@@ -2,6 +2,8 @@ package tools
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -139,3 +141,43 @@ func TestIsMetricsEnabled(t *testing.T) {
 	SetMetricsEnabled(false)
 	assert.False(t, IsMetricsEnabled(), "should be disabled")
 }
+
+func TestWithMetrics_Collector(t *testing.T) {
+	oldStore := metricsStore
+	oldCollector := metricsCollector
+	oldEnabled := metricsEnabled
+	defer func() {
+		metricsStore = oldStore
+		metricsCollector = oldCollector
+		metricsEnabled = oldEnabled
+	}()
+
+	store, err := metrics.NewStore(t.TempDir()+"/metrics.json", 24)
+	if err != nil {
+		t.Fatalf("create store: %v", err)
+	}
+	defer store.Close()
+
+	live := metrics.NewLive()
+	collector := metrics.NewLiveCollector(store, live)
+	InitMetricsStore(store)
+	InitMetricsCollector(collector)
+	SetMetricsEnabled(true)
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest, input string) (*mcp.CallToolResult, string, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "ok"}}}, "", nil
+	}
+
+	_, _, err = WithMetrics("test_tool", handler)(context.Background(), &mcp.CallToolRequest{}, "input")
+	assert.NoError(t, err)
+
+	recordCost("claude-code", "sonnet", 100, 50, 0.01)
+	assert.NoError(t, collector.Shutdown(context.Background()))
+
+	assert.Len(t, store.GetAll(), 1, "collector should persist to the store")
+
+	rec := httptest.NewRecorder()
+	live.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `goent_tool_calls_total{tool="test_tool",status="success"} 1`)
+	assert.Contains(t, rec.Body.String(), `goent_cost_usd_total{provider="claude-code",model="sonnet"} 0.01`)
+}
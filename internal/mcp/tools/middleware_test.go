@@ -2,12 +2,20 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/victorzhuk/go-ent/internal/metrics"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 func TestWithMetrics(t *testing.T) {
@@ -186,3 +194,90 @@ func TestWithMetrics(t *testing.T) {
 		}
 	})
 }
+
+func TestTracingMiddleware(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	exp, err := tracing.NewJSONLExporter(path)
+	require.NoError(t, err)
+	tracing.SetDefault(tracing.NewTracer(exp, tracing.Options{}))
+
+	var inner *tracing.Span
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		inner = tracing.SpanFromContext(ctx)
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: "Error: failed"}},
+		}, nil
+	}
+
+	handler := TracingMiddleware()(next)
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "spec_list"}}
+	_, err = handler(context.Background(), "tools/call", req)
+	require.NoError(t, err)
+	require.NotNil(t, inner)
+	require.NoError(t, tracing.Shutdown(context.Background()))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var span tracing.SpanData
+	require.NoError(t, json.Unmarshal(data, &span))
+	assert.Equal(t, "mcp.tools/call", span.Name)
+	assert.Equal(t, tracing.SpanKindServer, span.Kind)
+	assert.Equal(t, "spec_list", span.Attributes["mcp.tool"])
+	assert.Equal(t, tracing.StatusError, span.StatusCode)
+	assert.Equal(t, inner.SpanID().String(), span.SpanID)
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	oldStore := metricsStore
+	oldCollector := metricsCollector
+	oldEnabled := metricsEnabled
+	defer func() {
+		metricsStore = oldStore
+		metricsCollector = oldCollector
+		metricsEnabled = oldEnabled
+	}()
+
+	store, err := metrics.NewStore(filepath.Join(t.TempDir(), "metrics.json"), 24*time.Hour)
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	live := metrics.NewLive()
+	collector := metrics.NewLiveCollector(store, live)
+	InitMetricsStore(store)
+	InitMetricsCollector(collector)
+	SetMetricsEnabled(true)
+
+	var sessionID string
+	next := func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		sessionID = getSessionID(ctx)
+		if method != "tools/call" {
+			return &mcp.ListToolsResult{}, nil
+		}
+		return &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: "Error: no openspec folder"}},
+		}, nil
+	}
+
+	handler := MetricsMiddleware()(next)
+	_, err = handler(context.Background(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "spec_list"}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, sessionID, "handlers see the metrics session")
+
+	_, err = handler(context.Background(), "tools/list", &mcp.ListToolsRequest{})
+	require.NoError(t, err)
+	require.NoError(t, collector.Shutdown(context.Background()))
+
+	all := store.GetAll()
+	require.Len(t, all, 1, "only tool calls are recorded")
+	assert.Equal(t, "spec_list", all[0].ToolName)
+	assert.False(t, all[0].Success)
+	assert.Equal(t, "Error: no openspec folder", all[0].ErrorMsg)
+
+	rec := httptest.NewRecorder()
+	live.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `goent_tool_calls_total{tool="spec_list",status="error"} 1`)
+	assert.Contains(t, rec.Body.String(), `goent_tool_duration_seconds_count{tool="spec_list"} 1`)
+}
@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/breaker"
+	"github.com/victorzhuk/go-ent/internal/config"
+)
+
+func TestProviderList_CircuitState(t *testing.T) {
+	prev := breaker.Default()
+	t.Cleanup(func() { breaker.SetDefault(prev) })
+
+	reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1})
+	reg.Record("anthropic", errors.New("HTTP 529"))
+	reg.Record("glm", nil)
+	breaker.SetDefault(reg)
+
+	providers := &config.ProvidersConfig{
+		Providers: map[string]config.ProviderDefinition{
+			"haiku":    {Method: config.MethodAPI, Provider: "anthropic", Model: "claude-3-haiku"},
+			"glm":      {Method: config.MethodACP, Provider: "moonshot", Model: "glm-4"},
+			"deepseek": {Method: config.MethodCLI, Provider: "deepseek", Model: "deepseek-coder"},
+		},
+	}
+	handler := makeProviderListHandler(providers)
+
+	t.Run("reports circuit state", func(t *testing.T) {
+		_, out, err := handler(context.Background(), nil, ProviderListInput{})
+		require.NoError(t, err)
+
+		response := out.(ProviderListResponse)
+		assert.Equal(t, 3, response.Total)
+		assert.Equal(t, 2, response.Active)
+
+		byID := make(map[string]ProviderInfo)
+		for _, p := range response.Providers {
+			byID[p.ID] = p
+		}
+
+		assert.Equal(t, "open", byID["haiku"].Circuit)
+		assert.Equal(t, "unhealthy", byID["haiku"].Health)
+		assert.Equal(t, "HTTP 529", byID["haiku"].LastError)
+		assert.Equal(t, "closed", byID["glm"].Circuit)
+		assert.Equal(t, "healthy", byID["glm"].Health)
+		assert.Equal(t, "closed", byID["deepseek"].Circuit)
+		assert.Equal(t, "unknown", byID["deepseek"].Health)
+	})
+
+	t.Run("active only hides open circuits", func(t *testing.T) {
+		_, out, err := handler(context.Background(), nil, ProviderListInput{ActiveOnly: true})
+		require.NoError(t, err)
+
+		response := out.(ProviderListResponse)
+		require.Len(t, response.Providers, 2)
+		for _, p := range response.Providers {
+			assert.NotEqual(t, "haiku", p.ID)
+		}
+	})
+}
@@ -27,6 +27,8 @@ func Register(s *mcp.Server, skillRegistry *skill.Registry, pluginManager *plugi
 	registerGenerate(s)
 	registerValidate(s)
 	registerArchive(s)
+	registerSpecConflicts(s)
+	registerSpecTrace(s)
 	registerListArchetypes(s)
 	registerGenerateComponent(s)
 	registerGenerateFromSpec(s)
@@ -62,6 +64,7 @@ func Register(s *mcp.Server, skillRegistry *skill.Registry, pluginManager *plugi
 	registerEngineExecute(s, skillRegistry)
 	registerEngineStatus(s)
 	registerEngineBudget(s)
+	registerCostReport(s)
 	registerEngineInterrupt(s)
 	registerASTParse(s)
 	registerASTQuery(s)
@@ -69,6 +72,7 @@ func Register(s *mcp.Server, skillRegistry *skill.Registry, pluginManager *plugi
 	registerASTRename(s)
 	registerASTExtract(s)
 	registerASTGenerate(s)
+	registerASTLint(s)
 
 	// Register plugin tools
 	if pluginManager != nil {
@@ -80,6 +84,7 @@ func Register(s *mcp.Server, skillRegistry *skill.Registry, pluginManager *plugi
 	if marketplaceSearcher != nil {
 		registerPluginSearch(s, marketplaceSearcher)
 	}
+	registerReloadStatus(s)
 
 	// Register state tools
 	registerStateSync(s)
@@ -1,12 +1,15 @@
 package tools
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/spec"
@@ -23,9 +26,19 @@ type RegistryListInput struct {
 }
 
 type RegistryNextInput struct {
-	Path     string `json:"path"`
-	ChangeID string `json:"change_id,omitempty"`
-	Count    int    `json:"count,omitempty"`
+	Path       string `json:"path"`
+	ChangeID   string `json:"change_id,omitempty"`
+	Count      int    `json:"count,omitempty"`
+	ClaimAs    string `json:"claim_as,omitempty"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
+}
+
+type RegistryLeaseInput struct {
+	Path       string `json:"path"`
+	TaskID     string `json:"task_id"`
+	Operation  string `json:"operation"`
+	Holder     string `json:"holder"`
+	TTLSeconds int    `json:"ttl_seconds,omitempty"`
 }
 
 type RegistryUpdateInput struct {
@@ -44,10 +57,33 @@ type RegistryDepsInput struct {
 	DependsOn string `json:"depends_on,omitempty"`
 }
 
+type RegistryPlanInput struct {
+	Path            string  `json:"path"`
+	ChangeID        string  `json:"change_id,omitempty"`
+	Format          string  `json:"format,omitempty"`
+	DefaultEstimate float64 `json:"default_estimate,omitempty"`
+}
+
+type RegistryExportInput struct {
+	Path     string `json:"path"`
+	Format   string `json:"format"`
+	ChangeID string `json:"change_id,omitempty"`
+}
+
+type RegistryImportInput struct {
+	Path     string `json:"path"`
+	Format   string `json:"format,omitempty"`
+	File     string `json:"file,omitempty"`
+	Content  string `json:"content,omitempty"`
+	ChangeID string `json:"change_id,omitempty"`
+	DryRun   bool   `json:"dry_run,omitempty"`
+}
+
 type RegistrySyncInput struct {
-	Path   string `json:"path"`
-	DryRun bool   `json:"dry_run,omitempty"`
-	Force  bool   `json:"force,omitempty"`
+	Path    string `json:"path"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+	Force   bool   `json:"force,omitempty"`
+	Resolve string `json:"resolve,omitempty"`
 }
 
 type RegistryInitInput struct {
@@ -76,19 +112,38 @@ func registerRegistry(s *mcp.Server) {
 
 	nextTool := &mcp.Tool{
 		Name:        "registry_next",
-		Description: "Get the next recommended task(s) based on priority, dependencies, and status. Returns unblocked, highest priority pending tasks.",
+		Description: "Get the next recommended task(s) based on priority, dependencies, and status. Returns unblocked, highest priority pending tasks. With claim_as, atomically leases the next task (or one whose lease expired) to that worker or agent ID so parallel callers never get the same task.",
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"path":      map[string]any{"type": "string", "description": "Path to project directory"},
-				"change_id": map[string]any{"type": "string", "description": "Filter by change ID"},
-				"count":     map[string]any{"type": "integer", "description": "Number of tasks to return", "default": 1},
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"change_id":   map[string]any{"type": "string", "description": "Filter by change ID"},
+				"count":       map[string]any{"type": "integer", "description": "Number of tasks to return", "default": 1},
+				"claim_as":    map[string]any{"type": "string", "description": "Worker or agent ID to claim the next task for"},
+				"ttl_seconds": map[string]any{"type": "integer", "description": "Lease duration before the claim can be stolen", "default": int(spec.DefaultLeaseTTL.Seconds())},
 			},
 			"required": []string{"path"},
 		},
 	}
 	mcp.AddTool(s, nextTool, registryNextHandler)
 
+	leaseTool := &mcp.Tool{
+		Name:        "registry_lease",
+		Description: "Manage task leases held by workers or agents: claim a task, heartbeat to extend the lease, release it back to pending, or steal a task whose lease expired.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"task_id":     map[string]any{"type": "string", "description": "Task ID (change-id/task-num)"},
+				"operation":   map[string]any{"type": "string", "description": "Lease operation", "enum": []string{"claim", "heartbeat", "release", "steal"}},
+				"holder":      map[string]any{"type": "string", "description": "Worker or agent ID holding the lease"},
+				"ttl_seconds": map[string]any{"type": "integer", "description": "Lease duration for claim and steal", "default": int(spec.DefaultLeaseTTL.Seconds())},
+			},
+			"required": []string{"path", "task_id", "operation", "holder"},
+		},
+	}
+	mcp.AddTool(s, leaseTool, registryLeaseHandler)
+
 	updateTool := &mcp.Tool{
 		Name:        "registry_update",
 		Description: "Update task status, priority, or assignment in the registry. Automatically updates blocked_by for dependent tasks.",
@@ -123,15 +178,65 @@ func registerRegistry(s *mcp.Server) {
 	}
 	mcp.AddTool(s, depsTool, registryDepsHandler)
 
+	planTool := &mcp.Tool{
+		Name:        "registry_plan",
+		Description: "Schedule remaining tasks with the critical path method. Uses <!-- estimate: 4h --> comments from tasks.md (units m, h, d, w), reports slack per task and the critical path, and projects completion from the throughput of completed tasks. Renders a text Gantt chart, a Mermaid gantt diagram, or JSON.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":             map[string]any{"type": "string", "description": "Path to project directory"},
+				"change_id":        map[string]any{"type": "string", "description": "Plan a single change"},
+				"format":           map[string]any{"type": "string", "description": "Output format", "enum": []string{"text", "mermaid", "json"}, "default": "text"},
+				"default_estimate": map[string]any{"type": "number", "description": "Hours assumed for tasks without an estimate", "default": spec.DefaultTaskEstimate},
+			},
+			"required": []string{"path"},
+		},
+	}
+	mcp.AddTool(s, planTool, registryPlanHandler)
+
+	exportTool := &mcp.Tool{
+		Name:        "registry_export",
+		Description: "Export registry tasks as GitHub issues JSON (the shape of gh issue list --json number,title,body,state,labels,assignees), Jira CSV, or JSON Lines. Change, priority, status, task ID and dependencies are kept so the export imports back unchanged.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":      map[string]any{"type": "string", "description": "Path to project directory"},
+				"format":    map[string]any{"type": "string", "description": "Output format", "enum": []string{"github", "jira", "jsonl"}},
+				"change_id": map[string]any{"type": "string", "description": "Export a single change"},
+			},
+			"required": []string{"path", "format"},
+		},
+	}
+	mcp.AddTool(s, exportTool, registryExportHandler)
+
+	importTool := &mcp.Tool{
+		Name:        "registry_import",
+		Description: "Import tasks from GitHub issues JSON, Jira CSV, or JSON Lines. Issues are matched to tasks by task ID or by change and title; new tasks are appended to tasks.md, and status, priority, assignee and dependencies of existing tasks are updated. Returns the diff; dry_run stops there.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":      map[string]any{"type": "string", "description": "Path to project directory"},
+				"format":    map[string]any{"type": "string", "description": "Input format, guessed from the file extension when omitted", "enum": []string{"github", "jira", "jsonl"}},
+				"file":      map[string]any{"type": "string", "description": "File to import, relative to path"},
+				"content":   map[string]any{"type": "string", "description": "Data to import instead of a file"},
+				"change_id": map[string]any{"type": "string", "description": "Change for issues that do not name one"},
+				"dry_run":   map[string]any{"type": "boolean", "description": "Show the diff without writing", "default": false},
+			},
+			"required": []string{"path"},
+		},
+	}
+	mcp.AddTool(s, importTool, registryImportHandler)
+
 	syncTool := &mcp.Tool{
 		Name:        "registry_sync",
-		Description: "Synchronize registry from tasks.md files. Rebuilds registry from source change proposals.",
+		Description: "Synchronize registry from tasks.md files and back. Each task is compared with its state at the last sync: checkbox, text and dependency edits made on one side are carried to the other, and fields changed differently on both sides are reported as conflicts and settled by resolve.",
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
 				"path":    map[string]any{"type": "string", "description": "Path to project directory"},
 				"dry_run": map[string]any{"type": "boolean", "description": "Preview changes without saving", "default": false},
-				"force":   map[string]any{"type": "boolean", "description": "Force sync even if registry has local changes", "default": false},
+				"force":   map[string]any{"type": "boolean", "description": "Force sync even if registry has local changes; same as resolve=file", "default": false},
+				"resolve": map[string]any{"type": "string", "description": "Conflict resolution: file takes tasks.md, registry rewrites tasks.md, manual leaves conflicting tasks untouched", "enum": []string{"file", "registry", "manual"}, "default": "manual"},
 			},
 			"required": []string{"path"},
 		},
@@ -258,6 +363,12 @@ func registryNextHandler(ctx context.Context, req *mcp.CallToolRequest, input Re
 	// Check if registry.db exists before creating BoltDB
 	registryPath := filepath.Join(input.Path, "openspec", "registry.db")
 	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		if input.ClaimAs != "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "Registry not found. Run registry_sync before claiming tasks."}},
+			}, nil, nil
+		}
+
 		// Fallback to parsing tasks.md
 		tasks, err := parseTasksFromSource(input.Path, input.ChangeID)
 		if err != nil {
@@ -295,6 +406,23 @@ func registryNextHandler(ctx context.Context, req *mcp.CallToolRequest, input Re
 	}
 	defer func() { _ = regStore.Close() }()
 
+	if input.ClaimAs != "" {
+		task, err := regStore.ClaimNext(input.ClaimAs, input.ChangeID, time.Duration(input.TTLSeconds)*time.Second)
+		if errors.Is(err, spec.ErrNoClaimableTask) {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "No claimable task: everything is completed, blocked or leased."}},
+			}, nil, nil
+		}
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error claiming task: %v", err)}},
+			}, nil, nil
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: formatLeaseResult("Claimed", task)}},
+		}, nil, nil
+	}
+
 	count := input.Count
 	if count <= 0 {
 		count = 1
@@ -379,6 +507,313 @@ func registryUpdateHandler(ctx context.Context, req *mcp.CallToolRequest, input
 	}, nil, nil
 }
 
+func registryLeaseHandler(ctx context.Context, req *mcp.CallToolRequest, input RegistryLeaseInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if input.TaskID == "" {
+		return nil, nil, fmt.Errorf("task_id is required")
+	}
+	if input.Holder == "" {
+		return nil, nil, fmt.Errorf("holder is required")
+	}
+
+	registryPath := filepath.Join(input.Path, "openspec", "registry.db")
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Registry not found. Run registry_sync first."}},
+		}, nil, nil
+	}
+
+	taskID, err := parseTaskID(input.TaskID)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid task_id format: %v", err)}},
+		}, nil, nil
+	}
+
+	store := spec.NewStore(input.Path)
+	regStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating registry store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = regStore.Close() }()
+
+	ttl := time.Duration(input.TTLSeconds) * time.Second
+
+	var (
+		task *spec.RegistryTask
+		verb string
+	)
+	switch input.Operation {
+	case "claim":
+		task, err = regStore.ClaimTask(taskID, input.Holder, ttl)
+		verb = "Claimed"
+	case "heartbeat":
+		task, err = regStore.Heartbeat(taskID, input.Holder)
+		verb = "Extended lease on"
+	case "release":
+		task, err = regStore.ReleaseTask(taskID, input.Holder)
+		verb = "Released"
+	case "steal":
+		task, err = regStore.StealTask(taskID, input.Holder, ttl)
+		verb = "Stole"
+	default:
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown operation: %s. Use claim, heartbeat, release, or steal.", input.Operation)}},
+		}, nil, nil
+	}
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error: %v", err)}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatLeaseResult(verb, task)}},
+	}, nil, nil
+}
+
+func formatLeaseResult(verb string, task *spec.RegistryTask) string {
+	msg := fmt.Sprintf("✅ %s task %s", verb, task.ID.String())
+	if task.Lease != nil {
+		msg += fmt.Sprintf(" (held by %s until %s)", task.Lease.Holder, task.Lease.ExpiresAt.Format(time.RFC3339))
+		if task.Lease.StolenFrom != "" {
+			msg += fmt.Sprintf("; expired lease of %s taken over", task.Lease.StolenFrom)
+		}
+	}
+	data, _ := json.MarshalIndent(task, "", "  ")
+	return msg + "\n\n" + string(data)
+}
+
+func registryPlanHandler(ctx context.Context, req *mcp.CallToolRequest, input RegistryPlanInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	opts := spec.PlanOptions{
+		ChangeID:        input.ChangeID,
+		DefaultEstimate: input.DefaultEstimate,
+		Now:             time.Now(),
+	}
+
+	var (
+		plan *spec.Plan
+		note string
+	)
+
+	// Check if registry.db exists before creating BoltDB
+	registryPath := filepath.Join(input.Path, "openspec", "registry.db")
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		// Fallback to parsing tasks.md, which carries no completion history
+		tasks, err := parseTasksFromSource(input.Path, input.ChangeID)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error parsing tasks.md: %v", err)}},
+			}, nil, nil
+		}
+		plan, err = spec.BuildPlan(tasks, opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error planning tasks: %v", err)}},
+			}, nil, nil
+		}
+		note = "Note: Planned from tasks.md files (registry.db not found), no throughput history"
+	} else {
+		store := spec.NewStore(input.Path)
+		regStore, err := spec.NewRegistryStore(store)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating registry store: %v", err)}},
+			}, nil, nil
+		}
+		defer func() { _ = regStore.Close() }()
+
+		plan, err = regStore.Plan(opts)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error planning tasks: %v", err)}},
+			}, nil, nil
+		}
+	}
+
+	var text string
+	switch input.Format {
+	case "", "text":
+		text = spec.FormatPlan(plan)
+	case "mermaid":
+		text = spec.FormatPlanMermaid(plan)
+	case "json":
+		data, _ := json.MarshalIndent(plan, "", "  ")
+		text = string(data)
+	default:
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown format: %s. Use text, mermaid, or json.", input.Format)}},
+		}, nil, nil
+	}
+
+	switch {
+	case note == "":
+	case input.Format == "mermaid":
+		// Keep the diagram parseable
+		text += "%% " + note + "\n"
+	default:
+		text += "\n\n" + note
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
+
+func registryExportHandler(ctx context.Context, req *mcp.CallToolRequest, input RegistryExportInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	format, err := spec.ParseTrackerFormat(input.Format)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Unknown format: %s. Use github, jira, or jsonl.", input.Format)}},
+		}, nil, nil
+	}
+
+	// Check if registry.db exists before creating BoltDB
+	registryPath := filepath.Join(input.Path, "openspec", "registry.db")
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Registry not found. Run registry_sync first."}},
+		}, nil, nil
+	}
+
+	store := spec.NewStore(input.Path)
+	regStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating registry store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = regStore.Close() }()
+
+	tasks, err := regStore.ListTasks(spec.TaskFilter{ChangeID: input.ChangeID})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error listing tasks: %v", err)}},
+		}, nil, nil
+	}
+	registry, err := regStore.Load()
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading registry: %v", err)}},
+		}, nil, nil
+	}
+	var changes []spec.ChangeSummary
+	for id, c := range registry.Changes {
+		if input.ChangeID == "" || id == input.ChangeID {
+			changes = append(changes, c)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+
+	var buf bytes.Buffer
+	if err := spec.ExportTasks(&buf, format, changes, tasks); err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error exporting tasks: %v", err)}},
+		}, nil, nil
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: buf.String()}},
+	}, nil, nil
+}
+
+func registryImportHandler(ctx context.Context, req *mcp.CallToolRequest, input RegistryImportInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if input.File == "" && input.Content == "" {
+		return nil, nil, fmt.Errorf("file or content is required")
+	}
+
+	var (
+		format spec.TrackerFormat
+		err    error
+	)
+	switch {
+	case input.Format != "":
+		format, err = spec.ParseTrackerFormat(input.Format)
+	case input.File != "":
+		format, err = spec.TrackerFormatFromPath(input.File)
+	default:
+		err = fmt.Errorf("format is required with content")
+	}
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error: %v. Use github, jira, or jsonl.", err)}},
+		}, nil, nil
+	}
+
+	data := []byte(input.Content)
+	if input.File != "" {
+		file := input.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(input.Path, file)
+		}
+		data, err = os.ReadFile(file) // #nosec G304 -- user-selected import file
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error reading %s: %v", input.File, err)}},
+			}, nil, nil
+		}
+	}
+
+	imp, err := spec.ParseTrackerImport(bytes.NewReader(data), format)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error parsing %s import: %v", format, err)}},
+		}, nil, nil
+	}
+
+	// Check if registry.db exists before creating BoltDB
+	registryPath := filepath.Join(input.Path, "openspec", "registry.db")
+	if _, err := os.Stat(registryPath); os.IsNotExist(err) {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "Registry not found. Run registry_sync first."}},
+		}, nil, nil
+	}
+
+	store := spec.NewStore(input.Path)
+	regStore, err := spec.NewRegistryStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating registry store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = regStore.Close() }()
+
+	diff, err := regStore.DiffImport(imp, spec.ImportOptions{ChangeID: input.ChangeID})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error diffing import: %v", err)}},
+		}, nil, nil
+	}
+
+	text := diff.String()
+	if input.DryRun {
+		text += "\nDry run: nothing written."
+	} else if len(diff.Added)+len(diff.Updated) > 0 {
+		if err := regStore.ApplyImport(diff); err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error applying import: %v", err)}},
+			}, nil, nil
+		}
+		text += "\n✅ Import applied."
+	}
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: text}},
+	}, nil, nil
+}
+
 func registryDepsHandler(ctx context.Context, req *mcp.CallToolRequest, input RegistryDepsInput) (*mcp.CallToolResult, any, error) {
 	if input.Path == "" {
 		return nil, nil, fmt.Errorf("path is required")
@@ -495,14 +930,40 @@ func registrySyncHandler(ctx context.Context, req *mcp.CallToolRequest, input Re
 	}
 	defer func() { _ = regStore.Close() }()
 
-	result, err := regStore.RebuildFromSource()
+	resolve := spec.ResolveManual
+	if input.Force {
+		resolve = spec.ResolveFile
+	}
+	if input.Resolve != "" {
+		resolve, err = spec.ParseSyncResolution(input.Resolve)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Invalid resolve: %v", err)}},
+			}, nil, nil
+		}
+	}
+
+	result, err := regStore.Sync(spec.SyncOptions{Resolve: resolve, DryRun: input.DryRun})
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error syncing registry: %v", err)}},
 		}, nil, nil
 	}
 
-	msg := "✅ Registry synced from tasks.md files\n\n"
+	msg := "✅ Registry synced with tasks.md files\n\n"
+	if input.DryRun {
+		msg = "🔍 Dry run: nothing written\n\n"
+	}
+	if len(result.Conflicts) > 0 {
+		msg += fmt.Sprintf("⚠️  %d conflict(s), resolve=%s:\n", len(result.Conflicts), resolve)
+		for _, c := range result.Conflicts {
+			msg += fmt.Sprintf("- %s %s: was %q, tasks.md %q, registry %q\n", c.TaskID.String(), c.Field, c.BaseValue, c.SourceValue, c.RegistryVal)
+		}
+		if resolve == spec.ResolveManual {
+			msg += "Conflicting tasks were left unchanged. Make both sides agree, or sync again with resolve=file or resolve=registry.\n"
+		}
+		msg += "\n"
+	}
 	data, _ := json.MarshalIndent(result, "", "  ")
 	msg += string(data)
 
@@ -2,10 +2,15 @@ package tools
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
 )
 
 func TestRegistryListHandler_UsesBoltDB(t *testing.T) {
@@ -33,3 +38,189 @@ func TestRegistryListHandlerPathRequired(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "path is required")
 }
+
+func TestRegistryNextHandler_ClaimAs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte("# Tasks\n\n- [ ] Add provider\n"), 0o600))
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := registryNextHandler(ctx, &mcp.CallToolRequest{}, RegistryNextInput{Path: root, ClaimAs: "worker-1"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "✅ Claimed task add-sso/1 (held by worker-1 until")
+
+	result, _, err = registryNextHandler(ctx, &mcp.CallToolRequest{}, RegistryNextInput{Path: root, ClaimAs: "worker-2"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "No claimable task")
+
+	result, _, err = registryLeaseHandler(ctx, &mcp.CallToolRequest{}, RegistryLeaseInput{Path: root, TaskID: "add-sso/1", Operation: "steal", Holder: "worker-2"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "task is leased by another holder")
+
+	result, _, err = registryLeaseHandler(ctx, &mcp.CallToolRequest{}, RegistryLeaseInput{Path: root, TaskID: "add-sso/1", Operation: "release", Holder: "worker-1"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "✅ Released task add-sso/1")
+}
+
+func TestRegistryPlanHandler(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte(`# Tasks
+
+- [ ] Add provider <!-- estimate: 2h -->
+- [ ] Add callback <!-- depends: 1 --> <!-- estimate: 1h -->
+`), 0o600))
+
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := registryPlanHandler(ctx, &mcp.CallToolRequest{}, RegistryPlanInput{Path: root})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "critical path 3.0h")
+	assert.Contains(t, text(result), "registry.db not found")
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	result, _, err = registryPlanHandler(ctx, &mcp.CallToolRequest{}, RegistryPlanInput{Path: root, ChangeID: "add-sso", Format: "mermaid"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(text(result), "gantt\n"))
+	assert.Contains(t, text(result), ":crit, add_sso_2,")
+	assert.NotContains(t, text(result), "registry.db not found")
+
+	result, _, err = registryPlanHandler(ctx, &mcp.CallToolRequest{}, RegistryPlanInput{Path: root, Format: "svg"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Unknown format: svg")
+}
+
+func TestRegistryExportImportHandlers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n"), 0o600))
+
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := registryExportHandler(ctx, &mcp.CallToolRequest{}, RegistryExportInput{Path: root, Format: "github"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Registry not found")
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	result, _, err = registryExportHandler(ctx, &mcp.CallToolRequest{}, RegistryExportInput{Path: root, Format: "jira"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Add provider,,To Do,Medium,,change:add-sso,,,add-sso/1")
+
+	result, _, err = registryExportHandler(ctx, &mcp.CallToolRequest{}, RegistryExportInput{Path: root, Format: "trello"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Unknown format: trello")
+
+	_, _, err = registryImportHandler(ctx, &mcp.CallToolRequest{}, RegistryImportInput{Path: root})
+	require.Error(t, err)
+
+	jsonl := `{"kind":"task","task":{"id":{"change_id":"add-sso","task_num":"1"},"content":"Add provider","status":"in_progress","priority":"high"}}
+{"kind":"task","task":{"id":{"change_id":"add-sso","task_num":"9"},"content":"Add callback","status":"pending","priority":"medium","depends_on":[{"change_id":"add-sso","task_num":"1"}]}}
+`
+	input := RegistryImportInput{Path: root, Format: "jsonl", Content: jsonl, DryRun: true}
+	result, _, err = registryImportHandler(ctx, &mcp.CallToolRequest{}, input)
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "+ add-sso/2 \"Add callback\" [pending, medium] depends on add-sso/1")
+	assert.Contains(t, text(result), "~ add-sso/1 \"Add provider\": status pending → in_progress; priority medium → high")
+	assert.Contains(t, text(result), "Dry run")
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [ ] Add provider\n", string(content))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "tasks.jsonl"), []byte(jsonl), 0o600))
+	result, _, err = registryImportHandler(ctx, &mcp.CallToolRequest{}, RegistryImportInput{Path: root, File: "tasks.jsonl"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Import applied")
+
+	content, err = os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [ ] Add provider\n\n- [ ] Add callback <!-- depends: 1 -->\n", string(content))
+}
+
+func TestRegistrySyncHandlerResolve(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n"), 0o600))
+
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root, Force: true})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Registry synced")
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	require.NoError(t, reg.AddDependency(spec.TaskID{ChangeID: "add-sso", TaskNum: "2"}, spec.TaskID{ChangeID: "add-sso", TaskNum: "3"}))
+	require.NoError(t, reg.Close())
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback <!-- depends: 1 -->\n"), 0o600))
+
+	result, _, err = registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "1 conflict(s), resolve=manual")
+	assert.Contains(t, text(result), `add-sso/2 depends_on: was "", tasks.md "1", registry "3"`)
+	assert.Contains(t, text(result), "left unchanged")
+
+	result, _, err = registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root, Resolve: "registry", DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Dry run")
+	assert.Contains(t, text(result), "resolve=registry")
+
+	result, _, err = registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root, Resolve: "file"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "resolve=file")
+
+	result, _, err = registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root})
+	require.NoError(t, err)
+	assert.NotContains(t, text(result), "conflict(s)")
+
+	result, _, err = registrySyncHandler(ctx, &mcp.CallToolRequest{}, RegistrySyncInput{Path: root, Resolve: "theirs"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Invalid resolve")
+}
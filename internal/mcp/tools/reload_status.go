@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/reload"
+)
+
+type ReloadStatusInput struct{}
+
+var (
+	reloadMu      sync.RWMutex
+	reloadHistory []reload.Report
+)
+
+// reloadHistoryLimit bounds the reports kept for reload_status.
+const reloadHistoryLimit = 10
+
+func registerReloadStatus(s *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "reload_status",
+		Description: "Show recent hot reloads of skills, agents and plugins: what was swapped in and any validation errors",
+		InputSchema: map[string]any{
+			"type":       "object",
+			"properties": map[string]any{},
+		},
+	}
+
+	mcp.AddTool(s, tool, reloadStatusHandler)
+}
+
+// PublishReload records report for reload_status and tells connected
+// clients that skills, agents or plugins changed. Re-adding the
+// reload_status tool makes the SDK send notifications/tools/list_changed to
+// every session, so clients refetch tool and skill listings; sessions that
+// enabled logging also get the summary as a notifications/message.
+func PublishReload(ctx context.Context, s *mcp.Server, report reload.Report) {
+	reloadMu.Lock()
+	reloadHistory = append(reloadHistory, report)
+	if len(reloadHistory) > reloadHistoryLimit {
+		reloadHistory = reloadHistory[len(reloadHistory)-reloadHistoryLimit:]
+	}
+	reloadMu.Unlock()
+
+	registerReloadStatus(s)
+
+	level := mcp.LoggingLevel("info")
+	if len(report.Errors) > 0 {
+		level = "warning"
+	}
+	for ss := range s.Sessions() {
+		_ = ss.Log(ctx, &mcp.LoggingMessageParams{
+			Level:  level,
+			Logger: "go-ent.reload",
+			Data:   report.Summary(),
+		})
+	}
+}
+
+func reloadStatusHandler(ctx context.Context, req *mcp.CallToolRequest, input ReloadStatusInput) (*mcp.CallToolResult, any, error) {
+	reloadMu.RLock()
+	history := make([]reload.Report, len(reloadHistory))
+	copy(history, reloadHistory)
+	reloadMu.RUnlock()
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatReloadHistory(history)}},
+	}, nil, nil
+}
+
+func formatReloadHistory(history []reload.Report) string {
+	if len(history) == 0 {
+		return "No hot reloads since the server started.\n"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("# Hot Reloads (%d)\n\n", len(history)))
+	for i := len(history) - 1; i >= 0; i-- {
+		r := history[i]
+		sb.WriteString(fmt.Sprintf("## %s — %s\n\n", r.Time.Format("2006-01-02 15:04:05"), r.Summary()))
+		writeReloadNames(&sb, "", "Skills added", r.SkillsAdded)
+		writeReloadNames(&sb, "", "Skills changed", r.SkillsChanged)
+		writeReloadNames(&sb, "", "Skills removed", r.SkillsRemoved)
+		for _, p := range r.Plugins {
+			switch {
+			case p.Removed:
+				sb.WriteString(fmt.Sprintf("- Plugin **%s** removed\n", p.Plugin))
+			case p.Added:
+				sb.WriteString(fmt.Sprintf("- Plugin **%s** discovered (disabled)\n", p.Plugin))
+			default:
+				sb.WriteString(fmt.Sprintf("- Plugin **%s** reloaded\n", p.Plugin))
+				writeReloadNames(&sb, "  ", "Skills", p.Skills)
+				writeReloadNames(&sb, "  ", "Agents", p.Agents)
+				writeReloadNames(&sb, "  ", "Rules", p.Rules)
+			}
+		}
+		for _, e := range r.Errors {
+			sb.WriteString("- ❌ " + e + "\n")
+		}
+		for _, w := range r.Warnings {
+			sb.WriteString("- ⚠️ " + w + "\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+func writeReloadNames(sb *strings.Builder, indent, label string, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	sb.WriteString(fmt.Sprintf("%s- %s: %s\n", indent, label, strings.Join(names, ", ")))
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/plugin"
+	"github.com/victorzhuk/go-ent/internal/reload"
+)
+
+func TestPublishReload_NotifiesSessions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	registerReloadStatus(s)
+
+	listChanged := make(chan struct{}, 1)
+	logged := make(chan any, 1)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			select {
+			case listChanged <- struct{}{}:
+			default:
+			}
+		},
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			select {
+			case logged <- req.Params.Data:
+			default:
+			}
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = ss.Close() }()
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	require.NoError(t, err)
+	defer func() { _ = cs.Close() }()
+	require.NoError(t, cs.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: "info"}))
+
+	report := reload.Report{
+		Time:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		SkillsAdded: []string{"go-test"},
+		Plugins:     []plugin.ReloadResult{{Plugin: "acme", Skills: []string{"acme-skill"}, Rules: []string{"no-todo"}}},
+		Errors:      []string{"skills/bad/SKILL.md: [error] parse: missing frontmatter"},
+	}
+	PublishReload(ctx, s, report)
+
+	select {
+	case <-listChanged:
+	case <-ctx.Done():
+		t.Fatal("no tools/list_changed notification")
+	}
+	select {
+	case data := <-logged:
+		assert.Equal(t, report.Summary(), data)
+	case <-ctx.Done():
+		t.Fatal("no log notification")
+	}
+
+	result, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "reload_status"})
+	require.NoError(t, err)
+	text := result.Content[0].(*mcp.TextContent).Text
+	assert.Contains(t, text, "2026-01-02 03:04:05 — skills +1 ~0 -0; plugin acme: 1 skill(s), 0 agent(s), 1 rule(s); 1 error(s)")
+	assert.Contains(t, text, "- Skills added: go-test")
+	assert.Contains(t, text, "  - Rules: no-todo")
+	assert.Contains(t, text, "❌ skills/bad/SKILL.md")
+}
+
+func TestFormatReloadHistory_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "No hot reloads since the server started.\n", formatReloadHistory(nil))
+}
@@ -4,14 +4,16 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/spec"
 )
 
 type SpecShowInput struct {
-	Type string `json:"type"`
-	ID   string `json:"id"`
+	Type        string `json:"type"`
+	ID          string `json:"id"`
+	Requirement string `json:"requirement,omitempty"`
 }
 
 func registerShow(s *mcp.Server) {
@@ -30,14 +32,17 @@ func registerShow(s *mcp.Server) {
 					"type":        "string",
 					"description": "Identifier of the item to show",
 				},
+				"requirement": map[string]any{
+					"type":        "string",
+					"description": "For specs: show only this requirement",
+				},
 			},
 			"required": []string{"type", "id"},
 		},
 	}
 
 	baseHandler := specShowHandler
-	handler := WithMetrics[SpecShowInput, any]("spec_show", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func specShowHandler(ctx context.Context, req *mcp.CallToolRequest, input SpecShowInput) (*mcp.CallToolResult, any, error) {
@@ -86,9 +91,45 @@ func specShowHandler(ctx context.Context, req *mcp.CallToolRequest, input SpecSh
 		}, nil, nil
 	}
 
+	if input.Type == "spec" {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: formatSpecShow(input, content)}},
+		}, nil, nil
+	}
+
 	msg := fmt.Sprintf("# %s: %s\n\n```markdown\n%s\n```", input.Type, input.ID, content)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
 	}, nil, nil
 }
+
+// formatSpecShow prints a requirement outline followed by the spec source,
+// or only the requested requirement.
+func formatSpecShow(input SpecShowInput, content string) string {
+	doc := spec.ParseDocument(content)
+
+	if input.Requirement != "" {
+		req := doc.Requirement(input.Requirement)
+		if req == nil {
+			return fmt.Sprintf("Requirement not found in spec %s: %s", input.ID, input.Requirement)
+		}
+		return fmt.Sprintf("# spec: %s — %s (line %d)\n\n```markdown\n%s\n```",
+			input.ID, req.Name, req.Line, strings.TrimRight(req.String(), "\n"))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# spec: %s\n\n", input.ID)
+
+	reqs := doc.Requirements()
+	fmt.Fprintf(&sb, "## Outline (%d requirement(s))\n\n", len(reqs))
+	for _, req := range reqs {
+		fmt.Fprintf(&sb, "- %s (line %d)\n", req.Name, req.Line)
+		for _, sc := range req.Scenarios {
+			fmt.Fprintf(&sb, "  - Scenario: %s (%d step(s))\n", sc.Name, len(sc.Steps))
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n```markdown\n%s\n```", content)
+	return sb.String()
+}
@@ -0,0 +1,24 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSpecShow(t *testing.T) {
+	t.Parallel()
+
+	content := "# Auth\n\n### Requirement: Login\n\n#### Scenario: Valid\n- **WHEN** valid\n- **THEN** ok\n\n### Requirement: Logout\nNo scenarios yet.\n"
+
+	out := formatSpecShow(SpecShowInput{Type: "spec", ID: "auth"}, content)
+	assert.Contains(t, out, "## Outline (2 requirement(s))")
+	assert.Contains(t, out, "- Login (line 3)\n  - Scenario: Valid (2 step(s))\n- Logout (line 9)\n")
+	assert.Contains(t, out, "```markdown\n"+content+"\n```")
+
+	out = formatSpecShow(SpecShowInput{Type: "spec", ID: "auth", Requirement: "Logout"}, content)
+	assert.Equal(t, "# spec: auth — Logout (line 9)\n\n```markdown\n### Requirement: Logout\nNo scenarios yet.\n```", out)
+
+	out = formatSpecShow(SpecShowInput{Type: "spec", ID: "auth", Requirement: "Missing"}, content)
+	assert.Equal(t, "Requirement not found in spec auth: Missing", out)
+}
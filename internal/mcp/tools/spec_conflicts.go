@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+type SpecConflictsInput struct {
+	Path   string `json:"path"`
+	Change string `json:"change,omitempty"`
+}
+
+func registerSpecConflicts(s *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "spec_conflicts",
+		Description: "Detect pending changes whose delta specs touch the same requirement. Classifies overlaps (modify/modify, modify/remove, rename-chain) and lists what blocks archiving a change.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{
+					"type":        "string",
+					"description": "Path to the project directory containing openspec folder",
+				},
+				"change": map[string]any{
+					"type":        "string",
+					"description": "Only report conflicts involving this change, with its archive blockers",
+				},
+			},
+			"required": []string{"path"},
+		},
+	}
+
+	mcp.AddTool(s, tool, specConflictsHandler)
+}
+
+func specConflictsHandler(ctx context.Context, req *mcp.CallToolRequest, input SpecConflictsInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	store := spec.NewStore(input.Path)
+
+	exists, err := store.Exists()
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error checking spec folder: %v", err)}},
+		}, nil, nil
+	}
+
+	if !exists {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No openspec folder found. Run spec_init first."}},
+		}, nil, nil
+	}
+
+	report, err := spec.DetectConflicts(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error detecting conflicts: %v", err)}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatConflictReport(report, input.Change)}},
+	}, nil, nil
+}
+
+func formatConflictReport(report *spec.ConflictReport, changeID string) string {
+	var sb strings.Builder
+
+	conflicts := report.Conflicts
+	if changeID != "" {
+		conflicts = report.ForChange(changeID)
+	}
+
+	if len(conflicts) == 0 {
+		if changeID != "" {
+			sb.WriteString(fmt.Sprintf("✅ No conflicts for '%s'\n", changeID))
+		} else {
+			sb.WriteString(fmt.Sprintf("✅ No conflicts across %d pending change(s)\n", len(report.Changes)))
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Found %d conflict(s):\n\n", len(conflicts)))
+	for _, c := range conflicts {
+		icon := "❌"
+		if c.Resolved {
+			icon = "✓"
+		}
+		sb.WriteString(fmt.Sprintf("%s %s\n", icon, c.String()))
+		for _, t := range c.Touches {
+			sb.WriteString(fmt.Sprintf("    - %s\n", t.String()))
+		}
+	}
+
+	if changeID != "" {
+		blockers := report.Blockers(changeID)
+		if len(blockers) == 0 {
+			sb.WriteString(fmt.Sprintf("\n'%s' can be archived.\n", changeID))
+		} else {
+			sb.WriteString(fmt.Sprintf("\n'%s' is blocked:\n", changeID))
+			for _, b := range blockers {
+				sb.WriteString(fmt.Sprintf("  - %s\n", b))
+			}
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+type SpecTraceInput struct {
+	Path    string `json:"path"`
+	Format  string `json:"format,omitempty"`
+	Output  string `json:"output,omitempty"`
+	SkipGit bool   `json:"skip_git,omitempty"`
+}
+
+func registerSpecTrace(s *mcp.Server) {
+	tool := &mcp.Tool{
+		Name:        "spec_trace",
+		Description: "Build a requirement-to-code traceability matrix from `// spec: capability/Requirement` annotations, symbols and tests named after requirements, and commits mentioning task IDs. Reports untested requirements, orphan tests and stale references.",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{
+					"type":        "string",
+					"description": "Path to the project directory containing openspec folder",
+				},
+				"format": map[string]any{
+					"type":        "string",
+					"description": "Report format: markdown or json",
+					"enum":        []string{"markdown", "json"},
+					"default":     "markdown",
+				},
+				"output": map[string]any{
+					"type":        "string",
+					"description": "Optional file to write the report to, relative to path",
+				},
+				"skip_git": map[string]any{
+					"type":        "boolean",
+					"description": "Do not read task-to-commit links from git history",
+					"default":     false,
+				},
+			},
+			"required": []string{"path"},
+		},
+	}
+
+	mcp.AddTool(s, tool, specTraceHandler)
+}
+
+func specTraceHandler(ctx context.Context, req *mcp.CallToolRequest, input SpecTraceInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	store := spec.NewStore(input.Path)
+
+	exists, err := store.Exists()
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error checking spec folder: %v", err)}},
+		}, nil, nil
+	}
+
+	if !exists {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No openspec folder found. Run spec_init first."}},
+		}, nil, nil
+	}
+
+	matrix, err := spec.BuildTrace(store, spec.TraceOptions{SkipGit: input.SkipGit})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error building trace: %v", err)}},
+		}, nil, nil
+	}
+
+	report, err := formatTraceReport(matrix, input.Format)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+		}, nil, nil
+	}
+
+	if input.Output != "" {
+		outPath := filepath.Join(input.Path, input.Output)
+		if err := os.WriteFile(outPath, []byte(report), 0600); err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error writing report: %v", err)}},
+			}, nil, nil
+		}
+		report = fmt.Sprintf("Wrote traceability report to %s\n\n%s", outPath, report)
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: report}},
+	}, nil, nil
+}
+
+func formatTraceReport(matrix *spec.TraceMatrix, format string) (string, error) {
+	switch format {
+	case "", "markdown":
+		return matrix.Markdown(), nil
+	case "json":
+		data, err := json.MarshalIndent(matrix, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("marshal trace: %w", err)
+		}
+		return string(data) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown format: %s. Must be markdown or json", format)
+	}
+}
@@ -128,34 +128,32 @@ func stateSyncHandler(ctx context.Context, req *mcp.CallToolRequest, input State
 		}, nil, nil
 	}
 
-	bolt, err := spec.NewBoltStore(boltPath)
+	regStore, err := spec.NewRegistryStore(store)
 	if err != nil {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating bolt store: %v", err)}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error creating registry store: %v", err)}},
 		}, nil, nil
 	}
-	defer func() { _ = bolt.Close() }()
-
-	stateStore := spec.NewStateStore(store, bolt)
+	defer func() { _ = regStore.Close() }()
 
-	if err := stateStore.SyncFromTasksMd(); err != nil {
+	syncResult, err := regStore.Sync(spec.SyncOptions{DryRun: input.DryRun})
+	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error syncing from tasks.md: %v", err)}},
 		}, nil, nil
 	}
-
-	changes, err := bolt.ListChanges()
-	if err != nil {
+	if input.DryRun {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error listing changes: %v", err)}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Dry run: would sync %d changes and regenerate state.md files\n", len(syncResult.SyncedChanges))}},
 		}, nil, nil
 	}
 
-	for _, change := range changes {
-		changeStatePath := filepath.Join(input.Path, "openspec", "changes", change.ID, "state.md")
-		if err := stateStore.WriteChangeStateMd(change.ID, changeStatePath); err != nil {
+	stateStore := regStore.StateStore()
+	for _, changeID := range syncResult.SyncedChanges {
+		changeStatePath := filepath.Join(input.Path, "openspec", "changes", changeID, "state.md")
+		if err := stateStore.WriteChangeStateMd(changeID, changeStatePath); err != nil {
 			return &mcp.CallToolResult{
-				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error writing state for %s: %v", change.ID, err)}},
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error writing state for %s: %v", changeID, err)}},
 			}, nil, nil
 		}
 	}
@@ -167,9 +165,12 @@ func stateSyncHandler(ctx context.Context, req *mcp.CallToolRequest, input State
 		}, nil, nil
 	}
 
-	result := fmt.Sprintf("✓ Synced %d changes\n", len(changes))
+	result := fmt.Sprintf("✓ Synced %d changes\n", len(syncResult.SyncedChanges))
 	result += "✓ Generated state.md files\n"
 	result += "✓ BoltDB registry updated\n"
+	if len(syncResult.Conflicts) > 0 {
+		result += fmt.Sprintf("⚠️  %d conflicting task(s) left unchanged: run registry_sync to resolve them\n", len(syncResult.Conflicts))
+	}
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: result}},
@@ -0,0 +1,73 @@
+package tools
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+func TestStateSyncHandlerKeepsRegistryState(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n"), 0o600))
+
+	reg, err := spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	id := spec.TaskID{ChangeID: "add-sso", TaskNum: "2"}
+	high := spec.PriorityHigh
+	require.NoError(t, reg.UpdateTask(id, spec.TaskUpdate{Priority: &high}))
+	_, err = reg.ClaimTask(id, "worker-1", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, reg.Close())
+
+	// The first task is ticked by hand and a task is inserted above both
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add config\n- [x] Add provider\n- [ ] Add callback\n"), 0o600))
+
+	result, _, err := stateSyncHandler(context.Background(), &mcp.CallToolRequest{}, StateSyncInput{Path: root, DryRun: true})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Dry run")
+	_, err = os.Stat(filepath.Join(changeDir, "state.md"))
+	assert.True(t, os.IsNotExist(err), "a dry run writes nothing")
+
+	result, _, err = stateSyncHandler(context.Background(), &mcp.CallToolRequest{}, StateSyncInput{Path: root})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "✓ Synced 1 changes")
+
+	reg, err = spec.NewRegistryStore(spec.NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+
+	task, err := reg.GetTask(spec.TaskID{ChangeID: "add-sso", TaskNum: "2"})
+	require.NoError(t, err)
+	assert.Equal(t, spec.RegStatusCompleted, task.Status)
+	task, err = reg.GetTask(spec.TaskID{ChangeID: "add-sso", TaskNum: "3"})
+	require.NoError(t, err)
+	assert.Equal(t, "Add callback", task.Content)
+	assert.Equal(t, spec.PriorityHigh, task.Priority)
+	require.NotNil(t, task.Lease)
+	assert.Equal(t, "worker-1", task.Lease.Holder)
+
+	sync, err := reg.Sync(spec.SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, sync.Updated, "state_sync records the sync bases")
+	assert.Empty(t, sync.Renumbered)
+
+	content, err := os.ReadFile(filepath.Join(changeDir, "state.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "worker-1")
+}
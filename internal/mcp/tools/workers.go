@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/victorzhuk/go-ent/internal/breaker"
 	"github.com/victorzhuk/go-ent/internal/config"
 	"github.com/victorzhuk/go-ent/internal/execution"
 	"github.com/victorzhuk/go-ent/internal/router"
@@ -125,6 +126,9 @@ type ProviderInfo struct {
 	Cost         string   `json:"cost,omitempty"`
 	ContextLimit int      `json:"context_limit,omitempty"`
 	Health       string   `json:"health"`
+	Circuit      string   `json:"circuit"`
+	Failures     int      `json:"failures,omitempty"`
+	LastError    string   `json:"last_error,omitempty"`
 }
 
 type ProviderListResponse struct {
@@ -139,6 +143,8 @@ type ProviderRecommendInput struct {
 	ContextSize int      `json:"context_size,omitempty"`
 	Complexity  string   `json:"complexity,omitempty"`
 	Priority    string   `json:"priority,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	AgentRole   string   `json:"agent_role,omitempty"`
 }
 
 type ProviderAlternative struct {
@@ -194,8 +200,7 @@ func registerWorkerSpawn(s *mcp.Server, manager *worker.WorkerManager, providerC
 	}
 
 	baseHandler := makeWorkerSpawnHandler(manager, providerConfig)
-	handler := WithMetrics[WorkerSpawnInput, any]("worker_spawn", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerWorkerPrompt(s *mcp.Server, manager *worker.WorkerManager) {
@@ -233,8 +238,7 @@ func registerWorkerPrompt(s *mcp.Server, manager *worker.WorkerManager) {
 	}
 
 	baseHandler := makeWorkerPromptHandler(manager)
-	handler := WithMetrics[WorkerPromptInput, any]("worker_prompt", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerWorkerStatus(s *mcp.Server, manager *worker.WorkerManager) {
@@ -254,8 +258,7 @@ func registerWorkerStatus(s *mcp.Server, manager *worker.WorkerManager) {
 	}
 
 	baseHandler := makeWorkerStatusHandler(manager)
-	handler := WithMetrics[WorkerStatusInput, any]("worker_status", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerWorkerOutput(s *mcp.Server, manager *worker.WorkerManager) {
@@ -288,8 +291,7 @@ func registerWorkerOutput(s *mcp.Server, manager *worker.WorkerManager) {
 	}
 
 	baseHandler := makeWorkerOutputHandler(manager)
-	handler := WithMetrics[WorkerOutputInput, any]("worker_output", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerWorkerCancel(s *mcp.Server, manager *worker.WorkerManager) {
@@ -313,8 +315,7 @@ func registerWorkerCancel(s *mcp.Server, manager *worker.WorkerManager) {
 	}
 
 	baseHandler := makeWorkerCancelHandler(manager)
-	handler := WithMetrics[WorkerCancelInput, any]("worker_cancel", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerWorkerList(s *mcp.Server, manager *worker.WorkerManager) {
@@ -342,8 +343,7 @@ func registerWorkerList(s *mcp.Server, manager *worker.WorkerManager) {
 	}
 
 	baseHandler := makeWorkerListHandler(manager)
-	handler := WithMetrics[WorkerListInput, any]("worker_list", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func registerProviderList(s *mcp.Server, providerConfig *config.ProvidersConfig) {
@@ -355,7 +355,7 @@ func registerProviderList(s *mcp.Server, providerConfig *config.ProvidersConfig)
 			"properties": map[string]any{
 				"active_only": map[string]any{
 					"type":        "boolean",
-					"description": "Only show active/healthy providers",
+					"description": "Only show providers whose circuit breaker is not open",
 				},
 				"method": map[string]any{
 					"type":        "string",
@@ -367,8 +367,7 @@ func registerProviderList(s *mcp.Server, providerConfig *config.ProvidersConfig)
 	}
 
 	baseHandler := makeProviderListHandler(providerConfig)
-	handler := WithMetrics[ProviderListInput, any]("provider_list", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func makeWorkerPromptHandler(manager *worker.WorkerManager) func(context.Context, *mcp.CallToolRequest, WorkerPromptInput) (*mcp.CallToolResult, any, error) {
@@ -979,15 +978,25 @@ func makeProviderListHandler(providerConfig *config.ProvidersConfig) func(contex
 
 		var result []ProviderInfo
 		total := len(providerConfig.Providers)
+		active := 0
 
 		for name, provider := range providerConfig.Providers {
 			if input.Method != "" && provider.Method != methodFilter {
 				continue
 			}
 
+			circuit, recorded := providerCircuit(name, provider.Provider)
+			if circuit.State == breaker.StateOpen {
+				if input.ActiveOnly {
+					continue
+				}
+			} else {
+				active++
+			}
+
 			health := "unknown"
-			if input.ActiveOnly && health != "healthy" {
-				continue
+			if recorded {
+				health = circuitHealth(circuit.State)
 			}
 
 			cost := ""
@@ -1012,13 +1021,14 @@ func makeProviderListHandler(providerConfig *config.ProvidersConfig) func(contex
 				Cost:         cost,
 				ContextLimit: provider.ContextLimit,
 				Health:       health,
+				Circuit:      string(circuit.State),
+				Failures:     circuit.Failures,
+				LastError:    circuit.LastError,
 			}
 
 			result = append(result, providerInfo)
 		}
 
-		active := len(result)
-
 		response := ProviderListResponse{
 			Providers: result,
 			Total:     total,
@@ -1051,6 +1061,45 @@ func makeProviderListHandler(providerConfig *config.ProvidersConfig) func(contex
 	}
 }
 
+// providerCircuit returns the breaker snapshot for a configured provider,
+// taking whichever of its own and its vendor's breakers is worse. The bool
+// reports whether any outcome has been recorded for either.
+func providerCircuit(name, vendor string) (breaker.Snapshot, bool) {
+	reg := breaker.Default()
+	snap, recorded := reg.Lookup(name)
+	if vendor != "" && vendor != name {
+		if v, ok := reg.Lookup(vendor); ok && (!recorded || circuitRank(v.State) > circuitRank(snap.State)) {
+			snap, recorded = v, true
+		}
+	}
+	if !recorded {
+		snap.State = breaker.StateClosed
+	}
+	return snap, recorded
+}
+
+func circuitRank(state breaker.State) int {
+	switch state {
+	case breaker.StateOpen:
+		return 2
+	case breaker.StateHalfOpen:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func circuitHealth(state breaker.State) string {
+	switch state {
+	case breaker.StateOpen:
+		return "unhealthy"
+	case breaker.StateHalfOpen:
+		return "recovering"
+	default:
+		return "healthy"
+	}
+}
+
 func registerProviderRecommend(s *mcp.Server, providerConfig *config.ProvidersConfig) {
 	tool := &mcp.Tool{
 		Name:        "provider_recommend",
@@ -1081,14 +1130,22 @@ func registerProviderRecommend(s *mcp.Server, providerConfig *config.ProvidersCo
 					"enum":        []any{"low", "medium", "high"},
 					"description": "Task priority",
 				},
+				"labels": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "OpenSpec change labels matched by routing rules",
+				},
+				"agent_role": map[string]any{
+					"type":        "string",
+					"description": "Agent role the task runs for, matched by routing rules",
+				},
 			},
 			"required": []string{"task"},
 		},
 	}
 
 	baseHandler := makeProviderRecommendHandler(providerConfig)
-	handler := WithMetrics[ProviderRecommendInput, any]("provider_recommend", baseHandler)
-	mcp.AddTool(s, tool, handler)
+	mcp.AddTool(s, tool, baseHandler)
 }
 
 func makeProviderRecommendHandler(providerConfig *config.ProvidersConfig) func(context.Context, *mcp.CallToolRequest, ProviderRecommendInput) (*mcp.CallToolResult, any, error) {
@@ -1125,6 +1182,14 @@ func makeProviderRecommendHandler(providerConfig *config.ProvidersConfig) func(c
 			task.WithMetadata("priority", input.Priority)
 		}
 
+		if len(input.Labels) > 0 {
+			task.WithMetadata(router.MetadataLabels, input.Labels)
+		}
+
+		if input.AgentRole != "" {
+			task.WithMetadata(router.MetadataAgentRole, input.AgentRole)
+		}
+
 		workerConfig := &worker.Config{
 			Providers: make(map[string]worker.ProviderDefinition),
 		}
@@ -4,29 +4,57 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/victorzhuk/go-ent/internal/spec"
 )
 
 type WorkflowStartInput struct {
-	Path     string                 `json:"path"`
-	ChangeID string                 `json:"change_id"`
-	Phase    string                 `json:"phase"`
-	Context  map[string]interface{} `json:"context,omitempty"`
+	Path       string                 `json:"path"`
+	ChangeID   string                 `json:"change_id"`
+	Phase      string                 `json:"phase,omitempty"`
+	Definition string                 `json:"definition,omitempty"`
+	Context    map[string]interface{} `json:"context,omitempty"`
 }
 
 type WorkflowApproveInput struct {
-	Path string `json:"path"`
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	ChangeID   string `json:"change_id,omitempty"`
+	Approver   string `json:"approver"`
 }
 
 type WorkflowStatusInput struct {
-	Path string `json:"path"`
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	ChangeID   string `json:"change_id,omitempty"`
 }
 
 type WorkflowWaitInput struct {
-	Path      string `json:"path"`
-	WaitPoint string `json:"wait_point"`
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	ChangeID   string `json:"change_id,omitempty"`
+	WaitPoint  string `json:"wait_point"`
+}
+
+type WorkflowTransitionInput struct {
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	ChangeID   string `json:"change_id,omitempty"`
+	To         string `json:"to"`
+}
+
+type WorkflowListInput struct {
+	Path     string `json:"path"`
+	ChangeID string `json:"change_id,omitempty"`
+	All      bool   `json:"all,omitempty"`
+}
+
+type WorkflowCancelInput struct {
+	Path       string `json:"path"`
+	WorkflowID string `json:"workflow_id,omitempty"`
+	ChangeID   string `json:"change_id,omitempty"`
 }
 
 func registerWorkflow(s *mcp.Server) {
@@ -36,12 +64,13 @@ func registerWorkflow(s *mcp.Server) {
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"path":      map[string]any{"type": "string", "description": "Path to project directory"},
-				"change_id": map[string]any{"type": "string", "description": "Change ID to work on"},
-				"phase":     map[string]any{"type": "string", "description": "Workflow phase to execute"},
-				"context":   map[string]any{"type": "object", "description": "Additional context for the workflow"},
+				"path":       map[string]any{"type": "string", "description": "Path to project directory"},
+				"change_id":  map[string]any{"type": "string", "description": "Change ID to work on"},
+				"phase":      map[string]any{"type": "string", "description": "Workflow phase to start in (defaults to the definition's initial phase)"},
+				"definition": map[string]any{"type": "string", "description": "Workflow definition name from .go-ent/workflows (default: default)"},
+				"context":    map[string]any{"type": "object", "description": "Additional context for the workflow"},
 			},
-			"required": []string{"path", "change_id", "phase"},
+			"required": []string{"path", "change_id"},
 		},
 	}
 	mcp.AddTool(s, startTool, workflowStartHandler)
@@ -52,9 +81,12 @@ func registerWorkflow(s *mcp.Server) {
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"path": map[string]any{"type": "string", "description": "Path to project directory"},
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"workflow_id": map[string]any{"type": "string", "description": "Workflow ID (optional when only one workflow is active)"},
+				"change_id":   map[string]any{"type": "string", "description": "Select the active workflow of this change"},
+				"approver":    map[string]any{"type": "string", "description": "Name of the approver; each approver counts once per phase"},
 			},
-			"required": []string{"path"},
+			"required": []string{"path", "approver"},
 		},
 	}
 	mcp.AddTool(s, approveTool, workflowApproveHandler)
@@ -65,7 +97,9 @@ func registerWorkflow(s *mcp.Server) {
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"path": map[string]any{"type": "string", "description": "Path to project directory"},
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"workflow_id": map[string]any{"type": "string", "description": "Workflow ID"},
+				"change_id":   map[string]any{"type": "string", "description": "Select the active workflow of this change"},
 			},
 			"required": []string{"path"},
 		},
@@ -78,13 +112,61 @@ func registerWorkflow(s *mcp.Server) {
 		InputSchema: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
-				"path":       map[string]any{"type": "string", "description": "Path to project directory"},
-				"wait_point": map[string]any{"type": "string", "description": "Name of the wait point (e.g., 'user-clarification')"},
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"workflow_id": map[string]any{"type": "string", "description": "Workflow ID (optional when only one workflow is active)"},
+				"change_id":   map[string]any{"type": "string", "description": "Select the active workflow of this change"},
+				"wait_point":  map[string]any{"type": "string", "description": "Name of the wait point (e.g., 'user-clarification')"},
 			},
 			"required": []string{"path", "wait_point"},
 		},
 	}
 	mcp.AddTool(s, waitTool, workflowWaitHandler)
+
+	transitionTool := &mcp.Tool{
+		Name:        "workflow_transition",
+		Description: "Move a workflow to another phase, validated against its definition's allowed transitions and approvals",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"workflow_id": map[string]any{"type": "string", "description": "Workflow ID (optional when only one workflow is active)"},
+				"change_id":   map[string]any{"type": "string", "description": "Select the active workflow of this change"},
+				"to":          map[string]any{"type": "string", "description": "Target phase"},
+			},
+			"required": []string{"path", "to"},
+		},
+	}
+	mcp.AddTool(s, transitionTool, workflowTransitionHandler)
+
+	listTool := &mcp.Tool{
+		Name:        "workflow_list",
+		Description: "List active workflows across all changes",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":      map[string]any{"type": "string", "description": "Path to project directory"},
+				"change_id": map[string]any{"type": "string", "description": "Filter by change ID"},
+				"all":       map[string]any{"type": "boolean", "description": "Include completed and cancelled workflows", "default": false},
+			},
+			"required": []string{"path"},
+		},
+	}
+	mcp.AddTool(s, listTool, workflowListHandler)
+
+	cancelTool := &mcp.Tool{
+		Name:        "workflow_cancel",
+		Description: "Cancel an active workflow",
+		InputSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":        map[string]any{"type": "string", "description": "Path to project directory"},
+				"workflow_id": map[string]any{"type": "string", "description": "Workflow ID (optional when only one workflow is active)"},
+				"change_id":   map[string]any{"type": "string", "description": "Select the active workflow of this change"},
+			},
+			"required": []string{"path"},
+		},
+	}
+	mcp.AddTool(s, cancelTool, workflowCancelHandler)
 }
 
 func workflowStartHandler(ctx context.Context, req *mcp.CallToolRequest, input WorkflowStartInput) (*mcp.CallToolResult, any, error) {
@@ -94,39 +176,49 @@ func workflowStartHandler(ctx context.Context, req *mcp.CallToolRequest, input W
 	if input.ChangeID == "" {
 		return nil, nil, fmt.Errorf("change_id is required")
 	}
-	if input.Phase == "" {
-		return nil, nil, fmt.Errorf("phase is required")
-	}
 
 	store := spec.NewStore(input.Path)
 
-	if store.WorkflowExists() {
-		existing, err := store.LoadWorkflow()
-		if err == nil && existing.Status == spec.WorkflowStatusActive {
-			return &mcp.CallToolResult{
-				Content: []mcp.Content{&mcp.TextContent{
-					Text: fmt.Sprintf("Workflow already active for change %s. Use workflow_status to check or cancel first.", existing.ChangeID),
-				}},
-			}, nil, nil
-		}
+	def, err := store.LoadWorkflowDefinition(input.Definition)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow definition: %v", err)}},
+		}, nil, nil
 	}
 
-	workflow := spec.NewWorkflowState(input.ChangeID, input.Phase)
-	if input.Context != nil {
-		workflow.Context = input.Context
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
+		}, nil, nil
 	}
+	defer func() { _ = wfStore.Close() }()
 
-	if err := store.SaveWorkflow(workflow); err != nil {
+	workflow, err := wfStore.Start(def, input.ChangeID, input.Phase)
+	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error starting workflow: %v", err)}},
 		}, nil, nil
 	}
 
+	if input.Context != nil {
+		for k, v := range input.Context {
+			workflow.Context[k] = v
+		}
+		if err := wfStore.Save(workflow); err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error starting workflow: %v", err)}},
+			}, nil, nil
+		}
+	}
+
 	msg := "✅ Workflow started\n\n"
 	msg += fmt.Sprintf("ID: %s\n", workflow.ID)
 	msg += fmt.Sprintf("Change: %s\n", workflow.ChangeID)
+	msg += fmt.Sprintf("Definition: %s\n", workflow.Definition)
 	msg += fmt.Sprintf("Phase: %s\n", workflow.Phase)
 	msg += fmt.Sprintf("Status: %s\n", workflow.Status)
+	msg += formatWorkflowNext(def, workflow)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
@@ -137,16 +229,20 @@ func workflowApproveHandler(ctx context.Context, req *mcp.CallToolRequest, input
 	if input.Path == "" {
 		return nil, nil, fmt.Errorf("path is required")
 	}
+	if input.Approver == "" {
+		return nil, nil, fmt.Errorf("approver is required")
+	}
 
 	store := spec.NewStore(input.Path)
-
-	if !store.WorkflowExists() {
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: "No active workflow found"}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
 		}, nil, nil
 	}
+	defer func() { _ = wfStore.Close() }()
 
-	workflow, err := store.LoadWorkflow()
+	workflow, err := wfStore.Resolve(input.WorkflowID, input.ChangeID)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow: %v", err)}},
@@ -161,17 +257,40 @@ func workflowApproveHandler(ctx context.Context, req *mcp.CallToolRequest, input
 		}, nil, nil
 	}
 
-	workflow.Approve()
+	def, err := store.LoadWorkflowDefinition(workflow.Definition)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow definition: %v", err)}},
+		}, nil, nil
+	}
+
+	remaining, err := workflow.AddApproval(def, input.Approver)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error approving workflow: %v", err)}},
+		}, nil, nil
+	}
 
-	if err := store.SaveWorkflow(workflow); err != nil {
+	if err := wfStore.Save(workflow); err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error approving workflow: %v", err)}},
 		}, nil, nil
 	}
 
+	if remaining > 0 {
+		msg := "✅ Approval recorded\n\n"
+		msg += fmt.Sprintf("Phase: %s\n", workflow.Phase)
+		msg += fmt.Sprintf("Approvals: %s\n", strings.Join(workflow.Approvals, ", "))
+		msg += fmt.Sprintf("Still waiting for %d more approval(s)\n", remaining)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: msg}},
+		}, nil, nil
+	}
+
 	msg := "✅ Wait point approved\n\n"
 	msg += fmt.Sprintf("Phase: %s\n", workflow.Phase)
 	msg += fmt.Sprintf("Status: %s (ready to continue)\n", workflow.Status)
+	msg += formatWorkflowNext(def, workflow)
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
@@ -184,14 +303,35 @@ func workflowStatusHandler(ctx context.Context, req *mcp.CallToolRequest, input
 	}
 
 	store := spec.NewStore(input.Path)
-
-	if !store.WorkflowExists() {
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: "No workflow found"}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
 		}, nil, nil
 	}
+	defer func() { _ = wfStore.Close() }()
 
-	workflow, err := store.LoadWorkflow()
+	if input.WorkflowID == "" {
+		active, err := wfStore.List(spec.WorkflowFilter{ChangeID: input.ChangeID, ActiveOnly: true})
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error listing workflows: %v", err)}},
+			}, nil, nil
+		}
+		switch len(active) {
+		case 0:
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: "No workflow found"}},
+			}, nil, nil
+		case 1:
+		default:
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: formatWorkflowList(active)}},
+			}, nil, nil
+		}
+	}
+
+	workflow, err := wfStore.Resolve(input.WorkflowID, input.ChangeID)
 	if err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow: %v", err)}},
@@ -212,6 +352,10 @@ func workflowStatusHandler(ctx context.Context, req *mcp.CallToolRequest, input
 		msg += "\n\nUse workflow_approve to continue"
 	}
 
+	if def, err := store.LoadWorkflowDefinition(workflow.Definition); err == nil {
+		msg += "\n\n" + formatWorkflowNext(def, workflow)
+	}
+
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
 	}, nil, nil
@@ -226,17 +370,18 @@ func workflowWaitHandler(ctx context.Context, req *mcp.CallToolRequest, input Wo
 	}
 
 	store := spec.NewStore(input.Path)
-
-	if !store.WorkflowExists() {
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: "No active workflow found. Start with workflow_start first."}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
 		}, nil, nil
 	}
+	defer func() { _ = wfStore.Close() }()
 
-	workflow, err := store.LoadWorkflow()
+	workflow, err := wfStore.Resolve(input.WorkflowID, input.ChangeID)
 	if err != nil {
 		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow: %v", err)}},
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("No active workflow found. Start with workflow_start first. (%v)", err)}},
 		}, nil, nil
 	}
 
@@ -247,16 +392,24 @@ func workflowWaitHandler(ctx context.Context, req *mcp.CallToolRequest, input Wo
 			}},
 		}, nil, nil
 	}
+	if workflow.IsTerminal() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{
+				Text: fmt.Sprintf("Workflow not active (status: %s)", workflow.Status),
+			}},
+		}, nil, nil
+	}
 
 	workflow.SetWaitPoint(input.WaitPoint)
 
-	if err := store.SaveWorkflow(workflow); err != nil {
+	if err := wfStore.Save(workflow); err != nil {
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error saving workflow: %v", err)}},
 		}, nil, nil
 	}
 
 	msg := fmt.Sprintf("⏸️  Workflow paused at wait point: %s\n\n", input.WaitPoint)
+	msg += fmt.Sprintf("Workflow: %s (change %s)\n", workflow.ID, workflow.ChangeID)
 	msg += "Status: waiting\n"
 	msg += "\nUse workflow_approve to continue after user approval"
 
@@ -264,3 +417,156 @@ func workflowWaitHandler(ctx context.Context, req *mcp.CallToolRequest, input Wo
 		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
 	}, nil, nil
 }
+
+func workflowTransitionHandler(ctx context.Context, req *mcp.CallToolRequest, input WorkflowTransitionInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+	if input.To == "" {
+		return nil, nil, fmt.Errorf("to is required")
+	}
+
+	store := spec.NewStore(input.Path)
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = wfStore.Close() }()
+
+	workflow, err := wfStore.Resolve(input.WorkflowID, input.ChangeID)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow: %v", err)}},
+		}, nil, nil
+	}
+
+	def, err := store.LoadWorkflowDefinition(workflow.Definition)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow definition: %v", err)}},
+		}, nil, nil
+	}
+
+	from := workflow.Phase
+	if err := workflow.Transition(def, input.To); err != nil {
+		msg := fmt.Sprintf("❌ Cannot transition: %v\n\n", err)
+		msg += formatWorkflowNext(def, workflow)
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: msg}},
+		}, nil, nil
+	}
+
+	if err := wfStore.Save(workflow); err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error saving workflow: %v", err)}},
+		}, nil, nil
+	}
+
+	msg := fmt.Sprintf("✅ Workflow moved %s → %s\n\n", from, workflow.Phase)
+	msg += fmt.Sprintf("Workflow: %s (change %s)\n", workflow.ID, workflow.ChangeID)
+	if workflow.AgentRole != "" {
+		msg += fmt.Sprintf("Agent: %s\n", workflow.AgentRole)
+	}
+	msg += fmt.Sprintf("Status: %s\n", workflow.Status)
+	if workflow.Status == spec.WorkflowStatusWaiting {
+		msg += fmt.Sprintf("Waiting at: %s\n", workflow.WaitPoint)
+	}
+	msg += formatWorkflowNext(def, workflow)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: msg}},
+	}, nil, nil
+}
+
+func workflowListHandler(ctx context.Context, req *mcp.CallToolRequest, input WorkflowListInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	store := spec.NewStore(input.Path)
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = wfStore.Close() }()
+
+	workflows, err := wfStore.List(spec.WorkflowFilter{ChangeID: input.ChangeID, ActiveOnly: !input.All})
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error listing workflows: %v", err)}},
+		}, nil, nil
+	}
+
+	if len(workflows) == 0 {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: "No workflows found"}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: formatWorkflowList(workflows)}},
+	}, nil, nil
+}
+
+func workflowCancelHandler(ctx context.Context, req *mcp.CallToolRequest, input WorkflowCancelInput) (*mcp.CallToolResult, any, error) {
+	if input.Path == "" {
+		return nil, nil, fmt.Errorf("path is required")
+	}
+
+	store := spec.NewStore(input.Path)
+	wfStore, err := spec.NewWorkflowStore(store)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error opening workflow store: %v", err)}},
+		}, nil, nil
+	}
+	defer func() { _ = wfStore.Close() }()
+
+	workflow, err := wfStore.Resolve(input.WorkflowID, input.ChangeID)
+	if err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error loading workflow: %v", err)}},
+		}, nil, nil
+	}
+
+	workflow.Cancel()
+
+	if err := wfStore.Save(workflow); err != nil {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error saving workflow: %v", err)}},
+		}, nil, nil
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{
+			Text: fmt.Sprintf("🛑 Workflow %s cancelled (change %s, phase %s)", workflow.ID, workflow.ChangeID, workflow.Phase),
+		}},
+	}, nil, nil
+}
+
+func formatWorkflowList(workflows []spec.WorkflowState) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Workflows (%d):\n\n", len(workflows)))
+	sb.WriteString("| ID | Change | Definition | Phase | Agent | Status | Wait Point |\n")
+	sb.WriteString("|----|--------|------------|-------|-------|--------|------------|\n")
+	for _, w := range workflows {
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %s |\n",
+			w.ID, w.ChangeID, w.Definition, w.Phase, w.AgentRole, w.Status, w.WaitPoint))
+	}
+	return sb.String()
+}
+
+func formatWorkflowNext(def *spec.WorkflowDefinition, workflow *spec.WorkflowState) string {
+	if workflow.IsTerminal() {
+		return ""
+	}
+	phase, ok := def.Phase(workflow.Phase)
+	if !ok || len(phase.Transitions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Allowed transitions: %s\n", strings.Join(phase.Transitions, ", "))
+}
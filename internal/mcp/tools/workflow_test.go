@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowHandlers(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	ctx := context.Background()
+	text := func(result *mcp.CallToolResult) string {
+		require.Len(t, result.Content, 1)
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	result, _, err := workflowStartHandler(ctx, &mcp.CallToolRequest{}, WorkflowStartInput{Path: root, ChangeID: "add-sso"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Phase: planning")
+	assert.Contains(t, text(result), "Status: waiting")
+
+	_, _, err = workflowStartHandler(ctx, &mcp.CallToolRequest{}, WorkflowStartInput{Path: root, ChangeID: "add-logout", Phase: "execution"})
+	require.NoError(t, err)
+
+	result, _, err = workflowListHandler(ctx, &mcp.CallToolRequest{}, WorkflowListInput{Path: root})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Workflows (2):")
+	assert.Contains(t, text(result), "| add-sso | default | planning | architect | waiting | planning-approval |")
+
+	result, _, err = workflowListHandler(ctx, &mcp.CallToolRequest{}, WorkflowListInput{Path: root, ChangeID: "add-logout"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Workflows (1):")
+
+	result, _, err = workflowTransitionHandler(ctx, &mcp.CallToolRequest{}, WorkflowTransitionInput{Path: root, ChangeID: "add-sso", To: "execution"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "❌ Cannot transition: required approvals pending")
+	assert.Contains(t, text(result), "Allowed transitions: execution, cancelled")
+
+	_, _, err = workflowApproveHandler(ctx, &mcp.CallToolRequest{}, WorkflowApproveInput{Path: root, ChangeID: "add-sso"})
+	require.Error(t, err, "approver is required")
+
+	result, _, err = workflowApproveHandler(ctx, &mcp.CallToolRequest{}, WorkflowApproveInput{Path: root, ChangeID: "add-sso", Approver: "alice"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Wait point approved")
+
+	result, _, err = workflowTransitionHandler(ctx, &mcp.CallToolRequest{}, WorkflowTransitionInput{Path: root, ChangeID: "add-sso", To: "done"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "❌ Cannot transition: transition not allowed: planning -> done")
+
+	result, _, err = workflowTransitionHandler(ctx, &mcp.CallToolRequest{}, WorkflowTransitionInput{Path: root, ChangeID: "add-sso", To: "execution"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "✅ Workflow moved planning → execution")
+	assert.Contains(t, text(result), "Agent: developer")
+
+	result, _, err = workflowTransitionHandler(ctx, &mcp.CallToolRequest{}, WorkflowTransitionInput{Path: root, ChangeID: "add-sso", To: "cancelled"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Status: cancelled")
+
+	result, _, err = workflowCancelHandler(ctx, &mcp.CallToolRequest{}, WorkflowCancelInput{Path: root, ChangeID: "add-logout"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "🛑 Workflow")
+	assert.Contains(t, text(result), "cancelled (change add-logout, phase execution)")
+
+	result, _, err = workflowCancelHandler(ctx, &mcp.CallToolRequest{}, WorkflowCancelInput{Path: root, ChangeID: "add-logout"})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Error loading workflow: workflow not found")
+
+	result, _, err = workflowListHandler(ctx, &mcp.CallToolRequest{}, WorkflowListInput{Path: root})
+	require.NoError(t, err)
+	assert.Equal(t, "No workflows found", text(result))
+
+	result, _, err = workflowListHandler(ctx, &mcp.CallToolRequest{}, WorkflowListInput{Path: root, All: true})
+	require.NoError(t, err)
+	assert.Contains(t, text(result), "Workflows (2):")
+	assert.Contains(t, text(result), "| cancelled |")
+
+	_, _, err = workflowTransitionHandler(ctx, &mcp.CallToolRequest{}, WorkflowTransitionInput{Path: root})
+	require.Error(t, err)
+	_, _, err = workflowListHandler(ctx, &mcp.CallToolRequest{}, WorkflowListInput{})
+	require.Error(t, err)
+}
@@ -2,6 +2,7 @@ package memory
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -211,6 +212,61 @@ func (m *MemoryStore) evaluatePattern(patterns []*Pattern, stats *PatternStats,
 	return nil
 }
 
+// Outcome aggregates recorded executions of one provider, model and method.
+type Outcome struct {
+	Provider      string
+	Model         string
+	Method        string
+	Executions    int
+	Successes     int
+	TotalCost     float64
+	TotalDuration time.Duration
+}
+
+// Outcomes aggregates recorded patterns per provider, model and method for
+// taskType, or for all task types when taskType is empty. Results are sorted
+// by provider, model and method.
+func (m *MemoryStore) Outcomes(taskType string) []Outcome {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	byKey := make(map[string]*Outcome)
+	for _, p := range m.patterns {
+		if taskType != "" && p.TaskType != taskType {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%s:%s", p.Provider, p.Model, p.Method)
+		o, ok := byKey[key]
+		if !ok {
+			o = &Outcome{Provider: p.Provider, Model: p.Model, Method: p.Method}
+			byKey[key] = o
+		}
+		o.Executions++
+		if p.Success {
+			o.Successes++
+		}
+		o.TotalCost += p.Cost
+		o.TotalDuration += p.Duration
+	}
+
+	outcomes := make([]Outcome, 0, len(byKey))
+	for _, o := range byKey {
+		outcomes = append(outcomes, *o)
+	}
+	sort.Slice(outcomes, func(i, j int) bool {
+		a, b := outcomes[i], outcomes[j]
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Model != b.Model {
+			return a.Model < b.Model
+		}
+		return a.Method < b.Method
+	})
+	return outcomes
+}
+
 func (m *MemoryStore) GetProviderStats(provider, model, method string) (*PatternStats, error) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
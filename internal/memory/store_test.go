@@ -281,3 +281,49 @@ func TestGetTotalPatterns(t *testing.T) {
 		assert.Equal(t, 0, mem.GetTotalPatterns())
 	})
 }
+
+func TestOutcomes(t *testing.T) {
+	mem := NewMemoryStore()
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, mem.Store(&Pattern{
+			TaskType: "implement",
+			Provider: "glm",
+			Model:    "glm-4",
+			Method:   "acp",
+			Success:  i != 0,
+			Cost:     0.01,
+			Duration: time.Second,
+		}))
+	}
+	require.NoError(t, mem.Store(&Pattern{
+		TaskType: "review",
+		Provider: "claude",
+		Model:    "sonnet",
+		Method:   "api",
+		Success:  true,
+		Cost:     0.05,
+		Duration: 3 * time.Second,
+	}))
+
+	t.Run("filters by task type", func(t *testing.T) {
+		outcomes := mem.Outcomes("implement")
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, "glm", outcomes[0].Provider)
+		assert.Equal(t, 4, outcomes[0].Executions)
+		assert.Equal(t, 3, outcomes[0].Successes)
+		assert.InDelta(t, 0.04, outcomes[0].TotalCost, 1e-9)
+		assert.Equal(t, 4*time.Second, outcomes[0].TotalDuration)
+	})
+
+	t.Run("empty task type returns all sorted", func(t *testing.T) {
+		outcomes := mem.Outcomes("")
+		require.Len(t, outcomes, 2)
+		assert.Equal(t, "claude", outcomes[0].Provider)
+		assert.Equal(t, "glm", outcomes[1].Provider)
+	})
+
+	t.Run("unknown task type", func(t *testing.T) {
+		assert.Empty(t, mem.Outcomes("unknown"))
+	})
+}
@@ -16,11 +16,15 @@ const (
 
 type Filter func(Metric) bool
 
+// Aggregator computes statistics over a metrics source. When the source
+// also implements RollupSource, unfiltered percentiles and time groupings,
+// Summarize and GroupRollups read pre-aggregated rollups instead of raw
+// events.
 type Aggregator struct {
-	store *Store
+	store Source
 }
 
-func NewAggregator(store *Store) *Aggregator {
+func NewAggregator(store Source) *Aggregator {
 	return &Aggregator{store: store}
 }
 
@@ -71,6 +75,16 @@ func (a *Aggregator) Percentile(field string, p float64, filter Filter) (float64
 		return 0, fmt.Errorf("invalid percentile %.2f: %w", p, ErrInvalidPercentile)
 	}
 
+	if filter == nil {
+		if _, ok := a.store.(RollupSource); ok {
+			summary, err := a.Summarize(Query{})
+			if err != nil {
+				return 0, err
+			}
+			return summary.Percentile(field, p)
+		}
+	}
+
 	metrics := a.applyFilter(filter)
 	if len(metrics) == 0 {
 		return 0, nil
@@ -89,18 +103,106 @@ func (a *Aggregator) Percentile(field string, p float64, filter Filter) (float64
 	return calculatePercentile(values, p), nil
 }
 
-func (a *Aggregator) GroupByTime(groupBy GroupBy, filter Filter) map[string][]Metric {
-	metrics := a.applyFilter(filter)
-	result := make(map[string][]Metric)
+// GroupByTime returns one merged rollup per time key for metrics matching
+// filter. Without a filter it reads the source's rollups like GroupRollups;
+// a filter needs raw events, which are rolled up before grouping.
+func (a *Aggregator) GroupByTime(groupBy GroupBy, filter Filter) (map[string]Rollup, error) {
+	if filter == nil {
+		return a.GroupRollups(groupBy, Query{})
+	}
 
-	for _, m := range metrics {
-		key := formatTimeKey(m.Timestamp, groupBy)
-		result[key] = append(result[key], m)
+	g := groupGranularity(groupBy)
+	return groupRollups(buildRollups(a.store.Filter(filter), g), g, groupBy, ""), nil
+}
+
+// Summarize merges all metrics matching q into a single rollup. With a
+// RollupSource, hourly rollups are used when q starts within the hourly
+// retention and daily rollups otherwise, so bucket edges round to the hour or
+// day; other sources are summarized from raw metrics.
+func (a *Aggregator) Summarize(q Query) (Rollup, error) {
+	hourly := DefaultRetention().Hourly
+	if rr, ok := a.store.(interface{ Retention() Retention }); ok {
+		hourly = rr.Retention().Hourly
+	}
+
+	g := GranularityHour
+	if q.Start.IsZero() || time.Since(q.Start) > hourly {
+		g = GranularityDay
+	}
+
+	rollups, err := a.rollups(g, q)
+	if err != nil {
+		return Rollup{}, err
 	}
 
+	summary := Rollup{Start: q.Start, Granularity: g, Tool: q.Tool}
+	for _, r := range rollups {
+		summary.Merge(r)
+	}
+	return summary, nil
+}
+
+// GroupRollups returns one merged rollup per time key (see GroupByTime) for
+// metrics matching q.
+func (a *Aggregator) GroupRollups(groupBy GroupBy, q Query) (map[string]Rollup, error) {
+	g := groupGranularity(groupBy)
+
+	rollups, err := a.rollups(g, q)
+	if err != nil {
+		return nil, err
+	}
+	return groupRollups(rollups, g, groupBy, q.Tool), nil
+}
+
+// groupGranularity is the rollup granularity a time grouping merges.
+func groupGranularity(groupBy GroupBy) Granularity {
+	if groupBy == GroupByHour {
+		return GranularityHour
+	}
+	return GranularityDay
+}
+
+func groupRollups(rollups []Rollup, g Granularity, groupBy GroupBy, tool string) map[string]Rollup {
+	result := make(map[string]Rollup)
+	for _, r := range rollups {
+		key := formatTimeKey(r.Start, groupBy)
+		merged, ok := result[key]
+		if !ok {
+			merged = Rollup{Start: r.Start, Granularity: g, Tool: tool}
+		}
+		merged.Merge(r)
+		result[key] = merged
+	}
 	return result
 }
 
+// GroupRollupsByTool returns one merged daily rollup per tool for metrics
+// matching q.
+func (a *Aggregator) GroupRollupsByTool(q Query) (map[string]Rollup, error) {
+	rollups, err := a.rollups(GranularityDay, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]Rollup)
+	for _, r := range rollups {
+		merged, ok := result[r.Tool]
+		if !ok {
+			merged = Rollup{Start: r.Start, Granularity: GranularityDay, Tool: r.Tool}
+		}
+		merged.Merge(r)
+		result[r.Tool] = merged
+	}
+	return result, nil
+}
+
+func (a *Aggregator) rollups(g Granularity, q Query) ([]Rollup, error) {
+	if rs, ok := a.store.(RollupSource); ok {
+		return rs.Rollups(g, q)
+	}
+	return buildRollups(a.store.Filter(q.Filter()), g), nil
+}
+
 func (a *Aggregator) FilterByTool(toolName string) Filter {
 	return func(m Metric) bool {
 		return m.ToolName == toolName
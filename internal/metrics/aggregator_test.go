@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAggregator_AverageTokensIn(t *testing.T) {
@@ -287,10 +288,11 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t2})
 		_ = store.Add(Metric{Timestamp: t3})
 
-		groups := agg.GroupByTime(GroupByHour, nil)
+		groups, err := agg.GroupByTime(GroupByHour, nil)
+		require.NoError(t, err)
 		assert.Len(t, groups, 2)
-		assert.Len(t, groups["2026-01-15T14"], 2)
-		assert.Len(t, groups["2026-01-15T15"], 1)
+		assert.Equal(t, 2, groups["2026-01-15T14"].Count)
+		assert.Equal(t, 1, groups["2026-01-15T15"].Count)
 	})
 
 	t.Run("groups by day", func(t *testing.T) {
@@ -306,10 +308,11 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t2})
 		_ = store.Add(Metric{Timestamp: t3})
 
-		groups := agg.GroupByTime(GroupByDay, nil)
+		groups, err := agg.GroupByTime(GroupByDay, nil)
+		require.NoError(t, err)
 		assert.Len(t, groups, 2)
-		assert.Len(t, groups["2026-01-15"], 2)
-		assert.Len(t, groups["2026-01-16"], 1)
+		assert.Equal(t, 2, groups["2026-01-15"].Count)
+		assert.Equal(t, 1, groups["2026-01-16"].Count)
 	})
 
 	t.Run("groups by week", func(t *testing.T) {
@@ -325,10 +328,11 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t2})
 		_ = store.Add(Metric{Timestamp: t3})
 
-		groups := agg.GroupByTime(GroupByWeek, nil)
+		groups, err := agg.GroupByTime(GroupByWeek, nil)
+		require.NoError(t, err)
 		assert.Len(t, groups, 2)
-		assert.Len(t, groups["2026-W03"], 2)
-		assert.Len(t, groups["2026-W04"], 1)
+		assert.Equal(t, 2, groups["2026-W03"].Count)
+		assert.Equal(t, 1, groups["2026-W04"].Count)
 	})
 
 	t.Run("applies filter before grouping", func(t *testing.T) {
@@ -342,9 +346,10 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t1, ToolName: "tool1"})
 		_ = store.Add(Metric{Timestamp: t2, ToolName: "tool2"})
 
-		groups := agg.GroupByTime(GroupByHour, agg.FilterByTool("tool1"))
+		groups, err := agg.GroupByTime(GroupByHour, agg.FilterByTool("tool1"))
+		require.NoError(t, err)
 		assert.Len(t, groups, 1)
-		assert.Len(t, groups["2026-01-15T14"], 1)
+		assert.Equal(t, 1, groups["2026-01-15T14"].Count)
 	})
 
 	t.Run("groups metrics on hour boundaries", func(t *testing.T) {
@@ -362,11 +367,12 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t3})
 		_ = store.Add(Metric{Timestamp: t4})
 
-		groups := agg.GroupByTime(GroupByHour, nil)
+		groups, err := agg.GroupByTime(GroupByHour, nil)
+		require.NoError(t, err)
 		assert.Len(t, groups, 3)
-		assert.Len(t, groups["2026-01-15T13"], 1)
-		assert.Len(t, groups["2026-01-15T14"], 2)
-		assert.Len(t, groups["2026-01-15T15"], 1)
+		assert.Equal(t, 1, groups["2026-01-15T13"].Count)
+		assert.Equal(t, 2, groups["2026-01-15T14"].Count)
+		assert.Equal(t, 1, groups["2026-01-15T15"].Count)
 	})
 
 	t.Run("groups metrics on day boundaries", func(t *testing.T) {
@@ -382,10 +388,11 @@ func TestAggregator_GroupByTime(t *testing.T) {
 		_ = store.Add(Metric{Timestamp: t2})
 		_ = store.Add(Metric{Timestamp: t3})
 
-		groups := agg.GroupByTime(GroupByDay, nil)
+		groups, err := agg.GroupByTime(GroupByDay, nil)
+		require.NoError(t, err)
 		assert.Len(t, groups, 2)
-		assert.Len(t, groups["2026-01-15"], 1)
-		assert.Len(t, groups["2026-01-16"], 2)
+		assert.Equal(t, 1, groups["2026-01-15"].Count)
+		assert.Equal(t, 2, groups["2026-01-16"].Count)
 	})
 }
 
@@ -584,9 +591,10 @@ func TestAggregator_Integration(t *testing.T) {
 		tool1SuccessRate := agg.SuccessRate(agg.FilterByTool("tool1"))
 		assert.Equal(t, 100.0, tool1SuccessRate)
 
-		hourlyGroups := agg.GroupByTime(GroupByHour, nil)
-		assert.Len(t, hourlyGroups["2026-01-15T14"], 2)
-		assert.Len(t, hourlyGroups["2026-01-15T15"], 1)
+		hourlyGroups, err := agg.GroupByTime(GroupByHour, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2, hourlyGroups["2026-01-15T14"].Count)
+		assert.Equal(t, 1, hourlyGroups["2026-01-15T15"].Count)
 
 		session1Metrics := len(store.Filter(agg.FilterBySession("session1")))
 		assert.Equal(t, 2, session1Metrics)
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,29 +16,38 @@ type contextKey string
 
 const sessionKey contextKey = "session"
 
-const (
-	defaultChannelSize = 100
-	channelTimeout     = 1 * time.Millisecond
-)
+const defaultChannelSize = 100
 
 type sessionState struct {
 	startTime time.Time
 	toolName  string
 }
 
+// Collector feeds finished tool calls to the live metrics synchronously and
+// to the Store asynchronously. Samples that find the store queue full are
+// dropped rather than blocking the caller, and counted.
 type Collector struct {
-	store *Store
-	ch    chan Metric
-	done  chan struct{}
-	wg    sync.WaitGroup
+	store   Backend
+	live    *Live
+	ch      chan Metric
+	done    chan struct{}
+	wg      sync.WaitGroup
+	dropped atomic.Uint64
 
 	mu     sync.Mutex
 	starts map[string]sessionState
 }
 
-func NewCollector(store *Store) *Collector {
+func NewCollector(store Backend) *Collector {
+	return NewLiveCollector(store, nil)
+}
+
+// NewLiveCollector creates a collector that also updates live. Either store
+// or live may be nil.
+func NewLiveCollector(store Backend, live *Live) *Collector {
 	c := &Collector{
 		store:  store,
+		live:   live,
 		ch:     make(chan Metric, defaultChannelSize),
 		done:   make(chan struct{}),
 		starts: make(map[string]sessionState),
@@ -79,7 +89,7 @@ func (c *Collector) EndExecution(ctx context.Context, result *execution.Result)
 		return fmt.Errorf("session %q not started: %w", sessionID, ErrSessionNotStarted)
 	}
 
-	metric := Metric{
+	c.Record(Metric{
 		SessionID: sessionID,
 		ToolName:  state.toolName,
 		TokensIn:  result.TokensIn,
@@ -88,15 +98,55 @@ func (c *Collector) EndExecution(ctx context.Context, result *execution.Result)
 		Success:   result.Success,
 		ErrorMsg:  result.Error,
 		Timestamp: time.Now(),
+	})
+
+	return nil
+}
+
+// Record updates the live metrics and queues m for the store.
+func (c *Collector) Record(m Metric) {
+	if c.live != nil {
+		c.live.ObserveTool(m)
+	}
+
+	if c.store == nil {
+		return
 	}
 
 	select {
-	case c.ch <- metric:
-	case <-time.After(channelTimeout):
-		slog.Warn("metrics channel full, dropping metric", "session", sessionID)
+	case c.ch <- m:
+	default:
+		c.dropped.Add(1)
+		if c.live != nil {
+			c.live.ObserveDropped()
+		}
+		slog.Warn("metrics channel full, dropping metric", "session", m.SessionID)
 	}
+}
 
-	return nil
+// Dropped returns the number of samples dropped because the store queue was
+// full.
+func (c *Collector) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+// RecordCost attributes tokens and spend to a provider and model.
+func (c *Collector) RecordCost(provider, model string, tokensIn, tokensOut int, cost float64) {
+	if c.live != nil {
+		c.live.ObserveCost(provider, model, tokensIn, tokensOut, cost)
+	}
+}
+
+// RecordSkillMatch counts a skill match.
+func (c *Collector) RecordSkillMatch(skill string) {
+	if c.live != nil {
+		c.live.ObserveSkillMatch(skill)
+	}
+}
+
+// Live returns the live metrics, or nil.
+func (c *Collector) Live() *Live {
+	return c.live
 }
 
 func (c *Collector) Shutdown(ctx context.Context) error {
@@ -403,3 +403,43 @@ func BenchmarkCollector_StartEnd(b *testing.B) {
 		}
 	}
 }
+
+// blockingStore holds every Add until release is closed.
+type blockingStore struct {
+	*Store
+	release chan struct{}
+}
+
+func (s *blockingStore) Add(m Metric) error {
+	<-s.release
+	return s.Store.Add(m)
+}
+
+func TestCollector_CountsDroppedSamples(t *testing.T) {
+	t.Parallel()
+
+	inner, err := NewStore(filepath.Join(t.TempDir(), "metrics.json"), 24*time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, inner.Close())
+	})
+
+	store := &blockingStore{Store: inner, release: make(chan struct{})}
+	live := NewLive()
+	c := NewLiveCollector(store, live)
+
+	// One sample is held by the writer, defaultChannelSize fill the queue
+	const extra = 5
+	total := defaultChannelSize + 1 + extra
+	for i := 0; i < total; i++ {
+		c.Record(Metric{SessionID: uuid.NewString(), ToolName: "spec_list", Success: true})
+	}
+
+	assert.GreaterOrEqual(t, c.Dropped(), uint64(extra))
+	assert.Equal(t, float64(c.Dropped()), live.dropped.Value())
+	assert.Equal(t, float64(total), live.toolCalls.Value("spec_list", "success"), "live metrics see every sample")
+
+	close(store.release)
+	require.NoError(t, c.Shutdown(context.Background()))
+	assert.Equal(t, total-int(c.Dropped()), inner.Count())
+}
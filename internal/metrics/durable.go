@@ -0,0 +1,526 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// DurableFile is the metrics database path relative to the project root.
+	DurableFile = "data/metrics.db"
+	// LegacyFile is the JSON store imported into the database on startup.
+	LegacyFile = "data/metrics.json"
+
+	bucketRaw    = "raw"
+	bucketHourly = "hourly"
+	bucketDaily  = "daily"
+
+	compactTxMaxSize = 64 * 1024
+)
+
+// Retention configures how long each granularity is kept.
+type Retention struct {
+	Raw    time.Duration
+	Hourly time.Duration
+	Daily  time.Duration
+}
+
+// DefaultRetention keeps raw events for 7 days, hourly rollups for 90 days
+// and daily rollups for two years.
+func DefaultRetention() Retention {
+	return Retention{
+		Raw:    7 * 24 * time.Hour,
+		Hourly: 90 * 24 * time.Hour,
+		Daily:  730 * 24 * time.Hour,
+	}
+}
+
+func (r Retention) withDefaults() Retention {
+	def := DefaultRetention()
+	if r.Raw <= 0 {
+		r.Raw = def.Raw
+	}
+	if r.Hourly <= 0 {
+		r.Hourly = def.Hourly
+	}
+	if r.Daily <= 0 {
+		r.Daily = def.Daily
+	}
+	return r
+}
+
+// CompactStats reports what a compaction removed.
+type CompactStats struct {
+	RawDeleted    int
+	HourlyDeleted int
+	DailyDeleted  int
+	SizeBefore    int64
+	SizeAfter     int64
+}
+
+// DurableStore persists raw metrics in bbolt and maintains hourly and daily
+// rollups on every Add. Raw events expire after a short retention while the
+// rollups keep long-range queries cheap.
+type DurableStore struct {
+	mu        sync.RWMutex
+	db        *bbolt.DB
+	path      string
+	retention Retention
+	now       func() time.Time
+}
+
+// DurablePath returns the metrics database path of the project at root.
+func DurablePath(root string) string {
+	return filepath.Join(root, DurableFile)
+}
+
+// OpenDurableStore opens or creates the metrics database at path and applies
+// retention.
+func OpenDurableStore(path string, retention Retention) (*DurableStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create dir: %w", err)
+	}
+
+	s := &DurableStore{
+		path:      path,
+		retention: retention.withDefaults(),
+		now:       time.Now,
+	}
+
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.applyRetention(); err != nil {
+		_ = s.db.Close()
+		return nil, fmt.Errorf("apply retention: %w", err)
+	}
+
+	slog.Debug("durable metrics store initialized",
+		"storage_path", path,
+		"raw_retention_hours", int(s.retention.Raw.Hours()),
+		"hourly_retention_hours", int(s.retention.Hourly.Hours()),
+		"daily_retention_hours", int(s.retention.Daily.Hours()),
+	)
+
+	return s, nil
+}
+
+func (s *DurableStore) open() error {
+	db, err := bbolt.Open(s.path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return fmt.Errorf("open metrics db: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{bucketRaw, bucketHourly, bucketDaily} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return fmt.Errorf("create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+
+	s.db = db
+	return nil
+}
+
+// Retention returns the effective retention per granularity.
+func (s *DurableStore) Retention() Retention {
+	return s.retention
+}
+
+// Add stores m and updates its hourly and daily rollups in one transaction.
+func (s *DurableStore) Add(m Metric) error {
+	if m.Timestamp.IsZero() {
+		m.Timestamp = s.now()
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("marshal metric: %w", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return ErrStoreClosed
+	}
+
+	err = s.db.Update(func(tx *bbolt.Tx) error {
+		raw := tx.Bucket([]byte(bucketRaw))
+		seq, err := raw.NextSequence()
+		if err != nil {
+			return err
+		}
+		if err := raw.Put(rawKey(m.Timestamp, seq), data); err != nil {
+			return err
+		}
+
+		if err := upsertRollup(tx.Bucket([]byte(bucketHourly)), GranularityHour, m); err != nil {
+			return fmt.Errorf("hourly rollup: %w", err)
+		}
+		if err := upsertRollup(tx.Bucket([]byte(bucketDaily)), GranularityDay, m); err != nil {
+			return fmt.Errorf("daily rollup: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSaveFailed, err)
+	}
+	return nil
+}
+
+func upsertRollup(b *bbolt.Bucket, g Granularity, m Metric) error {
+	start := g.Truncate(m.Timestamp)
+	key := rollupKey(start, m.ToolName)
+
+	r := Rollup{Start: start, Granularity: g, Tool: m.ToolName}
+	if existing := b.Get(key); existing != nil {
+		if err := json.Unmarshal(existing, &r); err != nil {
+			return fmt.Errorf("unmarshal rollup: %w", err)
+		}
+	}
+	r.Observe(m)
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal rollup: %w", err)
+	}
+	return b.Put(key, data)
+}
+
+// GetAll returns all raw metrics still within raw retention, oldest first.
+func (s *DurableStore) GetAll() []Metric {
+	return s.Filter(nil)
+}
+
+// Filter returns raw metrics matching filter, oldest first.
+func (s *DurableStore) Filter(filter func(Metric) bool) []Metric {
+	result := []Metric{}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return result
+	}
+
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(bucketRaw)).ForEach(func(_, v []byte) error {
+			var m Metric
+			if err := json.Unmarshal(v, &m); err != nil {
+				slog.Warn("skipping corrupt metric", "error", err)
+				return nil
+			}
+			if filter == nil || filter(m) {
+				result = append(result, m)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		slog.Warn("failed to read metrics", "error", err)
+	}
+
+	return result
+}
+
+// Count returns the number of raw metrics.
+func (s *DurableStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return 0
+	}
+
+	count := 0
+	_ = s.db.View(func(tx *bbolt.Tx) error {
+		count = tx.Bucket([]byte(bucketRaw)).Stats().KeyN
+		return nil
+	})
+	return count
+}
+
+// Rollups returns the rollups of granularity g whose bucket overlaps q,
+// ordered by start time and tool.
+func (s *DurableStore) Rollups(g Granularity, q Query) ([]Rollup, error) {
+	bucket := bucketHourly
+	if g == GranularityDay {
+		bucket = bucketDaily
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return nil, ErrStoreClosed
+	}
+
+	var rollups []Rollup
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		c := tx.Bucket([]byte(bucket)).Cursor()
+
+		var k, v []byte
+		if q.Start.IsZero() {
+			k, v = c.First()
+		} else {
+			k, v = c.Seek(rollupKey(g.Truncate(q.Start), ""))
+		}
+
+		for ; k != nil; k, v = c.Next() {
+			start := rollupKeyTime(k)
+			if !q.End.IsZero() && start.After(q.End) {
+				break
+			}
+
+			var r Rollup
+			if err := json.Unmarshal(v, &r); err != nil {
+				return fmt.Errorf("unmarshal rollup: %w", err)
+			}
+			if q.Tool != "" && r.Tool != q.Tool {
+				continue
+			}
+			rollups = append(rollups, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadFailed, err)
+	}
+
+	return rollups, nil
+}
+
+// Clear removes all raw metrics and rollups.
+func (s *DurableStore) Clear() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return ErrStoreClosed
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range []string{bucketRaw, bucketHourly, bucketDaily} {
+			if err := tx.DeleteBucket([]byte(name)); err != nil {
+				return fmt.Errorf("delete bucket %s: %w", name, err)
+			}
+			if _, err := tx.CreateBucket([]byte(name)); err != nil {
+				return fmt.Errorf("create bucket %s: %w", name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the database.
+func (s *DurableStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return nil
+	}
+
+	err := s.db.Close()
+	s.db = nil
+	return err
+}
+
+// Compact drops data past retention and rewrites the database file to
+// reclaim the freed pages.
+func (s *DurableStore) Compact() (CompactStats, error) {
+	stats, err := s.applyRetention()
+	if err != nil {
+		return stats, fmt.Errorf("apply retention: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.db == nil {
+		return stats, ErrStoreClosed
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		stats.SizeBefore = info.Size()
+	}
+
+	tmpPath := s.path + ".compact"
+	_ = os.Remove(tmpPath)
+
+	dst, err := bbolt.Open(tmpPath, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return stats, fmt.Errorf("open compaction target: %w", err)
+	}
+
+	if err := bbolt.Compact(dst, s.db, compactTxMaxSize); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+		return stats, fmt.Errorf("compact: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return stats, fmt.Errorf("close compaction target: %w", err)
+	}
+
+	if err := s.db.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return stats, fmt.Errorf("close metrics db: %w", err)
+	}
+	s.db = nil
+
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		if openErr := s.open(); openErr != nil {
+			return stats, fmt.Errorf("reopen after failed rename: %w", openErr)
+		}
+		return stats, fmt.Errorf("replace metrics db: %w", err)
+	}
+
+	if err := s.open(); err != nil {
+		return stats, err
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		stats.SizeAfter = info.Size()
+	}
+
+	slog.Info("metrics store compacted",
+		"raw_deleted", stats.RawDeleted,
+		"hourly_deleted", stats.HourlyDeleted,
+		"daily_deleted", stats.DailyDeleted,
+		"size_before", stats.SizeBefore,
+		"size_after", stats.SizeAfter,
+	)
+
+	return stats, nil
+}
+
+// RunCompaction compacts the store every interval until ctx is cancelled.
+func (s *DurableStore) RunCompaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Compact(); err != nil {
+				slog.Warn("metrics compaction failed", "error", err)
+			}
+		}
+	}
+}
+
+// ImportJSON adds the metrics of a legacy JSON store file and renames the
+// file so it is imported only once. A missing file is not an error.
+func (s *DurableStore) ImportJSON(path string) (int, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- controlled config/template file path
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read file: %w", err)
+	}
+
+	var legacy []Metric
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &legacy); err != nil {
+			return 0, fmt.Errorf("unmarshal: %w", err)
+		}
+	}
+
+	for _, m := range legacy {
+		if err := s.Add(m); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := os.Rename(path, path+".imported"); err != nil {
+		return len(legacy), fmt.Errorf("rename imported file: %w", err)
+	}
+
+	return len(legacy), nil
+}
+
+func (s *DurableStore) applyRetention() (CompactStats, error) {
+	var stats CompactStats
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.db == nil {
+		return stats, ErrStoreClosed
+	}
+
+	now := s.now()
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		stats.RawDeleted, err = deleteBefore(tx.Bucket([]byte(bucketRaw)), rawKey(now.Add(-s.retention.Raw), 0))
+		if err != nil {
+			return err
+		}
+		stats.HourlyDeleted, err = deleteBefore(tx.Bucket([]byte(bucketHourly)), rollupKey(now.Add(-s.retention.Hourly), ""))
+		if err != nil {
+			return err
+		}
+		stats.DailyDeleted, err = deleteBefore(tx.Bucket([]byte(bucketDaily)), rollupKey(now.Add(-s.retention.Daily), ""))
+		return err
+	})
+
+	if total := stats.RawDeleted + stats.HourlyDeleted + stats.DailyDeleted; total > 0 {
+		slog.Info("metrics retention applied",
+			"raw_deleted", stats.RawDeleted,
+			"hourly_deleted", stats.HourlyDeleted,
+			"daily_deleted", stats.DailyDeleted,
+		)
+	}
+
+	return stats, err
+}
+
+// deleteBefore removes all keys ordered before limit.
+func deleteBefore(b *bbolt.Bucket, limit []byte) (int, error) {
+	var keys [][]byte
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && bytes.Compare(k, limit) < 0; k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+
+	for _, k := range keys {
+		if err := b.Delete(k); err != nil {
+			return 0, err
+		}
+	}
+	return len(keys), nil
+}
+
+// rawKey orders raw metrics by timestamp; seq keeps equal timestamps unique.
+func rawKey(t time.Time, seq uint64) []byte {
+	key := make([]byte, 16)
+	binary.BigEndian.PutUint64(key[:8], uint64(max(0, int(t.UnixNano()))))
+	binary.BigEndian.PutUint64(key[8:], seq)
+	return key
+}
+
+// rollupKey orders rollups by bucket start, then tool name.
+func rollupKey(start time.Time, tool string) []byte {
+	key := make([]byte, 8, 8+len(tool))
+	binary.BigEndian.PutUint64(key, uint64(max(0, int(start.Unix()))))
+	return append(key, tool...)
+}
+
+func rollupKeyTime(key []byte) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint64(key[:8])), 0).UTC()
+}
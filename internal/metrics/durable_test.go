@@ -0,0 +1,299 @@
+package metrics
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDurableStore(t *testing.T) *DurableStore {
+	t.Helper()
+
+	s, err := OpenDurableStore(filepath.Join(t.TempDir(), "metrics.db"), Retention{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestOpenDurableStore_DefaultRetention(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	assert.Equal(t, DefaultRetention(), s.Retention())
+
+	custom, err := OpenDurableStore(filepath.Join(t.TempDir(), "metrics.db"), Retention{Raw: time.Hour})
+	require.NoError(t, err)
+	defer func() { _ = custom.Close() }()
+
+	assert.Equal(t, time.Hour, custom.Retention().Raw)
+	assert.Equal(t, DefaultRetention().Hourly, custom.Retention().Hourly)
+}
+
+func TestDurableStore_AddAndRollups(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	base := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+
+	for i := 0; i < 4; i++ {
+		require.NoError(t, s.Add(Metric{
+			ToolName:  "spec_list",
+			Timestamp: base.Add(time.Duration(i) * 10 * time.Minute),
+			Duration:  time.Duration(i+1) * 100 * time.Millisecond,
+			TokensIn:  10,
+			TokensOut: 20,
+			Success:   i != 0,
+		}))
+	}
+	require.NoError(t, s.Add(Metric{ToolName: "registry_next", Timestamp: base.Add(time.Hour), Success: true}))
+
+	assert.Equal(t, 5, s.Count())
+	all := s.GetAll()
+	require.Len(t, all, 5)
+	assert.Equal(t, "spec_list", all[0].ToolName)
+	assert.Equal(t, "registry_next", all[4].ToolName)
+
+	hourly, err := s.Rollups(GranularityHour, Query{})
+	require.NoError(t, err)
+	require.Len(t, hourly, 2)
+	assert.Equal(t, "spec_list", hourly[0].Tool)
+	assert.Equal(t, base, hourly[0].Start)
+	assert.Equal(t, 4, hourly[0].Count)
+	assert.Equal(t, 3, hourly[0].Successes)
+	assert.Equal(t, int64(40), hourly[0].TokensIn)
+	assert.Equal(t, 250*time.Millisecond, hourly[0].AverageDuration())
+
+	byTool, err := s.Rollups(GranularityHour, Query{Tool: "registry_next"})
+	require.NoError(t, err)
+	require.Len(t, byTool, 1)
+	assert.Equal(t, base.Add(time.Hour), byTool[0].Start)
+
+	inRange, err := s.Rollups(GranularityHour, Query{Start: base.Add(time.Hour)})
+	require.NoError(t, err)
+	require.Len(t, inRange, 1)
+	assert.Equal(t, "registry_next", inRange[0].Tool)
+
+	daily, err := s.Rollups(GranularityDay, Query{Tool: "spec_list"})
+	require.NoError(t, err)
+	require.Len(t, daily, 1)
+	assert.Equal(t, 4, daily[0].Count)
+}
+
+func TestDurableStore_Retention(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	old := now.Add(-10 * 24 * time.Hour)
+	ancient := now.Add(-200 * 24 * time.Hour)
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: ancient, Success: true}))
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: old, Success: true}))
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: now.Add(-time.Hour), Success: true}))
+
+	stats, err := s.Compact()
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.RawDeleted)
+	assert.Equal(t, 1, stats.HourlyDeleted)
+	assert.Equal(t, 0, stats.DailyDeleted)
+	assert.Positive(t, stats.SizeAfter)
+
+	assert.Equal(t, 1, s.Count())
+
+	hourly, err := s.Rollups(GranularityHour, Query{})
+	require.NoError(t, err)
+	assert.Len(t, hourly, 2)
+
+	daily, err := s.Rollups(GranularityDay, Query{})
+	require.NoError(t, err)
+	assert.Len(t, daily, 3)
+
+	require.NoError(t, s.Add(Metric{ToolName: "b", Timestamp: now, Success: true}))
+	assert.Equal(t, 2, s.Count())
+}
+
+func TestDurableStore_ReopenKeepsData(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	path := DurablePath(root)
+	assert.Equal(t, filepath.Join(root, "data", "metrics.db"), path)
+	s, err := OpenDurableStore(path, Retention{})
+	require.NoError(t, err)
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: time.Now(), Success: true}))
+	require.NoError(t, s.Close())
+
+	reopened, err := OpenDurableStore(path, Retention{})
+	require.NoError(t, err)
+	defer func() { _ = reopened.Close() }()
+
+	assert.Equal(t, 1, reopened.Count())
+}
+
+func TestDurableStore_ClearAndClose(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: time.Now(), Success: true}))
+	require.NoError(t, s.Clear())
+
+	assert.Equal(t, 0, s.Count())
+	rollups, err := s.Rollups(GranularityDay, Query{})
+	require.NoError(t, err)
+	assert.Empty(t, rollups)
+
+	require.NoError(t, s.Close())
+	assert.ErrorIs(t, s.Add(Metric{ToolName: "a", Timestamp: time.Now()}), ErrStoreClosed)
+	_, err = s.Rollups(GranularityDay, Query{})
+	assert.ErrorIs(t, err, ErrStoreClosed)
+}
+
+func TestDurableStore_ImportJSON(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	legacyPath := filepath.Join(dir, "metrics.json")
+	legacy := []Metric{
+		{ToolName: "a", Timestamp: time.Now().Add(-time.Hour), Success: true},
+		{ToolName: "b", Timestamp: time.Now(), Success: false},
+	}
+	data, err := json.Marshal(legacy)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(legacyPath, data, 0600))
+
+	s := openTestDurableStore(t)
+
+	n, err := s.ImportJSON(legacyPath)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2, s.Count())
+
+	_, err = os.Stat(legacyPath)
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(legacyPath + ".imported")
+	assert.NoError(t, err)
+
+	n, err = s.ImportJSON(legacyPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestDurableStore_RunCompactionStops(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.RunCompaction(ctx, time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("RunCompaction did not stop")
+	}
+
+	require.NoError(t, s.Add(Metric{ToolName: "a", Timestamp: time.Now(), Success: true}))
+	assert.Equal(t, 1, s.Count())
+}
+
+func TestAggregator_DurableStore(t *testing.T) {
+	t.Parallel()
+
+	s := openTestDurableStore(t)
+	base := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	for i := 1; i <= 100; i++ {
+		tool := "a"
+		if i%2 == 0 {
+			tool = "b"
+		}
+		require.NoError(t, s.Add(Metric{
+			ToolName:  tool,
+			Timestamp: base.Add(time.Duration(i) * time.Minute),
+			Duration:  time.Duration(i) * time.Millisecond,
+			TokensIn:  i,
+			Success:   true,
+		}))
+	}
+
+	agg := NewAggregator(s)
+
+	summary, err := agg.Summarize(Query{})
+	require.NoError(t, err)
+	assert.Equal(t, 100, summary.Count)
+	assert.InDelta(t, 50.5, summary.AverageTokensIn(), 0.01)
+
+	recent, err := agg.Summarize(Query{Start: base})
+	require.NoError(t, err)
+	assert.Equal(t, GranularityHour, recent.Granularity)
+	assert.Equal(t, 100, recent.Count)
+
+	p95, err := agg.Percentile("duration", 0.95, nil)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(95*time.Millisecond), p95, float64(5*time.Millisecond))
+
+	hours, err := agg.GroupRollups(GroupByHour, Query{Tool: "a"})
+	require.NoError(t, err)
+	total := 0
+	for _, r := range hours {
+		total += r.Count
+	}
+	assert.Equal(t, 50, total)
+	assert.Len(t, hours, 2)
+
+	byTool, err := agg.GroupRollupsByTool(Query{})
+	require.NoError(t, err)
+	require.Len(t, byTool, 2)
+	assert.Equal(t, 50, byTool["a"].Count)
+	assert.Equal(t, 50, byTool["b"].Count)
+}
+
+func TestAggregator_GroupByTimeUsesRollups(t *testing.T) {
+	t.Parallel()
+
+	s, err := OpenDurableStore(filepath.Join(t.TempDir(), "metrics.db"), Retention{Raw: time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	base := time.Now().UTC().Truncate(time.Hour).Add(-4 * time.Hour)
+	for i := 0; i < 6; i++ {
+		require.NoError(t, s.Add(Metric{
+			ToolName:  "a",
+			Timestamp: base.Add(time.Duration(i*20) * time.Minute),
+			Duration:  time.Duration(i+1) * time.Millisecond,
+			Success:   i%3 != 0,
+		}))
+	}
+
+	_, err = s.Compact()
+	require.NoError(t, err)
+	require.Empty(t, s.GetAll(), "raw events are past retention")
+
+	agg := NewAggregator(s)
+	groups, err := agg.GroupByTime(GroupByHour, nil)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	first := groups[base.Format("2006-01-02T15")]
+	assert.Equal(t, 3, first.Count)
+	assert.Equal(t, 2, first.Successes)
+	assert.Equal(t, 2*time.Millisecond, first.AverageDuration())
+
+	filtered, err := agg.GroupByTime(GroupByHour, agg.FilterByTool("a"))
+	require.NoError(t, err)
+	assert.Empty(t, filtered, "filters need raw events")
+}
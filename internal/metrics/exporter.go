@@ -36,10 +36,10 @@ func validatePrometheusMetricName(name string) error {
 }
 
 type Exporter struct {
-	store *Store
+	store Source
 }
 
-func NewExporter(store *Store) *Exporter {
+func NewExporter(store Source) *Exporter {
 	return &Exporter{store: store}
 }
 
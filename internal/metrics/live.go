@@ -0,0 +1,208 @@
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Live holds the counters, gauges and histograms served on /metrics.
+// It is fed incrementally by the Collector, so its totals do not depend on
+// how many entries the Store ring buffer retains.
+type Live struct {
+	registry *Registry
+
+	toolCalls      *CounterVec
+	toolDuration   *HistogramVec
+	tokens         *CounterVec
+	providerCost   *CounterVec
+	providerTokens *CounterVec
+	skillMatches   *CounterVec
+	dropped        *CounterVec
+
+	mu           sync.Mutex
+	dailyLimit   float64
+	monthlyLimit float64
+	dayKey       string
+	monthKey     string
+	daySpent     float64
+	monthSpent   float64
+	now          func() time.Time
+}
+
+// NewLive creates the go-ent metric families on a fresh registry.
+func NewLive() *Live {
+	r := NewRegistry()
+
+	l := &Live{
+		registry: r,
+		toolCalls: r.NewCounterVec("goent_tool_calls_total",
+			"Total MCP tool calls by tool and status.", "tool", "status"),
+		toolDuration: r.NewHistogramVec("goent_tool_duration_seconds",
+			"MCP tool call latency in seconds.", DefaultDurationBuckets, "tool"),
+		tokens: r.NewCounterVec("goent_tool_tokens_total",
+			"Estimated tokens per tool and direction (in|out).", "tool", "direction"),
+		providerCost: r.NewCounterVec("goent_cost_usd_total",
+			"Spend in USD by provider and model.", "provider", "model"),
+		providerTokens: r.NewCounterVec("goent_provider_tokens_total",
+			"Tokens by provider, model and direction (in|out).", "provider", "model", "direction"),
+		skillMatches: r.NewCounterVec("goent_skill_matches_total",
+			"Number of times a skill matched a task or query.", "skill"),
+		dropped: r.NewCounterVec("goent_metrics_dropped_total",
+			"Tool call samples dropped because the metrics store queue was full."),
+		now: time.Now,
+	}
+
+	r.NewGaugeFunc("goent_budget_remaining_usd",
+		"Remaining budget in USD for the current period (daily|monthly).", "period", l.budgetRemaining)
+
+	return l
+}
+
+// Registry returns the underlying registry, e.g. to register extra families.
+func (l *Live) Registry() *Registry {
+	return l.registry
+}
+
+// ObserveTool records a finished tool call.
+func (l *Live) ObserveTool(m Metric) {
+	status := "success"
+	if !m.Success {
+		status = "error"
+	}
+
+	l.toolCalls.Inc(m.ToolName, status)
+	l.toolDuration.Observe(m.Duration.Seconds(), m.ToolName)
+	l.tokens.Add(float64(m.TokensIn), m.ToolName, "in")
+	l.tokens.Add(float64(m.TokensOut), m.ToolName, "out")
+}
+
+// ObserveDropped counts a sample the store never received.
+func (l *Live) ObserveDropped() {
+	l.dropped.Inc()
+}
+
+// ObserveCost records tokens and spend attributed to a provider and model.
+func (l *Live) ObserveCost(provider, model string, tokensIn, tokensOut int, cost float64) {
+	l.providerTokens.Add(float64(tokensIn), provider, model, "in")
+	l.providerTokens.Add(float64(tokensOut), provider, model, "out")
+	l.providerCost.Add(cost, provider, model)
+
+	if cost <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollWindowsLocked()
+	l.daySpent += cost
+	l.monthSpent += cost
+}
+
+// SeedSpend counts spend recorded at a past time, such as a persisted ledger
+// entry read at startup, toward the budget windows it falls in. Spend before
+// the current month is ignored.
+func (l *Live) SeedSpend(at time.Time, cost float64) {
+	if cost <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollWindowsLocked()
+	at = at.In(l.now().Location())
+	if at.Format("2006-01") == l.monthKey {
+		l.monthSpent += cost
+	}
+	if at.Format("2006-01-02") == l.dayKey {
+		l.daySpent += cost
+	}
+}
+
+// ObserveSkillMatch records that a skill matched.
+func (l *Live) ObserveSkillMatch(skill string) {
+	l.skillMatches.Inc(skill)
+}
+
+// SetBudget sets the daily and monthly limits used for the remaining budget
+// gauge. A zero limit is not reported.
+func (l *Live) SetBudget(daily, monthly float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.dailyLimit = daily
+	l.monthlyLimit = monthly
+}
+
+// WatchWorkers exposes goent_workers{status} computed by fn at scrape time.
+// It may be called once.
+func (l *Live) WatchWorkers(fn func() map[string]int) {
+	l.registry.NewGaugeFunc("goent_workers", "Current workers by status.", "status", func() map[string]float64 {
+		counts := fn()
+		out := make(map[string]float64, len(counts))
+		for status, n := range counts {
+			out[status] = float64(n)
+		}
+		return out
+	})
+}
+
+func (l *Live) budgetRemaining() map[string]float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rollWindowsLocked()
+
+	out := make(map[string]float64, 2)
+	if l.dailyLimit > 0 {
+		out["daily"] = l.dailyLimit - l.daySpent
+	}
+	if l.monthlyLimit > 0 {
+		out["monthly"] = l.monthlyLimit - l.monthSpent
+	}
+	return out
+}
+
+func (l *Live) rollWindowsLocked() {
+	now := l.now()
+	if day := now.Format("2006-01-02"); day != l.dayKey {
+		l.dayKey = day
+		l.daySpent = 0
+	}
+	if month := now.Format("2006-01"); month != l.monthKey {
+		l.monthKey = month
+		l.monthSpent = 0
+	}
+}
+
+// Handler returns the /metrics HTTP handler.
+func (l *Live) Handler() http.Handler {
+	return l.registry
+}
+
+// Listen starts an HTTP server on addr serving /metrics. It returns once the
+// listener is bound; use Shutdown on the returned server to stop it.
+func (l *Live) Listen(addr string) (*http.Server, net.Addr, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("listen %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", l.Handler())
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("metrics listener stopped", "addr", ln.Addr().String(), "error", err)
+		}
+	}()
+
+	return srv, ln.Addr(), nil
+}
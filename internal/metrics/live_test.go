@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, l *Live) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, l.Registry().WritePrometheus(&buf))
+	return buf.String()
+}
+
+func TestLive_ObserveTool(t *testing.T) {
+	t.Parallel()
+
+	l := NewLive()
+	l.ObserveTool(Metric{ToolName: "spec_list", TokensIn: 10, TokensOut: 40, Duration: 20 * time.Millisecond, Success: true})
+	l.ObserveTool(Metric{ToolName: "spec_list", Duration: 2 * time.Second, Success: false})
+
+	out := scrape(t, l)
+	assert.Contains(t, out, `goent_tool_calls_total{tool="spec_list",status="success"} 1`)
+	assert.Contains(t, out, `goent_tool_calls_total{tool="spec_list",status="error"} 1`)
+	assert.Contains(t, out, `goent_tool_duration_seconds_bucket{tool="spec_list",le="0.025"} 1`)
+	assert.Contains(t, out, `goent_tool_duration_seconds_count{tool="spec_list"} 2`)
+	assert.Contains(t, out, `goent_tool_tokens_total{tool="spec_list",direction="in"} 10`)
+	assert.Contains(t, out, `goent_tool_tokens_total{tool="spec_list",direction="out"} 40`)
+}
+
+func TestLive_CostAndBudget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	l := NewLive()
+	l.now = func() time.Time { return now }
+	l.SetBudget(10, 100)
+
+	l.ObserveCost("anthropic", "claude-sonnet", 1000, 500, 1.5)
+	l.ObserveCost("anthropic", "claude-sonnet", 1000, 500, 0.5)
+
+	out := scrape(t, l)
+	assert.Contains(t, out, `goent_cost_usd_total{provider="anthropic",model="claude-sonnet"} 2`)
+	assert.Contains(t, out, `goent_provider_tokens_total{provider="anthropic",model="claude-sonnet",direction="in"} 2000`)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="daily"} 8`)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="monthly"} 98`)
+
+	now = now.Add(2 * time.Hour)
+	out = scrape(t, l)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="daily"} 10`)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="monthly"} 100`)
+	assert.Contains(t, out, `goent_cost_usd_total{provider="anthropic",model="claude-sonnet"} 2`)
+}
+
+func TestLive_SeedSpend(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	l := NewLive()
+	l.now = func() time.Time { return now }
+	l.SetBudget(10, 100)
+
+	l.SeedSpend(now.Add(-time.Hour), 2)
+	l.SeedSpend(now.AddDate(0, 0, -3), 5)
+	l.SeedSpend(now.AddDate(0, -1, 0), 40)
+	l.ObserveCost("anthropic", "claude-sonnet", 10, 10, 1)
+
+	out := scrape(t, l)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="daily"} 7`)
+	assert.Contains(t, out, `goent_budget_remaining_usd{period="monthly"} 92`)
+	assert.Contains(t, out, `goent_cost_usd_total{provider="anthropic",model="claude-sonnet"} 1`, "seeded spend is not counted as new")
+}
+
+func TestLive_NoBudget(t *testing.T) {
+	t.Parallel()
+
+	l := NewLive()
+	assert.NotContains(t, scrape(t, l), "goent_budget_remaining_usd{")
+}
+
+func TestLive_WorkersAndSkills(t *testing.T) {
+	t.Parallel()
+
+	l := NewLive()
+	running := 1
+	l.WatchWorkers(func() map[string]int {
+		return map[string]int{"running": running, "completed": 3}
+	})
+	l.ObserveSkillMatch("go-code")
+	l.ObserveSkillMatch("go-code")
+
+	running = 2
+	out := scrape(t, l)
+	assert.Contains(t, out, `goent_workers{status="running"} 2`)
+	assert.Contains(t, out, `goent_workers{status="completed"} 3`)
+	assert.Contains(t, out, `goent_skill_matches_total{skill="go-code"} 2`)
+}
+
+func TestLive_Listen(t *testing.T) {
+	t.Parallel()
+
+	l := NewLive()
+	l.ObserveTool(Metric{ToolName: "spec_show", Success: true})
+
+	srv, addr, err := l.Listen("127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, srv.Shutdown(context.Background()))
+	})
+
+	resp, err := http.Get("http://" + addr.String() + "/metrics")
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `goent_tool_calls_total{tool="spec_show",status="success"} 1`)
+}
+
+func TestCollector_RecordFeedsLiveAndStore(t *testing.T) {
+	t.Parallel()
+
+	store, err := NewStore(t.TempDir()+"/metrics.json", 24*time.Hour)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, store.Close())
+	})
+
+	l := NewLive()
+	c := NewLiveCollector(store, l)
+
+	c.Record(Metric{SessionID: "s1", ToolName: "spec_list", Success: true})
+	c.RecordCost("openai", "gpt-4o", 10, 20, 0.25)
+	c.RecordSkillMatch("go-api")
+	require.NoError(t, c.Shutdown(context.Background()))
+
+	assert.Len(t, store.GetAll(), 1)
+	out := scrape(t, l)
+	assert.Contains(t, out, `goent_tool_calls_total{tool="spec_list",status="success"} 1`)
+	assert.Contains(t, out, `goent_cost_usd_total{provider="openai",model="gpt-4o"} 0.25`)
+	assert.Contains(t, out, `goent_skill_matches_total{skill="go-api"} 1`)
+}
+
+func TestCollector_RecordWithoutStore(t *testing.T) {
+	t.Parallel()
+
+	l := NewLive()
+	c := NewLiveCollector(nil, l)
+	c.Record(Metric{ToolName: "spec_list", Success: true})
+	require.NoError(t, c.Shutdown(context.Background()))
+
+	assert.Contains(t, scrape(t, l), `goent_tool_calls_total{tool="spec_list",status="success"} 1`)
+}
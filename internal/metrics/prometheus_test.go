@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Counter(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	c := r.NewCounterVec("calls_total", "Calls.", "tool", "status")
+
+	c.Inc("spec_list", "success")
+	c.Add(2, "spec_list", "success")
+	c.Add(-5, "spec_list", "success")
+	c.Inc("spec_show", "error")
+
+	assert.Equal(t, 3.0, c.Value("spec_list", "success"))
+	assert.Equal(t, 1.0, c.Value("spec_show", "error"))
+	assert.Equal(t, 0.0, c.Value("spec_show", "success"))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePrometheus(&buf))
+	assert.Equal(t, "# HELP calls_total Calls.\n"+
+		"# TYPE calls_total counter\n"+
+		`calls_total{tool="spec_list",status="success"} 3`+"\n"+
+		`calls_total{tool="spec_show",status="error"} 1`+"\n", buf.String())
+}
+
+func TestRegistry_Gauge(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	g := r.NewGaugeVec("queue_depth", "Depth.")
+	g.Set(4)
+	g.Set(2)
+
+	assert.Equal(t, 2.0, g.Value())
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), "# TYPE queue_depth gauge\nqueue_depth 2\n")
+}
+
+func TestRegistry_Histogram(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	h := r.NewHistogramVec("latency_seconds", "Latency.", []float64{1, 0.1}, "tool")
+	h.Observe(0.05, "a")
+	h.Observe(0.5, "a")
+	h.Observe(3, "a")
+
+	assert.Equal(t, uint64(3), h.Count("a"))
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePrometheus(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE latency_seconds histogram\n")
+	assert.Contains(t, out, `latency_seconds_bucket{tool="a",le="0.1"} 1`)
+	assert.Contains(t, out, `latency_seconds_bucket{tool="a",le="1"} 2`)
+	assert.Contains(t, out, `latency_seconds_bucket{tool="a",le="+Inf"} 3`)
+	assert.Contains(t, out, `latency_seconds_sum{tool="a"} 3.55`)
+	assert.Contains(t, out, `latency_seconds_count{tool="a"} 3`)
+}
+
+func TestRegistry_GaugeFunc(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewGaugeFunc("workers", "Workers.", "status", func() map[string]float64 {
+		return map[string]float64{"running": 2, "idle": 1}
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), "workers{status=\"idle\"} 1\nworkers{status=\"running\"} 2\n")
+}
+
+func TestRegistry_EscapesLabels(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewCounterVec("escaped_total", "Escaped.", "v").Inc("a\"b\\c\nd")
+
+	var buf bytes.Buffer
+	require.NoError(t, r.WritePrometheus(&buf))
+	assert.Contains(t, buf.String(), `escaped_total{v="a\"b\\c\nd"} 1`)
+}
+
+func TestRegistry_RegisterPanics(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewCounterVec("dup_total", "Dup.")
+
+	assert.Panics(t, func() { r.NewCounterVec("dup_total", "Dup.") })
+	assert.Panics(t, func() { r.NewCounterVec("bad-name", "Bad.") })
+	assert.Panics(t, func() { r.NewCounterVec("labels_total", "Labels.", "a").Inc() })
+}
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.NewCounterVec("served_total", "Served.").Inc()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "served_total 1\n")
+}
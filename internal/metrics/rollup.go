@@ -0,0 +1,274 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// Granularity is the bucket width of a rollup.
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// Truncate returns the start of the bucket containing t, in UTC.
+func (g Granularity) Truncate(t time.Time) time.Time {
+	t = t.UTC()
+	if g == GranularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// Query selects metrics by tool and time range. Zero values match everything.
+type Query struct {
+	Tool  string
+	Start time.Time
+	End   time.Time
+}
+
+// Matches reports whether m falls within the query.
+func (q Query) Matches(m Metric) bool {
+	if q.Tool != "" && m.ToolName != q.Tool {
+		return false
+	}
+	if !q.Start.IsZero() && m.Timestamp.Before(q.Start) {
+		return false
+	}
+	if !q.End.IsZero() && m.Timestamp.After(q.End) {
+		return false
+	}
+	return true
+}
+
+// Filter returns the query as a raw metric filter.
+func (q Query) Filter() Filter {
+	return q.Matches
+}
+
+// RollupSource is implemented by stores that maintain pre-aggregated rollups.
+type RollupSource interface {
+	Rollups(g Granularity, q Query) ([]Rollup, error)
+}
+
+// Rollup aggregates all metrics of one tool within one time bucket.
+type Rollup struct {
+	Start       time.Time     `json:"start"`
+	Granularity Granularity   `json:"granularity"`
+	Tool        string        `json:"tool"`
+	Count       int           `json:"count"`
+	Successes   int           `json:"successes"`
+	TokensIn    int64         `json:"tokens_in"`
+	TokensOut   int64         `json:"tokens_out"`
+	Duration    time.Duration `json:"duration"`
+
+	DurationHist  Histogram `json:"duration_hist"`
+	TokensInHist  Histogram `json:"tokens_in_hist"`
+	TokensOutHist Histogram `json:"tokens_out_hist"`
+}
+
+// Observe adds a single metric to the rollup.
+func (r *Rollup) Observe(m Metric) {
+	r.Count++
+	if m.Success {
+		r.Successes++
+	}
+	r.TokensIn += int64(m.TokensIn)
+	r.TokensOut += int64(m.TokensOut)
+	r.Duration += m.Duration
+
+	r.DurationHist.Observe(float64(m.Duration))
+	r.TokensInHist.Observe(float64(m.TokensIn))
+	r.TokensOutHist.Observe(float64(m.TokensOut))
+}
+
+// Merge adds the totals of o to r.
+func (r *Rollup) Merge(o Rollup) {
+	r.Count += o.Count
+	r.Successes += o.Successes
+	r.TokensIn += o.TokensIn
+	r.TokensOut += o.TokensOut
+	r.Duration += o.Duration
+
+	r.DurationHist.Merge(o.DurationHist)
+	r.TokensInHist.Merge(o.TokensInHist)
+	r.TokensOutHist.Merge(o.TokensOutHist)
+}
+
+// AverageDuration returns the mean duration.
+func (r *Rollup) AverageDuration() time.Duration {
+	if r.Count == 0 {
+		return 0
+	}
+	return r.Duration / time.Duration(r.Count)
+}
+
+// AverageTokensIn returns the mean input tokens.
+func (r *Rollup) AverageTokensIn() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+	return float64(r.TokensIn) / float64(r.Count)
+}
+
+// AverageTokensOut returns the mean output tokens.
+func (r *Rollup) AverageTokensOut() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+	return float64(r.TokensOut) / float64(r.Count)
+}
+
+// SuccessRate returns the success percentage (0-100).
+func (r *Rollup) SuccessRate() float64 {
+	if r.Count == 0 {
+		return 0
+	}
+	return float64(r.Successes) / float64(r.Count) * 100
+}
+
+// Percentile estimates the p-th percentile of field (tokensIn, tokensOut,
+// duration) from the rollup histogram.
+func (r *Rollup) Percentile(field string, p float64) (float64, error) {
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("invalid percentile %.2f: %w", p, ErrInvalidPercentile)
+	}
+
+	switch field {
+	case "duration":
+		return r.DurationHist.Quantile(p), nil
+	case "tokensIn":
+		return r.TokensInHist.Quantile(p), nil
+	case "tokensOut":
+		return r.TokensOutHist.Quantile(p), nil
+	default:
+		return 0, nil
+	}
+}
+
+// buildRollups groups raw metrics into rollups of granularity g, sorted by
+// start time and tool.
+func buildRollups(metrics []Metric, g Granularity) []Rollup {
+	type key struct {
+		start int64
+		tool  string
+	}
+
+	byKey := make(map[key]*Rollup)
+	for _, m := range metrics {
+		start := g.Truncate(m.Timestamp)
+		k := key{start: start.Unix(), tool: m.ToolName}
+		r, ok := byKey[k]
+		if !ok {
+			r = &Rollup{Start: start, Granularity: g, Tool: m.ToolName}
+			byKey[k] = r
+		}
+		r.Observe(m)
+	}
+
+	out := make([]Rollup, 0, len(byKey))
+	for _, r := range byKey {
+		out = append(out, *r)
+	}
+	sortRollups(out)
+	return out
+}
+
+func sortRollups(rollups []Rollup) {
+	sort.Slice(rollups, func(i, j int) bool {
+		if !rollups[i].Start.Equal(rollups[j].Start) {
+			return rollups[i].Start.Before(rollups[j].Start)
+		}
+		return rollups[i].Tool < rollups[j].Tool
+	})
+}
+
+// histogramGrowth is the ratio between consecutive histogram bucket bounds.
+// Quantiles estimated from the histogram are within ~5% of the true value.
+const histogramGrowth = 1.1
+
+var logHistogramGrowth = math.Log(histogramGrowth)
+
+// Histogram is a sparse log-scale histogram. Bucket i holds values in
+// (growth^(i-1), growth^i]; values <= 0 are counted in Zero.
+type Histogram struct {
+	Zero    uint64         `json:"zero,omitempty"`
+	Buckets map[int]uint64 `json:"buckets,omitempty"`
+}
+
+func histogramBucket(v float64) int {
+	return int(math.Ceil(math.Log(v) / logHistogramGrowth))
+}
+
+// Observe records a value.
+func (h *Histogram) Observe(v float64) {
+	if v <= 0 {
+		h.Zero++
+		return
+	}
+	if h.Buckets == nil {
+		h.Buckets = make(map[int]uint64)
+	}
+	h.Buckets[histogramBucket(v)]++
+}
+
+// Merge adds the counts of o.
+func (h *Histogram) Merge(o Histogram) {
+	h.Zero += o.Zero
+	if len(o.Buckets) == 0 {
+		return
+	}
+	if h.Buckets == nil {
+		h.Buckets = make(map[int]uint64, len(o.Buckets))
+	}
+	for i, n := range o.Buckets {
+		h.Buckets[i] += n
+	}
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	total := h.Zero
+	for _, n := range h.Buckets {
+		total += n
+	}
+	return total
+}
+
+// Quantile estimates the q-th quantile (0-1), interpolating linearly within
+// the bucket that contains it. The estimate never leaves that bucket.
+func (h *Histogram) Quantile(q float64) float64 {
+	total := h.Count()
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total-1)
+	if rank < float64(h.Zero) {
+		return 0
+	}
+
+	indexes := make([]int, 0, len(h.Buckets))
+	for i := range h.Buckets {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	seen := float64(h.Zero)
+	for _, i := range indexes {
+		n := float64(h.Buckets[i])
+		if rank < seen+n {
+			lower := math.Pow(histogramGrowth, float64(i-1))
+			upper := math.Pow(histogramGrowth, float64(i))
+			frac := math.Min(math.Max((rank-seen+0.5)/n, 0), 1)
+			return lower + (upper-lower)*frac
+		}
+		seen += n
+	}
+
+	return math.Pow(histogramGrowth, float64(indexes[len(indexes)-1]))
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_Quantile(t *testing.T) {
+	t.Parallel()
+
+	var h Histogram
+	for i := 1; i <= 1000; i++ {
+		h.Observe(float64(i))
+	}
+	h.Observe(0)
+
+	assert.Equal(t, uint64(1001), h.Count())
+	assert.Equal(t, float64(0), h.Quantile(0))
+
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{q: 0.50, want: 500},
+		{q: 0.95, want: 950},
+		{q: 0.99, want: 990},
+	}
+
+	for _, tt := range tests {
+		got := h.Quantile(tt.q)
+		assert.InDelta(t, tt.want, got, tt.want*0.05, "q=%.2f", tt.q)
+	}
+}
+
+func TestHistogram_QuantileStaysInBucket(t *testing.T) {
+	t.Parallel()
+
+	var h Histogram
+	h.Observe(10)
+	h.Observe(100)
+
+	bounds := func(v float64) (float64, float64) {
+		i := histogramBucket(v)
+		return math.Pow(histogramGrowth, float64(i-1)), math.Pow(histogramGrowth, float64(i))
+	}
+
+	lower, upper := bounds(10)
+	for _, q := range []float64{0, 0.25, 0.5, 0.9, 0.99} {
+		got := h.Quantile(q)
+		assert.GreaterOrEqual(t, got, lower, "q=%.2f", q)
+		assert.LessOrEqual(t, got, upper, "q=%.2f", q)
+	}
+
+	lower, upper = bounds(100)
+	got := h.Quantile(1)
+	assert.GreaterOrEqual(t, got, lower)
+	assert.LessOrEqual(t, got, upper)
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	t.Parallel()
+
+	var a, b Histogram
+	for i := 1; i <= 100; i++ {
+		a.Observe(float64(i))
+		b.Observe(float64(i + 100))
+	}
+
+	var empty Histogram
+	empty.Merge(a)
+	empty.Merge(b)
+
+	assert.Equal(t, uint64(200), empty.Count())
+	assert.InDelta(t, 100, empty.Quantile(0.5), 5)
+	assert.GreaterOrEqual(t, empty.Quantile(1), 190.0)
+}
+
+func TestBuildRollups(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 10, 14, 20, 0, 0, time.UTC)
+	metrics := []Metric{
+		{ToolName: "a", Timestamp: base, Duration: 100 * time.Millisecond, TokensIn: 10, TokensOut: 20, Success: true},
+		{ToolName: "a", Timestamp: base.Add(10 * time.Minute), Duration: 300 * time.Millisecond, TokensIn: 30, TokensOut: 40, Success: false},
+		{ToolName: "b", Timestamp: base.Add(time.Hour), Duration: time.Second, TokensIn: 5, TokensOut: 5, Success: true},
+	}
+
+	hourly := buildRollups(metrics, GranularityHour)
+	require.Len(t, hourly, 2)
+	assert.Equal(t, "a", hourly[0].Tool)
+	assert.Equal(t, time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC), hourly[0].Start)
+	assert.Equal(t, 2, hourly[0].Count)
+	assert.Equal(t, 200*time.Millisecond, hourly[0].AverageDuration())
+	assert.InDelta(t, 20, hourly[0].AverageTokensIn(), 0.01)
+	assert.InDelta(t, 30, hourly[0].AverageTokensOut(), 0.01)
+	assert.InDelta(t, 50, hourly[0].SuccessRate(), 0.01)
+	assert.Equal(t, "b", hourly[1].Tool)
+
+	daily := buildRollups(metrics, GranularityDay)
+	require.Len(t, daily, 2)
+	assert.Equal(t, time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), daily[0].Start)
+}
+
+func TestRollup_Percentile(t *testing.T) {
+	t.Parallel()
+
+	var r Rollup
+	for i := 1; i <= 100; i++ {
+		r.Observe(Metric{Duration: time.Duration(i) * time.Millisecond, TokensIn: i, TokensOut: 2 * i})
+	}
+
+	p95, err := r.Percentile("duration", 0.95)
+	require.NoError(t, err)
+	assert.InDelta(t, float64(95*time.Millisecond), p95, float64(5*time.Millisecond))
+
+	p50, err := r.Percentile("tokensOut", 0.5)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, p50, 5)
+
+	_, err = r.Percentile("duration", 1.5)
+	assert.ErrorIs(t, err, ErrInvalidPercentile)
+}
+
+func TestQuery_Matches(t *testing.T) {
+	t.Parallel()
+
+	base := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	m := Metric{ToolName: "a", Timestamp: base}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  bool
+	}{
+		{name: "empty", query: Query{}, want: true},
+		{name: "tool match", query: Query{Tool: "a"}, want: true},
+		{name: "tool mismatch", query: Query{Tool: "b"}, want: false},
+		{name: "in range", query: Query{Start: base.Add(-time.Hour), End: base.Add(time.Hour)}, want: true},
+		{name: "before start", query: Query{Start: base.Add(time.Minute)}, want: false},
+		{name: "after end", query: Query{End: base.Add(-time.Minute)}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.query.Matches(m))
+		})
+	}
+}
@@ -28,6 +28,22 @@ type Metric struct {
 	Metadata  map[string]string
 }
 
+// Source provides raw metrics to the Aggregator and Exporter.
+type Source interface {
+	GetAll() []Metric
+	Filter(filter func(Metric) bool) []Metric
+}
+
+// Backend is a metrics store the Collector and MCP tools can write to.
+// Store and DurableStore implement it.
+type Backend interface {
+	Source
+	Add(m Metric) error
+	Count() int
+	Clear() error
+	Close() error
+}
+
 // Store manages metrics with in-memory ring buffer and file persistence.
 type Store struct {
 	mu        sync.RWMutex
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 type jsonrpcRequest struct {
@@ -465,6 +466,23 @@ func (c *ACPClient) Updates() <-chan SessionUpdateNotification {
 func (c *ACPClient) sendRequest(ctx context.Context, method string, params interface{}, result interface{}) error {
 	reqID := uuid.Must(uuid.NewV7()).String()
 
+	_, span := tracing.Start(ctx, "acp."+method,
+		tracing.String("rpc.system", "jsonrpc"),
+		tracing.String("rpc.method", method),
+		tracing.String("rpc.jsonrpc.request_id", reqID),
+	)
+	defer span.End()
+	span.SetKind(tracing.SpanKindClient)
+	if c.sessionID != "" {
+		span.SetAttributes(tracing.String("goent.acp.session_id", c.sessionID))
+	}
+
+	err := c.doSendRequest(ctx, reqID, method, params, result)
+	span.RecordError(err)
+	return err
+}
+
+func (c *ACPClient) doSendRequest(ctx context.Context, reqID, method string, params interface{}, result interface{}) error {
 	req := jsonrpcRequest{
 		JSONRPC: "2.0",
 		ID:      reqID,
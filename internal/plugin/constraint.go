@@ -0,0 +1,264 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version. Missing minor or patch parts parse
+// as zero.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	Pre   string
+}
+
+// ParseVersion parses "1.2.3", "v1.2.3" or "1.2.3-rc.1".
+func ParseVersion(s string) (Version, error) {
+	v, parts, err := parsePartial(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if parts != 3 {
+		return Version{}, fmt.Errorf("invalid version %q (expected semver: x.y.z)", s)
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1. A pre-release sorts before its release.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	}
+	return strings.Compare(v.Pre, o.Pre)
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// parsePartial parses a version that may omit minor and patch or use "x"
+// for them, returning how many numeric parts were given.
+func parsePartial(s string) (Version, int, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+
+	var v Version
+	if base, pre, ok := strings.Cut(s, "-"); ok {
+		s, v.Pre = base, pre
+	}
+
+	fields := strings.Split(s, ".")
+	if len(fields) > 3 || s == "" {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	parts := 0
+	for i, f := range fields {
+		if f == "x" || f == "X" || f == "*" {
+			break
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+		parts++
+	}
+
+	return v, parts, nil
+}
+
+type bound struct {
+	op string
+	v  Version
+}
+
+func (b bound) allows(v Version) bool {
+	c := v.Compare(b.v)
+	switch b.op {
+	case ">=":
+		return c >= 0
+	case ">":
+		return c > 0
+	case "<=":
+		return c <= 0
+	case "<":
+		return c < 0
+	default:
+		return c == 0
+	}
+}
+
+// Constraint is a semver range such as "^1.2", "~1.2.3", ">=1.0, <2.0",
+// "1.x" or "1.2.3 || ^2". An empty constraint, "*" or "latest" allows any
+// version.
+type Constraint struct {
+	raw  string
+	sets [][]bound
+}
+
+// ParseConstraint parses a version range. Comma or space separated terms
+// must all hold; "||" separates alternatives.
+func ParseConstraint(s string) (Constraint, error) {
+	c := Constraint{raw: strings.TrimSpace(s)}
+
+	for _, alt := range strings.Split(s, "||") {
+		var set []bound
+		for _, term := range strings.Fields(strings.ReplaceAll(alt, ",", " ")) {
+			bounds, err := parseTerm(term)
+			if err != nil {
+				return Constraint{}, fmt.Errorf("constraint %q: %w", s, err)
+			}
+			set = append(set, bounds...)
+		}
+		c.sets = append(c.sets, set)
+	}
+
+	return c, nil
+}
+
+func parseTerm(term string) ([]bound, error) {
+	if term == "*" || term == "latest" {
+		return nil, nil
+	}
+
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, prefix) {
+			op, term = prefix, term[len(prefix):]
+			break
+		}
+	}
+
+	v, parts, err := parsePartial(term)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case ">=", "<=", ">", "<":
+		return []bound{{op, v}}, nil
+	case "^":
+		upper := Version{Major: v.Major + 1}
+		switch {
+		case v.Major == 0 && parts >= 2 && v.Minor > 0:
+			upper = Version{Minor: v.Minor + 1}
+		case v.Major == 0 && parts == 3:
+			upper = Version{Minor: v.Minor, Patch: v.Patch + 1}
+		case v.Major == 0 && parts == 2:
+			upper = Version{Minor: 1}
+		}
+		return []bound{{">=", v}, {"<", upper}}, nil
+	case "~":
+		if parts <= 1 {
+			return []bound{{">=", v}, {"<", Version{Major: v.Major + 1}}}, nil
+		}
+		return []bound{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}, nil
+	}
+
+	switch parts {
+	case 0:
+		return nil, nil
+	case 1:
+		return []bound{{">=", v}, {"<", Version{Major: v.Major + 1}}}, nil
+	case 2:
+		return []bound{{">=", v}, {"<", Version{Major: v.Major, Minor: v.Minor + 1}}}, nil
+	}
+	return []bound{{"=", v}}, nil
+}
+
+// Check reports whether version satisfies the constraint. Unparsable
+// versions satisfy only an unconstrained range.
+func (c Constraint) Check(version string) bool {
+	if c.Any() {
+		return true
+	}
+
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, set := range c.sets {
+		ok := true
+		for _, b := range set {
+			if !b.allows(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Any reports whether the constraint allows every version.
+func (c Constraint) Any() bool {
+	for _, set := range c.sets {
+		if len(set) == 0 {
+			return true
+		}
+	}
+	return len(c.sets) == 0
+}
+
+// Exact returns the version when the constraint pins a single version.
+func (c Constraint) Exact() (string, bool) {
+	if len(c.sets) != 1 || len(c.sets[0]) != 1 || c.sets[0][0].op != "=" {
+		return "", false
+	}
+	return c.sets[0][0].v.String(), true
+}
+
+func (c Constraint) String() string {
+	if c.raw == "" {
+		return "*"
+	}
+	return c.raw
+}
+
+// checkMinVersion fails when current is older than the minimum go-ent
+// version a plugin declares. Development builds satisfy any minimum.
+func checkMinVersion(plugin, minimum, current string) error {
+	if minimum == "" {
+		return nil
+	}
+
+	want, err := ParseVersion(minimum)
+	if err != nil {
+		return fmt.Errorf("plugin %s: min_version: %w", plugin, err)
+	}
+
+	have, err := ParseVersion(current)
+	if err != nil {
+		return nil
+	}
+
+	if have.Compare(want) < 0 {
+		return fmt.Errorf("plugin %s requires go-ent %s or newer (running %s)", plugin, want, have)
+	}
+	return nil
+}
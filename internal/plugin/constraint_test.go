@@ -0,0 +1,62 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstraint_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		constraint string
+		allowed    []string
+		denied     []string
+	}{
+		{constraint: "", allowed: []string{"0.0.1", "9.9.9"}},
+		{constraint: "latest", allowed: []string{"1.0.0"}},
+		{constraint: "1.2.3", allowed: []string{"1.2.3", "v1.2.3"}, denied: []string{"1.2.4"}},
+		{constraint: "^1.2", allowed: []string{"1.2.0", "1.9.9"}, denied: []string{"1.1.9", "2.0.0"}},
+		{constraint: "^0.2.3", allowed: []string{"0.2.3", "0.2.9"}, denied: []string{"0.3.0"}},
+		{constraint: "~1.2.3", allowed: []string{"1.2.3", "1.2.9"}, denied: []string{"1.3.0"}},
+		{constraint: "1.x", allowed: []string{"1.0.0", "1.5.2"}, denied: []string{"2.0.0"}},
+		{constraint: ">=1.0, <2.0", allowed: []string{"1.0.0", "1.99.0"}, denied: []string{"0.9.0", "2.0.0"}},
+		{constraint: "<1.0 || >=3", allowed: []string{"0.5.0", "3.1.0"}, denied: []string{"2.0.0"}},
+		{constraint: ">=1.0.0", allowed: []string{"1.0.0"}, denied: []string{"1.0.0-rc.1", "dev"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			for _, v := range tt.allowed {
+				assert.True(t, c.Check(v), "%s should allow %s", tt.constraint, v)
+			}
+			for _, v := range tt.denied {
+				assert.False(t, c.Check(v), "%s should deny %s", tt.constraint, v)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{"^one", ">=1.2.3.4", "~"} {
+		_, err := ParseConstraint(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestCheckMinVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkMinVersion("p", "", "1.0.0"))
+	assert.NoError(t, checkMinVersion("p", "2.0.0", "2.1.0"))
+	assert.NoError(t, checkMinVersion("p", "2.0.0", "dev"))
+	assert.ErrorContains(t, checkMinVersion("p", "3.1.0", "v3.0.0"), "requires go-ent 3.1.0 or newer")
+}
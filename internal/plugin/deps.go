@@ -0,0 +1,380 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/agent"
+	"github.com/victorzhuk/go-ent/internal/marketplace"
+)
+
+// VersionLister is implemented by marketplace clients that can list every
+// published version of a plugin. Without it, dependencies resolve to the
+// latest version, or to the version a constraint pins.
+type VersionLister interface {
+	Versions(ctx context.Context, name string) ([]string, error)
+}
+
+type requirement struct {
+	name       string
+	constraint Constraint
+	requiredBy string
+}
+
+func (r requirement) String() string {
+	if r.requiredBy == "" {
+		return fmt.Sprintf("%s %s (requested)", r.name, r.constraint)
+	}
+	return fmt.Sprintf("%s %s (required by %s)", r.name, r.constraint, r.requiredBy)
+}
+
+// resolution is the set of plugins an install adds. Archives are extracted
+// into a staging directory and only moved into the plugins directory once
+// every constraint holds.
+type resolution struct {
+	staging  string
+	client   marketplace.Client
+	opts     []marketplace.InstallerOption
+	fetched  map[string]*fetchedPlugin
+	selected map[string]*Plugin
+	locks    map[string]marketplace.LockEntry
+	reasons  map[string][]requirement
+}
+
+// fetchedPlugin is one downloaded candidate version, kept so backtracking
+// does not download it again.
+type fetchedPlugin struct {
+	plugin *Plugin
+	lock   marketplace.LockEntry
+	err    error
+}
+
+// resolve installs name and its transitive dependencies into a staging
+// directory, returning the plugins to add in dependency order.
+func (m *Manager) resolve(ctx context.Context, client marketplace.Client, name, version string) (*resolution, []string, error) {
+	root, err := ParseConstraint(version)
+	if err != nil {
+		return nil, nil, fmt.Errorf("version: %w", err)
+	}
+
+	staging, err := os.MkdirTemp(m.pluginsDir, ".staging-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create staging directory: %w", err)
+	}
+
+	r := &resolution{
+		staging: staging,
+		client:  client,
+		opts: []marketplace.InstallerOption{
+			marketplace.WithTrustedKeys(m.trust.Keys),
+			marketplace.WithAllowUnsigned(m.trust.AllowUnsigned),
+		},
+		fetched:  make(map[string]*fetchedPlugin),
+		selected: make(map[string]*Plugin),
+		locks:    make(map[string]marketplace.LockEntry),
+		reasons:  make(map[string][]requirement),
+	}
+
+	if err := m.solve(ctx, r, []requirement{{name: name, constraint: root}}); err != nil {
+		r.cleanup()
+		return nil, nil, err
+	}
+
+	order, err := m.installOrder(r.selected)
+	if err != nil {
+		r.cleanup()
+		return nil, nil, err
+	}
+
+	return r, order, nil
+}
+
+// solve satisfies queue in order. Each plugin gets the newest version that
+// meets every pending constraint on it; when a later constraint rules that
+// version out, solve backtracks and tries the next older candidate, as it
+// does when a candidate cannot be downloaded. It returns the download errors
+// and the last conflict when no combination works.
+func (m *Manager) solve(ctx context.Context, r *resolution, queue []requirement) error {
+	if len(queue) == 0 {
+		return nil
+	}
+	req, rest := queue[0], queue[1:]
+
+	if installed, ok := m.plugins[req.name]; ok {
+		if !req.constraint.Check(installed.Manifest.Version) {
+			return fmt.Errorf("dependency conflict: %s@%s is installed, but %s", req.name, installed.Manifest.Version, req)
+		}
+		return m.solve(ctx, r, rest)
+	}
+
+	r.reasons[req.name] = append(r.reasons[req.name], req)
+	defer func() { r.reasons[req.name] = r.reasons[req.name][:len(r.reasons[req.name])-1] }()
+
+	if staged, ok := r.selected[req.name]; ok {
+		if !req.constraint.Check(staged.Manifest.Version) {
+			return fmt.Errorf("dependency conflict on %s@%s: %s", req.name, staged.Manifest.Version, r.explain(req.name))
+		}
+		return m.solve(ctx, r, rest)
+	}
+
+	pending := []requirement{req}
+	for _, other := range rest {
+		if other.name == req.name {
+			pending = append(pending, other)
+		}
+	}
+
+	versions, err := candidateVersions(ctx, r.client, pending)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	var fetchErrs []error
+	for _, version := range versions {
+		f := r.fetch(ctx, req.name, version)
+		if f.err != nil {
+			if ctx.Err() != nil {
+				return f.err
+			}
+			fetchErrs = append(fetchErrs, f.err)
+			continue
+		}
+		p := f.plugin
+
+		if err := checkMinVersion(p.Manifest.Name, p.Manifest.MinVersion, m.appVersion); err != nil {
+			lastErr = err
+			continue
+		}
+		if unmet := unmetRequirement(pending, p.Manifest.Version); unmet != nil {
+			lastErr = fmt.Errorf("no version of %s satisfies %s: marketplace returned %s", req.name, unmet.constraint, p.Manifest.Version)
+			continue
+		}
+
+		next := slices.Clone(rest)
+		for _, dep := range p.Manifest.Requires {
+			c, err := ParseConstraint(dep.Version)
+			if err != nil {
+				return fmt.Errorf("plugin %s: %w", p.Manifest.Name, err)
+			}
+			next = append(next, requirement{name: dep.Name, constraint: c, requiredBy: p.Manifest.Name + "@" + p.Manifest.Version})
+		}
+
+		r.selected[req.name] = p
+		r.locks[req.name] = f.lock
+		if lastErr = m.solve(ctx, r, next); lastErr == nil {
+			return nil
+		}
+		delete(r.selected, req.name)
+		delete(r.locks, req.name)
+	}
+
+	return errors.Join(append(fetchErrs, lastErr)...)
+}
+
+// candidateVersions lists the versions to try for the plugin reqs name,
+// newest first. Without a VersionLister the marketplace picks the latest
+// version, or the version a constraint pins.
+func candidateVersions(ctx context.Context, client marketplace.Client, reqs []requirement) ([]string, error) {
+	req := reqs[0]
+	if v, ok := req.constraint.Exact(); ok {
+		if unmetRequirement(reqs, v) != nil {
+			return nil, unsatisfiable(reqs)
+		}
+		return []string{v}, nil
+	}
+
+	lister, ok := client.(VersionLister)
+	if !ok {
+		return []string{"latest"}, nil
+	}
+
+	versions, err := lister.Versions(ctx, req.name)
+	if err != nil {
+		return nil, fmt.Errorf("list versions of %s: %w", req.name, err)
+	}
+
+	var matching []string
+	for _, v := range versions {
+		if unmetRequirement(reqs, v) == nil {
+			matching = append(matching, v)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, unsatisfiable(reqs)
+	}
+
+	return matching, nil
+}
+
+// unsatisfiable reports that no version meets every one of reqs.
+func unsatisfiable(reqs []requirement) error {
+	parts := make([]string, 0, len(reqs))
+	for _, r := range reqs {
+		parts = append(parts, r.String())
+	}
+	if len(reqs) > 1 {
+		return fmt.Errorf("dependency conflict on %s: no published version satisfies %s", reqs[0].name, strings.Join(parts, "; "))
+	}
+	return fmt.Errorf("no published version satisfies %s", parts[0])
+}
+
+// unmetRequirement returns the first of reqs that version does not satisfy.
+func unmetRequirement(reqs []requirement, version string) *requirement {
+	for i := range reqs {
+		if !reqs[i].constraint.Check(version) {
+			return &reqs[i]
+		}
+	}
+	return nil
+}
+
+// fetch downloads one version of name into its own staging subdirectory.
+func (r *resolution) fetch(ctx context.Context, name, version string) *fetchedPlugin {
+	key := name + "@" + version
+	if f, ok := r.fetched[key]; ok {
+		return f
+	}
+
+	f := &fetchedPlugin{}
+	f.plugin, f.lock, f.err = r.download(ctx, filepath.Join(r.staging, key), name, version)
+	r.fetched[key] = f
+	return f
+}
+
+func (r *resolution) download(ctx context.Context, dir, name, version string) (*Plugin, marketplace.LockEntry, error) {
+	installer := marketplace.NewInstaller(r.client, dir, r.opts...)
+	if err := installer.Install(ctx, name, version); err != nil {
+		return nil, marketplace.LockEntry{}, fmt.Errorf("install %s: %w", name, err)
+	}
+	if err := installer.Validate(name); err != nil {
+		return nil, marketplace.LockEntry{}, fmt.Errorf("validate %s: %w", name, err)
+	}
+
+	root := filepath.Join(dir, name)
+	manifest, err := ParseManifest(filepath.Join(root, ManifestFile))
+	if err != nil {
+		return nil, marketplace.LockEntry{}, fmt.Errorf("plugin %s: %w", name, err)
+	}
+	if manifest.Name != name {
+		return nil, marketplace.LockEntry{}, fmt.Errorf("plugin %s: archive contains plugin %s", name, manifest.Name)
+	}
+
+	lock, err := marketplace.LoadLockfile(filepath.Join(dir, marketplace.LockFile))
+	if err != nil {
+		return nil, marketplace.LockEntry{}, err
+	}
+
+	return &Plugin{Manifest: *manifest, RootPath: root, Installed: true, Enabled: true}, lock.Plugins[name], nil
+}
+
+// installOrder sorts the selected plugins so dependencies come first,
+// rejecting dependency cycles.
+func (m *Manager) installOrder(selected map[string]*Plugin) ([]string, error) {
+	graph := agent.NewDependencyGraph()
+	for name := range m.plugins {
+		graph.AddNode(name, agent.AgentMeta{Name: name})
+	}
+	for name := range selected {
+		graph.AddNode(name, agent.AgentMeta{Name: name})
+	}
+	for name, p := range selected {
+		for _, dep := range p.Manifest.Requires {
+			graph.AddEdge(name, dep.Name)
+		}
+	}
+
+	names := make([]string, 0, len(selected))
+	for name := range selected {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	resolved, err := agent.NewResolver(graph).ResolveDependencies(names)
+	if err != nil {
+		return nil, fmt.Errorf("resolve plugin dependencies: %w", err)
+	}
+
+	// The resolver lists dependents before their dependencies.
+	slices.Reverse(resolved)
+
+	order := make([]string, 0, len(selected))
+	for _, name := range resolved {
+		if _, ok := selected[name]; ok {
+			order = append(order, name)
+		}
+	}
+	return order, nil
+}
+
+// commit moves the staged plugins into pluginsDir in order and records them
+// in the lockfile.
+func (r *resolution) commit(pluginsDir string, order []string) ([]*Plugin, error) {
+	defer r.cleanup()
+
+	added := make([]*Plugin, 0, len(order))
+	for _, name := range order {
+		p := r.selected[name]
+		dir := filepath.Join(pluginsDir, name)
+		if err := os.Rename(p.RootPath, dir); err != nil {
+			for _, done := range added {
+				_ = os.RemoveAll(done.RootPath)
+			}
+			return nil, fmt.Errorf("move plugin %s: %w", name, err)
+		}
+		p.RootPath = dir
+		added = append(added, p)
+	}
+
+	err := marketplace.UpdateLockfile(filepath.Join(pluginsDir, marketplace.LockFile), func(l *marketplace.Lockfile) {
+		for _, p := range added {
+			entry, ok := r.locks[p.Manifest.Name]
+			if !ok || entry.Version == "" {
+				entry = marketplace.LockEntry{Version: p.Manifest.Version, InstalledAt: time.Now().UTC()}
+			}
+			entry.Requires = nil
+			for _, dep := range p.Manifest.Requires {
+				entry.Requires = append(entry.Requires, dep.Name)
+			}
+			l.Plugins[p.Manifest.Name] = entry
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("update lockfile: %w", err)
+	}
+
+	return added, nil
+}
+
+func (r *resolution) cleanup() {
+	_ = os.RemoveAll(r.staging)
+}
+
+func (r *resolution) explain(name string) string {
+	parts := make([]string, 0, len(r.reasons[name]))
+	for _, req := range r.reasons[name] {
+		parts = append(parts, req.String())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// dependents returns the installed plugins that require name, sorted.
+func (m *Manager) dependents(name string) []string {
+	var names []string
+	for _, p := range m.plugins {
+		for _, dep := range p.Manifest.Requires {
+			if dep.Name == name {
+				names = append(names, p.Manifest.Name)
+				break
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
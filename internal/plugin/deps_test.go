@@ -0,0 +1,317 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/marketplace"
+)
+
+// publish packs one plugin version into mirror. requires are "name range"
+// pairs.
+func publish(t *testing.T, mirror, name, version, minVersion string, requires ...string) {
+	t.Helper()
+
+	src := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.MkdirAll(src, 0750))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "name: %s\nversion: %s\ndescription: Test plugin\nauthor: Test\n", name, version)
+	if minVersion != "" {
+		fmt.Fprintf(&b, "min_version: %s\n", minVersion)
+	}
+	if len(requires) > 0 {
+		b.WriteString("requires:\n")
+		for _, r := range requires {
+			dep, rng, _ := strings.Cut(r, " ")
+			fmt.Fprintf(&b, "  - name: %s\n    version: %q\n", dep, rng)
+		}
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(src, ManifestFile), []byte(b.String()), 0600))
+
+	_, err := BuildIndex([]string{src}, mirror, nil, time.Now())
+	require.NoError(t, err)
+}
+
+// corrupt overwrites a published archive so downloading it fails.
+func corrupt(t *testing.T, mirror, name, version string) {
+	t.Helper()
+
+	archive, err := marketplace.ArchiveName(name, version)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(mirror, archive), []byte("corrupt"), 0600))
+}
+
+func newDepsManager(t *testing.T, mirror string) (*Manager, string) {
+	t.Helper()
+
+	pluginsDir := t.TempDir()
+	m := NewManager(pluginsDir, &mockRegistry{}, marketplace.NewFSClient(mirror), nil)
+	m.appVersion = "3.0.0"
+	m.SetAllowUnsigned(true)
+	return m, pluginsDir
+}
+
+func TestManager_Install_ResolvesDependencies(t *testing.T) {
+	t.Parallel()
+
+	mirror := t.TempDir()
+	publish(t, mirror, "base", "1.0.0", "")
+	publish(t, mirror, "base", "1.4.0", "")
+	publish(t, mirror, "base", "2.0.0", "")
+	publish(t, mirror, "lint", "0.3.0", "", "base ^1.2")
+	publish(t, mirror, "app", "1.0.0", "", "lint ~0.3", "base >=1.0, <2")
+
+	m, pluginsDir := newDepsManager(t, mirror)
+	require.NoError(t, m.Install(context.Background(), "app", "latest"))
+
+	for name, want := range map[string]string{"app": "1.0.0", "lint": "0.3.0", "base": "1.4.0"} {
+		p, err := m.Get(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, want, p.Manifest.Version, name)
+		assert.DirExists(t, filepath.Join(pluginsDir, name))
+	}
+
+	lock, err := marketplace.LoadLockfile(filepath.Join(pluginsDir, marketplace.LockFile))
+	require.NoError(t, err)
+	assert.Equal(t, "1.4.0", lock.Plugins["base"].Version)
+	assert.Equal(t, []string{"lint", "base"}, lock.Plugins["app"].Requires)
+	assert.True(t, strings.HasPrefix(lock.Plugins["lint"].Digest, "sha256:"))
+
+	entries, err := os.ReadDir(pluginsDir)
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.False(t, strings.HasPrefix(e.Name(), ".staging-"), "staging directory left behind")
+	}
+
+	err = m.Uninstall(context.Background(), "base")
+	assert.ErrorContains(t, err, "plugin base is required by app, lint")
+
+	require.NoError(t, m.Uninstall(context.Background(), "app"))
+	require.NoError(t, m.Uninstall(context.Background(), "lint"))
+	require.NoError(t, m.Uninstall(context.Background(), "base"))
+
+	lock, err = marketplace.LoadLockfile(filepath.Join(pluginsDir, marketplace.LockFile))
+	require.NoError(t, err)
+	assert.Empty(t, lock.Plugins)
+}
+
+func TestManager_Install_DependencyErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		setup   func(t *testing.T, mirror string)
+		install string
+		wantErr string
+	}{
+		{
+			name: "conflicting ranges",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "left", "1.0.0", "", "base 1.0.0")
+				publish(t, mirror, "right", "1.0.0", "", "base ^2")
+				publish(t, mirror, "app", "1.0.0", "", "left", "right")
+			},
+			install: "app",
+			wantErr: "dependency conflict on base: no published version satisfies base 1.0.0 (required by left@1.0.0); base ^2 (required by right@1.0.0)",
+		},
+		{
+			name: "disjoint ranges",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "base", "1.5.0", "")
+				publish(t, mirror, "left", "1.0.0", "", "base >=1.5")
+				publish(t, mirror, "right", "1.0.0", "", "base <1.5")
+				publish(t, mirror, "app", "1.0.0", "", "left", "right")
+			},
+			install: "app",
+			wantErr: "dependency conflict on base: no published version satisfies base >=1.5 (required by left@1.0.0); base <1.5 (required by right@1.0.0)",
+		},
+		{
+			name: "no matching version",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "app", "1.0.0", "", "base ^2")
+			},
+			install: "app",
+			wantErr: "no published version satisfies base ^2 (required by app@1.0.0)",
+		},
+		{
+			name: "missing dependency",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "app", "1.0.0", "", "ghost")
+			},
+			install: "app",
+			wantErr: "ghost",
+		},
+		{
+			name: "cycle",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "a", "1.0.0", "", "b")
+				publish(t, mirror, "b", "1.0.0", "", "a")
+			},
+			install: "a",
+			wantErr: "cycle detected",
+		},
+		{
+			name: "go-ent too old",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "3.5.0")
+				publish(t, mirror, "app", "1.0.0", "", "base")
+			},
+			install: "app",
+			wantErr: "plugin base requires go-ent 3.5.0 or newer",
+		},
+		{
+			name: "no version can be downloaded",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "base", "1.5.0", "")
+				publish(t, mirror, "app", "1.0.0", "", "base ^1")
+				corrupt(t, mirror, "base", "1.5.0")
+				corrupt(t, mirror, "base", "1.0.0")
+			},
+			install: "app",
+			wantErr: "install base",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mirror := t.TempDir()
+			tt.setup(t, mirror)
+
+			m, pluginsDir := newDepsManager(t, mirror)
+			err := m.Install(context.Background(), tt.install, "latest")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			assert.Empty(t, m.List())
+			entries, err := os.ReadDir(pluginsDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "nothing should be installed")
+		})
+	}
+}
+
+func TestManager_Install_Backtracks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, mirror string)
+		want  map[string]string
+	}{
+		{
+			name: "newest dependency conflicts with a sibling",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.4.0", "")
+				publish(t, mirror, "base", "2.0.0", "")
+				publish(t, mirror, "lint", "0.3.0", "", "base ^1")
+				publish(t, mirror, "lint", "0.4.0", "", "base ^2")
+				publish(t, mirror, "app", "1.0.0", "", "lint", "base ^1")
+			},
+			want: map[string]string{"app": "1.0.0", "lint": "0.3.0", "base": "1.4.0"},
+		},
+		{
+			name: "selected version rejected later",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "base", "1.5.0", "")
+				publish(t, mirror, "mid", "1.0.0", "", "base <1.5")
+				publish(t, mirror, "left", "1.0.0", "", "base ^1")
+				publish(t, mirror, "right", "1.0.0", "", "mid")
+				publish(t, mirror, "app", "1.0.0", "", "left", "right")
+			},
+			want: map[string]string{"app": "1.0.0", "left": "1.0.0", "right": "1.0.0", "mid": "1.0.0", "base": "1.0.0"},
+		},
+		{
+			name: "newest version cannot be downloaded",
+			setup: func(t *testing.T, mirror string) {
+				publish(t, mirror, "base", "1.0.0", "")
+				publish(t, mirror, "base", "1.5.0", "")
+				publish(t, mirror, "app", "1.0.0", "", "base ^1")
+				corrupt(t, mirror, "base", "1.5.0")
+			},
+			want: map[string]string{"app": "1.0.0", "base": "1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mirror := t.TempDir()
+			tt.setup(t, mirror)
+
+			m, pluginsDir := newDepsManager(t, mirror)
+			require.NoError(t, m.Install(context.Background(), "app", "latest"))
+
+			lock, err := marketplace.LoadLockfile(filepath.Join(pluginsDir, marketplace.LockFile))
+			require.NoError(t, err)
+			for name, want := range tt.want {
+				p, err := m.Get(name)
+				require.NoError(t, err, name)
+				assert.Equal(t, want, p.Manifest.Version, name)
+				assert.Equal(t, want, lock.Plugins[name].Version, name)
+			}
+			assert.Len(t, m.List(), len(tt.want))
+		})
+	}
+}
+
+func TestManager_Install_ChecksInstalledVersions(t *testing.T) {
+	t.Parallel()
+
+	mirror := t.TempDir()
+	publish(t, mirror, "base", "1.0.0", "")
+	publish(t, mirror, "app", "1.0.0", "", "base ^2")
+
+	m, _ := newDepsManager(t, mirror)
+	require.NoError(t, m.Install(context.Background(), "base", "1.0.0"))
+
+	err := m.Install(context.Background(), "app", "latest")
+	assert.ErrorContains(t, err, "base@1.0.0 is installed, but base ^2 (required by app@1.0.0)")
+}
+
+func TestValidator_CheckDependencies(t *testing.T) {
+	t.Parallel()
+
+	v := NewValidator()
+	v.appVersion = "3.0.0"
+	v.RegisterPlugin(&Plugin{Manifest: Manifest{Name: "base", Version: "1.2.0"}})
+
+	tests := []struct {
+		name     string
+		manifest Manifest
+		wantErr  string
+	}{
+		{name: "satisfied", manifest: Manifest{Name: "app", Requires: []Require{{Name: "base", Version: "^1.1"}}}},
+		{name: "missing", manifest: Manifest{Name: "app", Requires: []Require{{Name: "ghost"}}}, wantErr: "missing dependency: app requires ghost"},
+		{name: "wrong version", manifest: Manifest{Name: "app", Requires: []Require{{Name: "base", Version: ">=2"}}}, wantErr: "requires base >=2, found 1.2.0"},
+		{name: "min version", manifest: Manifest{Name: "app", MinVersion: "4.0.0"}, wantErr: "requires go-ent 4.0.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := v.checkDependencies(&Plugin{Manifest: tt.manifest})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
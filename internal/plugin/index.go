@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/marketplace"
+)
+
+// IndexBuildResult reports what BuildIndex packed.
+type IndexBuildResult struct {
+	Index   *marketplace.Index
+	Added   []marketplace.IndexEntry
+	Skipped []string
+}
+
+// BuildIndex packs every plugin folder found in sources into outDir and
+// writes outDir/index.yaml. A source is either a plugin folder or a folder
+// of plugin folders. Entries already in the index for other versions are
+// kept, so outDir can serve as a mirror with history. Archives are signed
+// with signer when it is not nil.
+func BuildIndex(sources []string, outDir string, signer ed25519.PrivateKey, now time.Time) (*IndexBuildResult, error) {
+	idx, err := marketplace.LoadIndex(outDir)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		idx = &marketplace.Index{}
+	}
+
+	result := &IndexBuildResult{Index: idx}
+
+	for _, src := range sources {
+		dirs, err := pluginDirs(src)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dir := range dirs {
+			manifest, err := ParseManifest(filepath.Join(dir, ManifestFile))
+			if err != nil {
+				result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", dir, err))
+				continue
+			}
+
+			entry, err := packPlugin(dir, manifest, outDir, signer)
+			if err != nil {
+				return nil, err
+			}
+
+			idx.Put(entry)
+			result.Added = append(result.Added, entry)
+		}
+	}
+
+	idx.Generated = now.UTC()
+	if err := marketplace.WriteIndex(outDir, idx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func pluginDirs(src string) ([]string, error) {
+	if _, err := os.Stat(filepath.Join(src, ManifestFile)); err == nil {
+		return []string{src}, nil
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return nil, fmt.Errorf("read plugin source %s: %w", src, err)
+	}
+
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(src, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, ManifestFile)); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	return dirs, nil
+}
+
+func packPlugin(dir string, m *Manifest, outDir string, signer ed25519.PrivateKey) (marketplace.IndexEntry, error) {
+	data, err := marketplace.PackDir(dir, signer)
+	if err != nil {
+		return marketplace.IndexEntry{}, fmt.Errorf("pack %s: %w", m.Name, err)
+	}
+
+	archive, err := marketplace.ArchiveName(m.Name, m.Version)
+	if err != nil {
+		return marketplace.IndexEntry{}, err
+	}
+	path := filepath.Join(outDir, filepath.FromSlash(archive))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return marketplace.IndexEntry{}, fmt.Errorf("create archive directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return marketplace.IndexEntry{}, fmt.Errorf("write archive %s: %w", archive, err)
+	}
+
+	return marketplace.IndexEntry{
+		Name:        m.Name,
+		Version:     m.Version,
+		Description: m.Description,
+		Author:      m.Author,
+		Category:    m.Category,
+		Tags:        m.Tags,
+		Skills:      len(m.Skills),
+		Agents:      len(m.Agents),
+		Rules:       len(m.Rules),
+		Archive:     archive,
+		SHA256:      marketplace.Digest(data),
+		Size:        int64(len(data)),
+	}, nil
+}
@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/marketplace"
+)
+
+func writePluginSource(t *testing.T, dir, name, version string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "skills", "go"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "skills", "go", "SKILL.md"), []byte("# Go\n"), 0600))
+	manifest := fmt.Sprintf(`name: %s
+version: %s
+description: Test plugin
+author: Test
+category: skills
+tags: [go]
+skills:
+  - name: %s-go
+    path: skills/go
+`, name, version, name)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ManifestFile), []byte(manifest), 0600))
+}
+
+func TestBuildIndex(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	out := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	writePluginSource(t, filepath.Join(src, "go-kit"), "go-kit", "1.0.0")
+	writePluginSource(t, filepath.Join(src, "db-kit"), "db-kit", "0.1.0")
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "broken"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "broken", ManifestFile), []byte("name: broken\n"), 0600))
+
+	result, err := BuildIndex([]string{src}, out, nil, now)
+	require.NoError(t, err)
+	assert.Len(t, result.Added, 2)
+	assert.Len(t, result.Skipped, 1)
+
+	writePluginSource(t, filepath.Join(src, "go-kit"), "go-kit", "1.1.0")
+	_, err = BuildIndex([]string{filepath.Join(src, "go-kit")}, out, nil, now)
+	require.NoError(t, err)
+
+	idx, err := marketplace.LoadIndex(out)
+	require.NoError(t, err)
+	assert.Equal(t, now, idx.Generated)
+
+	versions := []string{}
+	for _, e := range idx.Plugins {
+		versions = append(versions, e.Name+"@"+e.Version)
+		assert.FileExists(t, filepath.Join(out, e.Archive))
+	}
+	assert.Equal(t, []string{"db-kit@0.1.0", "go-kit@1.0.0", "go-kit@1.1.0"}, versions)
+
+	latest, ok := idx.Find("go-kit", "")
+	require.True(t, ok)
+	assert.Equal(t, "1.1.0", latest.Version)
+	assert.Equal(t, 1, latest.Skills)
+	assert.Equal(t, []string{"go"}, latest.Tags)
+}
+
+func TestManager_Install_FromFSMarketplace(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	mirror := t.TempDir()
+	writePluginSource(t, filepath.Join(src, "go-kit"), "go-kit", "1.0.0")
+
+	_, err := BuildIndex([]string{src}, mirror, nil, time.Now())
+	require.NoError(t, err)
+
+	pluginsDir := t.TempDir()
+	m := NewManager(pluginsDir, &mockRegistry{}, marketplace.NewFSClient(mirror), nil)
+	require.ErrorIs(t, m.Install(context.Background(), "go-kit", "latest"), marketplace.ErrUnsigned, "unsigned archives need allow_unsigned")
+
+	m.SetAllowUnsigned(true)
+	require.NoError(t, m.Install(context.Background(), "go-kit", "latest"))
+
+	p, err := m.Get("go-kit")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", p.Manifest.Version)
+	assert.FileExists(t, filepath.Join(pluginsDir, "go-kit", "skills", "go", "SKILL.md"))
+}
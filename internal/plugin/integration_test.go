@@ -114,7 +114,7 @@ agents:
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -178,7 +178,7 @@ skills:
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -275,7 +275,7 @@ agents:
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -352,7 +352,7 @@ author: Test
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -435,7 +435,7 @@ agents:
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -506,7 +506,7 @@ skills:
 
 	tracking := &trackingRegistry{}
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelWarn}))
-	m := NewManager(tmpDir, tracking, &marketplace.Client{}, logger)
+	m := NewManager(tmpDir, tracking, &marketplace.HTTPClient{}, logger)
 
 	err := m.Initialize(context.Background())
 	require.NoError(t, err)
@@ -6,9 +6,12 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
+	"github.com/victorzhuk/go-ent/internal/lint"
 	"github.com/victorzhuk/go-ent/internal/marketplace"
+	"github.com/victorzhuk/go-ent/internal/version"
 )
 
 type Manager struct {
@@ -18,6 +21,9 @@ type Manager struct {
 	mu          sync.RWMutex
 	registry    Registry
 	marketplace MarketplaceClient
+	trust       marketplace.Trust
+	appVersion  string
+	rules       *lint.Registry
 	logger      *slog.Logger
 }
 
@@ -43,10 +49,38 @@ func NewManager(pluginsDir string, registry Registry, marketplace MarketplaceCli
 		enabled:     make(map[string]bool),
 		registry:    registry,
 		marketplace: marketplace,
+		appVersion:  version.Get().Version,
+		rules:       lint.Default(),
 		logger:      logger,
 	}
 }
 
+// SetTrustedKeys requires installed archives to be signed by one of keys.
+func (m *Manager) SetTrustedKeys(keys []marketplace.TrustedKey) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trust.Keys = keys
+}
+
+// SetAllowUnsigned lets installs accept archives that no trusted key signed,
+// as configured by marketplace.allow_unsigned.
+func (m *Manager) SetAllowUnsigned(allow bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.trust.AllowUnsigned = allow
+}
+
+// SetRules sets the registry that enabled plugins add their rules to,
+// instead of the process-wide lint.Default().
+func (m *Manager) SetRules(rules *lint.Registry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.rules = rules
+}
+
 func (m *Manager) Initialize(ctx context.Context) error {
 	if err := os.MkdirAll(m.pluginsDir, 0750); err != nil {
 		return fmt.Errorf("create plugins directory: %w", err)
@@ -58,7 +92,7 @@ func (m *Manager) Initialize(ctx context.Context) error {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
 			continue
 		}
 
@@ -84,6 +118,12 @@ func (m *Manager) Initialize(ctx context.Context) error {
 	return nil
 }
 
+// Install installs name from the marketplace together with the plugins it
+// requires. version may be an exact version, a semver range or "latest".
+// Each plugin gets the newest version that meets every requirement on it,
+// falling back to older versions when a newer one leads to a conflict.
+// Nothing is added unless every requirement, including those of already
+// installed plugins, can be satisfied.
 func (m *Manager) Install(ctx context.Context, name, version string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -92,40 +132,29 @@ func (m *Manager) Install(ctx context.Context, name, version string) error {
 		return fmt.Errorf("plugin %s already installed", name)
 	}
 
-	client, ok := m.marketplace.(*marketplace.Client)
+	client, ok := m.marketplace.(marketplace.Client)
 	if !ok {
 		return fmt.Errorf("invalid marketplace client type")
 	}
 
-	installer := marketplace.NewInstaller(client, m.pluginsDir)
-	if err := installer.Install(ctx, name, version); err != nil {
+	res, order, err := m.resolve(ctx, client, name, version)
+	if err != nil {
 		return fmt.Errorf("install plugin: %w", err)
 	}
 
-	if err := installer.Validate(name); err != nil {
-		pluginDir := filepath.Join(m.pluginsDir, name)
-		_ = os.RemoveAll(pluginDir)
-		return fmt.Errorf("validate plugin: %w", err)
-	}
-
-	manifestPath := filepath.Join(m.pluginsDir, name, ManifestFile)
-	manifest, err := ParseManifest(manifestPath)
+	added, err := res.commit(m.pluginsDir, order)
 	if err != nil {
-		pluginDir := filepath.Join(m.pluginsDir, name)
-		_ = os.RemoveAll(pluginDir)
-		return fmt.Errorf("parse manifest: %w", err)
+		return fmt.Errorf("install plugin: %w", err)
 	}
 
-	plugin := &Plugin{
-		Manifest:  *manifest,
-		RootPath:  filepath.Join(m.pluginsDir, name),
-		Installed: true,
-		Enabled:   true,
+	for _, p := range added {
+		m.plugins[p.Manifest.Name] = p
+		m.enabled[p.Manifest.Name] = true
+		if p.Manifest.Name != name {
+			m.logger.Info("installed plugin dependency", "plugin", p.Manifest.Name, "version", p.Manifest.Version, "required_by", name)
+		}
 	}
 
-	m.plugins[name] = plugin
-	m.enabled[name] = true
-
 	return nil
 }
 
@@ -137,11 +166,21 @@ func (m *Manager) Uninstall(ctx context.Context, name string) error {
 		return fmt.Errorf("plugin %s not found", name)
 	}
 
+	if deps := m.dependents(name); len(deps) > 0 {
+		return fmt.Errorf("plugin %s is required by %s", name, strings.Join(deps, ", "))
+	}
+
 	pluginDir := filepath.Join(m.pluginsDir, name)
 	if err := os.RemoveAll(pluginDir); err != nil {
 		return fmt.Errorf("remove plugin directory: %w", err)
 	}
 
+	lockPath := filepath.Join(m.pluginsDir, marketplace.LockFile)
+	if err := marketplace.UpdateLockfile(lockPath, func(l *marketplace.Lockfile) { delete(l.Plugins, name) }); err != nil {
+		return fmt.Errorf("update lockfile: %w", err)
+	}
+
+	m.rules.RemovePlugin(name)
 	delete(m.plugins, name)
 	delete(m.enabled, name)
 
@@ -228,6 +267,11 @@ func (m *Manager) Get(name string) (*Plugin, error) {
 }
 
 func (m *Manager) loadPlugin(p *Plugin) error {
+	rules, err := loadRules(p)
+	if err != nil {
+		return err
+	}
+
 	for _, skillRef := range p.Manifest.Skills {
 		absPath, err := p.ResolvePath(skillRef.Path)
 		if err != nil {
@@ -250,10 +294,37 @@ func (m *Manager) loadPlugin(p *Plugin) error {
 		}
 	}
 
+	for _, rule := range rules {
+		m.rules.Add(p.Manifest.Name, rule)
+	}
+
 	return nil
 }
 
+// loadRules parses every rule file of p without registering them.
+func loadRules(p *Plugin) ([]*lint.Rule, error) {
+	rules := make([]*lint.Rule, 0, len(p.Manifest.Rules))
+	for _, ruleRef := range p.Manifest.Rules {
+		absPath, err := p.ResolvePath(ruleRef.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolve rule path %s: %w", ruleRef.Path, err)
+		}
+
+		rule, err := lint.LoadRule(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("load rule %s: %w", ruleRef.Name, err)
+		}
+		if rule.Name != ruleRef.Name {
+			return nil, fmt.Errorf("load rule %s: rule file declares name %s", ruleRef.Name, rule.Name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
 func (m *Manager) unloadPlugin(p *Plugin) error {
+	m.rules.RemovePlugin(p.Manifest.Name)
+
 	for _, skillRef := range p.Manifest.Skills {
 		if err := m.registry.UnregisterSkill(skillRef.Name); err != nil {
 			return fmt.Errorf("unregister skill %s: %w", skillRef.Name, err)
@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/lint"
 )
 
 type mockRegistry struct{}
@@ -627,3 +628,65 @@ func TestManager_Get_DisabledPlugin(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, plugin.Enabled)
 }
+
+func TestManager_EnableDisable_Rules(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "go-kit")
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "rules"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "rules", "no-todo.yaml"), []byte(`name: no-todo
+trigger:
+  pattern: "*.go"
+conditions:
+  - type: content_matches
+    pattern: TODO
+actions:
+  - type: reject
+    message: TODOs not allowed
+`), 0600))
+
+	rules := lint.NewRegistry()
+	m := NewManager(tmpDir, &mockRegistry{}, &mockMarketplace{}, nil)
+	m.SetRules(rules)
+	m.plugins["go-kit"] = &Plugin{
+		Manifest: Manifest{
+			Name:    "go-kit",
+			Version: "1.0.0",
+			Rules:   []RuleRef{{Name: "no-todo", Path: "rules/no-todo.yaml"}},
+		},
+		RootPath:  pluginDir,
+		Installed: true,
+	}
+
+	require.NoError(t, m.Enable(context.Background(), "go-kit"))
+	findings := rules.Check(context.Background(), lint.NewFile(lint.TargetGo, "main.go", "package main\n// TODO\n"))
+	require.Len(t, findings, 1)
+	assert.Equal(t, "go-kit", findings[0].Plugin)
+	assert.Equal(t, lint.SeverityError, findings[0].Severity)
+
+	require.NoError(t, m.Disable(context.Background(), "go-kit"))
+	assert.Zero(t, rules.Count(""))
+}
+
+func TestManager_Enable_InvalidRule(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "go-kit")
+	require.NoError(t, os.MkdirAll(pluginDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "rule.yaml"), []byte("name: other\ntarget: go\nconditions: [{type: content_matches, pattern: x}]\nactions: [{type: warn}]\n"), 0600))
+
+	rules := lint.NewRegistry()
+	m := NewManager(tmpDir, &mockRegistry{}, &mockMarketplace{}, nil)
+	m.SetRules(rules)
+	m.plugins["go-kit"] = &Plugin{
+		Manifest:  Manifest{Name: "go-kit", Version: "1.0.0", Rules: []RuleRef{{Name: "no-todo", Path: "rule.yaml"}}},
+		RootPath:  pluginDir,
+		Installed: true,
+	}
+
+	err := m.Enable(context.Background(), "go-kit")
+	assert.ErrorContains(t, err, "rule file declares name other")
+	assert.Zero(t, rules.Count(""))
+}
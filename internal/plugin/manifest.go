@@ -18,12 +18,22 @@ type Manifest struct {
 	Version     string     `yaml:"version" json:"version"`
 	Description string     `yaml:"description" json:"description"`
 	Author      string     `yaml:"author" json:"author"`
+	Category    string     `yaml:"category,omitempty" json:"category,omitempty"`
+	Tags        []string   `yaml:"tags,omitempty" json:"tags,omitempty"`
 	Skills      []SkillRef `yaml:"skills,omitempty" json:"skills,omitempty"`
 	Agents      []AgentRef `yaml:"agents,omitempty" json:"agents,omitempty"`
 	Rules       []RuleRef  `yaml:"rules,omitempty" json:"rules,omitempty"`
+	Requires    []Require  `yaml:"requires,omitempty" json:"requires,omitempty"`
 	MinVersion  string     `yaml:"min_version,omitempty" json:"min_version,omitempty"`
 }
 
+// Require declares a dependency on another plugin. Version is a semver
+// range; empty allows any version.
+type Require struct {
+	Name    string `yaml:"name" json:"name"`
+	Version string `yaml:"version,omitempty" json:"version,omitempty"`
+}
+
 type SkillRef struct {
 	Name string `yaml:"name" json:"name"`
 	Path string `yaml:"path" json:"path"`
@@ -116,6 +126,24 @@ func (m *Manifest) Validate() error {
 		}
 	}
 
+	for i, req := range m.Requires {
+		if req.Name == "" {
+			return fmt.Errorf("requires[%d]: name cannot be empty", i)
+		}
+		if req.Name == m.Name {
+			return fmt.Errorf("requires[%d]: plugin cannot require itself", i)
+		}
+		if _, err := ParseConstraint(req.Version); err != nil {
+			return fmt.Errorf("requires[%d]: %w", i, err)
+		}
+	}
+
+	if m.MinVersion != "" {
+		if _, err := ParseVersion(m.MinVersion); err != nil {
+			return fmt.Errorf("min_version: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,262 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Replacer is implemented by registries that can swap a loaded skill or
+// agent for a new version in one step. Without it Reload unregisters and
+// registers the entry again.
+type Replacer interface {
+	ReplaceSkill(name, path string) error
+	ReplaceAgent(name, path string) error
+}
+
+// ReloadResult describes what Reload did for one plugin directory.
+type ReloadResult struct {
+	Plugin  string
+	Added   bool
+	Removed bool
+	Skills  []string
+	Agents  []string
+	Rules   []string
+}
+
+// Reload re-reads the plugin in dir after its files changed on disk. A new
+// directory is added as installed but disabled and a deleted one is unloaded
+// and forgotten. For an enabled plugin the manifest and rule files are parsed
+// first, so a broken edit leaves the loaded version in place; skills, agents
+// and rules are then swapped entry by entry, and an entry the registry
+// rejects restores the entries already swapped. Directories without a
+// manifest that the manager does not know return a nil result.
+func (m *Manager) Reload(ctx context.Context, dir string) (*ReloadResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	var old *Plugin
+	for _, p := range m.plugins {
+		if filepath.Clean(p.RootPath) == dir {
+			old = p
+			break
+		}
+	}
+
+	manifestPath := filepath.Join(dir, ManifestFile)
+	if _, err := os.Stat(manifestPath); errors.Is(err, fs.ErrNotExist) {
+		if old == nil {
+			return nil, nil
+		}
+		if err := m.forget(old); err != nil {
+			return nil, err
+		}
+		return &ReloadResult{Plugin: old.Manifest.Name, Removed: true}, nil
+	}
+
+	manifest, err := ParseManifest(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("reload plugin %s: %w", filepath.Base(dir), err)
+	}
+
+	next := &Plugin{Manifest: *manifest, RootPath: dir, Installed: true}
+	result := &ReloadResult{Plugin: manifest.Name}
+
+	if old != nil && old.Manifest.Name != manifest.Name {
+		if err := m.forget(old); err != nil {
+			return nil, err
+		}
+		old = nil
+	}
+
+	if old == nil {
+		if taken, exists := m.plugins[manifest.Name]; exists {
+			return nil, fmt.Errorf("reload plugin %s: name already used by %s", manifest.Name, taken.RootPath)
+		}
+		m.plugins[manifest.Name] = next
+		result.Added = true
+		return result, nil
+	}
+
+	if !old.Enabled {
+		m.plugins[manifest.Name] = next
+		return result, nil
+	}
+
+	rules, err := loadRules(next)
+	if err != nil {
+		return nil, fmt.Errorf("reload plugin %s: %w", manifest.Name, err)
+	}
+
+	newSkills, err := resolveRefs(next, skillRefs(manifest.Skills))
+	if err != nil {
+		return nil, fmt.Errorf("reload plugin %s: %w", manifest.Name, err)
+	}
+	newAgents, err := resolveRefs(next, agentRefs(manifest.Agents))
+	if err != nil {
+		return nil, fmt.Errorf("reload plugin %s: %w", manifest.Name, err)
+	}
+
+	replacer, _ := m.registry.(Replacer)
+	skillOps := entryOps{m.registry.RegisterSkill, m.registry.UnregisterSkill, nil}
+	agentOps := entryOps{m.registry.RegisterAgent, m.registry.UnregisterAgent, nil}
+	if replacer != nil {
+		skillOps.replace = replacer.ReplaceSkill
+		agentOps.replace = replacer.ReplaceAgent
+	}
+
+	var undoSkills func()
+	if result.Skills, undoSkills, err = swapEntries(skillOps, rootedRefs(old, skillRefs(old.Manifest.Skills)), newSkills); err != nil {
+		return nil, fmt.Errorf("reload plugin %s: skill %w", manifest.Name, err)
+	}
+	if result.Agents, _, err = swapEntries(agentOps, rootedRefs(old, agentRefs(old.Manifest.Agents)), newAgents); err != nil {
+		undoSkills()
+		return nil, fmt.Errorf("reload plugin %s: agent %w", manifest.Name, err)
+	}
+
+	m.rules.SetPlugin(manifest.Name, rules)
+	for _, rule := range rules {
+		result.Rules = append(result.Rules, rule.Name)
+	}
+
+	next.Enabled = true
+	m.plugins[manifest.Name] = next
+	return result, nil
+}
+
+// forget unloads p if it is enabled and drops it from the manager.
+func (m *Manager) forget(p *Plugin) error {
+	if p.Enabled {
+		if err := m.unloadPlugin(p); err != nil {
+			return fmt.Errorf("unload plugin %s: %w", p.Manifest.Name, err)
+		}
+	}
+	m.rules.RemovePlugin(p.Manifest.Name)
+	delete(m.plugins, p.Manifest.Name)
+	return nil
+}
+
+type entryRef struct {
+	name string
+	path string
+}
+
+type entryOps struct {
+	register   func(name, path string) error
+	unregister func(name string) error
+	replace    func(name, path string) error
+}
+
+func skillRefs(refs []SkillRef) []entryRef {
+	out := make([]entryRef, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, entryRef{ref.Name, ref.Path})
+	}
+	return out
+}
+
+func agentRefs(refs []AgentRef) []entryRef {
+	out := make([]entryRef, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, entryRef{ref.Name, ref.Path})
+	}
+	return out
+}
+
+// rootedRefs joins the paths of p's loaded entries to its root without
+// checking that the files still exist.
+func rootedRefs(p *Plugin, refs []entryRef) []entryRef {
+	out := make([]entryRef, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, entryRef{ref.name, filepath.Join(p.RootPath, ref.path)})
+	}
+	return out
+}
+
+// resolveRefs turns manifest-relative paths into absolute ones, failing if
+// any file is missing.
+func resolveRefs(p *Plugin, refs []entryRef) ([]entryRef, error) {
+	out := make([]entryRef, 0, len(refs))
+	for _, ref := range refs {
+		absPath, err := p.ResolvePath(ref.path)
+		if err != nil {
+			return nil, fmt.Errorf("resolve path %s: %w", ref.path, err)
+		}
+		out = append(out, entryRef{ref.name, absPath})
+	}
+	return out, nil
+}
+
+// swapEntries replaces the loaded entries with next and returns the names
+// now registered. When a step fails the entries already swapped are restored
+// to their loaded paths before the error is returned; after success undo
+// restores them the same way.
+func swapEntries(ops entryOps, loaded, next []entryRef) (names []string, undo func(), err error) {
+	previous := make(map[string]string, len(loaded))
+	for _, ref := range loaded {
+		previous[ref.name] = ref.path
+	}
+
+	var undos []func()
+	undo = func() {
+		for i := len(undos) - 1; i >= 0; i-- {
+			undos[i]()
+		}
+	}
+	restore := func(name string) func() {
+		return func() {
+			path, ok := previous[name]
+			switch {
+			case !ok:
+				_ = ops.unregister(name)
+			case ops.replace != nil:
+				_ = ops.replace(name, path)
+			default:
+				_ = ops.unregister(name)
+				_ = ops.register(name, path)
+			}
+		}
+	}
+
+	names = make([]string, 0, len(next))
+	keep := make(map[string]bool, len(next))
+	for _, ref := range next {
+		_, wasLoaded := previous[ref.name]
+		switch {
+		case ops.replace != nil:
+			err = ops.replace(ref.name, ref.path)
+		case wasLoaded:
+			if err = ops.unregister(ref.name); err == nil {
+				if err = ops.register(ref.name, ref.path); err != nil {
+					_ = ops.register(ref.name, previous[ref.name])
+				}
+			}
+		default:
+			err = ops.register(ref.name, ref.path)
+		}
+		if err != nil {
+			undo()
+			return nil, nil, fmt.Errorf("%s: %w", ref.name, err)
+		}
+		undos = append(undos, restore(ref.name))
+		keep[ref.name] = true
+		names = append(names, ref.name)
+	}
+
+	for _, ref := range loaded {
+		if keep[ref.name] {
+			continue
+		}
+		if err := ops.unregister(ref.name); err != nil {
+			undo()
+			return nil, nil, fmt.Errorf("%s: %w", ref.name, err)
+		}
+		undos = append(undos, func() { _ = ops.register(ref.name, ref.path) })
+	}
+
+	return names, undo, nil
+}
@@ -0,0 +1,276 @@
+package plugin
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/lint"
+)
+
+type replacingRegistry struct {
+	trackingRegistry
+	replacedSkills map[string]string
+}
+
+func (r *replacingRegistry) ReplaceSkill(name, path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replacedSkills == nil {
+		r.replacedSkills = make(map[string]string)
+	}
+	r.replacedSkills[name] = path
+	return nil
+}
+
+func (r *replacingRegistry) ReplaceAgent(name, path string) error {
+	return r.RegisterAgent(name, path)
+}
+
+func writePluginFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+// reloadRuleBody is a rule file without its name line.
+const reloadRuleBody = `trigger:
+  pattern: "*.go"
+conditions:
+  - type: content_matches
+    pattern: TODO
+actions:
+  - type: warn
+`
+
+func TestManager_Reload_EnabledPlugin(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "acme")
+	writePluginFile(t, filepath.Join(pluginDir, "skills", "one.md"), "one")
+	writePluginFile(t, filepath.Join(pluginDir, "skills", "two.md"), "two")
+	writePluginFile(t, filepath.Join(pluginDir, "rules", "no-todo.yaml"), "name: no-todo\n"+reloadRuleBody)
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.0.0
+description: Acme
+author: Test
+skills:
+  - name: one
+    path: skills/one.md
+  - name: two
+    path: skills/two.md
+`)
+
+	reg := &replacingRegistry{}
+	rules := lint.NewRegistry()
+	m := NewManager(tmpDir, reg, &mockMarketplace{}, nil)
+	m.SetRules(rules)
+	require.NoError(t, m.Initialize(context.Background()))
+	require.NoError(t, m.Enable(context.Background(), "acme"))
+
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.1.0
+description: Acme
+author: Test
+skills:
+  - name: one
+    path: skills/one.md
+rules:
+  - name: no-todo
+    path: rules/no-todo.yaml
+`)
+
+	res, err := m.Reload(context.Background(), pluginDir)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", res.Plugin)
+	assert.Equal(t, []string{"one"}, res.Skills)
+	assert.Equal(t, []string{"no-todo"}, res.Rules)
+
+	assert.Equal(t, filepath.Join(pluginDir, "skills", "one.md"), reg.replacedSkills["one"])
+	assert.True(t, reg.unregisteredSkills["two"])
+	assert.Equal(t, 1, rules.Count(lint.TargetGo))
+
+	p, err := m.Get("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", p.Manifest.Version)
+	assert.True(t, p.Enabled)
+}
+
+func TestManager_Reload_BrokenEditKeepsLoadedVersion(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "acme")
+	writePluginFile(t, filepath.Join(pluginDir, "rule.yaml"), "name: no-todo\n"+reloadRuleBody)
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), "name: acme\nversion: 1.0.0\ndescription: Acme\nauthor: Test\nrules:\n  - name: no-todo\n    path: rule.yaml\n")
+
+	rules := lint.NewRegistry()
+	m := NewManager(tmpDir, &mockRegistry{}, &mockMarketplace{}, nil)
+	m.SetRules(rules)
+	require.NoError(t, m.Initialize(context.Background()))
+	require.NoError(t, m.Enable(context.Background(), "acme"))
+
+	writePluginFile(t, filepath.Join(pluginDir, "rule.yaml"), "name: renamed\n"+reloadRuleBody)
+
+	_, err := m.Reload(context.Background(), pluginDir)
+	assert.ErrorContains(t, err, "rule file declares name renamed")
+	assert.Equal(t, 1, rules.Count(""))
+
+	p, err := m.Get("acme")
+	require.NoError(t, err)
+	assert.True(t, p.Enabled)
+}
+
+// rejectingRegistry fails to register entries from one path.
+type rejectingRegistry struct {
+	trackingRegistry
+	reject string
+}
+
+func (r *rejectingRegistry) RegisterSkill(name, path string) error {
+	if path == r.reject {
+		return errors.New("invalid skill")
+	}
+	return r.trackingRegistry.RegisterSkill(name, path)
+}
+
+func (r *rejectingRegistry) RegisterAgent(name, path string) error {
+	if path == r.reject {
+		return errors.New("invalid agent")
+	}
+	return r.trackingRegistry.RegisterAgent(name, path)
+}
+
+func TestManager_Reload_FailedEntryRestoresLoadedSet(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "acme")
+	for _, name := range []string{"one", "two", "three", "one-v2", "two-v2"} {
+		writePluginFile(t, filepath.Join(pluginDir, "skills", name+".md"), name)
+	}
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.0.0
+description: Acme
+author: Test
+skills:
+  - name: one
+    path: skills/one.md
+  - name: two
+    path: skills/two.md
+  - name: three
+    path: skills/three.md
+`)
+
+	reg := &rejectingRegistry{reject: filepath.Join(pluginDir, "skills", "two-v2.md")}
+	m := NewManager(tmpDir, reg, &mockMarketplace{}, nil)
+	require.NoError(t, m.Initialize(context.Background()))
+	require.NoError(t, m.Enable(context.Background(), "acme"))
+
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.1.0
+description: Acme
+author: Test
+skills:
+  - name: one
+    path: skills/one-v2.md
+  - name: two
+    path: skills/two-v2.md
+`)
+
+	_, err := m.Reload(context.Background(), pluginDir)
+	require.ErrorContains(t, err, "skill two: invalid skill")
+
+	assert.Equal(t, map[string]string{
+		"one":   filepath.Join(pluginDir, "skills", "one.md"),
+		"two":   filepath.Join(pluginDir, "skills", "two.md"),
+		"three": filepath.Join(pluginDir, "skills", "three.md"),
+	}, reg.registeredSkills)
+	assert.False(t, reg.unregisteredSkills["three"], "entries after the failure are untouched")
+
+	p, err := m.Get("acme")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", p.Manifest.Version)
+	assert.True(t, p.Enabled)
+}
+
+func TestManager_Reload_FailedAgentRestoresSkills(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	pluginDir := filepath.Join(tmpDir, "acme")
+	for _, name := range []string{"skills/one.md", "skills/two.md", "agents/dev.md", "agents/dev-v2.md"} {
+		writePluginFile(t, filepath.Join(pluginDir, name), name)
+	}
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.0.0
+description: Acme
+author: Test
+skills:
+  - name: one
+    path: skills/one.md
+agents:
+  - name: dev
+    path: agents/dev.md
+`)
+
+	reg := &rejectingRegistry{reject: filepath.Join(pluginDir, "agents", "dev-v2.md")}
+	m := NewManager(tmpDir, reg, &mockMarketplace{}, nil)
+	require.NoError(t, m.Initialize(context.Background()))
+	require.NoError(t, m.Enable(context.Background(), "acme"))
+
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), `name: acme
+version: 1.1.0
+description: Acme
+author: Test
+skills:
+  - name: two
+    path: skills/two.md
+agents:
+  - name: dev
+    path: agents/dev-v2.md
+`)
+
+	_, err := m.Reload(context.Background(), pluginDir)
+	require.ErrorContains(t, err, "agent dev: invalid agent")
+
+	assert.Equal(t, filepath.Join(pluginDir, "skills", "one.md"), reg.registeredSkills["one"], "the removed skill is registered again")
+	assert.True(t, reg.unregisteredSkills["two"], "the added skill is unregistered again")
+	assert.Equal(t, filepath.Join(pluginDir, "agents", "dev.md"), reg.registeredAgents["dev"])
+}
+
+func TestManager_Reload_AddAndRemove(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	m := NewManager(tmpDir, &mockRegistry{}, &mockMarketplace{}, nil)
+
+	pluginDir := filepath.Join(tmpDir, "acme")
+	res, err := m.Reload(context.Background(), pluginDir)
+	require.NoError(t, err)
+	assert.Nil(t, res, "unknown directories without a manifest are ignored")
+
+	writePluginFile(t, filepath.Join(pluginDir, ManifestFile), "name: acme\nversion: 1.0.0\ndescription: Acme\nauthor: Test\n")
+	res, err = m.Reload(context.Background(), pluginDir)
+	require.NoError(t, err)
+	assert.True(t, res.Added)
+
+	p, err := m.Get("acme")
+	require.NoError(t, err)
+	assert.False(t, p.Enabled, "discovered plugins start disabled")
+
+	require.NoError(t, os.RemoveAll(pluginDir))
+	res, err = m.Reload(context.Background(), pluginDir)
+	require.NoError(t, err)
+	assert.True(t, res.Removed)
+
+	_, err = m.Get("acme")
+	assert.Error(t, err)
+}
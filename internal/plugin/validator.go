@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"regexp"
 	"sync"
+
+	"github.com/victorzhuk/go-ent/internal/version"
 )
 
 type Validator struct {
@@ -12,6 +14,7 @@ type Validator struct {
 	loadedSkills     map[string]string
 	loadedAgents     map[string]string
 	loadedRules      map[string]string
+	appVersion       string
 }
 
 func NewValidator() *Validator {
@@ -20,6 +23,7 @@ func NewValidator() *Validator {
 		loadedSkills:     make(map[string]string),
 		loadedAgents:     make(map[string]string),
 		loadedRules:      make(map[string]string),
+		appVersion:       version.Get().Version,
 	}
 }
 
@@ -65,9 +69,9 @@ func (v *Validator) validateManifestSchema(m *Manifest) error {
 	return nil
 }
 
-func (v *Validator) validateVersion(version string) error {
+func (v *Validator) validateVersion(ver string) error {
 	regex := regexp.MustCompile(`^v?\d+\.\d+\.\d+(-[a-zA-Z0-9.]+)?$`)
-	if !regex.MatchString(version) {
+	if !regex.MatchString(ver) {
 		return fmt.Errorf("invalid version format (expected semver: x.y.z)")
 	}
 	return nil
@@ -99,6 +103,28 @@ func (v *Validator) checkConflicts(p *Plugin, existingPlugins map[string]*Plugin
 }
 
 func (v *Validator) checkDependencies(p *Plugin) error {
+	if err := checkMinVersion(p.Manifest.Name, p.Manifest.MinVersion, v.appVersion); err != nil {
+		return err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, req := range p.Manifest.Requires {
+		dep, exists := v.installedPlugins[req.Name]
+		if !exists {
+			return fmt.Errorf("missing dependency: %s requires %s", p.Manifest.Name, req.Name)
+		}
+
+		c, err := ParseConstraint(req.Version)
+		if err != nil {
+			return err
+		}
+		if !c.Check(dep.Manifest.Version) {
+			return fmt.Errorf("dependency conflict: %s requires %s %s, found %s", p.Manifest.Name, req.Name, c, dep.Manifest.Version)
+		}
+	}
+
 	return nil
 }
 
@@ -12,6 +12,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"github.com/victorzhuk/go-ent/internal/breaker"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 const (
@@ -181,7 +184,13 @@ func (c *Client) sendRequest(ctx context.Context, req Request) (string, error) {
 	return "", fmt.Errorf("max retry attempts reached: %w", lastErr)
 }
 
-func (c *Client) doSendRequest(ctx context.Context, req Request) (string, int, error) {
+func (c *Client) doSendRequest(ctx context.Context, req Request) (text string, statusCode int, err error) {
+	ctx, span := startRequestSpan(ctx, "anthropic", req.Model, false)
+	defer func() {
+		endRequestSpan(span, statusCode, err)
+		breaker.Default().RecordStatus("anthropic", statusCode, err)
+	}()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return "", 0, fmt.Errorf("marshal request: %w", err)
@@ -204,7 +213,7 @@ func (c *Client) doSendRequest(ctx context.Context, req Request) (string, int, e
 	}
 	defer closeBody(resp)
 
-	statusCode := resp.StatusCode
+	statusCode = resp.StatusCode
 	if statusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return "", statusCode, fmt.Errorf("API error: %s: %s", resp.Status, string(body))
@@ -231,11 +240,16 @@ func (c *Client) doSendRequest(ctx context.Context, req Request) (string, int, e
 		return "", statusCode, fmt.Errorf("decode response: %w", err)
 	}
 
+	span.SetAttributes(
+		tracing.Int(tracing.AttrTokensIn, result.Usage.InputTokens),
+		tracing.Int(tracing.AttrTokensOut, result.Usage.OutputTokens),
+	)
+
 	if len(result.Content) == 0 {
 		return "", statusCode, nil
 	}
 
-	text := result.Content[0].Text
+	text = result.Content[0].Text
 	c.logger.Debug("anthropic response", "id", result.ID, "tokens", result.Usage.OutputTokens)
 
 	return text, statusCode, nil
@@ -279,7 +293,13 @@ func (c *Client) streamRequest(ctx context.Context, req Request, callback func(t
 	return fmt.Errorf("max retry attempts reached: %w", lastErr)
 }
 
-func (c *Client) doStreamRequest(ctx context.Context, req Request, callback func(text string)) (int, error) {
+func (c *Client) doStreamRequest(ctx context.Context, req Request, callback func(text string)) (statusCode int, err error) {
+	ctx, span := startRequestSpan(ctx, "anthropic", req.Model, true)
+	defer func() {
+		endRequestSpan(span, statusCode, err)
+		breaker.Default().RecordStatus("anthropic", statusCode, err)
+	}()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("marshal request: %w", err)
@@ -302,7 +322,7 @@ func (c *Client) doStreamRequest(ctx context.Context, req Request, callback func
 	}
 	defer closeBody(resp)
 
-	statusCode := resp.StatusCode
+	statusCode = resp.StatusCode
 	if statusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return statusCode, fmt.Errorf("API error: %s: %s", resp.Status, string(body))
@@ -9,9 +9,13 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
+
+	"github.com/victorzhuk/go-ent/internal/breaker"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 type ProviderType string
@@ -38,6 +42,7 @@ type OpenAIMessage struct {
 }
 
 type OpenAIClient struct {
+	name        string
 	baseURL     string
 	apiKey      string
 	httpClient  *http.Client
@@ -110,6 +115,7 @@ func NewOpenAICompatClient(provider ProviderType, logger *slog.Logger) (*OpenAIC
 	}
 
 	return &OpenAIClient{
+		name:    string(provider),
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
@@ -135,7 +141,13 @@ func NewOpenAICompatClientWithConfig(baseURL, apiKeyEnv string, logger *slog.Log
 		return nil, fmt.Errorf("%s environment variable not set", apiKeyEnv)
 	}
 
+	name := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		name = u.Host
+	}
+
 	return &OpenAIClient{
+		name:    name,
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		httpClient: &http.Client{
@@ -239,7 +251,14 @@ func (c *OpenAIClient) sendRequest(ctx context.Context, req OpenAIRequest) (stri
 	return "", fmt.Errorf("max retry attempts reached: %w", lastErr)
 }
 
-func (c *OpenAIClient) doSendRequest(ctx context.Context, req OpenAIRequest) (string, int, error) {
+func (c *OpenAIClient) doSendRequest(ctx context.Context, req OpenAIRequest) (text string, statusCode int, err error) {
+	ctx, span := startRequestSpan(ctx, "openai_compat", req.Model, false)
+	span.SetAttributes(tracing.String("server.address", c.baseURL))
+	defer func() {
+		endRequestSpan(span, statusCode, err)
+		breaker.Default().RecordStatus(c.name, statusCode, err)
+	}()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return "", 0, fmt.Errorf("marshal request: %w", err)
@@ -262,7 +281,7 @@ func (c *OpenAIClient) doSendRequest(ctx context.Context, req OpenAIRequest) (st
 	}
 	defer closeBody(resp)
 
-	statusCode := resp.StatusCode
+	statusCode = resp.StatusCode
 	if statusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return "", statusCode, fmt.Errorf("API error: %s: %s", resp.Status, string(body))
@@ -273,11 +292,16 @@ func (c *OpenAIClient) doSendRequest(ctx context.Context, req OpenAIRequest) (st
 		return "", statusCode, fmt.Errorf("decode response: %w", err)
 	}
 
+	span.SetAttributes(
+		tracing.Int(tracing.AttrTokensIn, result.Usage.PromptTokens),
+		tracing.Int(tracing.AttrTokensOut, result.Usage.CompletionTokens),
+	)
+
 	if len(result.Choices) == 0 {
 		return "", statusCode, nil
 	}
 
-	text := result.Choices[0].Message.Content
+	text = result.Choices[0].Message.Content
 	c.logger.Debug("openai compat response", "id", result.ID, "tokens", result.Usage.CompletionTokens)
 
 	return text, statusCode, nil
@@ -321,7 +345,14 @@ func (c *OpenAIClient) streamRequest(ctx context.Context, req OpenAIRequest, cal
 	return fmt.Errorf("max retry attempts reached: %w", lastErr)
 }
 
-func (c *OpenAIClient) doStreamRequest(ctx context.Context, req OpenAIRequest, callback func(text string)) (int, error) {
+func (c *OpenAIClient) doStreamRequest(ctx context.Context, req OpenAIRequest, callback func(text string)) (statusCode int, err error) {
+	ctx, span := startRequestSpan(ctx, "openai_compat", req.Model, true)
+	span.SetAttributes(tracing.String("server.address", c.baseURL))
+	defer func() {
+		endRequestSpan(span, statusCode, err)
+		breaker.Default().RecordStatus(c.name, statusCode, err)
+	}()
+
 	body, err := json.Marshal(req)
 	if err != nil {
 		return 0, fmt.Errorf("marshal request: %w", err)
@@ -344,7 +375,7 @@ func (c *OpenAIClient) doStreamRequest(ctx context.Context, req OpenAIRequest, c
 	}
 	defer closeBody(resp)
 
-	statusCode := resp.StatusCode
+	statusCode = resp.StatusCode
 	if statusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return statusCode, fmt.Errorf("API error: %s: %s", resp.Status, string(body))
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/victorzhuk/go-ent/internal/tracing"
+)
+
+// startRequestSpan starts a client span for a single provider HTTP attempt.
+func startRequestSpan(ctx context.Context, provider, model string, stream bool) (context.Context, *tracing.Span) {
+	ctx, span := tracing.Start(ctx, "provider."+provider+".request",
+		tracing.String(tracing.AttrProvider, provider),
+		tracing.String(tracing.AttrModel, model),
+		tracing.Bool("goent.stream", stream),
+	)
+	span.SetKind(tracing.SpanKindClient)
+	return ctx, span
+}
+
+// endRequestSpan records the HTTP status and error of an attempt and ends the span.
+func endRequestSpan(span *tracing.Span, statusCode int, err error) {
+	if statusCode != 0 {
+		span.SetAttributes(tracing.Int(tracing.AttrStatus, statusCode))
+	}
+	span.RecordError(err)
+	span.End()
+}
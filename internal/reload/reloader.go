@@ -0,0 +1,220 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/plugin"
+	"github.com/victorzhuk/go-ent/internal/skill"
+)
+
+// Report describes one reload: the files that triggered it, what was swapped
+// into the registries and the problems found on the way.
+type Report struct {
+	Time          time.Time
+	Files         []string
+	SkillsAdded   []string
+	SkillsChanged []string
+	SkillsRemoved []string
+	Plugins       []plugin.ReloadResult
+	Errors        []string
+	Warnings      []string
+}
+
+// Empty reports whether the reload swapped nothing and found no problems.
+func (r Report) Empty() bool {
+	return len(r.SkillsAdded) == 0 && len(r.SkillsChanged) == 0 && len(r.SkillsRemoved) == 0 &&
+		len(r.Plugins) == 0 && len(r.Errors) == 0 && len(r.Warnings) == 0
+}
+
+// Summary renders the report on one line, e.g.
+// "skills +1 ~2 -0; plugin acme: 2 skill(s), 1 agent(s), 1 rule(s); 1 error(s)".
+func (r Report) Summary() string {
+	var parts []string
+	if n := len(r.SkillsAdded) + len(r.SkillsChanged) + len(r.SkillsRemoved); n > 0 {
+		parts = append(parts, fmt.Sprintf("skills +%d ~%d -%d", len(r.SkillsAdded), len(r.SkillsChanged), len(r.SkillsRemoved)))
+	}
+	for _, p := range r.Plugins {
+		switch {
+		case p.Removed:
+			parts = append(parts, fmt.Sprintf("plugin %s removed", p.Plugin))
+		case p.Added:
+			parts = append(parts, fmt.Sprintf("plugin %s added", p.Plugin))
+		default:
+			parts = append(parts, fmt.Sprintf("plugin %s: %d skill(s), %d agent(s), %d rule(s)",
+				p.Plugin, len(p.Skills), len(p.Agents), len(p.Rules)))
+		}
+	}
+	if len(r.Errors) > 0 {
+		parts = append(parts, fmt.Sprintf("%d error(s)", len(r.Errors)))
+	}
+	if len(r.Warnings) > 0 {
+		parts = append(parts, fmt.Sprintf("%d warning(s)", len(r.Warnings)))
+	}
+	if len(parts) == 0 {
+		return "no changes"
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Reloader applies watcher changes to the skill registry and the plugin
+// manager. Files under the skills path reload the skill directory; files in
+// a plugin directory reload that plugin.
+type Reloader struct {
+	skills     *skill.Registry
+	skillsPath string
+	plugins    *plugin.Manager
+	pluginsDir string
+	logger     *slog.Logger
+	observers  []func(Report)
+	now        func() time.Time
+}
+
+// NewReloader creates a reloader. Either registry may be nil.
+func NewReloader(skills *skill.Registry, skillsPath string, plugins *plugin.Manager, pluginsDir string, logger *slog.Logger) *Reloader {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Reloader{
+		skills:     skills,
+		skillsPath: filepath.Clean(skillsPath),
+		plugins:    plugins,
+		pluginsDir: filepath.Clean(pluginsDir),
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// OnReload registers fn to be called with every non-empty report.
+func (r *Reloader) OnReload(fn func(Report)) {
+	r.observers = append(r.observers, fn)
+}
+
+// Watch polls the skills path and plugins directory until ctx is done.
+func (r *Reloader) Watch(ctx context.Context, interval time.Duration) {
+	var roots []string
+	if r.skills != nil {
+		roots = append(roots, r.skillsPath)
+	}
+	if r.plugins != nil {
+		roots = append(roots, r.pluginsDir)
+	}
+
+	NewWatcher(interval, roots...).Run(ctx, func(c Changes) {
+		r.Apply(ctx, c)
+	})
+}
+
+// Apply reloads whatever c touches, logs the outcome and notifies observers.
+func (r *Reloader) Apply(ctx context.Context, c Changes) Report {
+	report := Report{Time: r.now(), Files: c.Paths()}
+
+	reloadSkills := false
+	var pluginDirs []string
+	for _, path := range report.Files {
+		switch {
+		case r.skills != nil && within(r.skillsPath, path):
+			reloadSkills = true
+		case r.plugins != nil && within(r.pluginsDir, path):
+			if dir, ok := r.pluginDir(path); ok && !slices.Contains(pluginDirs, dir) {
+				pluginDirs = append(pluginDirs, dir)
+			}
+		}
+	}
+
+	if reloadSkills {
+		r.applySkills(&report)
+	}
+	for _, dir := range pluginDirs {
+		res, err := r.plugins.Reload(ctx, dir)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			continue
+		}
+		if res != nil {
+			report.Plugins = append(report.Plugins, *res)
+		}
+	}
+
+	if report.Empty() {
+		return report
+	}
+
+	r.log(report)
+	for _, fn := range r.observers {
+		fn(report)
+	}
+	return report
+}
+
+func (r *Reloader) applySkills(report *Report) {
+	res, err := r.skills.Reload(r.skillsPath)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("reload skills: %v", err))
+		return
+	}
+
+	report.SkillsAdded = res.Added
+	report.SkillsChanged = res.Changed
+	report.SkillsRemoved = res.Removed
+	for _, issue := range res.Issues {
+		line := fmt.Sprintf("%s: [%s] %s: %s", issue.Path, issue.Severity, issue.Rule, issue.Message)
+		if issue.Severity == skill.SeverityError {
+			report.Errors = append(report.Errors, line)
+		} else {
+			report.Warnings = append(report.Warnings, line)
+		}
+	}
+}
+
+// pluginDir maps a path inside the plugins directory to the plugin folder
+// that contains it. Top-level files such as plugins.lock and dot folders used
+// for staging installs are ignored.
+func (r *Reloader) pluginDir(path string) (string, bool) {
+	rel, err := filepath.Rel(r.pluginsDir, path)
+	if err != nil {
+		return "", false
+	}
+
+	first, rest, found := strings.Cut(filepath.ToSlash(rel), "/")
+	if !found || rest == "" || strings.HasPrefix(first, ".") {
+		return "", false
+	}
+	return filepath.Join(r.pluginsDir, first), true
+}
+
+func (r *Reloader) log(report Report) {
+	var plugins []string
+	for _, p := range report.Plugins {
+		plugins = append(plugins, p.Plugin)
+	}
+
+	attrs := []any{
+		"summary", report.Summary(),
+		"files", len(report.Files),
+		"skills_added", report.SkillsAdded,
+		"skills_changed", report.SkillsChanged,
+		"skills_removed", report.SkillsRemoved,
+		"plugins", plugins,
+	}
+	if len(report.Errors) > 0 || len(report.Warnings) > 0 {
+		attrs = append(attrs, "errors", report.Errors, "warnings", report.Warnings)
+	}
+
+	if len(report.Errors) > 0 {
+		r.logger.Warn("hot reload", attrs...)
+		return
+	}
+	r.logger.Info("hot reload", attrs...)
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,103 @@
+package reload
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/plugin"
+	"github.com/victorzhuk/go-ent/internal/skill"
+)
+
+type nopRegistry struct{}
+
+func (nopRegistry) RegisterSkill(name, path string) error { return nil }
+func (nopRegistry) RegisterAgent(name, path string) error { return nil }
+func (nopRegistry) UnregisterSkill(name string) error     { return nil }
+func (nopRegistry) UnregisterAgent(name string) error     { return nil }
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+}
+
+func TestReloader_Apply(t *testing.T) {
+	t.Parallel()
+
+	pluginsDir := t.TempDir()
+	skillsPath := filepath.Join(pluginsDir, "go-ent", "skills")
+	writeFile(t, filepath.Join(skillsPath, "go-code", "SKILL.md"), "---\nname: go-code\ndescription: \"Go code.\"\n---\n\n# go-code\n")
+
+	skills := skill.NewRegistry()
+	require.NoError(t, skills.Load(skillsPath))
+	plugins := plugin.NewManager(pluginsDir, nopRegistry{}, nil, nil)
+	require.NoError(t, plugins.Initialize(context.Background()))
+
+	r := NewReloader(skills, skillsPath, plugins, pluginsDir, nil)
+	r.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	var observed []Report
+	r.OnReload(func(report Report) { observed = append(observed, report) })
+
+	added := filepath.Join(skillsPath, "go-test", "SKILL.md")
+	writeFile(t, added, "---\nname: go-test\ndescription: \"Go tests.\"\n---\n\n# go-test\n")
+	manifest := filepath.Join(pluginsDir, "acme", "plugin.yaml")
+	writeFile(t, manifest, "name: acme\nversion: 1.0.0\ndescription: Acme\nauthor: Test\n")
+
+	report := r.Apply(context.Background(), Changes{
+		Added: []string{added, manifest, filepath.Join(pluginsDir, "plugins.lock")},
+	})
+
+	assert.Equal(t, []string{"go-test"}, report.SkillsAdded)
+	require.Len(t, report.Plugins, 1)
+	assert.Equal(t, "acme", report.Plugins[0].Plugin)
+	assert.True(t, report.Plugins[0].Added)
+	assert.Equal(t, "skills +1 ~0 -0; plugin acme added", report.Summary())
+	require.Len(t, observed, 1)
+	assert.Equal(t, report.Time, observed[0].Time)
+
+	_, err := plugins.Get("acme")
+	assert.NoError(t, err)
+}
+
+func TestReloader_Apply_ReportsErrors(t *testing.T) {
+	t.Parallel()
+
+	pluginsDir := t.TempDir()
+	plugins := plugin.NewManager(pluginsDir, nopRegistry{}, nil, nil)
+
+	r := NewReloader(nil, "", plugins, pluginsDir, nil)
+
+	manifest := filepath.Join(pluginsDir, "broken", "plugin.yaml")
+	writeFile(t, manifest, "version: [\n")
+
+	report := r.Apply(context.Background(), Changes{Added: []string{manifest}})
+	require.Len(t, report.Errors, 1)
+	assert.Contains(t, report.Errors[0], "reload plugin broken")
+	assert.Equal(t, "1 error(s)", report.Summary())
+}
+
+func TestReloader_Apply_IgnoresUnknownDirs(t *testing.T) {
+	t.Parallel()
+
+	pluginsDir := t.TempDir()
+	plugins := plugin.NewManager(pluginsDir, nopRegistry{}, nil, nil)
+
+	var observed int
+	r := NewReloader(nil, "", plugins, pluginsDir, nil)
+	r.OnReload(func(Report) { observed++ })
+
+	readme := filepath.Join(pluginsDir, "notes", "README.md")
+	writeFile(t, readme, "# notes\n")
+
+	report := r.Apply(context.Background(), Changes{Added: []string{readme}})
+	assert.True(t, report.Empty())
+	assert.Zero(t, observed)
+}
@@ -0,0 +1,156 @@
+// Package reload polls skill, agent and plugin files for changes so the MCP
+// server can pick them up without a restart.
+package reload
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is how often Run polls when no interval is given.
+const DefaultInterval = 2 * time.Second
+
+// Changes lists the files that differ from the previous poll.
+type Changes struct {
+	Added    []string
+	Modified []string
+	Removed  []string
+}
+
+// Empty reports whether nothing changed.
+func (c Changes) Empty() bool {
+	return len(c.Added) == 0 && len(c.Modified) == 0 && len(c.Removed) == 0
+}
+
+// Paths returns every changed path, sorted.
+func (c Changes) Paths() []string {
+	paths := slices.Concat(c.Added, c.Modified, c.Removed)
+	slices.Sort(paths)
+	return paths
+}
+
+type stamp struct {
+	size    int64
+	modTime time.Time
+	sum     [sha256.Size]byte
+}
+
+// Watcher compares snapshots of the files under its roots. Files whose size
+// and modification time are unchanged are not re-read; touched files whose
+// content is identical are not reported.
+type Watcher struct {
+	roots    []string
+	interval time.Duration
+	files    map[string]stamp
+}
+
+// NewWatcher takes the initial snapshot of roots, so files that already exist
+// are not reported as added by the first Poll. Missing roots are watched for
+// creation.
+func NewWatcher(interval time.Duration, roots ...string) *Watcher {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	w := &Watcher{interval: interval}
+	for _, root := range roots {
+		w.roots = append(w.roots, filepath.Clean(root))
+	}
+	w.files = w.scan(nil)
+	return w
+}
+
+// Poll rescans the roots and returns what changed since the last call.
+func (w *Watcher) Poll() Changes {
+	next := w.scan(w.files)
+
+	var c Changes
+	for path, cur := range next {
+		prev, ok := w.files[path]
+		switch {
+		case !ok:
+			c.Added = append(c.Added, path)
+		case prev.sum != cur.sum:
+			c.Modified = append(c.Modified, path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := next[path]; !ok {
+			c.Removed = append(c.Removed, path)
+		}
+	}
+	w.files = next
+
+	slices.Sort(c.Added)
+	slices.Sort(c.Modified)
+	slices.Sort(c.Removed)
+	return c
+}
+
+// Run polls until ctx is done and calls fn with every non-empty change set.
+func (w *Watcher) Run(ctx context.Context, fn func(Changes)) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c := w.Poll(); !c.Empty() {
+				fn(c)
+			}
+		}
+	}
+}
+
+func (w *Watcher) scan(prev map[string]stamp) map[string]stamp {
+	files := make(map[string]stamp)
+	for _, root := range w.roots {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// A root that does not exist yet, or a file removed mid-walk.
+				if errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.IsDir() {
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			cur := stamp{size: info.Size(), modTime: info.ModTime()}
+			if old, ok := prev[path]; ok && old.size == cur.size && old.modTime.Equal(cur.modTime) {
+				files[path] = old
+				return nil
+			}
+
+			content, err := os.ReadFile(path) // #nosec G304 -- walked from a watched root
+			if err != nil {
+				return nil
+			}
+			cur.sum = sha256.Sum256(content)
+			files[path] = cur
+			return nil
+		})
+	}
+	return files
+}
@@ -0,0 +1,100 @@
+package reload
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher_Poll(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "keep.md")
+	edit := filepath.Join(dir, "sub", "edit.md")
+	gone := filepath.Join(dir, "gone.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(edit), 0750))
+	require.NoError(t, os.WriteFile(keep, []byte("keep"), 0600))
+	require.NoError(t, os.WriteFile(edit, []byte("v1"), 0600))
+	require.NoError(t, os.WriteFile(gone, []byte("gone"), 0600))
+
+	w := NewWatcher(time.Second, dir)
+	assert.True(t, w.Poll().Empty(), "existing files are part of the initial snapshot")
+
+	added := filepath.Join(dir, "added.md")
+	require.NoError(t, os.WriteFile(added, []byte("new"), 0600))
+	require.NoError(t, os.WriteFile(edit, []byte("v2 is longer"), 0600))
+	require.NoError(t, os.Remove(gone))
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(keep, later, later))
+
+	c := w.Poll()
+	assert.Equal(t, []string{added}, c.Added)
+	assert.Equal(t, []string{edit}, c.Modified)
+	assert.Equal(t, []string{gone}, c.Removed)
+	assert.Equal(t, []string{added, gone, edit}, c.Paths())
+
+	assert.True(t, w.Poll().Empty())
+}
+
+func TestWatcher_Poll_MissingRootAndDotDirs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	root := filepath.Join(dir, "plugins")
+
+	w := NewWatcher(time.Second, root)
+	assert.True(t, w.Poll().Empty())
+
+	staging := filepath.Join(root, ".staging-1", "plugin.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(staging), 0750))
+	require.NoError(t, os.WriteFile(staging, []byte("name: x"), 0600))
+	manifest := filepath.Join(root, "acme", "plugin.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(manifest), 0750))
+	require.NoError(t, os.WriteFile(manifest, []byte("name: acme"), 0600))
+
+	assert.Equal(t, []string{manifest}, w.Poll().Added)
+}
+
+func TestWatcher_Run(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	w := NewWatcher(10*time.Millisecond, dir)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	got := make(chan Changes, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx, func(c Changes) {
+			select {
+			case got <- c:
+			default:
+			}
+		})
+	}()
+
+	path := filepath.Join(dir, "SKILL.md")
+	require.NoError(t, os.WriteFile(path, []byte("x"), 0600))
+
+	select {
+	case c := <-got:
+		assert.Equal(t, []string{path}, c.Added)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no changes reported")
+	}
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,412 @@
+package router
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/execution"
+)
+
+// Task metadata keys read by match conditions.
+const (
+	// MetadataLabels holds the OpenSpec change labels as []string or a
+	// comma-separated string.
+	MetadataLabels = "labels"
+
+	// MetadataAgentRole holds the agent role the task is executed for.
+	MetadataAgentRole = "agent_role"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+var languageExtensions = map[string]string{
+	".go":    "go",
+	".py":    "python",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".rs":    "rust",
+	".java":  "java",
+	".kt":    "kotlin",
+	".rb":    "ruby",
+	".php":   "php",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".cs":    "csharp",
+	".swift": "swift",
+	".sql":   "sql",
+	".sh":    "shell",
+	".bash":  "shell",
+	".proto": "protobuf",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".json":  "json",
+	".md":    "markdown",
+	".html":  "html",
+	".css":   "css",
+}
+
+// TimeWindow restricts a rule to hours of the day and days of the week.
+// A window whose After is later than Before wraps past midnight.
+type TimeWindow struct {
+	After    string   `yaml:"after,omitempty"`
+	Before   string   `yaml:"before,omitempty"`
+	Days     []string `yaml:"days,omitempty"`
+	Timezone string   `yaml:"timezone,omitempty"`
+}
+
+// Validate checks clock formats, day names and the timezone.
+func (w *TimeWindow) Validate() error {
+	for _, v := range []string{w.After, w.Before} {
+		if v == "" {
+			continue
+		}
+		if _, err := parseClock(v); err != nil {
+			return err
+		}
+	}
+
+	for _, d := range w.Days {
+		if _, ok := weekdays[strings.ToLower(d)]; !ok {
+			return fmt.Errorf("invalid day %q, must be one of: mon, tue, wed, thu, fri, sat, sun", d)
+		}
+	}
+
+	if w.Timezone != "" {
+		if _, err := time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", w.Timezone, err)
+		}
+	}
+
+	return nil
+}
+
+// contains reports why now falls outside the window, or "" when inside.
+func (w *TimeWindow) contains(now time.Time) string {
+	if w.Timezone != "" {
+		if loc, err := time.LoadLocation(w.Timezone); err == nil {
+			now = now.In(loc)
+		}
+	}
+
+	if len(w.Days) > 0 {
+		day := strings.ToLower(now.Weekday().String()[:3])
+		if !slices.ContainsFunc(w.Days, func(d string) bool { return strings.EqualFold(d, day) }) {
+			return fmt.Sprintf("day %s not in [%s]", day, strings.Join(w.Days, ", "))
+		}
+	}
+
+	if w.After == "" && w.Before == "" {
+		return ""
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	after, _ := parseClock(w.After)
+	before := 24 * 60
+	if w.Before != "" {
+		before, _ = parseClock(w.Before)
+	}
+
+	inside := minute >= after && minute < before
+	if after > before {
+		inside = minute >= after || minute < before
+	}
+	if inside {
+		return ""
+	}
+
+	return fmt.Sprintf("time %s outside %s-%s", now.Format("15:04"), orDefault(w.After, "00:00"), orDefault(w.Before, "24:00"))
+}
+
+func parseClock(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, must be HH:MM", v)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// Validate checks condition values, including nested all/any/not conditions.
+func (m *MatchConditions) Validate() error {
+	validTypes := []string{"implement", "refactor", "analyze", "fix", "test", "feature", "bugfix", "documentation"}
+	for _, t := range m.Type {
+		if !slices.Contains(validTypes, t) {
+			return fmt.Errorf("invalid type %q, must be one of: %s", t, strings.Join(validTypes, ", "))
+		}
+	}
+
+	validComplexity := []string{"trivial", "simple", "medium", "complex", "high"}
+	if m.Complexity != "" && !slices.Contains(validComplexity, m.Complexity) {
+		return fmt.Errorf("invalid complexity %q, must be one of: %s", m.Complexity, strings.Join(validComplexity, ", "))
+	}
+
+	if m.FileCount != nil && *m.FileCount < 0 {
+		return fmt.Errorf("file_count must be non-negative")
+	}
+
+	if m.ContextSize != nil && *m.ContextSize < 0 {
+		return fmt.Errorf("context_size must be non-negative")
+	}
+
+	if m.MaxCost != nil && *m.MaxCost < 0 {
+		return fmt.Errorf("max_cost must be non-negative")
+	}
+
+	for _, g := range m.Files {
+		if _, err := path.Match(strings.ReplaceAll(g, "**", "*"), ""); err != nil {
+			return fmt.Errorf("invalid file glob %q: %w", g, err)
+		}
+	}
+
+	if m.Time != nil {
+		if err := m.Time.Validate(); err != nil {
+			return fmt.Errorf("time: %w", err)
+		}
+	}
+
+	for i := range m.All {
+		if err := m.All[i].Validate(); err != nil {
+			return fmt.Errorf("all[%d]: %w", i, err)
+		}
+	}
+
+	for i := range m.Any {
+		if err := m.Any[i].Validate(); err != nil {
+			return fmt.Errorf("any[%d]: %w", i, err)
+		}
+	}
+
+	if m.Not != nil {
+		if err := m.Not.Validate(); err != nil {
+			return fmt.Errorf("not: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// matchConditions returns the first sub-condition the task fails, or "" when
+// every condition holds. Nested failures are prefixed with their path.
+func (r *Router) matchConditions(m MatchConditions, task *execution.Task, chars taskCharacteristics) string {
+	if m.Complexity != "" && m.Complexity != chars.complexity {
+		return fmt.Sprintf("complexity %s != %s", chars.complexity, m.Complexity)
+	}
+
+	if len(m.Type) > 0 && !slices.ContainsFunc(m.Type, func(t string) bool { return strings.EqualFold(t, task.Type) }) {
+		return fmt.Sprintf("type %q not in [%s]", task.Type, strings.Join(m.Type, ", "))
+	}
+
+	if m.FileCount != nil && chars.fileCount < *m.FileCount {
+		return fmt.Sprintf("files %d < %d", chars.fileCount, *m.FileCount)
+	}
+
+	if m.ContextSize != nil && chars.estimatedTokens < *m.ContextSize {
+		return fmt.Sprintf("tokens %d < %d", chars.estimatedTokens, *m.ContextSize)
+	}
+
+	if !matchKeywords(m.Keywords, task.Description) {
+		return fmt.Sprintf("no keyword of [%s] in description", strings.Join(m.Keywords, ", "))
+	}
+
+	if len(m.Files) > 0 && !anyFileMatches(m.Files, taskFiles(task)) {
+		return fmt.Sprintf("no file matches [%s]", strings.Join(m.Files, ", "))
+	}
+
+	if len(m.Languages) > 0 && !intersects(m.Languages, chars.languages) {
+		return fmt.Sprintf("languages [%s] not in [%s]", strings.Join(chars.languages, ", "), strings.Join(m.Languages, ", "))
+	}
+
+	if len(m.Labels) > 0 {
+		labels := taskLabels(task)
+		if !intersects(m.Labels, labels) {
+			return fmt.Sprintf("labels [%s] not in [%s]", strings.Join(labels, ", "), strings.Join(m.Labels, ", "))
+		}
+	}
+
+	if len(m.AgentRole) > 0 {
+		role := taskAgentRole(task)
+		if !slices.ContainsFunc(m.AgentRole, func(a string) bool { return strings.EqualFold(a, role) }) {
+			return fmt.Sprintf("agent role %q not in [%s]", role, strings.Join(m.AgentRole, ", "))
+		}
+	}
+
+	if m.Time != nil {
+		if reason := m.Time.contains(r.now()); reason != "" {
+			return reason
+		}
+	}
+
+	for _, provider := range m.Healthy {
+		if r.healthy != nil && !r.healthy(provider) {
+			return fmt.Sprintf("provider %s unhealthy", provider)
+		}
+	}
+
+	for i, sub := range m.All {
+		if reason := r.matchConditions(sub, task, chars); reason != "" {
+			return fmt.Sprintf("all[%d]: %s", i, reason)
+		}
+	}
+
+	if len(m.Any) > 0 {
+		reasons := make([]string, 0, len(m.Any))
+		matched := false
+		for i, sub := range m.Any {
+			reason := r.matchConditions(sub, task, chars)
+			if reason == "" {
+				matched = true
+				break
+			}
+			reasons = append(reasons, fmt.Sprintf("any[%d]: %s", i, reason))
+		}
+		if !matched {
+			return strings.Join(reasons, "; ")
+		}
+	}
+
+	if m.Not != nil && r.matchConditions(*m.Not, task, chars) == "" {
+		return "not: nested conditions matched"
+	}
+
+	return ""
+}
+
+func matchKeywords(keywords []string, description string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+
+	descLower := strings.ToLower(description)
+	for _, kw := range keywords {
+		if strings.Contains(descLower, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	return false
+}
+
+func taskFiles(task *execution.Task) []string {
+	if task.Context == nil {
+		return nil
+	}
+	return task.Context.Files
+}
+
+func anyFileMatches(globs, files []string) bool {
+	for _, f := range files {
+		name := filepath.ToSlash(f)
+		for _, g := range globs {
+			if matchGlob(g, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchGlob matches a slash-separated name against a path.Match pattern in
+// which a "**" segment matches zero or more directories.
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// detectLanguages maps file extensions to language names, sorted.
+func detectLanguages(files []string) []string {
+	var langs []string
+	for _, f := range files {
+		lang, ok := languageExtensions[strings.ToLower(filepath.Ext(f))]
+		if ok && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+func taskLabels(task *execution.Task) []string {
+	switch v := task.Metadata[MetadataLabels].(type) {
+	case []string:
+		return v
+	case []any:
+		labels := make([]string, 0, len(v))
+		for _, l := range v {
+			if s, ok := l.(string); ok {
+				labels = append(labels, s)
+			}
+		}
+		return labels
+	case string:
+		var labels []string
+		for _, l := range strings.Split(v, ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				labels = append(labels, l)
+			}
+		}
+		return labels
+	default:
+		return nil
+	}
+}
+
+func taskAgentRole(task *execution.Task) string {
+	if role, ok := task.Metadata[MetadataAgentRole].(string); ok && role != "" {
+		return role
+	}
+	return string(task.ForceAgent)
+}
+
+func intersects(want, have []string) bool {
+	for _, w := range want {
+		if slices.ContainsFunc(have, func(h string) bool { return strings.EqualFold(h, w) }) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,189 @@
+package router
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/execution"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+func TestMatchGlob(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"internal/**/migrations/*.sql", "internal/db/migrations/001.sql", true},
+		{"internal/**/migrations/*.sql", "internal/migrations/001.sql", true},
+		{"internal/**/migrations/*.sql", "internal/a/b/migrations/001.sql", true},
+		{"internal/**/migrations/*.sql", "internal/db/migrations/001.go", false},
+		{"internal/**/migrations/*.sql", "cmd/migrations/001.sql", false},
+		{"**/*_test.go", "router_test.go", true},
+		{"**/*_test.go", "internal/router/router_test.go", true},
+		{"*.go", "internal/router.go", false},
+		{"docs/**", "docs/a/b.md", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, matchGlob(tt.pattern, tt.name))
+		})
+	}
+}
+
+func TestDetectLanguages(t *testing.T) {
+	t.Parallel()
+
+	langs := detectLanguages([]string{"main.go", "db/001.SQL", "x.go", "README", "web/app.tsx"})
+	assert.Equal(t, []string{"go", "sql", "typescript"}, langs)
+}
+
+func TestRouter_MatchConditions(t *testing.T) {
+	t.Parallel()
+
+	// Saturday 2026-01-10 23:30 UTC.
+	saturdayNight := time.Date(2026, 1, 10, 23, 30, 0, 0, time.UTC)
+
+	task := execution.NewTask("add migration").
+		WithType("implement").
+		WithContext(&execution.TaskContext{Files: []string{"internal/db/migrations/001.sql", "internal/db/repo.go"}}).
+		WithMetadata(MetadataLabels, "db, backend").
+		WithMetadata(MetadataAgentRole, "developer")
+
+	tests := []struct {
+		name   string
+		match  MatchConditions
+		reason string
+	}{
+		{name: "empty matches", match: MatchConditions{}},
+		{name: "file glob", match: MatchConditions{Files: []string{"internal/**/migrations/*.sql"}}},
+		{name: "file glob fails", match: MatchConditions{Files: []string{"docs/**"}}, reason: "no file matches [docs/**]"},
+		{name: "language", match: MatchConditions{Languages: []string{"SQL"}}},
+		{name: "language fails", match: MatchConditions{Languages: []string{"python"}}, reason: "languages [go, sql] not in [python]"},
+		{name: "label", match: MatchConditions{Labels: []string{"backend"}}},
+		{name: "label fails", match: MatchConditions{Labels: []string{"frontend"}}, reason: "labels [db, backend] not in [frontend]"},
+		{name: "agent role", match: MatchConditions{AgentRole: []string{"developer", "senior"}}},
+		{name: "agent role fails", match: MatchConditions{AgentRole: []string{"architect"}}, reason: `agent role "developer" not in [architect]`},
+		{name: "off-peak window wraps midnight", match: MatchConditions{Time: &TimeWindow{After: "22:00", Before: "06:00"}}},
+		{name: "business hours fail", match: MatchConditions{Time: &TimeWindow{After: "09:00", Before: "18:00"}}, reason: "time 23:30 outside 09:00-18:00"},
+		{name: "weekend", match: MatchConditions{Time: &TimeWindow{Days: []string{"sat", "sun"}}}},
+		{name: "weekday fails", match: MatchConditions{Time: &TimeWindow{Days: []string{"mon"}}}, reason: "day sat not in [mon]"},
+		{name: "timezone shifts day", match: MatchConditions{Time: &TimeWindow{Days: []string{"sun"}, Timezone: "Asia/Tokyo"}}},
+		{name: "healthy provider", match: MatchConditions{Healthy: []string{"glm"}}},
+		{name: "unhealthy provider", match: MatchConditions{Healthy: []string{"glm", "kimi"}}, reason: "provider kimi unhealthy"},
+		{
+			name:   "all reports nested failure",
+			match:  MatchConditions{All: []MatchConditions{{Type: []string{"implement"}}, {Languages: []string{"rust"}}}},
+			reason: "all[1]: languages [go, sql] not in [rust]",
+		},
+		{
+			name:  "any matches one",
+			match: MatchConditions{Any: []MatchConditions{{Labels: []string{"frontend"}}, {Files: []string{"**/*.sql"}}}},
+		},
+		{
+			name:   "any reports every failure",
+			match:  MatchConditions{Any: []MatchConditions{{Labels: []string{"frontend"}}, {Type: []string{"test"}}}},
+			reason: `any[0]: labels [db, backend] not in [frontend]; any[1]: type "implement" not in [test]`,
+		},
+		{name: "not", match: MatchConditions{Not: &MatchConditions{Labels: []string{"frontend"}}}},
+		{name: "not fails", match: MatchConditions{Not: &MatchConditions{Labels: []string{"db"}}}, reason: "not: nested conditions matched"},
+	}
+
+	cfg := worker.DefaultConfig()
+	cfg.Providers = scoringProviders()
+	r, err := NewRouter(cfg, nil,
+		WithClock(func() time.Time { return saturdayNight }),
+		WithHealthCheck(func(provider string) bool { return provider != "kimi" }),
+	)
+	require.NoError(t, err)
+	chars := r.analyzeTask(task)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			matched, reason := r.ruleMatches(RoutingRule{ID: "rule", Match: tt.match}, task, chars)
+			assert.Equal(t, tt.reason == "", matched)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestMatchConditions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		match   MatchConditions
+		wantErr string
+	}{
+		{name: "valid", match: MatchConditions{Files: []string{"internal/**/*.sql"}, Time: &TimeWindow{After: "22:00", Before: "06:00", Days: []string{"Sat"}}}},
+		{name: "bad glob", match: MatchConditions{Files: []string{"internal/[.sql"}}, wantErr: "invalid file glob"},
+		{name: "bad clock", match: MatchConditions{Time: &TimeWindow{After: "25:00"}}, wantErr: "time: invalid time"},
+		{name: "bad day", match: MatchConditions{Time: &TimeWindow{Days: []string{"someday"}}}, wantErr: "invalid day"},
+		{name: "bad timezone", match: MatchConditions{Time: &TimeWindow{Timezone: "Mars/Base"}}, wantErr: "invalid timezone"},
+		{name: "nested all", match: MatchConditions{All: []MatchConditions{{}, {Complexity: "huge"}}}, wantErr: "all[1]: invalid complexity"},
+		{name: "nested any", match: MatchConditions{Any: []MatchConditions{{Type: []string{"deploy"}}}}, wantErr: "any[0]: invalid type"},
+		{name: "nested not", match: MatchConditions{Not: &MatchConditions{FileCount: intPtr(-1)}}, wantErr: "not: file_count must be non-negative"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.match.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadRoutingConfig_ComposedConditions(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "routing.yaml")
+	content := `
+rules:
+  - id: off-peak-migrations
+    priority: 100
+    match:
+      files: ["internal/**/migrations/*.sql"]
+      time:
+        after: "22:00"
+        before: "06:00"
+      any:
+        - labels: [db]
+        - agent_role: [developer]
+      not:
+        healthy: [kimi]
+    action:
+      method: acp
+      provider: deepseek
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o600))
+
+	cfg, err := LoadRoutingConfig(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+
+	match := cfg.Rules[0].Match
+	assert.Equal(t, []string{"internal/**/migrations/*.sql"}, match.Files)
+	require.NotNil(t, match.Time)
+	assert.Equal(t, "22:00", match.Time.After)
+	require.Len(t, match.Any, 2)
+	assert.Equal(t, []string{"developer"}, match.Any[1].AgentRole)
+	require.NotNil(t, match.Not)
+	assert.Equal(t, []string{"kimi"}, match.Not.Healthy)
+}
@@ -0,0 +1,129 @@
+package router
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/victorzhuk/go-ent/internal/breaker"
+)
+
+// providerAvailable reports whether neither the named provider nor its
+// vendor has an open circuit. Half-open providers are available while no
+// probe is in flight, so the next call can probe them.
+func (r *Router) providerAvailable(name string) bool {
+	if r.breakers == nil {
+		return true
+	}
+	if !r.breakers.Available(name) {
+		return false
+	}
+	if vendor := r.providerVendor(name); vendor != "" {
+		return r.breakers.Available(vendor)
+	}
+	return true
+}
+
+// claimProvider is providerAvailable for the provider a decision will use:
+// it also claims the probe of a half-open provider or vendor, so concurrent
+// routes do not all probe a recovering provider at once. When the vendor
+// refuses, the provider's probe is released again.
+func (r *Router) claimProvider(name string) bool {
+	if r.breakers == nil {
+		return true
+	}
+	if !r.breakers.Allow(name) {
+		return false
+	}
+	if vendor := r.providerVendor(name); vendor != "" && !r.breakers.Allow(vendor) {
+		r.breakers.Release(name)
+		return false
+	}
+	return true
+}
+
+// providerVendor returns the vendor breaker name of a provider, or "" when
+// the provider is its own vendor.
+func (r *Router) providerVendor(name string) string {
+	if def, ok := r.providers[name]; ok && def.Provider != "" && def.Provider != name {
+		return def.Provider
+	}
+	return ""
+}
+
+// failover replaces a decision whose provider has an open circuit with the
+// next learned candidate, a configured default, or the cheapest available
+// provider, in that order.
+func (r *Router) failover(decision *RoutingDecision, chars taskCharacteristics) error {
+	original := decision.Provider
+
+	use := func(name, via string) {
+		def := r.providers[name]
+		decision.Provider = name
+		decision.Model = def.Model
+		decision.Method = def.Method
+		decision.EstimatedCost = r.estimateCost(def, chars, 1.0)
+		decision.Reason = fmt.Sprintf("%s; %s circuit %s, failover to %s (%s)",
+			decision.Reason, original, breaker.StateOpen, name, via)
+
+		r.logger.Warn("provider circuit open, failing over",
+			"provider", original,
+			"fallback_provider", name,
+			"via", via,
+		)
+	}
+
+	for _, c := range decision.Candidates {
+		if c.Provider != original && r.claimProvider(c.Provider) {
+			use(c.Provider, "next candidate")
+			return nil
+		}
+	}
+
+	for _, name := range r.defaultProviders() {
+		if _, ok := r.providers[name]; ok && name != original && r.claimProvider(name) {
+			use(name, "default")
+			return nil
+		}
+	}
+
+	var available []string
+	for name := range r.providers {
+		if name != original && r.providerAvailable(name) {
+			available = append(available, name)
+		}
+	}
+	slices.SortFunc(available, func(a, b string) int {
+		if c := cmp.Compare(r.estimateCost(r.providers[a], chars, 1.0), r.estimateCost(r.providers[b], chars, 1.0)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	for _, name := range available {
+		if r.claimProvider(name) {
+			use(name, "cheapest available")
+			return nil
+		}
+	}
+
+	return fmt.Errorf("provider %s circuit open: %w", original, ErrNoHealthyProvider)
+}
+
+// defaultProviders lists the configured default routes without duplicates.
+func (r *Router) defaultProviders() []string {
+	var names []string
+	for _, name := range []string{
+		r.defaults.Implementation,
+		r.defaults.SimpleTasks,
+		r.defaults.LargeContext,
+		r.defaults.ComplexTasks,
+		r.defaults.Research,
+		r.defaults.Planning,
+		r.defaults.Review,
+	} {
+		if name != "" && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,142 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/breaker"
+	"github.com/victorzhuk/go-ent/internal/execution"
+)
+
+func openCircuit(reg *breaker.Registry, names ...string) {
+	for _, name := range names {
+		reg.Record(name, errors.New("HTTP 503"))
+	}
+}
+
+func newFailoverRouter(t *testing.T, reg *breaker.Registry) *Router {
+	t.Helper()
+	r := newScoringRouter(t, nil, WithBreakers(reg))
+	r.SetRules([]RoutingRule{{
+		ID:     "cheap-first",
+		Match:  MatchConditions{Type: []string{"implement"}},
+		Action: RouteAction{Method: "cli", Provider: "cheap", Model: "deepseek-coder"},
+	}})
+	r.SetDefaults(DefaultRoutes{Implementation: "reliable"})
+	return r
+}
+
+func TestRouter_Failover(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		open     []string
+		expected string
+		reason   string
+		err      error
+	}{
+		{name: "closed circuit keeps rule decision", expected: "cheap"},
+		{name: "open provider fails over to default", open: []string{"cheap"}, expected: "reliable", reason: "cheap circuit open, failover to reliable (default)"},
+		{name: "open vendor counts as open provider", open: []string{"deepseek", "anthropic"}, expected: "fresh", reason: "failover to fresh (cheapest available)"},
+		{name: "every circuit open", open: []string{"cheap", "reliable", "fresh"}, err: ErrNoHealthyProvider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1})
+			openCircuit(reg, tt.open...)
+			r := newFailoverRouter(t, reg)
+
+			decision, err := r.Route(context.Background(), execution.NewTask("add endpoint").WithType("implement"))
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, decision.Provider)
+			assert.Equal(t, scoringProviders()[tt.expected].Model, decision.Model)
+			assert.Contains(t, decision.Reason, tt.reason)
+		})
+	}
+}
+
+func TestRouter_FailoverWhileProbing(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	reg.SetClock(func() time.Time { return now })
+	openCircuit(reg, "cheap")
+	r := newFailoverRouter(t, reg)
+	task := execution.NewTask("add endpoint").WithType("implement")
+
+	now = now.Add(time.Minute)
+	decision, err := r.Route(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", decision.Provider, "the half-open provider gets the probe")
+
+	decision, err = r.Route(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "reliable", decision.Provider, "other routes fail over while the probe runs")
+
+	reg.Record("cheap", nil)
+	decision, err = r.Route(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", decision.Provider)
+}
+
+func TestRouter_ClaimReleasesProviderProbe(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1, OpenTimeout: time.Minute})
+	reg.SetClock(func() time.Time { return now })
+	openCircuit(reg, "cheap", "deepseek")
+	r := newFailoverRouter(t, reg)
+
+	now = now.Add(time.Minute)
+	require.True(t, reg.Allow("deepseek"), "another route probes the vendor")
+
+	assert.False(t, r.claimProvider("cheap"))
+	assert.True(t, reg.Available("cheap"), "the provider probe is not held when the vendor refuses")
+
+	reg.Record("deepseek", nil)
+	assert.True(t, r.claimProvider("cheap"))
+}
+
+func TestRouter_FailoverKeepsOverride(t *testing.T) {
+	t.Parallel()
+
+	reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1})
+	openCircuit(reg, "cheap")
+	r := newFailoverRouter(t, reg)
+
+	task := execution.NewTask("add endpoint").WithType("implement")
+	task.ForceProvider = "cheap"
+
+	decision, err := r.Route(context.Background(), task)
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", decision.Provider)
+}
+
+func TestRouter_HealthyConditionUsesBreakers(t *testing.T) {
+	t.Parallel()
+
+	reg := breaker.NewRegistry(breaker.Config{FailureThreshold: 1})
+	openCircuit(reg, "anthropic")
+	r := newScoringRouter(t, nil, WithBreakers(reg))
+
+	task := execution.NewTask("add endpoint").WithType("implement")
+	chars := r.analyzeTask(task)
+
+	assert.Empty(t, r.matchConditions(MatchConditions{Healthy: []string{"cheap"}}, task, chars))
+	assert.Equal(t, "provider reliable unhealthy", r.matchConditions(MatchConditions{Healthy: []string{"reliable"}}, task, chars))
+}
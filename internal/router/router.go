@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"slices"
 	"strings"
+	"time"
 
+	"github.com/victorzhuk/go-ent/internal/breaker"
 	"github.com/victorzhuk/go-ent/internal/config"
 	"github.com/victorzhuk/go-ent/internal/execution"
 	"github.com/victorzhuk/go-ent/internal/memory"
@@ -31,6 +33,9 @@ var (
 
 	// ErrInvalidModel indicates the requested model is not available.
 	ErrInvalidModel = fmt.Errorf("invalid model")
+
+	// ErrNoHealthyProvider indicates every fallback provider has an open circuit.
+	ErrNoHealthyProvider = fmt.Errorf("no healthy provider")
 )
 
 type DefaultRoutes struct {
@@ -74,6 +79,9 @@ type RoutingDecision struct {
 
 	// RuleName indicates which rule produced this decision.
 	RuleName string
+
+	// Candidates ranks every eligible provider when the decision was learned.
+	Candidates []ProviderScore
 }
 
 type Router struct {
@@ -86,6 +94,11 @@ type Router struct {
 	memory          *memory.MemoryStore
 	logger          *slog.Logger
 	learningEnabled bool
+	scoring         ScoringConfig
+	random          func() float64
+	now             func() time.Time
+	healthy         func(provider string) bool
+	breakers        *breaker.Registry
 }
 
 func NewRouter(config *worker.Config, memoryStore *memory.MemoryStore, opts ...RouterOption) (*Router, error) {
@@ -106,12 +119,20 @@ func NewRouter(config *worker.Config, memoryStore *memory.MemoryStore, opts ...R
 		memory:          memoryStore,
 		logger:          slog.Default(),
 		learningEnabled: memoryStore != nil,
+		scoring:         DefaultScoringConfig(),
+		random:          defaultRandom,
+		now:             time.Now,
+		breakers:        breaker.Default(),
 	}
 
 	for _, opt := range opts {
 		opt(router)
 	}
 
+	if router.healthy == nil {
+		router.healthy = router.providerAvailable
+	}
+
 	if config.CostTracking != nil && config.CostTracking.Enabled {
 		router.SetCostBudget(config.CostTracking.GlobalBudget)
 	}
@@ -134,6 +155,29 @@ func WithRoutingConfig(path string) RouterOption {
 	}
 }
 
+// WithClock replaces the clock used by time window conditions.
+func WithClock(now func() time.Time) RouterOption {
+	return func(r *Router) {
+		r.now = now
+	}
+}
+
+// WithHealthCheck sets the function healthy conditions ask about providers.
+// Without one the circuit breakers decide.
+func WithHealthCheck(healthy func(provider string) bool) RouterOption {
+	return func(r *Router) {
+		r.healthy = healthy
+	}
+}
+
+// WithBreakers sets the circuit breakers consulted before routing to a
+// provider, replacing the process-wide registry.
+func WithBreakers(breakers *breaker.Registry) RouterOption {
+	return func(r *Router) {
+		r.breakers = breakers
+	}
+}
+
 func (r *Router) SetRules(rules []RoutingRule) {
 	slices.SortFunc(rules, func(a, b RoutingRule) int {
 		return b.Priority - a.Priority
@@ -173,6 +217,14 @@ func (r *Router) Route(ctx context.Context, task *execution.Task) (*RoutingDecis
 		return nil, err
 	}
 
+	if !r.claimProvider(decision.Provider) {
+		if decision.RuleName == "override" {
+			r.logger.Warn("overridden provider circuit open, routing anyway", "provider", decision.Provider)
+		} else if err := r.failover(decision, r.analyzeTask(task)); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := r.checkBudget(decision); err != nil {
 		return nil, fmt.Errorf("budget check: %w", err)
 	}
@@ -186,9 +238,11 @@ func (r *Router) applyRoutingRules(ctx context.Context, task *execution.Task) (*
 	taskCharacteristics := r.analyzeTask(task)
 
 	for _, rule := range r.rules {
-		if r.ruleMatches(rule, task, taskCharacteristics) {
+		matched, reason := r.ruleMatches(rule, task, taskCharacteristics)
+		if matched {
 			return r.createDecision(rule, task, taskCharacteristics), nil
 		}
+		r.logger.Debug("rule did not match", "rule", rule.ID, "failed", reason)
 	}
 
 	return r.applyDefaultRouting(ctx, task, taskCharacteristics)
@@ -301,67 +355,39 @@ func (r *Router) routeWithLearning(ctx context.Context, task *execution.Task) (*
 		return nil, fmt.Errorf("insufficient pattern data")
 	}
 
-	recommendation := r.memory.Query(task.Type, chars.fileCount, chars.estimatedTokens)
-	if recommendation == nil {
+	candidates := r.explore(r.rankProviders(task.Type, chars))
+	if len(candidates) == 0 || (candidates[0].Samples == 0 && !candidates[0].Explored) {
 		return nil, fmt.Errorf("no matching pattern")
 	}
 
-	_, exists := r.providers[recommendation.Provider]
-	if !exists {
-		return nil, fmt.Errorf("recommended provider %s not found: %w", recommendation.Provider, ErrProviderNotFound)
-	}
-
+	best := candidates[0]
 	decision := &RoutingDecision{
-		Method:        config.CommunicationMethod(recommendation.Method),
-		Provider:      recommendation.Provider,
-		Model:         recommendation.Model,
-		Reason:        fmt.Sprintf("learned pattern: %s (confidence: %.1f%%)", recommendation.Reason, recommendation.Confidence*100),
-		EstimatedCost: recommendation.EstimatedCost,
+		Method:        best.Method,
+		Provider:      best.Provider,
+		Model:         best.Model,
+		Reason:        fmt.Sprintf("learned pattern: %s scored %.2f (%s)", best.Provider, best.Score, strings.Join(best.Reasons, ", ")),
+		EstimatedCost: best.ExpectedCost,
 		RuleName:      "learned_pattern",
+		Candidates:    candidates,
 	}
 
 	r.logger.Info("using learned pattern",
 		"task_type", task.Type,
 		"provider", decision.Provider,
 		"model", decision.Model,
-		"confidence", fmt.Sprintf("%.1f%%", recommendation.Confidence*100),
-		"reason", recommendation.Reason,
+		"score", fmt.Sprintf("%.2f", best.Score),
+		"candidates", len(candidates),
+		"explored", best.Explored,
 	)
 
 	return decision, nil
 }
 
-func (r *Router) ruleMatches(rule RoutingRule, task *execution.Task, chars taskCharacteristics) bool {
-	if rule.Match.Complexity != "" && rule.Match.Complexity != chars.complexity {
-		return false
-	}
-
-	if len(rule.Match.Type) > 0 {
-		matches := false
-		for _, tt := range rule.Match.Type {
-			if strings.EqualFold(tt, task.Type) {
-				matches = true
-				break
-			}
-		}
-		if !matches {
-			return false
-		}
-	}
-
-	if rule.Match.FileCount != nil && chars.fileCount < *rule.Match.FileCount {
-		return false
-	}
-
-	if rule.Match.ContextSize != nil && chars.estimatedTokens < *rule.Match.ContextSize {
-		return false
-	}
-
-	if !rule.MatchKeywords(task.Description) {
-		return false
-	}
-
-	return true
+// ruleMatches reports whether the rule applies to the task and, when it does
+// not, which sub-condition failed.
+func (r *Router) ruleMatches(rule RoutingRule, task *execution.Task, chars taskCharacteristics) (bool, string) {
+	reason := r.matchConditions(rule.Match, task, chars)
+	return reason == "", reason
 }
 
 func (r *Router) createDecision(rule RoutingRule, task *execution.Task, chars taskCharacteristics) *RoutingDecision {
@@ -442,6 +468,7 @@ type taskCharacteristics struct {
 	fileCount       int
 	estimatedTokens int
 	hasFiles        bool
+	languages       []string
 }
 
 func (r *Router) analyzeTask(task *execution.Task) taskCharacteristics {
@@ -450,6 +477,7 @@ func (r *Router) analyzeTask(task *execution.Task) taskCharacteristics {
 	if task.Context != nil {
 		chars.fileCount = len(task.Context.Files)
 		chars.hasFiles = chars.fileCount > 0
+		chars.languages = detectLanguages(task.Context.Files)
 	}
 
 	chars.estimatedTokens = r.estimateTokens(task)
@@ -622,6 +650,10 @@ func (r *Router) findCheaperProvider(decision *RoutingDecision) (*providerFallba
 	var chars taskCharacteristics
 
 	for name, provider := range r.providers {
+		if !r.providerAvailable(name) {
+			continue
+		}
+
 		cost := r.estimateCost(provider, chars, 1.0)
 		if cost < decision.EstimatedCost && cost <= r.remainingBudget {
 			if cheapest == nil || cost < cheapest.EstimatedCost {
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"os"
 	"slices"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 
@@ -34,6 +33,30 @@ type MatchConditions struct {
 	ContextSize *int     `yaml:"context_size,omitempty"`
 	Keywords    []string `yaml:"keywords,omitempty"`
 	MaxCost     *float64 `yaml:"max_cost,omitempty"`
+
+	// Files matches when any task file matches one of the globs; "**"
+	// spans directories, e.g. internal/**/migrations/*.sql.
+	Files []string `yaml:"files,omitempty"`
+
+	// Languages matches languages detected from task file extensions.
+	Languages []string `yaml:"languages,omitempty"`
+
+	// Labels matches OpenSpec change labels from task metadata.
+	Labels []string `yaml:"labels,omitempty"`
+
+	// AgentRole matches the agent role the task runs for.
+	AgentRole []string `yaml:"agent_role,omitempty"`
+
+	// Time restricts the rule to hours of the day and days of the week.
+	Time *TimeWindow `yaml:"time,omitempty"`
+
+	// Healthy requires every listed provider to be healthy.
+	Healthy []string `yaml:"healthy,omitempty"`
+
+	// All, Any and Not compose nested conditions.
+	All []MatchConditions `yaml:"all,omitempty"`
+	Any []MatchConditions `yaml:"any,omitempty"`
+	Not *MatchConditions  `yaml:"not,omitempty"`
 }
 
 type RouteAction struct {
@@ -103,28 +126,8 @@ func (r *RoutingRule) Validate() error {
 		return fmt.Errorf("action: %w", err)
 	}
 
-	validTypes := []string{"implement", "refactor", "analyze", "fix", "test", "feature", "bugfix", "documentation"}
-	for _, t := range r.Match.Type {
-		if !slices.Contains(validTypes, t) {
-			return fmt.Errorf("invalid type %q, must be one of: %s", t, strings.Join(validTypes, ", "))
-		}
-	}
-
-	validComplexity := []string{"trivial", "simple", "medium", "complex", "high"}
-	if r.Match.Complexity != "" && !slices.Contains(validComplexity, r.Match.Complexity) {
-		return fmt.Errorf("invalid complexity %q, must be one of: %s", r.Match.Complexity, strings.Join(validComplexity, ", "))
-	}
-
-	if r.Match.FileCount != nil && *r.Match.FileCount < 0 {
-		return fmt.Errorf("file_count must be non-negative")
-	}
-
-	if r.Match.ContextSize != nil && *r.Match.ContextSize < 0 {
-		return fmt.Errorf("context_size must be non-negative")
-	}
-
-	if r.Match.MaxCost != nil && *r.Match.MaxCost < 0 {
-		return fmt.Errorf("max_cost must be non-negative")
+	if err := r.Match.Validate(); err != nil {
+		return err
 	}
 
 	return nil
@@ -248,15 +251,5 @@ func (c *RoutingConfig) SortRules() {
 }
 
 func (r *RoutingRule) MatchKeywords(description string) bool {
-	if len(r.Match.Keywords) == 0 {
-		return true
-	}
-
-	descLower := strings.ToLower(description)
-	for _, kw := range r.Match.Keywords {
-		if strings.Contains(descLower, strings.ToLower(kw)) {
-			return true
-		}
-	}
-	return false
+	return matchKeywords(r.Match.Keywords, description)
 }
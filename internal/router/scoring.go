@@ -0,0 +1,256 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/memory"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+// ScoringConfig weighs cost, success and latency when ranking providers.
+type ScoringConfig struct {
+	// SuccessWeight weighs the smoothed success probability.
+	SuccessWeight float64
+
+	// CostWeight weighs the expected cost per successful run.
+	CostWeight float64
+
+	// LatencyWeight weighs the expected duration.
+	LatencyWeight float64
+
+	// PriorStrength is the number of pseudo-observations backing the priors.
+	PriorStrength float64
+
+	// PriorSuccess is the success probability assumed for unseen providers.
+	PriorSuccess float64
+
+	// PriorLatency is the duration assumed when nothing has been recorded.
+	PriorLatency time.Duration
+
+	// ExplorationRate is the probability of picking an under-sampled provider.
+	ExplorationRate float64
+
+	// MinSamples is the number of runs after which a provider is no longer
+	// considered for exploration.
+	MinSamples int
+}
+
+// DefaultScoringConfig returns the weights used when none are configured.
+func DefaultScoringConfig() ScoringConfig {
+	return ScoringConfig{
+		SuccessWeight:   0.5,
+		CostWeight:      0.3,
+		LatencyWeight:   0.2,
+		PriorStrength:   2,
+		PriorSuccess:    0.7,
+		PriorLatency:    30 * time.Second,
+		ExplorationRate: 0.05,
+		MinSamples:      3,
+	}
+}
+
+// ProviderScore is one ranked candidate of a learned routing decision.
+type ProviderScore struct {
+	Provider           string
+	Method             config.CommunicationMethod
+	Model              string
+	Score              float64
+	SuccessProbability float64
+	ExpectedCost       float64
+	ExpectedLatency    time.Duration
+	Samples            int
+	Explored           bool
+	Reasons            []string
+}
+
+// WithScoring sets the weights and exploration rate of learned routing.
+func WithScoring(cfg ScoringConfig) RouterOption {
+	return func(r *Router) {
+		r.scoring = cfg
+	}
+}
+
+// WithRandom replaces the source used to decide when to explore.
+func WithRandom(fn func() float64) RouterOption {
+	return func(r *Router) {
+		r.random = fn
+	}
+}
+
+type providerObservation struct {
+	samples   int
+	successes int
+	cost      float64
+	duration  time.Duration
+}
+
+func (o providerObservation) add(out memory.Outcome) providerObservation {
+	o.samples += out.Executions
+	o.successes += out.Successes
+	o.cost += out.TotalCost
+	o.duration += out.TotalDuration
+	return o
+}
+
+// rankProviders scores every eligible provider for taskType, skipping
+// providers over their context limit or with an open circuit. Success is a
+// Beta-smoothed rate whose prior is the provider's record on other task
+// types; cost and latency shrink observed averages toward static estimates.
+func (r *Router) rankProviders(taskType string, chars taskCharacteristics) []ProviderScore {
+	cfg := r.scoring
+
+	byTask := make(map[string]providerObservation)
+	for _, out := range r.memory.Outcomes(taskType) {
+		byTask[out.Provider] = byTask[out.Provider].add(out)
+	}
+	overall := make(map[string]providerObservation)
+	for _, out := range r.memory.Outcomes("") {
+		overall[out.Provider] = overall[out.Provider].add(out)
+	}
+
+	priorLatency := cfg.PriorLatency
+	var seen providerObservation
+	for _, obs := range byTask {
+		seen.samples += obs.samples
+		seen.duration += obs.duration
+	}
+	if seen.samples > 0 {
+		priorLatency = seen.duration / time.Duration(seen.samples)
+	}
+
+	k := cfg.PriorStrength
+	candidates := make([]ProviderScore, 0, len(r.providers))
+	for name, def := range r.providers {
+		if def.ContextLimit > 0 && chars.estimatedTokens > def.ContextLimit {
+			continue
+		}
+		if !r.providerAvailable(name) {
+			continue
+		}
+
+		obs := byTask[name]
+		other := overall[name]
+		other.samples -= obs.samples
+		other.successes -= obs.successes
+
+		prior := (float64(other.successes) + k*cfg.PriorSuccess) / (float64(other.samples) + k)
+		n := float64(obs.samples)
+		success := (float64(obs.successes) + k*prior) / (n + k)
+
+		cost := (obs.cost + k*r.staticCost(def, chars)) / (n + k)
+		latency := time.Duration((float64(obs.duration) + k*float64(priorLatency)) / (n + k))
+
+		candidates = append(candidates, ProviderScore{
+			Provider:           name,
+			Method:             def.Method,
+			Model:              def.Model,
+			SuccessProbability: success,
+			ExpectedCost:       cost,
+			ExpectedLatency:    latency,
+			Samples:            obs.samples,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	minCost, minLatency := math.Inf(1), math.Inf(1)
+	for _, c := range candidates {
+		minCost = math.Min(minCost, costPerSuccess(c))
+		minLatency = math.Min(minLatency, float64(c.ExpectedLatency))
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		c.Score = cfg.SuccessWeight*c.SuccessProbability +
+			cfg.CostWeight*ratio(minCost, costPerSuccess(*c)) +
+			cfg.LatencyWeight*ratio(minLatency, float64(c.ExpectedLatency))
+		c.Reasons = []string{
+			fmt.Sprintf("success %.1f%% over %d runs", c.SuccessProbability*100, c.Samples),
+			fmt.Sprintf("expected cost $%.4f", c.ExpectedCost),
+			fmt.Sprintf("expected latency %v", c.ExpectedLatency.Round(time.Millisecond)),
+		}
+	}
+
+	slices.SortFunc(candidates, func(a, b ProviderScore) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Provider, b.Provider)
+	})
+
+	return candidates
+}
+
+// explore moves the least-sampled provider to the front with probability
+// ExplorationRate, so providers without history still get tried.
+func (r *Router) explore(candidates []ProviderScore) []ProviderScore {
+	if len(candidates) < 2 || r.scoring.ExplorationRate <= 0 || r.random() >= r.scoring.ExplorationRate {
+		return candidates
+	}
+
+	pick := -1
+	for i, c := range candidates {
+		if c.Samples >= r.scoring.MinSamples {
+			continue
+		}
+		if pick < 0 || c.Samples < candidates[pick].Samples {
+			pick = i
+		}
+	}
+	if pick <= 0 {
+		return candidates
+	}
+
+	chosen := candidates[pick]
+	chosen.Explored = true
+	chosen.Reasons = append(chosen.Reasons, fmt.Sprintf("exploring: fewer than %d runs", r.scoring.MinSamples))
+
+	ranked := make([]ProviderScore, 0, len(candidates))
+	ranked = append(ranked, chosen)
+	ranked = append(ranked, candidates[:pick]...)
+	return append(ranked, candidates[pick+1:]...)
+}
+
+// staticCost estimates a run from configured pricing, falling back to the
+// method and provider heuristics of estimateCost.
+func (r *Router) staticCost(def worker.ProviderDefinition, chars taskCharacteristics) float64 {
+	if def.Cost != nil && def.Cost.Per1kTokens > 0 {
+		return def.Cost.Per1kTokens * float64(chars.estimatedTokens) / 1000
+	}
+	return r.estimateCost(def, chars, 1.0)
+}
+
+func costPerSuccess(c ProviderScore) float64 {
+	if c.SuccessProbability <= 0 {
+		return math.Inf(1)
+	}
+	return c.ExpectedCost / c.SuccessProbability
+}
+
+// ratio returns best/value in [0, 1], treating a zero best as a tie: a
+// provider with unknown or zero cost or latency does not push every other
+// provider to the bottom.
+func ratio(best, value float64) float64 {
+	if math.IsInf(value, 1) {
+		return 0
+	}
+	if best <= 0 || value <= 0 {
+		return 1
+	}
+	return best / value
+}
+
+func defaultRandom() float64 {
+	return rand.Float64()
+}
@@ -0,0 +1,255 @@
+package router
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/execution"
+	"github.com/victorzhuk/go-ent/internal/memory"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+func scoringProviders() map[string]worker.ProviderDefinition {
+	return map[string]worker.ProviderDefinition{
+		"cheap": {
+			Method:   config.MethodCLI,
+			Provider: "deepseek",
+			Model:    "deepseek-coder",
+		},
+		"reliable": {
+			Method:   config.MethodAPI,
+			Provider: "anthropic",
+			Model:    "claude-sonnet",
+		},
+		"fresh": {
+			Method:   config.MethodACP,
+			Provider: "moonshot",
+			Model:    "glm-4",
+		},
+	}
+}
+
+func storeRuns(t *testing.T, mem *memory.MemoryStore, taskType, provider string, runs, successes int, cost float64, duration time.Duration) {
+	t.Helper()
+	for i := 0; i < runs; i++ {
+		require.NoError(t, mem.Store(&memory.Pattern{
+			TaskType: taskType,
+			Provider: provider,
+			Success:  i < successes,
+			Cost:     cost,
+			Duration: duration,
+		}))
+	}
+}
+
+func newScoringRouter(t *testing.T, mem *memory.MemoryStore, opts ...RouterOption) *Router {
+	t.Helper()
+	cfg := worker.DefaultConfig()
+	cfg.Providers = scoringProviders()
+	r, err := NewRouter(cfg, mem, opts...)
+	require.NoError(t, err)
+	return r
+}
+
+func TestRatio(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		best, value float64
+		want        float64
+	}{
+		{"best", 2, 2, 1},
+		{"twice the best", 2, 4, 0.5},
+		{"zero best is a tie", 0, 4, 1},
+		{"zero value", 0, 0, 1},
+		{"unreachable", 2, math.Inf(1), 0},
+		{"unreachable beside a zero best", 0, math.Inf(1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, ratio(tt.best, tt.value), 1e-9)
+		})
+	}
+}
+
+func TestRouter_RankProviders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		setup    func(t *testing.T, mem *memory.MemoryStore)
+		scoring  ScoringConfig
+		expected string
+	}{
+		{
+			name: "cheap provider wins when equally reliable",
+			setup: func(t *testing.T, mem *memory.MemoryStore) {
+				storeRuns(t, mem, "implement", "cheap", 20, 19, 0.005, 10*time.Second)
+				storeRuns(t, mem, "implement", "reliable", 20, 19, 0.05, 10*time.Second)
+			},
+			scoring:  DefaultScoringConfig(),
+			expected: "cheap",
+		},
+		{
+			name: "unreliable cheap provider loses",
+			setup: func(t *testing.T, mem *memory.MemoryStore) {
+				storeRuns(t, mem, "implement", "cheap", 20, 4, 0.005, 10*time.Second)
+				storeRuns(t, mem, "implement", "reliable", 20, 20, 0.05, 10*time.Second)
+			},
+			scoring:  ScoringConfig{SuccessWeight: 0.8, CostWeight: 0.1, LatencyWeight: 0.1, PriorStrength: 2, PriorSuccess: 0.7},
+			expected: "reliable",
+		},
+		{
+			name: "latency weight prefers faster provider",
+			setup: func(t *testing.T, mem *memory.MemoryStore) {
+				storeRuns(t, mem, "implement", "cheap", 20, 20, 0.01, time.Minute)
+				storeRuns(t, mem, "implement", "reliable", 20, 20, 0.01, time.Second)
+			},
+			scoring:  ScoringConfig{SuccessWeight: 0.2, CostWeight: 0, LatencyWeight: 0.8, PriorStrength: 2, PriorSuccess: 0.7},
+			expected: "reliable",
+		},
+		{
+			name: "other task types only inform the prior",
+			setup: func(t *testing.T, mem *memory.MemoryStore) {
+				storeRuns(t, mem, "review", "reliable", 50, 50, 0.05, time.Second)
+				storeRuns(t, mem, "implement", "cheap", 10, 9, 0.01, time.Second)
+			},
+			scoring:  DefaultScoringConfig(),
+			expected: "cheap",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mem := memory.NewMemoryStore()
+			tt.setup(t, mem)
+			r := newScoringRouter(t, mem, WithScoring(tt.scoring))
+
+			ranked := r.rankProviders("implement", r.analyzeTask(&execution.Task{Type: "implement"}))
+			require.Len(t, ranked, 3)
+			assert.Equal(t, tt.expected, ranked[0].Provider)
+			for i := 1; i < len(ranked); i++ {
+				assert.GreaterOrEqual(t, ranked[i-1].Score, ranked[i].Score)
+			}
+		})
+	}
+}
+
+func TestRouter_RankProviders_Smoothing(t *testing.T) {
+	t.Parallel()
+
+	mem := memory.NewMemoryStore()
+	storeRuns(t, mem, "implement", "cheap", 2, 2, 0.01, time.Second)
+	r := newScoringRouter(t, mem)
+
+	ranked := r.rankProviders("implement", r.analyzeTask(&execution.Task{Type: "implement"}))
+	byName := make(map[string]ProviderScore)
+	for _, c := range ranked {
+		byName[c.Provider] = c
+	}
+
+	cheap := byName["cheap"]
+	assert.Equal(t, 2, cheap.Samples)
+	assert.InDelta(t, (2+2*0.7)/4.0, cheap.SuccessProbability, 1e-9)
+	assert.Less(t, cheap.SuccessProbability, 1.0)
+
+	fresh := byName["fresh"]
+	assert.Equal(t, 0, fresh.Samples)
+	assert.InDelta(t, 0.7, fresh.SuccessProbability, 1e-9)
+	assert.Equal(t, time.Second, fresh.ExpectedLatency)
+	assert.NotEmpty(t, fresh.Reasons)
+}
+
+func TestRouter_RankProviders_ContextLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := worker.DefaultConfig()
+	cfg.Providers = scoringProviders()
+	small := cfg.Providers["cheap"]
+	small.ContextLimit = 1000
+	cfg.Providers["cheap"] = small
+
+	mem := memory.NewMemoryStore()
+	r, err := NewRouter(cfg, mem)
+	require.NoError(t, err)
+
+	task := &execution.Task{
+		Type:    "implement",
+		Context: &execution.TaskContext{Files: []string{"a.go", "b.go"}},
+	}
+	ranked := r.rankProviders(task.Type, r.analyzeTask(task))
+	for _, c := range ranked {
+		assert.NotEqual(t, "cheap", c.Provider)
+	}
+}
+
+func TestRouter_RouteWithLearning_Candidates(t *testing.T) {
+	t.Parallel()
+
+	mem := memory.NewMemoryStore()
+	storeRuns(t, mem, "implement", "cheap", 10, 10, 0.005, 2*time.Second)
+	storeRuns(t, mem, "implement", "reliable", 10, 10, 0.05, 2*time.Second)
+	r := newScoringRouter(t, mem, WithRandom(func() float64 { return 1 }))
+
+	decision, err := r.Route(context.Background(), &execution.Task{Type: "implement", Description: "add feature"})
+	require.NoError(t, err)
+	assert.Equal(t, "cheap", decision.Provider)
+	assert.Equal(t, "deepseek-coder", decision.Model)
+	assert.Equal(t, config.MethodCLI, decision.Method)
+	assert.Equal(t, "learned_pattern", decision.RuleName)
+	assert.Contains(t, decision.Reason, "learned pattern")
+	require.Len(t, decision.Candidates, 3)
+	assert.Equal(t, "cheap", decision.Candidates[0].Provider)
+	assert.False(t, decision.Candidates[0].Explored)
+}
+
+func TestRouter_RouteWithLearning_Exploration(t *testing.T) {
+	t.Parallel()
+
+	mem := memory.NewMemoryStore()
+	storeRuns(t, mem, "implement", "cheap", 10, 10, 0.005, 2*time.Second)
+	storeRuns(t, mem, "implement", "reliable", 10, 10, 0.05, 2*time.Second)
+
+	tests := []struct {
+		name     string
+		random   float64
+		expected string
+		explored bool
+	}{
+		{name: "exploits below rate", random: 0.5, expected: "cheap"},
+		{name: "explores unseen provider", random: 0.01, expected: "fresh", explored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := newScoringRouter(t, mem, WithRandom(func() float64 { return tt.random }))
+			decision, err := r.Route(context.Background(), &execution.Task{Type: "implement"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decision.Provider)
+			assert.Equal(t, tt.explored, decision.Candidates[0].Explored)
+		})
+	}
+}
+
+func TestRouter_RouteWithLearning_NoHistoryFallsBack(t *testing.T) {
+	t.Parallel()
+
+	mem := memory.NewMemoryStore()
+	storeRuns(t, mem, "review", "reliable", 10, 10, 0.05, 2*time.Second)
+	r := newScoringRouter(t, mem, WithScoring(ScoringConfig{SuccessWeight: 1, PriorStrength: 2, PriorSuccess: 0.7}))
+
+	decision, err := r.routeWithLearning(context.Background(), &execution.Task{Type: "implement"})
+	require.Error(t, err)
+	assert.Nil(t, decision)
+}
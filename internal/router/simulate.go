@@ -0,0 +1,393 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/victorzhuk/go-ent/internal/execution"
+	"github.com/victorzhuk/go-ent/internal/memory"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+// HistoryTask is a recorded task replayed by Simulate. Timestamp is the time
+// the task was routed at, which time window conditions are evaluated against.
+type HistoryTask struct {
+	Type        string    `json:"type"`
+	Description string    `json:"description,omitempty"`
+	Files       []string  `json:"files,omitempty"`
+	FileCount   int       `json:"file_count,omitempty"`
+	Provider    string    `json:"provider,omitempty"`
+	Cost        float64   `json:"cost,omitempty"`
+	Timestamp   time.Time `json:"timestamp,omitzero"`
+	Labels      []string  `json:"labels,omitempty"`
+	AgentRole   string    `json:"agent_role,omitempty"`
+}
+
+// Task converts the record to a routable task. A bare file count becomes
+// placeholder file names so the router sees the same characteristics.
+func (h HistoryTask) Task() *execution.Task {
+	task := execution.NewTask(h.Description)
+	task.Type = h.Type
+	if len(h.Labels) > 0 {
+		task.Metadata[MetadataLabels] = h.Labels
+	}
+	if h.AgentRole != "" {
+		task.Metadata[MetadataAgentRole] = h.AgentRole
+	}
+
+	files := h.Files
+	if len(files) == 0 && h.FileCount > 0 {
+		files = make([]string, h.FileCount)
+		for i := range files {
+			files[i] = fmt.Sprintf("file%d", i+1)
+		}
+	}
+	if len(files) > 0 {
+		task.Context = &execution.TaskContext{Files: files}
+	}
+
+	return task
+}
+
+// metricRecord is the subset of a metrics.Metric that describes a task.
+type metricRecord struct {
+	Timestamp time.Time         `json:"Timestamp"`
+	Metadata  map[string]string `json:"Metadata"`
+}
+
+// LoadHistory reads recorded tasks from a JSON array or JSONL file. Records
+// may be HistoryTask objects, exported memory patterns, or metrics whose
+// metadata carries task_type, description, file_count, labels (comma
+// separated) and agent_role.
+func LoadHistory(path string) ([]HistoryTask, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read history: %w", err)
+	}
+
+	var raws []json.RawMessage
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		if err := json.Unmarshal(trimmed, &raws); err != nil {
+			return nil, fmt.Errorf("parse history: %w", err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(trimmed))
+		scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+		for line := 1; scanner.Scan(); line++ {
+			text := bytes.TrimSpace(scanner.Bytes())
+			if len(text) == 0 {
+				continue
+			}
+			if !json.Valid(text) {
+				return nil, fmt.Errorf("parse history line %d: invalid JSON", line)
+			}
+			raws = append(raws, json.RawMessage(slices.Clone(text)))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("scan history: %w", err)
+		}
+	}
+
+	history := make([]HistoryTask, 0, len(raws))
+	for i, raw := range raws {
+		task, ok, err := decodeHistoryRecord(raw)
+		if err != nil {
+			return nil, fmt.Errorf("history record %d: %w", i+1, err)
+		}
+		if ok {
+			history = append(history, task)
+		}
+	}
+
+	return history, nil
+}
+
+func decodeHistoryRecord(raw json.RawMessage) (HistoryTask, bool, error) {
+	var task HistoryTask
+	if err := json.Unmarshal(raw, &task); err != nil {
+		return HistoryTask{}, false, err
+	}
+	if task.Type != "" {
+		return task, true, nil
+	}
+
+	var pattern memory.Pattern
+	if err := json.Unmarshal(raw, &pattern); err == nil && pattern.TaskType != "" {
+		return HistoryTask{
+			Type:      pattern.TaskType,
+			FileCount: pattern.FileCount,
+			Provider:  pattern.Provider,
+			Cost:      pattern.Cost,
+			Timestamp: pattern.Timestamp,
+		}, true, nil
+	}
+
+	var metric metricRecord
+	if err := json.Unmarshal(raw, &metric); err == nil && metric.Metadata["task_type"] != "" {
+		count, _ := strconv.Atoi(metric.Metadata["file_count"])
+		var labels []string
+		for _, l := range strings.Split(metric.Metadata[MetadataLabels], ",") {
+			if l = strings.TrimSpace(l); l != "" {
+				labels = append(labels, l)
+			}
+		}
+		return HistoryTask{
+			Type:        metric.Metadata["task_type"],
+			Description: metric.Metadata["description"],
+			FileCount:   count,
+			Provider:    metric.Metadata["provider"],
+			Timestamp:   metric.Timestamp,
+			Labels:      labels,
+			AgentRole:   metric.Metadata[MetadataAgentRole],
+		}, true, nil
+	}
+
+	return HistoryTask{}, false, nil
+}
+
+// DecisionSummary is the comparable part of a RoutingDecision.
+type DecisionSummary struct {
+	Provider      string  `json:"provider,omitempty"`
+	Model         string  `json:"model,omitempty"`
+	Method        string  `json:"method,omitempty"`
+	Rule          string  `json:"rule,omitempty"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	Error         string  `json:"error,omitempty"`
+}
+
+func summarizeDecision(d *RoutingDecision, err error) DecisionSummary {
+	if err != nil {
+		return DecisionSummary{Error: err.Error()}
+	}
+	return DecisionSummary{
+		Provider:      d.Provider,
+		Model:         d.Model,
+		Method:        string(d.Method),
+		Rule:          d.RuleName,
+		EstimatedCost: d.EstimatedCost,
+	}
+}
+
+func (s DecisionSummary) same(o DecisionSummary) bool {
+	return s.Provider == o.Provider && s.Model == o.Model && s.Method == o.Method && s.Error == o.Error
+}
+
+// DecisionChange is a task routed differently by the candidate rules.
+type DecisionChange struct {
+	Index     int             `json:"index"`
+	Task      string          `json:"task"`
+	Before    DecisionSummary `json:"before"`
+	After     DecisionSummary `json:"after"`
+	CostDelta float64         `json:"cost_delta"`
+}
+
+// ShadowedRule matched some tasks but never won against higher-priority rules.
+type ShadowedRule struct {
+	Rule    string   `json:"rule"`
+	Matches int      `json:"matches"`
+	By      []string `json:"by"`
+}
+
+// SimulationReport compares baseline and candidate rules over a history.
+type SimulationReport struct {
+	Tasks         int              `json:"tasks"`
+	Changed       []DecisionChange `json:"changed"`
+	BaselineCost  float64          `json:"baseline_cost"`
+	CandidateCost float64          `json:"candidate_cost"`
+	CostDelta     float64          `json:"cost_delta"`
+	RuleHits      map[string]int   `json:"rule_hits"`
+	NeverFired    []string         `json:"never_fired"`
+	Shadowed      []ShadowedRule   `json:"shadowed"`
+	Errors        int              `json:"errors"`
+}
+
+// Simulate replays history through Router.Route with the baseline and the
+// candidate rules and reports what the candidate rules would change. Learned
+// routing, budget fallbacks and circuit breakers are disabled so only the
+// rules are compared. Each task is routed as of its Timestamp; tasks without
+// one are routed at the current time.
+func Simulate(ctx context.Context, cfg *worker.Config, baseline, candidate []RoutingRule, history []HistoryTask) (*SimulationReport, error) {
+	var at time.Time
+	clock := func() time.Time {
+		if at.IsZero() {
+			return time.Now()
+		}
+		return at
+	}
+
+	before, err := newSimulationRouter(cfg, baseline, clock)
+	if err != nil {
+		return nil, fmt.Errorf("baseline router: %w", err)
+	}
+	after, err := newSimulationRouter(cfg, candidate, clock)
+	if err != nil {
+		return nil, fmt.Errorf("candidate router: %w", err)
+	}
+
+	report := &SimulationReport{
+		Tasks:    len(history),
+		Changed:  []DecisionChange{},
+		RuleHits: make(map[string]int, len(after.rules)),
+	}
+
+	matches := make(map[string]int)
+	winners := make(map[string]map[string]bool)
+	for _, rule := range after.rules {
+		report.RuleHits[rule.ID] = 0
+	}
+
+	for i, h := range history {
+		task := h.Task()
+		at = h.Timestamp
+
+		b := summarizeDecision(before.Route(ctx, task))
+		a := summarizeDecision(after.Route(ctx, task))
+
+		if b.Error != "" || a.Error != "" {
+			report.Errors++
+		}
+		report.BaselineCost += b.EstimatedCost
+		report.CandidateCost += a.EstimatedCost
+
+		if !b.same(a) {
+			report.Changed = append(report.Changed, DecisionChange{
+				Index:     i,
+				Task:      describeHistoryTask(h),
+				Before:    b,
+				After:     a,
+				CostDelta: a.EstimatedCost - b.EstimatedCost,
+			})
+		}
+
+		chars := after.analyzeTask(task)
+		winner := ""
+		for _, rule := range after.rules {
+			if ok, _ := after.ruleMatches(rule, task, chars); !ok {
+				continue
+			}
+			matches[rule.ID]++
+			if winner == "" {
+				winner = rule.ID
+				report.RuleHits[rule.ID]++
+				continue
+			}
+			if winners[rule.ID] == nil {
+				winners[rule.ID] = make(map[string]bool)
+			}
+			winners[rule.ID][winner] = true
+		}
+	}
+
+	report.CostDelta = report.CandidateCost - report.BaselineCost
+
+	for _, rule := range after.rules {
+		switch {
+		case matches[rule.ID] == 0:
+			report.NeverFired = append(report.NeverFired, rule.ID)
+		case report.RuleHits[rule.ID] == 0:
+			by := make([]string, 0, len(winners[rule.ID]))
+			for id := range winners[rule.ID] {
+				by = append(by, id)
+			}
+			slices.Sort(by)
+			report.Shadowed = append(report.Shadowed, ShadowedRule{Rule: rule.ID, Matches: matches[rule.ID], By: by})
+		}
+	}
+
+	return report, nil
+}
+
+func newSimulationRouter(cfg *worker.Config, rules []RoutingRule, clock func() time.Time) (*Router, error) {
+	replay := *cfg
+	replay.CostTracking = nil
+
+	r, err := NewRouter(&replay, nil, WithBreakers(nil), WithClock(clock))
+	if err != nil {
+		return nil, err
+	}
+	r.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	r.SetRules(slices.Clone(rules))
+	r.SetDefaults(DefaultRoutes{
+		SimpleTasks:    cfg.Defaults.SimpleTasks,
+		Implementation: cfg.Defaults.Implementation,
+		LargeContext:   cfg.Defaults.LargeContext,
+		Research:       cfg.Defaults.Research,
+		Planning:       cfg.Defaults.Planning,
+		Review:         cfg.Defaults.Review,
+	})
+	return r, nil
+}
+
+func describeHistoryTask(h HistoryTask) string {
+	desc := h.Type
+	if h.Description != "" {
+		desc += ": " + h.Description
+	}
+	return desc
+}
+
+// FormatSimulation renders a simulation report as markdown.
+func FormatSimulation(report *SimulationReport) string {
+	var b strings.Builder
+
+	b.WriteString("# Routing Simulation\n\n")
+	fmt.Fprintf(&b, "**Tasks:** %d  \n", report.Tasks)
+	fmt.Fprintf(&b, "**Changed decisions:** %d  \n", len(report.Changed))
+	fmt.Fprintf(&b, "**Estimated cost:** $%.4f → $%.4f (%+.4f)  \n", report.BaselineCost, report.CandidateCost, report.CostDelta)
+	if report.Errors > 0 {
+		fmt.Fprintf(&b, "**Unroutable tasks:** %d  \n", report.Errors)
+	}
+
+	if len(report.Changed) > 0 {
+		b.WriteString("\n## Changed Decisions\n\n")
+		b.WriteString("| # | Task | Before | After | Cost Δ |\n")
+		b.WriteString("|---|------|--------|-------|--------|\n")
+		for _, c := range report.Changed {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s | %+.4f |\n", c.Index+1, c.Task, formatSummary(c.Before), formatSummary(c.After), c.CostDelta)
+		}
+	}
+
+	b.WriteString("\n## Rule Hits\n\n")
+	ids := make([]string, 0, len(report.RuleHits))
+	for id := range report.RuleHits {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		fmt.Fprintf(&b, "- %s: %d\n", id, report.RuleHits[id])
+	}
+
+	if len(report.NeverFired) > 0 {
+		b.WriteString("\n## Rules That Never Fire\n\n")
+		for _, id := range report.NeverFired {
+			fmt.Fprintf(&b, "- %s\n", id)
+		}
+	}
+
+	if len(report.Shadowed) > 0 {
+		b.WriteString("\n## Shadowed Rules\n\n")
+		for _, s := range report.Shadowed {
+			fmt.Fprintf(&b, "- %s: matched %d tasks, always preceded by %s\n", s.Rule, s.Matches, strings.Join(s.By, ", "))
+		}
+	}
+
+	return b.String()
+}
+
+func formatSummary(s DecisionSummary) string {
+	if s.Error != "" {
+		return "error: " + s.Error
+	}
+	return fmt.Sprintf("%s/%s (%s)", s.Provider, s.Model, s.Rule)
+}
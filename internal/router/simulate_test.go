@@ -0,0 +1,231 @@
+package router
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/config"
+	"github.com/victorzhuk/go-ent/internal/worker"
+)
+
+func TestLoadHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		expected []HistoryTask
+		wantErr  bool
+	}{
+		{
+			name:    "jsonl tasks",
+			content: "{\"type\":\"implement\",\"description\":\"add api\",\"files\":[\"a.go\"]}\n\n{\"type\":\"test\",\"file_count\":2}\n",
+			expected: []HistoryTask{
+				{Type: "implement", Description: "add api", Files: []string{"a.go"}},
+				{Type: "test", FileCount: 2},
+			},
+		},
+		{
+			name:     "json array of memory patterns",
+			content:  `[{"TaskType":"refactor","Provider":"glm","FileCount":4,"Cost":0.02}]`,
+			expected: []HistoryTask{{Type: "refactor", Provider: "glm", FileCount: 4, Cost: 0.02}},
+		},
+		{
+			name:     "metrics with task metadata",
+			content:  `[{"ToolName":"engine_execute","Metadata":{"task_type":"fix","file_count":"1"}},{"ToolName":"spec_list"}]`,
+			expected: []HistoryTask{{Type: "fix", FileCount: 1}},
+		},
+		{
+			name:    "task time labels and role",
+			content: `{"type":"fix","timestamp":"2026-03-02T22:00:00Z","labels":["hotfix"],"agent_role":"reviewer"}`,
+			expected: []HistoryTask{{
+				Type:      "fix",
+				Timestamp: time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC),
+				Labels:    []string{"hotfix"},
+				AgentRole: "reviewer",
+			}},
+		},
+		{
+			name:    "metrics with time labels and role",
+			content: `[{"Timestamp":"2026-03-02T22:00:00Z","Metadata":{"task_type":"fix","labels":"hotfix, api","agent_role":"reviewer"}}]`,
+			expected: []HistoryTask{{
+				Type:      "fix",
+				Timestamp: time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC),
+				Labels:    []string{"hotfix", "api"},
+				AgentRole: "reviewer",
+			}},
+		},
+		{
+			name:    "invalid line",
+			content: "{\"type\":\"implement\"}\nnot json\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "history")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			history, err := LoadHistory(path)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, history)
+		})
+	}
+}
+
+func simulationConfig() *worker.Config {
+	cfg := worker.DefaultConfig()
+	cfg.Providers = map[string]worker.ProviderDefinition{
+		"glm": {
+			Method:   config.MethodACP,
+			Provider: "moonshot",
+			Model:    "glm-4",
+		},
+		"haiku": {
+			Method:   config.MethodCLI,
+			Provider: "anthropic",
+			Model:    "claude-3-haiku",
+		},
+	}
+	cfg.Defaults.Implementation = "glm"
+	return cfg
+}
+
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+
+	baseline := []RoutingRule{
+		{
+			ID:       "implement-glm",
+			Priority: 100,
+			Match:    MatchConditions{Type: []string{"implement"}},
+			Action:   RouteAction{Method: "acp", Provider: "glm"},
+		},
+	}
+	candidate := []RoutingRule{
+		{
+			ID:       "tests-haiku",
+			Priority: 200,
+			Match:    MatchConditions{Type: []string{"test"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+		{
+			ID:       "implement-glm",
+			Priority: 100,
+			Match:    MatchConditions{Type: []string{"implement"}},
+			Action:   RouteAction{Method: "acp", Provider: "glm"},
+		},
+		{
+			ID:       "implement-haiku",
+			Priority: 50,
+			Match:    MatchConditions{Type: []string{"implement"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+		{
+			ID:       "docs-haiku",
+			Priority: 10,
+			Match:    MatchConditions{Type: []string{"documentation"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+	}
+	history := []HistoryTask{
+		{Type: "implement", Description: "add handler"},
+		{Type: "test", Description: "cover handler"},
+		{Type: "implement", FileCount: 2},
+	}
+
+	report, err := Simulate(context.Background(), simulationConfig(), baseline, candidate, history)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Tasks)
+	assert.Zero(t, report.Errors)
+
+	require.Len(t, report.Changed, 1)
+	change := report.Changed[0]
+	assert.Equal(t, 1, change.Index)
+	assert.Equal(t, "test: cover handler", change.Task)
+	assert.Equal(t, "glm", change.Before.Provider)
+	assert.Equal(t, "default", change.Before.Rule)
+	assert.Equal(t, "haiku", change.After.Provider)
+	assert.Equal(t, "tests-haiku", change.After.Rule)
+	assert.Less(t, change.CostDelta, 0.0)
+	assert.InDelta(t, report.CandidateCost-report.BaselineCost, report.CostDelta, 1e-9)
+
+	assert.Equal(t, 2, report.RuleHits["implement-glm"])
+	assert.Equal(t, 1, report.RuleHits["tests-haiku"])
+	assert.Equal(t, []string{"docs-haiku"}, report.NeverFired)
+	require.Len(t, report.Shadowed, 1)
+	assert.Equal(t, ShadowedRule{Rule: "implement-haiku", Matches: 2, By: []string{"implement-glm"}}, report.Shadowed[0])
+
+	md := FormatSimulation(report)
+	assert.Contains(t, md, "**Changed decisions:** 1")
+	assert.Contains(t, md, "## Rules That Never Fire")
+	assert.Contains(t, md, "implement-haiku: matched 2 tasks, always preceded by implement-glm")
+}
+
+func TestSimulate_ReplaysTaskContext(t *testing.T) {
+	t.Parallel()
+
+	candidate := []RoutingRule{
+		{
+			ID:       "nightly-haiku",
+			Priority: 300,
+			Match:    MatchConditions{Time: &TimeWindow{After: "20:00", Before: "06:00", Timezone: "UTC"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+		{
+			ID:       "hotfix-haiku",
+			Priority: 200,
+			Match:    MatchConditions{Labels: []string{"hotfix"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+		{
+			ID:       "reviewer-haiku",
+			Priority: 100,
+			Match:    MatchConditions{AgentRole: []string{"reviewer"}},
+			Action:   RouteAction{Method: "cli", Provider: "haiku"},
+		},
+	}
+	noon := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	history := []HistoryTask{
+		{Type: "implement", Timestamp: time.Date(2026, 3, 2, 22, 0, 0, 0, time.UTC)},
+		{Type: "implement", Timestamp: noon, Labels: []string{"hotfix"}},
+		{Type: "implement", Timestamp: noon, AgentRole: "reviewer"},
+		{Type: "implement", Timestamp: noon},
+	}
+
+	report, err := Simulate(context.Background(), simulationConfig(), nil, candidate, history)
+	require.NoError(t, err)
+	assert.Zero(t, report.Errors)
+
+	require.Len(t, report.Changed, 3)
+	assert.Equal(t, "nightly-haiku", report.Changed[0].After.Rule)
+	assert.Equal(t, "hotfix-haiku", report.Changed[1].After.Rule)
+	assert.Equal(t, "reviewer-haiku", report.Changed[2].After.Rule)
+	assert.Empty(t, report.NeverFired)
+}
+
+func TestSimulate_IgnoresBudget(t *testing.T) {
+	t.Parallel()
+
+	cfg := simulationConfig()
+	cfg.CostTracking = &config.CostTrackingConfig{Enabled: true, GlobalBudget: 0.0001}
+
+	report, err := Simulate(context.Background(), cfg, nil, nil, []HistoryTask{{Type: "implement"}})
+	require.NoError(t, err)
+	assert.Zero(t, report.Errors)
+	assert.Empty(t, report.Changed)
+	assert.Positive(t, report.BaselineCost)
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -205,12 +206,18 @@ func matchFilePattern(pattern, fileType string) bool {
 }
 
 // Registry manages skill metadata and matching.
+//
+// The skill list is copy-on-write: writers build a new slice and swap it in
+// under mu, so readers iterate a snapshot that a reload never mutates.
 type Registry struct {
+	mu            sync.RWMutex
 	skills        []SkillMeta
+	digests       map[string]string
 	runtimeSkills map[string]domain.Skill
 	parser        *Parser
 	validator     *Validator
 	scorer        *QualityScorer
+	onMatch       func(skill string)
 }
 
 // NewRegistry creates a new skill registry.
@@ -221,9 +228,17 @@ func NewRegistry() *Registry {
 		parser:        NewParser(),
 		validator:     NewValidator(),
 		scorer:        NewQualityScorer(),
+		digests:       make(map[string]string),
 	}
 }
 
+// snapshot returns the current skill list. Callers must not modify it.
+func (r *Registry) snapshot() []SkillMeta {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.skills
+}
+
 // DelegationHint provides a hint for delegating work to another skill.
 type DelegationHint struct {
 	ToSkill string // Name of skill to delegate to
@@ -366,6 +381,7 @@ func (r *Registry) resolveLoadOrder(skills []SkillMeta) ([]SkillMeta, error) {
 // Use UpgradeToLevel to load Level 2 (core) or Level 3 (extended) on demand.
 func (r *Registry) Load(skillsPath string) error {
 	var collected []SkillMeta
+	digests := make(map[string]string)
 
 	err := filepath.Walk(skillsPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -390,6 +406,7 @@ func (r *Registry) Load(skillsPath string) error {
 
 		meta.QualityScore = r.scorer.Score(meta, string(content))
 		collected = append(collected, *meta)
+		digests[path] = digest(content)
 		return nil
 	})
 
@@ -402,7 +419,10 @@ func (r *Registry) Load(skillsPath string) error {
 		return fmt.Errorf("resolve load order: %w", err)
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.skills = sorted
+	r.digests = digests
 	return nil
 }
 
@@ -417,27 +437,45 @@ func (r *Registry) RegisterSkill(name, path string) error {
 		return fmt.Errorf("skill name mismatch: expected %s, got %s", name, meta.Name)
 	}
 
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for _, s := range r.skills {
 		if s.Name == name {
 			return fmt.Errorf("skill %s already registered", name)
 		}
 	}
 
-	r.skills = append(r.skills, *meta)
+	r.skills = append(slices.Clip(r.skills), *meta)
 	return nil
 }
 
 // UnregisterSkill removes a skill from the metadata list.
 func (r *Registry) UnregisterSkill(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
 	for i, s := range r.skills {
 		if s.Name == name {
-			r.skills = append(r.skills[:i], r.skills[i+1:]...)
+			r.skills = slices.Delete(slices.Clone(r.skills), i, i+1)
 			return nil
 		}
 	}
 	return fmt.Errorf("skill %s not found", name)
 }
 
+// SetMatchObserver registers fn to be called with the name of every skill
+// returned by MatchForContext or FindMatchingSkills.
+func (r *Registry) SetMatchObserver(fn func(skill string)) {
+	r.onMatch = fn
+}
+
+func (r *Registry) observeMatch(name string) {
+	if r.onMatch != nil {
+		r.onMatch(name)
+	}
+}
+
 // MatchForContext returns skill names that match the given context.
 func (r *Registry) MatchForContext(ctx domain.SkillContext) []string {
 	var matched []string
@@ -451,28 +489,35 @@ func (r *Registry) MatchForContext(ctx domain.SkillContext) []string {
 
 	// Then check metadata skills
 	terms := r.buildSearchTerms(ctx)
-	for _, skill := range r.skills {
+	for _, skill := range r.snapshot() {
 		if r.matchesContext(skill, terms) {
 			matched = append(matched, skill.Name)
 		}
 	}
 
+	for _, name := range matched {
+		r.observeMatch(name)
+	}
+
 	return matched
 }
 
 // FindMatchingSkills returns skills with match scores and reasons based on the given query and context.
 func (r *Registry) FindMatchingSkills(query string, context ...*MatchContext) []MatchResult {
 	if len(context) == 0 || context[0] == nil {
-		return r.matchByQuery(query)
+		results := r.matchByQuery(query)
+		r.observeResults(results)
+		return results
 	}
 
 	ctx := context[0]
 	var results []MatchResult
 
-	for i := range r.skills {
-		result := scoreSkill(&r.skills[i], query, ctx)
+	skills := r.snapshot()
+	for i := range skills {
+		result := scoreSkill(&skills[i], query, ctx)
 		if result.Score > 0 {
-			boost := r.applyContextBoosts(r.skills[i].Name, ctx)
+			boost := r.applyContextBoosts(skills[i].Name, ctx)
 			result.Score += boost
 			results = append(results, result)
 		}
@@ -493,15 +538,34 @@ func (r *Registry) FindMatchingSkills(query string, context ...*MatchContext) []
 		return results[i].Score > results[j].Score
 	})
 
+	r.observeResults(results)
+
 	return results
 }
 
+func (r *Registry) observeResults(results []MatchResult) {
+	if r.onMatch == nil {
+		return
+	}
+	for _, res := range results {
+		if res.Skill != nil {
+			r.observeMatch(res.Skill.Name)
+			continue
+		}
+		for _, reason := range res.MatchedBy {
+			if reason.Type == "runtime" {
+				r.observeMatch(reason.Value)
+			}
+		}
+	}
+}
+
 // matchByQuery performs query-based skill matching (backward compatible).
 func (r *Registry) matchByQuery(query string) []MatchResult {
 	var results []MatchResult
 	queryLower := strings.ToLower(query)
 
-	for _, skill := range r.skills {
+	for _, skill := range r.snapshot() {
 		if strings.Contains(strings.ToLower(skill.Name), queryLower) {
 			results = append(results, MatchResult{
 				Skill:       &skill,
@@ -647,7 +711,7 @@ func (r *Registry) affinityBoost(skillName string, ctx *MatchContext) float64 {
 
 // Get retrieves a skill by name.
 func (r *Registry) Get(name string) (*SkillMeta, error) {
-	for _, skill := range r.skills {
+	for _, skill := range r.snapshot() {
 		if skill.Name == name {
 			return &skill, nil
 		}
@@ -657,7 +721,7 @@ func (r *Registry) Get(name string) (*SkillMeta, error) {
 
 // All returns all loaded skills.
 func (r *Registry) All() []SkillMeta {
-	return r.skills
+	return r.snapshot()
 }
 
 // buildSearchTerms extracts searchable terms from SkillContext.
@@ -741,7 +805,8 @@ func (r *Registry) ValidateSkill(name string) (*ValidationResult, error) {
 
 // ValidateAll validates all loaded skills and returns aggregate result.
 func (r *Registry) ValidateAll() (*ValidationResult, error) {
-	if len(r.skills) == 0 {
+	skills := r.snapshot()
+	if len(skills) == 0 {
 		return &ValidationResult{
 			Valid:  true,
 			Issues: []ValidationIssue{},
@@ -752,7 +817,7 @@ func (r *Registry) ValidateAll() (*ValidationResult, error) {
 	var allIssues []ValidationIssue
 	totalScore := 0.0
 
-	for _, skill := range r.skills {
+	for _, skill := range skills {
 		result, err := r.ValidateSkill(skill.Name)
 		if err != nil {
 			return nil, fmt.Errorf("validate skill %s: %w", skill.Name, err)
@@ -764,7 +829,7 @@ func (r *Registry) ValidateAll() (*ValidationResult, error) {
 		}
 	}
 
-	avgScore := totalScore / float64(len(r.skills))
+	avgScore := totalScore / float64(len(skills))
 	valid := true
 	for _, issue := range allIssues {
 		if issue.Severity == SeverityError {
@@ -782,8 +847,9 @@ func (r *Registry) ValidateAll() (*ValidationResult, error) {
 
 // GetQualityReport returns a map of skill names to quality scores.
 func (r *Registry) GetQualityReport() map[string]float64 {
-	report := make(map[string]float64, len(r.skills))
-	for _, skill := range r.skills {
+	skills := r.snapshot()
+	report := make(map[string]float64, len(skills))
+	for _, skill := range skills {
 		if skill.QualityScore != nil {
 			report[skill.Name] = skill.QualityScore.Total
 		}
@@ -822,11 +888,12 @@ func (r *Registry) UpgradeSkill(name string, targetLevel LoadLevel) error {
 		return fmt.Errorf("upgrade %s: %w", name, err)
 	}
 
-	for i := range r.skills {
-		if r.skills[i].Name == name {
-			r.skills[i] = *skill
-			break
-		}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.indexOf(name); i >= 0 {
+		r.skills = slices.Clone(r.skills)
+		r.skills[i] = *skill
 	}
 
 	return nil
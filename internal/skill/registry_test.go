@@ -280,6 +280,29 @@ func TestRegistry_MatchForContext_RuntimeSkills(t *testing.T) {
 	}
 }
 
+func TestRegistry_SetMatchObserver(t *testing.T) {
+	r := NewRegistry()
+
+	require.NoError(t, r.Register(&mockSkill{
+		name:        "go-code",
+		description: "Go code",
+		canHandle: func(ctx domain.SkillContext) bool {
+			return ctx.Action == domain.SpecActionProposal
+		},
+	}))
+
+	var observed []string
+	r.SetMatchObserver(func(skill string) {
+		observed = append(observed, skill)
+	})
+
+	r.MatchForContext(domain.SkillContext{Action: domain.SpecActionProposal})
+	r.MatchForContext(domain.SkillContext{})
+	r.FindMatchingSkills("go-code")
+
+	assert.Equal(t, []string{"go-code", "go-code"}, observed)
+}
+
 func TestRegistry_MatchForContext_MetadataSkills(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -0,0 +1,206 @@
+package skill
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ReloadResult describes what Reload swapped into the registry.
+type ReloadResult struct {
+	Added   []string
+	Changed []string
+	Removed []string
+	Issues  []ReloadIssue
+}
+
+// ReloadIssue is a parse or validation problem found in a reloaded file.
+type ReloadIssue struct {
+	Path string
+	ValidationIssue
+}
+
+func (i ReloadIssue) String() string {
+	return i.Path + ": " + i.ValidationIssue.String()
+}
+
+// Empty reports whether the reload changed nothing and found no issues.
+func (r *ReloadResult) Empty() bool {
+	return len(r.Added) == 0 && len(r.Changed) == 0 && len(r.Removed) == 0 && len(r.Issues) == 0
+}
+
+// Reload re-reads the SKILL.md files under skillsPath and swaps the skills
+// loaded from there in one step. Skills registered from elsewhere, such as
+// plugin skills, are kept. A file that no longer parses keeps its previous
+// version; added and changed skills are validated and their issues reported
+// without blocking the swap, the same as Load.
+func (r *Registry) Reload(skillsPath string) (*ReloadResult, error) {
+	root := filepath.Clean(skillsPath)
+	result := &ReloadResult{}
+
+	var parsed []SkillMeta
+	contents := make(map[string]string)
+	digests := make(map[string]string)
+	failed := make(map[string]bool)
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != "SKILL.md" {
+			return nil
+		}
+
+		content, err := os.ReadFile(path) // #nosec G304 -- controlled skill file path
+		if err == nil {
+			var meta *SkillMeta
+			if meta, err = r.parser.ParseSkillFile(path); err == nil {
+				meta.QualityScore = r.scorer.Score(meta, string(content))
+				parsed = append(parsed, *meta)
+				contents[path] = string(content)
+				digests[path] = digest(content)
+				return nil
+			}
+		}
+
+		failed[path] = true
+		result.Issues = append(result.Issues, ReloadIssue{Path: path, ValidationIssue: ValidationIssue{
+			Rule:     "parse",
+			Severity: SeverityError,
+			Message:  err.Error(),
+		}})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk %s: %w", root, err)
+	}
+
+	var validate []SkillMeta
+	if err := func() error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		current := make(map[string]SkillMeta, len(r.skills))
+		var next []SkillMeta
+		for _, s := range r.skills {
+			current[s.Name] = s
+			switch {
+			case !within(root, s.FilePath):
+				next = append(next, s)
+			case failed[s.FilePath]:
+				next = append(next, s)
+				digests[s.FilePath] = r.digests[s.FilePath]
+			}
+		}
+
+		seen := make(map[string]bool, len(parsed))
+		var added, changed []string
+		for _, meta := range parsed {
+			seen[meta.Name] = true
+			old, exists := current[meta.Name]
+			switch {
+			case !exists:
+				added = append(added, meta.Name)
+			case !within(root, old.FilePath):
+				result.Issues = append(result.Issues, ReloadIssue{Path: meta.FilePath, ValidationIssue: ValidationIssue{
+					Rule:     "duplicate",
+					Severity: SeverityError,
+					Message:  fmt.Sprintf("skill %s already registered from %s", meta.Name, old.FilePath),
+				}})
+				continue
+			case old.FilePath == meta.FilePath && r.digests[meta.FilePath] == digests[meta.FilePath]:
+				// Unchanged: keep the old entry so upgraded load levels survive.
+				next = append(next, old)
+				continue
+			default:
+				changed = append(changed, meta.Name)
+			}
+			next = append(next, meta)
+			validate = append(validate, meta)
+		}
+
+		var removed []string
+		for _, s := range r.skills {
+			if within(root, s.FilePath) && !failed[s.FilePath] && !seen[s.Name] {
+				removed = append(removed, s.Name)
+			}
+		}
+
+		sorted, err := r.resolveLoadOrder(next)
+		if err != nil {
+			return fmt.Errorf("resolve load order: %w", err)
+		}
+
+		r.skills = sorted
+		for path := range r.digests {
+			if !within(root, path) {
+				digests[path] = r.digests[path]
+			}
+		}
+		r.digests = digests
+		result.Added, result.Changed, result.Removed = added, changed, removed
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+
+	for i := range validate {
+		meta := &validate[i]
+		res := r.validator.ValidateWithContext(meta, contents[meta.FilePath], r)
+		for _, issue := range res.Issues {
+			if issue.Severity == SeverityInfo {
+				continue
+			}
+			result.Issues = append(result.Issues, ReloadIssue{Path: meta.FilePath, ValidationIssue: issue})
+		}
+	}
+
+	slices.Sort(result.Added)
+	slices.Sort(result.Changed)
+	slices.Sort(result.Removed)
+	return result, nil
+}
+
+// ReplaceSkill re-reads the skill registered as name from path and swaps it
+// in place, or registers it if it is not loaded yet.
+func (r *Registry) ReplaceSkill(name, path string) error {
+	meta, err := r.parser.ParseSkillFile(path)
+	if err != nil {
+		return fmt.Errorf("parse skill file: %w", err)
+	}
+
+	if meta.Name != name {
+		return fmt.Errorf("skill name mismatch: expected %s, got %s", name, meta.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	skills := slices.Clone(r.skills)
+	if i := r.indexOf(name); i >= 0 {
+		skills[i] = *meta
+	} else {
+		skills = append(skills, *meta)
+	}
+	r.skills = skills
+	return nil
+}
+
+// indexOf returns the position of name in r.skills, or -1. Callers hold mu.
+func (r *Registry) indexOf(name string) int {
+	return slices.IndexFunc(r.skills, func(s SkillMeta) bool { return s.Name == name })
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,122 @@
+package skill
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeReloadSkill(t *testing.T, dir, name, description string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name, "SKILL.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte("---\nname: "+name+"\ndescription: \""+description+"\"\n---\n\n# "+name+"\nContent here.\n"), 0600))
+	return path
+}
+
+func TestRegistry_Reload(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeReloadSkill(t, dir, "keep", "Unchanged skill. Auto-activates for: keep.")
+	writeReloadSkill(t, dir, "edit", "Old description. Auto-activates for: old.")
+	gone := writeReloadSkill(t, dir, "gone", "Removed skill. Auto-activates for: gone.")
+
+	r := NewRegistry()
+	require.NoError(t, r.Load(dir))
+
+	pluginSkill := writeReloadSkill(t, t.TempDir(), "from-plugin", "Plugin skill. Auto-activates for: plugin.")
+	require.NoError(t, r.RegisterSkill("from-plugin", pluginSkill))
+
+	writeReloadSkill(t, dir, "edit", "New description. Auto-activates for: new.")
+	writeReloadSkill(t, dir, "fresh", "Added skill. Auto-activates for: fresh.")
+	require.NoError(t, os.RemoveAll(filepath.Dir(gone)))
+
+	res, err := r.Reload(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fresh"}, res.Added)
+	assert.Equal(t, []string{"edit"}, res.Changed)
+	assert.Equal(t, []string{"gone"}, res.Removed)
+
+	edit, err := r.Get("edit")
+	require.NoError(t, err)
+	assert.Contains(t, edit.Description, "New description")
+
+	_, err = r.Get("gone")
+	assert.Error(t, err)
+	_, err = r.Get("from-plugin")
+	assert.NoError(t, err, "skills registered from outside the directory survive a reload")
+
+	res, err = r.Reload(dir)
+	require.NoError(t, err)
+	assert.Empty(t, res.Added)
+	assert.Empty(t, res.Changed)
+	assert.Empty(t, res.Removed)
+}
+
+func TestRegistry_Reload_ParseErrorKeepsOldVersion(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeReloadSkill(t, dir, "broken", "Working skill. Auto-activates for: work.")
+
+	r := NewRegistry()
+	require.NoError(t, r.Load(dir))
+
+	require.NoError(t, os.WriteFile(path, []byte("no frontmatter here\n"), 0600))
+
+	res, err := r.Reload(dir)
+	require.NoError(t, err)
+	assert.Empty(t, res.Removed)
+	require.NotEmpty(t, res.Issues)
+	assert.Equal(t, path, res.Issues[0].Path)
+	assert.Equal(t, SeverityError, res.Issues[0].Severity)
+
+	meta, err := r.Get("broken")
+	require.NoError(t, err)
+	assert.Contains(t, meta.Description, "Working skill")
+}
+
+func TestRegistry_Reload_MissingDependencyRejected(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeReloadSkill(t, dir, "base", "Base skill. Auto-activates for: base.")
+
+	r := NewRegistry()
+	require.NoError(t, r.Load(dir))
+
+	path := filepath.Join(dir, "child", "SKILL.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+	require.NoError(t, os.WriteFile(path, []byte("---\nname: child\ndescription: \"Child skill.\"\ndepends_on: [missing]\n---\n\n<role>Child</role>\n"), 0600))
+
+	_, err := r.Reload(dir)
+	assert.ErrorContains(t, err, "dependency not found")
+	assert.Len(t, r.All(), 1, "a failed reload swaps nothing")
+}
+
+func TestRegistry_ReplaceSkill(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := writeReloadSkill(t, dir, "swap", "First version.")
+
+	r := NewRegistry()
+	require.NoError(t, r.RegisterSkill("swap", path))
+
+	writeReloadSkill(t, dir, "swap", "Second version.")
+	require.NoError(t, r.ReplaceSkill("swap", path))
+
+	meta, err := r.Get("swap")
+	require.NoError(t, err)
+	assert.Equal(t, "Second version.", meta.Description)
+	assert.Len(t, r.All(), 1)
+
+	assert.ErrorContains(t, r.ReplaceSkill("other", path), "skill name mismatch")
+}
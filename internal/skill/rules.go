@@ -1,9 +1,12 @@
 package skill
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/victorzhuk/go-ent/internal/lint"
 )
 
 var semverRegex = regexp.MustCompile(`^v?\d+\.\d+\.\d+$`)
@@ -787,3 +790,20 @@ func checkRedundancy(ctx *ValidationContext, registry *Registry) []ValidationIss
 
 	return nil
 }
+
+// checkPluginRules runs the skill rules of enabled plugins.
+func checkPluginRules(ctx *ValidationContext) []ValidationIssue {
+	findings := lint.Default().Check(context.Background(), lint.NewFile(lint.TargetSkill, ctx.FilePath, ctx.Content))
+
+	issues := make([]ValidationIssue, 0, len(findings))
+	for _, f := range findings {
+		issues = append(issues, ValidationIssue{
+			Plugin:   f.Plugin,
+			Rule:     f.Rule,
+			Severity: Severity(f.Severity),
+			Message:  f.Message,
+			Line:     f.Line,
+		})
+	}
+	return issues
+}
@@ -17,6 +17,7 @@ const (
 
 // ValidationIssue represents a validation error or warning.
 type ValidationIssue struct {
+	Plugin     string
 	Rule       string
 	Severity   Severity
 	Message    string
@@ -28,8 +29,11 @@ type ValidationIssue struct {
 
 func (v ValidationIssue) String() string {
 	loc := v.Rule
+	if v.Plugin != "" {
+		loc = v.Plugin + "/" + v.Rule
+	}
 	if v.Line > 0 {
-		loc = fmt.Sprintf("%s:%d", v.Rule, v.Line)
+		loc = fmt.Sprintf("%s:%d", loc, v.Line)
 	}
 	result := fmt.Sprintf("[%s] %s: %s", v.Severity, loc, v.Message)
 	if v.Suggestion != "" {
@@ -108,6 +112,7 @@ func NewValidator() *Validator {
 			checkTriggerExplicit,
 			checkExampleDiversity,
 			checkInstructionConcise,
+			checkPluginRules,
 		},
 		rulesWithContext: []ValidationRuleWithContext{
 			checkRedundancy,
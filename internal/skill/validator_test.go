@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/victorzhuk/go-ent/internal/lint"
 )
 
 func TestValidateFrontmatter(t *testing.T) {
@@ -2421,3 +2422,42 @@ func TestValidationRulesNoFalsePositives(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_PluginRules(t *testing.T) {
+	dir := t.TempDir()
+	rulePath := dir + "/rule.yaml"
+	err := os.WriteFile(rulePath, []byte(`name: no-lorem
+target: skill
+trigger:
+  pattern: plugin-rules-SKILL.md
+conditions:
+  - type: content_matches
+    pattern: (?i)lorem ipsum
+actions:
+  - type: warn
+    message: replace placeholder text
+`), 0600)
+	assert.NoError(t, err)
+
+	rule, err := lint.LoadRule(rulePath)
+	assert.NoError(t, err)
+	lint.Default().Add("skill-test-plugin", rule)
+	t.Cleanup(func() { lint.Default().RemovePlugin("skill-test-plugin") })
+
+	meta := &SkillMeta{Name: "demo", FilePath: dir + "/plugin-rules-SKILL.md"}
+	result := NewValidator().Validate(meta, "---\nname: demo\n---\nLorem ipsum dolor\n")
+
+	var found []ValidationIssue
+	for _, issue := range result.Issues {
+		if issue.Plugin != "" {
+			found = append(found, issue)
+		}
+	}
+	if assert.Len(t, found, 1) {
+		assert.Equal(t, "skill-test-plugin", found[0].Plugin)
+		assert.Equal(t, "no-lorem", found[0].Rule)
+		assert.Equal(t, SeverityWarning, found[0].Severity)
+		assert.Equal(t, 4, found[0].Line)
+		assert.True(t, strings.HasPrefix(found[0].String(), "[warning] skill-test-plugin/no-lorem:4: replace placeholder text"))
+	}
+}
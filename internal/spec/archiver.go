@@ -1,6 +1,7 @@
 package spec
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -27,8 +28,12 @@ func NewArchiver(store *Store) *Archiver {
 	return &Archiver{store: store}
 }
 
-// Archive archives a change and optionally merges deltas into specs.
-func (a *Archiver) Archive(changeID string, skipSpecs bool, dryRun bool) (*ArchiveResult, error) {
+// Archive archives a change and optionally merges deltas into specs. It
+// fails with ErrBranchNotMerged while the change's git branch has commits
+// its base branch lacks, unless allowUnmerged is set. The archived change
+// keeps an ArchiveManifestFile with a patch per updated spec, which
+// Unarchive reverts.
+func (a *Archiver) Archive(changeID string, skipSpecs bool, dryRun bool, allowUnmerged bool) (*ArchiveResult, error) {
 	result := &ArchiveResult{
 		ChangeID: changeID,
 		DryRun:   dryRun,
@@ -41,20 +46,33 @@ func (a *Archiver) Archive(changeID string, skipSpecs bool, dryRun bool) (*Archi
 		return nil, fmt.Errorf("change not found: %s", changeID)
 	}
 
+	// Refuse to archive work that has not reached the base branch
+	if !allowUnmerged {
+		if err := NewGit(a.store.RootPath()).CheckMerged(changeID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Generate archive path with date prefix
-	today := time.Now().Format("2006-01-02")
+	now := time.Now()
+	today := now.Format("2006-01-02")
 	archiveName := fmt.Sprintf("%s-%s", today, changeID)
 	archivePath := filepath.Join(a.store.SpecPath(), "changes", "archive", archiveName)
 	result.ArchivePath = archivePath
 
+	manifest := ArchiveManifest{ChangeID: changeID, ArchivedAt: now, SkipSpecs: skipSpecs}
+
 	// Merge deltas into specs if not skipped
 	if !skipSpecs {
-		updatedSpecs, err := a.mergeDeltas(changePath, dryRun)
+		patches, err := a.mergeDeltas(changePath, dryRun)
 		if err != nil {
 			result.Errors = append(result.Errors, fmt.Sprintf("merge deltas: %v", err))
 			return result, nil
 		}
-		result.UpdatedSpecs = updatedSpecs
+		manifest.Patches = patches
+		for _, p := range patches {
+			result.UpdatedSpecs = append(result.UpdatedSpecs, p.Spec)
+		}
 	}
 
 	// Move change to archive
@@ -68,14 +86,23 @@ func (a *Archiver) Archive(changeID string, skipSpecs bool, dryRun bool) (*Archi
 		if err := os.Rename(changePath, archivePath); err != nil {
 			return nil, fmt.Errorf("move to archive: %w", err)
 		}
+
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal archive manifest: %w", err)
+		}
+		if err := os.WriteFile(filepath.Join(archivePath, ArchiveManifestFile), data, 0600); err != nil {
+			return nil, fmt.Errorf("write archive manifest: %w", err)
+		}
 	}
 
 	return result, nil
 }
 
-// mergeDeltas merges all delta specs from change into main specs.
-func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]string, error) {
-	var updatedSpecs []string
+// mergeDeltas merges all delta specs from change into main specs and
+// returns what it did to each.
+func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]SpecPatch, error) {
+	var patches []SpecPatch
 
 	deltaSpecsPath := filepath.Join(changePath, "specs")
 
@@ -95,14 +122,9 @@ func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]string, error)
 		}
 
 		// Get capability name from path
-		relPath, err := filepath.Rel(deltaSpecsPath, path)
+		capabilityDir, err := deltaCapability(deltaSpecsPath, path)
 		if err != nil {
-			return fmt.Errorf("get relative path: %w", err)
-		}
-
-		capabilityDir := filepath.Dir(relPath)
-		if capabilityDir == "." {
-			capabilityDir = strings.TrimSuffix(filepath.Base(path), ".md")
+			return err
 		}
 
 		// Read delta spec
@@ -121,7 +143,8 @@ func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]string, error)
 		baseSpecPath := filepath.Join(a.store.SpecPath(), "specs", capabilityDir, "spec.md")
 		var baseContent string
 
-		if _, err := os.Stat(baseSpecPath); os.IsNotExist(err) {
+		_, statErr := os.Stat(baseSpecPath)
+		if os.IsNotExist(statErr) {
 			baseContent = fmt.Sprintf("# %s Specification\n\n", capitalizeFirst(capabilityDir))
 		} else {
 			content, err := os.ReadFile(baseSpecPath) // #nosec G304 -- controlled file path
@@ -147,7 +170,13 @@ func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]string, error)
 			}
 		}
 
-		updatedSpecs = append(updatedSpecs, capabilityDir)
+		patches = append(patches, SpecPatch{
+			Spec:         capabilityDir,
+			Created:      os.IsNotExist(statErr),
+			Before:       baseContent,
+			After:        merged,
+			Requirements: requirementPatches(baseContent, merged, delta),
+		})
 		return nil
 	})
 
@@ -155,7 +184,7 @@ func (a *Archiver) mergeDeltas(changePath string, dryRun bool) ([]string, error)
 		return nil, err
 	}
 
-	return updatedSpecs, nil
+	return patches, nil
 }
 
 func capitalizeFirst(s string) string {
@@ -169,9 +198,40 @@ func capitalizeFirst(s string) string {
 	return string(r)
 }
 
-// ValidateBeforeArchive validates a change before archiving.
+// ValidateBeforeArchive validates a change before archiving. Deltas that
+// conflict with other pending changes are errors in strict mode and
+// warnings otherwise.
 func (a *Archiver) ValidateBeforeArchive(changeID string, strict bool) (*ValidationResult, error) {
 	changePath := filepath.Join(a.store.SpecPath(), "changes", changeID)
 	validator := NewValidator()
-	return validator.ValidateChange(changePath, strict)
+	result, err := validator.ValidateChange(changePath, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := DetectConflicts(a.store)
+	if err != nil {
+		return nil, fmt.Errorf("detect conflicts: %w", err)
+	}
+
+	severity := SeverityWarning
+	if strict {
+		severity = SeverityError
+	}
+	for _, reason := range conflicts.Blockers(changeID) {
+		result.Issues = append(result.Issues, ValidationIssue{
+			Severity: severity,
+			File:     changePath,
+			Message:  reason,
+			RuleID:   "delta-conflict",
+		})
+	}
+
+	if strict {
+		result.Valid = len(result.Issues) == 0
+	} else {
+		result.Valid = result.ErrorCount() == 0
+	}
+	result.Summary = validator.buildSummary(result)
+	return result, nil
 }
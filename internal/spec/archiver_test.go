@@ -38,7 +38,7 @@ func TestArchive(t *testing.T) {
 	archiver := NewArchiver(store)
 
 	t.Run("archive with skipSpecs", func(t *testing.T) {
-		result, err := archiver.Archive(changeID, true, false)
+		result, err := archiver.Archive(changeID, true, false, false)
 		require.NoError(t, err)
 		assert.Equal(t, changeID, result.ChangeID)
 		assert.False(t, result.DryRun)
@@ -78,7 +78,7 @@ func TestArchive_DryRun(t *testing.T) {
 	store := NewStore(tmpDir)
 	archiver := NewArchiver(store)
 
-	result, err := archiver.Archive(changeID, true, true)
+	result, err := archiver.Archive(changeID, true, true, false)
 	require.NoError(t, err)
 	assert.True(t, result.DryRun)
 
@@ -100,7 +100,7 @@ func TestArchive_NonexistentChange(t *testing.T) {
 	store := NewStore(tmpDir)
 	archiver := NewArchiver(store)
 
-	_, err := archiver.Archive("nonexistent", false, false)
+	_, err := archiver.Archive("nonexistent", false, false, false)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "change not found")
 }
@@ -151,7 +151,7 @@ func TestMergeDeltas(t *testing.T) {
 	store := NewStore(tmpDir)
 	archiver := NewArchiver(store)
 
-	result, err := archiver.Archive(changeID, false, false)
+	result, err := archiver.Archive(changeID, false, false, false)
 	require.NoError(t, err)
 	assert.Contains(t, result.UpdatedSpecs, "auth")
 
@@ -198,7 +198,7 @@ func TestMergeDeltas_NewSpec(t *testing.T) {
 	store := NewStore(tmpDir)
 	archiver := NewArchiver(store)
 
-	result, err := archiver.Archive(changeID, false, false)
+	result, err := archiver.Archive(changeID, false, false, false)
 	require.NoError(t, err)
 	assert.Contains(t, result.UpdatedSpecs, "notifications")
 
@@ -287,7 +287,7 @@ func TestMergeDeltas_DryRun(t *testing.T) {
 	store := NewStore(tmpDir)
 	archiver := NewArchiver(store)
 
-	result, err := archiver.Archive(changeID, false, true)
+	result, err := archiver.Archive(changeID, false, true, false)
 	require.NoError(t, err)
 	assert.True(t, result.DryRun)
 	assert.Contains(t, result.UpdatedSpecs, "auth")
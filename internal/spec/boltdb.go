@@ -19,6 +19,7 @@ const (
 	BucketDeps     = "deps"
 	BucketBlocking = "blocking"
 	BucketMeta     = "meta"
+	BucketSync     = "sync"
 )
 
 type BoltStore struct {
@@ -59,7 +60,7 @@ func (s *BoltStore) Close() error {
 
 func (s *BoltStore) initBuckets() error {
 	return s.db.Update(func(tx *bbolt.Tx) error {
-		buckets := []string{BucketTasks, BucketChanges, BucketDeps, BucketBlocking, BucketMeta}
+		buckets := []string{BucketTasks, BucketChanges, BucketDeps, BucketBlocking, BucketMeta, BucketSync}
 		for _, bucket := range buckets {
 			if _, err := tx.CreateBucketIfNotExists([]byte(bucket)); err != nil {
 				return fmt.Errorf("create bucket %s: %w", bucket, err)
@@ -207,6 +208,18 @@ func (s *BoltStore) RemoveDependency(from, to TaskID) error {
 	})
 }
 
+// GetDependencies returns the tasks id depends on as recorded in the deps
+// bucket, including those added with AddDependency after the last sync.
+func (s *BoltStore) GetDependencies(id TaskID) ([]TaskID, error) {
+	var deps []TaskID
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		deps, err = s.getDeps(tx, id)
+		return err
+	})
+	return deps, err
+}
+
 func (s *BoltStore) GetBlockers(id TaskID) ([]TaskID, error) {
 	var blockers []TaskID
 	err := s.db.View(func(tx *bbolt.Tx) error {
@@ -387,6 +400,48 @@ func (s *BoltStore) ClearTasks() error {
 	})
 }
 
+// ListSyncBases returns the state of every task line as of the last sync,
+// keyed by task ID.
+func (s *BoltStore) ListSyncBases() (map[string]SyncBase, error) {
+	bases := make(map[string]SyncBase)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		b := tx.Bucket([]byte(BucketSync))
+		return b.ForEach(func(k, v []byte) error {
+			var base SyncBase
+			if err := json.Unmarshal(v, &base); err != nil {
+				return err
+			}
+			bases[string(k)] = base
+			return nil
+		})
+	})
+	return bases, err
+}
+
+// ReplaceSyncBases stores bases as the new last-synced state, dropping those
+// of tasks that no longer exist.
+func (s *BoltStore) ReplaceSyncBases(bases map[string]SyncBase) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.DeleteBucket([]byte(BucketSync)); err != nil && !errors.Is(err, bboltErrors.ErrBucketNotFound) {
+			return fmt.Errorf("delete sync bucket: %w", err)
+		}
+		b, err := tx.CreateBucket([]byte(BucketSync))
+		if err != nil {
+			return fmt.Errorf("create sync bucket: %w", err)
+		}
+		for id, base := range bases {
+			data, err := json.Marshal(base)
+			if err != nil {
+				return fmt.Errorf("marshal sync base: %w", err)
+			}
+			if err := b.Put([]byte(id), data); err != nil {
+				return fmt.Errorf("put sync base: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
 // SetMeta sets a metadata value (generic version of SetSyncedAt)
 func (s *BoltStore) SetMeta(key, value string) error {
 	return s.db.Update(func(tx *bbolt.Tx) error {
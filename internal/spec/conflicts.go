@@ -0,0 +1,317 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ConflictKind classifies how several changes overlap on one requirement.
+type ConflictKind string
+
+const (
+	ConflictModifyModify ConflictKind = "modify/modify"
+	ConflictModifyRemove ConflictKind = "modify/remove"
+	ConflictRemoveRemove ConflictKind = "remove/remove"
+	ConflictAddAdd       ConflictKind = "add/add"
+	ConflictRenameChain  ConflictKind = "rename-chain"
+)
+
+// DeltaTouch is one delta operation of a change on a requirement.
+type DeltaTouch struct {
+	ChangeID string
+	Op       string
+	File     string
+	Line     int
+	// Rename holds "from → to" for RENAMED operations.
+	Rename string
+}
+
+// Conflict is a requirement touched by deltas of more than one change.
+type Conflict struct {
+	Capability  string
+	Requirement string
+	Kind        ConflictKind
+	Changes     []string
+	Touches     []DeltaTouch
+	// Resolved is set when every pair of changes is ordered by a Requires
+	// entry in proposal.md, meaning the later change is rebased on the earlier.
+	Resolved bool
+}
+
+// ConflictReport lists the conflicts between all pending changes.
+type ConflictReport struct {
+	Changes   []string
+	Conflicts []Conflict
+	// requires maps a change to the changes its proposal lists as Requires.
+	requires map[string][]string
+}
+
+// ForChange returns the conflicts that involve the change.
+func (r *ConflictReport) ForChange(changeID string) []Conflict {
+	var out []Conflict
+	for _, c := range r.Conflicts {
+		if slices.Contains(c.Changes, changeID) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Unresolved returns the number of conflicts not ordered by Requires.
+func (r *ConflictReport) Unresolved() int {
+	n := 0
+	for _, c := range r.Conflicts {
+		if !c.Resolved {
+			n++
+		}
+	}
+	return n
+}
+
+// Blockers explains why the change cannot be archived yet: conflicts with
+// changes it is not ordered against, and changes it is rebased on that are
+// still pending.
+func (r *ConflictReport) Blockers(changeID string) []string {
+	var reasons []string
+	for _, c := range r.ForChange(changeID) {
+		for _, other := range c.Changes {
+			if other == changeID {
+				continue
+			}
+			where := fmt.Sprintf("requirement %q in %s", c.Requirement, c.Capability)
+			switch {
+			case r.dependsOn(changeID, other):
+				reasons = append(reasons, fmt.Sprintf("%s conflicts with %s (%s); archive %s first", where, other, c.Kind, other))
+			case r.dependsOn(other, changeID):
+				// other is rebased on this change, so this one goes first.
+			default:
+				reasons = append(reasons, fmt.Sprintf("%s conflicts with %s (%s); rebase one change on the other and list it under Requires in proposal.md", where, other, c.Kind))
+			}
+		}
+	}
+	return reasons
+}
+
+func (r *ConflictReport) dependsOn(changeID, other string) bool {
+	return slices.Contains(r.requires[changeID], other)
+}
+
+// DetectConflicts scans the delta specs of every pending change and reports
+// requirements touched by more than one of them.
+func DetectConflicts(store *Store) (*ConflictReport, error) {
+	changes, err := store.ListChanges("")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ConflictReport{requires: make(map[string][]string)}
+	touches := make(map[[2]string][]DeltaTouch)
+
+	for _, change := range changes {
+		report.Changes = append(report.Changes, change.ID)
+		changePath := filepath.Join(store.SpecPath(), "changes", change.ID)
+
+		report.requires[change.ID] = proposalRequires(filepath.Join(changePath, "proposal.md"))
+
+		if err := collectTouches(change.ID, changePath, touches); err != nil {
+			return nil, fmt.Errorf("change %s: %w", change.ID, err)
+		}
+	}
+
+	for key, ts := range touches {
+		ids := changeIDs(ts)
+		if len(ids) < 2 {
+			continue
+		}
+		report.Conflicts = append(report.Conflicts, Conflict{
+			Capability:  key[0],
+			Requirement: key[1],
+			Kind:        classifyConflict(ts),
+			Changes:     ids,
+			Touches:     ts,
+			Resolved:    report.ordered(ids),
+		})
+	}
+
+	sort.Slice(report.Conflicts, func(i, j int) bool {
+		a, b := report.Conflicts[i], report.Conflicts[j]
+		if a.Capability != b.Capability {
+			return a.Capability < b.Capability
+		}
+		return a.Requirement < b.Requirement
+	})
+	return report, nil
+}
+
+// ordered reports whether every pair of changes is linked by Requires.
+func (r *ConflictReport) ordered(ids []string) bool {
+	for i, a := range ids {
+		for _, b := range ids[i+1:] {
+			if !r.dependsOn(a, b) && !r.dependsOn(b, a) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// collectTouches records the requirements each delta file of a change touches.
+// A rename touches both names so chains across changes meet on a key.
+func collectTouches(changeID, changePath string, touches map[[2]string][]DeltaTouch) error {
+	deltaSpecsPath := filepath.Join(changePath, "specs")
+	if _, err := os.Stat(deltaSpecsPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.Walk(deltaSpecsPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+
+		capability, err := deltaCapability(deltaSpecsPath, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path) // #nosec G304 -- controlled file path
+		if err != nil {
+			return fmt.Errorf("read delta spec: %w", err)
+		}
+
+		add := func(name string, t DeltaTouch) {
+			key := [2]string{capability, name}
+			touches[key] = append(touches[key], t)
+		}
+
+		doc := ParseDocument(string(content))
+		for _, section := range doc.Sections {
+			switch section.Op {
+			case "ADDED", "MODIFIED", "REMOVED":
+				for _, req := range section.Requirements {
+					add(req.Name, DeltaTouch{ChangeID: changeID, Op: section.Op, File: path, Line: req.Line})
+				}
+			}
+		}
+
+		delta, err := ParseDeltaSpec(string(content))
+		if err != nil {
+			return fmt.Errorf("parse delta spec: %w", err)
+		}
+		for _, rn := range delta.Renamed {
+			t := DeltaTouch{ChangeID: changeID, Op: "RENAMED", File: path, Rename: rn.FromName + " → " + rn.ToName}
+			add(rn.FromName, t)
+			add(rn.ToName, t)
+		}
+		return nil
+	})
+}
+
+var requiresRe = regexp.MustCompile(`(?i)^\s*[-*]\s+(?:\*\*)?requires(?:\*\*)?:?(?:\*\*)?\s*(.*)$`)
+var backtickRe = regexp.MustCompile("`([^`]+)`")
+
+// proposalRequires returns the change IDs a proposal lists on "Requires:"
+// lines, written in backticks.
+func proposalRequires(path string) []string {
+	data, err := os.ReadFile(path) // #nosec G304 -- controlled file path
+	if err != nil {
+		return nil
+	}
+
+	var ids []string
+	for _, line := range strings.Split(string(data), "\n") {
+		m := requiresRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		for _, id := range backtickRe.FindAllStringSubmatch(m[1], -1) {
+			ids = append(ids, id[1])
+		}
+	}
+	return ids
+}
+
+func changeIDs(ts []DeltaTouch) []string {
+	var ids []string
+	for _, t := range ts {
+		if !slices.Contains(ids, t.ChangeID) {
+			ids = append(ids, t.ChangeID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// classifyConflict names the overlap. Renames win because a rename makes
+// every other delta on the old or new name ambiguous.
+func classifyConflict(ts []DeltaTouch) ConflictKind {
+	ops := make(map[string]int)
+	for _, t := range ts {
+		ops[t.Op]++
+	}
+
+	switch {
+	case ops["RENAMED"] > 0:
+		return ConflictRenameChain
+	case ops["MODIFIED"] > 0 && ops["REMOVED"] > 0:
+		return ConflictModifyRemove
+	case ops["MODIFIED"] > 1:
+		return ConflictModifyModify
+	case ops["REMOVED"] > 1:
+		return ConflictRemoveRemove
+	case ops["ADDED"] > 1:
+		return ConflictAddAdd
+	}
+
+	// Mixed add and modify or remove: name the pair in a stable order.
+	var names []string
+	for _, op := range []struct{ op, verb string }{{"MODIFIED", "modify"}, {"REMOVED", "remove"}, {"ADDED", "add"}} {
+		if ops[op.op] > 0 {
+			names = append(names, op.verb)
+		}
+	}
+	return ConflictKind(strings.Join(names, "/"))
+}
+
+// deltaCapability returns the capability a delta spec file applies to: its
+// directory under specs/, or the file name for files directly in specs/.
+func deltaCapability(deltaSpecsPath, path string) (string, error) {
+	relPath, err := filepath.Rel(deltaSpecsPath, path)
+	if err != nil {
+		return "", fmt.Errorf("get relative path: %w", err)
+	}
+
+	capability := filepath.Dir(relPath)
+	if capability == "." {
+		capability = strings.TrimSuffix(filepath.Base(path), ".md")
+	}
+	return filepath.ToSlash(capability), nil
+}
+
+// String prints the conflict as one line, e.g.
+// `auth: "Login" modify/remove between add-sso, drop-login`.
+func (c Conflict) String() string {
+	state := "unresolved"
+	if c.Resolved {
+		state = "ordered by Requires"
+	}
+	return fmt.Sprintf("%s: %q %s between %s (%s)", c.Capability, c.Requirement, c.Kind, strings.Join(c.Changes, ", "), state)
+}
+
+// String prints where the touch happened.
+func (t DeltaTouch) String() string {
+	op := t.Op
+	if t.Rename != "" {
+		op += " " + t.Rename
+	}
+	if t.Line > 0 {
+		return fmt.Sprintf("%s %s (%s:%d)", t.ChangeID, op, t.File, t.Line)
+	}
+	return fmt.Sprintf("%s %s (%s)", t.ChangeID, op, t.File)
+}
@@ -0,0 +1,157 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeChange creates a pending change with a proposal and one delta spec
+// for the auth capability.
+func writeChange(t *testing.T, root, id, proposal, delta string) {
+	t.Helper()
+
+	changePath := filepath.Join(root, "openspec", "changes", id)
+	require.NoError(t, os.MkdirAll(filepath.Join(changePath, "specs", "auth"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(changePath, "proposal.md"), []byte(proposal), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(changePath, "tasks.md"), []byte("# Tasks"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(changePath, "specs", "auth", "spec.md"), []byte(delta), 0600))
+}
+
+const modifyLoginDelta = `## MODIFIED Requirements
+
+### Requirement: Login
+
+The system SHALL accept passwords and passkeys.
+
+#### Scenario: Passkey
+- WHEN a user presents a passkey
+- THEN the session starts
+`
+
+const removeLoginDelta = `## REMOVED Requirements
+
+### Requirement: Login
+**Reason**: replaced by SSO
+`
+
+func TestDetectConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		deltaA   string
+		deltaB   string
+		wantKind ConflictKind
+		wantReq  string
+	}{
+		{
+			name:     "modify/modify",
+			deltaA:   modifyLoginDelta,
+			deltaB:   modifyLoginDelta,
+			wantKind: ConflictModifyModify,
+			wantReq:  "Login",
+		},
+		{
+			name:     "modify/remove",
+			deltaA:   modifyLoginDelta,
+			deltaB:   removeLoginDelta,
+			wantKind: ConflictModifyRemove,
+			wantReq:  "Login",
+		},
+		{
+			name:     "rename chain",
+			deltaA:   "## RENAMED Requirements\n\n- FROM: `### Requirement: Login`\n- TO: `### Requirement: Sign in`\n",
+			deltaB:   strings.ReplaceAll(modifyLoginDelta, "Login", "Sign in"),
+			wantKind: ConflictRenameChain,
+			wantReq:  "Sign in",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			root := t.TempDir()
+			writeChange(t, root, "change-a", "# A", tt.deltaA)
+			writeChange(t, root, "change-b", "# B", tt.deltaB)
+
+			report, err := DetectConflicts(NewStore(root))
+			require.NoError(t, err)
+
+			assert.Equal(t, []string{"change-a", "change-b"}, report.Changes)
+			require.Len(t, report.Conflicts, 1)
+			c := report.Conflicts[0]
+			assert.Equal(t, "auth", c.Capability)
+			assert.Equal(t, tt.wantReq, c.Requirement)
+			assert.Equal(t, tt.wantKind, c.Kind)
+			assert.Equal(t, []string{"change-a", "change-b"}, c.Changes)
+			assert.False(t, c.Resolved)
+			assert.Equal(t, 1, report.Unresolved())
+			assert.Len(t, report.Blockers("change-a"), 1)
+		})
+	}
+}
+
+func TestDetectConflicts_NoOverlap(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeChange(t, root, "change-a", "# A", modifyLoginDelta)
+	writeChange(t, root, "change-b", "# B", strings.ReplaceAll(modifyLoginDelta, "Login", "Logout"))
+
+	report, err := DetectConflicts(NewStore(root))
+	require.NoError(t, err)
+	assert.Empty(t, report.Conflicts)
+	assert.Empty(t, report.Blockers("change-a"))
+}
+
+func TestDetectConflicts_Requires(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeChange(t, root, "change-a", "# A", modifyLoginDelta)
+	writeChange(t, root, "change-b", "# B\n\n- Requires: `change-a`\n", removeLoginDelta)
+
+	report, err := DetectConflicts(NewStore(root))
+	require.NoError(t, err)
+
+	require.Len(t, report.Conflicts, 1)
+	assert.True(t, report.Conflicts[0].Resolved)
+	assert.Zero(t, report.Unresolved())
+
+	assert.Empty(t, report.Blockers("change-a"), "the required change is archived first")
+	blockers := report.Blockers("change-b")
+	require.Len(t, blockers, 1)
+	assert.Contains(t, blockers[0], "archive change-a first")
+}
+
+func TestValidateBeforeArchive_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeChange(t, root, "change-a", "# A", modifyLoginDelta)
+	writeChange(t, root, "change-b", "# B", modifyLoginDelta)
+
+	archiver := NewArchiver(NewStore(root))
+
+	strict, err := archiver.ValidateBeforeArchive("change-a", true)
+	require.NoError(t, err)
+	assert.False(t, strict.Valid)
+	require.Len(t, strict.Issues, 1)
+	assert.Equal(t, SeverityError, strict.Issues[0].Severity)
+	assert.Equal(t, "delta-conflict", strict.Issues[0].RuleID)
+	assert.Contains(t, strict.Issues[0].Message, "rebase one change on the other")
+
+	lenient, err := archiver.ValidateBeforeArchive("change-a", false)
+	require.NoError(t, err)
+	assert.True(t, lenient.Valid)
+	require.Len(t, lenient.Issues, 1)
+	assert.Equal(t, SeverityWarning, lenient.Issues[0].Severity)
+}
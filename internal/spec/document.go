@@ -0,0 +1,355 @@
+package spec
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Document is a parsed OpenSpec markdown file. Every source line belongs to
+// exactly one node, so String reproduces the input byte for byte and edits
+// made through the model leave untouched lines as they were.
+type Document struct {
+	// Head holds the lines before the first section or requirement,
+	// usually the "# Title" heading and an introduction.
+	Head     []string
+	Sections []*Section
+
+	code map[int]bool
+}
+
+// Section is a "## " heading and what follows it. Requirements that appear
+// before any such heading live in a section with an empty Heading.
+type Section struct {
+	Heading string
+	Title   string
+	// Op is the delta operation for "## ADDED Requirements" style headings.
+	Op           string
+	Line         int
+	Body         []string
+	Requirements []*RequirementBlock
+}
+
+// RequirementBlock is a "### Requirement:" block.
+type RequirementBlock struct {
+	Name      string
+	Header    string
+	Line      int
+	Body      []string
+	Scenarios []*ScenarioBlock
+}
+
+// ScenarioBlock is a "#### Scenario:" block and its steps.
+type ScenarioBlock struct {
+	Name   string
+	Header string
+	Line   int
+	Lines  []string
+	Steps  []Step
+}
+
+// Step is a WHEN/THEN style bullet of a scenario.
+type Step struct {
+	Keyword string
+	Text    string
+	Line    int
+}
+
+var (
+	docSectionRe     = regexp.MustCompile(`^##\s+(.+?)\s*$`)
+	docDeltaOpRe     = regexp.MustCompile(`(?i)^(ADDED|MODIFIED|REMOVED|RENAMED)\s+Requirements?$`)
+	docRequirementRe = regexp.MustCompile(`^###\s+Requirement:\s*(.+?)\s*$`)
+	docScenarioRe    = regexp.MustCompile(`^####\s+Scenario:\s*(.+?)\s*$`)
+	docStepRe        = regexp.MustCompile(`^[-*]\s+(?:\*\*)?(GIVEN|WHEN|THEN|AND|BUT)(?:\*\*)?:?\s+(.*?)\s*$`)
+	docFenceRe       = regexp.MustCompile("^\\s*(```|~~~)")
+)
+
+// ParseDocument parses spec markdown. Headings inside fenced code blocks are
+// treated as text.
+func ParseDocument(content string) *Document {
+	doc := &Document{code: make(map[int]bool)}
+
+	var (
+		section  *Section
+		req      *RequirementBlock
+		scenario *ScenarioBlock
+		fence    string
+	)
+
+	for i, line := range strings.Split(content, "\n") {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(line)
+
+		if m := docFenceRe.FindStringSubmatch(line); m != nil && (fence == "" || m[1] == fence) {
+			doc.code[lineNo] = true
+			if fence == "" {
+				fence = m[1]
+			} else {
+				fence = ""
+			}
+		} else if fence != "" {
+			doc.code[lineNo] = true
+		} else if m := docSectionRe.FindStringSubmatch(trimmed); m != nil {
+			section = &Section{Heading: line, Title: m[1], Line: lineNo}
+			if op := docDeltaOpRe.FindStringSubmatch(m[1]); op != nil {
+				section.Op = strings.ToUpper(op[1])
+			}
+			doc.Sections = append(doc.Sections, section)
+			req, scenario = nil, nil
+			continue
+		} else if m := docRequirementRe.FindStringSubmatch(trimmed); m != nil {
+			if section == nil {
+				section = &Section{}
+				doc.Sections = append(doc.Sections, section)
+			}
+			req = &RequirementBlock{Name: m[1], Header: line, Line: lineNo}
+			section.Requirements = append(section.Requirements, req)
+			scenario = nil
+			continue
+		} else if m := docScenarioRe.FindStringSubmatch(trimmed); m != nil && req != nil {
+			scenario = &ScenarioBlock{Name: m[1], Header: line, Line: lineNo}
+			req.Scenarios = append(req.Scenarios, scenario)
+			continue
+		} else if m := docStepRe.FindStringSubmatch(trimmed); m != nil && scenario != nil {
+			scenario.Steps = append(scenario.Steps, Step{Keyword: m[1], Text: m[2], Line: lineNo})
+		}
+
+		switch {
+		case scenario != nil:
+			scenario.Lines = append(scenario.Lines, line)
+		case req != nil:
+			req.Body = append(req.Body, line)
+		case section != nil:
+			section.Body = append(section.Body, line)
+		default:
+			doc.Head = append(doc.Head, line)
+		}
+	}
+
+	return doc
+}
+
+// String prints the document. An unmodified document prints its source.
+func (d *Document) String() string {
+	lines := append([]string(nil), d.Head...)
+	for _, s := range d.Sections {
+		lines = append(lines, s.lines()...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// IsCode reports whether the 1-based source line is inside a fenced code
+// block, fence lines included.
+func (d *Document) IsCode(line int) bool {
+	return d.code[line]
+}
+
+// Requirements returns all requirements in document order.
+func (d *Document) Requirements() []*RequirementBlock {
+	var reqs []*RequirementBlock
+	for _, s := range d.Sections {
+		reqs = append(reqs, s.Requirements...)
+	}
+	return reqs
+}
+
+// Requirement returns the requirement with the given name, or nil.
+func (d *Document) Requirement(name string) *RequirementBlock {
+	s, i := d.find(name)
+	if s == nil {
+		return nil
+	}
+	return s.Requirements[i]
+}
+
+// Section returns the first section with the given delta operation, or nil.
+func (d *Document) Section(op string) *Section {
+	for _, s := range d.Sections {
+		if s.Op == strings.ToUpper(op) {
+			return s
+		}
+	}
+	return nil
+}
+
+// RemoveRequirement deletes a requirement and reports whether it existed.
+func (d *Document) RemoveRequirement(name string) bool {
+	s, i := d.find(name)
+	if s == nil {
+		return false
+	}
+	s.Requirements = append(s.Requirements[:i], s.Requirements[i+1:]...)
+	return true
+}
+
+// ReplaceRequirement swaps a requirement for another one, keeping the blank
+// lines that separated the old block from what followed it. It reports
+// whether the requirement existed.
+func (d *Document) ReplaceRequirement(name string, with *RequirementBlock) bool {
+	s, i := d.find(name)
+	if s == nil {
+		return false
+	}
+	with = with.clone()
+	with.trimTrailingBlank()
+	with.appendLines(trailingBlank(s.Requirements[i].lines())...)
+	s.Requirements[i] = with
+	return true
+}
+
+// AppendRequirement adds a requirement at the end of the last section,
+// separated by one blank line and followed by a final newline.
+func (d *Document) AppendRequirement(req *RequirementBlock) {
+	if len(d.Sections) == 0 {
+		d.Sections = append(d.Sections, &Section{})
+	}
+	d.trimTrailingBlank()
+	if d.String() != "" {
+		d.lastLines(func(lines []string) []string { return append(lines, "") })
+	}
+
+	req = req.clone()
+	req.trimTrailingBlank()
+	req.appendLines("")
+
+	s := d.Sections[len(d.Sections)-1]
+	s.Requirements = append(s.Requirements, req)
+}
+
+// Rename changes the requirement name in place, keeping the rest of the
+// header line as written.
+func (r *RequirementBlock) Rename(name string) {
+	if i := strings.LastIndex(r.Header, r.Name); i >= 0 {
+		r.Header = r.Header[:i] + name + r.Header[i+len(r.Name):]
+	} else {
+		r.Header = "### Requirement: " + name
+	}
+	r.Name = name
+}
+
+// String prints the requirement block.
+func (r *RequirementBlock) String() string {
+	return strings.Join(r.lines(), "\n")
+}
+
+// Text returns the requirement's body and scenario lines joined, without the
+// header.
+func (r *RequirementBlock) Text() string {
+	return strings.Join(r.lines()[1:], "\n")
+}
+
+// EndLine returns the last source line of the block.
+func (r *RequirementBlock) EndLine() int {
+	return r.Line + len(r.lines()) - 1
+}
+
+func (d *Document) find(name string) (*Section, int) {
+	for _, s := range d.Sections {
+		for i, r := range s.Requirements {
+			if r.Name == name {
+				return s, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// lastLines rewrites the lines of whichever node ends the document.
+func (d *Document) lastLines(fn func([]string) []string) {
+	if len(d.Sections) == 0 {
+		d.Head = fn(d.Head)
+		return
+	}
+	s := d.Sections[len(d.Sections)-1]
+	if len(s.Requirements) == 0 {
+		if s.Heading == "" && len(s.Body) == 0 && len(d.Sections) == 1 {
+			d.Head = fn(d.Head)
+			return
+		}
+		s.Body = fn(s.Body)
+		return
+	}
+	r := s.Requirements[len(s.Requirements)-1]
+	if len(r.Scenarios) == 0 {
+		r.Body = fn(r.Body)
+		return
+	}
+	sc := r.Scenarios[len(r.Scenarios)-1]
+	sc.Lines = fn(sc.Lines)
+}
+
+func (d *Document) trimTrailingBlank() {
+	for {
+		trimmed := false
+		d.lastLines(func(lines []string) []string {
+			if n := len(lines); n > 0 && strings.TrimSpace(lines[n-1]) == "" {
+				trimmed = true
+				return lines[:n-1]
+			}
+			return lines
+		})
+		if !trimmed {
+			return
+		}
+	}
+}
+
+func (s *Section) lines() []string {
+	var lines []string
+	if s.Heading != "" {
+		lines = append(lines, s.Heading)
+	}
+	lines = append(lines, s.Body...)
+	for _, r := range s.Requirements {
+		lines = append(lines, r.lines()...)
+	}
+	return lines
+}
+
+func (r *RequirementBlock) lines() []string {
+	lines := append([]string{r.Header}, r.Body...)
+	for _, sc := range r.Scenarios {
+		lines = append(lines, sc.Header)
+		lines = append(lines, sc.Lines...)
+	}
+	return lines
+}
+
+func (r *RequirementBlock) clone() *RequirementBlock {
+	c := *r
+	c.Body = append([]string(nil), r.Body...)
+	c.Scenarios = make([]*ScenarioBlock, len(r.Scenarios))
+	for i, sc := range r.Scenarios {
+		scc := *sc
+		scc.Lines = append([]string(nil), sc.Lines...)
+		scc.Steps = append([]Step(nil), sc.Steps...)
+		c.Scenarios[i] = &scc
+	}
+	return &c
+}
+
+func (r *RequirementBlock) appendLines(lines ...string) {
+	if n := len(r.Scenarios); n > 0 {
+		r.Scenarios[n-1].Lines = append(r.Scenarios[n-1].Lines, lines...)
+		return
+	}
+	r.Body = append(r.Body, lines...)
+}
+
+func (r *RequirementBlock) trimTrailingBlank() {
+	tail := &r.Body
+	if n := len(r.Scenarios); n > 0 {
+		tail = &r.Scenarios[n-1].Lines
+	}
+	for len(*tail) > 0 && strings.TrimSpace((*tail)[len(*tail)-1]) == "" {
+		*tail = (*tail)[:len(*tail)-1]
+	}
+}
+
+// trailingBlank returns the blank lines at the end of lines.
+func trailingBlank(lines []string) []string {
+	i := len(lines)
+	for i > 0 && strings.TrimSpace(lines[i-1]) == "" {
+		i--
+	}
+	return lines[i:]
+}
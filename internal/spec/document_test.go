@@ -0,0 +1,123 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const documentFixture = "# Auth Specification\n" +
+	"\n" +
+	"Intro text.\n" +
+	"\n" +
+	"## Requirements\n" +
+	"\n" +
+	"### Requirement: Login   \n" +
+	"The system SHALL log users in.\n" +
+	"\n" +
+	"```markdown\n" +
+	"### Requirement: Not a requirement\n" +
+	"#### Scenario: Not a scenario\n" +
+	"```\n" +
+	"\n" +
+	"#### Scenario: Valid credentials\n" +
+	"- **WHEN** the user submits valid credentials\n" +
+	"- **THEN** a session is created\n" +
+	"- **AND** the user is redirected\n" +
+	"\n" +
+	"##### Notes\n" +
+	"Nested heading stays in the scenario.\n" +
+	"\n" +
+	"### Requirement: Logout\n" +
+	"\n" +
+	"#### Scenario: Active session\n" +
+	"- WHEN the user logs out\n" +
+	"- THEN the session ends\n"
+
+func TestParseDocument(t *testing.T) {
+	t.Parallel()
+
+	doc := ParseDocument(documentFixture)
+	assert.Equal(t, documentFixture, doc.String(), "printing an unmodified document is lossless")
+
+	assert.Equal(t, []string{"# Auth Specification", "", "Intro text.", ""}, doc.Head)
+	require.Len(t, doc.Sections, 1)
+	assert.Equal(t, "Requirements", doc.Sections[0].Title)
+	assert.Equal(t, 5, doc.Sections[0].Line)
+
+	reqs := doc.Requirements()
+	require.Len(t, reqs, 2)
+
+	login := reqs[0]
+	assert.Equal(t, "Login", login.Name)
+	assert.Equal(t, 7, login.Line)
+	assert.Equal(t, 22, login.EndLine())
+	require.Len(t, login.Scenarios, 1, "headings inside code fences are text")
+	assert.Equal(t, []Step{
+		{Keyword: "WHEN", Text: "the user submits valid credentials", Line: 16},
+		{Keyword: "THEN", Text: "a session is created", Line: 17},
+		{Keyword: "AND", Text: "the user is redirected", Line: 18},
+	}, login.Scenarios[0].Steps)
+
+	assert.True(t, doc.IsCode(11))
+	assert.False(t, doc.IsCode(15))
+
+	logout := doc.Requirement("Logout")
+	require.NotNil(t, logout)
+	require.Len(t, logout.Scenarios, 1)
+	assert.Len(t, logout.Scenarios[0].Steps, 2)
+}
+
+func TestDocument_Edits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rename keeps header formatting", func(t *testing.T) {
+		t.Parallel()
+
+		doc := ParseDocument(documentFixture)
+		doc.Requirement("Login").Rename("Sign in")
+		assert.Contains(t, doc.String(), "### Requirement: Sign in   \n")
+		assert.Nil(t, doc.Requirement("Login"))
+	})
+
+	t.Run("remove keeps neighbours", func(t *testing.T) {
+		t.Parallel()
+
+		doc := ParseDocument(documentFixture)
+		require.True(t, doc.RemoveRequirement("Login"))
+		assert.False(t, doc.RemoveRequirement("Login"))
+		assert.Equal(t, "# Auth Specification\n\nIntro text.\n\n## Requirements\n\n"+
+			"### Requirement: Logout\n\n#### Scenario: Active session\n- WHEN the user logs out\n- THEN the session ends\n",
+			doc.String())
+	})
+
+	t.Run("replace keeps separating blank lines", func(t *testing.T) {
+		t.Parallel()
+
+		doc := ParseDocument(documentFixture)
+		block := ParseDocument("### Requirement: Login\n\n#### Scenario: SSO\n- WHEN SSO succeeds\n- THEN a session is created\n\n\n").Requirements()[0]
+		require.True(t, doc.ReplaceRequirement("Login", block))
+
+		out := doc.String()
+		assert.Contains(t, out, "- THEN a session is created\n\n### Requirement: Logout\n")
+		assert.NotContains(t, out, "Valid credentials")
+	})
+
+	t.Run("append separates with one blank line", func(t *testing.T) {
+		t.Parallel()
+
+		doc := ParseDocument("# Spec\n\n### Requirement: First\n\n#### Scenario: One\n- WHEN a\n- THEN b\n\n\n")
+		doc.AppendRequirement(ParseDocument("### Requirement: Second\n\n#### Scenario: Two\n- WHEN c\n- THEN d").Requirements()[0])
+		assert.Equal(t, "# Spec\n\n### Requirement: First\n\n#### Scenario: One\n- WHEN a\n- THEN b\n\n"+
+			"### Requirement: Second\n\n#### Scenario: Two\n- WHEN c\n- THEN d\n", doc.String())
+	})
+
+	t.Run("append to a document without requirements", func(t *testing.T) {
+		t.Parallel()
+
+		doc := ParseDocument("# Spec\n\n")
+		doc.AppendRequirement(ParseDocument("### Requirement: First\nBody").Requirements()[0])
+		assert.Equal(t, "# Spec\n\n### Requirement: First\nBody\n", doc.String())
+	})
+}
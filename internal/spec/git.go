@@ -0,0 +1,380 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ChangeBranchPrefix prefixes the git branch of a change.
+const ChangeBranchPrefix = "change/"
+
+// gitBaseKey is the per-branch git config entry remembering the branch a
+// change was started from.
+const gitBaseKey = "goentbase"
+
+// taskNumRe matches the number a tasks.md line starts with, like 1.2.
+var taskNumRe = regexp.MustCompile(`^\d+(?:\.\d+)*$`)
+
+var (
+	ErrNotGitRepo      = errors.New("not a git repository")
+	ErrBranchNotMerged = errors.New("change branch is not merged")
+)
+
+// ChangeBranch returns the git branch name for a change.
+func ChangeBranch(changeID string) string {
+	return ChangeBranchPrefix + changeID
+}
+
+// GitCommit is a commit read from git log.
+type GitCommit struct {
+	SHA     string
+	Time    time.Time
+	Subject string
+	Message string
+}
+
+// Git runs the local git binary in a working tree. It never talks to a
+// remote.
+type Git struct {
+	dir string
+}
+
+func NewGit(dir string) *Git {
+	return &Git{dir: dir}
+}
+
+// IsRepo reports whether the directory is inside a git working tree.
+func (g *Git) IsRepo() bool {
+	out, err := g.run("rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// CurrentBranch returns the checked out branch, or "" on a detached HEAD.
+func (g *Git) CurrentBranch() (string, error) {
+	out, err := g.run("symbolic-ref", "--quiet", "--short", "HEAD")
+	if err != nil {
+		return "", nil //nolint:nilerr // detached HEAD
+	}
+	return out, nil
+}
+
+// BranchExists reports whether a local branch exists.
+func (g *Git) BranchExists(branch string) bool {
+	_, err := g.run("rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	return err == nil
+}
+
+// StartChange creates and checks out the branch of a change from the
+// current branch, which is remembered as the branch it merges back into.
+func (g *Git) StartChange(changeID string) (string, error) {
+	if !g.IsRepo() {
+		return "", ErrNotGitRepo
+	}
+
+	branch := ChangeBranch(changeID)
+	if g.BranchExists(branch) {
+		return "", fmt.Errorf("branch %s already exists", branch)
+	}
+
+	base, err := g.CurrentBranch()
+	if err != nil {
+		return "", err
+	}
+	if base == "" {
+		return "", fmt.Errorf("HEAD is detached: check out the branch %s should merge into", branch)
+	}
+
+	if _, err := g.run("checkout", "-b", branch); err != nil {
+		return "", err
+	}
+	if _, err := g.run("config", "branch."+branch+"."+gitBaseKey, base); err != nil {
+		return "", err
+	}
+	return branch, nil
+}
+
+// BaseBranch returns the branch a change merges into: the branch it was
+// started from, else main or master.
+func (g *Git) BaseBranch(changeID string) string {
+	if base, err := g.run("config", "--get", "branch."+ChangeBranch(changeID)+"."+gitBaseKey); err == nil && base != "" {
+		return base
+	}
+	for _, name := range []string{"main", "master"} {
+		if g.BranchExists(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// CheckMerged returns ErrBranchNotMerged when the branch of a change has
+// changes its base branch does not. Commits are compared by patch, so a
+// rebased or cherry-picked branch counts as merged, and so does a squash
+// merge, which leaves nothing for a merge of the branch to add. A change
+// without a branch, or outside a git repository, has nothing to merge.
+func (g *Git) CheckMerged(changeID string) error {
+	branch := ChangeBranch(changeID)
+	if !g.IsRepo() || !g.BranchExists(branch) {
+		return nil
+	}
+
+	base := g.BaseBranch(changeID)
+	if base == "" || base == branch {
+		return fmt.Errorf("%w: %s has no base branch to merge into", ErrBranchNotMerged, branch)
+	}
+
+	ahead, err := g.ahead(base, branch)
+	if err != nil {
+		return err
+	}
+	if ahead == 0 || g.mergeAddsNothing(base, branch) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is %d commit(s) ahead of %s", ErrBranchNotMerged, branch, ahead, base)
+}
+
+// IsMerged reports whether the changes of ref reached base, the way
+// CheckMerged decides it for a branch: ref is an ancestor of base, its
+// commits have patch-equivalent ones in base, or merging it adds nothing.
+func (g *Git) IsMerged(ref, base string) bool {
+	if g.IsAncestor(ref, base) {
+		return true
+	}
+	ahead, err := g.ahead(base, ref)
+	if err != nil {
+		return false
+	}
+	return ahead == 0 || g.mergeAddsNothing(base, ref)
+}
+
+// ahead counts the commits of ref with no patch-equivalent commit in base.
+func (g *Git) ahead(base, ref string) (int, error) {
+	out, err := g.run("cherry", base, ref)
+	if err != nil {
+		return 0, err
+	}
+	ahead := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "+") {
+			ahead++
+		}
+	}
+	return ahead, nil
+}
+
+// mergeAddsNothing reports whether merging branch into base would leave the
+// tree of base as it is. A conflict, or a git too old for merge-tree
+// --write-tree, counts as adding something.
+func (g *Git) mergeAddsNothing(base, branch string) bool {
+	merged, err := g.run("merge-tree", "--write-tree", base, branch)
+	if err != nil {
+		return false
+	}
+	tree, err := g.run("rev-parse", base+"^{tree}")
+	if err != nil {
+		return false
+	}
+	first, _, _ := strings.Cut(merged, "\n")
+	return first == tree
+}
+
+// HooksDir returns the directory git runs hooks from.
+func (g *Git) HooksDir() (string, error) {
+	dir, err := g.run("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(g.dir, dir)
+	}
+	return dir, nil
+}
+
+// IsAncestor reports whether commit is reachable from ref.
+func (g *Git) IsAncestor(commit, ref string) bool {
+	_, err := g.run("merge-base", "--is-ancestor", commit, ref)
+	return err == nil
+}
+
+// Log returns the commits of every local branch, newest first.
+func (g *Git) Log() ([]GitCommit, error) {
+	out, err := g.run("log", "--branches", "--format=%x1e%H%x1f%cI%x1f%s%x1f%B")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []GitCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		parts := strings.SplitN(record, "\x1f", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("parse commit time of %s: %w", parts[0], err)
+		}
+		commits = append(commits, GitCommit{
+			SHA:     parts[0],
+			Time:    at,
+			Subject: parts[2],
+			Message: strings.TrimSpace(parts[3]),
+		})
+	}
+	return commits, nil
+}
+
+func (g *Git) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", g.dir}, args...)...) // #nosec G204 -- fixed git subcommands
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// GitSyncResult reports what SyncGit recorded.
+type GitSyncResult struct {
+	Linked    []TaskCommitLink `json:"linked"`
+	Completed []TaskID         `json:"completed"`
+}
+
+// TaskCommitLink is a commit newly recorded on a task.
+type TaskCommitLink struct {
+	TaskID TaskID     `json:"task_id"`
+	Commit TaskCommit `json:"commit"`
+}
+
+// SyncGit records commits whose messages mention a task ID on that task,
+// then completes tasks with a recorded commit that reached the base branch
+// of their change, as decided by IsMerged, so rebased and squash-merged
+// commits count too. Completed tasks are ticked in tasks.md unless
+// registryOnly is set. A
+// reference is either the registry ID (add-sso/3) or the number written in
+// tasks.md (add-sso/1.2). With registryOnly, the next Sync ticks completed
+// tasks in tasks.md.
+func (r *RegistryStore) SyncGit(g *Git, registryOnly bool) (*GitSyncResult, error) {
+	result := &GitSyncResult{
+		Linked:    make([]TaskCommitLink, 0),
+		Completed: make([]TaskID, 0),
+	}
+	if !g.IsRepo() {
+		return nil, ErrNotGitRepo
+	}
+
+	commits, err := g.Log()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := r.bolt.ListTasks(TaskFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+
+	byRef := make(map[string]*RegistryTask)
+	for i := range tasks {
+		t := &tasks[i]
+		byRef[t.ID.String()] = t
+		if num, _, ok := strings.Cut(t.Content, " "); ok && taskNumRe.MatchString(num) {
+			ref := t.ID.ChangeID + "/" + num
+			if _, taken := byRef[ref]; !taken {
+				byRef[ref] = t
+			}
+		}
+	}
+
+	changed := make(map[string]*RegistryTask)
+	for i := len(commits) - 1; i >= 0; i-- {
+		c := commits[i]
+		for _, m := range taskRefRe.FindAllString(c.Message, -1) {
+			t, ok := byRef[m]
+			if !ok || hasCommit(t, c.SHA) {
+				continue
+			}
+			tc := TaskCommit{SHA: c.SHA, Time: c.Time, Subject: c.Subject}
+			t.Commits = append(t.Commits, tc)
+			changed[t.ID.String()] = t
+			result.Linked = append(result.Linked, TaskCommitLink{TaskID: t.ID, Commit: tc})
+		}
+	}
+
+	for _, t := range changed {
+		if err := r.bolt.UpdateTask(t); err != nil {
+			return nil, fmt.Errorf("record commits on %s: %w", t.ID, err)
+		}
+	}
+
+	bases := make(map[string]string)
+	for i := range tasks {
+		t := &tasks[i]
+		if t.Status == RegStatusCompleted || t.Status == RegStatusSkipped || len(t.Commits) == 0 {
+			continue
+		}
+
+		base, ok := bases[t.ID.ChangeID]
+		if !ok {
+			base = g.BaseBranch(t.ID.ChangeID)
+			bases[t.ID.ChangeID] = base
+		}
+		if base == "" {
+			continue
+		}
+
+		var merged *TaskCommit
+		for j := range t.Commits {
+			if g.IsMerged(t.Commits[j].SHA, base) {
+				merged = &t.Commits[j]
+				break
+			}
+		}
+		if merged == nil {
+			continue
+		}
+
+		if err := r.completeMerged(t.ID, merged, registryOnly); err != nil {
+			return nil, err
+		}
+		result.Completed = append(result.Completed, t.ID)
+	}
+
+	return result, nil
+}
+
+func (r *RegistryStore) completeMerged(id TaskID, c *TaskCommit, registryOnly bool) error {
+	status := RegStatusCompleted
+	notes := fmt.Sprintf("✓ %s (%s)", time.Now().Format("2006-01-02"), shortSHA(c.SHA))
+	if err := r.UpdateTask(id, TaskUpdate{Status: &status, Notes: &notes}); err != nil {
+		return fmt.Errorf("complete %s: %w", id, err)
+	}
+	if registryOnly {
+		return nil
+	}
+	if err := r.stateStore.UpdateTaskInFile(id, status, notes); err != nil {
+		return fmt.Errorf("update task in file: %w", err)
+	}
+	return nil
+}
+
+func hasCommit(t *RegistryTask, sha string) bool {
+	for _, c := range t.Commits {
+		if c.SHA == sha {
+			return true
+		}
+	}
+	return false
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
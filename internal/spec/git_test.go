@@ -0,0 +1,277 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestGitChangeLifecycle(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] 1.1 Add provider\n- [ ] 1.2 Add callback\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("openspec/registry.db\n"), 0o600))
+
+	gitRun(t, root, "init", "-q", "-b", "main")
+	gitRun(t, root, "add", "-A")
+	gitRun(t, root, "commit", "-qm", "Propose add-sso")
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	git := NewGit(root)
+	branch, err := git.StartChange("add-sso")
+	require.NoError(t, err)
+	assert.Equal(t, "change/add-sso", branch)
+	assert.Equal(t, "main", git.BaseBranch("add-sso"))
+	require.NoError(t, git.CheckMerged("add-sso"), "a branch without commits has nothing to merge")
+
+	_, err = git.StartChange("add-sso")
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, "sso.go"), []byte("package sso\n"), 0o600))
+	gitRun(t, root, "add", "sso.go")
+	gitRun(t, root, "commit", "-qm", "Add SSO provider\n\nRefs add-sso/1.1")
+
+	err = git.CheckMerged("add-sso")
+	require.ErrorIs(t, err, ErrBranchNotMerged)
+	assert.Contains(t, err.Error(), "1 commit(s) ahead of main")
+
+	archiver := NewArchiver(NewStore(root))
+	_, err = archiver.Archive("add-sso", true, true, false)
+	require.ErrorIs(t, err, ErrBranchNotMerged)
+
+	result, err := reg.SyncGit(git, false)
+	require.NoError(t, err)
+	require.Len(t, result.Linked, 1)
+	assert.Equal(t, "add-sso/1", result.Linked[0].TaskID.String())
+	assert.Equal(t, "Add SSO provider", result.Linked[0].Commit.Subject)
+	assert.Empty(t, result.Completed, "the commit is not on main yet")
+
+	result, err = reg.SyncGit(git, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Linked, "commits are recorded once")
+
+	gitRun(t, root, "checkout", "-q", "main")
+	gitRun(t, root, "merge", "-q", "--no-ff", "-m", "Merge change/add-sso", "change/add-sso")
+
+	result, err = reg.SyncGit(git, false)
+	require.NoError(t, err)
+	assert.Empty(t, result.Linked)
+	require.Len(t, result.Completed, 1)
+	assert.Equal(t, "add-sso/1", result.Completed[0].String())
+
+	task, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusCompleted, task.Status)
+	require.Len(t, task.Commits, 1)
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	lines := strings.Split(string(content), "\n")
+	assert.True(t, strings.HasPrefix(lines[2], "- [x] 1.1 Add provider ✓ "), lines[2])
+	assert.Contains(t, lines[2], "("+shortSHA(task.Commits[0].SHA)+")")
+	assert.Equal(t, "- [ ] 1.2 Add callback", lines[3])
+
+	// Commits survive a rebuild from tasks.md
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	task, err = reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	assert.Len(t, task.Commits, 1)
+
+	require.NoError(t, git.CheckMerged("add-sso"))
+	_, err = archiver.Archive("add-sso", true, true, false)
+	require.NoError(t, err)
+}
+
+func TestGitOutsideRepository(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	git := NewGit(t.TempDir())
+	assert.False(t, git.IsRepo())
+	require.NoError(t, git.CheckMerged("add-sso"))
+
+	_, err := git.StartChange("add-sso")
+	require.ErrorIs(t, err, ErrNotGitRepo)
+}
+
+func TestGitCheckMergedComparesPatches(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "proposal.md"), []byte("# Proposal\n"), 0o600))
+
+	gitRun(t, root, "init", "-q", "-b", "main")
+	gitRun(t, root, "add", "-A")
+	gitRun(t, root, "commit", "-qm", "Propose add-sso")
+
+	git := NewGit(root)
+	_, err := git.StartChange("add-sso")
+	require.NoError(t, err)
+	for _, name := range []string{"provider.go", "callback.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("package sso\n"), 0o600))
+		gitRun(t, root, "add", name)
+		gitRun(t, root, "commit", "-qm", "Add "+name)
+	}
+
+	archiver := NewArchiver(NewStore(root))
+	_, err = archiver.Archive("add-sso", true, true, false)
+	require.ErrorIs(t, err, ErrBranchNotMerged)
+	result, err := archiver.Archive("add-sso", true, true, true)
+	require.NoError(t, err, "allowUnmerged skips the check")
+	assert.True(t, result.DryRun)
+
+	// A rebase onto main copies the commits with new hashes
+	gitRun(t, root, "checkout", "-q", "main")
+	require.NoError(t, os.WriteFile(filepath.Join(root, "README.md"), []byte("# App\n"), 0o600))
+	gitRun(t, root, "add", "README.md")
+	gitRun(t, root, "commit", "-qm", "Add readme")
+	gitRun(t, root, "cherry-pick", "main..change/add-sso")
+	require.NoError(t, git.CheckMerged("add-sso"), "rebased commits carry the same patches")
+
+	// A squash merge folds both commits into one
+	gitRun(t, root, "reset", "-q", "--hard", "HEAD~2")
+	err = git.CheckMerged("add-sso")
+	require.ErrorIs(t, err, ErrBranchNotMerged)
+	assert.Contains(t, err.Error(), "2 commit(s) ahead of main")
+
+	gitRun(t, root, "merge", "-q", "--squash", "change/add-sso")
+	gitRun(t, root, "commit", "-qm", "Add SSO")
+	require.NoError(t, git.CheckMerged("add-sso"), "a squash merge leaves nothing to merge")
+	_, err = archiver.Archive("add-sso", true, true, false)
+	require.NoError(t, err)
+}
+
+func TestSyncGitRegistryOnly(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	tasks := "# Tasks\n\n- [ ] 1.1 Add provider\n"
+	require.NoError(t, os.WriteFile(tasksPath, []byte(tasks), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("openspec/registry.db\n"), 0o600))
+
+	gitRun(t, root, "init", "-q", "-b", "main")
+	gitRun(t, root, "add", "-A")
+	gitRun(t, root, "commit", "-qm", "Propose add-sso")
+	gitRun(t, root, "commit", "-q", "--allow-empty", "-m", "Wire provider (add-sso/1.1)")
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	result, err := reg.SyncGit(NewGit(root), true)
+	require.NoError(t, err)
+	require.Len(t, result.Completed, 1)
+
+	task, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusCompleted, task.Status)
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, tasks, string(content), "tasks.md is left to the next sync")
+
+	_, err = reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	content, err = os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- [x] 1.1 Add provider")
+}
+
+func TestSyncGitSquashMerge(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte("# Tasks\n\n- [ ] 1.1 Add provider\n- [ ] 1.2 Add callback\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".gitignore"), []byte("openspec/registry.db\n"), 0o600))
+
+	gitRun(t, root, "init", "-q", "-b", "main")
+	gitRun(t, root, "add", "-A")
+	gitRun(t, root, "commit", "-qm", "Propose add-sso")
+
+	git := NewGit(root)
+	_, err := git.StartChange("add-sso")
+	require.NoError(t, err)
+	for i, name := range []string{"provider.go", "callback.go"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("package sso\n"), 0o600))
+		gitRun(t, root, "add", name)
+		gitRun(t, root, "commit", "-qm", fmt.Sprintf("Add %s (add-sso/1.%d)", name, i+1))
+	}
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	result, err := reg.SyncGit(git, true)
+	require.NoError(t, err)
+	assert.Len(t, result.Linked, 2)
+	assert.Empty(t, result.Completed, "the branch is not merged yet")
+
+	gitRun(t, root, "checkout", "-q", "main")
+	gitRun(t, root, "merge", "-q", "--squash", "change/add-sso")
+	gitRun(t, root, "commit", "-qm", "Add SSO")
+
+	result, err = reg.SyncGit(git, true)
+	require.NoError(t, err)
+	assert.Len(t, result.Completed, 2, "squash-merged commits complete their tasks")
+	for _, num := range []string{"1", "2"} {
+		task, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: num})
+		require.NoError(t, err)
+		assert.Equal(t, RegStatusCompleted, task.Status, num)
+	}
+}
@@ -0,0 +1,478 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveManifestFile is written into an archived change and records what
+// archiving it did to the specs.
+const ArchiveManifestFile = "archive.json"
+
+var (
+	ErrUnarchiveConflict = errors.New("later edits conflict with the archived change")
+	ErrNoArchivePatch    = errors.New("archived without spec patches")
+
+	archiveNameRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+)
+
+// ArchiveManifest is what archiving a change did to the specs.
+type ArchiveManifest struct {
+	ChangeID   string      `json:"change_id"`
+	ArchivedAt time.Time   `json:"archived_at"`
+	SkipSpecs  bool        `json:"skip_specs,omitempty"`
+	Patches    []SpecPatch `json:"patches,omitempty"`
+}
+
+// SpecPatch is what archiving did to one spec. The whole file before and
+// after reverts it exactly while nothing else touched it; the requirement
+// edits revert it around later edits to other requirements.
+type SpecPatch struct {
+	Spec         string             `json:"spec"`
+	Created      bool               `json:"created,omitempty"`
+	Before       string             `json:"before"`
+	After        string             `json:"after"`
+	Requirements []RequirementPatch `json:"requirements"`
+}
+
+// RequirementPatch is one requirement edit with the block before and after
+// it. Name is the requirement name after archiving, or the removed name;
+// From is the name before a rename.
+type RequirementPatch struct {
+	Op     DeltaOperation `json:"op"`
+	Name   string         `json:"name"`
+	From   string         `json:"from,omitempty"`
+	Before string         `json:"before,omitempty"`
+	After  string         `json:"after,omitempty"`
+}
+
+// UnarchiveResult contains the result of an unarchive operation.
+type UnarchiveResult struct {
+	ChangeID      string
+	ArchivePath   string
+	ChangePath    string
+	RevertedSpecs []string
+	Conflicts     []string
+	DryRun        bool
+}
+
+// SpecHistoryEntry is one archived change that edited a spec.
+type SpecHistoryEntry struct {
+	ChangeID     string             `json:"change_id"`
+	Archive      string             `json:"archive"`
+	ArchivedAt   time.Time          `json:"archived_at"`
+	Requirements []RequirementPatch `json:"requirements"`
+	// Recorded is false for changes archived before patches were kept,
+	// whose edits are read from the archived delta spec.
+	Recorded bool `json:"recorded"`
+}
+
+// archivedChange is an archive directory and its manifest, if any.
+type archivedChange struct {
+	name     string
+	path     string
+	changeID string
+	date     time.Time
+	manifest *ArchiveManifest
+}
+
+// requirementPatches records the requirement edits delta made to before,
+// giving after.
+func requirementPatches(before, after string, delta *DeltaSpec) []RequirementPatch {
+	baseDoc, mergedDoc := ParseDocument(before), ParseDocument(after)
+	block := func(doc *Document, name string) string {
+		if r := doc.Requirement(name); r != nil {
+			return strings.TrimSpace(r.String())
+		}
+		return ""
+	}
+
+	var patches []RequirementPatch
+	renamedFrom := make(map[string]string)
+	for _, r := range delta.Renamed {
+		renamedFrom[r.ToName] = r.FromName
+		patches = append(patches, RequirementPatch{
+			Op:     OpRenamed,
+			Name:   r.ToName,
+			From:   r.FromName,
+			Before: block(baseDoc, r.FromName),
+			After:  block(mergedDoc, r.ToName),
+		})
+	}
+
+	// Edits after a rename use the new name
+	beforeBlock := func(name string) string {
+		if b := block(baseDoc, name); b != "" {
+			return b
+		}
+		return block(baseDoc, renamedFrom[name])
+	}
+	for _, r := range delta.Removed {
+		patches = append(patches, RequirementPatch{Op: OpRemoved, Name: r.Name, Before: beforeBlock(r.Name)})
+	}
+	for _, r := range delta.Modified {
+		patches = append(patches, RequirementPatch{Op: OpModified, Name: r.Name, Before: beforeBlock(r.Name), After: block(mergedDoc, r.Name)})
+	}
+	for _, r := range delta.Added {
+		patches = append(patches, RequirementPatch{Op: OpAdded, Name: r.Name, Before: block(baseDoc, r.Name), After: block(mergedDoc, r.Name)})
+	}
+	return patches
+}
+
+// Unarchive reverts the spec edits of an archived change and moves it back
+// to changes/. It refuses with ErrUnarchiveConflict, listing the conflicts
+// in the result, when a later archived change or a hand edit touched a
+// requirement the change edited.
+func (a *Archiver) Unarchive(changeID string, dryRun bool) (*UnarchiveResult, error) {
+	changePath := filepath.Join(a.store.SpecPath(), "changes", changeID)
+	if _, err := os.Stat(changePath); err == nil {
+		return nil, fmt.Errorf("change already exists: %s", changeID)
+	}
+
+	archives, err := a.archivedChanges()
+	if err != nil {
+		return nil, err
+	}
+	var target *archivedChange
+	for i := range archives {
+		if archives[i].changeID == changeID {
+			target = &archives[i]
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("archived change not found: %s", changeID)
+	}
+
+	result := &UnarchiveResult{
+		ChangeID:    changeID,
+		ArchivePath: target.path,
+		ChangePath:  changePath,
+		DryRun:      dryRun,
+	}
+
+	if target.manifest == nil {
+		if deltas, _ := filepath.Glob(filepath.Join(target.path, "specs", "*", "*.md")); len(deltas) > 0 {
+			return nil, fmt.Errorf("%w: %s was archived before patches were recorded, revert its specs by hand", ErrNoArchivePatch, target.name)
+		}
+	}
+
+	reverted := make(map[string]string)
+	var removed []string
+	if target.manifest != nil {
+		for _, patch := range target.manifest.Patches {
+			specPath := filepath.Join(a.store.SpecPath(), "specs", patch.Spec, "spec.md")
+			content, err := os.ReadFile(specPath) // #nosec G304 -- controlled file path
+			if err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("read spec %s: %w", patch.Spec, err)
+			}
+
+			if string(content) == patch.After {
+				if patch.Created {
+					removed = append(removed, specPath)
+				} else {
+					reverted[specPath] = patch.Before
+				}
+				result.RevertedSpecs = append(result.RevertedSpecs, patch.Spec)
+				continue
+			}
+
+			conflicts := a.laterConflicts(archives, target, patch)
+			doc := ParseDocument(string(content))
+			conflicts = append(conflicts, patchConflicts(doc, patch)...)
+			if len(conflicts) > 0 {
+				result.Conflicts = append(result.Conflicts, conflicts...)
+				continue
+			}
+
+			if err := revertRequirements(doc, patch); err != nil {
+				return nil, fmt.Errorf("revert spec %s: %w", patch.Spec, err)
+			}
+			reverted[specPath] = doc.String()
+			result.RevertedSpecs = append(result.RevertedSpecs, patch.Spec)
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		return result, fmt.Errorf("%w: %d conflict(s)", ErrUnarchiveConflict, len(result.Conflicts))
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	for path, content := range reverted {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return nil, fmt.Errorf("write spec: %w", err)
+		}
+	}
+	for _, path := range removed {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("remove spec: %w", err)
+		}
+		// Drop the capability directory too if archiving created it
+		_ = os.Remove(filepath.Dir(path))
+	}
+
+	if err := os.Rename(target.path, changePath); err != nil {
+		return nil, fmt.Errorf("restore change: %w", err)
+	}
+	if err := os.Remove(filepath.Join(changePath, ArchiveManifestFile)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove archive manifest: %w", err)
+	}
+
+	return result, nil
+}
+
+// laterConflicts lists changes archived after target that edited a
+// requirement patch touches.
+func (a *Archiver) laterConflicts(archives []archivedChange, target *archivedChange, patch SpecPatch) []string {
+	names := make(map[string]bool)
+	for _, r := range patch.Requirements {
+		names[r.Name] = true
+		if r.From != "" {
+			names[r.From] = true
+		}
+	}
+
+	var conflicts []string
+	for _, later := range archives {
+		if later.manifest == nil || !later.manifest.ArchivedAt.After(target.manifest.ArchivedAt) {
+			continue
+		}
+		for _, p := range later.manifest.Patches {
+			if p.Spec != patch.Spec {
+				continue
+			}
+			for _, r := range p.Requirements {
+				if names[r.Name] || (r.From != "" && names[r.From]) {
+					conflicts = append(conflicts, fmt.Sprintf("%s: %s %s later by %s", patch.Spec, strings.ToLower(string(r.Op)), r.Name, later.changeID))
+				}
+			}
+		}
+	}
+	return conflicts
+}
+
+// patchConflicts lists requirements of doc that no longer look the way the
+// patch left them.
+func patchConflicts(doc *Document, patch SpecPatch) []string {
+	var conflicts []string
+	for _, r := range patch.Requirements {
+		current := ""
+		if block := doc.Requirement(r.Name); block != nil {
+			current = strings.TrimSpace(block.String())
+		}
+		switch {
+		case r.After == "" && current != "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: requirement %s was added again", patch.Spec, r.Name))
+		case r.After != "" && current == "":
+			conflicts = append(conflicts, fmt.Sprintf("%s: requirement %s is gone", patch.Spec, r.Name))
+		case current != r.After:
+			conflicts = append(conflicts, fmt.Sprintf("%s: requirement %s was edited since", patch.Spec, r.Name))
+		}
+	}
+	return conflicts
+}
+
+// revertRequirements undoes the requirement edits of patch in reverse
+// order. Removed requirements come back at the end of the spec.
+func revertRequirements(doc *Document, patch SpecPatch) error {
+	for i := len(patch.Requirements) - 1; i >= 0; i-- {
+		r := patch.Requirements[i]
+		switch r.Op {
+		case OpRenamed:
+			if block := doc.Requirement(r.Name); block != nil {
+				block.Rename(r.From)
+			}
+		case OpAdded, OpModified, OpRemoved:
+			if r.Before == "" {
+				doc.RemoveRequirement(r.Name)
+				continue
+			}
+			block, err := parseRequirementBlock(r.Before)
+			if err != nil {
+				return fmt.Errorf("requirement %s: %w", r.Name, err)
+			}
+			if !doc.ReplaceRequirement(r.Name, block) {
+				doc.AppendRequirement(block)
+			}
+		}
+	}
+	return nil
+}
+
+// History lists the archived changes that edited a spec, oldest first. With
+// a requirement name, only changes that edited that requirement are listed,
+// following it back through renames.
+func (a *Archiver) History(specName, requirement string) ([]SpecHistoryEntry, error) {
+	archives, err := a.archivedChanges()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []SpecHistoryEntry
+	for _, ac := range archives {
+		entry := SpecHistoryEntry{ChangeID: ac.changeID, Archive: ac.name, ArchivedAt: ac.date}
+		if ac.manifest != nil {
+			entry.ArchivedAt = ac.manifest.ArchivedAt
+			entry.Recorded = true
+			for _, p := range ac.manifest.Patches {
+				if p.Spec == specName {
+					entry.Requirements = append(entry.Requirements, p.Requirements...)
+				}
+			}
+		} else {
+			entry.Requirements = deltaRequirementPatches(filepath.Join(ac.path, "specs", specName, "spec.md"))
+		}
+		if len(entry.Requirements) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].ArchivedAt.Before(entries[j].ArchivedAt) })
+	if requirement == "" {
+		return entries, nil
+	}
+
+	// Walk back in time so a rename adds the earlier name
+	names := map[string]bool{requirement: true}
+	var filtered []SpecHistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		var reqs []RequirementPatch
+		for _, r := range e.Requirements {
+			if names[r.Name] {
+				reqs = append(reqs, r)
+				if r.From != "" {
+					names[r.From] = true
+				}
+			}
+		}
+		if len(reqs) > 0 {
+			e.Requirements = reqs
+			filtered = append([]SpecHistoryEntry{e}, filtered...)
+		}
+	}
+	return filtered, nil
+}
+
+// deltaRequirementPatches reads the edits of an archived delta spec, without
+// the blocks before and after.
+func deltaRequirementPatches(path string) []RequirementPatch {
+	content, err := os.ReadFile(path) // #nosec G304 -- controlled file path
+	if err != nil {
+		return nil
+	}
+	delta, err := ParseDeltaSpec(string(content))
+	if err != nil {
+		return nil
+	}
+
+	var patches []RequirementPatch
+	for _, r := range delta.Renamed {
+		patches = append(patches, RequirementPatch{Op: OpRenamed, Name: r.ToName, From: r.FromName})
+	}
+	for _, r := range delta.Removed {
+		patches = append(patches, RequirementPatch{Op: OpRemoved, Name: r.Name})
+	}
+	for _, r := range delta.Modified {
+		patches = append(patches, RequirementPatch{Op: OpModified, Name: r.Name})
+	}
+	for _, r := range delta.Added {
+		patches = append(patches, RequirementPatch{Op: OpAdded, Name: r.Name})
+	}
+	return patches
+}
+
+// archivedChanges lists changes/archive, oldest first.
+func (a *Archiver) archivedChanges() ([]archivedChange, error) {
+	archiveDir := filepath.Join(a.store.SpecPath(), "changes", "archive")
+	entries, err := os.ReadDir(archiveDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read archive dir: %w", err)
+	}
+
+	var archives []archivedChange
+	for _, entry := range entries {
+		m := archiveNameRegex.FindStringSubmatch(entry.Name())
+		if !entry.IsDir() || m == nil {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", m[1])
+		if err != nil {
+			continue
+		}
+
+		ac := archivedChange{
+			name:     entry.Name(),
+			path:     filepath.Join(archiveDir, entry.Name()),
+			changeID: m[2],
+			date:     date,
+		}
+		data, err := os.ReadFile(filepath.Join(ac.path, ArchiveManifestFile)) // #nosec G304 -- controlled file path
+		switch {
+		case err == nil:
+			var manifest ArchiveManifest
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				return nil, fmt.Errorf("read %s manifest: %w", entry.Name(), err)
+			}
+			ac.manifest = &manifest
+		case !os.IsNotExist(err):
+			return nil, fmt.Errorf("read %s manifest: %w", entry.Name(), err)
+		}
+		archives = append(archives, ac)
+	}
+
+	// Directory names sort by date; the manifest time orders one day
+	sort.SliceStable(archives, func(i, j int) bool {
+		return archiveTime(archives[i]).Before(archiveTime(archives[j]))
+	})
+	return archives, nil
+}
+
+func archiveTime(ac archivedChange) time.Time {
+	if ac.manifest != nil {
+		return ac.manifest.ArchivedAt
+	}
+	return ac.date
+}
+
+// FormatSpecHistory renders a spec history one change per paragraph: +
+// added, ~ modified, - removed, → renamed.
+func FormatSpecHistory(specName string, entries []SpecHistoryEntry) string {
+	if len(entries) == 0 {
+		return fmt.Sprintf("No archived changes edited %s\n", specName)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("History of %s (%d archived change(s))\n", specName, len(entries)))
+	for _, e := range entries {
+		sb.WriteString(fmt.Sprintf("\n%s  %s", e.ArchivedAt.Format("2006-01-02"), e.ChangeID))
+		if !e.Recorded {
+			sb.WriteString("  (from delta, no patch)")
+		}
+		sb.WriteString("\n")
+		for _, r := range e.Requirements {
+			switch r.Op {
+			case OpAdded:
+				sb.WriteString("  + " + r.Name + "\n")
+			case OpModified:
+				sb.WriteString("  ~ " + r.Name + "\n")
+			case OpRemoved:
+				sb.WriteString("  - " + r.Name + "\n")
+			case OpRenamed:
+				sb.WriteString(fmt.Sprintf("  → %s (was %s)\n", r.Name, r.From))
+			}
+		}
+	}
+	return sb.String()
+}
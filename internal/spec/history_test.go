@@ -0,0 +1,202 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const historyBaseSpec = `# Auth Specification
+
+### Requirement: User login
+
+#### Scenario: Valid credentials
+- WHEN user provides valid credentials
+- THEN user is authenticated
+
+### Requirement: Session timeout
+
+#### Scenario: Idle session
+- WHEN a session is idle for 30 minutes
+- THEN it expires
+`
+
+func writeHistoryChange(t *testing.T, root, changeID, capability, delta string) {
+	t.Helper()
+
+	dir := filepath.Join(root, "openspec", "changes", changeID, "specs", capability)
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.md"), []byte(delta), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "openspec", "changes", changeID, "proposal.md"), []byte("# Proposal"), 0o600))
+}
+
+func TestArchiveHistoryAndUnarchive(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	specPath := filepath.Join(root, "openspec", "specs", "auth", "spec.md")
+	require.NoError(t, os.MkdirAll(filepath.Dir(specPath), 0o750))
+	require.NoError(t, os.WriteFile(specPath, []byte(historyBaseSpec), 0o600))
+
+	archiver := NewArchiver(NewStore(root))
+	readSpec := func() string {
+		content, err := os.ReadFile(specPath)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	writeHistoryChange(t, root, "add-2fa", "auth", `## ADDED Requirements
+
+### Requirement: Two-factor authentication
+
+#### Scenario: Enable 2FA
+- WHEN user enables 2FA
+- THEN login asks for a code
+
+## MODIFIED Requirements
+
+### Requirement: User login
+
+#### Scenario: Valid credentials
+- WHEN user provides valid credentials and a code
+- THEN user is authenticated
+`)
+	_, err := archiver.Archive("add-2fa", false, false, false)
+	require.NoError(t, err)
+	afterAdd2FA := readSpec()
+
+	writeHistoryChange(t, root, "rename-session", "auth", "## RENAMED Requirements\n\n- FROM: `### Requirement: Session timeout`\n- TO: `### Requirement: Idle timeout`\n")
+	_, err = archiver.Archive("rename-session", false, false, false)
+	require.NoError(t, err)
+
+	entries, err := archiver.History("auth", "")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "add-2fa", entries[0].ChangeID)
+	assert.True(t, entries[0].Recorded)
+	assert.Equal(t, []DeltaOperation{OpModified, OpAdded}, []DeltaOperation{entries[0].Requirements[0].Op, entries[0].Requirements[1].Op})
+	assert.Equal(t, "rename-session", entries[1].ChangeID)
+
+	entries, err = archiver.History("auth", "Idle timeout")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Session timeout", entries[0].Requirements[0].From)
+
+	text := FormatSpecHistory("auth", entries)
+	assert.Contains(t, text, "History of auth (1 archived change(s))")
+	assert.Contains(t, text, "rename-session\n  → Idle timeout (was Session timeout)")
+
+	// A later change edits the requirement add-2fa added
+	writeHistoryChange(t, root, "harden-2fa", "auth", `## MODIFIED Requirements
+
+### Requirement: Two-factor authentication
+
+#### Scenario: Enable 2FA
+- WHEN user enables 2FA
+- THEN login asks for a code from an authenticator app
+`)
+	_, err = archiver.Archive("harden-2fa", false, false, false)
+	require.NoError(t, err)
+
+	beforeUnarchive := readSpec()
+	result, err := archiver.Unarchive("add-2fa", false)
+	require.ErrorIs(t, err, ErrUnarchiveConflict)
+	require.Len(t, result.Conflicts, 2)
+	assert.Contains(t, result.Conflicts[0], "auth: modified Two-factor authentication later by harden-2fa")
+	assert.Contains(t, result.Conflicts[1], "requirement Two-factor authentication was edited since")
+	assert.Equal(t, beforeUnarchive, readSpec(), "a refused unarchive writes nothing")
+
+	// The latest change reverts exactly, after which add-2fa reverts
+	// around the rename that followed it
+	result, err = archiver.Unarchive("harden-2fa", true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth"}, result.RevertedSpecs)
+	assert.Equal(t, beforeUnarchive, readSpec(), "a dry run writes nothing")
+
+	_, err = archiver.Unarchive("harden-2fa", false)
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(root, "openspec", "changes", "harden-2fa", "specs", "auth", "spec.md"))
+	require.NoError(t, err)
+	_, err = os.Stat(filepath.Join(root, "openspec", "changes", "harden-2fa", ArchiveManifestFile))
+	assert.True(t, os.IsNotExist(err), "the manifest goes with the archive")
+	assert.NotEqual(t, afterAdd2FA, readSpec())
+
+	result, err = archiver.Unarchive("add-2fa", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"auth"}, result.RevertedSpecs)
+	spec := readSpec()
+	assert.NotContains(t, spec, "Two-factor authentication")
+	assert.Contains(t, spec, "- WHEN user provides valid credentials\n")
+	assert.Contains(t, spec, "### Requirement: Idle timeout")
+
+	_, err = archiver.Unarchive("add-2fa", false)
+	require.Error(t, err, "the change is back in changes/")
+
+	entries, err = archiver.History("auth", "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "rename-session", entries[0].ChangeID)
+}
+
+func TestUnarchiveCreatedSpec(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	archiver := NewArchiver(NewStore(root))
+
+	writeHistoryChange(t, root, "add-billing", "billing", `## ADDED Requirements
+
+### Requirement: Invoices
+
+#### Scenario: Monthly invoice
+- WHEN a month ends
+- THEN an invoice is sent
+`)
+	_, err := archiver.Archive("add-billing", false, false, false)
+	require.NoError(t, err)
+	billingDir := filepath.Join(root, "openspec", "specs", "billing")
+	_, err = os.Stat(filepath.Join(billingDir, "spec.md"))
+	require.NoError(t, err)
+
+	_, err = archiver.Unarchive("add-billing", false)
+	require.NoError(t, err)
+	_, err = os.Stat(billingDir)
+	assert.True(t, os.IsNotExist(err), "a spec the archive created is removed")
+
+	_, err = archiver.Unarchive("missing", false)
+	require.Error(t, err)
+}
+
+func TestHistoryWithoutManifest(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	archived := filepath.Join(root, "openspec", "changes", "archive", "2025-01-02-add-2fa", "specs", "auth")
+	require.NoError(t, os.MkdirAll(archived, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(archived, "spec.md"), []byte(`## ADDED Requirements
+
+### Requirement: Two-factor authentication
+
+#### Scenario: Enable 2FA
+- WHEN user enables 2FA
+- THEN login asks for a code
+`), 0o600))
+
+	archiver := NewArchiver(NewStore(root))
+	entries, err := archiver.History("auth", "")
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.False(t, entries[0].Recorded)
+	assert.Equal(t, "2025-01-02", entries[0].ArchivedAt.Format("2006-01-02"))
+	assert.Contains(t, FormatSpecHistory("auth", entries), "2025-01-02  add-2fa  (from delta, no patch)\n  + Two-factor authentication")
+
+	_, err = archiver.Unarchive("add-2fa", false)
+	require.ErrorIs(t, err, ErrNoArchivePatch)
+
+	assert.Equal(t, "No archived changes edited billing\n", FormatSpecHistory("billing", nil))
+}
@@ -0,0 +1,262 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// DefaultLeaseTTL is how long a claim lasts without a heartbeat.
+const DefaultLeaseTTL = 15 * time.Minute
+
+var (
+	ErrLeaseHeld        = errors.New("task is leased by another holder")
+	ErrLeaseNotHeld     = errors.New("lease not held")
+	ErrTaskNotClaimable = errors.New("task cannot be claimed")
+	ErrNoClaimableTask  = errors.New("no claimable task")
+)
+
+// TaskLease records which worker or agent is working on a task. A lease
+// that is not renewed by a heartbeat before ExpiresAt can be stolen.
+type TaskLease struct {
+	Holder      string        `yaml:"holder" json:"holder"`
+	ClaimedAt   time.Time     `yaml:"claimed_at" json:"claimed_at"`
+	HeartbeatAt time.Time     `yaml:"heartbeat_at" json:"heartbeat_at"`
+	ExpiresAt   time.Time     `yaml:"expires_at" json:"expires_at"`
+	TTL         time.Duration `yaml:"ttl" json:"ttl"`
+	// StolenFrom is the holder of the expired lease this one replaced.
+	StolenFrom string `yaml:"stolen_from,omitempty" json:"stolen_from,omitempty"`
+}
+
+// Active reports whether the lease is held at now.
+func (l *TaskLease) Active(now time.Time) bool {
+	return l != nil && now.Before(l.ExpiresAt)
+}
+
+// ClaimTask leases a pending, unblocked task to holder and marks it in
+// progress. Claiming a task the holder already leases renews the lease.
+func (s *BoltStore) ClaimTask(id TaskID, holder string, ttl time.Duration, now time.Time) (*RegistryTask, error) {
+	var claimed *RegistryTask
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		task, err := getTaskTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case task.Lease.Active(now) && task.Lease.Holder != holder:
+			return fmt.Errorf("%w: %s held by %s until %s", ErrLeaseHeld, id, task.Lease.Holder, task.Lease.ExpiresAt.Format(time.RFC3339))
+		case task.Lease.Active(now):
+			// Renewal by the current holder.
+		case task.Status != RegStatusPending:
+			return fmt.Errorf("%w: %s is %s", ErrTaskNotClaimable, id, task.Status)
+		case len(task.BlockedBy) > 0:
+			return fmt.Errorf("%w: %s is blocked by %d task(s)", ErrTaskNotClaimable, id, len(task.BlockedBy))
+		}
+
+		claimed = lease(task, holder, ttl, now)
+		return s.putTaskTx(tx, claimed)
+	})
+	return claimed, err
+}
+
+// ClaimNext atomically leases the highest-priority claimable task: a
+// pending unblocked task, or an in-progress one whose lease expired. An
+// empty changeID considers every change.
+func (s *BoltStore) ClaimNext(holder, changeID string, ttl time.Duration, now time.Time) (*RegistryTask, error) {
+	var claimed *RegistryTask
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var candidates []RegistryTask
+		err := tx.Bucket([]byte(BucketTasks)).ForEach(func(k, v []byte) error {
+			var task RegistryTask
+			if err := json.Unmarshal(v, &task); err != nil {
+				return err
+			}
+			if changeID != "" && task.ID.ChangeID != changeID {
+				return nil
+			}
+			if claimable(&task, now) {
+				candidates = append(candidates, task)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(candidates) == 0 {
+			return ErrNoClaimableTask
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].Priority != candidates[j].Priority {
+				return priorityValue(candidates[i].Priority) < priorityValue(candidates[j].Priority)
+			}
+			return candidates[i].ID.String() < candidates[j].ID.String()
+		})
+
+		claimed = lease(&candidates[0], holder, ttl, now)
+		return s.putTaskTx(tx, claimed)
+	})
+	return claimed, err
+}
+
+// Heartbeat extends the holder's lease by its TTL.
+func (s *BoltStore) Heartbeat(id TaskID, holder string, now time.Time) (*RegistryTask, error) {
+	var task *RegistryTask
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		task, err = getTaskTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkHolder(task, holder, now); err != nil {
+			return err
+		}
+
+		task.Lease.HeartbeatAt = now
+		task.Lease.ExpiresAt = now.Add(task.Lease.TTL)
+		return s.putTaskTx(tx, task)
+	})
+	return task, err
+}
+
+// ReleaseTask drops the holder's lease and assignment. A task still in
+// progress goes back to pending so another holder can claim it.
+func (s *BoltStore) ReleaseTask(id TaskID, holder string, now time.Time) (*RegistryTask, error) {
+	var task *RegistryTask
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		var err error
+		task, err = getTaskTx(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkHolder(task, holder, now); err != nil {
+			return err
+		}
+
+		task.Lease = nil
+		if task.Assignee == holder {
+			task.Assignee = ""
+		}
+		if task.Status == RegStatusInProgress {
+			task.Status = RegStatusPending
+			task.StartedAt = nil
+		}
+		return s.putTaskTx(tx, task)
+	})
+	return task, err
+}
+
+// StealTask takes over a task whose lease expired, or an in-progress task
+// nobody leases, keeping its progress.
+func (s *BoltStore) StealTask(id TaskID, holder string, ttl time.Duration, now time.Time) (*RegistryTask, error) {
+	var stolen *RegistryTask
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		task, err := getTaskTx(tx, id)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case task.Lease.Active(now):
+			return fmt.Errorf("%w: %s held by %s until %s", ErrLeaseHeld, id, task.Lease.Holder, task.Lease.ExpiresAt.Format(time.RFC3339))
+		case task.Status != RegStatusInProgress:
+			return fmt.Errorf("%w: %s is %s, claim it instead", ErrTaskNotClaimable, id, task.Status)
+		}
+
+		stolen = lease(task, holder, ttl, now)
+		return s.putTaskTx(tx, stolen)
+	})
+	return stolen, err
+}
+
+// ListLeases returns tasks that carry a lease, expired ones included,
+// ordered by task ID.
+func (s *BoltStore) ListLeases() ([]RegistryTask, error) {
+	tasks, err := s.ListTasks(TaskFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	var leased []RegistryTask
+	for _, t := range tasks {
+		if t.Lease != nil {
+			leased = append(leased, t)
+		}
+	}
+	sort.Slice(leased, func(i, j int) bool { return leased[i].ID.String() < leased[j].ID.String() })
+	return leased, nil
+}
+
+func claimable(task *RegistryTask, now time.Time) bool {
+	switch task.Status {
+	case RegStatusPending:
+		return len(task.BlockedBy) == 0 && !task.Lease.Active(now)
+	case RegStatusInProgress:
+		return task.Lease != nil && !task.Lease.Active(now)
+	}
+	return false
+}
+
+// lease sets a new lease on task and makes holder its assignee, recording
+// the previous holder when an expired lease of someone else is replaced.
+func lease(task *RegistryTask, holder string, ttl time.Duration, now time.Time) *RegistryTask {
+	if ttl <= 0 {
+		ttl = DefaultLeaseTTL
+	}
+
+	next := &TaskLease{Holder: holder, ClaimedAt: now, HeartbeatAt: now, ExpiresAt: now.Add(ttl), TTL: ttl}
+	if prev := task.Lease; prev != nil {
+		if prev.Holder == holder {
+			next.ClaimedAt = prev.ClaimedAt
+			next.StolenFrom = prev.StolenFrom
+		} else {
+			next.StolenFrom = prev.Holder
+		}
+	}
+
+	task.Lease = next
+	task.Assignee = holder
+	if task.Status != RegStatusInProgress {
+		task.Status = RegStatusInProgress
+		task.StartedAt = &now
+	}
+	return task
+}
+
+func checkHolder(task *RegistryTask, holder string, now time.Time) error {
+	if task.Lease == nil || task.Lease.Holder != holder {
+		return fmt.Errorf("%w: %s is not leased by %s", ErrLeaseNotHeld, task.ID, holder)
+	}
+	if !task.Lease.Active(now) {
+		return fmt.Errorf("%w: lease of %s on %s expired at %s", ErrLeaseNotHeld, holder, task.ID, task.Lease.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func getTaskTx(tx *bbolt.Tx, id TaskID) (*RegistryTask, error) {
+	data := tx.Bucket([]byte(BucketTasks)).Get([]byte(id.String()))
+	if data == nil {
+		return nil, fmt.Errorf("task not found: %s", id)
+	}
+	var task RegistryTask
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("unmarshal task: %w", err)
+	}
+	return &task, nil
+}
+
+func (s *BoltStore) putTaskTx(tx *bbolt.Tx, task *RegistryTask) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("marshal task: %w", err)
+	}
+	if err := tx.Bucket([]byte(BucketTasks)).Put([]byte(task.ID.String()), data); err != nil {
+		return fmt.Errorf("put task: %w", err)
+	}
+	return s.updateChangeSummary(tx, task.ID.ChangeID)
+}
@@ -0,0 +1,255 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoltStore_ClaimTask(t *testing.T) {
+	t.Parallel()
+
+	s := setupBoltStore(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	task := createTestTask("1", "change-1", RegStatusPending, PriorityHigh)
+	require.NoError(t, s.UpdateTask(task))
+	blocked := createTestTask("2", "change-1", RegStatusPending, PriorityHigh)
+	blocked.BlockedBy = []TaskID{task.ID}
+	require.NoError(t, s.UpdateTask(blocked))
+
+	claimed, err := s.ClaimTask(task.ID, "agent-a", time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusInProgress, claimed.Status)
+	assert.Equal(t, "agent-a", claimed.Lease.Holder)
+	assert.Equal(t, now.Add(time.Minute), claimed.Lease.ExpiresAt)
+	assert.Equal(t, "agent-a", claimed.Assignee)
+
+	_, err = s.ClaimTask(task.ID, "agent-b", time.Minute, now.Add(30*time.Second))
+	assert.ErrorIs(t, err, ErrLeaseHeld)
+
+	renewed, err := s.ClaimTask(task.ID, "agent-a", time.Minute, now.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, now, renewed.Lease.ClaimedAt)
+	assert.Equal(t, now.Add(90*time.Second), renewed.Lease.ExpiresAt)
+
+	_, err = s.ClaimTask(blocked.ID, "agent-b", time.Minute, now)
+	assert.ErrorIs(t, err, ErrTaskNotClaimable)
+
+	summary, err := s.GetChangeSummary("change-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, summary.InProgress)
+}
+
+func TestBoltStore_LeaseLifecycle(t *testing.T) {
+	t.Parallel()
+
+	s := setupBoltStore(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	task := createTestTask("1", "change-1", RegStatusPending, PriorityMedium)
+	require.NoError(t, s.UpdateTask(task))
+
+	_, err := s.ClaimTask(task.ID, "agent-a", time.Minute, now)
+	require.NoError(t, err)
+
+	_, err = s.Heartbeat(task.ID, "agent-b", now)
+	assert.ErrorIs(t, err, ErrLeaseNotHeld)
+
+	beat, err := s.Heartbeat(task.ID, "agent-a", now.Add(50*time.Second))
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(110*time.Second), beat.Lease.ExpiresAt)
+
+	_, err = s.StealTask(task.ID, "agent-b", time.Minute, now.Add(100*time.Second))
+	assert.ErrorIs(t, err, ErrLeaseHeld)
+
+	stolen, err := s.StealTask(task.ID, "agent-b", time.Minute, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "agent-b", stolen.Lease.Holder)
+	assert.Equal(t, "agent-a", stolen.Lease.StolenFrom)
+	assert.Equal(t, RegStatusInProgress, stolen.Status)
+
+	_, err = s.Heartbeat(task.ID, "agent-a", now.Add(2*time.Minute))
+	assert.ErrorIs(t, err, ErrLeaseNotHeld)
+
+	released, err := s.ReleaseTask(task.ID, "agent-b", now.Add(150*time.Second))
+	require.NoError(t, err)
+	assert.Nil(t, released.Lease)
+	assert.Equal(t, RegStatusPending, released.Status)
+
+	_, err = s.StealTask(task.ID, "agent-c", time.Minute, now.Add(3*time.Minute))
+	assert.ErrorIs(t, err, ErrTaskNotClaimable)
+}
+
+func TestBoltStore_ClaimNext(t *testing.T) {
+	t.Parallel()
+
+	s := setupBoltStore(t)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	require.NoError(t, s.UpdateTask(createTestTask("1", "change-1", RegStatusPending, PriorityLow)))
+	require.NoError(t, s.UpdateTask(createTestTask("2", "change-1", RegStatusPending, PriorityCritical)))
+	require.NoError(t, s.UpdateTask(createTestTask("1", "change-2", RegStatusPending, PriorityHigh)))
+
+	first, err := s.ClaimNext("agent-a", "", time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, "change-1/2", first.ID.String())
+
+	second, err := s.ClaimNext("agent-b", "change-1", time.Minute, now)
+	require.NoError(t, err)
+	assert.Equal(t, "change-1/1", second.ID.String())
+
+	_, err = s.ClaimNext("agent-c", "change-1", time.Minute, now)
+	assert.ErrorIs(t, err, ErrNoClaimableTask)
+
+	// Once agent-a's lease lapses its critical task is the best candidate again.
+	stolen, err := s.ClaimNext("agent-c", "change-1", time.Minute, now.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, "change-1/2", stolen.ID.String())
+	assert.Equal(t, "agent-a", stolen.Lease.StolenFrom)
+}
+
+func TestBoltStore_ClaimNext_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	s := setupBoltStore(t)
+	for _, num := range []string{"1", "2", "3"} {
+		require.NoError(t, s.UpdateTask(createTestTask(num, "change-1", RegStatusPending, PriorityMedium)))
+	}
+
+	var (
+		mu      sync.Mutex
+		claimed = make(map[string]string)
+		wg      sync.WaitGroup
+	)
+	for i := range 8 {
+		wg.Add(1)
+		go func(holder string) {
+			defer wg.Done()
+			task, err := s.ClaimNext(holder, "", time.Minute, time.Now())
+			if errors.Is(err, ErrNoClaimableTask) {
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			_, dup := claimed[task.ID.String()]
+			assert.False(t, dup, "task %s claimed twice", task.ID)
+			claimed[task.ID.String()] = holder
+		}(string(rune('a' + i)))
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, 3)
+}
+
+func TestRegistryStore_LeasesSurviveSyncAndShowInState(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n"), 0600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Close() })
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	tasks, err := reg.ListTasks(TaskFilter{ChangeID: "add-sso"})
+	require.NoError(t, err)
+	require.NotEmpty(t, tasks)
+	id := tasks[0].ID
+
+	_, err = reg.ClaimTask(id, "worker-1", time.Hour)
+	require.NoError(t, err)
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	task, err := reg.GetTask(id)
+	require.NoError(t, err)
+	require.NotNil(t, task.Lease)
+	assert.Equal(t, "worker-1", task.Lease.Holder)
+	assert.Equal(t, RegStatusInProgress, task.Status)
+
+	statePath := filepath.Join(root, "state.md")
+	require.NoError(t, reg.StateStore().WriteRootStateMd(statePath))
+	content, err := os.ReadFile(statePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## Leases")
+	assert.Contains(t, string(content), "| "+id.String()+" | worker-1 | until ")
+
+	changeStatePath := filepath.Join(root, "change-state.md")
+	require.NoError(t, reg.StateStore().WriteChangeStateMd("add-sso", changeStatePath))
+	content, err = os.ReadFile(changeStatePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "(held by worker-1, until ")
+
+	completed := RegStatusCompleted
+	require.NoError(t, reg.UpdateTask(id, TaskUpdate{Status: &completed}))
+	task, err = reg.GetTask(id)
+	require.NoError(t, err)
+	assert.Nil(t, task.Lease, "completing a task ends its lease")
+}
+
+func TestRegistryStore_ExpiredLeaseDroppedOnSync(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(changeDir, "tasks.md"), []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n"), 0600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reg.Close() })
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	tasks, err := reg.ListTasks(TaskFilter{ChangeID: "add-sso"})
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	_, err = reg.bolt.ClaimTask(tasks[0].ID, "worker-1", time.Minute, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = reg.ClaimTask(tasks[1].ID, "worker-2", time.Hour)
+	require.NoError(t, err)
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	expired, err := reg.GetTask(tasks[0].ID)
+	require.NoError(t, err)
+	assert.Nil(t, expired.Lease, "an expired lease does not survive a rebuild")
+	assert.Equal(t, RegStatusPending, expired.Status)
+	assert.Empty(t, expired.Assignee)
+	assert.Nil(t, expired.StartedAt)
+
+	held, err := reg.GetTask(tasks[1].ID)
+	require.NoError(t, err)
+	require.NotNil(t, held.Lease)
+	assert.Equal(t, RegStatusInProgress, held.Status)
+	assert.Equal(t, "worker-2", held.Assignee)
+
+	released, err := reg.ReleaseTask(tasks[1].ID, "worker-2")
+	require.NoError(t, err)
+	assert.Empty(t, released.Assignee, "releasing a lease ends the assignment")
+
+	claimed, err := reg.ClaimTask(tasks[0].ID, "worker-3", time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "worker-3", claimed.Assignee)
+}
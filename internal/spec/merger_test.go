@@ -359,6 +359,44 @@ func TestMergeDeltas_Complex(t *testing.T) {
 	assert.Contains(t, result, "Stay")
 }
 
+func TestMergeDeltas_Lossless(t *testing.T) {
+	t.Parallel()
+
+	baseSpec := "# Test Spec\n\n" +
+		"### Requirement: Documented   \n\n" +
+		"Example:\n\n" +
+		"```markdown\n" +
+		"### Requirement: Example inside a fence\n" +
+		"```\n\n" +
+		"#### Scenario: Usage\n" +
+		"-   **WHEN**   spaced oddly\n" +
+		"- **THEN** kept as written\n\n" +
+		"### Requirement: To modify\n\n" +
+		"#### Scenario: Old\n" +
+		"- WHEN old\n" +
+		"- THEN old\n\n" +
+		"### Requirement: Tail\n\n" +
+		"#### Scenario: Tail\n" +
+		"- WHEN tail\n" +
+		"- THEN tail\n"
+
+	delta := &DeltaSpec{
+		Modified: []Requirement{{
+			Name:    "To modify",
+			Content: "### Requirement: To modify\n\n#### Scenario: New\n- WHEN new\n- THEN new",
+		}},
+		Removed: []RemovedRequirement{{Name: "Example inside a fence", Reason: "not a requirement"}},
+	}
+
+	result, err := MergeDeltas(baseSpec, delta)
+	require.NoError(t, err)
+
+	want := strings.Replace(baseSpec,
+		"#### Scenario: Old\n- WHEN old\n- THEN old\n",
+		"#### Scenario: New\n- WHEN new\n- THEN new\n", 1)
+	assert.Equal(t, want, result, "only the modified requirement changes")
+}
+
 func TestRenameRequirement(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,494 @@
+package spec
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTaskEstimate is the effort in hours assumed for a task whose
+// tasks.md line has no <!-- estimate: ... --> comment.
+const DefaultTaskEstimate = 1.0
+
+// planEpsilon absorbs float error when comparing schedule times.
+const planEpsilon = 1e-9
+
+// PlanOptions controls BuildPlan.
+type PlanOptions struct {
+	// ChangeID limits the schedule to one change. Throughput is always
+	// measured over every completed task.
+	ChangeID string
+	// DefaultEstimate replaces DefaultTaskEstimate for unestimated tasks.
+	DefaultEstimate float64
+	Now             time.Time
+}
+
+// PlannedTask is a task placed on the schedule. Times are working hours
+// from the start of the plan.
+type PlannedTask struct {
+	ID          TaskID             `json:"id"`
+	Content     string             `json:"content"`
+	Status      RegistryTaskStatus `json:"status"`
+	DependsOn   []TaskID           `json:"depends_on,omitempty"`
+	Estimate    float64            `json:"estimate_hours"`
+	Estimated   bool               `json:"estimated"`
+	Remaining   float64            `json:"remaining_hours"`
+	EarlyStart  float64            `json:"early_start"`
+	EarlyFinish float64            `json:"early_finish"`
+	LateStart   float64            `json:"late_start"`
+	LateFinish  float64            `json:"late_finish"`
+	Slack       float64            `json:"slack"`
+	Critical    bool               `json:"critical"`
+}
+
+// Throughput is the rate at which estimated effort got completed.
+type Throughput struct {
+	Completed   int       `json:"completed"`
+	Since       time.Time `json:"since"`
+	Until       time.Time `json:"until"`
+	HoursPerDay float64   `json:"hours_per_day"`
+	TasksPerDay float64   `json:"tasks_per_day"`
+}
+
+// Plan is a critical-path schedule of the remaining tasks.
+type Plan struct {
+	ChangeID     string        `json:"change_id,omitempty"`
+	GeneratedAt  time.Time     `json:"generated_at"`
+	Tasks        []PlannedTask `json:"tasks"`
+	CriticalPath []TaskID      `json:"critical_path"`
+	// Duration is the length of the critical path in working hours.
+	Duration float64 `json:"duration_hours"`
+	// Remaining is the total effort left, ignoring parallelism.
+	Remaining   float64 `json:"remaining_hours"`
+	Completed   int     `json:"completed"`
+	Unestimated int     `json:"unestimated"`
+	// DefaultEstimate is the effort assumed for each unestimated task.
+	DefaultEstimate float64     `json:"default_estimate_hours"`
+	Throughput      *Throughput `json:"throughput,omitempty"`
+	// ProjectedCompletion is Remaining spread at the historical throughput.
+	ProjectedCompletion *time.Time `json:"projected_completion,omitempty"`
+}
+
+// Plan schedules the tasks in the registry.
+func (r *RegistryStore) Plan(opts PlanOptions) (*Plan, error) {
+	tasks, err := r.bolt.ListTasks(TaskFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	return BuildPlan(tasks, opts)
+}
+
+// BuildPlan runs the critical path method over tasks: a forward pass gives
+// the earliest start and finish of each task, a backward pass the latest,
+// and the difference is the slack. Completed and skipped tasks take no time.
+// Dependencies on tasks outside the plan are treated as done.
+func BuildPlan(tasks []RegistryTask, opts PlanOptions) (*Plan, error) {
+	defaultEstimate := opts.DefaultEstimate
+	if defaultEstimate <= 0 {
+		defaultEstimate = DefaultTaskEstimate
+	}
+
+	plan := &Plan{
+		ChangeID:        opts.ChangeID,
+		GeneratedAt:     opts.Now,
+		DefaultEstimate: defaultEstimate,
+		Tasks:           make([]PlannedTask, 0),
+		CriticalPath:    make([]TaskID, 0),
+		Throughput:      measureThroughput(tasks, defaultEstimate, opts.Now),
+	}
+
+	index := make(map[string]int)
+	for _, t := range tasks {
+		if opts.ChangeID != "" && t.ID.ChangeID != opts.ChangeID {
+			continue
+		}
+
+		pt := PlannedTask{
+			ID:        t.ID,
+			Content:   t.Content,
+			Status:    t.Status,
+			Estimate:  t.Estimate,
+			Estimated: t.Estimate > 0,
+		}
+		if !pt.Estimated {
+			pt.Estimate = defaultEstimate
+		}
+
+		switch t.Status {
+		case RegStatusCompleted, RegStatusSkipped:
+			plan.Completed++
+		default:
+			pt.Remaining = pt.Estimate
+			plan.Remaining += pt.Remaining
+			if !pt.Estimated {
+				plan.Unestimated++
+			}
+		}
+
+		index[t.ID.String()] = len(plan.Tasks)
+		plan.Tasks = append(plan.Tasks, pt)
+	}
+
+	for _, t := range tasks {
+		j, ok := index[t.ID.String()]
+		if !ok {
+			continue
+		}
+		for _, dep := range t.DependsOn {
+			if _, ok := index[dep.String()]; ok {
+				plan.Tasks[j].DependsOn = append(plan.Tasks[j].DependsOn, dep)
+			}
+		}
+	}
+
+	order, err := topoOrder(plan.Tasks, index)
+	if err != nil {
+		return nil, err
+	}
+
+	successors := make(map[int][]int)
+	for _, i := range order {
+		pt := &plan.Tasks[i]
+		for _, dep := range pt.DependsOn {
+			d := index[dep.String()]
+			successors[d] = append(successors[d], i)
+			pt.EarlyStart = math.Max(pt.EarlyStart, plan.Tasks[d].EarlyFinish)
+		}
+		pt.EarlyFinish = pt.EarlyStart + pt.Remaining
+		plan.Duration = math.Max(plan.Duration, pt.EarlyFinish)
+	}
+
+	for k := len(order) - 1; k >= 0; k-- {
+		pt := &plan.Tasks[order[k]]
+		pt.LateFinish = plan.Duration
+		for _, s := range successors[order[k]] {
+			pt.LateFinish = math.Min(pt.LateFinish, plan.Tasks[s].LateStart)
+		}
+		pt.LateStart = pt.LateFinish - pt.Remaining
+		pt.Slack = pt.LateStart - pt.EarlyStart
+		if pt.Slack < planEpsilon {
+			pt.Slack = 0
+		}
+		pt.Critical = pt.Remaining > 0 && pt.Slack == 0
+	}
+
+	plan.CriticalPath = criticalPath(plan.Tasks, index, plan.Duration)
+
+	sort.SliceStable(plan.Tasks, func(i, j int) bool {
+		a, b := plan.Tasks[i], plan.Tasks[j]
+		if a.EarlyStart != b.EarlyStart {
+			return a.EarlyStart < b.EarlyStart
+		}
+		return taskIDLess(a.ID, b.ID)
+	})
+
+	if tp := plan.Throughput; tp != nil && tp.HoursPerDay > 0 {
+		days := plan.Remaining / tp.HoursPerDay
+		projected := opts.Now.Add(time.Duration(days * 24 * float64(time.Hour)))
+		plan.ProjectedCompletion = &projected
+	}
+
+	return plan, nil
+}
+
+// topoOrder sorts tasks so every task follows its dependencies, breaking
+// ties by task ID. It fails on dependency cycles.
+func topoOrder(tasks []PlannedTask, index map[string]int) ([]int, error) {
+	indegree := make([]int, len(tasks))
+	dependents := make(map[int][]int)
+	for i, t := range tasks {
+		for _, dep := range t.DependsOn {
+			d := index[dep.String()]
+			indegree[i]++
+			dependents[d] = append(dependents[d], i)
+		}
+	}
+
+	var ready []int
+	for i := range tasks {
+		if indegree[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]int, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Slice(ready, func(a, b int) bool { return taskIDLess(tasks[ready[a]].ID, tasks[ready[b]].ID) })
+		i := ready[0]
+		ready = ready[1:]
+		order = append(order, i)
+		for _, d := range dependents[i] {
+			indegree[d]--
+			if indegree[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) < len(tasks) {
+		var cycle []string
+		for i, n := range indegree {
+			if n > 0 {
+				cycle = append(cycle, tasks[i].ID.String())
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle among %s", strings.Join(cycle, ", "))
+	}
+	return order, nil
+}
+
+// criticalPath walks back from the critical task finishing last through
+// critical dependencies that finish exactly when their dependent starts.
+func criticalPath(tasks []PlannedTask, index map[string]int, duration float64) []TaskID {
+	var current *PlannedTask
+	for i := range tasks {
+		t := &tasks[i]
+		if !t.Critical || math.Abs(t.EarlyFinish-duration) > planEpsilon {
+			continue
+		}
+		if current == nil || taskIDLess(t.ID, current.ID) {
+			current = t
+		}
+	}
+
+	path := make([]TaskID, 0)
+	for current != nil {
+		path = append(path, current.ID)
+		var prev *PlannedTask
+		for _, dep := range current.DependsOn {
+			d := &tasks[index[dep.String()]]
+			if !d.Critical || math.Abs(d.EarlyFinish-current.EarlyStart) > planEpsilon {
+				continue
+			}
+			if prev == nil || taskIDLess(d.ID, prev.ID) {
+				prev = d
+			}
+		}
+		current = prev
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// measureThroughput divides the estimated effort of completed tasks by the
+// calendar days from the first of them starting until now. The window is at
+// least one day so a burst of completions does not inflate the rate.
+func measureThroughput(tasks []RegistryTask, defaultEstimate float64, now time.Time) *Throughput {
+	tp := &Throughput{Until: now}
+	var hours float64
+	for _, t := range tasks {
+		if t.Status != RegStatusCompleted || t.CompletedAt == nil {
+			continue
+		}
+		start := *t.CompletedAt
+		if t.StartedAt != nil && t.StartedAt.Before(start) {
+			start = *t.StartedAt
+		}
+		if tp.Completed == 0 || start.Before(tp.Since) {
+			tp.Since = start
+		}
+		tp.Completed++
+		if t.Estimate > 0 {
+			hours += t.Estimate
+		} else {
+			hours += defaultEstimate
+		}
+	}
+	if tp.Completed == 0 {
+		return nil
+	}
+
+	days := math.Max(now.Sub(tp.Since).Hours()/24, 1)
+	tp.HoursPerDay = hours / days
+	tp.TasksPerDay = float64(tp.Completed) / days
+	return tp
+}
+
+// taskIDLess orders task IDs by change, then numerically by task number.
+func taskIDLess(a, b TaskID) bool {
+	if a.ChangeID != b.ChangeID {
+		return a.ChangeID < b.ChangeID
+	}
+	na, errA := strconv.Atoi(a.TaskNum)
+	nb, errB := strconv.Atoi(b.TaskNum)
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a.TaskNum < b.TaskNum
+}
+
+// ganttWidth is the number of columns the text Gantt chart spans.
+const ganttWidth = 40
+
+// FormatPlan renders the plan as a summary and a text Gantt chart of the
+// remaining tasks. Critical tasks are drawn with █, others with ▒ followed by
+// their slack as ·.
+func FormatPlan(plan *Plan) string {
+	var sb strings.Builder
+
+	scope := "all changes"
+	if plan.ChangeID != "" {
+		scope = plan.ChangeID
+	}
+	remaining := len(plan.Tasks) - plan.Completed
+	sb.WriteString(fmt.Sprintf("Plan for %s: %d task(s) remaining, %d done\n", scope, remaining, plan.Completed))
+	sb.WriteString(fmt.Sprintf("Effort: %s remaining, critical path %s\n", formatHours(plan.Remaining), formatHours(plan.Duration)))
+	if plan.Unestimated > 0 {
+		sb.WriteString(fmt.Sprintf("Unestimated: %d task(s), assumed %s each\n", plan.Unestimated, formatHours(plan.DefaultEstimate)))
+	}
+
+	switch tp := plan.Throughput; {
+	case tp == nil:
+		sb.WriteString("Throughput: no completed task history, no projection\n")
+	default:
+		sb.WriteString(fmt.Sprintf("Throughput: %s/day over %d completed task(s) since %s\n",
+			formatHours(tp.HoursPerDay), tp.Completed, tp.Since.Format("2006-01-02")))
+	}
+	if plan.ProjectedCompletion != nil {
+		sb.WriteString(fmt.Sprintf("Projected completion: %s\n", plan.ProjectedCompletion.Format("2006-01-02")))
+	}
+
+	if remaining == 0 {
+		return sb.String()
+	}
+
+	if len(plan.CriticalPath) > 0 {
+		ids := make([]string, len(plan.CriticalPath))
+		for i, id := range plan.CriticalPath {
+			ids[i] = id.String()
+		}
+		sb.WriteString(fmt.Sprintf("Critical path: %s\n", strings.Join(ids, " → ")))
+	}
+	sb.WriteString("\n")
+
+	width := 0
+	for _, t := range plan.Tasks {
+		if t.Remaining > 0 && len(t.ID.String()) > width {
+			width = len(t.ID.String())
+		}
+	}
+
+	for _, t := range plan.Tasks {
+		if t.Remaining == 0 {
+			continue
+		}
+		marker := " "
+		if t.Critical {
+			marker = "*"
+		}
+		sb.WriteString(fmt.Sprintf("%s %-*s |%s| %6s → %6s  slack %-6s %s\n",
+			marker, width, t.ID.String(), ganttBar(t, plan.Duration),
+			formatHours(t.EarlyStart), formatHours(t.EarlyFinish), formatHours(t.Slack), truncateLabel(t.Content, 50)))
+	}
+	return sb.String()
+}
+
+func ganttBar(t PlannedTask, duration float64) string {
+	col := func(hours float64) int {
+		if duration <= 0 {
+			return 0
+		}
+		return int(math.Round(hours / duration * ganttWidth))
+	}
+
+	start, end, late := col(t.EarlyStart), col(t.EarlyFinish), col(t.LateFinish)
+	if end <= start {
+		end = start + 1
+	}
+	if end > ganttWidth {
+		start, end = ganttWidth-1, ganttWidth
+	}
+	if late < end {
+		late = end
+	}
+
+	fill := "▒"
+	if t.Critical {
+		fill = "█"
+	}
+	return strings.Repeat(" ", start) + strings.Repeat(fill, end-start) +
+		strings.Repeat("·", late-end) + strings.Repeat(" ", ganttWidth-late)
+}
+
+// FormatPlanMermaid renders the remaining tasks as a Mermaid gantt diagram
+// starting at the plan's generation time, one section per change.
+func FormatPlanMermaid(plan *Plan) string {
+	var sb strings.Builder
+
+	title := "OpenSpec plan"
+	if plan.ChangeID != "" {
+		title += ": " + plan.ChangeID
+	}
+	sb.WriteString("gantt\n")
+	sb.WriteString("    title " + mermaidLabel(title) + "\n")
+	sb.WriteString("    dateFormat YYYY-MM-DD HH:mm\n")
+	sb.WriteString("    axisFormat %m-%d\n")
+
+	section := ""
+	tasks := make([]PlannedTask, 0, len(plan.Tasks))
+	for _, t := range plan.Tasks {
+		if t.Remaining > 0 {
+			tasks = append(tasks, t)
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].ID.ChangeID < tasks[j].ID.ChangeID })
+
+	for _, t := range tasks {
+		if t.ID.ChangeID != section {
+			section = t.ID.ChangeID
+			sb.WriteString("    section " + mermaidLabel(section) + "\n")
+		}
+
+		start := plan.GeneratedAt.Add(time.Duration(t.EarlyStart * float64(time.Hour)))
+		tags := ""
+		if t.Critical {
+			tags = "crit, "
+		}
+		sb.WriteString(fmt.Sprintf("    %s :%s%s, %s, %s\n",
+			mermaidLabel(t.ID.String()+" "+truncateLabel(t.Content, 50)), tags, mermaidID(t.ID),
+			start.Format("2006-01-02 15:04"), mermaidDuration(t.Remaining)))
+	}
+	return sb.String()
+}
+
+func mermaidID(id TaskID) string {
+	return strings.NewReplacer("/", "_", "-", "_", ".", "_").Replace(id.String())
+}
+
+// mermaidLabel drops characters that end a Mermaid gantt task name.
+func mermaidLabel(s string) string {
+	return strings.TrimSpace(strings.NewReplacer(":", " ", ";", " ", "#", "").Replace(s))
+}
+
+func mermaidDuration(hours float64) string {
+	minutes := int(math.Round(hours * 60))
+	if minutes < 1 {
+		minutes = 1
+	}
+	if minutes%60 == 0 {
+		return fmt.Sprintf("%dh", minutes/60)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+func formatHours(h float64) string {
+	return strconv.FormatFloat(h, 'f', 1, 64) + "h"
+}
+
+func truncateLabel(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,226 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEstimate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		line string
+		want float64
+	}{
+		{"<!-- estimate: 4h -->", 4},
+		{"<!-- estimate: 1.5h -->", 1.5},
+		{"<!-- estimate: 30m -->", 0.5},
+		{"<!-- estimate: 2d -->", 16},
+		{"<!-- estimate: 1w -->", 40},
+		{"<!-- estimate: 3 -->", 3},
+		{"<!-- depends: 1 --> <!-- estimate: 2H -->", 2},
+		{"<!-- estimate: soon -->", 0},
+		{"<!-- depends: 1 -->", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			t.Parallel()
+			assert.InDelta(t, tt.want, ParseEstimate(tt.line), 1e-9)
+		})
+	}
+}
+
+func planTask(num string, status RegistryTaskStatus, estimate float64, deps ...string) RegistryTask {
+	task := RegistryTask{
+		ID:       TaskID{ChangeID: "add-sso", TaskNum: num},
+		Content:  "task " + num,
+		Status:   status,
+		Priority: PriorityMedium,
+		Estimate: estimate,
+	}
+	for _, d := range deps {
+		task.DependsOn = append(task.DependsOn, TaskID{ChangeID: "add-sso", TaskNum: d})
+	}
+	return task
+}
+
+func TestBuildPlan_CriticalPath(t *testing.T) {
+	t.Parallel()
+
+	// 1 → 2 → 4 is 4h+3h+1h, 1 → 3 → 4 is 4h+1h+1h, so 3 has 2h slack.
+	tasks := []RegistryTask{
+		planTask("1", RegStatusPending, 4),
+		planTask("2", RegStatusPending, 3, "1"),
+		planTask("3", RegStatusPending, 0, "1"),
+		planTask("4", RegStatusPending, 1, "2", "3"),
+		planTask("5", RegStatusCompleted, 2),
+	}
+
+	plan, err := BuildPlan(tasks, PlanOptions{Now: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)})
+	require.NoError(t, err)
+
+	assert.InDelta(t, 8, plan.Duration, 1e-9)
+	assert.InDelta(t, 9, plan.Remaining, 1e-9)
+	assert.Equal(t, 1, plan.Completed)
+	assert.Equal(t, 1, plan.Unestimated)
+	assert.Nil(t, plan.Throughput)
+	assert.Nil(t, plan.ProjectedCompletion)
+
+	var ids []string
+	for _, id := range plan.CriticalPath {
+		ids = append(ids, id.TaskNum)
+	}
+	assert.Equal(t, []string{"1", "2", "4"}, ids)
+
+	byNum := make(map[string]PlannedTask)
+	for _, pt := range plan.Tasks {
+		byNum[pt.ID.TaskNum] = pt
+	}
+	assert.True(t, byNum["2"].Critical)
+	assert.False(t, byNum["3"].Critical)
+	assert.InDelta(t, 2, byNum["3"].Slack, 1e-9)
+	assert.InDelta(t, 4, byNum["3"].EarlyStart, 1e-9)
+	assert.InDelta(t, 6, byNum["3"].LateStart, 1e-9)
+	assert.InDelta(t, 7, byNum["4"].EarlyStart, 1e-9)
+	assert.False(t, byNum["5"].Critical)
+	assert.Zero(t, byNum["5"].Remaining)
+}
+
+func TestBuildPlan_Throughput(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)
+	started := now.Add(-4 * 24 * time.Hour)
+	done := now.Add(-24 * time.Hour)
+
+	finished := planTask("1", RegStatusCompleted, 6)
+	finished.StartedAt = &started
+	finished.CompletedAt = &done
+	other := planTask("2", RegStatusCompleted, 2)
+	other.ID.ChangeID = "other-change"
+	other.CompletedAt = &done
+
+	tasks := []RegistryTask{
+		finished,
+		other,
+		planTask("3", RegStatusPending, 4),
+		planTask("4", RegStatusPending, 0, "3"),
+	}
+
+	plan, err := BuildPlan(tasks, PlanOptions{ChangeID: "add-sso", DefaultEstimate: 2, Now: now})
+	require.NoError(t, err)
+
+	require.NotNil(t, plan.Throughput)
+	assert.Equal(t, 2, plan.Throughput.Completed)
+	assert.Equal(t, started, plan.Throughput.Since)
+	assert.InDelta(t, 2, plan.Throughput.HoursPerDay, 1e-9)
+	assert.InDelta(t, 6, plan.Remaining, 1e-9)
+
+	require.NotNil(t, plan.ProjectedCompletion)
+	assert.Equal(t, now.Add(3*24*time.Hour), *plan.ProjectedCompletion)
+	assert.Len(t, plan.Tasks, 3, "only tasks of the planned change are scheduled")
+}
+
+func TestBuildPlan_Cycle(t *testing.T) {
+	t.Parallel()
+
+	tasks := []RegistryTask{
+		planTask("1", RegStatusPending, 1, "2"),
+		planTask("2", RegStatusPending, 1, "1"),
+		planTask("3", RegStatusPending, 1),
+	}
+
+	_, err := BuildPlan(tasks, PlanOptions{Now: time.Now()})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle among add-sso/1, add-sso/2")
+}
+
+func TestFormatPlan(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	plan, err := BuildPlan([]RegistryTask{
+		planTask("1", RegStatusPending, 2),
+		planTask("2", RegStatusPending, 1.5, "1"),
+		planTask("3", RegStatusPending, 1),
+	}, PlanOptions{Now: now})
+	require.NoError(t, err)
+
+	text := FormatPlan(plan)
+	assert.Contains(t, text, "Plan for all changes: 3 task(s) remaining, 0 done")
+	assert.Contains(t, text, "Effort: 4.5h remaining, critical path 3.5h")
+	assert.Contains(t, text, "no completed task history")
+	assert.Contains(t, text, "Critical path: add-sso/1 → add-sso/2")
+	assert.Contains(t, text, "* add-sso/1 |███████████████████████")
+	assert.Contains(t, text, "slack 2.5h")
+
+	mermaid := FormatPlanMermaid(plan)
+	assert.Contains(t, mermaid, "gantt\n")
+	assert.Contains(t, mermaid, "    section add-sso\n")
+	assert.Contains(t, mermaid, "    add-sso/1 task 1 :crit, add_sso_1, 2026-03-02 09:00, 2h\n")
+	assert.Contains(t, mermaid, "    add-sso/2 task 2 :crit, add_sso_2, 2026-03-02 11:00, 90m\n")
+	assert.Contains(t, mermaid, "    add-sso/3 task 3 :add_sso_3, 2026-03-02 09:00, 1h\n")
+}
+
+func TestRegistryStore_PlanFromTasksMd(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte(`# Tasks
+
+- [ ] 1.1 Add provider <!-- estimate: 1d -->
+- [ ] 1.2 Add callback <!-- depends: 1 --> <!-- estimate: 3h -->
+`), 0o600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	task, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "2"})
+	require.NoError(t, err)
+	assert.Equal(t, "1.2 Add callback", task.Content)
+	assert.InDelta(t, 3, task.Estimate, 1e-9)
+
+	// Ticking a box in tasks.md records a completion time on the next sync,
+	// which the plan then uses as throughput history.
+	require.NoError(t, os.WriteFile(tasksPath, []byte(`# Tasks
+
+- [x] 1.1 Add provider <!-- estimate: 1d -->
+- [ ] 1.2 Add callback <!-- depends: 1 --> <!-- estimate: 3h -->
+`), 0o600))
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	done, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	require.NotNil(t, done.CompletedAt)
+
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+	again, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	require.NotNil(t, again.CompletedAt)
+	assert.True(t, done.CompletedAt.Equal(*again.CompletedAt), "completion time survives later syncs")
+
+	plan, err := reg.Plan(PlanOptions{ChangeID: "add-sso"})
+	require.NoError(t, err)
+	assert.InDelta(t, 3, plan.Remaining, 1e-9)
+	require.NotNil(t, plan.Throughput)
+	assert.Equal(t, 1, plan.Throughput.Completed)
+	assert.NotNil(t, plan.ProjectedCompletion)
+}
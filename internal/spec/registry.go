@@ -46,14 +46,23 @@ type RegistryTask struct {
 	DependsOn   []TaskID           `yaml:"depends_on,omitempty" json:"depends_on,omitempty"`
 	BlockedBy   []TaskID           `yaml:"blocked_by,omitempty" json:"blocked_by,omitempty"`
 	Assignee    string             `yaml:"assignee,omitempty" json:"assignee,omitempty"`
-	Session     string             `yaml:"session,omitempty" json:"session,omitempty"`
+	Lease       *TaskLease         `yaml:"lease,omitempty" json:"lease,omitempty"`
 	StartedAt   *time.Time         `yaml:"started_at,omitempty" json:"started_at,omitempty"`
 	CompletedAt *time.Time         `yaml:"completed_at,omitempty" json:"completed_at,omitempty"`
 	Notes       string             `yaml:"notes,omitempty" json:"notes,omitempty"`
+	Estimate    float64            `yaml:"estimate_hours,omitempty" json:"estimate_hours,omitempty"`
+	Commits     []TaskCommit       `yaml:"commits,omitempty" json:"commits,omitempty"`
 	SourceLine  int                `yaml:"source_line" json:"source_line"`
 	SyncedAt    time.Time          `yaml:"synced_at" json:"synced_at"`
 }
 
+// TaskCommit is a git commit whose message mentions a task.
+type TaskCommit struct {
+	SHA     string    `yaml:"sha" json:"sha"`
+	Time    time.Time `yaml:"time" json:"time"`
+	Subject string    `yaml:"subject" json:"subject"`
+}
+
 type ChangeSummary struct {
 	ID         string       `yaml:"id" json:"id"`
 	Title      string       `yaml:"title" json:"title"`
@@ -100,11 +109,21 @@ type SyncResult struct {
 	Updated       []TaskID       `json:"updated"`
 	Removed       []TaskID       `json:"removed"`
 	Conflicts     []SyncConflict `json:"conflicts"`
+	WrittenToFile []TaskID       `json:"written_to_file,omitempty"`
+	Renumbered    []SyncRenumber `json:"renumbered,omitempty"`
+}
+
+// SyncRenumber is a task whose line moved in tasks.md, so it took the ID of
+// its new position.
+type SyncRenumber struct {
+	From TaskID `json:"from"`
+	To   TaskID `json:"to"`
 }
 
 type SyncConflict struct {
 	TaskID      TaskID `json:"task_id"`
 	Field       string `json:"field"`
+	BaseValue   string `json:"base_value"`
 	SourceValue string `json:"source_value"`
 	RegistryVal string `json:"registry_value"`
 	Resolution  string `json:"resolution"`
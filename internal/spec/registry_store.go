@@ -107,6 +107,9 @@ func (r *RegistryStore) UpdateTask(id TaskID, updates TaskUpdate) error {
 		if *updates.Status == RegStatusCompleted && oldStatus != RegStatusCompleted {
 			task.CompletedAt = &now
 		}
+		if *updates.Status != RegStatusInProgress {
+			task.Lease = nil
+		}
 	}
 
 	// Apply other updates
@@ -180,6 +183,32 @@ func (r *RegistryStore) NextTask(count int) (*NextTaskResult, error) {
 	return result, nil
 }
 
+// ClaimTask leases a task to holder for ttl.
+func (r *RegistryStore) ClaimTask(id TaskID, holder string, ttl time.Duration) (*RegistryTask, error) {
+	return r.bolt.ClaimTask(id, holder, ttl, time.Now())
+}
+
+// ClaimNext leases the highest-priority claimable task of changeID, or of
+// any change when changeID is empty.
+func (r *RegistryStore) ClaimNext(holder, changeID string, ttl time.Duration) (*RegistryTask, error) {
+	return r.bolt.ClaimNext(holder, changeID, ttl, time.Now())
+}
+
+// Heartbeat renews the holder's lease on a task.
+func (r *RegistryStore) Heartbeat(id TaskID, holder string) (*RegistryTask, error) {
+	return r.bolt.Heartbeat(id, holder, time.Now())
+}
+
+// ReleaseTask gives up the holder's lease on a task.
+func (r *RegistryStore) ReleaseTask(id TaskID, holder string) (*RegistryTask, error) {
+	return r.bolt.ReleaseTask(id, holder, time.Now())
+}
+
+// StealTask takes over a task whose lease expired.
+func (r *RegistryStore) StealTask(id TaskID, holder string, ttl time.Duration) (*RegistryTask, error) {
+	return r.bolt.StealTask(id, holder, ttl, time.Now())
+}
+
 func (r *RegistryStore) AddDependency(task, dependsOn TaskID) error {
 	// Use BoltDB AddDependency directly (includes cycle detection)
 	return r.bolt.AddDependency(task, dependsOn)
@@ -237,91 +266,28 @@ func (r *RegistryStore) GetDependencyGraph(id TaskID) (*DependencyGraph, error)
 	return graph, nil
 }
 
+// RebuildFromSource rebuilds the registry from tasks.md files, which win
+// over any registry edit to the checkbox, text or dependencies of a task.
+// Fields tasks.md does not hold are kept. Use Sync to carry registry edits
+// back to tasks.md instead.
 func (r *RegistryStore) RebuildFromSource() (*SyncResult, error) {
-	result := &SyncResult{
-		SyncedChanges: make([]string, 0),
-		Added:         make([]TaskID, 0),
-		Updated:       make([]TaskID, 0),
-		Removed:       make([]TaskID, 0),
-		Conflicts:     make([]SyncConflict, 0),
-	}
-
-	changesPath := filepath.Join(r.store.SpecPath(), "changes")
-	entries, err := os.ReadDir(changesPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return result, nil
-		}
-		return nil, fmt.Errorf("read changes dir: %w", err)
-	}
+	return r.sync(SyncOptions{Resolve: ResolveFile}, true)
+}
 
-	// Clear existing tasks from BoltDB (full rebuild)
-	if err := r.bolt.ClearTasks(); err != nil {
-		return nil, fmt.Errorf("clear bolt tasks: %w", err)
+// restoreTimestamps carries StartedAt and CompletedAt of a task over a
+// rebuild. A task ticked in tasks.md since the last sync completes at now.
+func restoreTimestamps(task, prev *RegistryTask, now time.Time) {
+	if task.Status == RegStatusPending {
+		return
 	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() || entry.Name() == "archive" {
-			continue
-		}
-
-		changeID := entry.Name()
-		tasksPath := filepath.Join(changesPath, changeID, "tasks.md")
-
-		if _, err := os.Stat(tasksPath); os.IsNotExist(err) {
-			continue
-		}
-
-		// Use StateStore to parse tasks WITH dependencies from HTML comments
-		tasks, err := r.stateStore.ParseTasksWithDependencies(changeID, tasksPath)
-		if err != nil {
-			continue
-		}
-
-		summary := ChangeSummary{
-			ID:        changeID,
-			TasksFile: tasksPath,
-			Total:     len(tasks),
-		}
-
-		for _, task := range tasks {
-			switch task.Status {
-			case RegStatusCompleted:
-				summary.Completed++
-			case RegStatusInProgress:
-				summary.InProgress++
-			}
-
-			// Store task in BoltDB
-			if err := r.bolt.UpdateTask(&task); err != nil {
-				return nil, fmt.Errorf("store task %s: %w", task.ID.String(), err)
-			}
-
-			// Store dependencies
-			for _, depID := range task.DependsOn {
-				if err := r.bolt.AddDependency(task.ID, depID); err != nil {
-					// Ignore cycle errors during initial load - they'll be caught later
-					continue
-				}
-			}
-
-			result.Added = append(result.Added, task.ID)
-		}
-
-		// Store change summary
-		if err := r.bolt.UpdateChange(summary); err != nil {
-			return nil, fmt.Errorf("store change summary: %w", err)
-		}
-
-		result.SyncedChanges = append(result.SyncedChanges, changeID)
+	task.StartedAt = prev.StartedAt
+	if task.Status != RegStatusCompleted {
+		return
 	}
-
-	// Set sync metadata
-	if err := r.bolt.SetMeta("synced_at", time.Now().Format(time.RFC3339)); err != nil {
-		return nil, fmt.Errorf("set sync metadata: %w", err)
+	task.CompletedAt = prev.CompletedAt
+	if prev.Status != RegStatusCompleted {
+		task.CompletedAt = &now
 	}
-
-	return result, nil
 }
 
 func (r *RegistryStore) Stats() (*RegistryStats, error) {
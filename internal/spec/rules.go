@@ -1,32 +1,24 @@
 package spec
 
 import (
+	"context"
 	"regexp"
 	"strings"
+
+	"github.com/victorzhuk/go-ent/internal/lint"
 )
 
 // validateRequirementHasScenario checks that each requirement has at least one scenario.
 func validateRequirementHasScenario(ctx *ValidationContext) []ValidationIssue {
 	var issues []ValidationIssue
 
-	requirements := parseRequirements(ctx.Lines)
-	if len(requirements) == 0 {
-		return issues
-	}
-
-	for reqName, reqLine := range requirements {
-		// Find the next requirement or end of file
-		endLine := findNextRequirementLine(ctx.Lines, reqLine)
-
-		// Count scenarios between this requirement and the next
-		scenarioCount := parseScenariosAfterLine(ctx.Lines, reqLine, endLine)
-
-		if scenarioCount == 0 {
+	for _, req := range ctx.Document().Requirements() {
+		if len(req.Scenarios) == 0 {
 			issues = append(issues, ValidationIssue{
 				Severity: SeverityError,
 				File:     ctx.CurrentFile,
-				Line:     reqLine,
-				Message:  "Requirement must have at least one scenario: " + reqName,
+				Line:     req.Line,
+				Message:  "Requirement must have at least one scenario: " + req.Name,
 				RuleID:   "requirement-needs-scenario",
 			})
 		}
@@ -45,27 +37,25 @@ func validateScenarioFormat(ctx *ValidationContext) []ValidationIssue {
 		message string
 	}{
 		{
-			pattern: regexp.MustCompile(`(?m)^-\s+\*\*Scenario:`),
+			pattern: regexp.MustCompile(`^-\s+\*\*Scenario:`),
 			message: "Scenario should use '#### Scenario:' header, not bullet with bold",
 		},
 		{
-			pattern: regexp.MustCompile(`(?m)^\*\*Scenario\*\*:`),
+			pattern: regexp.MustCompile(`^\*\*Scenario\*\*:`),
 			message: "Scenario should use '#### Scenario:' header, not bold text",
 		},
 		{
-			pattern: regexp.MustCompile(`(?m)^###\s+Scenario:`),
+			pattern: regexp.MustCompile(`^###\s+Scenario:`),
 			message: "Scenario should use '####' (4 hashtags), not '###' (3 hashtags)",
 		},
 		{
-			pattern: regexp.MustCompile(`(?m)^#####\s+Scenario:`),
+			pattern: regexp.MustCompile(`^#####\s+Scenario:`),
 			message: "Scenario should use '####' (4 hashtags), not '#####' (5 hashtags)",
 		},
 	}
 
 	for _, p := range incorrectPatterns {
-		matches := p.pattern.FindAllStringIndex(ctx.Content, -1)
-		for _, match := range matches {
-			line := findLineAtOffset(ctx.Content, match[0])
+		for _, line := range matchingLines(ctx, p.pattern) {
 			issues = append(issues, ValidationIssue{
 				Severity: SeverityError,
 				File:     ctx.CurrentFile,
@@ -83,33 +73,22 @@ func validateScenarioFormat(ctx *ValidationContext) []ValidationIssue {
 func validateDeltaOperations(ctx *ValidationContext) []ValidationIssue {
 	var issues []ValidationIssue
 
-	// Valid delta operations
-	validOps := []string{"ADDED", "MODIFIED", "REMOVED", "RENAMED"}
-
-	// Check for delta operation headers
-	deltaPattern := regexp.MustCompile(`(?m)^##\s+(\w+)\s+Requirements?`)
-
-	for i, line := range ctx.Lines {
-		matches := deltaPattern.FindStringSubmatch(line)
-		if matches != nil {
-			op := matches[1]
-			valid := false
-			for _, validOp := range validOps {
-				if strings.EqualFold(op, validOp) {
-					valid = true
-					break
-				}
-			}
-			if !valid {
-				issues = append(issues, ValidationIssue{
-					Severity: SeverityError,
-					File:     ctx.CurrentFile,
-					Line:     i + 1,
-					Message:  "Invalid delta operation: " + op + ". Must be ADDED, MODIFIED, REMOVED, or RENAMED",
-					RuleID:   "delta-operation",
-				})
-			}
+	// Section headings shaped like a delta operation that the parser did not
+	// recognise as one.
+	deltaPattern := regexp.MustCompile(`^(\w+)\s+Requirements?$`)
+
+	for _, section := range ctx.Document().Sections {
+		matches := deltaPattern.FindStringSubmatch(section.Title)
+		if matches == nil || section.Op != "" {
+			continue
 		}
+		issues = append(issues, ValidationIssue{
+			Severity: SeverityError,
+			File:     ctx.CurrentFile,
+			Line:     section.Line,
+			Message:  "Invalid delta operation: " + matches[1] + ". Must be ADDED, MODIFIED, REMOVED, or RENAMED",
+			RuleID:   "delta-operation",
+		})
 	}
 
 	return issues
@@ -125,23 +104,21 @@ func validateRequirementFormat(ctx *ValidationContext) []ValidationIssue {
 		message string
 	}{
 		{
-			pattern: regexp.MustCompile(`(?m)^-\s+\*\*Requirement:`),
+			pattern: regexp.MustCompile(`^-\s+\*\*Requirement:`),
 			message: "Requirement should use '### Requirement:' header, not bullet with bold",
 		},
 		{
-			pattern: regexp.MustCompile(`(?m)^##\s+Requirement:`),
+			pattern: regexp.MustCompile(`^##\s+Requirement:`),
 			message: "Requirement should use '###' (3 hashtags), not '##' (2 hashtags)",
 		},
 		{
-			pattern: regexp.MustCompile(`(?m)^####\s+Requirement:`),
+			pattern: regexp.MustCompile(`^####\s+Requirement:`),
 			message: "Requirement should use '###' (3 hashtags), not '####' (4 hashtags)",
 		},
 	}
 
 	for _, p := range incorrectPatterns {
-		matches := p.pattern.FindAllStringIndex(ctx.Content, -1)
-		for _, match := range matches {
-			line := findLineAtOffset(ctx.Content, match[0])
+		for _, line := range matchingLines(ctx, p.pattern) {
 			issues = append(issues, ValidationIssue{
 				Severity: SeverityError,
 				File:     ctx.CurrentFile,
@@ -155,13 +132,39 @@ func validateRequirementFormat(ctx *ValidationContext) []ValidationIssue {
 	return issues
 }
 
-// findLineAtOffset converts a byte offset to a line number.
-func findLineAtOffset(content string, offset int) int {
-	line := 1
-	for i := 0; i < offset && i < len(content); i++ {
-		if content[i] == '\n' {
-			line++
+// matchingLines returns the 1-based lines outside fenced code blocks that
+// match pattern.
+func matchingLines(ctx *ValidationContext, pattern *regexp.Regexp) []int {
+	doc := ctx.Document()
+
+	var lines []int
+	for i, line := range strings.Split(ctx.Content, "\n") {
+		if !doc.IsCode(i+1) && pattern.MatchString(line) {
+			lines = append(lines, i+1)
 		}
 	}
-	return line
+	return lines
+}
+
+// validatePluginRules runs the spec rules of enabled plugins. Plugin info
+// findings are reported as warnings.
+func validatePluginRules(ctx *ValidationContext) []ValidationIssue {
+	findings := lint.Default().Check(context.Background(), lint.NewFile(lint.TargetSpec, ctx.CurrentFile, ctx.Content))
+
+	issues := make([]ValidationIssue, 0, len(findings))
+	for _, f := range findings {
+		severity := SeverityWarning
+		if f.Severity == lint.SeverityError {
+			severity = SeverityError
+		}
+		issues = append(issues, ValidationIssue{
+			Severity: severity,
+			File:     ctx.CurrentFile,
+			Line:     f.Line,
+			Message:  f.Message,
+			RuleID:   f.Rule,
+			Plugin:   f.Plugin,
+		})
+	}
+	return issues
 }
@@ -0,0 +1,335 @@
+package spec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ScenarioStatus is the outcome of running a scenario against step definitions.
+type ScenarioStatus string
+
+const (
+	ScenarioPassed    ScenarioStatus = "passed"
+	ScenarioFailed    ScenarioStatus = "failed"
+	ScenarioUndefined ScenarioStatus = "undefined"
+)
+
+// StepFunc implements a scenario step. args holds the submatches of the
+// step's pattern.
+type StepFunc func(sc *ScenarioContext, args ...string) error
+
+// ScenarioContext carries state between the steps of one scenario.
+type ScenarioContext struct {
+	Capability  string
+	Requirement string
+	Scenario    string
+
+	values map[string]any
+}
+
+// Set stores a value for later steps of the scenario.
+func (c *ScenarioContext) Set(key string, value any) {
+	if c.values == nil {
+		c.values = make(map[string]any)
+	}
+	c.values[key] = value
+}
+
+// Get returns a value stored by an earlier step.
+func (c *ScenarioContext) Get(key string) (any, bool) {
+	v, ok := c.values[key]
+	return v, ok
+}
+
+type stepDefinition struct {
+	re *regexp.Regexp
+	fn StepFunc
+}
+
+// StepRegistry binds regular expressions to step implementations. Patterns
+// match the step text after its GIVEN/WHEN/THEN/AND keyword.
+type StepRegistry struct {
+	defs []stepDefinition
+}
+
+func NewStepRegistry() *StepRegistry {
+	return &StepRegistry{}
+}
+
+// Step registers fn for steps matching pattern. It panics if the pattern
+// does not compile, like regexp.MustCompile, since steps are registered by
+// test code at setup time.
+func (r *StepRegistry) Step(pattern string, fn StepFunc) {
+	r.defs = append(r.defs, stepDefinition{re: regexp.MustCompile(pattern), fn: fn})
+}
+
+// ScenarioResult is the outcome of one scenario.
+type ScenarioResult struct {
+	Capability  string         `json:"capability"`
+	Requirement string         `json:"requirement"`
+	Scenario    string         `json:"scenario"`
+	Line        int            `json:"line"`
+	Status      ScenarioStatus `json:"status"`
+	// Step is the failing step, or the first undefined one.
+	Step  string `json:"step,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Undefined lists every step without a matching definition.
+	Undefined []string `json:"undefined,omitempty"`
+}
+
+// RunScenarios runs every scenario of every spec in store.
+func (r *StepRegistry) RunScenarios(store *Store) ([]ScenarioResult, error) {
+	specs, err := store.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ScenarioResult
+	for _, s := range specs {
+		content, err := os.ReadFile(s.Path) // #nosec G304 -- controlled file path
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read spec %s: %w", s.ID, err)
+		}
+		for _, req := range ParseDocument(string(content)).Requirements() {
+			for _, sc := range req.Scenarios {
+				results = append(results, r.RunScenario(s.ID, req, sc))
+			}
+		}
+	}
+	return results, nil
+}
+
+// RunScenario runs the steps of one scenario in order. Undefined steps are
+// collected up front and nothing runs; otherwise execution stops at the first
+// step that returns an error or panics.
+func (r *StepRegistry) RunScenario(capability string, req *RequirementBlock, sc *ScenarioBlock) ScenarioResult {
+	result := ScenarioResult{
+		Capability:  capability,
+		Requirement: req.Name,
+		Scenario:    sc.Name,
+		Line:        sc.Line,
+		Status:      ScenarioPassed,
+	}
+
+	type boundStep struct {
+		text string
+		fn   StepFunc
+		args []string
+	}
+
+	var steps []boundStep
+	for _, step := range sc.Steps {
+		text := step.Keyword + " " + step.Text
+		matches := r.match(step.Text)
+		switch len(matches) {
+		case 0:
+			result.Undefined = append(result.Undefined, text)
+		case 1:
+			m := matches[0]
+			steps = append(steps, boundStep{text: text, fn: m.def.fn, args: m.args})
+		default:
+			if result.Status == ScenarioPassed {
+				result.Status = ScenarioFailed
+				result.Step = text
+				result.Error = fmt.Sprintf("ambiguous step: %d definitions match", len(matches))
+			}
+		}
+	}
+
+	if result.Status == ScenarioFailed {
+		return result
+	}
+	if len(result.Undefined) > 0 {
+		result.Status = ScenarioUndefined
+		result.Step = result.Undefined[0]
+		return result
+	}
+
+	ctx := &ScenarioContext{Capability: capability, Requirement: req.Name, Scenario: sc.Name}
+	for _, step := range steps {
+		if err := callStep(ctx, step.fn, step.args); err != nil {
+			result.Status = ScenarioFailed
+			result.Step = step.text
+			result.Error = err.Error()
+			break
+		}
+	}
+	return result
+}
+
+type stepMatch struct {
+	def  stepDefinition
+	args []string
+}
+
+func (r *StepRegistry) match(text string) []stepMatch {
+	var matches []stepMatch
+	for _, def := range r.defs {
+		if m := def.re.FindStringSubmatch(text); m != nil {
+			matches = append(matches, stepMatch{def: def, args: m[1:]})
+		}
+	}
+	return matches
+}
+
+func callStep(ctx *ScenarioContext, fn StepFunc, args []string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx, args...)
+}
+
+// RequirementScenarios groups scenario results under their requirement.
+type RequirementScenarios struct {
+	Capability  string           `json:"capability"`
+	Requirement string           `json:"requirement"`
+	Scenarios   []ScenarioResult `json:"scenarios"`
+}
+
+// Status is failed if any scenario failed, undefined if any is undefined and
+// passed otherwise.
+func (r RequirementScenarios) Status() ScenarioStatus {
+	status := ScenarioPassed
+	for _, sc := range r.Scenarios {
+		switch sc.Status {
+		case ScenarioFailed:
+			return ScenarioFailed
+		case ScenarioUndefined:
+			status = ScenarioUndefined
+		}
+	}
+	return status
+}
+
+// SummarizeScenarios lists every scenario in the specs of store with the
+// result reported for it. Results may come from several test packages: a
+// failure anywhere wins, then a pass, and a scenario no package ran is
+// undefined.
+func SummarizeScenarios(store *Store, results []ScenarioResult) ([]RequirementScenarios, error) {
+	merged := make(map[string]ScenarioResult)
+	for _, res := range results {
+		key := res.Capability + "\x00" + res.Requirement + "\x00" + res.Scenario
+		prev, ok := merged[key]
+		if !ok || statusRank(res.Status) > statusRank(prev.Status) {
+			merged[key] = res
+		}
+	}
+
+	specs, err := store.ListSpecs()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].ID < specs[j].ID })
+
+	var out []RequirementScenarios
+	for _, s := range specs {
+		content, err := os.ReadFile(s.Path) // #nosec G304 -- controlled file path
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read spec %s: %w", s.ID, err)
+		}
+		for _, req := range ParseDocument(string(content)).Requirements() {
+			if len(req.Scenarios) == 0 {
+				continue
+			}
+			group := RequirementScenarios{Capability: s.ID, Requirement: req.Name}
+			for _, sc := range req.Scenarios {
+				res, ok := merged[s.ID+"\x00"+req.Name+"\x00"+sc.Name]
+				if !ok {
+					res = ScenarioResult{
+						Capability:  s.ID,
+						Requirement: req.Name,
+						Scenario:    sc.Name,
+						Status:      ScenarioUndefined,
+						Error:       "no step definitions ran this scenario",
+					}
+				}
+				res.Line = sc.Line
+				group.Scenarios = append(group.Scenarios, res)
+			}
+			out = append(out, group)
+		}
+	}
+	return out, nil
+}
+
+// ParseScenarioResults reads results written one JSON object per line.
+func ParseScenarioResults(data []byte) ([]ScenarioResult, error) {
+	var results []ScenarioResult
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var res ScenarioResult
+		if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		results = append(results, res)
+	}
+	return results, scanner.Err()
+}
+
+func statusRank(s ScenarioStatus) int {
+	switch s {
+	case ScenarioFailed:
+		return 2
+	case ScenarioPassed:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FormatScenarioReport prints results per requirement with a summary line.
+func FormatScenarioReport(groups []RequirementScenarios) string {
+	var sb strings.Builder
+	counts := make(map[ScenarioStatus]int)
+
+	for _, g := range groups {
+		sb.WriteString(fmt.Sprintf("%s %s/%s\n", statusIcon(g.Status()), g.Capability, g.Requirement))
+		for _, sc := range g.Scenarios {
+			counts[sc.Status]++
+			sb.WriteString(fmt.Sprintf("  %s %s", statusIcon(sc.Status), sc.Scenario))
+			switch {
+			case sc.Status == ScenarioFailed:
+				sb.WriteString(fmt.Sprintf(": %s: %s", sc.Step, sc.Error))
+			case len(sc.Undefined) > 0:
+				sb.WriteString(fmt.Sprintf(": undefined %s", strings.Join(sc.Undefined, "; ")))
+			case sc.Status == ScenarioUndefined && sc.Error != "":
+				sb.WriteString(": " + sc.Error)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	total := counts[ScenarioPassed] + counts[ScenarioFailed] + counts[ScenarioUndefined]
+	sb.WriteString(fmt.Sprintf("\n%d scenario(s) in %d requirement(s): %d passed, %d failed, %d undefined\n",
+		total, len(groups), counts[ScenarioPassed], counts[ScenarioFailed], counts[ScenarioUndefined]))
+	return sb.String()
+}
+
+func statusIcon(s ScenarioStatus) string {
+	switch s {
+	case ScenarioPassed:
+		return "✓"
+	case ScenarioFailed:
+		return "✗"
+	default:
+		return "?"
+	}
+}
@@ -0,0 +1,165 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const calculatorSpec = `# Calculator
+
+### Requirement: Addition
+
+#### Scenario: Small numbers
+- **GIVEN** the number 2
+- **AND** the number 3
+- **WHEN** they are added
+- **THEN** the result is 5
+
+#### Scenario: Wrong expectation
+- **GIVEN** the number 1
+- **WHEN** they are added
+- **THEN** the result is 3
+
+### Requirement: Division
+
+#### Scenario: By zero
+- **GIVEN** the number 1
+- **WHEN** it is divided by 0
+- **THEN** an error is reported
+
+### Requirement: Rounding
+No scenarios yet.
+`
+
+func calculatorSteps() *StepRegistry {
+	steps := NewStepRegistry()
+	steps.Step(`^the number (\d+)$`, func(sc *ScenarioContext, args ...string) error {
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		nums, _ := sc.Get("nums")
+		list, _ := nums.([]int)
+		sc.Set("nums", append(list, n))
+		return nil
+	})
+	steps.Step(`^they are added$`, func(sc *ScenarioContext, args ...string) error {
+		nums, _ := sc.Get("nums")
+		sum := 0
+		for _, n := range nums.([]int) {
+			sum += n
+		}
+		sc.Set("result", sum)
+		return nil
+	})
+	steps.Step(`^the result is (\d+)$`, func(sc *ScenarioContext, args ...string) error {
+		got, _ := sc.Get("result")
+		if strconv.Itoa(got.(int)) != args[0] {
+			return errors.New("got " + strconv.Itoa(got.(int)))
+		}
+		return nil
+	})
+	return steps
+}
+
+func writeCalculatorSpec(t *testing.T) *Store {
+	t.Helper()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "openspec", "specs", "calculator")
+	require.NoError(t, os.MkdirAll(dir, 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.md"), []byte(calculatorSpec), 0600))
+	return NewStore(root)
+}
+
+func TestStepRegistry_RunScenarios(t *testing.T) {
+	t.Parallel()
+
+	results, err := calculatorSteps().RunScenarios(writeCalculatorSpec(t))
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	assert.Equal(t, ScenarioResult{
+		Capability: "calculator", Requirement: "Addition", Scenario: "Small numbers", Line: 5, Status: ScenarioPassed,
+	}, results[0])
+
+	assert.Equal(t, ScenarioFailed, results[1].Status)
+	assert.Equal(t, "THEN the result is 3", results[1].Step)
+	assert.Equal(t, "got 1", results[1].Error)
+
+	assert.Equal(t, ScenarioUndefined, results[2].Status)
+	assert.Equal(t, []string{"WHEN it is divided by 0", "THEN an error is reported"}, results[2].Undefined)
+	assert.Equal(t, "WHEN it is divided by 0", results[2].Step)
+}
+
+func TestStepRegistry_AmbiguousAndPanic(t *testing.T) {
+	t.Parallel()
+
+	doc := ParseDocument("### Requirement: R\n\n#### Scenario: S\n- WHEN it runs\n")
+	req := doc.Requirements()[0]
+
+	steps := NewStepRegistry()
+	steps.Step(`^it runs$`, func(sc *ScenarioContext, args ...string) error { panic("boom") })
+	res := steps.RunScenario("cap", req, req.Scenarios[0])
+	assert.Equal(t, ScenarioFailed, res.Status)
+	assert.Equal(t, "panic: boom", res.Error)
+
+	steps.Step(`runs`, func(sc *ScenarioContext, args ...string) error { return nil })
+	res = steps.RunScenario("cap", req, req.Scenarios[0])
+	assert.Equal(t, ScenarioFailed, res.Status)
+	assert.Equal(t, "ambiguous step: 2 definitions match", res.Error)
+}
+
+func TestSummarizeScenarios(t *testing.T) {
+	t.Parallel()
+
+	store := writeCalculatorSpec(t)
+	results := []ScenarioResult{
+		{Capability: "calculator", Requirement: "Addition", Scenario: "Small numbers", Status: ScenarioUndefined},
+		{Capability: "calculator", Requirement: "Addition", Scenario: "Small numbers", Status: ScenarioPassed},
+		{Capability: "calculator", Requirement: "Addition", Scenario: "Wrong expectation", Status: ScenarioPassed},
+		{Capability: "calculator", Requirement: "Addition", Scenario: "Wrong expectation", Status: ScenarioFailed, Step: "THEN the result is 3", Error: "got 1"},
+	}
+
+	groups, err := SummarizeScenarios(store, results)
+	require.NoError(t, err)
+	require.Len(t, groups, 2, "requirements without scenarios are left out")
+
+	assert.Equal(t, ScenarioFailed, groups[0].Status())
+	assert.Equal(t, ScenarioPassed, groups[0].Scenarios[0].Status)
+	assert.Equal(t, ScenarioFailed, groups[0].Scenarios[1].Status)
+	assert.Equal(t, ScenarioUndefined, groups[1].Status())
+	assert.Equal(t, 18, groups[1].Scenarios[0].Line)
+
+	assert.Equal(t, `✗ calculator/Addition
+  ✓ Small numbers
+  ✗ Wrong expectation: THEN the result is 3: got 1
+? calculator/Division
+  ? By zero: no step definitions ran this scenario
+
+3 scenario(s) in 2 requirement(s): 1 passed, 1 failed, 1 undefined
+`, FormatScenarioReport(groups))
+}
+
+func TestParseScenarioResults(t *testing.T) {
+	t.Parallel()
+
+	results, err := ParseScenarioResults([]byte(`{"capability":"c","requirement":"r","scenario":"s","status":"passed"}
+
+{"capability":"c","requirement":"r","scenario":"t","status":"failed","error":"x"}
+`))
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, ScenarioFailed, results[1].Status)
+
+	_, err = ParseScenarioResults([]byte("{not json}\n"))
+	assert.ErrorContains(t, err, "line 1")
+}
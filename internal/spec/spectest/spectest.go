@@ -0,0 +1,72 @@
+// Package spectest runs OpenSpec scenarios as Go subtests.
+//
+// Step definitions are registered on a spec.StepRegistry inside a test:
+//
+//	func TestSpecScenarios(t *testing.T) {
+//		steps := spec.NewStepRegistry()
+//		steps.Step(`^the user runs "(.+)"$`, func(sc *spec.ScenarioContext, args ...string) error {
+//			sc.Set("cmd", args[0])
+//			return nil
+//		})
+//		spectest.Run(t, "../..", steps)
+//	}
+//
+// Failed scenarios fail the test and undefined ones are skipped. When
+// ReportEnv is set, every result is also appended to that file as a JSON
+// line, which is how `go-ent spec test` collects results across packages.
+package spectest
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/victorzhuk/go-ent/internal/spec"
+)
+
+// ReportEnv names the file scenario results are appended to.
+const ReportEnv = "GOENT_SPEC_REPORT"
+
+// Run executes every scenario of the specs under root, one subtest per
+// scenario named capability/Requirement/Scenario.
+func Run(t *testing.T, root string, steps *spec.StepRegistry) {
+	t.Helper()
+
+	results, err := steps.RunScenarios(spec.NewStore(root))
+	if err != nil {
+		t.Fatalf("run scenarios: %v", err)
+	}
+
+	for _, res := range results {
+		t.Run(res.Capability+"/"+res.Requirement+"/"+res.Scenario, func(t *testing.T) {
+			switch res.Status {
+			case spec.ScenarioFailed:
+				t.Errorf("%s: %s", res.Step, res.Error)
+			case spec.ScenarioUndefined:
+				t.Skipf("undefined step: %s", res.Step)
+			}
+		})
+	}
+
+	if path := os.Getenv(ReportEnv); path != "" {
+		if err := appendReport(path, results); err != nil {
+			t.Errorf("write scenario report: %v", err)
+		}
+	}
+}
+
+func appendReport(path string, results []spec.ScenarioResult) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 -- path from the caller's environment
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	enc := json.NewEncoder(f)
+	for _, res := range results {
+		if err := enc.Encode(res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
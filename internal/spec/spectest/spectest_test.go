@@ -0,0 +1,54 @@
+package spectest_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/spec"
+	"github.com/victorzhuk/go-ent/internal/spec/spectest"
+)
+
+func TestRun(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "openspec", "specs", "greeting")
+	require.NoError(t, os.MkdirAll(dir, 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.md"), []byte(`# Greeting
+
+### Requirement: Hello
+
+#### Scenario: Named user
+- **WHEN** greeting "Ada"
+- **THEN** the reply is "Hello, Ada"
+
+#### Scenario: Anonymous user
+- **WHEN** greeting nobody
+- **THEN** the reply is "Hello"
+`), 0o600))
+
+	report := filepath.Join(t.TempDir(), "scenarios.jsonl")
+	t.Setenv(spectest.ReportEnv, report)
+
+	steps := spec.NewStepRegistry()
+	steps.Step(`^greeting "(.+)"$`, func(sc *spec.ScenarioContext, args ...string) error {
+		sc.Set("reply", "Hello, "+args[0])
+		return nil
+	})
+	steps.Step(`^the reply is "(.+)"$`, func(sc *spec.ScenarioContext, args ...string) error {
+		reply, _ := sc.Get("reply")
+		assert.Equal(t, args[0], reply)
+		return nil
+	})
+
+	spectest.Run(t, root, steps)
+
+	data, err := os.ReadFile(report)
+	require.NoError(t, err)
+	results, err := spec.ParseScenarioResults(data)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, spec.ScenarioPassed, results[0].Status)
+	assert.Equal(t, spec.ScenarioUndefined, results[1].Status)
+}
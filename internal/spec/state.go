@@ -6,11 +6,22 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
 
-var depCommentRegex = regexp.MustCompile(`<!--\s*depends:\s*(.+?)\s*-->`)
+var (
+	depCommentRegex      = regexp.MustCompile(`<!--\s*depends:\s*(.+?)\s*-->`)
+	estimateCommentRegex = regexp.MustCompile(`<!--\s*estimate:\s*(.+?)\s*-->`)
+	estimateValueRegex   = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*(m|min|h|d|w)?$`)
+)
+
+// Working hours behind the d and w estimate units.
+const (
+	hoursPerDay  = 8
+	hoursPerWeek = 5 * hoursPerDay
+)
 
 type StateStore struct {
 	store *Store
@@ -30,6 +41,7 @@ type ChangeState struct {
 	Progress       ProgressInfo
 	CurrentTask    *TaskInfo
 	Blockers       []TaskInfo
+	Leases         []LeaseInfo
 	RecentActivity []ActivityInfo
 	Updated        time.Time
 }
@@ -48,6 +60,15 @@ type TaskInfo struct {
 	Status  RegistryTaskStatus
 }
 
+// LeaseInfo is a task lease as shown in state.md.
+type LeaseInfo struct {
+	ID        TaskID
+	Content   string
+	Holder    string
+	ExpiresAt time.Time
+	Expired   bool
+}
+
 type ActivityInfo struct {
 	TaskID TaskID
 	Action string
@@ -57,6 +78,7 @@ type ActivityInfo struct {
 type RootState struct {
 	ActiveChanges    []ChangeSummary
 	RecommendedTasks []RegistryTask
+	Leases           []LeaseInfo
 	Updated          time.Time
 }
 
@@ -109,6 +131,8 @@ func (s *StateStore) GenerateChangeState(changeID string) (*ChangeState, error)
 		}
 	}
 
+	state.Leases = leaseInfos(tasks, state.Updated)
+
 	for i := len(tasks) - 1; i >= 0 && len(state.RecentActivity) < 5; i-- {
 		if tasks[i].CompletedAt != nil {
 			state.RecentActivity = append(state.RecentActivity, ActivityInfo{
@@ -133,13 +157,44 @@ func (s *StateStore) GenerateRootState() (*RootState, error) {
 		return nil, fmt.Errorf("get next tasks: %w", err)
 	}
 
+	leased, err := s.bolt.ListLeases()
+	if err != nil {
+		return nil, fmt.Errorf("list leases: %w", err)
+	}
+
+	now := time.Now()
 	return &RootState{
 		ActiveChanges:    changes,
 		RecommendedTasks: next,
-		Updated:          time.Now(),
+		Leases:           leaseInfos(leased, now),
+		Updated:          now,
 	}, nil
 }
 
+func leaseInfos(tasks []RegistryTask, now time.Time) []LeaseInfo {
+	var leases []LeaseInfo
+	for _, t := range tasks {
+		if t.Lease == nil {
+			continue
+		}
+		leases = append(leases, LeaseInfo{
+			ID:        t.ID,
+			Content:   t.Content,
+			Holder:    t.Lease.Holder,
+			ExpiresAt: t.Lease.ExpiresAt,
+			Expired:   !t.Lease.Active(now),
+		})
+	}
+	return leases
+}
+
+func leaseExpiry(l LeaseInfo) string {
+	if l.Expired {
+		return "expired " + l.ExpiresAt.Format(time.RFC3339)
+	}
+	return "until " + l.ExpiresAt.Format(time.RFC3339)
+}
+
 func (s *StateStore) WriteChangeStateMd(changeID string, outputPath string) error {
 	state, err := s.GenerateChangeState(changeID)
 	if err != nil {
@@ -178,6 +233,14 @@ func (s *StateStore) WriteChangeStateMd(changeID string, outputPath string) erro
 		_, _ = fmt.Fprintf(f, "\n")
 	}
 
+	if len(state.Leases) > 0 {
+		_, _ = fmt.Fprintf(f, "## Leases\n")
+		for _, l := range state.Leases {
+			_, _ = fmt.Fprintf(f, "- **T%s**: %s (held by %s, %s)\n", l.ID.TaskNum, l.Content, l.Holder, leaseExpiry(l))
+		}
+		_, _ = fmt.Fprintf(f, "\n")
+	}
+
 	_, _ = fmt.Fprintf(f, "## Recent Activity\n")
 	if len(state.RecentActivity) == 0 {
 		_, _ = fmt.Fprintf(f, "No recent activity\n\n")
@@ -224,6 +287,16 @@ func (s *StateStore) WriteRootStateMd(outputPath string) error {
 	}
 	_, _ = fmt.Fprintf(f, "\n")
 
+	if len(state.Leases) > 0 {
+		_, _ = fmt.Fprintf(f, "## Leases\n\n")
+		_, _ = fmt.Fprintf(f, "| Task | Holder | Expires |\n")
+		_, _ = fmt.Fprintf(f, "|------|--------|---------|\n")
+		for _, l := range state.Leases {
+			_, _ = fmt.Fprintf(f, "| %s | %s | %s |\n", l.ID.String(), l.Holder, leaseExpiry(l))
+		}
+		_, _ = fmt.Fprintf(f, "\n")
+	}
+
 	_, _ = fmt.Fprintf(f, "## Recommended Next\n")
 	if len(state.RecommendedTasks) == 0 {
 		_, _ = fmt.Fprintf(f, "No unblocked tasks available\n\n")
@@ -256,6 +329,37 @@ func ParseDependencies(line string) []string {
 	return deps
 }
 
+// ParseEstimate reads an effort estimate comment such as
+// <!-- estimate: 4h --> and returns it in working hours. Units are m, h,
+// d (8h) and w (5d); a bare number means hours. It returns 0 when the line
+// has no estimate or the value cannot be parsed.
+func ParseEstimate(line string) float64 {
+	matches := estimateCommentRegex.FindStringSubmatch(line)
+	if len(matches) < 2 {
+		return 0
+	}
+
+	m := estimateValueRegex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(matches[1])))
+	if m == nil {
+		return 0
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0
+	}
+
+	switch m[2] {
+	case "m", "min":
+		return value / 60
+	case "d":
+		return value * hoursPerDay
+	case "w":
+		return value * hoursPerWeek
+	default:
+		return value
+	}
+}
+
 func (s *StateStore) ParseTasksWithDependencies(changeID string, tasksPath string) ([]RegistryTask, error) {
 	f, err := os.Open(tasksPath) // #nosec G304 -- controlled config/template file path
 	if err != nil {
@@ -301,6 +405,7 @@ func (s *StateStore) ParseTasksWithDependencies(changeID string, tasksPath strin
 			Content:    content,
 			Status:     status,
 			Priority:   PriorityMedium,
+			Estimate:   ParseEstimate(depStr),
 			SourceLine: lineNum,
 			SyncedAt:   time.Now(),
 		}
@@ -325,49 +430,6 @@ func (s *StateStore) ParseTasksWithDependencies(changeID string, tasksPath strin
 	return tasks, nil
 }
 
-func (s *StateStore) SyncFromTasksMd() error {
-	changesDir := filepath.Join(s.store.RootPath(), "openspec", "changes")
-	entries, err := os.ReadDir(changesDir)
-	if err != nil {
-		return fmt.Errorf("read changes dir: %w", err)
-	}
-
-	for _, entry := range entries {
-		if !entry.IsDir() || entry.Name() == "archive" {
-			continue
-		}
-
-		changeID := entry.Name()
-		tasksPath := filepath.Join(changesDir, changeID, "tasks.md")
-		if _, err := os.Stat(tasksPath); os.IsNotExist(err) {
-			continue
-		}
-
-		tasks, err := s.ParseTasksWithDependencies(changeID, tasksPath)
-		if err != nil {
-			return fmt.Errorf("parse tasks for %s: %w", changeID, err)
-		}
-
-		for i := range tasks {
-			if err := s.bolt.UpdateTask(&tasks[i]); err != nil {
-				return fmt.Errorf("update task %s: %w", tasks[i].ID, err)
-			}
-
-			for _, dep := range tasks[i].DependsOn {
-				if err := s.bolt.AddDependency(tasks[i].ID, dep); err != nil {
-					return fmt.Errorf("add dependency %s->%s: %w", tasks[i].ID, dep, err)
-				}
-			}
-		}
-	}
-
-	if err := s.bolt.SetSyncedAt(time.Now()); err != nil {
-		return fmt.Errorf("set synced_at: %w", err)
-	}
-
-	return nil
-}
-
 func (s *StateStore) UpdateTaskInFile(taskID TaskID, status RegistryTaskStatus, notes string) error {
 	changeDir := filepath.Join(s.store.SpecPath(), "changes", taskID.ChangeID)
 	tasksPath := filepath.Join(changeDir, "tasks.md")
@@ -0,0 +1,508 @@
+package spec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SyncResolution picks the side that wins a field changed differently in
+// tasks.md and in the registry since the last sync.
+type SyncResolution string
+
+const (
+	// ResolveFile takes the tasks.md value.
+	ResolveFile SyncResolution = "file"
+	// ResolveRegistry takes the registry value and rewrites the tasks.md line.
+	ResolveRegistry SyncResolution = "registry"
+	// ResolveManual leaves conflicting tasks alone on both sides, so they are
+	// reported again until someone makes them agree.
+	ResolveManual SyncResolution = "manual"
+)
+
+var (
+	ErrUnknownResolution = errors.New("unknown sync resolution")
+
+	syncLineRegex    = regexp.MustCompile(`^([-*])\s+\[[ xX]\]\s+(.+)$`)
+	htmlCommentRegex = regexp.MustCompile(`<!--.*?-->`)
+)
+
+// ParseSyncResolution maps file, registry or manual to a SyncResolution.
+func ParseSyncResolution(name string) (SyncResolution, error) {
+	switch r := SyncResolution(strings.ToLower(strings.TrimSpace(name))); r {
+	case ResolveFile, ResolveRegistry, ResolveManual:
+		return r, nil
+	}
+	return "", fmt.Errorf("%w: %s (use file, registry or manual)", ErrUnknownResolution, name)
+}
+
+// SyncOptions controls a two-way sync.
+type SyncOptions struct {
+	// Resolve settles conflicts; empty means ResolveManual.
+	Resolve SyncResolution
+	// DryRun reports what would change without writing tasks.md or the
+	// registry.
+	DryRun bool
+}
+
+// TaskSyncState is the part of a task that both tasks.md and the registry
+// hold: the checkbox, the text and the dependencies within the change.
+type TaskSyncState struct {
+	Done      bool     `json:"done"`
+	Content   string   `json:"content"`
+	DependsOn []string `json:"depends_on,omitempty"`
+}
+
+// SyncBase is the state of a task line at the last sync, the common ancestor
+// the tasks.md and registry sides are compared against.
+type SyncBase struct {
+	Hash  string        `json:"hash"`
+	State TaskSyncState `json:"state"`
+}
+
+// Sync reconciles tasks.md files and the registry in both directions. Each
+// task is compared with its state at the last sync: edits made on one side
+// only are carried to the other, so registry updates reach tasks.md and hand
+// edits reach the registry. A field changed differently on both sides is a
+// conflict, reported in the result and settled by opts.Resolve.
+//
+// A task line is matched to the registry task whose last synced state has
+// the same hash or text, and only then by position, so inserting or deleting
+// lines moves the registry state of the tasks below along with them. Task
+// IDs follow the position in tasks.md; moved tasks are listed as renumbered.
+func (r *RegistryStore) Sync(opts SyncOptions) (*SyncResult, error) {
+	if opts.Resolve == "" {
+		opts.Resolve = ResolveManual
+	}
+	if _, err := ParseSyncResolution(string(opts.Resolve)); err != nil {
+		return nil, err
+	}
+	return r.sync(opts, false)
+}
+
+// sync backs Sync and RebuildFromSource. A rebuild takes every task from
+// tasks.md, as if each conflict were resolved for the file, and lists all
+// tasks as added.
+func (r *RegistryStore) sync(opts SyncOptions, rebuild bool) (*SyncResult, error) {
+	result := &SyncResult{
+		SyncedChanges: make([]string, 0),
+		Added:         make([]TaskID, 0),
+		Updated:       make([]TaskID, 0),
+		Removed:       make([]TaskID, 0),
+		Conflicts:     make([]SyncConflict, 0),
+	}
+
+	changesPath := filepath.Join(r.store.SpecPath(), "changes")
+	entries, err := os.ReadDir(changesPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("read changes dir: %w", err)
+	}
+
+	previous := make(map[string]RegistryTask)
+	if existing, err := r.bolt.ListTasks(TaskFilter{}); err == nil {
+		for _, t := range existing {
+			// registry_deps edits only the deps bucket
+			if deps, err := r.bolt.GetDependencies(t.ID); err == nil && deps != nil {
+				t.DependsOn = deps
+			}
+			previous[t.ID.String()] = t
+		}
+	}
+	bases, err := r.bolt.ListSyncBases()
+	if err != nil {
+		return nil, fmt.Errorf("list sync bases: %w", err)
+	}
+	now := time.Now()
+
+	var (
+		synced   []RegistryTask
+		changes  []string
+		newBases = make(map[string]SyncBase)
+		rewrites = make(map[string][]string)
+		matched  = make(map[string]bool)
+	)
+
+	for _, entry := range entries {
+		if !entry.IsDir() || entry.Name() == "archive" {
+			continue
+		}
+
+		changeID := entry.Name()
+		tasksPath := filepath.Join(changesPath, changeID, "tasks.md")
+
+		if _, err := os.Stat(tasksPath); os.IsNotExist(err) {
+			continue
+		}
+
+		// Use StateStore to parse tasks WITH dependencies from HTML comments
+		tasks, err := r.stateStore.ParseTasksWithDependencies(changeID, tasksPath)
+		if err != nil {
+			continue
+		}
+		lines, err := r.stateStore.readFileLines(tasksPath)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", tasksPath, err)
+		}
+
+		rewritten := false
+		sources := matchSyncSources(changeID, tasks, previous, bases)
+		for _, ft := range tasks {
+			id := ft.ID.String()
+			fileState := syncStateOf(&ft)
+			merged := fileState
+
+			var (
+				prev    *RegistryTask
+				base    SyncBase
+				hasBase bool
+			)
+			if src, ok := sources[id]; ok {
+				p := previous[src]
+				prev = &p
+				// Without a base, as after upgrading, tasks.md is authoritative
+				base, hasBase = bases[src]
+				matched[src] = true
+				if src != id {
+					result.Renumbered = append(result.Renumbered, SyncRenumber{From: p.ID, To: ft.ID})
+				}
+			}
+
+			if prev != nil && hasBase && !rebuild {
+				regState := syncStateOf(prev)
+				var fields []string
+				merged, fields = mergeSyncState(base.State, fileState, regState)
+				for _, f := range fields {
+					result.Conflicts = append(result.Conflicts, SyncConflict{
+						TaskID:      ft.ID,
+						Field:       f,
+						BaseValue:   base.State.field(f),
+						SourceValue: fileState.field(f),
+						RegistryVal: regState.field(f),
+						Resolution:  string(opts.Resolve),
+					})
+				}
+				if len(fields) > 0 {
+					switch opts.Resolve {
+					case ResolveFile:
+						merged = merged.take(fields, fileState)
+					case ResolveRegistry:
+						merged = merged.take(fields, regState)
+					default:
+						kept := *prev
+						kept.ID = ft.ID
+						kept.SourceLine = ft.SourceLine
+						synced = append(synced, kept)
+						newBases[id] = base
+						continue
+					}
+				}
+			}
+
+			task := syncedTask(ft, prev, merged, now)
+			if merged.hash() != fileState.hash() {
+				lines[ft.SourceLine-1] = renderSyncedLine(lines[ft.SourceLine-1], &task)
+				rewritten = true
+				result.WrittenToFile = append(result.WrittenToFile, ft.ID)
+			}
+
+			switch {
+			case prev == nil || rebuild:
+				result.Added = append(result.Added, ft.ID)
+			case merged.hash() != syncStateOf(prev).hash() || prev.ID != ft.ID:
+				result.Updated = append(result.Updated, ft.ID)
+			}
+
+			synced = append(synced, task)
+			newBases[id] = SyncBase{Hash: merged.hash(), State: merged}
+		}
+
+		if rewritten {
+			rewrites[tasksPath] = lines
+		}
+		changes = append(changes, changeID)
+		result.SyncedChanges = append(result.SyncedChanges, changeID)
+	}
+
+	for id, t := range previous {
+		if !matched[id] {
+			result.Removed = append(result.Removed, t.ID)
+		}
+	}
+	sort.Slice(result.Removed, func(i, j int) bool { return taskIDLess(result.Removed[i], result.Removed[j]) })
+
+	if opts.DryRun {
+		return result, nil
+	}
+
+	for path, lines := range rewrites {
+		if err := r.stateStore.writeFileLines(path, lines); err != nil {
+			return nil, fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+
+	// Clear existing tasks from BoltDB (full rebuild)
+	if err := r.bolt.ClearTasks(); err != nil {
+		return nil, fmt.Errorf("clear bolt tasks: %w", err)
+	}
+
+	for i := range synced {
+		task := &synced[i]
+		if err := r.bolt.UpdateTask(task); err != nil {
+			return nil, fmt.Errorf("store task %s: %w", task.ID.String(), err)
+		}
+		for _, depID := range task.DependsOn {
+			// Ignore cycle errors during initial load - they'll be caught later
+			_ = r.bolt.AddDependency(task.ID, depID)
+		}
+	}
+
+	for _, changeID := range changes {
+		if err := r.bolt.UpdateChange(ChangeSummary{ID: changeID}); err != nil {
+			return nil, fmt.Errorf("store change summary: %w", err)
+		}
+	}
+
+	if err := r.bolt.ReplaceSyncBases(newBases); err != nil {
+		return nil, fmt.Errorf("store sync bases: %w", err)
+	}
+
+	// Set sync metadata
+	if err := r.bolt.SetMeta("synced_at", now.Format(time.RFC3339)); err != nil {
+		return nil, fmt.Errorf("set sync metadata: %w", err)
+	}
+
+	return result, nil
+}
+
+// matchSyncSources maps the ID of each tasks.md task of a change to the ID
+// of the registry task it continues. A task keeps its position when its last
+// synced state there has the same text; otherwise an unmatched task of the
+// change with the same synced hash, then the same text, is taken, as when
+// lines were inserted above it. Tasks left over fall back to position, which
+// treats the line as edited in place. A task without a match is new.
+func matchSyncSources(changeID string, tasks []RegistryTask, previous map[string]RegistryTask, bases map[string]SyncBase) map[string]string {
+	sources := make(map[string]string)
+	claimed := make(map[string]bool)
+	claim := func(id, src string) {
+		sources[id] = src
+		claimed[src] = true
+	}
+
+	var candidates []TaskID
+	for _, t := range previous {
+		if _, ok := bases[t.ID.String()]; ok && t.ID.ChangeID == changeID {
+			candidates = append(candidates, t.ID)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return taskIDLess(candidates[i], candidates[j]) })
+
+	for i := range tasks {
+		id := tasks[i].ID.String()
+		if base, ok := bases[id]; ok && base.State.Content == tasks[i].Content {
+			if _, ok := previous[id]; ok {
+				claim(id, id)
+			}
+		}
+	}
+
+	find := func(same func(SyncBase) bool) string {
+		for _, c := range candidates {
+			if src := c.String(); !claimed[src] && same(bases[src]) {
+				return src
+			}
+		}
+		return ""
+	}
+	for i := range tasks {
+		id := tasks[i].ID.String()
+		if _, ok := sources[id]; ok {
+			continue
+		}
+		hash := syncStateOf(&tasks[i]).hash()
+		src := find(func(b SyncBase) bool { return b.Hash == hash })
+		if src == "" {
+			src = find(func(b SyncBase) bool { return b.State.Content == tasks[i].Content })
+		}
+		if src != "" {
+			claim(id, src)
+		}
+	}
+
+	for i := range tasks {
+		id := tasks[i].ID.String()
+		if _, ok := sources[id]; ok || claimed[id] {
+			continue
+		}
+		if _, ok := previous[id]; ok {
+			claim(id, id)
+		}
+	}
+	return sources
+}
+
+// syncedTask builds the registry task for a tasks.md task once its shared
+// state is merged. Active leases, timestamps, linked commits, priority,
+// assignee, notes, statuses tasks.md cannot show and dependencies on other
+// changes live only in the registry and are kept. An expired lease is
+// dropped and its task handed back as pending.
+func syncedTask(ft RegistryTask, prev *RegistryTask, merged TaskSyncState, now time.Time) RegistryTask {
+	task := ft
+	task.Content = merged.Content
+	task.Status = RegStatusPending
+	if merged.Done {
+		task.Status = RegStatusCompleted
+	}
+
+	task.DependsOn = nil
+	for _, num := range merged.DependsOn {
+		task.DependsOn = append(task.DependsOn, TaskID{ChangeID: ft.ID.ChangeID, TaskNum: num})
+	}
+	if prev == nil {
+		return task
+	}
+
+	for _, dep := range prev.DependsOn {
+		if dep.ChangeID != ft.ID.ChangeID {
+			task.DependsOn = append(task.DependsOn, dep)
+		}
+	}
+	if !merged.Done && prev.Status != RegStatusCompleted {
+		task.Status = prev.Status
+	}
+	task.Assignee = prev.Assignee
+	switch {
+	case merged.Done:
+	case prev.Lease.Active(now):
+		task.Lease = prev.Lease
+		task.Status = RegStatusInProgress
+	case prev.Lease != nil:
+		if task.Status == RegStatusInProgress {
+			task.Status = RegStatusPending
+		}
+		if task.Assignee == prev.Lease.Holder {
+			task.Assignee = ""
+		}
+	}
+	restoreTimestamps(&task, prev, now)
+	task.Commits = prev.Commits
+	task.Priority = prev.Priority
+	task.Notes = prev.Notes
+	return task
+}
+
+func syncStateOf(t *RegistryTask) TaskSyncState {
+	state := TaskSyncState{
+		Done:    t.Status == RegStatusCompleted,
+		Content: t.Content,
+	}
+	for _, dep := range t.DependsOn {
+		if dep.ChangeID == t.ID.ChangeID {
+			state.DependsOn = append(state.DependsOn, dep.TaskNum)
+		}
+	}
+	sort.Strings(state.DependsOn)
+	return state
+}
+
+// hash fingerprints the state so the last-synced line can be compared
+// without rereading it.
+func (s TaskSyncState) hash() string {
+	done := " "
+	if s.Done {
+		done = "x"
+	}
+	sum := sha256.Sum256([]byte(done + "\x00" + s.Content + "\x00" + strings.Join(s.DependsOn, ",")))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s TaskSyncState) field(name string) string {
+	switch name {
+	case "status":
+		if s.Done {
+			return "done"
+		}
+		return "open"
+	case "content":
+		return s.Content
+	case "depends_on":
+		return strings.Join(s.DependsOn, ", ")
+	}
+	return ""
+}
+
+// take copies the named fields from other.
+func (s TaskSyncState) take(fields []string, other TaskSyncState) TaskSyncState {
+	for _, f := range fields {
+		switch f {
+		case "status":
+			s.Done = other.Done
+		case "content":
+			s.Content = other.Content
+		case "depends_on":
+			s.DependsOn = other.DependsOn
+		}
+	}
+	return s
+}
+
+// mergeSyncState merges the tasks.md and registry states of a task field by
+// field against their common base. A field changed on one side takes that
+// side's value; fields changed differently on both are returned as
+// conflicts and keep the base value.
+func mergeSyncState(base, file, reg TaskSyncState) (TaskSyncState, []string) {
+	merged := base
+	var conflicts []string
+	for _, f := range []string{"status", "content", "depends_on"} {
+		b, fv, rv := base.field(f), file.field(f), reg.field(f)
+		switch {
+		case fv == rv, rv == b:
+			merged = merged.take([]string{f}, file)
+		case fv == b:
+			merged = merged.take([]string{f}, reg)
+		default:
+			conflicts = append(conflicts, f)
+		}
+	}
+	return merged, conflicts
+}
+
+// renderSyncedLine rewrites a tasks.md line to show task, keeping the bullet
+// and any comments other than the dependency list.
+func renderSyncedLine(line string, task *RegistryTask) string {
+	bullet := "-"
+	var comments []string
+	if m := syncLineRegex.FindStringSubmatch(line); m != nil {
+		bullet = m[1]
+		if idx := strings.Index(m[2], "<!--"); idx != -1 {
+			for _, c := range htmlCommentRegex.FindAllString(m[2][idx:], -1) {
+				if !depCommentRegex.MatchString(c) {
+					comments = append(comments, c)
+				}
+			}
+		}
+	}
+
+	state := syncStateOf(task)
+	checked := " "
+	if state.Done {
+		checked = "x"
+	}
+	out := fmt.Sprintf("%s [%s] %s", bullet, checked, state.Content)
+	if len(state.DependsOn) > 0 {
+		out += fmt.Sprintf(" <!-- depends: %s -->", strings.Join(state.DependsOn, ", "))
+	}
+	for _, c := range comments {
+		out += " " + c
+	}
+	return out
+}
@@ -0,0 +1,286 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncTwoWay(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte(`# Tasks
+
+- [ ] Add provider <!-- estimate: 2h -->
+- [ ] Add callback
+* [ ] Write docs
+`), 0o600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	id := func(num string) TaskID { return TaskID{ChangeID: "add-sso", TaskNum: num} }
+	done, started := RegStatusCompleted, RegStatusInProgress
+
+	// The registry completes task 1 and starts task 2 while tasks.md gets
+	// task 3 renamed; task 3 also gains a dependency in the registry.
+	require.NoError(t, reg.UpdateTask(id("1"), TaskUpdate{Status: &done}))
+	require.NoError(t, reg.UpdateTask(id("2"), TaskUpdate{Status: &started}))
+	require.NoError(t, reg.AddDependency(id("3"), id("1")))
+	require.NoError(t, os.WriteFile(tasksPath, []byte(`# Tasks
+
+- [ ] Add provider <!-- estimate: 2h -->
+- [ ] Add callback
+* [ ] Write user docs
+`), 0o600))
+
+	result, err := reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, []TaskID{id("3")}, result.Updated)
+	assert.Equal(t, []TaskID{id("1"), id("3")}, result.WrittenToFile)
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, `# Tasks
+
+- [x] Add provider <!-- estimate: 2h -->
+- [ ] Add callback
+* [ ] Write user docs <!-- depends: 1 -->
+`, string(content))
+
+	task, err := reg.GetTask(id("2"))
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusInProgress, task.Status, "statuses tasks.md cannot show are kept")
+	task, err = reg.GetTask(id("3"))
+	require.NoError(t, err)
+	assert.Equal(t, "Write user docs", task.Content)
+	assert.Equal(t, []TaskID{id("1")}, task.DependsOn)
+
+	result, err = reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Updated, "a second sync has nothing to do")
+	assert.Empty(t, result.WrittenToFile)
+}
+
+func TestSyncConflicts(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n- [ ] Write docs\n"), 0o600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	id := func(num string) TaskID { return TaskID{ChangeID: "add-sso", TaskNum: num} }
+
+	// Both sides give task 3 a different dependency
+	require.NoError(t, reg.AddDependency(id("3"), id("2")))
+	edited := "# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n- [ ] Write docs <!-- depends: 1 -->\n"
+	require.NoError(t, os.WriteFile(tasksPath, []byte(edited), 0o600))
+
+	for range 2 {
+		result, err := reg.Sync(SyncOptions{Resolve: ResolveManual})
+		require.NoError(t, err)
+		require.Len(t, result.Conflicts, 1, "manual conflicts stay until resolved")
+		c := result.Conflicts[0]
+		assert.Equal(t, id("3"), c.TaskID)
+		assert.Equal(t, "depends_on", c.Field)
+		assert.Empty(t, c.BaseValue)
+		assert.Equal(t, "1", c.SourceValue)
+		assert.Equal(t, "2", c.RegistryVal)
+		assert.Equal(t, "manual", c.Resolution)
+
+		content, err := os.ReadFile(tasksPath)
+		require.NoError(t, err)
+		assert.Equal(t, edited, string(content))
+		task, err := reg.GetTask(id("3"))
+		require.NoError(t, err)
+		assert.Equal(t, []TaskID{id("2")}, task.DependsOn)
+	}
+
+	result, err := reg.Sync(SyncOptions{Resolve: ResolveRegistry, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, []TaskID{id("3")}, result.WrittenToFile)
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, edited, string(content), "a dry run writes nothing")
+
+	result, err = reg.Sync(SyncOptions{Resolve: ResolveRegistry})
+	require.NoError(t, err)
+	require.Len(t, result.Conflicts, 1)
+	assert.Equal(t, "registry", result.Conflicts[0].Resolution)
+	content, err = os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n- [ ] Write docs <!-- depends: 2 -->\n", string(content))
+
+	result, err = reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+
+	// Resolving for the file drops the registry edit
+	require.NoError(t, reg.RemoveDependency(id("3"), id("2")))
+	require.NoError(t, reg.AddDependency(id("3"), id("1")))
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n- [ ] Write docs\n"), 0o600))
+
+	result, err = reg.Sync(SyncOptions{Resolve: ResolveFile})
+	require.NoError(t, err)
+	require.Len(t, result.Conflicts, 1)
+	task, err := reg.GetTask(id("3"))
+	require.NoError(t, err)
+	assert.Empty(t, task.DependsOn)
+
+	_, err = reg.Sync(SyncOptions{Resolve: "theirs"})
+	require.ErrorIs(t, err, ErrUnknownResolution)
+}
+
+func TestSyncFollowsMovedTasks(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add provider\n- [ ] Add callback\n"), 0o600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	id := func(num string) TaskID { return TaskID{ChangeID: "add-sso", TaskNum: num} }
+	done, high := RegStatusCompleted, PriorityHigh
+	require.NoError(t, reg.UpdateTask(id("1"), TaskUpdate{Status: &done}))
+	require.NoError(t, reg.UpdateTask(id("2"), TaskUpdate{Priority: &high}))
+
+	// A task inserted above shifts both down; the registry state moves along
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [ ] Add config\n- [ ] Add provider\n- [ ] Add callback\n"), 0o600))
+
+	result, err := reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, []TaskID{id("1")}, result.Added)
+	assert.Equal(t, []SyncRenumber{{From: id("1"), To: id("2")}, {From: id("2"), To: id("3")}}, result.Renumbered)
+	assert.Empty(t, result.Removed)
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [ ] Add config\n- [x] Add provider\n- [ ] Add callback\n", string(content))
+
+	task, err := reg.GetTask(id("1"))
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusPending, task.Status)
+	assert.NotEqual(t, PriorityHigh, task.Priority)
+	task, err = reg.GetTask(id("2"))
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusCompleted, task.Status)
+	task, err = reg.GetTask(id("3"))
+	require.NoError(t, err)
+	assert.Equal(t, "Add callback", task.Content)
+	assert.Equal(t, PriorityHigh, task.Priority)
+
+	// Deleting the first line shifts them back up
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [x] Add provider\n- [ ] Add callback\n"), 0o600))
+	result, err = reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, []SyncRenumber{{From: id("2"), To: id("1")}, {From: id("3"), To: id("2")}}, result.Renumbered)
+	assert.Equal(t, []TaskID{id("1")}, result.Removed, "the old task 1 is gone")
+	task, err = reg.GetTask(id("2"))
+	require.NoError(t, err)
+	assert.Equal(t, "Add callback", task.Content)
+	assert.Equal(t, PriorityHigh, task.Priority)
+	_, err = reg.GetTask(id("3"))
+	require.Error(t, err)
+
+	// A line edited in place keeps its position
+	require.NoError(t, os.WriteFile(tasksPath, []byte("# Tasks\n\n- [x] Add provider\n- [ ] Add OAuth callback\n"), 0o600))
+	result, err = reg.Sync(SyncOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, result.Renumbered)
+	assert.Equal(t, []TaskID{id("2")}, result.Updated)
+	task, err = reg.GetTask(id("2"))
+	require.NoError(t, err)
+	assert.Equal(t, PriorityHigh, task.Priority)
+}
+
+func TestMergeSyncState(t *testing.T) {
+	t.Parallel()
+
+	base := TaskSyncState{Content: "Add provider"}
+
+	tests := []struct {
+		name      string
+		file      TaskSyncState
+		reg       TaskSyncState
+		want      TaskSyncState
+		conflicts []string
+	}{
+		{
+			name: "unchanged",
+			file: base,
+			reg:  base,
+			want: base,
+		},
+		{
+			name: "file edit",
+			file: TaskSyncState{Content: "Add OIDC provider"},
+			reg:  base,
+			want: TaskSyncState{Content: "Add OIDC provider"},
+		},
+		{
+			name: "registry edit",
+			file: base,
+			reg:  TaskSyncState{Done: true, Content: "Add provider"},
+			want: TaskSyncState{Done: true, Content: "Add provider"},
+		},
+		{
+			name: "edits to different fields merge",
+			file: TaskSyncState{Content: "Add OIDC provider"},
+			reg:  TaskSyncState{Done: true, Content: "Add provider"},
+			want: TaskSyncState{Done: true, Content: "Add OIDC provider"},
+		},
+		{
+			name: "same edit on both sides",
+			file: TaskSyncState{Done: true, Content: "Add provider"},
+			reg:  TaskSyncState{Done: true, Content: "Add provider"},
+			want: TaskSyncState{Done: true, Content: "Add provider"},
+		},
+		{
+			name:      "conflicting edits keep the base",
+			file:      TaskSyncState{Content: "Add OIDC provider"},
+			reg:       TaskSyncState{Content: "Add SAML provider"},
+			want:      base,
+			conflicts: []string{"content"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, conflicts := mergeSyncState(base, tt.file, tt.reg)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.conflicts, conflicts)
+		})
+	}
+}
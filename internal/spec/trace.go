@@ -0,0 +1,543 @@
+package spec
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	astpkg "github.com/victorzhuk/go-ent/internal/ast"
+)
+
+// TraceLinkKind says how a piece of code was linked to a requirement.
+type TraceLinkKind string
+
+const (
+	// TraceAnnotation is a "// spec: capability/Requirement" comment.
+	TraceAnnotation TraceLinkKind = "annotation"
+	// TraceSymbol is a declaration or test named after the requirement.
+	TraceSymbol TraceLinkKind = "symbol"
+	// TraceCommit is a file changed by a commit that mentions a task of a
+	// change whose deltas touch the requirement.
+	TraceCommit TraceLinkKind = "commit"
+)
+
+// TraceLink points at code or a test that implements a requirement.
+type TraceLink struct {
+	Kind   TraceLinkKind `json:"kind"`
+	File   string        `json:"file"`
+	Line   int           `json:"line,omitempty"`
+	Symbol string        `json:"symbol,omitempty"`
+	Commit string        `json:"commit,omitempty"`
+	Task   string        `json:"task,omitempty"`
+}
+
+// TraceEntry is one row of the traceability matrix.
+type TraceEntry struct {
+	Capability  string      `json:"capability"`
+	Requirement string      `json:"requirement"`
+	Line        int         `json:"line"`
+	Code        []TraceLink `json:"code"`
+	Tests       []TraceLink `json:"tests"`
+}
+
+// Ref returns the "capability/Requirement" form used by annotations.
+func (e TraceEntry) Ref() string {
+	return e.Capability + "/" + e.Requirement
+}
+
+// StaleReference is an annotation naming a requirement that no longer exists.
+type StaleReference struct {
+	Ref    string `json:"ref"`
+	File   string `json:"file"`
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// TraceMatrix maps every requirement in openspec/specs to the code and tests
+// that implement it.
+type TraceMatrix struct {
+	Requirements []TraceEntry        `json:"requirements"`
+	OrphanTests  []TraceLink         `json:"orphan_tests"`
+	Stale        []StaleReference    `json:"stale_references"`
+	Commits      int                 `json:"commits_scanned"`
+	index        map[string]int      // "capability/Requirement" -> Requirements index
+	capabilities map[string]bool     // known capability IDs
+	linkedTests  map[string]bool     // "file" or "file#Test" keys of linked tests
+	seen         map[string]bool     // dedupe key for links
+	tests        []TraceLink         // every test function found
+	changeReqs   map[string][]string // change ID -> requirement refs its deltas touch
+}
+
+// Untested returns the requirements without any linked test.
+func (m *TraceMatrix) Untested() []TraceEntry {
+	var out []TraceEntry
+	for _, e := range m.Requirements {
+		if len(e.Tests) == 0 {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// TraceOptions controls which sources BuildTrace reads.
+type TraceOptions struct {
+	// Dir is the directory scanned for Go files, defaulting to the store root.
+	Dir string
+	// SkipGit disables task→commit links from git history.
+	SkipGit bool
+}
+
+var (
+	specAnnotationRe = regexp.MustCompile(`^//\s*spec:\s*(.+?)\s*$`)
+	archivedChangeRe = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-(.+)$`)
+	taskRefRe        = regexp.MustCompile(`\b([a-z0-9][a-z0-9-]*)/(\d+(?:\.\d+)*)\b`)
+)
+
+// BuildTrace builds the traceability matrix for the specs in store. Links
+// come from spec annotations in Go comments, from declarations and tests
+// named after a requirement, and from commits that mention a task ID.
+func BuildTrace(store *Store, opts TraceOptions) (*TraceMatrix, error) {
+	if opts.Dir == "" {
+		opts.Dir = store.RootPath()
+	}
+
+	m := &TraceMatrix{
+		index:        make(map[string]int),
+		capabilities: make(map[string]bool),
+		linkedTests:  make(map[string]bool),
+		seen:         make(map[string]bool),
+		changeReqs:   make(map[string][]string),
+	}
+
+	if err := m.loadRequirements(store); err != nil {
+		return nil, err
+	}
+	if err := m.scanGoFiles(opts.Dir); err != nil {
+		return nil, err
+	}
+	if !opts.SkipGit {
+		m.loadChangeRequirements(store)
+		m.scanCommits(opts.Dir)
+	}
+
+	for _, t := range m.tests {
+		if !m.linkedTests[t.File] && !m.linkedTests[t.File+"#"+t.Symbol] {
+			m.OrphanTests = append(m.OrphanTests, t)
+		}
+	}
+
+	for i := range m.Requirements {
+		sortLinks(m.Requirements[i].Code)
+		sortLinks(m.Requirements[i].Tests)
+	}
+	sort.Slice(m.Stale, func(i, j int) bool {
+		if m.Stale[i].File != m.Stale[j].File {
+			return m.Stale[i].File < m.Stale[j].File
+		}
+		return m.Stale[i].Line < m.Stale[j].Line
+	})
+	return m, nil
+}
+
+func (m *TraceMatrix) loadRequirements(store *Store) error {
+	specs, err := store.ListSpecs()
+	if err != nil {
+		return err
+	}
+
+	for _, s := range specs {
+		m.capabilities[s.ID] = true
+
+		content, err := os.ReadFile(s.Path) // #nosec G304 -- controlled file path
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("read spec %s: %w", s.ID, err)
+		}
+		for _, req := range ParseDocument(string(content)).Requirements() {
+			entry := TraceEntry{Capability: s.ID, Requirement: req.Name, Line: req.Line}
+			m.index[entry.Ref()] = len(m.Requirements)
+			m.Requirements = append(m.Requirements, entry)
+		}
+	}
+	return nil
+}
+
+// scanGoFiles walks dir for Go files, skipping hidden, vendor and testdata
+// directories.
+func (m *TraceMatrix) scanGoFiles(dir string) error {
+	idents := make(map[string][]int)
+	for i, e := range m.Requirements {
+		id := strings.ToLower(requirementIdent(e.Requirement))
+		idents[id] = append(idents[id], i)
+	}
+
+	fset := token.NewFileSet()
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != dir && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "vendor" || name == "testdata" || name == "node_modules") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		f, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			// Files that do not parse cannot be traced; skip them.
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			rel = path
+		}
+		m.scanFile(fset, f, filepath.ToSlash(rel), idents)
+		return nil
+	})
+}
+
+func (m *TraceMatrix) scanFile(fset *token.FileSet, f *goast.File, file string, idents map[string][]int) {
+	isTest := strings.HasSuffix(file, "_test.go")
+
+	if isTest {
+		for _, r := range astpkg.FindFunctions(f, "Test*") {
+			if r.Name == "TestMain" {
+				continue
+			}
+			m.tests = append(m.tests, TraceLink{Kind: TraceSymbol, File: file, Line: declLine(fset, f, r.Name), Symbol: r.Name})
+		}
+	}
+
+	for _, group := range f.Comments {
+		for _, c := range group.List {
+			match := specAnnotationRe.FindStringSubmatch(c.Text)
+			if match == nil {
+				continue
+			}
+			line := fset.Position(c.Pos()).Line
+			idx, reason := m.resolveRef(match[1])
+			if reason != "" {
+				m.Stale = append(m.Stale, StaleReference{Ref: match[1], File: file, Line: line, Reason: reason})
+				continue
+			}
+			symbol := enclosingDecl(f, group)
+			m.link(idx, isTest, TraceLink{Kind: TraceAnnotation, File: file, Line: line, Symbol: symbol})
+		}
+	}
+
+	scope, err := astpkg.NewBuilder(fset).BuildFile(f)
+	if err != nil {
+		return
+	}
+	for name, sym := range scope.Symbols {
+		if sym.Kind == astpkg.SymbolPackage {
+			continue
+		}
+		for _, idx := range symbolMatches(name, isTest, idents) {
+			m.link(idx, isTest, TraceLink{Kind: TraceSymbol, File: file, Line: fset.Position(sym.Pos).Line, Symbol: name})
+		}
+	}
+}
+
+// resolveRef finds the requirement a "capability/Requirement" annotation
+// names, or explains why it is stale.
+func (m *TraceMatrix) resolveRef(ref string) (int, string) {
+	capability, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		return -1, "expected capability/Requirement"
+	}
+	capability, name = strings.TrimSpace(capability), strings.TrimSpace(name)
+	if !m.capabilities[capability] {
+		return -1, fmt.Sprintf("capability %s not found", capability)
+	}
+	idx, ok := m.index[capability+"/"+name]
+	if !ok {
+		return -1, fmt.Sprintf("requirement %q not found in %s", name, capability)
+	}
+	return idx, ""
+}
+
+func (m *TraceMatrix) link(idx int, isTest bool, l TraceLink) {
+	key := fmt.Sprintf("%d|%s|%s|%d|%s", idx, l.Kind, l.File, l.Line, l.Symbol)
+	if m.seen[key] {
+		return
+	}
+	m.seen[key] = true
+
+	e := &m.Requirements[idx]
+	if !isTest {
+		e.Code = append(e.Code, l)
+		return
+	}
+	e.Tests = append(e.Tests, l)
+	if l.Symbol != "" && strings.HasPrefix(l.Symbol, "Test") {
+		m.linkedTests[l.File+"#"+l.Symbol] = true
+	} else {
+		m.linkedTests[l.File] = true
+	}
+}
+
+// loadChangeRequirements records, for pending and archived changes, the
+// requirements their delta specs add, modify or rename to.
+func (m *TraceMatrix) loadChangeRequirements(store *Store) {
+	changesPath := filepath.Join(store.SpecPath(), "changes")
+	dirs := map[string]string{}
+
+	if entries, err := os.ReadDir(changesPath); err == nil {
+		for _, e := range entries {
+			if e.IsDir() && e.Name() != "archive" {
+				dirs[e.Name()] = filepath.Join(changesPath, e.Name())
+			}
+		}
+	}
+	if entries, err := os.ReadDir(filepath.Join(changesPath, "archive")); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() {
+				continue
+			}
+			id := e.Name()
+			if match := archivedChangeRe.FindStringSubmatch(id); match != nil {
+				id = match[1]
+			}
+			if _, ok := dirs[id]; !ok {
+				dirs[id] = filepath.Join(changesPath, "archive", e.Name())
+			}
+		}
+	}
+
+	for id, dir := range dirs {
+		touches := make(map[[2]string][]DeltaTouch)
+		if err := collectTouches(id, dir, touches); err != nil {
+			continue
+		}
+		for key, ts := range touches {
+			for _, t := range ts {
+				if t.Op == "REMOVED" {
+					continue
+				}
+				if t.Op == "RENAMED" && !strings.HasSuffix(t.Rename, " → "+key[1]) {
+					continue
+				}
+				m.changeReqs[id] = append(m.changeReqs[id], key[0]+"/"+key[1])
+				break
+			}
+		}
+		sort.Strings(m.changeReqs[id])
+	}
+}
+
+// scanCommits links Go files changed by commits that mention a task ID
+// ("change-id/1.2") to the requirements of that change. Directories that are
+// not git repositories are skipped.
+func (m *TraceMatrix) scanCommits(dir string) {
+	if len(m.changeReqs) == 0 {
+		return
+	}
+
+	cmd := exec.Command("git", "-C", dir, "log", "--no-merges", "--relative", "--name-only", "--format=%x1e%H%x1f%B%x1f") // #nosec G204 -- fixed arguments
+	out, err := cmd.Output()
+	if err != nil {
+		return
+	}
+
+	for _, record := range strings.Split(string(out), "\x1e") {
+		parts := strings.SplitN(record, "\x1f", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		m.Commits++
+
+		hash, message, files := parts[0], parts[1], strings.Fields(parts[2])
+		if len(hash) > 7 {
+			hash = hash[:7]
+		}
+
+		for _, ref := range taskRefRe.FindAllStringSubmatch(message, -1) {
+			reqs := m.changeReqs[ref[1]]
+			for _, reqRef := range reqs {
+				idx, ok := m.index[reqRef]
+				if !ok {
+					continue
+				}
+				for _, file := range files {
+					if !strings.HasSuffix(file, ".go") {
+						continue
+					}
+					if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+						continue
+					}
+					m.link(idx, strings.HasSuffix(file, "_test.go"), TraceLink{Kind: TraceCommit, File: file, Commit: hash, Task: ref[0]})
+				}
+			}
+		}
+	}
+}
+
+// requirementIdent turns a requirement name into a Go identifier:
+// "MCP tool input schemas" becomes "MCPToolInputSchemas".
+func requirementIdent(name string) string {
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// symbolMatches returns the requirements a declaration is named after. Tests
+// match on any underscore-separated part after the Test prefix, so
+// TestProjectGenerationTool_InvalidPath traces to "Project Generation Tool".
+func symbolMatches(name string, isTest bool, idents map[string][]int) []int {
+	if !isTest {
+		return idents[strings.ToLower(name)]
+	}
+	rest, ok := strings.CutPrefix(name, "Test")
+	if !ok {
+		return nil
+	}
+	var out []int
+	for _, part := range strings.Split(rest, "_") {
+		out = append(out, idents[strings.ToLower(part)]...)
+	}
+	return out
+}
+
+// enclosingDecl names the function or type a comment group documents or sits
+// in, or returns "" for file-level comments.
+func enclosingDecl(f *goast.File, group *goast.CommentGroup) string {
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *goast.FuncDecl:
+			if d.Doc == group || (d.Pos() <= group.Pos() && group.End() <= d.End()) {
+				return d.Name.Name
+			}
+		case *goast.GenDecl:
+			if d.Doc != group && (group.Pos() < d.Pos() || d.End() < group.End()) {
+				continue
+			}
+			for _, s := range d.Specs {
+				if ts, ok := s.(*goast.TypeSpec); ok {
+					return ts.Name.Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func sortLinks(links []TraceLink) {
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].File != links[j].File {
+			return links[i].File < links[j].File
+		}
+		return links[i].Line < links[j].Line
+	})
+}
+
+func declLine(fset *token.FileSet, f *goast.File, name string) int {
+	for _, decl := range f.Decls {
+		if fn, ok := decl.(*goast.FuncDecl); ok && fn.Name.Name == name {
+			return fset.Position(fn.Pos()).Line
+		}
+	}
+	return 0
+}
+
+// Markdown renders the matrix as a review report.
+func (m *TraceMatrix) Markdown() string {
+	var sb strings.Builder
+
+	untested := m.Untested()
+	sb.WriteString("# Traceability Matrix\n\n")
+	sb.WriteString(fmt.Sprintf("%d requirement(s), %d untested, %d orphan test(s), %d stale reference(s)",
+		len(m.Requirements), len(untested), len(m.OrphanTests), len(m.Stale)))
+	if m.Commits > 0 {
+		sb.WriteString(fmt.Sprintf(", %d commit(s) scanned", m.Commits))
+	}
+	sb.WriteString("\n\n")
+
+	if len(m.Requirements) > 0 {
+		sb.WriteString("| Requirement | Code | Tests |\n")
+		sb.WriteString("|-------------|------|-------|\n")
+		for _, e := range m.Requirements {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s |\n", e.Ref(), formatLinks(e.Code), formatLinks(e.Tests)))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(untested) > 0 {
+		sb.WriteString("## Untested Requirements\n\n")
+		for _, e := range untested {
+			sb.WriteString(fmt.Sprintf("- %s (specs/%s/spec.md:%d)\n", e.Ref(), e.Capability, e.Line))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(m.OrphanTests) > 0 {
+		sb.WriteString("## Orphan Tests\n\n")
+		for _, t := range m.OrphanTests {
+			sb.WriteString(fmt.Sprintf("- %s (%s:%d)\n", t.Symbol, t.File, t.Line))
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(m.Stale) > 0 {
+		sb.WriteString("## Stale References\n\n")
+		for _, r := range m.Stale {
+			sb.WriteString(fmt.Sprintf("- `spec: %s` (%s:%d): %s\n", r.Ref, r.File, r.Line, r.Reason))
+		}
+		sb.WriteString("\n")
+	}
+
+	return strings.TrimRight(sb.String(), "\n") + "\n"
+}
+
+func formatLinks(links []TraceLink) string {
+	if len(links) == 0 {
+		return "—"
+	}
+	parts := make([]string, 0, len(links))
+	for _, l := range links {
+		parts = append(parts, l.String())
+	}
+	return strings.Join(parts, "<br>")
+}
+
+// String prints the link as file:line with the symbol or commit it came from.
+func (l TraceLink) String() string {
+	loc := l.File
+	if l.Line > 0 {
+		loc = fmt.Sprintf("%s:%d", l.File, l.Line)
+	}
+	switch {
+	case l.Commit != "":
+		return fmt.Sprintf("%s (%s %s)", loc, l.Commit, l.Task)
+	case l.Symbol != "":
+		return fmt.Sprintf("%s %s", loc, l.Symbol)
+	}
+	return loc
+}
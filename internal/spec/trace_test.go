@@ -0,0 +1,153 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const traceSpec = `# Auth
+
+### Requirement: Login
+
+#### Scenario: Valid password
+- WHEN the password matches
+- THEN a session starts
+
+### Requirement: Password Reset
+
+### Requirement: Audit Log
+`
+
+func writeTraceProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	files["openspec/specs/auth/spec.md"] = traceSpec
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	return root
+}
+
+func TestBuildTrace(t *testing.T) {
+	t.Parallel()
+
+	root := writeTraceProject(t, map[string]string{
+		"auth/login.go": `package auth
+
+// Login checks credentials.
+//
+// spec: auth/Login
+func Login() {}
+
+// PasswordReset sends a reset link.
+func PasswordReset() {}
+`,
+		"auth/login_test.go": `package auth
+
+import "testing"
+
+func TestLogin_ValidPassword(t *testing.T) {}
+
+func TestReset(t *testing.T) {
+	// spec: auth/Password Reset
+}
+
+// spec: auth/Logout
+func TestLogout(t *testing.T) {}
+
+func TestHelpers(t *testing.T) {}
+`,
+		"vendor/x/x.go":  "package x\n\n// spec: auth/Audit Log\nfunc AuditLog() {}\n",
+		"broken/bad.go":  "package broken\n\nfunc {",
+		"other/other.go": "package other\n\n// spec: billing/Invoices\nvar _ = 1\n",
+	})
+
+	matrix, err := BuildTrace(NewStore(root), TraceOptions{SkipGit: true})
+	require.NoError(t, err)
+	require.Len(t, matrix.Requirements, 3)
+
+	login := matrix.Requirements[0]
+	assert.Equal(t, "auth/Login", login.Ref())
+	assert.Equal(t, []TraceLink{
+		{Kind: TraceAnnotation, File: "auth/login.go", Line: 5, Symbol: "Login"},
+		{Kind: TraceSymbol, File: "auth/login.go", Line: 6, Symbol: "Login"},
+	}, login.Code)
+	assert.Equal(t, []TraceLink{
+		{Kind: TraceSymbol, File: "auth/login_test.go", Line: 5, Symbol: "TestLogin_ValidPassword"},
+	}, login.Tests)
+
+	reset := matrix.Requirements[1]
+	assert.Equal(t, []TraceLink{{Kind: TraceSymbol, File: "auth/login.go", Line: 9, Symbol: "PasswordReset"}}, reset.Code)
+	assert.Equal(t, []TraceLink{{Kind: TraceAnnotation, File: "auth/login_test.go", Line: 8, Symbol: "TestReset"}}, reset.Tests)
+
+	audit := matrix.Requirements[2]
+	assert.Empty(t, audit.Code, "vendor is not scanned")
+	assert.Equal(t, []TraceEntry{audit}, matrix.Untested())
+
+	var orphans []string
+	for _, o := range matrix.OrphanTests {
+		orphans = append(orphans, o.Symbol)
+	}
+	assert.Equal(t, []string{"TestLogout", "TestHelpers"}, orphans)
+
+	require.Len(t, matrix.Stale, 2)
+	assert.Equal(t, StaleReference{Ref: "auth/Logout", File: "auth/login_test.go", Line: 11, Reason: `requirement "Logout" not found in auth`}, matrix.Stale[0])
+	assert.Equal(t, "capability billing not found", matrix.Stale[1].Reason)
+
+	report := matrix.Markdown()
+	assert.Contains(t, report, "3 requirement(s), 1 untested, 2 orphan test(s), 2 stale reference(s)\n")
+	assert.Contains(t, report, "| auth/Audit Log | — | — |\n")
+	assert.Contains(t, report, "## Untested Requirements\n\n- auth/Audit Log (specs/auth/spec.md:11)\n")
+	assert.Contains(t, report, "- `spec: auth/Logout` (auth/login_test.go:11): requirement \"Logout\" not found in auth\n")
+}
+
+func TestBuildTrace_Commits(t *testing.T) {
+	t.Parallel()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	root := writeTraceProject(t, map[string]string{
+		"openspec/changes/archive/2026-01-02-add-audit/specs/auth/spec.md": "## ADDED Requirements\n\n### Requirement: Audit Log\n",
+		"audit/audit.go":      "package audit\n\nfunc Record() {}\n",
+		"audit/audit_test.go": "package audit\n\nimport \"testing\"\n\nfunc TestRecord(t *testing.T) {}\n",
+	})
+
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-qm", "Record audit events\n\nCloses add-audit/1.1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = root
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	matrix, err := BuildTrace(NewStore(root), TraceOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, matrix.Commits)
+
+	audit := matrix.Requirements[2]
+	require.Len(t, audit.Code, 1)
+	assert.Equal(t, "audit/audit.go", audit.Code[0].File)
+	assert.Equal(t, TraceCommit, audit.Code[0].Kind)
+	assert.Equal(t, "add-audit/1.1", audit.Code[0].Task)
+	require.Len(t, audit.Tests, 1)
+	assert.Equal(t, "audit/audit_test.go", audit.Tests[0].File)
+	assert.True(t, strings.HasPrefix(audit.Tests[0].String(), "audit/audit_test.go ("))
+
+	assert.Empty(t, matrix.OrphanTests, "tests in a commit-linked file are traced")
+}
@@ -0,0 +1,519 @@
+package spec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TrackerFormat is a file format tasks are imported from and exported to.
+type TrackerFormat string
+
+const (
+	// TrackerGitHub is the JSON array printed by
+	// gh issue list --json number,title,body,state,labels,assignees.
+	TrackerGitHub TrackerFormat = "github"
+	// TrackerJira is a Jira issue CSV export.
+	TrackerJira TrackerFormat = "jira"
+	// TrackerJSONL is one change or task record per line, without loss.
+	TrackerJSONL TrackerFormat = "jsonl"
+)
+
+// Label prefixes carrying registry fields on GitHub issues and Jira labels.
+const (
+	labelChange   = "change:"
+	labelPriority = "priority:"
+	labelStatus   = "status:"
+)
+
+var (
+	ErrUnknownTrackerFormat = errors.New("unknown tracker format")
+
+	taskMarkerRegex = regexp.MustCompile(`<!--\s*openspec-task:\s*(\S+?)\s*-->`)
+	issueDepRegex   = regexp.MustCompile(`(?i)\b(?:depends on|blocked by)\s+(#\d+)`)
+)
+
+// TrackerIssue is a task as an issue tracker sees it, the common form every
+// format is read into before it is matched against the registry.
+type TrackerIssue struct {
+	// Key identifies the issue in its tracker: "#12", "SSO-4" or a task ID.
+	Key string `json:"key"`
+	// TaskID is set when the issue names the registry task it came from.
+	TaskID   TaskID             `json:"task_id"`
+	ChangeID string             `json:"change_id"`
+	Title    string             `json:"title"`
+	Status   RegistryTaskStatus `json:"status"`
+	Priority TaskPriority       `json:"priority,omitempty"`
+	Assignee string             `json:"assignee,omitempty"`
+	// DependsOn holds issue keys, task IDs, or task numbers of the same change.
+	DependsOn []string `json:"depends_on,omitempty"`
+	Notes     string   `json:"notes,omitempty"`
+}
+
+// TrackerImport is the content of an import file.
+type TrackerImport struct {
+	Changes []ChangeSummary `json:"changes,omitempty"`
+	Issues  []TrackerIssue  `json:"issues"`
+}
+
+// ParseTrackerFormat validates a format name.
+func ParseTrackerFormat(name string) (TrackerFormat, error) {
+	switch f := TrackerFormat(strings.ToLower(name)); f {
+	case TrackerGitHub, TrackerJira, TrackerJSONL:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %s (use github, jira or jsonl)", ErrUnknownTrackerFormat, name)
+}
+
+// TrackerFormatFromPath guesses the format from a file extension: .jsonl,
+// .csv for Jira, and .json for GitHub.
+func TrackerFormatFromPath(path string) (TrackerFormat, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return TrackerJSONL, nil
+	case ".csv":
+		return TrackerJira, nil
+	case ".json":
+		return TrackerGitHub, nil
+	}
+	return "", fmt.Errorf("%w: cannot tell the format of %s, name it", ErrUnknownTrackerFormat, path)
+}
+
+// ExportTasks writes changes and tasks in format.
+func ExportTasks(w io.Writer, format TrackerFormat, changes []ChangeSummary, tasks []RegistryTask) error {
+	sorted := make([]RegistryTask, len(tasks))
+	copy(sorted, tasks)
+	sort.SliceStable(sorted, func(i, j int) bool { return taskIDLess(sorted[i].ID, sorted[j].ID) })
+
+	switch format {
+	case TrackerGitHub:
+		return exportGitHub(w, sorted)
+	case TrackerJira:
+		return exportJira(w, sorted)
+	case TrackerJSONL:
+		return exportJSONL(w, changes, sorted)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownTrackerFormat, format)
+}
+
+// ParseTrackerImport reads an import file in format.
+func ParseTrackerImport(r io.Reader, format TrackerFormat) (*TrackerImport, error) {
+	switch format {
+	case TrackerGitHub:
+		return parseGitHub(r)
+	case TrackerJira:
+		return parseJira(r)
+	case TrackerJSONL:
+		return parseJSONL(r)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownTrackerFormat, format)
+}
+
+type githubIssue struct {
+	Number    int           `json:"number,omitempty"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	State     string        `json:"state"`
+	Labels    []githubLabel `json:"labels"`
+	Assignees []githubUser  `json:"assignees"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+func exportGitHub(w io.Writer, tasks []RegistryTask) error {
+	issues := make([]githubIssue, 0, len(tasks))
+	for _, t := range tasks {
+		issue := githubIssue{
+			Title:     t.Content,
+			Body:      trackerBody(t),
+			State:     "OPEN",
+			Labels:    []githubLabel{},
+			Assignees: []githubUser{},
+		}
+		if t.Status == RegStatusCompleted || t.Status == RegStatusSkipped {
+			issue.State = "CLOSED"
+		}
+		for _, l := range trackerLabels(t) {
+			issue.Labels = append(issue.Labels, githubLabel{Name: l})
+		}
+		if t.Assignee != "" {
+			issue.Assignees = append(issue.Assignees, githubUser{Login: t.Assignee})
+		}
+		issues = append(issues, issue)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+func parseGitHub(r io.Reader) (*TrackerImport, error) {
+	var issues []githubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("decode github issues: %w", err)
+	}
+
+	imp := &TrackerImport{Issues: make([]TrackerIssue, 0, len(issues))}
+	for _, gi := range issues {
+		issue := TrackerIssue{
+			Title:  strings.TrimSpace(gi.Title),
+			Status: RegStatusPending,
+		}
+		if strings.EqualFold(gi.State, "closed") {
+			issue.Status = RegStatusCompleted
+		}
+		if len(gi.Assignees) > 0 {
+			issue.Assignee = gi.Assignees[0].Login
+		}
+
+		labels := make([]string, 0, len(gi.Labels))
+		for _, l := range gi.Labels {
+			labels = append(labels, l.Name)
+		}
+		applyTrackerLabels(&issue, labels)
+		applyTrackerBody(&issue, gi.Body)
+		for _, m := range issueDepRegex.FindAllStringSubmatch(gi.Body, -1) {
+			issue.DependsOn = appendUnique(issue.DependsOn, m[1])
+		}
+		// Issues exported from the registry have no number yet
+		issue.Key = issue.TaskID.String()
+		if gi.Number > 0 {
+			issue.Key = "#" + strconv.Itoa(gi.Number)
+		}
+		imp.Issues = append(imp.Issues, issue)
+	}
+	return imp, nil
+}
+
+// Jira CSV columns. Labels and issue links repeat once per value, as in
+// Jira's own export.
+const (
+	jiraColSummary     = "Summary"
+	jiraColKey         = "Issue key"
+	jiraColStatus      = "Status"
+	jiraColPriority    = "Priority"
+	jiraColAssignee    = "Assignee"
+	jiraColLabels      = "Labels"
+	jiraColDescription = "Description"
+	jiraColBlockedBy   = "Inward issue link (Blocks)"
+	jiraColTask        = "OpenSpec Task"
+)
+
+var (
+	jiraStatusNames = map[RegistryTaskStatus]string{
+		RegStatusPending:    "To Do",
+		RegStatusInProgress: "In Progress",
+		RegStatusCompleted:  "Done",
+		RegStatusBlocked:    "Blocked",
+		RegStatusSkipped:    "Won't Do",
+	}
+	jiraPriorityNames = map[TaskPriority]string{
+		PriorityCritical: "Highest",
+		PriorityHigh:     "High",
+		PriorityMedium:   "Medium",
+		PriorityLow:      "Low",
+		PriorityBacklog:  "Lowest",
+	}
+)
+
+func exportJira(w io.Writer, tasks []RegistryTask) error {
+	maxLabels, maxDeps := 1, 1
+	for _, t := range tasks {
+		maxLabels = max(maxLabels, len(jiraLabelsFor(t)))
+		maxDeps = max(maxDeps, len(t.DependsOn))
+	}
+
+	header := []string{jiraColSummary, jiraColKey, jiraColStatus, jiraColPriority, jiraColAssignee}
+	for range maxLabels {
+		header = append(header, jiraColLabels)
+	}
+	header = append(header, jiraColDescription)
+	for range maxDeps {
+		header = append(header, jiraColBlockedBy)
+	}
+	header = append(header, jiraColTask)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		row := []string{t.Content, "", jiraStatusNames[t.Status], jiraPriorityNames[t.Priority], t.Assignee}
+		row = append(row, padded(jiraLabelsFor(t), maxLabels)...)
+		row = append(row, t.Notes)
+		deps := make([]string, 0, len(t.DependsOn))
+		for _, d := range t.DependsOn {
+			deps = append(deps, d.String())
+		}
+		row = append(row, padded(deps, maxDeps)...)
+		row = append(row, t.ID.String())
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func parseJira(r io.Reader) (*TrackerImport, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read jira csv: %w", err)
+	}
+	if len(records) == 0 {
+		return &TrackerImport{Issues: make([]TrackerIssue, 0)}, nil
+	}
+
+	columns := make(map[string][]int)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		columns[name] = append(columns[name], i)
+	}
+	if len(columns[jiraColSummary]) == 0 {
+		return nil, fmt.Errorf("read jira csv: missing %q column", jiraColSummary)
+	}
+
+	values := func(row []string, name string) []string {
+		var out []string
+		for _, i := range columns[name] {
+			if i < len(row) && strings.TrimSpace(row[i]) != "" {
+				out = append(out, strings.TrimSpace(row[i]))
+			}
+		}
+		return out
+	}
+	value := func(row []string, name string) string {
+		if v := values(row, name); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+
+	imp := &TrackerImport{Issues: make([]TrackerIssue, 0, len(records)-1)}
+	for _, row := range records[1:] {
+		issue := TrackerIssue{
+			Key:       value(row, jiraColKey),
+			Title:     value(row, jiraColSummary),
+			Status:    jiraStatus(value(row, jiraColStatus)),
+			Priority:  jiraPriority(value(row, jiraColPriority)),
+			Assignee:  value(row, jiraColAssignee),
+			DependsOn: values(row, jiraColBlockedBy),
+			Notes:     value(row, jiraColDescription),
+		}
+		if issue.Title == "" {
+			continue
+		}
+		applyTrackerLabels(&issue, values(row, jiraColLabels))
+		if id, err := parseTrackerTaskID(value(row, jiraColTask)); err == nil {
+			issue.TaskID = id
+			if issue.ChangeID == "" {
+				issue.ChangeID = id.ChangeID
+			}
+		}
+		if issue.Key == "" {
+			issue.Key = issue.TaskID.String()
+		}
+		imp.Issues = append(imp.Issues, issue)
+	}
+	return imp, nil
+}
+
+func jiraStatus(name string) RegistryTaskStatus {
+	switch strings.ToLower(name) {
+	case "in progress", "in review", "review":
+		return RegStatusInProgress
+	case "done", "closed", "resolved":
+		return RegStatusCompleted
+	case "blocked":
+		return RegStatusBlocked
+	case "won't do", "wont do", "cancelled", "canceled", "rejected":
+		return RegStatusSkipped
+	}
+	return RegStatusPending
+}
+
+func jiraPriority(name string) TaskPriority {
+	for p, n := range jiraPriorityNames {
+		if strings.EqualFold(n, name) {
+			return p
+		}
+	}
+	switch strings.ToLower(name) {
+	case "blocker", "critical":
+		return PriorityCritical
+	case "minor", "trivial":
+		return PriorityLow
+	}
+	return ""
+}
+
+// jiraLabelsFor is trackerLabels without the labels Jira has columns for.
+func jiraLabelsFor(t RegistryTask) []string {
+	return []string{labelChange + t.ID.ChangeID}
+}
+
+// trackerRecord is one line of the JSON Lines format.
+type trackerRecord struct {
+	Kind   string         `json:"kind"`
+	Change *ChangeSummary `json:"change,omitempty"`
+	Task   *RegistryTask  `json:"task,omitempty"`
+}
+
+func exportJSONL(w io.Writer, changes []ChangeSummary, tasks []RegistryTask) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for i := range changes {
+		if err := enc.Encode(trackerRecord{Kind: "change", Change: &changes[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range tasks {
+		if err := enc.Encode(trackerRecord{Kind: "task", Task: &tasks[i]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseJSONL(r io.Reader) (*TrackerImport, error) {
+	imp := &TrackerImport{Issues: make([]TrackerIssue, 0)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec trackerRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		switch {
+		case rec.Kind == "change" && rec.Change != nil:
+			imp.Changes = append(imp.Changes, *rec.Change)
+		case rec.Kind == "task" && rec.Task != nil:
+			t := rec.Task
+			issue := TrackerIssue{
+				Key:      t.ID.String(),
+				TaskID:   t.ID,
+				ChangeID: t.ID.ChangeID,
+				Title:    t.Content,
+				Status:   t.Status,
+				Priority: t.Priority,
+				Assignee: t.Assignee,
+				Notes:    t.Notes,
+			}
+			for _, d := range t.DependsOn {
+				issue.DependsOn = append(issue.DependsOn, d.String())
+			}
+			imp.Issues = append(imp.Issues, issue)
+		default:
+			return nil, fmt.Errorf("line %d: unknown record kind %q", line, rec.Kind)
+		}
+	}
+	return imp, scanner.Err()
+}
+
+// trackerLabels maps change, priority and the statuses an open or closed
+// issue cannot express to labels.
+func trackerLabels(t RegistryTask) []string {
+	labels := []string{labelChange + t.ID.ChangeID}
+	if t.Priority != "" {
+		labels = append(labels, labelPriority+string(t.Priority))
+	}
+	switch t.Status {
+	case RegStatusInProgress, RegStatusBlocked, RegStatusSkipped:
+		labels = append(labels, labelStatus+string(t.Status))
+	}
+	return labels
+}
+
+func applyTrackerLabels(issue *TrackerIssue, labels []string) {
+	for _, l := range labels {
+		switch {
+		case strings.HasPrefix(l, labelChange):
+			issue.ChangeID = strings.TrimPrefix(l, labelChange)
+		case strings.HasPrefix(l, labelPriority):
+			issue.Priority = TaskPriority(strings.TrimPrefix(l, labelPriority))
+		case strings.HasPrefix(l, labelStatus):
+			issue.Status = RegistryTaskStatus(strings.TrimPrefix(l, labelStatus))
+		}
+	}
+}
+
+// trackerBody renders notes followed by the task marker and dependencies as
+// HTML comments, which trackers hide when rendering Markdown.
+func trackerBody(t RegistryTask) string {
+	var sb strings.Builder
+	if t.Notes != "" {
+		sb.WriteString(t.Notes + "\n\n")
+	}
+	sb.WriteString(fmt.Sprintf("<!-- openspec-task: %s -->", t.ID.String()))
+	if len(t.DependsOn) > 0 {
+		deps := make([]string, 0, len(t.DependsOn))
+		for _, d := range t.DependsOn {
+			deps = append(deps, d.String())
+		}
+		sb.WriteString(fmt.Sprintf("\n<!-- depends: %s -->", strings.Join(deps, ", ")))
+	}
+	return sb.String()
+}
+
+func applyTrackerBody(issue *TrackerIssue, body string) {
+	if m := taskMarkerRegex.FindStringSubmatch(body); m != nil {
+		if id, err := parseTrackerTaskID(m[1]); err == nil {
+			issue.TaskID = id
+			if issue.ChangeID == "" {
+				issue.ChangeID = id.ChangeID
+			}
+		}
+	}
+	for _, dep := range ParseDependencies(body) {
+		issue.DependsOn = appendUnique(issue.DependsOn, dep)
+	}
+
+	notes := taskMarkerRegex.ReplaceAllString(body, "")
+	notes = depCommentRegex.ReplaceAllString(notes, "")
+	issue.Notes = strings.TrimSpace(notes)
+}
+
+func parseTrackerTaskID(s string) (TaskID, error) {
+	change, num, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok || change == "" || num == "" {
+		return TaskID{}, fmt.Errorf("invalid task ID: %s", s)
+	}
+	return TaskID{ChangeID: change, TaskNum: num}, nil
+}
+
+func appendUnique(list []string, v string) []string {
+	for _, e := range list {
+		if e == v {
+			return list
+		}
+	}
+	return append(list, v)
+}
+
+func padded(values []string, n int) []string {
+	out := make([]string, n)
+	copy(out, values)
+	return out
+}
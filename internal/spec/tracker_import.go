@@ -0,0 +1,413 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportOptions controls how imported issues are matched to the registry.
+type ImportOptions struct {
+	// ChangeID receives issues that do not name a change.
+	ChangeID string
+}
+
+// FieldChange is one field an import changes on an existing task.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// ImportedTask is a task an import adds or updates.
+type ImportedTask struct {
+	Key     string        `json:"key"`
+	Task    RegistryTask  `json:"task"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// ImportDiff is what an import would do to the registry and tasks.md.
+type ImportDiff struct {
+	Added     []ImportedTask `json:"added"`
+	Updated   []ImportedTask `json:"updated"`
+	Unchanged int            `json:"unchanged"`
+	Warnings  []string       `json:"warnings,omitempty"`
+}
+
+// DiffImport matches imported issues to registry tasks, by the task ID they
+// carry or else by change and title, and works out the tasks to add and the
+// status, priority, assignee and dependency changes to make. Titles of
+// existing tasks are left alone since tasks.md owns them.
+func (r *RegistryStore) DiffImport(imp *TrackerImport, opts ImportOptions) (*ImportDiff, error) {
+	existing, err := r.bolt.ListTasks(TaskFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
+
+	byID := make(map[string]*RegistryTask)
+	byTitle := make(map[string]*RegistryTask)
+	for i := range existing {
+		t := &existing[i]
+		byID[t.ID.String()] = t
+		byTitle[t.ID.ChangeID+"\x00"+t.Content] = t
+	}
+
+	diff := &ImportDiff{Added: make([]ImportedTask, 0), Updated: make([]ImportedTask, 0)}
+
+	// Resolve every issue to a task ID first so dependencies can point at
+	// issues further down the file.
+	type resolved struct {
+		issue TrackerIssue
+		id    TaskID
+		prev  *RegistryTask
+	}
+	var items []resolved
+	keys := make(map[string]TaskID)
+	nextNum := make(map[string]int)
+	seen := make(map[string]bool)
+
+	for _, issue := range imp.Issues {
+		change := issue.ChangeID
+		if change == "" {
+			change = opts.ChangeID
+		}
+		if change == "" {
+			diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s %q: no change, add a change: label or choose a default change", issue.Key, issue.Title))
+			continue
+		}
+
+		item := resolved{issue: issue}
+		switch {
+		case issue.TaskID.ChangeID == change && byID[issue.TaskID.String()] != nil:
+			item.prev = byID[issue.TaskID.String()]
+		case byTitle[change+"\x00"+issue.Title] != nil:
+			item.prev = byTitle[change+"\x00"+issue.Title]
+		}
+
+		if item.prev != nil {
+			item.id = item.prev.ID
+		} else {
+			if _, ok := nextNum[change]; !ok {
+				n, err := r.countSourceTasks(change)
+				if err != nil {
+					return nil, err
+				}
+				nextNum[change] = n + 1
+			}
+			item.id = TaskID{ChangeID: change, TaskNum: strconv.Itoa(nextNum[change])}
+			nextNum[change]++
+		}
+
+		if seen[item.id.String()] {
+			diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s %q: duplicate of %s, skipped", issue.Key, issue.Title, item.id))
+			continue
+		}
+		seen[item.id.String()] = true
+
+		if issue.Key != "" {
+			keys[issue.Key] = item.id
+		}
+		if !issue.TaskID.IsZero() {
+			keys[issue.TaskID.String()] = item.id
+		}
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		issue := item.issue
+
+		var deps []TaskID
+		for _, ref := range issue.DependsOn {
+			dep, ok := keys[ref]
+			if !ok {
+				id, err := parseTrackerTaskID(ref)
+				if err != nil {
+					id = TaskID{ChangeID: item.id.ChangeID, TaskNum: ref}
+				}
+				_, ok = byID[id.String()]
+				dep = id
+			}
+			if !ok {
+				diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s: unknown dependency %s, skipped", item.id, ref))
+				continue
+			}
+			if dep != item.id {
+				deps = append(deps, dep)
+			}
+		}
+		sort.Slice(deps, func(i, j int) bool { return taskIDLess(deps[i], deps[j]) })
+
+		status := issue.Status
+		if !validTaskStatus(status) {
+			if status != "" {
+				diff.Warnings = append(diff.Warnings, fmt.Sprintf("%s: unknown status %s, kept", item.id, status))
+			}
+			status = ""
+		}
+
+		if item.prev == nil {
+			task := RegistryTask{
+				ID:        item.id,
+				Content:   issue.Title,
+				Status:    RegStatusPending,
+				Priority:  PriorityMedium,
+				Assignee:  issue.Assignee,
+				Notes:     issue.Notes,
+				DependsOn: deps,
+			}
+			if status != "" {
+				task.Status = status
+			}
+			if issue.Priority != "" {
+				task.Priority = issue.Priority
+			}
+			diff.Added = append(diff.Added, ImportedTask{Key: issue.Key, Task: task})
+			continue
+		}
+
+		task := *item.prev
+		var changes []FieldChange
+		if status != "" && status != task.Status {
+			changes = append(changes, FieldChange{Field: "status", From: string(task.Status), To: string(status)})
+			task.Status = status
+		}
+		if issue.Priority != "" && issue.Priority != task.Priority {
+			changes = append(changes, FieldChange{Field: "priority", From: string(task.Priority), To: string(issue.Priority)})
+			task.Priority = issue.Priority
+		}
+		if issue.Assignee != task.Assignee {
+			changes = append(changes, FieldChange{Field: "assignee", From: task.Assignee, To: issue.Assignee})
+			task.Assignee = issue.Assignee
+		}
+		// Dependencies are only added: tasks.md comments would bring back
+		// any the tracker dropped on the next sync.
+		if merged := mergeTaskIDs(task.DependsOn, deps); len(merged) != len(task.DependsOn) {
+			changes = append(changes, FieldChange{Field: "depends_on", From: joinTaskIDs(task.DependsOn), To: joinTaskIDs(merged)})
+			task.DependsOn = merged
+		}
+
+		if len(changes) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Updated = append(diff.Updated, ImportedTask{Key: issue.Key, Task: task, Changes: changes})
+	}
+
+	return diff, nil
+}
+
+// ApplyImport writes a diff: new tasks are appended to the tasks.md of their
+// change and checkboxes follow status, then the registry is rebuilt and the
+// fields tasks.md cannot hold are set on it.
+func (r *RegistryStore) ApplyImport(diff *ImportDiff) error {
+	for _, it := range diff.Updated {
+		prev, err := r.bolt.GetTask(it.Task.ID)
+		if err != nil {
+			return fmt.Errorf("get task: %w", err)
+		}
+		if (prev.Status == RegStatusCompleted) == (it.Task.Status == RegStatusCompleted) {
+			continue
+		}
+		if err := r.stateStore.UpdateTaskInFile(it.Task.ID, it.Task.Status, ""); err != nil {
+			return fmt.Errorf("update task in file: %w", err)
+		}
+	}
+
+	var lines []string
+	change := ""
+	flush := func() error {
+		if len(lines) == 0 {
+			return nil
+		}
+		err := r.appendSourceTasks(change, lines)
+		lines = nil
+		return err
+	}
+	for _, it := range diff.Added {
+		if it.Task.ID.ChangeID != change {
+			if err := flush(); err != nil {
+				return err
+			}
+			change = it.Task.ID.ChangeID
+		}
+		lines = append(lines, taskSourceLine(it.Task))
+	}
+	if err := flush(); err != nil {
+		return err
+	}
+
+	if _, err := r.RebuildFromSource(); err != nil {
+		return fmt.Errorf("rebuild registry: %w", err)
+	}
+
+	for _, it := range append(append([]ImportedTask{}, diff.Added...), diff.Updated...) {
+		if err := r.applyImportedFields(it.Task); err != nil {
+			return fmt.Errorf("apply %s: %w", it.Task.ID, err)
+		}
+	}
+	return nil
+}
+
+func (r *RegistryStore) applyImportedFields(want RegistryTask) error {
+	notes := want.Notes
+	if err := r.UpdateTask(want.ID, TaskUpdate{
+		Status:   &want.Status,
+		Priority: &want.Priority,
+		Assignee: &want.Assignee,
+		Notes:    &notes,
+	}); err != nil {
+		return err
+	}
+
+	task, err := r.bolt.GetTask(want.ID)
+	if err != nil {
+		return err
+	}
+	task.DependsOn = want.DependsOn
+	if err := r.bolt.UpdateTask(task); err != nil {
+		return err
+	}
+	for _, dep := range want.DependsOn {
+		if err := r.bolt.AddDependency(want.ID, dep); err != nil {
+			return err
+		}
+	}
+	return r.recalculateBlockedByBolt(want.ID)
+}
+
+// countSourceTasks returns how many tasks the tasks.md of a change holds,
+// which fixes the number the next appended task gets.
+func (r *RegistryStore) countSourceTasks(changeID string) (int, error) {
+	tasksPath := filepath.Join(r.store.SpecPath(), "changes", changeID, "tasks.md")
+	if _, err := os.Stat(tasksPath); os.IsNotExist(err) {
+		return 0, nil
+	}
+	tasks, err := r.stateStore.ParseTasksWithDependencies(changeID, tasksPath)
+	if err != nil {
+		return 0, fmt.Errorf("parse tasks for %s: %w", changeID, err)
+	}
+	return len(tasks), nil
+}
+
+func (r *RegistryStore) appendSourceTasks(changeID string, lines []string) error {
+	changeDir := filepath.Join(r.store.SpecPath(), "changes", changeID)
+	if err := os.MkdirAll(changeDir, 0750); err != nil {
+		return fmt.Errorf("create change dir: %w", err)
+	}
+
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	content, err := os.ReadFile(tasksPath) // #nosec G304 -- controlled file path
+	switch {
+	case os.IsNotExist(err):
+		content = []byte("# Tasks\n")
+	case err != nil:
+		return fmt.Errorf("read tasks.md: %w", err)
+	}
+
+	text := string(content)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	text += "\n" + strings.Join(lines, "\n") + "\n"
+
+	if err := os.WriteFile(tasksPath, []byte(text), 0600); err != nil {
+		return fmt.Errorf("write tasks.md: %w", err)
+	}
+	return nil
+}
+
+// taskSourceLine renders a task as a tasks.md line. Only dependencies within
+// the same change fit the depends comment; the rest live in the registry.
+func taskSourceLine(t RegistryTask) string {
+	checked := " "
+	if t.Status == RegStatusCompleted {
+		checked = "x"
+	}
+	line := fmt.Sprintf("- [%s] %s", checked, t.Content)
+
+	var deps []string
+	for _, d := range t.DependsOn {
+		if d.ChangeID == t.ID.ChangeID {
+			deps = append(deps, d.TaskNum)
+		}
+	}
+	if len(deps) > 0 {
+		line += fmt.Sprintf(" <!-- depends: %s -->", strings.Join(deps, ", "))
+	}
+	return line
+}
+
+// String renders the diff one task per line: + added, ~ updated, ! warning.
+func (d *ImportDiff) String() string {
+	var sb strings.Builder
+	for _, it := range d.Added {
+		t := it.Task
+		sb.WriteString(fmt.Sprintf("+ %s %q [%s, %s]", t.ID, t.Content, t.Status, t.Priority))
+		if t.Assignee != "" {
+			sb.WriteString(" @" + t.Assignee)
+		}
+		if len(t.DependsOn) > 0 {
+			sb.WriteString(" depends on " + joinTaskIDs(t.DependsOn))
+		}
+		if it.Key != "" && it.Key != t.ID.String() {
+			sb.WriteString(" (" + it.Key + ")")
+		}
+		sb.WriteString("\n")
+	}
+	for _, it := range d.Updated {
+		parts := make([]string, 0, len(it.Changes))
+		for _, c := range it.Changes {
+			parts = append(parts, fmt.Sprintf("%s %s → %s", c.Field, orNone(c.From), orNone(c.To)))
+		}
+		sb.WriteString(fmt.Sprintf("~ %s %q: %s\n", it.Task.ID, it.Task.Content, strings.Join(parts, "; ")))
+	}
+	for _, w := range d.Warnings {
+		sb.WriteString("! " + w + "\n")
+	}
+	sb.WriteString(fmt.Sprintf("%d added, %d updated, %d unchanged\n", len(d.Added), len(d.Updated), d.Unchanged))
+	return sb.String()
+}
+
+func validTaskStatus(s RegistryTaskStatus) bool {
+	switch s {
+	case RegStatusPending, RegStatusInProgress, RegStatusCompleted, RegStatusBlocked, RegStatusSkipped:
+		return true
+	}
+	return false
+}
+
+func mergeTaskIDs(a, b []TaskID) []TaskID {
+	merged := append([]TaskID{}, a...)
+	for _, id := range b {
+		found := false
+		for _, e := range merged {
+			if e == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+func joinTaskIDs(ids []TaskID) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, id.String())
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
@@ -0,0 +1,244 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tasks := []RegistryTask{
+		{
+			ID:       TaskID{ChangeID: "add-sso", TaskNum: "1"},
+			Content:  "Add provider",
+			Status:   RegStatusCompleted,
+			Priority: PriorityHigh,
+			Assignee: "alice",
+			Notes:    "Use OIDC",
+		},
+		{
+			ID:        TaskID{ChangeID: "add-sso", TaskNum: "2"},
+			Content:   "Add callback",
+			Status:    RegStatusInProgress,
+			Priority:  PriorityCritical,
+			DependsOn: []TaskID{{ChangeID: "add-sso", TaskNum: "1"}},
+		},
+		{
+			ID:        TaskID{ChangeID: "add-sso", TaskNum: "3"},
+			Content:   "Document login",
+			Status:    RegStatusBlocked,
+			Priority:  PriorityLow,
+			DependsOn: []TaskID{{ChangeID: "add-sso", TaskNum: "1"}, {ChangeID: "add-sso", TaskNum: "2"}},
+		},
+	}
+
+	for _, format := range []TrackerFormat{TrackerGitHub, TrackerJira, TrackerJSONL} {
+		t.Run(string(format), func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+			require.NoError(t, ExportTasks(&buf, format, []ChangeSummary{{ID: "add-sso", Title: "Add SSO"}}, tasks))
+
+			imp, err := ParseTrackerImport(&buf, format)
+			require.NoError(t, err)
+			require.Len(t, imp.Issues, len(tasks))
+
+			for i, want := range tasks {
+				got := imp.Issues[i]
+				assert.Equal(t, want.ID, got.TaskID)
+				assert.Equal(t, "add-sso", got.ChangeID)
+				assert.Equal(t, want.Content, got.Title)
+				assert.Equal(t, want.Status, got.Status)
+				assert.Equal(t, want.Priority, got.Priority)
+				assert.Equal(t, want.Assignee, got.Assignee)
+				assert.Equal(t, want.Notes, got.Notes)
+				assert.Len(t, got.DependsOn, len(want.DependsOn))
+				for j, dep := range want.DependsOn {
+					assert.Equal(t, dep.String(), got.DependsOn[j])
+				}
+			}
+		})
+	}
+}
+
+func TestParseTrackerFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := ParseTrackerFormat("Jira")
+	require.NoError(t, err)
+	assert.Equal(t, TrackerJira, format)
+
+	_, err = ParseTrackerFormat("trello")
+	require.ErrorIs(t, err, ErrUnknownTrackerFormat)
+
+	tests := map[string]TrackerFormat{
+		"issues.json":  TrackerGitHub,
+		"export.CSV":   TrackerJira,
+		"tasks.jsonl":  TrackerJSONL,
+		"tasks.ndjson": TrackerJSONL,
+	}
+	for path, want := range tests {
+		got, err := TrackerFormatFromPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, got, path)
+	}
+
+	_, err = TrackerFormatFromPath("tasks.txt")
+	require.ErrorIs(t, err, ErrUnknownTrackerFormat)
+}
+
+func TestParseJiraCSV(t *testing.T) {
+	t.Parallel()
+
+	csv := "\ufeffIssue key,Summary,Status,Priority,Assignee,Labels,Labels,Inward issue link (Blocks),Inward issue link (Blocks)\n" +
+		"SSO-1,Add provider,Done,Highest,alice,change:add-sso,backend,,\n" +
+		"SSO-2,Add callback,In Review,Minor,,change:add-sso,,SSO-1,\n" +
+		"SSO-3,Document login,Won't Do,,,backend,,SSO-1,SSO-2\n" +
+		",,,,,,,,\n"
+
+	imp, err := ParseTrackerImport(strings.NewReader(csv), TrackerJira)
+	require.NoError(t, err)
+	require.Len(t, imp.Issues, 3)
+
+	assert.Equal(t, "SSO-1", imp.Issues[0].Key)
+	assert.Equal(t, RegStatusCompleted, imp.Issues[0].Status)
+	assert.Equal(t, PriorityCritical, imp.Issues[0].Priority)
+	assert.Equal(t, "alice", imp.Issues[0].Assignee)
+	assert.Equal(t, "add-sso", imp.Issues[0].ChangeID)
+
+	assert.Equal(t, RegStatusInProgress, imp.Issues[1].Status)
+	assert.Equal(t, PriorityLow, imp.Issues[1].Priority)
+	assert.Equal(t, []string{"SSO-1"}, imp.Issues[1].DependsOn)
+
+	assert.Equal(t, RegStatusSkipped, imp.Issues[2].Status)
+	assert.Empty(t, imp.Issues[2].ChangeID)
+	assert.Equal(t, []string{"SSO-1", "SSO-2"}, imp.Issues[2].DependsOn)
+
+	_, err = ParseTrackerImport(strings.NewReader("Key,Title\nA,B\n"), TrackerJira)
+	require.Error(t, err)
+}
+
+func TestParseGitHubIssueReferences(t *testing.T) {
+	t.Parallel()
+
+	issues := `[
+  {"number": 12, "title": "Add provider", "body": "", "state": "CLOSED", "labels": [{"name": "change:add-sso"}], "assignees": [{"login": "alice"}]},
+  {"number": 13, "title": "Add callback", "body": "Depends on #12\n\nBlocked by #12", "state": "OPEN", "labels": [{"name": "priority:high"}, {"name": "status:in_progress"}], "assignees": []}
+]`
+
+	imp, err := ParseTrackerImport(strings.NewReader(issues), TrackerGitHub)
+	require.NoError(t, err)
+	require.Len(t, imp.Issues, 2)
+
+	assert.Equal(t, "#12", imp.Issues[0].Key)
+	assert.Equal(t, RegStatusCompleted, imp.Issues[0].Status)
+	assert.Equal(t, "alice", imp.Issues[0].Assignee)
+
+	assert.Equal(t, "#13", imp.Issues[1].Key)
+	assert.Empty(t, imp.Issues[1].ChangeID)
+	assert.Equal(t, RegStatusInProgress, imp.Issues[1].Status)
+	assert.Equal(t, PriorityHigh, imp.Issues[1].Priority)
+	assert.Equal(t, []string{"#12"}, imp.Issues[1].DependsOn)
+}
+
+func TestImportDiffAndApply(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	changeDir := filepath.Join(root, "openspec", "changes", "add-sso")
+	require.NoError(t, os.MkdirAll(changeDir, 0o750))
+	tasksPath := filepath.Join(changeDir, "tasks.md")
+	source := "# Tasks\n\n- [ ] 1.1 Add provider\n- [ ] 1.2 Add callback\n"
+	require.NoError(t, os.WriteFile(tasksPath, []byte(source), 0o600))
+
+	reg, err := NewRegistryStore(NewStore(root))
+	require.NoError(t, err)
+	defer func() { _ = reg.Close() }()
+	_, err = reg.RebuildFromSource()
+	require.NoError(t, err)
+
+	issues := `[
+  {"number": 1, "title": "1.1 Add provider", "body": "", "state": "CLOSED", "labels": [{"name": "priority:high"}], "assignees": [{"login": "alice"}]},
+  {"number": 2, "title": "1.2 Add callback", "body": "<!-- openspec-task: add-sso/2 -->", "state": "OPEN", "labels": [], "assignees": []},
+  {"number": 3, "title": "Document login", "body": "Depends on #1\nDepends on #4", "state": "OPEN", "labels": [{"name": "priority:low"}], "assignees": []},
+  {"number": 4, "title": "Add logout", "body": "", "state": "OPEN", "labels": [{"name": "status:in_progress"}], "assignees": [{"login": "bob"}]},
+  {"number": 5, "title": "Broken", "body": "Depends on #99", "state": "OPEN", "labels": [{"name": "change:add-sso"}], "assignees": []}
+]`
+	imp, err := ParseTrackerImport(strings.NewReader(issues), TrackerGitHub)
+	require.NoError(t, err)
+
+	diff, err := reg.DiffImport(imp, ImportOptions{ChangeID: "add-sso"})
+	require.NoError(t, err)
+
+	require.Len(t, diff.Added, 3)
+	assert.Equal(t, "add-sso/3", diff.Added[0].Task.ID.String())
+	assert.Equal(t, "Document login", diff.Added[0].Task.Content)
+	assert.Equal(t, "add-sso/1, add-sso/4", joinTaskIDs(diff.Added[0].Task.DependsOn), "dependencies may point further down the file")
+	assert.Equal(t, "add-sso/4", diff.Added[1].Task.ID.String())
+	assert.Equal(t, RegStatusInProgress, diff.Added[1].Task.Status)
+	assert.Equal(t, "add-sso/5", diff.Added[2].Task.ID.String())
+
+	require.Len(t, diff.Updated, 1)
+	assert.Equal(t, "add-sso/1", diff.Updated[0].Task.ID.String())
+	assert.Equal(t, []FieldChange{
+		{Field: "status", From: "pending", To: "completed"},
+		{Field: "priority", From: "medium", To: "high"},
+		{Field: "assignee", From: "", To: "alice"},
+	}, diff.Updated[0].Changes)
+	assert.Equal(t, 1, diff.Unchanged)
+	require.Len(t, diff.Warnings, 1)
+	assert.Contains(t, diff.Warnings[0], "unknown dependency #99")
+
+	out := diff.String()
+	assert.Contains(t, out, "+ add-sso/3 \"Document login\" [pending, low] depends on add-sso/1, add-sso/4 (#3)")
+	assert.Contains(t, out, "~ add-sso/1 \"1.1 Add provider\": status pending → completed; priority medium → high; assignee (none) → alice")
+	assert.Contains(t, out, "3 added, 1 updated, 1 unchanged")
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, source, string(content), "diffing writes nothing")
+
+	require.NoError(t, reg.ApplyImport(diff))
+
+	content, err = os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, "# Tasks\n\n- [x] 1.1 Add provider\n- [ ] 1.2 Add callback\n\n"+
+		"- [ ] Document login <!-- depends: 1, 4 -->\n- [ ] Add logout\n- [ ] Broken\n", string(content))
+
+	task, err := reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "1"})
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusCompleted, task.Status)
+	assert.Equal(t, PriorityHigh, task.Priority)
+	assert.Equal(t, "alice", task.Assignee)
+
+	task, err = reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "4"})
+	require.NoError(t, err)
+	assert.Equal(t, RegStatusInProgress, task.Status)
+	assert.Equal(t, "bob", task.Assignee)
+
+	task, err = reg.GetTask(TaskID{ChangeID: "add-sso", TaskNum: "3"})
+	require.NoError(t, err)
+	assert.Equal(t, PriorityLow, task.Priority)
+	assert.Len(t, task.DependsOn, 2)
+	assert.Equal(t, "add-sso/4", joinTaskIDs(task.BlockedBy), "task 1 is done, task 4 is not")
+
+	diff, err = reg.DiffImport(imp, ImportOptions{ChangeID: "add-sso"})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Added, "a second import matches by title")
+	assert.Empty(t, diff.Updated)
+
+	diff, err = reg.DiffImport(imp, ImportOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, diff.Added)
+	require.Len(t, diff.Warnings, 4, "issues without a change are skipped")
+	assert.Contains(t, diff.Warnings[1], "#3 \"Document login\": no change")
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 )
 
@@ -24,6 +23,7 @@ type ValidationIssue struct {
 	Line     int
 	Message  string
 	RuleID   string
+	Plugin   string
 }
 
 func (v ValidationIssue) String() string {
@@ -31,7 +31,11 @@ func (v ValidationIssue) String() string {
 	if v.Line > 0 {
 		loc = fmt.Sprintf("%s:%d", v.File, v.Line)
 	}
-	return fmt.Sprintf("[%s] %s: %s (%s)", v.Severity, loc, v.Message, v.RuleID)
+	rule := v.RuleID
+	if v.Plugin != "" {
+		rule = v.Plugin + "/" + v.RuleID
+	}
+	return fmt.Sprintf("[%s] %s: %s (%s)", v.Severity, loc, v.Message, rule)
 }
 
 // ValidationResult holds the results of validation.
@@ -70,6 +74,17 @@ type ValidationContext struct {
 	Content     string
 	Lines       []string
 	Strict      bool
+	// Doc is the parsed Content. Rules that need it call Document, which
+	// parses on first use when the caller did not set it.
+	Doc *Document
+}
+
+// Document returns the parsed document for the content being validated.
+func (ctx *ValidationContext) Document() *Document {
+	if ctx.Doc == nil {
+		ctx.Doc = ParseDocument(ctx.Content)
+	}
+	return ctx.Doc
 }
 
 // ValidationRule is a function that validates content and returns issues.
@@ -88,6 +103,7 @@ func NewValidator() *Validator {
 			validateScenarioFormat,
 			validateDeltaOperations,
 			validateRequirementFormat,
+			validatePluginRules,
 		},
 	}
 }
@@ -105,6 +121,7 @@ func (v *Validator) ValidateSpec(path string, strict bool) (*ValidationResult, e
 		Content:     string(content),
 		Lines:       strings.Split(string(content), "\n"),
 		Strict:      strict,
+		Doc:         ParseDocument(string(content)),
 	}
 
 	var issues []ValidationIssue
@@ -268,42 +285,6 @@ func (v *Validator) buildSummary(result *ValidationResult) string {
 	return fmt.Sprintf("%s: %s", status, strings.Join(parts, ", "))
 }
 
-// FindLineNumber finds the line number of a pattern in content.
-// ParseRequirements extracts requirement names and their line numbers.
-func parseRequirements(lines []string) map[string]int {
-	result := make(map[string]int)
-	re := regexp.MustCompile(`^###\s+Requirement:\s+(.+)$`)
-	for i, line := range lines {
-		if matches := re.FindStringSubmatch(strings.TrimSpace(line)); matches != nil {
-			result[matches[1]] = i + 1
-		}
-	}
-	return result
-}
-
-// ParseScenariosAfterLine returns the number of scenarios between start and end lines.
-func parseScenariosAfterLine(lines []string, startLine, endLine int) int {
-	count := 0
-	re := regexp.MustCompile(`^####\s+Scenario:\s+`)
-	for i := startLine; i < endLine && i < len(lines); i++ {
-		if re.MatchString(strings.TrimSpace(lines[i])) {
-			count++
-		}
-	}
-	return count
-}
-
-// FindNextRequirementLine finds the next ### Requirement: line after startLine.
-func findNextRequirementLine(lines []string, startLine int) int {
-	re := regexp.MustCompile(`^###\s+Requirement:\s+`)
-	for i := startLine; i < len(lines); i++ {
-		if re.MatchString(strings.TrimSpace(lines[i])) {
-			return i
-		}
-	}
-	return len(lines)
-}
-
 // ReadFileLines reads a file and returns its lines.
 func ReadFileLines(path string) ([]string, error) {
 	file, err := os.Open(path) // #nosec G304 -- controlled file path
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/lint"
 )
 
 func TestValidateRequirementHasScenario(t *testing.T) {
@@ -105,6 +106,11 @@ func TestValidateScenarioFormat(t *testing.T) {
 `,
 			wantIssues: 1,
 		},
+		{
+			name:       "examples inside code fences are ignored",
+			content:    "#### Scenario: Valid input\n- WHEN user enters valid data\n\n```markdown\n### Scenario: Wrong on purpose\n- **Scenario: Also wrong**\n```\n",
+			wantIssues: 0,
+		},
 	}
 
 	for _, tt := range tests {
@@ -352,3 +358,47 @@ func splitLines(s string) []string {
 	}
 	return lines
 }
+
+func TestValidateSpec_PluginRules(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	rulePath := filepath.Join(tmpDir, "rule.yaml")
+	require.NoError(t, os.WriteFile(rulePath, []byte(`name: no-tbd
+target: spec
+trigger:
+  pattern: plugin-rules-spec.md
+conditions:
+  - type: content_matches
+    pattern: TBD
+actions:
+  - type: reject
+    message: resolve TBD before review
+`), 0600))
+
+	rule, err := lint.LoadRule(rulePath)
+	require.NoError(t, err)
+	lint.Default().Add("spec-test-plugin", rule)
+	t.Cleanup(func() { lint.Default().RemovePlugin("spec-test-plugin") })
+
+	specPath := filepath.Join(tmpDir, "plugin-rules-spec.md")
+	require.NoError(t, os.WriteFile(specPath, []byte(`# Test Spec
+
+### Requirement: Export
+
+#### Scenario: TBD
+- WHEN user exports
+- THEN a file is written
+`), 0600))
+
+	result, err := NewValidator().ValidateSpec(specPath, false)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	require.Len(t, result.Issues, 1)
+
+	issue := result.Issues[0]
+	assert.Equal(t, "spec-test-plugin", issue.Plugin)
+	assert.Equal(t, "no-tbd", issue.RuleID)
+	assert.Equal(t, 5, issue.Line)
+	assert.Contains(t, issue.String(), "(spec-test-plugin/no-tbd)")
+}
@@ -2,6 +2,7 @@ package spec
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -32,17 +33,27 @@ type ExecutionRecord struct {
 	ExecutedAt time.Time        `yaml:"executed_at" json:"executed_at"`
 }
 
+// PhaseTransition records a single move between workflow phases.
+type PhaseTransition struct {
+	From string    `yaml:"from,omitempty" json:"from,omitempty"`
+	To   string    `yaml:"to" json:"to"`
+	At   time.Time `yaml:"at" json:"at"`
+}
+
 // WorkflowState tracks the execution state of a change proposal workflow.
 // It bridges the spec domain (what needs to be done) with the agent domain
 // (who is doing it) by tracking the current agent role executing the workflow.
 type WorkflowState struct {
 	ID               string                 `yaml:"id" json:"id"`
 	ChangeID         string                 `yaml:"change_id" json:"change_id"`
+	Definition       string                 `yaml:"definition,omitempty" json:"definition,omitempty"`
 	Phase            string                 `yaml:"phase" json:"phase"`
 	AgentRole        domain.AgentRole       `yaml:"agent_role,omitempty" json:"agent_role,omitempty"` // Current agent executing this workflow
 	WaitPoint        string                 `yaml:"wait_point,omitempty" json:"wait_point,omitempty"`
 	Status           WorkflowStatus         `yaml:"status" json:"status"`
+	Approvals        []string               `yaml:"approvals,omitempty" json:"approvals,omitempty"` // Approvers collected in the current phase
 	Context          map[string]interface{} `yaml:"context,omitempty" json:"context,omitempty"`
+	Transitions      []PhaseTransition      `yaml:"transitions,omitempty" json:"transitions,omitempty"`
 	ExecutionHistory []ExecutionRecord      `yaml:"execution_history,omitempty" json:"execution_history,omitempty"`
 	CreatedAt        time.Time              `yaml:"created_at" json:"created_at"`
 	UpdatedAt        time.Time              `yaml:"updated_at" json:"updated_at"`
@@ -88,6 +99,114 @@ func (w *WorkflowState) Cancel() {
 	w.UpdatedAt = time.Now()
 }
 
+// IsTerminal reports whether the workflow has completed or been cancelled.
+func (w *WorkflowState) IsTerminal() bool {
+	return w.Status == WorkflowStatusCompleted || w.Status == WorkflowStatusCancelled
+}
+
+// AddApproval records an approval for the current phase. Each approver
+// counts once per phase, so repeated approvals by the same person do not
+// satisfy a gate needing several. The wait point is cleared once the phase
+// has collected the approvals its definition requires. It returns the number
+// of approvals still missing.
+func (w *WorkflowState) AddApproval(def *WorkflowDefinition, approver string) (int, error) {
+	if w.IsTerminal() {
+		return 0, fmt.Errorf("%w: %s", ErrWorkflowNotActive, w.Status)
+	}
+	if approver == "" {
+		return 0, ErrApproverRequired
+	}
+
+	if !slices.Contains(w.Approvals, approver) {
+		w.Approvals = append(w.Approvals, approver)
+		w.UpdatedAt = time.Now()
+	}
+
+	remaining := w.pendingApprovals(def)
+	if remaining == 0 {
+		w.Approve()
+	}
+	return remaining, nil
+}
+
+// Transition moves the workflow to another phase if the definition allows it
+// and the current phase has the approvals it requires. A workflow waiting at a
+// wait point moves only once AddApproval has released it. Entering a phase
+// assigns its agent role and runs its on_enter actions.
+func (w *WorkflowState) Transition(def *WorkflowDefinition, to string) error {
+	if w.IsTerminal() {
+		return fmt.Errorf("%w: %s", ErrWorkflowNotActive, w.Status)
+	}
+	if _, ok := def.Phase(to); !ok {
+		return fmt.Errorf("%w: unknown phase %q in %s", ErrInvalidTransition, to, def.Name)
+	}
+	if !def.CanTransition(w.Phase, to) {
+		return fmt.Errorf("%w: %s -> %s in %s", ErrInvalidTransition, w.Phase, to, def.Name)
+	}
+	if remaining := w.pendingApprovals(def); remaining > 0 {
+		return fmt.Errorf("%w: %d more for phase %s", ErrApprovalsPending, remaining, w.Phase)
+	}
+	if w.Status == WorkflowStatusWaiting {
+		return fmt.Errorf("%w: %s", ErrWorkflowWaiting, w.WaitPoint)
+	}
+
+	w.EnterPhase(def, to)
+	return nil
+}
+
+// EnterPhase unconditionally enters the named phase, assigning its agent
+// role, resetting approvals and executing its on_enter actions.
+func (w *WorkflowState) EnterPhase(def *WorkflowDefinition, name string) {
+	now := time.Now()
+	w.Transitions = append(w.Transitions, PhaseTransition{From: w.Phase, To: name, At: now})
+	w.Definition = def.Name
+	w.Phase = name
+	w.Approvals = nil
+	w.WaitPoint = ""
+	w.Status = WorkflowStatusActive
+	w.UpdatedAt = now
+
+	phase, ok := def.Phase(name)
+	if !ok {
+		return
+	}
+
+	if phase.Agent != "" {
+		w.AgentRole = phase.Agent
+	}
+	if phase.Approvals > 0 {
+		w.SetWaitPoint(name + "-approval")
+	}
+
+	for _, action := range phase.OnEnter {
+		switch action.Type {
+		case WorkflowActionWait:
+			w.SetWaitPoint(action.Value)
+		case WorkflowActionSetContext:
+			if w.Context == nil {
+				w.Context = make(map[string]interface{})
+			}
+			w.Context[action.Key] = action.Value
+		case WorkflowActionComplete:
+			w.Complete()
+		case WorkflowActionCancel:
+			w.Cancel()
+		}
+	}
+
+	if phase.Final && !w.IsTerminal() {
+		w.Complete()
+	}
+}
+
+func (w *WorkflowState) pendingApprovals(def *WorkflowDefinition) int {
+	phase, ok := def.Phase(w.Phase)
+	if !ok || phase.Approvals <= len(w.Approvals) {
+		return 0
+	}
+	return phase.Approvals - len(w.Approvals)
+}
+
 // RecordExecution adds an execution record to the history.
 func (w *WorkflowState) RecordExecution(record ExecutionRecord) {
 	if w.ExecutionHistory == nil {
@@ -115,6 +234,8 @@ func (w *WorkflowState) TotalTokens() int {
 	return total
 }
 
+// WorkflowPath returns the legacy single-workflow file. Workflows now live in
+// the bolt-backed WorkflowStore; the file is only read for migration.
 func (s *Store) WorkflowPath() string {
 	return fmt.Sprintf("%s/.workflow.yaml", s.SpecPath())
 }
@@ -123,10 +244,6 @@ func (s *Store) LoadWorkflow() (*WorkflowState, error) {
 	return loadYAML[WorkflowState](s.WorkflowPath())
 }
 
-func (s *Store) SaveWorkflow(state *WorkflowState) error {
-	return saveYAML(s.WorkflowPath(), state)
-}
-
 func (s *Store) WorkflowExists() bool {
 	return fileExists(s.WorkflowPath())
 }
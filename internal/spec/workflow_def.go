@@ -0,0 +1,264 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/victorzhuk/go-ent/internal/domain"
+)
+
+// DefaultWorkflowName is the name of the built-in workflow definition.
+const DefaultWorkflowName = "default"
+
+var (
+	ErrWorkflowNotFound        = errors.New("workflow not found")
+	ErrWorkflowDefNotFound     = errors.New("workflow definition not found")
+	ErrInvalidWorkflowDef      = errors.New("invalid workflow definition")
+	ErrInvalidTransition       = errors.New("transition not allowed")
+	ErrApprovalsPending        = errors.New("required approvals pending")
+	ErrWorkflowWaiting         = errors.New("workflow waiting for approval")
+	ErrWorkflowNotActive       = errors.New("workflow not active")
+	ErrAmbiguousWorkflow       = errors.New("multiple active workflows match")
+	ErrWorkflowAlreadyAssigned = errors.New("workflow already active for change")
+	ErrApproverRequired        = errors.New("approver is required")
+)
+
+// WorkflowActionType identifies an automatic action run on phase entry.
+type WorkflowActionType string
+
+const (
+	// WorkflowActionWait pauses the workflow at the wait point named by Value.
+	WorkflowActionWait WorkflowActionType = "wait"
+
+	// WorkflowActionSetContext stores Value under Key in the workflow context.
+	WorkflowActionSetContext WorkflowActionType = "set_context"
+
+	// WorkflowActionComplete marks the workflow completed.
+	WorkflowActionComplete WorkflowActionType = "complete"
+
+	// WorkflowActionCancel marks the workflow cancelled.
+	WorkflowActionCancel WorkflowActionType = "cancel"
+)
+
+// WorkflowAction is an automatic action executed when a phase is entered.
+type WorkflowAction struct {
+	Type  WorkflowActionType `yaml:"action" json:"action"`
+	Key   string             `yaml:"key,omitempty" json:"key,omitempty"`
+	Value string             `yaml:"value,omitempty" json:"value,omitempty"`
+}
+
+// WorkflowPhase describes a single state of a workflow definition.
+type WorkflowPhase struct {
+	Name        string           `yaml:"name" json:"name"`
+	Description string           `yaml:"description,omitempty" json:"description,omitempty"`
+	Agent       domain.AgentRole `yaml:"agent,omitempty" json:"agent,omitempty"`
+	Approvals   int              `yaml:"approvals,omitempty" json:"approvals,omitempty"`
+	Transitions []string         `yaml:"transitions,omitempty" json:"transitions,omitempty"`
+	OnEnter     []WorkflowAction `yaml:"on_enter,omitempty" json:"on_enter,omitempty"`
+	Final       bool             `yaml:"final,omitempty" json:"final,omitempty"`
+}
+
+// WorkflowDefinition is a declarative state machine for a change workflow.
+// Definitions are loaded from .go-ent/workflows/*.yaml; a built-in default
+// is always available unless a file overrides it by name.
+type WorkflowDefinition struct {
+	Name        string          `yaml:"name" json:"name"`
+	Description string          `yaml:"description,omitempty" json:"description,omitempty"`
+	Initial     string          `yaml:"initial" json:"initial"`
+	Phases      []WorkflowPhase `yaml:"phases" json:"phases"`
+}
+
+// DefaultWorkflowDefinition returns the built-in plan → implement → review workflow.
+func DefaultWorkflowDefinition() *WorkflowDefinition {
+	return &WorkflowDefinition{
+		Name:        DefaultWorkflowName,
+		Description: "Plan, implement and review a change proposal",
+		Initial:     "planning",
+		Phases: []WorkflowPhase{
+			{
+				Name:        "planning",
+				Description: "Design the change and agree on the approach",
+				Agent:       domain.AgentRoleArchitect,
+				Approvals:   1,
+				Transitions: []string{"execution", "cancelled"},
+			},
+			{
+				Name:        "execution",
+				Description: "Implement the tasks of the change",
+				Agent:       domain.AgentRoleDeveloper,
+				Transitions: []string{"validation", "planning", "cancelled"},
+			},
+			{
+				Name:        "validation",
+				Description: "Review and validate the implementation",
+				Agent:       domain.AgentRoleReviewer,
+				Approvals:   1,
+				Transitions: []string{"done", "execution"},
+			},
+			{
+				Name:  "done",
+				Final: true,
+			},
+			{
+				Name:    "cancelled",
+				OnEnter: []WorkflowAction{{Type: WorkflowActionCancel}},
+				Final:   true,
+			},
+		},
+	}
+}
+
+// Phase returns the phase with the given name.
+func (d *WorkflowDefinition) Phase(name string) (*WorkflowPhase, bool) {
+	for i := range d.Phases {
+		if d.Phases[i].Name == name {
+			return &d.Phases[i], true
+		}
+	}
+	return nil, false
+}
+
+// CanTransition reports whether the definition allows moving from one phase to another.
+func (d *WorkflowDefinition) CanTransition(from, to string) bool {
+	phase, ok := d.Phase(from)
+	if !ok {
+		return false
+	}
+	for _, t := range phase.Transitions {
+		if t == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that phases are unique and all transitions reference known phases.
+func (d *WorkflowDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWorkflowDef)
+	}
+	if len(d.Phases) == 0 {
+		return fmt.Errorf("%w: %s: at least one phase is required", ErrInvalidWorkflowDef, d.Name)
+	}
+
+	seen := make(map[string]bool, len(d.Phases))
+	for _, p := range d.Phases {
+		if p.Name == "" {
+			return fmt.Errorf("%w: %s: phase name is required", ErrInvalidWorkflowDef, d.Name)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("%w: %s: duplicate phase %q", ErrInvalidWorkflowDef, d.Name, p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	if _, ok := d.Phase(d.Initial); !ok {
+		return fmt.Errorf("%w: %s: initial phase %q not defined", ErrInvalidWorkflowDef, d.Name, d.Initial)
+	}
+
+	for _, p := range d.Phases {
+		if p.Agent != "" && !p.Agent.Valid() {
+			return fmt.Errorf("%w: %s: phase %q has invalid agent %q", ErrInvalidWorkflowDef, d.Name, p.Name, p.Agent)
+		}
+		if p.Approvals < 0 {
+			return fmt.Errorf("%w: %s: phase %q has negative approvals", ErrInvalidWorkflowDef, d.Name, p.Name)
+		}
+		for _, t := range p.Transitions {
+			if !seen[t] {
+				return fmt.Errorf("%w: %s: phase %q transitions to unknown phase %q", ErrInvalidWorkflowDef, d.Name, p.Name, t)
+			}
+		}
+		for _, a := range p.OnEnter {
+			switch a.Type {
+			case WorkflowActionWait:
+				if a.Value == "" {
+					return fmt.Errorf("%w: %s: phase %q wait action needs a value", ErrInvalidWorkflowDef, d.Name, p.Name)
+				}
+			case WorkflowActionSetContext:
+				if a.Key == "" {
+					return fmt.Errorf("%w: %s: phase %q set_context action needs a key", ErrInvalidWorkflowDef, d.Name, p.Name)
+				}
+			case WorkflowActionComplete, WorkflowActionCancel:
+			default:
+				return fmt.Errorf("%w: %s: phase %q has unknown action %q", ErrInvalidWorkflowDef, d.Name, p.Name, a.Type)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WorkflowDefinitionsPath returns the directory holding workflow definition files.
+func (s *Store) WorkflowDefinitionsPath() string {
+	return filepath.Join(s.rootPath, ".go-ent", "workflows")
+}
+
+// LoadWorkflowDefinitions loads all workflow definitions for the project.
+// The built-in default is included unless a file defines the same name.
+func (s *Store) LoadWorkflowDefinitions() (map[string]*WorkflowDefinition, error) {
+	defs := map[string]*WorkflowDefinition{
+		DefaultWorkflowName: DefaultWorkflowDefinition(),
+	}
+
+	dir := s.WorkflowDefinitionsPath()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return defs, nil
+		}
+		return nil, fmt.Errorf("read workflows dir: %w", err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (!strings.HasSuffix(name, ".yaml") && !strings.HasSuffix(name, ".yml")) {
+			continue
+		}
+
+		def, err := loadYAML[WorkflowDefinition](filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		if def.Name == "" {
+			def.Name = strings.TrimSuffix(strings.TrimSuffix(name, ".yaml"), ".yml")
+		}
+		if err := def.Validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		defs[def.Name] = def
+	}
+
+	return defs, nil
+}
+
+// LoadWorkflowDefinition loads a single workflow definition by name.
+func (s *Store) LoadWorkflowDefinition(name string) (*WorkflowDefinition, error) {
+	if name == "" {
+		name = DefaultWorkflowName
+	}
+
+	defs, err := s.LoadWorkflowDefinitions()
+	if err != nil {
+		return nil, err
+	}
+
+	def, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowDefNotFound, name)
+	}
+	return def, nil
+}
+
+// WorkflowDefinitionNames returns the sorted names of the given definitions.
+func WorkflowDefinitionNames(defs map[string]*WorkflowDefinition) []string {
+	names := make([]string, 0, len(defs))
+	for name := range defs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
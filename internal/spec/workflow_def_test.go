@@ -0,0 +1,292 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/victorzhuk/go-ent/internal/domain"
+)
+
+func TestDefaultWorkflowDefinition_Valid(t *testing.T) {
+	t.Parallel()
+
+	def := DefaultWorkflowDefinition()
+	require.NoError(t, def.Validate())
+	assert.True(t, def.CanTransition("planning", "execution"))
+	assert.False(t, def.CanTransition("planning", "done"))
+	assert.False(t, def.CanTransition("unknown", "done"))
+}
+
+func TestWorkflowDefinition_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		def    WorkflowDefinition
+		errMsg string
+	}{
+		{
+			name:   "missing name",
+			def:    WorkflowDefinition{Initial: "a", Phases: []WorkflowPhase{{Name: "a"}}},
+			errMsg: "name is required",
+		},
+		{
+			name:   "unknown initial",
+			def:    WorkflowDefinition{Name: "x", Initial: "b", Phases: []WorkflowPhase{{Name: "a"}}},
+			errMsg: "initial phase",
+		},
+		{
+			name:   "duplicate phase",
+			def:    WorkflowDefinition{Name: "x", Initial: "a", Phases: []WorkflowPhase{{Name: "a"}, {Name: "a"}}},
+			errMsg: "duplicate phase",
+		},
+		{
+			name:   "unknown transition target",
+			def:    WorkflowDefinition{Name: "x", Initial: "a", Phases: []WorkflowPhase{{Name: "a", Transitions: []string{"b"}}}},
+			errMsg: "unknown phase",
+		},
+		{
+			name:   "invalid agent",
+			def:    WorkflowDefinition{Name: "x", Initial: "a", Phases: []WorkflowPhase{{Name: "a", Agent: "wizard"}}},
+			errMsg: "invalid agent",
+		},
+		{
+			name: "unknown action",
+			def: WorkflowDefinition{Name: "x", Initial: "a", Phases: []WorkflowPhase{
+				{Name: "a", OnEnter: []WorkflowAction{{Type: "explode"}}},
+			}},
+			errMsg: "unknown action",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.def.Validate()
+			require.Error(t, err)
+			assert.ErrorIs(t, err, ErrInvalidWorkflowDef)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestStore_LoadWorkflowDefinitions(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	defs, err := store.LoadWorkflowDefinitions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultWorkflowName}, WorkflowDefinitionNames(defs))
+
+	require.NoError(t, os.MkdirAll(store.WorkflowDefinitionsPath(), 0750))
+	hotfix := `initial: fix
+phases:
+  - name: fix
+    agent: senior
+    transitions: [review]
+    on_enter:
+      - action: set_context
+        key: priority
+        value: critical
+  - name: review
+    agent: reviewer
+    approvals: 2
+    transitions: [shipped]
+  - name: shipped
+    final: true
+`
+	require.NoError(t, os.WriteFile(filepath.Join(store.WorkflowDefinitionsPath(), "hotfix.yaml"), []byte(hotfix), 0600))
+
+	defs, err = store.LoadWorkflowDefinitions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultWorkflowName, "hotfix"}, WorkflowDefinitionNames(defs))
+
+	def, err := store.LoadWorkflowDefinition("hotfix")
+	require.NoError(t, err)
+	assert.Equal(t, "fix", def.Initial)
+	phase, ok := def.Phase("review")
+	require.True(t, ok)
+	assert.Equal(t, domain.AgentRoleReviewer, phase.Agent)
+	assert.Equal(t, 2, phase.Approvals)
+
+	_, err = store.LoadWorkflowDefinition("missing")
+	assert.ErrorIs(t, err, ErrWorkflowDefNotFound)
+}
+
+func TestStore_LoadWorkflowDefinitions_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	store := NewStore(tmpDir)
+
+	require.NoError(t, os.MkdirAll(store.WorkflowDefinitionsPath(), 0750))
+	bad := "name: bad\ninitial: nowhere\nphases:\n  - name: a\n"
+	require.NoError(t, os.WriteFile(filepath.Join(store.WorkflowDefinitionsPath(), "bad.yaml"), []byte(bad), 0600))
+
+	_, err := store.LoadWorkflowDefinitions()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad.yaml")
+}
+
+func TestWorkflowState_Transition(t *testing.T) {
+	t.Parallel()
+
+	def := DefaultWorkflowDefinition()
+	state := NewWorkflowState("change-1", "")
+	state.EnterPhase(def, def.Initial)
+
+	assert.Equal(t, "planning", state.Phase)
+	assert.Equal(t, domain.AgentRoleArchitect, state.AgentRole)
+	assert.Equal(t, WorkflowStatusWaiting, state.Status)
+	assert.Equal(t, "planning-approval", state.WaitPoint)
+
+	err := state.Transition(def, "execution")
+	assert.ErrorIs(t, err, ErrApprovalsPending)
+
+	remaining, err := state.AddApproval(def, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, WorkflowStatusActive, state.Status)
+
+	err = state.Transition(def, "done")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	require.NoError(t, state.Transition(def, "execution"))
+	assert.Equal(t, domain.AgentRoleDeveloper, state.AgentRole)
+	assert.Empty(t, state.Approvals)
+
+	require.NoError(t, state.Transition(def, "validation"))
+	_, err = state.AddApproval(def, "bob")
+	require.NoError(t, err)
+	require.NoError(t, state.Transition(def, "done"))
+
+	assert.Equal(t, WorkflowStatusCompleted, state.Status)
+	assert.Len(t, state.Transitions, 4)
+
+	err = state.Transition(def, "execution")
+	assert.ErrorIs(t, err, ErrWorkflowNotActive)
+}
+
+func TestWorkflowState_EnterPhaseActions(t *testing.T) {
+	t.Parallel()
+
+	def := &WorkflowDefinition{
+		Name:    "custom",
+		Initial: "triage",
+		Phases: []WorkflowPhase{
+			{
+				Name:        "triage",
+				Approvals:   2,
+				Transitions: []string{"fix"},
+				OnEnter: []WorkflowAction{
+					{Type: WorkflowActionSetContext, Key: "severity", Value: "high"},
+				},
+			},
+			{
+				Name: "fix",
+				OnEnter: []WorkflowAction{
+					{Type: WorkflowActionWait, Value: "pairing"},
+				},
+			},
+		},
+	}
+	require.NoError(t, def.Validate())
+
+	state := NewWorkflowState("change-1", "")
+	state.EnterPhase(def, def.Initial)
+	assert.Equal(t, "high", state.Context["severity"])
+
+	remaining, err := state.AddApproval(def, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, remaining)
+	assert.Equal(t, WorkflowStatusWaiting, state.Status)
+
+	remaining, err = state.AddApproval(def, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+
+	require.NoError(t, state.Transition(def, "fix"))
+	assert.Equal(t, "pairing", state.WaitPoint)
+	assert.Equal(t, WorkflowStatusWaiting, state.Status)
+}
+
+func TestWorkflowState_WaitPointBlocksTransition(t *testing.T) {
+	t.Parallel()
+
+	def := &WorkflowDefinition{
+		Name:    "gated",
+		Initial: "build",
+		Phases: []WorkflowPhase{
+			{
+				Name:        "build",
+				Transitions: []string{"release"},
+				OnEnter:     []WorkflowAction{{Type: WorkflowActionWait, Value: "manual-check"}},
+			},
+			{Name: "release", Final: true},
+		},
+	}
+	require.NoError(t, def.Validate())
+
+	state := NewWorkflowState("change-1", "")
+	state.EnterPhase(def, def.Initial)
+	require.Equal(t, WorkflowStatusWaiting, state.Status)
+
+	err := state.Transition(def, "release")
+	require.ErrorIs(t, err, ErrWorkflowWaiting)
+	assert.Contains(t, err.Error(), "manual-check")
+	assert.Equal(t, "build", state.Phase)
+
+	remaining, err := state.AddApproval(def, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	require.NoError(t, state.Transition(def, "release"))
+	assert.Equal(t, WorkflowStatusCompleted, state.Status)
+}
+
+func TestWorkflowState_DistinctApprovers(t *testing.T) {
+	t.Parallel()
+
+	def := &WorkflowDefinition{
+		Name:    "two-reviewers",
+		Initial: "review",
+		Phases:  []WorkflowPhase{{Name: "review", Approvals: 2}},
+	}
+	state := NewWorkflowState("change-1", "")
+	state.EnterPhase(def, def.Initial)
+
+	_, err := state.AddApproval(def, "")
+	require.ErrorIs(t, err, ErrApproverRequired)
+
+	for range 2 {
+		remaining, err := state.AddApproval(def, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, 1, remaining, "one person cannot satisfy a two-approval gate")
+	}
+	assert.Equal(t, []string{"alice"}, state.Approvals)
+	assert.Equal(t, WorkflowStatusWaiting, state.Status)
+
+	remaining, err := state.AddApproval(def, "bob")
+	require.NoError(t, err)
+	assert.Equal(t, 0, remaining)
+	assert.Equal(t, WorkflowStatusActive, state.Status)
+}
+
+func TestWorkflowState_CancelledPhase(t *testing.T) {
+	t.Parallel()
+
+	def := DefaultWorkflowDefinition()
+	state := NewWorkflowState("change-1", "")
+	state.EnterPhase(def, "execution")
+
+	require.NoError(t, state.Transition(def, "cancelled"))
+	assert.Equal(t, WorkflowStatusCancelled, state.Status)
+	assert.True(t, state.IsTerminal())
+}
@@ -0,0 +1,253 @@
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+const BucketWorkflows = "workflows"
+
+// WorkflowFilter selects workflows from the store.
+type WorkflowFilter struct {
+	ChangeID   string
+	Definition string
+	Status     WorkflowStatus
+	ActiveOnly bool
+}
+
+// WorkflowStore persists workflow states keyed by ID in bbolt, so that any
+// number of workflows can run concurrently across changes.
+type WorkflowStore struct {
+	store *Store
+	db    *bbolt.DB
+}
+
+// WorkflowDBPath returns the path of the workflow database.
+func (s *Store) WorkflowDBPath() string {
+	return filepath.Join(s.SpecPath(), "workflows.db")
+}
+
+// NewWorkflowStore opens the workflow database for the project, importing the
+// legacy single-workflow file if one is present.
+func NewWorkflowStore(store *Store) (*WorkflowStore, error) {
+	path := store.WorkflowDBPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("create directory: %w", err)
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("open workflow db: %w", err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketWorkflows))
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("create bucket %s: %w", BucketWorkflows, err)
+	}
+
+	ws := &WorkflowStore{store: store, db: db}
+	if err := ws.migrateLegacy(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("migrate legacy workflow: %w", err)
+	}
+
+	return ws, nil
+}
+
+func (w *WorkflowStore) Close() error {
+	if w.db != nil {
+		return w.db.Close()
+	}
+	return nil
+}
+
+// Save creates or replaces a workflow state.
+func (w *WorkflowStore) Save(state *WorkflowState) error {
+	if state.ID == "" {
+		return errors.New("workflow id cannot be empty")
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal workflow: %w", err)
+	}
+
+	return w.db.Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket([]byte(BucketWorkflows)).Put([]byte(state.ID), data); err != nil {
+			return fmt.Errorf("put workflow: %w", err)
+		}
+		return nil
+	})
+}
+
+// Get returns the workflow with the given ID.
+func (w *WorkflowStore) Get(id string) (*WorkflowState, error) {
+	var state WorkflowState
+	err := w.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket([]byte(BucketWorkflows)).Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("%w: %s", ErrWorkflowNotFound, id)
+		}
+		return json.Unmarshal(data, &state)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// Delete removes a workflow.
+func (w *WorkflowStore) Delete(id string) error {
+	return w.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(BucketWorkflows)).Delete([]byte(id))
+	})
+}
+
+// List returns workflows matching the filter, oldest first.
+func (w *WorkflowStore) List(filter WorkflowFilter) ([]WorkflowState, error) {
+	var states []WorkflowState
+	err := w.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(BucketWorkflows)).ForEach(func(k, v []byte) error {
+			var state WorkflowState
+			if err := json.Unmarshal(v, &state); err != nil {
+				return fmt.Errorf("unmarshal workflow %s: %w", k, err)
+			}
+			if filter.matches(&state) {
+				states = append(states, state)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].CreatedAt.Before(states[j].CreatedAt)
+	})
+	return states, nil
+}
+
+// Resolve finds a single workflow by ID, or by change ID when the ID is empty.
+// Without either, it resolves to the only non-terminal workflow in the project.
+func (w *WorkflowStore) Resolve(id, changeID string) (*WorkflowState, error) {
+	if id != "" {
+		return w.Get(id)
+	}
+
+	states, err := w.List(WorkflowFilter{ChangeID: changeID, ActiveOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(states) {
+	case 0:
+		if changeID != "" {
+			return nil, fmt.Errorf("%w: no active workflow for change %s", ErrWorkflowNotFound, changeID)
+		}
+		return nil, fmt.Errorf("%w: no active workflow", ErrWorkflowNotFound)
+	case 1:
+		return &states[0], nil
+	default:
+		return nil, fmt.Errorf("%w: %d workflows, specify workflow_id", ErrAmbiguousWorkflow, len(states))
+	}
+}
+
+// Start creates a workflow for a change using the given definition. An empty
+// phase starts at the definition's initial phase. Only one non-terminal
+// workflow per change and definition may exist at a time; the check and the
+// write share one transaction so concurrent starts cannot both succeed.
+func (w *WorkflowStore) Start(def *WorkflowDefinition, changeID, phase string) (*WorkflowState, error) {
+	if phase == "" {
+		phase = def.Initial
+	}
+	if _, ok := def.Phase(phase); !ok {
+		return nil, fmt.Errorf("%w: unknown phase %q in %s", ErrInvalidTransition, phase, def.Name)
+	}
+
+	state := NewWorkflowState(changeID, "")
+	state.EnterPhase(def, phase)
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("marshal workflow: %w", err)
+	}
+
+	filter := WorkflowFilter{ChangeID: changeID, Definition: def.Name, ActiveOnly: true}
+	err = w.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketWorkflows))
+		err := bucket.ForEach(func(k, v []byte) error {
+			var existing WorkflowState
+			if err := json.Unmarshal(v, &existing); err != nil {
+				return fmt.Errorf("unmarshal workflow %s: %w", k, err)
+			}
+			if filter.matches(&existing) {
+				return fmt.Errorf("%w: %s (%s)", ErrWorkflowAlreadyAssigned, changeID, existing.ID)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(state.ID), data); err != nil {
+			return fmt.Errorf("put workflow: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func (w *WorkflowStore) migrateLegacy() error {
+	if !w.store.WorkflowExists() {
+		return nil
+	}
+
+	legacy, err := w.store.LoadWorkflow()
+	if err != nil {
+		return err
+	}
+	if legacy.Definition == "" {
+		legacy.Definition = DefaultWorkflowName
+	}
+
+	if _, err := w.Get(legacy.ID); errors.Is(err, ErrWorkflowNotFound) {
+		if err := w.Save(legacy); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Remove(w.store.WorkflowPath()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove legacy workflow file: %w", err)
+	}
+	return nil
+}
+
+func (f *WorkflowFilter) matches(state *WorkflowState) bool {
+	if f.ChangeID != "" && state.ChangeID != f.ChangeID {
+		return false
+	}
+	if f.Definition != "" && state.Definition != f.Definition {
+		return false
+	}
+	if f.Status != "" && state.Status != f.Status {
+		return false
+	}
+	if f.ActiveOnly && state.IsTerminal() {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,128 @@
+package spec
+
+//nolint:gosec // test file with necessary file operations
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupWorkflowStore(t *testing.T) (*Store, *WorkflowStore) {
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Init(Project{Name: "test", Module: "test/module"}))
+
+	wfStore, err := NewWorkflowStore(store)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = wfStore.Close()
+	})
+
+	return store, wfStore
+}
+
+func TestWorkflowStore_ConcurrentWorkflows(t *testing.T) {
+	t.Parallel()
+
+	_, wfStore := setupWorkflowStore(t)
+	def := DefaultWorkflowDefinition()
+
+	a, err := wfStore.Start(def, "change-a", "")
+	require.NoError(t, err)
+	b, err := wfStore.Start(def, "change-b", "execution")
+	require.NoError(t, err)
+
+	_, err = wfStore.Start(def, "change-a", "")
+	assert.ErrorIs(t, err, ErrWorkflowAlreadyAssigned)
+
+	_, err = wfStore.Start(def, "change-c", "nowhere")
+	assert.ErrorIs(t, err, ErrInvalidTransition)
+
+	active, err := wfStore.List(WorkflowFilter{ActiveOnly: true})
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, a.ID, active[0].ID)
+	assert.Equal(t, b.ID, active[1].ID)
+
+	_, err = wfStore.Resolve("", "")
+	assert.ErrorIs(t, err, ErrAmbiguousWorkflow)
+
+	resolved, err := wfStore.Resolve("", "change-b")
+	require.NoError(t, err)
+	assert.Equal(t, b.ID, resolved.ID)
+	assert.Equal(t, "execution", resolved.Phase)
+
+	resolved.Cancel()
+	require.NoError(t, wfStore.Save(resolved))
+
+	resolved, err = wfStore.Resolve("", "")
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, resolved.ID)
+
+	all, err := wfStore.List(WorkflowFilter{})
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	_, err = wfStore.Get("missing")
+	assert.ErrorIs(t, err, ErrWorkflowNotFound)
+}
+
+func TestWorkflowStore_MigratesLegacyFile(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	require.NoError(t, store.Init(Project{Name: "test", Module: "test/module"}))
+
+	legacy := NewWorkflowState("legacy-change", "planning")
+	require.NoError(t, saveYAML(store.WorkflowPath(), legacy))
+
+	wfStore, err := NewWorkflowStore(store)
+	require.NoError(t, err)
+	defer func() { _ = wfStore.Close() }()
+
+	assert.False(t, store.WorkflowExists())
+
+	loaded, err := wfStore.Get(legacy.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "legacy-change", loaded.ChangeID)
+	assert.Equal(t, DefaultWorkflowName, loaded.Definition)
+}
+
+func TestWorkflowStore_StartIsAtomic(t *testing.T) {
+	t.Parallel()
+
+	_, wfStore := setupWorkflowStore(t)
+	def := DefaultWorkflowDefinition()
+
+	const starts = 8
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		started int
+	)
+	for range starts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := wfStore.Start(def, "change-a", "")
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				started++
+			case !errors.Is(err, ErrWorkflowAlreadyAssigned):
+				t.Errorf("unexpected start error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, started)
+	active, err := wfStore.List(WorkflowFilter{ChangeID: "change-a", ActiveOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, active, 1)
+}
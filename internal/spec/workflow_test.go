@@ -183,12 +183,12 @@ func TestStore_WorkflowPersistence(t *testing.T) {
 	// Initially no workflow exists
 	assert.False(t, store.WorkflowExists())
 
-	// Create and save workflow
+	// Write a legacy workflow file
 	state := NewWorkflowState("test-change", "planning")
 	state.SetAgent(domain.AgentRoleArchitect)
 	state.Context["key"] = "value"
 
-	err = store.SaveWorkflow(state)
+	err = saveYAML(store.WorkflowPath(), state)
 	require.NoError(t, err)
 
 	// Now workflow exists
@@ -0,0 +1,20 @@
+// Package tracing provides lightweight OpenTelemetry-compatible spans for
+// go-ent without pulling in the OpenTelemetry SDK.
+//
+// Spans are started from a context with Start and ended with End. When no
+// tracer is installed with SetDefault, Start returns a nil *Span and every
+// Span method is a no-op, so instrumented code pays almost nothing when
+// tracing is disabled.
+//
+// Finished spans are batched and handed to an Exporter:
+//   - OTLPExporter posts OTLP/HTTP JSON to a collector (default http://localhost:4318/v1/traces)
+//   - JSONLExporter appends one span per line to a local file for offline use
+//
+// Instrumented layers and their span names:
+//   - mcp.<method> (MCP receiving middleware, one span per request)
+//   - engine.execute (execution.Engine.Execute)
+//   - strategy.<name> (single, multi and parallel strategies)
+//   - worker.send_prompt (worker.WorkerManager.SendPrompt)
+//   - acp.<method> (JSON-RPC requests to ACP workers)
+//   - provider.<name>.request (provider HTTP calls)
+package tracing
@@ -0,0 +1,45 @@
+package tracing
+
+import "fmt"
+
+// Config selects and configures the span exporter.
+type Config struct {
+	// Exporter is "otlp" (default) or "jsonl".
+	Exporter string
+
+	// Endpoint is the OTLP/HTTP traces endpoint.
+	Endpoint string
+
+	// Headers are sent with every OTLP export request.
+	Headers map[string]string
+
+	// File is the JSONL output path.
+	File string
+
+	// ServiceName is reported as service.name.
+	ServiceName string
+}
+
+// Setup builds a tracer from cfg and installs it as the default.
+func Setup(cfg Config) (*Tracer, error) {
+	var exp Exporter
+	switch cfg.Exporter {
+	case "", "otlp":
+		exp = NewOTLPExporter(cfg.Endpoint, cfg.Headers)
+	case "jsonl":
+		if cfg.File == "" {
+			return nil, fmt.Errorf("jsonl exporter requires a file path")
+		}
+		jsonl, err := NewJSONLExporter(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		exp = jsonl
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter: %s", cfg.Exporter)
+	}
+
+	t := NewTracer(exp, Options{Service: cfg.ServiceName})
+	SetDefault(t)
+	return t, nil
+}
@@ -0,0 +1,236 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Common attribute keys shared by instrumented packages.
+const (
+	AttrTokensIn  = "gen_ai.usage.input_tokens"
+	AttrTokensOut = "gen_ai.usage.output_tokens"
+	AttrCost      = "goent.cost_usd"
+	AttrModel     = "gen_ai.request.model"
+	AttrProvider  = "gen_ai.system"
+	AttrAgentRole = "goent.agent.role"
+	AttrStatus    = "http.response.status_code"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+// IsZero reports whether the ID is unset.
+func (t TraceID) IsZero() bool { return t == TraceID{} }
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+// IsZero reports whether the ID is unset.
+func (s SpanID) IsZero() bool { return s == SpanID{} }
+
+// SpanKind mirrors the OpenTelemetry span kinds.
+type SpanKind int
+
+const (
+	SpanKindInternal SpanKind = 1
+	SpanKindServer   SpanKind = 2
+	SpanKindClient   SpanKind = 3
+)
+
+// StatusCode mirrors the OpenTelemetry status codes.
+type StatusCode int
+
+const (
+	StatusUnset StatusCode = 0
+	StatusOK    StatusCode = 1
+	StatusError StatusCode = 2
+)
+
+// Attribute is a key/value pair attached to a span.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+func String(key, value string) Attribute      { return Attribute{Key: key, Value: value} }
+func Int(key string, value int) Attribute     { return Attribute{Key: key, Value: int64(value)} }
+func Int64(key string, value int64) Attribute { return Attribute{Key: key, Value: value} }
+func Float64(key string, value float64) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+func Bool(key string, value bool) Attribute { return Attribute{Key: key, Value: value} }
+
+// Event is a timestamped annotation on a span.
+type Event struct {
+	Name       string         `json:"name"`
+	Time       time.Time      `json:"time"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// SpanData is the immutable record of a finished span handed to exporters.
+type SpanData struct {
+	TraceID       string         `json:"trace_id"`
+	SpanID        string         `json:"span_id"`
+	ParentSpanID  string         `json:"parent_span_id,omitempty"`
+	Name          string         `json:"name"`
+	Kind          SpanKind       `json:"kind"`
+	StartTime     time.Time      `json:"start_time"`
+	EndTime       time.Time      `json:"end_time"`
+	Attributes    map[string]any `json:"attributes,omitempty"`
+	Events        []Event        `json:"events,omitempty"`
+	StatusCode    StatusCode     `json:"status_code"`
+	StatusMessage string         `json:"status_message,omitempty"`
+	Service       string         `json:"service"`
+}
+
+// Duration returns the span duration.
+func (d *SpanData) Duration() time.Duration {
+	return d.EndTime.Sub(d.StartTime)
+}
+
+// Span is an in-flight operation. A nil *Span is valid and records nothing.
+type Span struct {
+	tracer  *Tracer
+	traceID TraceID
+	spanID  SpanID
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// TraceID returns the trace the span belongs to.
+func (s *Span) TraceID() TraceID {
+	if s == nil {
+		return TraceID{}
+	}
+	return s.traceID
+}
+
+// SpanID returns the span identifier.
+func (s *Span) SpanID() SpanID {
+	if s == nil {
+		return SpanID{}
+	}
+	return s.spanID
+}
+
+// SetKind sets the span kind.
+func (s *Span) SetKind(kind SpanKind) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.data.Kind = kind
+	s.mu.Unlock()
+}
+
+// SetAttributes adds or replaces attributes.
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	for _, a := range attrs {
+		s.data.Attributes[a.Key] = a.Value
+	}
+}
+
+// AddEvent records a named event with optional attributes.
+func (s *Span) AddEvent(name string, attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	ev := Event{Name: name, Time: time.Now()}
+	if len(attrs) > 0 {
+		ev.Attributes = make(map[string]any, len(attrs))
+		for _, a := range attrs {
+			ev.Attributes[a.Key] = a.Value
+		}
+	}
+	s.mu.Lock()
+	if !s.ended {
+		s.data.Events = append(s.data.Events, ev)
+	}
+	s.mu.Unlock()
+}
+
+// RecordError marks the span failed and records the error as an event.
+// A nil error is ignored.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.AddEvent("exception", String("exception.message", err.Error()))
+	s.SetStatus(StatusError, err.Error())
+}
+
+// SetStatus sets the span status.
+func (s *Span) SetStatus(code StatusCode, msg string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.ended {
+		s.data.StatusCode = code
+		s.data.StatusMessage = msg
+	}
+	s.mu.Unlock()
+}
+
+// End finishes the span and queues it for export. Calling End twice is a no-op.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.EndTime = time.Now()
+	data := s.data
+	s.mu.Unlock()
+
+	s.tracer.enqueue(data)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a context carrying the span.
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext returns the current span, or nil when there is none.
+func SpanFromContext(ctx context.Context) *Span {
+	if ctx == nil {
+		return nil
+	}
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
+
+func newTraceID() TraceID {
+	var id TraceID
+	_, _ = rand.Read(id[:])
+	return id
+}
+
+func newSpanID() SpanID {
+	var id SpanID
+	_, _ = rand.Read(id[:])
+	return id
+}
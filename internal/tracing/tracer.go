@@ -0,0 +1,246 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultQueueSize     = 2048
+	defaultBatchSize     = 256
+	defaultFlushInterval = 5 * time.Second
+)
+
+// Tracer creates spans and exports them in batches.
+type Tracer struct {
+	service  string
+	exporter Exporter
+
+	queue chan SpanData
+	flush chan chan struct{}
+	done  chan struct{}
+	wg    sync.WaitGroup
+
+	batchSize     int
+	flushInterval time.Duration
+	dropped       atomic.Int64
+	closed        atomic.Bool
+}
+
+// Options configures a Tracer.
+type Options struct {
+	// Service is reported as the service.name resource attribute.
+	Service string
+
+	// BatchSize is the maximum number of spans per export call.
+	BatchSize int
+
+	// FlushInterval is the maximum time a span waits before export.
+	FlushInterval time.Duration
+}
+
+// NewTracer creates a tracer exporting finished spans to exp.
+func NewTracer(exp Exporter, opts Options) *Tracer {
+	if opts.Service == "" {
+		opts.Service = "go-ent"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultBatchSize
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = defaultFlushInterval
+	}
+
+	t := &Tracer{
+		service:       opts.Service,
+		exporter:      exp,
+		queue:         make(chan SpanData, defaultQueueSize),
+		flush:         make(chan chan struct{}),
+		done:          make(chan struct{}),
+		batchSize:     opts.BatchSize,
+		flushInterval: opts.FlushInterval,
+	}
+
+	t.wg.Add(1)
+	go t.run()
+
+	return t
+}
+
+// Start begins a span as a child of the span in ctx, if any.
+func (t *Tracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if t == nil || t.closed.Load() {
+		return ctx, nil
+	}
+
+	span := &Span{
+		tracer: t,
+		spanID: newSpanID(),
+		data: SpanData{
+			Name:       name,
+			Kind:       SpanKindInternal,
+			StartTime:  time.Now(),
+			Attributes: make(map[string]any, len(attrs)),
+			Service:    t.service,
+		},
+	}
+
+	if parent := SpanFromContext(ctx); parent != nil {
+		span.traceID = parent.traceID
+		span.data.ParentSpanID = parent.spanID.String()
+	} else {
+		span.traceID = newTraceID()
+	}
+	span.data.TraceID = span.traceID.String()
+	span.data.SpanID = span.spanID.String()
+
+	for _, a := range attrs {
+		span.data.Attributes[a.Key] = a.Value
+	}
+
+	return ContextWithSpan(ctx, span), span
+}
+
+// Dropped returns the number of spans dropped because the queue was full.
+func (t *Tracer) Dropped() int64 {
+	return t.dropped.Load()
+}
+
+// ForceFlush exports all queued spans.
+func (t *Tracer) ForceFlush(ctx context.Context) error {
+	if t.closed.Load() {
+		return nil
+	}
+	ack := make(chan struct{})
+	select {
+	case t.flush <- ack:
+	case <-ctx.Done():
+		return fmt.Errorf("flush: %w", ctx.Err())
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("flush: %w", ctx.Err())
+	}
+}
+
+// Shutdown exports remaining spans and closes the exporter.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if !t.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	close(t.done)
+
+	finished := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		return fmt.Errorf("shutdown: %w", ctx.Err())
+	}
+
+	return t.exporter.Shutdown(ctx)
+}
+
+func (t *Tracer) enqueue(data SpanData) {
+	if t == nil || t.closed.Load() {
+		return
+	}
+	select {
+	case t.queue <- data:
+	default:
+		if t.dropped.Add(1) == 1 {
+			slog.Warn("trace queue full, dropping spans", "span", data.Name)
+		}
+	}
+}
+
+func (t *Tracer) run() {
+	defer t.wg.Done()
+
+	ticker := time.NewTicker(t.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]SpanData, 0, t.batchSize)
+	export := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := t.exporter.Export(ctx, batch); err != nil {
+			slog.Warn("failed to export spans", "count", len(batch), "error", err)
+		}
+		cancel()
+		batch = make([]SpanData, 0, t.batchSize)
+	}
+	drain := func() {
+		for {
+			select {
+			case data := <-t.queue:
+				batch = append(batch, data)
+				if len(batch) >= t.batchSize {
+					export()
+				}
+			default:
+				export()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case data := <-t.queue:
+			batch = append(batch, data)
+			if len(batch) >= t.batchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case ack := <-t.flush:
+			drain()
+			close(ack)
+		case <-t.done:
+			drain()
+			return
+		}
+	}
+}
+
+var defaultTracer atomic.Pointer[Tracer]
+
+// SetDefault installs the process-wide tracer used by Start. Passing nil
+// disables tracing.
+func SetDefault(t *Tracer) {
+	defaultTracer.Store(t)
+}
+
+// Default returns the process-wide tracer, or nil when tracing is disabled.
+func Default() *Tracer {
+	return defaultTracer.Load()
+}
+
+// Start begins a span on the default tracer. It returns ctx unchanged and a
+// nil span when tracing is disabled.
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	return Default().Start(ctx, name, attrs...)
+}
+
+// Shutdown flushes and closes the default tracer, if any.
+func Shutdown(ctx context.Context) error {
+	t := Default()
+	if t == nil {
+		return nil
+	}
+	SetDefault(nil)
+	return t.Shutdown(ctx)
+}
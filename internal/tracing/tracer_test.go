@@ -0,0 +1,219 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []SpanData
+}
+
+func (e *memoryExporter) Export(ctx context.Context, spans []SpanData) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error { return nil }
+
+func (e *memoryExporter) byName() map[string]SpanData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make(map[string]SpanData, len(e.spans))
+	for _, s := range e.spans {
+		out[s.Name] = s
+	}
+	return out
+}
+
+func TestTracer_ParentChild(t *testing.T) {
+	t.Parallel()
+
+	exp := &memoryExporter{}
+	tr := NewTracer(exp, Options{Service: "test"})
+
+	ctx, root := tr.Start(context.Background(), "root", String("k", "v"))
+	_, child := tr.Start(ctx, "child")
+	child.SetKind(SpanKindClient)
+	child.SetAttributes(Int(AttrTokensIn, 10), Float64(AttrCost, 0.5))
+	child.RecordError(errors.New("boom"))
+	child.End()
+	root.End()
+
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	spans := exp.byName()
+	require.Len(t, spans, 2)
+
+	r, c := spans["root"], spans["child"]
+	assert.Equal(t, r.TraceID, c.TraceID)
+	assert.Equal(t, r.SpanID, c.ParentSpanID)
+	assert.Empty(t, r.ParentSpanID)
+	assert.Equal(t, "test", r.Service)
+	assert.Equal(t, "v", r.Attributes["k"])
+	assert.Equal(t, SpanKindClient, c.Kind)
+	assert.Equal(t, int64(10), c.Attributes[AttrTokensIn])
+	assert.Equal(t, StatusError, c.StatusCode)
+	assert.Equal(t, "boom", c.StatusMessage)
+}
+
+func TestTracer_EndTwice(t *testing.T) {
+	t.Parallel()
+
+	exp := &memoryExporter{}
+	tr := NewTracer(exp, Options{})
+
+	_, span := tr.Start(context.Background(), "once")
+	span.End()
+	span.End()
+
+	require.NoError(t, tr.Shutdown(context.Background()))
+	assert.Len(t, exp.byName(), 1)
+	assert.Len(t, exp.spans, 1)
+}
+
+func TestNilSpan(t *testing.T) {
+	t.Parallel()
+
+	var tr *Tracer
+	ctx, span := tr.Start(context.Background(), "noop")
+	assert.Nil(t, span)
+	assert.Nil(t, SpanFromContext(ctx))
+
+	assert.NotPanics(t, func() {
+		span.SetKind(SpanKindServer)
+		span.SetAttributes(String("a", "b"))
+		span.AddEvent("event")
+		span.RecordError(errors.New("x"))
+		span.SetStatus(StatusOK, "")
+		span.End()
+	})
+}
+
+func TestJSONLExporter(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "nested", "traces.jsonl")
+	exp, err := NewJSONLExporter(path)
+	require.NoError(t, err)
+
+	tr := NewTracer(exp, Options{})
+	ctx, root := tr.Start(context.Background(), "engine.execute")
+	_, child := tr.Start(ctx, "strategy.single")
+	child.End()
+	root.End()
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	var names []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var data SpanData
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &data))
+		names = append(names, data.Name)
+	}
+	require.NoError(t, scanner.Err())
+	assert.ElementsMatch(t, []string{"engine.execute", "strategy.single"}, names)
+}
+
+func TestOTLPExporter(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		payload otlpRequest
+		auth    string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		auth = r.Header.Get("Authorization")
+		_ = json.Unmarshal(body, &payload)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, map[string]string{"Authorization": "Bearer token"})
+	tr := NewTracer(exp, Options{Service: "go-ent"})
+	_, span := tr.Start(context.Background(), "mcp.tools/call", Int(AttrTokensOut, 42))
+	span.End()
+	require.NoError(t, tr.ForceFlush(context.Background()))
+	require.NoError(t, tr.Shutdown(context.Background()))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "Bearer token", auth)
+	require.Len(t, payload.ResourceSpans, 1)
+	rs := payload.ResourceSpans[0]
+	require.Len(t, rs.Resource.Attributes, 1)
+	assert.Equal(t, "go-ent", *rs.Resource.Attributes[0].Value.StringValue)
+	require.Len(t, rs.ScopeSpans, 1)
+	require.Len(t, rs.ScopeSpans[0].Spans, 1)
+
+	got := rs.ScopeSpans[0].Spans[0]
+	assert.Equal(t, "mcp.tools/call", got.Name)
+	assert.Len(t, got.TraceID, 32)
+	assert.Len(t, got.SpanID, 16)
+	require.Len(t, got.Attributes, 1)
+	assert.Equal(t, "42", *got.Attributes[0].Value.IntValue)
+}
+
+func TestOTLPExporter_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	exp := NewOTLPExporter(srv.URL, nil)
+	err := exp.Export(context.Background(), []SpanData{{Name: "x"}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "400")
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "otlp default", cfg: Config{}},
+		{name: "jsonl", cfg: Config{Exporter: "jsonl", File: filepath.Join(t.TempDir(), "t.jsonl")}},
+		{name: "jsonl without file", cfg: Config{Exporter: "jsonl"}, wantErr: true},
+		{name: "unknown exporter", cfg: Config{Exporter: "zipkin"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := Setup(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Same(t, tr, Default())
+			require.NoError(t, Shutdown(context.Background()))
+			assert.Nil(t, Default())
+		})
+	}
+}
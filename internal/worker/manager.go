@@ -11,11 +11,13 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+	"github.com/victorzhuk/go-ent/internal/breaker"
 	"github.com/victorzhuk/go-ent/internal/config"
 	"github.com/victorzhuk/go-ent/internal/execution"
 	"github.com/victorzhuk/go-ent/internal/opencode"
 	"github.com/victorzhuk/go-ent/internal/openspec"
 	"github.com/victorzhuk/go-ent/internal/spec"
+	"github.com/victorzhuk/go-ent/internal/tracing"
 )
 
 type HealthStatus string
@@ -386,6 +388,15 @@ func (m *WorkerManager) SetWorkerStatus(workerID string, status WorkerStatus) {
 	worker.Status = status
 	worker.Mutex.Unlock()
 
+	if status != oldStatus {
+		switch status {
+		case StatusFailed:
+			breaker.Default().Record(worker.Provider, fmt.Errorf("worker %s failed", workerID))
+		case StatusCompleted:
+			breaker.Default().Record(worker.Provider, nil)
+		}
+	}
+
 	if m.taskTracker != nil && worker.Task != nil {
 		taskID := m.taskTracker.ExtractTaskID(worker.Task.Description)
 		if !taskID.IsZero() {
@@ -449,6 +460,32 @@ type PromptResponse struct {
 }
 
 func (m *WorkerManager) SendPrompt(ctx context.Context, req PromptRequest) (*PromptResponse, error) {
+	ctx, span := tracing.Start(ctx, "worker.send_prompt",
+		tracing.String("goent.worker.id", req.WorkerID),
+		tracing.Int("goent.prompt.context_files", len(req.ContextFiles)),
+	)
+	defer span.End()
+
+	m.mu.RLock()
+	if w, ok := m.workers[req.WorkerID]; ok {
+		span.SetAttributes(
+			tracing.String(tracing.AttrProvider, w.Provider),
+			tracing.String(tracing.AttrModel, w.Model),
+			tracing.String("goent.worker.method", string(w.Method)),
+		)
+	}
+	m.mu.RUnlock()
+
+	resp, err := m.sendPrompt(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttributes(tracing.String("goent.prompt.id", resp.PromptID))
+	return resp, nil
+}
+
+func (m *WorkerManager) sendPrompt(ctx context.Context, req PromptRequest) (*PromptResponse, error) {
 	m.mu.RLock()
 	worker, exists := m.workers[req.WorkerID]
 	m.mu.RUnlock()
@@ -528,6 +565,7 @@ func (m *WorkerManager) SendPrompt(ctx context.Context, req PromptRequest) (*Pro
 		worker.Mutex.Lock()
 		worker.Status = StatusFailed
 		worker.Mutex.Unlock()
+		breaker.Default().Record(worker.Provider, err)
 
 		postHookCtx.HookType = HookPostFail
 		postHookCtx.Error = err
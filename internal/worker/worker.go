@@ -2,11 +2,13 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os/exec"
 	"time"
 
+	"github.com/victorzhuk/go-ent/internal/breaker"
 	"github.com/victorzhuk/go-ent/internal/config"
 	"github.com/victorzhuk/go-ent/internal/opencode"
 )
@@ -148,6 +150,7 @@ func (w *Worker) sendACP(ctx context.Context, prompt string) (string, error) {
 	if err != nil {
 		w.Status = StatusFailed
 		w.UpdateHealth(HealthUnhealthy, "ACP prompt failed")
+		breaker.Default().Record(w.Provider, err)
 		return "", fmt.Errorf("worker %s: send prompt: %w", w.ID, err)
 	}
 
@@ -210,6 +213,7 @@ func (w *Worker) handleUpdate(update opencode.SessionUpdateNotification) {
 		w.Status = StatusCompleted
 		w.Output += "\n[Complete]"
 		w.recordOutputLocked()
+		breaker.Default().Record(w.Provider, nil)
 
 	case "error":
 		w.Status = StatusFailed
@@ -217,6 +221,11 @@ func (w *Worker) handleUpdate(update opencode.SessionUpdateNotification) {
 			w.Output += fmt.Sprintf("\n[Error] %s", update.Error)
 		}
 		w.updateHealthLocked(HealthUnhealthy, "prompt execution failed")
+		reason := "prompt execution failed"
+		if update.Error != "" {
+			reason += ": " + update.Error
+		}
+		breaker.Default().Record(w.Provider, errors.New(reason))
 
 	case "cancelled":
 		w.Status = StatusCancelled
@@ -274,12 +283,14 @@ func (w *Worker) sendCLI(ctx context.Context, prompt string) (string, error) {
 	if err != nil {
 		w.Status = StatusFailed
 		w.UpdateHealth(HealthUnhealthy, "CLI execution failed")
+		breaker.Default().Record(w.Provider, err)
 		return string(output), fmt.Errorf("opencode run: %w", err)
 	}
 
 	w.Output = string(output)
 	w.Status = StatusCompleted
 	w.RecordOutput()
+	breaker.Default().Record(w.Provider, nil)
 
 	return w.Output, nil
 }